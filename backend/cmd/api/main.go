@@ -2,6 +2,7 @@ package main
 
 import (
 	"booking-app/internal/config"
+	"booking-app/internal/domain"
 	"booking-app/internal/handler"
 	esinfra "booking-app/internal/infrastructure/elasticsearch"
 	tokenpkg "booking-app/internal/infrastructure/jwt"
@@ -76,6 +77,26 @@ func main() {
 	refreshTTL := 7 * 24 * time.Hour
 	tokenMgr := tokenpkg.NewTokenManager(cfg.JWTSecret, accessTTL, refreshTTL)
 
+	quoteTTL, err := time.ParseDuration(cfg.QuoteTTL)
+	if err != nil {
+		logger.Fatal("invalid QUOTE_TTL", zap.Error(err))
+	}
+	quoteSigner := tokenpkg.NewQuoteSigner(cfg.QuoteTokenSecret)
+
+	waitlistOfferTTL, err := time.ParseDuration(cfg.WaitlistOfferTTL)
+	if err != nil {
+		logger.Fatal("invalid WAITLIST_OFFER_TTL", zap.Error(err))
+	}
+
+	outboxRetryBaseDelay, err := time.ParseDuration(cfg.OutboxRetryBaseDelay)
+	if err != nil {
+		logger.Fatal("invalid OUTBOX_RETRY_BASE_DELAY", zap.Error(err))
+	}
+	outboxRetryMaxDelay, err := time.ParseDuration(cfg.OutboxRetryMaxDelay)
+	if err != nil {
+		logger.Fatal("invalid OUTBOX_RETRY_MAX_DELAY", zap.Error(err))
+	}
+
 	// 5b. Elasticsearch
 	esClient, err := esinfra.NewClient(cfg.ElasticsearchURL)
 	if err != nil {
@@ -94,6 +115,7 @@ func main() {
 	hotelRepo := repository.NewHotelRepo(db)
 	roomRepo := repository.NewRoomRepo(db)
 	inventoryRepo := repository.NewInventoryRepo(db)
+	ratePlanRepo := repository.NewRatePlanRepo(db)
 	dashboardRepo := repository.NewDashboardRepo(db)
 	reviewRepo := repository.NewReviewRepo(db)
 	searchRepo := repository.NewESSearchRepo(esClient)
@@ -101,30 +123,59 @@ func main() {
 	outboxRepo := repository.NewOutboxRepo(db)
 	notifRepo := repository.NewNotificationRepo(db)
 	chatRepo := repository.NewChatRepo(db)
+	policyRepo := repository.NewCancellationPolicyRepo(db)
+	refundRepo := repository.NewRefundRepo(db)
+	fxRepo := repository.NewExchangeRateRepo(db)
+	chargeRepo := repository.NewHotelChargeRepo(db)
+	promoRepo := repository.NewPromotionRepo(db)
+	waitlistRepo := repository.NewWaitlistRepo(db)
+	txManager := repository.NewTxManager(db)
+
+	// 6b. WebSocket Hub (created before the services so they can push to clients)
+	hub := handler.NewHub()
 
 	// 7. Services
-	bookingSvc := service.NewBookingService(bookingRepo, roomRepo)
+	notifSvc := service.NewNotificationService(notifRepo)
+	// Hotel availability calendars are cached in Redis; bookings,
+	// cancellations, waitlist offers, inventory, rate and room changes
+	// invalidate them.
+	availabilitySvc := service.NewAvailabilityService(hotelRepo, roomRepo, inventoryRepo, ratePlanRepo,
+		redisinfra.NewAvailabilityCache(redisClient))
+	// Released inventory is offered to the waitlist, with a notification and
+	// a push to the guest's connected clients.
+	waitlistSvc := service.NewWaitlistService(waitlistRepo, roomRepo, waitlistOfferTTL,
+		service.WithWaitlistNotifier(&notifAdapter{svc: notifSvc}),
+		service.WithWaitlistPusher(hub),
+		service.WithWaitlistAvailabilityInvalidator(availabilitySvc))
 	authSvc := service.NewAuthService(userRepo, tokenRepo, tokenMgr)
 	hotelSvc := service.NewHotelService(hotelRepo)
-	roomSvc := service.NewRoomService(roomRepo, hotelRepo)
-	inventorySvc := service.NewInventoryService(inventoryRepo, roomRepo, hotelRepo)
+	roomSvc := service.NewRoomService(roomRepo, hotelRepo,
+		service.WithRoomAvailabilityInvalidator(availabilitySvc))
+	inventorySvc := service.NewInventoryService(inventoryRepo, ratePlanRepo, roomRepo, hotelRepo,
+		service.WithInventoryReleaseListener(waitlistSvc),
+		service.WithInventoryAvailabilityInvalidator(availabilitySvc))
 	reviewSvc := service.NewReviewService(reviewRepo)
 	searchCache := redisinfra.NewSearchCache(redisClient)
 	searchSvc := service.NewSearchService(searchRepo, searchCache)
-	paymentSvc := service.NewPaymentService(paymentRepo, outboxRepo, time.Now().UnixNano())
-	notifSvc := service.NewNotificationService(notifRepo)
+	// The API only reads payments; the worker is the one talking to the gateway.
+	paymentSvc := service.NewPaymentService(paymentRepo, outboxRepo, nil)
 	adminSvc := service.NewAdminService(userRepo, bookingRepo, outboxRepo)
 	chatSvc := service.NewChatService(chatRepo, hotelRepo)
+	policySvc := service.NewCancellationPolicyService(policyRepo, roomRepo, hotelRepo)
+	fxSvc := service.NewExchangeRateService(fxRepo)
+	chargeSvc := service.NewHotelChargeService(chargeRepo, hotelRepo)
+	promoSvc := service.NewPromotionService(promoRepo, hotelRepo, roomRepo)
 
-	// 7b. WebSocket Hub (created before RabbitMQ so it can receive broadcasts)
-	hub := handler.NewHub()
-
-	// 7c. RabbitMQ (optional — warn and continue if unavailable)
+	// 7b. RabbitMQ (optional — warn and continue if unavailable)
 	var sagaOrch service.SagaOrchestratorInterface
 	rabbitConn, rabbitErr := rabbitinfra.NewConnection(cfg.RabbitMQURL, logger)
 	if rabbitErr != nil {
 		logger.Warn("RabbitMQ not available, saga orchestration disabled", zap.Error(rabbitErr))
-		sagaOrch = service.NewSagaOrchestrator(bookingRepo, paymentRepo, outboxRepo, inventorySvc)
+		sagaOrch = service.NewSagaOrchestrator(bookingRepo, paymentRepo, outboxRepo, inventorySvc,
+			service.WithRefundRepository(refundRepo),
+			service.WithCapturePolicies(policyRepo),
+			service.WithPromotionReleaser(promoRepo),
+			service.WithSagaTxManager(txManager))
 	} else {
 		defer rabbitConn.Close()
 		logger.Info("connected to RabbitMQ")
@@ -138,7 +189,21 @@ func main() {
 		}
 
 		publisher := rabbitinfra.NewPublisher(rabbitConn, logger)
-		outboxWorker := service.NewOutboxWorker(outboxRepo, publisher, logger)
+		outboxOpts := []service.OutboxWorkerOption{
+			service.WithOutboxRetryPolicy(service.OutboxRetryPolicy{
+				MaxAttempts:       cfg.OutboxMaxAttempts,
+				MaxAttemptsByType: cfg.OutboxMaxAttemptsPerType(),
+				BaseDelay:         outboxRetryBaseDelay,
+				MaxDelay:          outboxRetryMaxDelay,
+			}),
+		}
+		if outboxListener, listenErr := repository.ListenForOutboxEvents(cfg.DBConnString()); listenErr != nil {
+			logger.Warn("outbox notifications unavailable, polling only", zap.Error(listenErr))
+		} else {
+			defer outboxListener.Close()
+			outboxOpts = append(outboxOpts, service.WithOutboxWakeups(outboxListener.Wakeups()))
+		}
+		outboxWorker := service.NewOutboxWorker(outboxRepo, publisher, logger, outboxOpts...)
 		go func() {
 			workerCtx, workerCancel := context.WithCancel(context.Background())
 			defer workerCancel()
@@ -147,7 +212,11 @@ func main() {
 			}
 		}()
 
-		sagaOrch = service.NewSagaOrchestrator(bookingRepo, paymentRepo, outboxRepo, inventorySvc)
+		sagaOrch = service.NewSagaOrchestrator(bookingRepo, paymentRepo, outboxRepo, inventorySvc,
+			service.WithRefundRepository(refundRepo),
+			service.WithCapturePolicies(policyRepo),
+			service.WithPromotionReleaser(promoRepo),
+			service.WithSagaTxManager(txManager))
 
 		// Notification broadcast consumer: receives payment result events and
 		// pushes real-time booking status updates to connected WebSocket clients.
@@ -161,6 +230,21 @@ func main() {
 		}()
 	}
 
+	// 7c. Booking service (after the saga so modifications of paid bookings can
+	// top up or refund the price difference)
+	bookingSvc := service.NewBookingService(bookingRepo, roomRepo,
+		service.WithPaymentAdjuster(sagaOrch),
+		service.WithCancellationPolicies(policyRepo, paymentRepo, sagaOrch),
+		service.WithExchangeRates(fxRepo),
+		service.WithRatePlans(ratePlanRepo),
+		service.WithHotelCharges(chargeRepo),
+		service.WithQuotes(quoteSigner, quoteTTL),
+		service.WithPromotions(promoRepo),
+		service.WithWaitlist(waitlistRepo, waitlistSvc),
+		service.WithAvailabilityInvalidator(availabilitySvc),
+		service.WithBookingTxManager(txManager),
+	)
+
 	// 8. Handlers
 	bookingHandler := handler.NewBookingHandler(bookingSvc)
 	authHandler := handler.NewAuthHandler(authSvc)
@@ -175,6 +259,15 @@ func main() {
 	chatHandler := handler.NewChatHandler(chatSvc, hub)
 	wsHandler := handler.NewWSHandler(hub, tokenMgr, handler.WithChatService(chatSvc))
 	adminHandler := handler.NewAdminHandler(adminSvc)
+	policyHandler := handler.NewCancellationPolicyHandler(policySvc)
+	webhookSvc := service.NewPaymentWebhookService(paymentRepo, refundRepo, outboxRepo, sagaOrch, cfg.WebhookSecrets(),
+		service.WithWebhookTxManager(txManager))
+	webhookHandler := handler.NewPaymentWebhookHandler(webhookSvc)
+	fxHandler := handler.NewExchangeRateHandler(fxSvc)
+	chargeHandler := handler.NewHotelChargeHandler(chargeSvc)
+	promoHandler := handler.NewPromotionHandler(promoSvc)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistSvc, bookingSvc)
+	availabilityHandler := handler.NewAvailabilityHandler(availabilitySvc)
 
 	// 8b. Optional distributed tracing (graceful degradation).
 	tracerShutdown, tracerErr := observability.InitTracer(context.Background(), cfg.AppName, cfg.JaegerEndpoint)
@@ -212,6 +305,13 @@ func main() {
 		wsHandler,
 		adminHandler,
 		chatHandler,
+		policyHandler,
+		webhookHandler,
+		fxHandler,
+		chargeHandler,
+		promoHandler,
+		waitlistHandler,
+		availabilityHandler,
 	)
 
 	// 10. Server with graceful shutdown
@@ -239,3 +339,13 @@ func main() {
 	}
 	logger.Info("server exited")
 }
+
+// notifAdapter adapts NotificationService to the NotificationSender interface.
+type notifAdapter struct {
+	svc *service.NotificationService
+}
+
+func (a *notifAdapter) Notify(ctx context.Context, userID string, notifType domain.NotificationType, title, message string, data map[string]any) error {
+	_, err := a.svc.CreateNotification(ctx, userID, notifType, title, message, data)
+	return err
+}
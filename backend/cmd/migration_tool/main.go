@@ -47,7 +47,7 @@ func main() {
 	fmt.Println("Initializing test data...")
 	_, err = db.Exec(`
 		INSERT INTO hotels (name, location) VALUES ('Grand Budapest', 'Zubrowka') ON CONFLICT DO NOTHING;
-		INSERT INTO rooms (hotel_id, name, capacity, price_per_night) VALUES (1, 'Suite 101', 2, 200.0) ON CONFLICT DO NOTHING;
+		INSERT INTO rooms (hotel_id, name, capacity, price_per_night) VALUES (1, 'Suite 101', 2, 20000) ON CONFLICT DO NOTHING;
 		
 		INSERT INTO inventory (room_id, date, total_inventory, booked_count)
 		VALUES (1, '2024-12-25', 1, 0)
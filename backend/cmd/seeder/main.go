@@ -58,6 +58,21 @@ var (
 		"Sydney":           "Australia",
 		"Dubai":            "United Arab Emirates",
 	}
+	// currencies is what hotels in each country price their rooms in.
+	currencies = map[string]string{
+		"Vietnam":              "VND",
+		"Thailand":             "THB",
+		"Singapore":            "SGD",
+		"Malaysia":             "MYR",
+		"Indonesia":            "IDR",
+		"Japan":                "JPY",
+		"South Korea":          "KRW",
+		"France":               "EUR",
+		"United Kingdom":       "GBP",
+		"United States":        "USD",
+		"Australia":            "AUD",
+		"United Arab Emirates": "AED",
+	}
 	cityCoords = map[string][2]float64{
 		"Ho Chi Minh City": {10.762622, 106.660172},
 		"Hanoi":            {21.027764, 105.834160},
@@ -160,8 +175,8 @@ func insertHotelBatch(ctx context.Context, db *sql.DB, rng *rand.Rand, count int
 
 	stmt, err := tx.PrepareContext(ctx, `
 		INSERT INTO hotels (owner_id, name, location, address, city, country, latitude, longitude,
-		                    amenities, images, star_rating, status, description, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)
+		                    amenities, images, star_rating, status, description, currency, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)
 		RETURNING id, created_at, updated_at`)
 	if err != nil {
 		return nil, fmt.Errorf("prepare: %w", err)
@@ -205,6 +220,7 @@ func insertHotelBatch(ctx context.Context, db *sql.DB, rng *rand.Rand, count int
 			starRating,                            // star_rating
 			string(domain.HotelStatusApproved),    // status
 			fmt.Sprintf("A beautiful %d-star hotel in %s.", starRating, city), // description
+			currencies[country], // currency
 			now, // created_at
 			now, // updated_at
 		).Scan(&hotelID, &createdAt, &updatedAt)
@@ -225,6 +241,7 @@ func insertHotelBatch(ctx context.Context, db *sql.DB, rng *rand.Rand, count int
 			StarRating:  starRating,
 			Status:      domain.HotelStatusApproved,
 			Description: fmt.Sprintf("A beautiful %d-star hotel in %s.", starRating, city),
+			Currency:    currencies[country],
 			CreatedAt:   createdAt,
 			UpdatedAt:   updatedAt,
 		})
@@ -4,6 +4,7 @@ import (
 	"booking-app/internal/domain"
 	"context"
 	"encoding/json"
+	"errors"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
@@ -14,6 +15,100 @@ type sagaResultHandler interface {
 	HandlePaymentSuccess(ctx context.Context, paymentID string) error
 	HandlePaymentFailure(ctx context.Context, paymentID string, reason string) error
 	HandlePaymentTimeout(ctx context.Context, paymentID string) error
+	HandleRefundSucceeded(ctx context.Context, refundID, gatewayRef string) error
+	HandleRefundFailed(ctx context.Context, refundID, reason string) error
+	HandleCaptureFailed(ctx context.Context, paymentID, reason string) error
+}
+
+// refundProcessor sends requested refunds to the payment gateway.
+type refundProcessor interface {
+	ProcessRefund(ctx context.Context, refundID string) error
+}
+
+// voidProcessor releases authorizations at the payment gateway.
+type voidProcessor interface {
+	ProcessVoid(ctx context.Context, paymentID string) error
+}
+
+// unitOfWork runs functions in a database transaction.
+type unitOfWork interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// eventLedger records which messages the worker has processed.
+type eventLedger interface {
+	IsEventProcessed(ctx context.Context, eventID string) (bool, error)
+	MarkProcessed(ctx context.Context, eventID string) error
+	ClaimEvent(ctx context.Context, eventID string) (bool, error)
+}
+
+// errNack rolls back the unit of work of a message its handler nacked.
+var errNack = errors.New("message nacked")
+
+// processOnce runs handle for a delivery at most once. The message ID is
+// claimed in the same transaction as the saga state change handle makes, so
+// a redelivered message finds it claimed and is acked without running handle
+// again, and a nacked or failed one leaves no claim behind. Messages without
+// an ID, published before events carried one, are handled unchecked.
+func processOnce(ctx context.Context, delivery amqp.Delivery, uow unitOfWork, ledger eventLedger, logger *zap.Logger, handle func(ctx context.Context) bool) bool {
+	if delivery.MessageId == "" {
+		return handle(ctx)
+	}
+
+	skipped := false
+	err := uow.WithinTx(ctx, func(ctx context.Context) error {
+		claimed, err := ledger.ClaimEvent(ctx, delivery.MessageId)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			skipped = true
+			return nil
+		}
+		if !handle(ctx) {
+			return errNack
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, errNack) {
+			logger.Error("failed to process event", zap.String("message_id", delivery.MessageId), zap.Error(err))
+		}
+		return false
+	}
+	if skipped {
+		logger.Debug("skipping already-processed event", zap.String("message_id", delivery.MessageId))
+	}
+	return true
+}
+
+// processThenRecord runs handle for a delivery unless its message ID was
+// recorded as processed, and records it once handle succeeds. It is for
+// handlers that call the payment gateway, which must not happen inside a
+// transaction; the gateway calls carry idempotency keys of their own, so a
+// message redelivered before it was recorded is harmless.
+func processThenRecord(ctx context.Context, delivery amqp.Delivery, ledger eventLedger, logger *zap.Logger, handle func(ctx context.Context) bool) bool {
+	if delivery.MessageId == "" {
+		return handle(ctx)
+	}
+
+	processed, err := ledger.IsEventProcessed(ctx, delivery.MessageId)
+	if err != nil {
+		logger.Error("failed to check processed event", zap.Error(err))
+		return false
+	}
+	if processed {
+		logger.Debug("skipping already-processed event", zap.String("message_id", delivery.MessageId))
+		return true
+	}
+
+	if !handle(ctx) {
+		return false
+	}
+	if err := ledger.MarkProcessed(ctx, delivery.MessageId); err != nil {
+		logger.Warn("failed to mark event processed", zap.String("message_id", delivery.MessageId), zap.Error(err))
+	}
+	return true
 }
 
 // handlePaymentSucceeded processes a payment.succeeded event.
@@ -97,3 +192,141 @@ func handlePaymentTimedOut(ctx context.Context, delivery amqp.Delivery, sagaOrch
 	logger.Info("booking cancelled on timeout", zap.String("payment_id", payload.PaymentID), zap.Int("booking_id", payload.BookingID))
 	return true
 }
+
+// handleRefundRequested processes a payment.refund.requested event.
+// Sends the refund to the gateway, which emits the refund result event.
+func handleRefundRequested(ctx context.Context, delivery amqp.Delivery, refunds refundProcessor, logger *zap.Logger) bool {
+	var payload domain.RefundRequestedPayload
+	if err := json.Unmarshal(delivery.Body, &payload); err != nil {
+		logger.Error("malformed payment.refund.requested payload",
+			zap.String("body", string(delivery.Body)),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("processing refund", zap.String("refund_id", payload.RefundID), zap.String("payment_id", payload.PaymentID))
+
+	if err := refunds.ProcessRefund(ctx, payload.RefundID); err != nil {
+		logger.Error("ProcessRefund failed",
+			zap.String("refund_id", payload.RefundID),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("refund sent to gateway", zap.String("refund_id", payload.RefundID))
+	return true
+}
+
+// handleRefundSucceeded processes a payment.refund.succeeded event.
+// Calls sagaOrch.HandleRefundSucceeded to add the refund to its payment's refunded total.
+func handleRefundSucceeded(ctx context.Context, delivery amqp.Delivery, sagaOrch sagaResultHandler, logger *zap.Logger) bool {
+	var payload domain.RefundResultPayload
+	if err := json.Unmarshal(delivery.Body, &payload); err != nil {
+		logger.Error("malformed payment.refund.succeeded payload",
+			zap.String("body", string(delivery.Body)),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("handling payment.refund.succeeded", zap.String("refund_id", payload.RefundID), zap.String("payment_id", payload.PaymentID))
+
+	if err := sagaOrch.HandleRefundSucceeded(ctx, payload.RefundID, payload.GatewayRef); err != nil {
+		logger.Error("HandleRefundSucceeded failed",
+			zap.String("refund_id", payload.RefundID),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("refund recorded", zap.String("refund_id", payload.RefundID), zap.Int("booking_id", payload.BookingID))
+	return true
+}
+
+// handleRefundFailed processes a payment.refund.failed event.
+// Calls sagaOrch.HandleRefundFailed to mark the refund failed.
+func handleRefundFailed(ctx context.Context, delivery amqp.Delivery, sagaOrch sagaResultHandler, logger *zap.Logger) bool {
+	var payload domain.RefundResultPayload
+	if err := json.Unmarshal(delivery.Body, &payload); err != nil {
+		logger.Error("malformed payment.refund.failed payload",
+			zap.String("body", string(delivery.Body)),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("handling payment.refund.failed",
+		zap.String("refund_id", payload.RefundID),
+		zap.String("payment_id", payload.PaymentID),
+		zap.String("reason", payload.Reason),
+	)
+
+	if err := sagaOrch.HandleRefundFailed(ctx, payload.RefundID, payload.Reason); err != nil {
+		logger.Error("HandleRefundFailed failed",
+			zap.String("refund_id", payload.RefundID),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("refund marked failed", zap.String("refund_id", payload.RefundID), zap.Int("booking_id", payload.BookingID))
+	return true
+}
+
+// handleVoidRequested processes a payment.void.requested event.
+// Releases the authorization of a booking cancelled before capture.
+func handleVoidRequested(ctx context.Context, delivery amqp.Delivery, voids voidProcessor, logger *zap.Logger) bool {
+	var payload domain.PaymentResultPayload
+	if err := json.Unmarshal(delivery.Body, &payload); err != nil {
+		logger.Error("malformed payment.void.requested payload",
+			zap.String("body", string(delivery.Body)),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("processing void", zap.String("payment_id", payload.PaymentID), zap.Int("booking_id", payload.BookingID))
+
+	if err := voids.ProcessVoid(ctx, payload.PaymentID); err != nil {
+		logger.Error("ProcessVoid failed",
+			zap.String("payment_id", payload.PaymentID),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("authorization voided", zap.String("payment_id", payload.PaymentID))
+	return true
+}
+
+// handleCaptureFailed processes a payment.capture_failed event.
+// Calls sagaOrch.HandleCaptureFailed to tell the guest; the booking is kept.
+func handleCaptureFailed(ctx context.Context, delivery amqp.Delivery, sagaOrch sagaResultHandler, logger *zap.Logger) bool {
+	var payload domain.PaymentResultPayload
+	if err := json.Unmarshal(delivery.Body, &payload); err != nil {
+		logger.Error("malformed payment.capture_failed payload",
+			zap.String("body", string(delivery.Body)),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("handling payment.capture_failed",
+		zap.String("payment_id", payload.PaymentID),
+		zap.Int("booking_id", payload.BookingID),
+		zap.String("reason", payload.Reason),
+	)
+
+	if err := sagaOrch.HandleCaptureFailed(ctx, payload.PaymentID, payload.Reason); err != nil {
+		logger.Error("HandleCaptureFailed failed",
+			zap.String("payment_id", payload.PaymentID),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	logger.Info("guest told of failed capture", zap.String("payment_id", payload.PaymentID), zap.Int("booking_id", payload.BookingID))
+	return true
+}
@@ -14,9 +14,12 @@ import (
 // --- Mock SagaOrchestrator ---
 
 type mockSagaOrch struct {
-	handlePaymentSuccessFn func(ctx context.Context, paymentID string) error
-	handlePaymentFailureFn func(ctx context.Context, paymentID string, reason string) error
-	handlePaymentTimeoutFn func(ctx context.Context, paymentID string) error
+	handlePaymentSuccessFn  func(ctx context.Context, paymentID string) error
+	handlePaymentFailureFn  func(ctx context.Context, paymentID string, reason string) error
+	handlePaymentTimeoutFn  func(ctx context.Context, paymentID string) error
+	handleRefundSucceededFn func(ctx context.Context, refundID, gatewayRef string) error
+	handleRefundFailedFn    func(ctx context.Context, refundID, reason string) error
+	handleCaptureFailedFn   func(ctx context.Context, paymentID, reason string) error
 }
 
 func (m *mockSagaOrch) HandlePaymentSuccess(ctx context.Context, paymentID string) error {
@@ -31,6 +34,27 @@ func (m *mockSagaOrch) HandlePaymentTimeout(ctx context.Context, paymentID strin
 	return m.handlePaymentTimeoutFn(ctx, paymentID)
 }
 
+func (m *mockSagaOrch) HandleRefundSucceeded(ctx context.Context, refundID, gatewayRef string) error {
+	if m.handleRefundSucceededFn != nil {
+		return m.handleRefundSucceededFn(ctx, refundID, gatewayRef)
+	}
+	return nil
+}
+
+func (m *mockSagaOrch) HandleRefundFailed(ctx context.Context, refundID, reason string) error {
+	if m.handleRefundFailedFn != nil {
+		return m.handleRefundFailedFn(ctx, refundID, reason)
+	}
+	return nil
+}
+
+func (m *mockSagaOrch) HandleCaptureFailed(ctx context.Context, paymentID, reason string) error {
+	if m.handleCaptureFailedFn != nil {
+		return m.handleCaptureFailedFn(ctx, paymentID, reason)
+	}
+	return nil
+}
+
 func makeMockSagaOrch(overrides mockSagaOrch) *mockSagaOrch {
 	defaults := &mockSagaOrch{
 		handlePaymentSuccessFn: func(ctx context.Context, paymentID string) error { return nil },
@@ -46,6 +70,9 @@ func makeMockSagaOrch(overrides mockSagaOrch) *mockSagaOrch {
 	if overrides.handlePaymentTimeoutFn != nil {
 		defaults.handlePaymentTimeoutFn = overrides.handlePaymentTimeoutFn
 	}
+	defaults.handleRefundSucceededFn = overrides.handleRefundSucceededFn
+	defaults.handleRefundFailedFn = overrides.handleRefundFailedFn
+	defaults.handleCaptureFailedFn = overrides.handleCaptureFailedFn
 	return defaults
 }
 
@@ -247,3 +274,308 @@ func TestHandlePaymentTimedOut_Nacks_OnMalformedPayload(t *testing.T) {
 		t.Error("expected ack=false for malformed JSON")
 	}
 }
+
+// --- Tests: refund handlers ---
+
+type mockRefundProcessor struct {
+	processRefundFn func(ctx context.Context, refundID string) error
+}
+
+func (m *mockRefundProcessor) ProcessRefund(ctx context.Context, refundID string) error {
+	return m.processRefundFn(ctx, refundID)
+}
+
+func TestHandleRefundRequested_SendsRefund(t *testing.T) {
+	var gotID string
+	refunds := &mockRefundProcessor{
+		processRefundFn: func(ctx context.Context, refundID string) error {
+			gotID = refundID
+			return nil
+		},
+	}
+
+	payload := domain.RefundRequestedPayload{RefundID: "ref-1", PaymentID: "pay-1", BookingID: 1, Amount: 50}
+	ack := handleRefundRequested(context.Background(), makeDelivery("payment.refund.requested", payload), refunds, testLogger)
+
+	if !ack {
+		t.Error("expected ack=true on success")
+	}
+	if gotID != "ref-1" {
+		t.Errorf("expected refund ref-1 processed, got %q", gotID)
+	}
+}
+
+func TestHandleRefundRequested_Nacks_OnProcessError(t *testing.T) {
+	refunds := &mockRefundProcessor{
+		processRefundFn: func(ctx context.Context, refundID string) error {
+			return errors.New("db error")
+		},
+	}
+
+	payload := domain.RefundRequestedPayload{RefundID: "ref-1"}
+	ack := handleRefundRequested(context.Background(), makeDelivery("payment.refund.requested", payload), refunds, testLogger)
+
+	if ack {
+		t.Error("expected ack=false when processing fails")
+	}
+}
+
+func TestHandleRefundSucceeded_PassesGatewayRef(t *testing.T) {
+	var gotID, gotRef string
+	sagaOrch := makeMockSagaOrch(mockSagaOrch{
+		handleRefundSucceededFn: func(ctx context.Context, refundID, gatewayRef string) error {
+			gotID, gotRef = refundID, gatewayRef
+			return nil
+		},
+	})
+
+	payload := domain.RefundResultPayload{RefundID: "ref-1", PaymentID: "pay-1", GatewayRef: "RF-9"}
+	ack := handleRefundSucceeded(context.Background(), makeDelivery("payment.refund.succeeded", payload), sagaOrch, testLogger)
+
+	if !ack {
+		t.Error("expected ack=true on success")
+	}
+	if gotID != "ref-1" || gotRef != "RF-9" {
+		t.Errorf("expected ref-1/RF-9, got %q/%q", gotID, gotRef)
+	}
+}
+
+func TestHandleRefundSucceeded_Nacks_OnMalformedPayload(t *testing.T) {
+	sagaOrch := makeMockSagaOrch(mockSagaOrch{})
+	delivery := amqp.Delivery{
+		RoutingKey: "payment.refund.succeeded",
+		Body:       []byte("not-json"),
+	}
+
+	if handleRefundSucceeded(context.Background(), delivery, sagaOrch, testLogger) {
+		t.Error("expected ack=false for malformed JSON")
+	}
+}
+
+func TestHandleRefundFailed_PassesReason(t *testing.T) {
+	var gotReason string
+	sagaOrch := makeMockSagaOrch(mockSagaOrch{
+		handleRefundFailedFn: func(ctx context.Context, refundID, reason string) error {
+			gotReason = reason
+			return nil
+		},
+	})
+
+	payload := domain.RefundResultPayload{RefundID: "ref-1", Reason: "card closed"}
+	ack := handleRefundFailed(context.Background(), makeDelivery("payment.refund.failed", payload), sagaOrch, testLogger)
+
+	if !ack {
+		t.Error("expected ack=true on success")
+	}
+	if gotReason != "card closed" {
+		t.Errorf("expected reason %q, got %q", "card closed", gotReason)
+	}
+}
+
+func TestHandleRefundFailed_Nacks_OnSagaError(t *testing.T) {
+	sagaOrch := makeMockSagaOrch(mockSagaOrch{
+		handleRefundFailedFn: func(ctx context.Context, refundID, reason string) error {
+			return errors.New("db error")
+		},
+	})
+
+	payload := domain.RefundResultPayload{RefundID: "ref-1"}
+	if handleRefundFailed(context.Background(), makeDelivery("payment.refund.failed", payload), sagaOrch, testLogger) {
+		t.Error("expected ack=false when saga returns error")
+	}
+}
+
+// --- Tests: authorization handlers ---
+
+type mockVoidProcessor struct {
+	processVoidFn func(ctx context.Context, paymentID string) error
+}
+
+func (m *mockVoidProcessor) ProcessVoid(ctx context.Context, paymentID string) error {
+	return m.processVoidFn(ctx, paymentID)
+}
+
+func TestHandleVoidRequested_VoidsPayment(t *testing.T) {
+	var gotID string
+	voids := &mockVoidProcessor{
+		processVoidFn: func(ctx context.Context, paymentID string) error {
+			gotID = paymentID
+			return nil
+		},
+	}
+
+	payload := domain.PaymentResultPayload{PaymentID: "pay-1", BookingID: 1, Reason: "booking cancelled"}
+	ack := handleVoidRequested(context.Background(), makeDelivery("payment.void.requested", payload), voids, testLogger)
+
+	if !ack {
+		t.Error("expected ack=true on success")
+	}
+	if gotID != "pay-1" {
+		t.Errorf("expected payment pay-1 voided, got %q", gotID)
+	}
+}
+
+func TestHandleVoidRequested_Nacks_OnProcessError(t *testing.T) {
+	voids := &mockVoidProcessor{
+		processVoidFn: func(ctx context.Context, paymentID string) error {
+			return errors.New("gateway down")
+		},
+	}
+
+	payload := domain.PaymentResultPayload{PaymentID: "pay-1"}
+	if handleVoidRequested(context.Background(), makeDelivery("payment.void.requested", payload), voids, testLogger) {
+		t.Error("expected ack=false when the void fails")
+	}
+}
+
+func TestHandleCaptureFailed_PassesReason(t *testing.T) {
+	var gotID, gotReason string
+	sagaOrch := makeMockSagaOrch(mockSagaOrch{
+		handleCaptureFailedFn: func(ctx context.Context, paymentID, reason string) error {
+			gotID, gotReason = paymentID, reason
+			return nil
+		},
+	})
+
+	payload := domain.PaymentResultPayload{PaymentID: "pay-1", Reason: "card_declined"}
+	ack := handleCaptureFailed(context.Background(), makeDelivery("payment.capture_failed", payload), sagaOrch, testLogger)
+
+	if !ack {
+		t.Error("expected ack=true on success")
+	}
+	if gotID != "pay-1" || gotReason != "card_declined" {
+		t.Errorf("expected pay-1/card_declined, got %q/%q", gotID, gotReason)
+	}
+}
+
+func TestHandleCaptureFailed_Nacks_OnMalformedPayload(t *testing.T) {
+	sagaOrch := makeMockSagaOrch(mockSagaOrch{})
+	delivery := amqp.Delivery{
+		RoutingKey: "payment.capture_failed",
+		Body:       []byte("not-json"),
+	}
+
+	if handleCaptureFailed(context.Background(), delivery, sagaOrch, testLogger) {
+		t.Error("expected ack=false for malformed JSON")
+	}
+}
+
+// --- Mock unitOfWork and eventLedger ---
+
+type mockUnitOfWork struct {
+	committed  int
+	rolledBack int
+}
+
+func (m *mockUnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		m.rolledBack++
+		return err
+	}
+	m.committed++
+	return nil
+}
+
+type mockEventLedger struct {
+	processed map[string]bool
+}
+
+func (m *mockEventLedger) IsEventProcessed(_ context.Context, eventID string) (bool, error) {
+	return m.processed[eventID], nil
+}
+
+func (m *mockEventLedger) MarkProcessed(_ context.Context, eventID string) error {
+	m.processed[eventID] = true
+	return nil
+}
+
+func (m *mockEventLedger) ClaimEvent(_ context.Context, eventID string) (bool, error) {
+	if m.processed[eventID] {
+		return false, nil
+	}
+	m.processed[eventID] = true
+	return true, nil
+}
+
+// --- Tests: processOnce / processThenRecord ---
+
+func TestProcessOnce_SkipsRedeliveredMessage(t *testing.T) {
+	uow := &mockUnitOfWork{}
+	ledger := &mockEventLedger{processed: map[string]bool{}}
+	delivery := amqp.Delivery{MessageId: "7d8f1c2e-0000-4000-8000-000000000001", RoutingKey: "payment.succeeded"}
+	handled := 0
+	handle := func(context.Context) bool { handled++; return true }
+
+	if !processOnce(context.Background(), delivery, uow, ledger, testLogger, handle) {
+		t.Fatal("expected first delivery to be acked")
+	}
+	if !processOnce(context.Background(), delivery, uow, ledger, testLogger, handle) {
+		t.Fatal("expected redelivery to be acked")
+	}
+
+	if handled != 1 {
+		t.Errorf("expected the saga to run once, ran %d times", handled)
+	}
+	if uow.committed != 2 {
+		t.Errorf("expected both deliveries to commit, got %d", uow.committed)
+	}
+}
+
+func TestProcessOnce_NackRollsBackClaim(t *testing.T) {
+	uow := &mockUnitOfWork{}
+	ledger := &mockEventLedger{processed: map[string]bool{}}
+	delivery := amqp.Delivery{MessageId: "7d8f1c2e-0000-4000-8000-000000000002", RoutingKey: "payment.succeeded"}
+
+	ack := processOnce(context.Background(), delivery, uow, ledger, testLogger, func(context.Context) bool { return false })
+
+	if ack {
+		t.Error("expected nack when the saga fails")
+	}
+	if uow.rolledBack != 1 {
+		t.Errorf("expected the claim to be rolled back with the saga, got %d rollbacks", uow.rolledBack)
+	}
+}
+
+func TestProcessOnce_HandlesMessagesWithoutID(t *testing.T) {
+	uow := &mockUnitOfWork{}
+	handled := false
+
+	ack := processOnce(context.Background(), amqp.Delivery{RoutingKey: "payment.succeeded"}, uow, &mockEventLedger{processed: map[string]bool{}}, testLogger,
+		func(context.Context) bool { handled = true; return true })
+
+	if !ack || !handled {
+		t.Errorf("expected a message without ID handled and acked, got ack=%v handled=%v", ack, handled)
+	}
+	if uow.committed+uow.rolledBack != 0 {
+		t.Error("expected no claim for a message without ID")
+	}
+}
+
+func TestProcessThenRecord_RecordsAfterSuccess(t *testing.T) {
+	ledger := &mockEventLedger{processed: map[string]bool{}}
+	delivery := amqp.Delivery{MessageId: "7d8f1c2e-0000-4000-8000-000000000003", RoutingKey: "payment.initiated"}
+	handled := 0
+	fail := true
+	handle := func(context.Context) bool {
+		handled++
+		return !fail
+	}
+
+	if processThenRecord(context.Background(), delivery, ledger, testLogger, handle) {
+		t.Fatal("expected nack when the gateway call fails")
+	}
+	if ledger.processed[delivery.MessageId] {
+		t.Fatal("expected a failed message not to be recorded")
+	}
+
+	fail = false
+	if !processThenRecord(context.Background(), delivery, ledger, testLogger, handle) {
+		t.Fatal("expected the retried message to be acked")
+	}
+	if !processThenRecord(context.Background(), delivery, ledger, testLogger, handle) {
+		t.Fatal("expected the redelivered message to be acked")
+	}
+	if handled != 2 {
+		t.Errorf("expected the failed and the retried delivery handled, got %d", handled)
+	}
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"booking-app/internal/config"
 	"booking-app/internal/domain"
+	"booking-app/internal/infrastructure/gateway"
 	"booking-app/internal/infrastructure/rabbitmq"
+	redisinfra "booking-app/internal/infrastructure/redis"
 	"booking-app/internal/observability"
 	"booking-app/internal/repository"
 	"booking-app/internal/service"
@@ -20,6 +22,7 @@ import (
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -48,24 +51,66 @@ func main() {
 		logger.Fatal("could not ping DB", zap.Error(err))
 	}
 
+	// Redis (holds the hotel availability calendars released inventory invalidates).
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+	})
+	if _, err := redisClient.Ping(context.Background()).Result(); err != nil {
+		logger.Fatal("could not connect to Redis", zap.Error(err))
+	}
+	defer redisClient.Close()
+
 	// Repositories.
+	txManager := repository.NewTxManager(db)
 	bookingRepo := repository.NewBookingRepo(db, nil) // locker not needed in worker
 	payRepo := repository.NewPaymentRepo(db)
 	outboxRepo := repository.NewOutboxRepo(db)
 	inventoryRepo := repository.NewInventoryRepo(db)
+	ratePlanRepo := repository.NewRatePlanRepo(db)
 	roomRepo := repository.NewRoomRepo(db)
 	hotelRepo := repository.NewHotelRepo(db)
 	notifRepo := repository.NewNotificationRepo(db)
+	refundRepo := repository.NewRefundRepo(db)
+	promoRepo := repository.NewPromotionRepo(db)
+	waitlistRepo := repository.NewWaitlistRepo(db)
+
+	// Payment gateway.
+	paymentGateway, err := gateway.New(gateway.Config{
+		Provider: cfg.PaymentGateway,
+		BaseURL:  cfg.PaymentGatewayURL,
+		APIKey:   cfg.PaymentGatewayAPIKey,
+		Timeout:  parseDuration(cfg.PaymentGatewayTimeout, "PAYMENT_GATEWAY_TIMEOUT", logger),
+	})
+	if err != nil {
+		logger.Fatal("failed to configure payment gateway", zap.Error(err))
+	}
+	logger.Info("payment gateway configured", zap.String("provider", cfg.PaymentGateway))
 
 	// Services.
-	paymentSvc := service.NewPaymentService(payRepo, outboxRepo, time.Now().UnixNano())
-	inventorySvc := service.NewInventoryService(inventoryRepo, roomRepo, hotelRepo)
+	paymentSvc := service.NewPaymentService(payRepo, outboxRepo, paymentGateway,
+		service.WithRefundProcessing(refundRepo),
+		service.WithPaymentTxManager(txManager))
 	notifSvc := service.NewNotificationService(notifRepo)
+	availabilitySvc := service.NewAvailabilityService(hotelRepo, roomRepo, inventoryRepo, ratePlanRepo,
+		redisinfra.NewAvailabilityCache(redisClient))
+	// Inventory given back by failed payments and expired holds is offered
+	// to the waitlist. Guests are notified; only the API pushes over WebSocket.
+	waitlistSvc := service.NewWaitlistService(waitlistRepo, roomRepo,
+		parseDuration(cfg.WaitlistOfferTTL, "WAITLIST_OFFER_TTL", logger),
+		service.WithWaitlistNotifier(&notifAdapter{svc: notifSvc}),
+		service.WithWaitlistAvailabilityInvalidator(availabilitySvc))
+	inventorySvc := service.NewInventoryService(inventoryRepo, ratePlanRepo, roomRepo, hotelRepo,
+		service.WithInventoryReleaseListener(waitlistSvc),
+		service.WithInventoryAvailabilityInvalidator(availabilitySvc))
 
 	// SagaOrchestrator with notification side-effects.
 	sagaOrch := service.NewSagaOrchestrator(
 		bookingRepo, payRepo, outboxRepo, inventorySvc,
 		service.WithNotificationSender(&notifAdapter{svc: notifSvc}),
+		service.WithRefundRepository(refundRepo),
+		service.WithPromotionReleaser(promoRepo),
+		service.WithSagaTxManager(txManager),
 	)
 
 	// RabbitMQ connection.
@@ -88,8 +133,25 @@ func main() {
 	// Publisher for outbox worker.
 	publisher := rabbitmq.NewPublisher(conn, logger)
 
-	// Outbox worker (publishes pending events to RabbitMQ).
-	outboxWorker := service.NewOutboxWorker(outboxRepo, publisher, logger)
+	// Outbox worker (publishes pending events to RabbitMQ). It shares the
+	// outbox with the API's relay, wakes on notifications of new events and
+	// polls as a fallback.
+	outboxOpts := []service.OutboxWorkerOption{
+		service.WithOutboxRetryPolicy(service.OutboxRetryPolicy{
+			MaxAttempts:       cfg.OutboxMaxAttempts,
+			MaxAttemptsByType: cfg.OutboxMaxAttemptsPerType(),
+			BaseDelay:         parseDuration(cfg.OutboxRetryBaseDelay, "OUTBOX_RETRY_BASE_DELAY", logger),
+			MaxDelay:          parseDuration(cfg.OutboxRetryMaxDelay, "OUTBOX_RETRY_MAX_DELAY", logger),
+		}),
+	}
+	outboxListener, err := repository.ListenForOutboxEvents(cfg.DBConnString())
+	if err != nil {
+		logger.Warn("outbox notifications unavailable, polling only", zap.Error(err))
+	} else {
+		defer outboxListener.Close()
+		outboxOpts = append(outboxOpts, service.WithOutboxWakeups(outboxListener.Wakeups()))
+	}
+	outboxWorker := service.NewOutboxWorker(outboxRepo, publisher, logger, outboxOpts...)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -101,12 +163,52 @@ func main() {
 		}
 	}()
 
+	// Hold expiry reaper (releases inventory held by unpaid bookings).
+	holdCfg := service.HoldExpiryConfig{
+		PendingTTL:         parseDuration(cfg.BookingHoldTTL, "BOOKING_HOLD_TTL", logger),
+		AwaitingPaymentTTL: parseDuration(cfg.PaymentHoldTTL, "PAYMENT_HOLD_TTL", logger),
+		Interval:           parseDuration(cfg.HoldSweepInterval, "HOLD_SWEEP_INTERVAL", logger),
+	}
+	holdWorker := service.NewHoldExpiryWorker(
+		bookingRepo, payRepo, outboxRepo, inventorySvc, holdCfg, logger,
+		service.WithHoldExpiryNotifier(&notifAdapter{svc: notifSvc}),
+		service.WithHoldExpiryTxManager(txManager),
+	)
+
+	go func() {
+		if err := holdWorker.Run(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("hold expiry worker exited with error", zap.Error(err))
+		}
+	}()
+
+	// Waitlist offer reaper (passes unclaimed offers on to the next guests).
+	waitlistWorker := service.NewWaitlistExpiryWorker(waitlistRepo, waitlistSvc,
+		parseDuration(cfg.WaitlistExpirySweepInterval, "WAITLIST_EXPIRY_SWEEP_INTERVAL", logger), logger,
+		service.WithWaitlistExpiryNotifier(&notifAdapter{svc: notifSvc}),
+	)
+
+	go func() {
+		if err := waitlistWorker.Run(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("waitlist expiry worker exited with error", zap.Error(err))
+		}
+	}()
+
+	// Capture scheduler (collects authorized payments before check-in).
+	captureWorker := service.NewCaptureWorker(payRepo, paymentSvc,
+		parseDuration(cfg.CaptureSweepInterval, "CAPTURE_SWEEP_INTERVAL", logger), logger)
+
+	go func() {
+		if err := captureWorker.Run(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("capture worker exited with error", zap.Error(err))
+		}
+	}()
+
 	// Consumer for all payment events (payment.# via booking.payments queue).
 	consumer := rabbitmq.NewConsumer(conn, "booking.payments", "payment-worker", logger)
 
 	go func() {
 		err := consumer.Consume(ctx, func(ctx context.Context, delivery amqp.Delivery) bool {
-			return handleDelivery(ctx, delivery, paymentSvc, outboxRepo, sagaOrch, logger)
+			return handleDelivery(ctx, delivery, paymentSvc, txManager, outboxRepo, sagaOrch, logger)
 		})
 		if err != nil && ctx.Err() == nil {
 			logger.Error("consumer exited with error", zap.Error(err))
@@ -125,28 +227,40 @@ func main() {
 }
 
 // handleDelivery routes incoming RabbitMQ messages to the appropriate handler.
-// Returns true to ack, false to nack.
-func handleDelivery(ctx context.Context, delivery amqp.Delivery, paymentSvc *service.PaymentService, outboxRepo repository.OutboxRepository, sagaOrch sagaResultHandler, logger *zap.Logger) bool {
-	// Check idempotency.
-	alreadyProcessed, err := outboxRepo.IsEventProcessed(ctx, delivery.MessageId)
-	if err != nil {
-		logger.Error("failed to check processed event", zap.Error(err))
-		return false
+// Returns true to ack, false to nack. Saga results are processed at most once,
+// in one transaction with the record of their message ID; gateway calls are
+// recorded once they succeed.
+func handleDelivery(ctx context.Context, delivery amqp.Delivery, paymentSvc *service.PaymentService, txManager repository.TxManager, outboxRepo repository.OutboxRepository, sagaOrch sagaResultHandler, logger *zap.Logger) bool {
+	// Events emitted while handling the message keep its correlation ID.
+	ctx = observability.WithCorrelationID(ctx, delivery.CorrelationId)
+	logger = logger.With(zap.String("message_id", delivery.MessageId), zap.String("correlation_id", delivery.CorrelationId))
+
+	inTx := func(handle func(ctx context.Context) bool) bool {
+		return processOnce(ctx, delivery, txManager, outboxRepo, logger, handle)
 	}
-	if alreadyProcessed {
-		logger.Debug("skipping already-processed event", zap.String("message_id", delivery.MessageId))
-		return true
+	thenRecord := func(handle func(ctx context.Context) bool) bool {
+		return processThenRecord(ctx, delivery, outboxRepo, logger, handle)
 	}
 
 	switch delivery.RoutingKey {
 	case "payment.initiated":
-		return handlePaymentInitiated(ctx, delivery, paymentSvc, logger)
+		return thenRecord(func(ctx context.Context) bool { return handlePaymentInitiated(ctx, delivery, paymentSvc, logger) })
 	case "payment.succeeded":
-		return handlePaymentSucceeded(ctx, delivery, sagaOrch, logger)
+		return inTx(func(ctx context.Context) bool { return handlePaymentSucceeded(ctx, delivery, sagaOrch, logger) })
 	case "payment.failed":
-		return handlePaymentFailed(ctx, delivery, sagaOrch, logger)
+		return inTx(func(ctx context.Context) bool { return handlePaymentFailed(ctx, delivery, sagaOrch, logger) })
 	case "payment.timed_out":
-		return handlePaymentTimedOut(ctx, delivery, sagaOrch, logger)
+		return inTx(func(ctx context.Context) bool { return handlePaymentTimedOut(ctx, delivery, sagaOrch, logger) })
+	case "payment.refund.requested":
+		return thenRecord(func(ctx context.Context) bool { return handleRefundRequested(ctx, delivery, paymentSvc, logger) })
+	case "payment.refund.succeeded":
+		return inTx(func(ctx context.Context) bool { return handleRefundSucceeded(ctx, delivery, sagaOrch, logger) })
+	case "payment.refund.failed":
+		return inTx(func(ctx context.Context) bool { return handleRefundFailed(ctx, delivery, sagaOrch, logger) })
+	case "payment.void.requested":
+		return thenRecord(func(ctx context.Context) bool { return handleVoidRequested(ctx, delivery, paymentSvc, logger) })
+	case "payment.capture_failed":
+		return inTx(func(ctx context.Context) bool { return handleCaptureFailed(ctx, delivery, sagaOrch, logger) })
 	default:
 		logger.Warn("unknown routing key", zap.String("routing_key", delivery.RoutingKey))
 		return false
@@ -177,6 +291,15 @@ func handlePaymentInitiated(ctx context.Context, delivery amqp.Delivery, payment
 	return true
 }
 
+// parseDuration parses a duration setting, exiting on a malformed value.
+func parseDuration(value, envKey string, logger *zap.Logger) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Fatal("invalid "+envKey, zap.Error(err))
+	}
+	return d
+}
+
 // notifAdapter adapts NotificationService to the NotificationSender interface.
 type notifAdapter struct {
 	svc *service.NotificationService
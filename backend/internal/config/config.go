@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 // Config holds all application configuration loaded from environment variables.
@@ -41,6 +42,42 @@ type Config struct {
 	OTELEndpoint    string
 	OTELServiceName string
 	JaegerEndpoint  string
+
+	// Hold expiry: how long unpaid bookings may hold inventory (worker).
+	BookingHoldTTL    string
+	PaymentHoldTTL    string
+	HoldSweepInterval string
+
+	// CaptureSweepInterval is how often the worker captures due authorizations.
+	CaptureSweepInterval string
+
+	// Payment gateway: "scripted" (deterministic fake) or "http".
+	PaymentGateway        string
+	PaymentGatewayURL     string
+	PaymentGatewayAPIKey  string
+	PaymentGatewayTimeout string
+	// PaymentWebhookSecrets lists webhook signing secrets as
+	// "provider:secret,provider:secret".
+	PaymentWebhookSecrets string
+
+	// Price quotes: the secret quote tokens are signed with and how long a
+	// quote can be booked at its price.
+	QuoteTokenSecret string
+	QuoteTTL         string
+
+	// Waitlist: how long an offer holds a released stay for a waiting guest,
+	// and how often the worker lapses unclaimed offers.
+	WaitlistOfferTTL            string
+	WaitlistExpirySweepInterval string
+
+	// Outbox relay: how many times an event is offered to the broker before
+	// it is dead-lettered, overridden per event type as
+	// "EventType:attempts,EventType:attempts", and the backoff between
+	// attempts, which doubles from the base delay up to the max delay.
+	OutboxMaxAttempts       int
+	OutboxMaxAttemptsByType string
+	OutboxRetryBaseDelay    string
+	OutboxRetryMaxDelay     string
 }
 
 // IsProduction returns true when running in production mode.
@@ -79,7 +116,62 @@ func Load() *Config {
 		OTELEndpoint:    getEnv("OTEL_ENDPOINT", "http://localhost:4318"),
 		OTELServiceName: getEnv("OTEL_SERVICE_NAME", "booking-app"),
 		JaegerEndpoint:  getEnv("JAEGER_ENDPOINT", "http://localhost:4318"),
+
+		BookingHoldTTL:    getEnv("BOOKING_HOLD_TTL", "15m"),
+		PaymentHoldTTL:    getEnv("PAYMENT_HOLD_TTL", "30m"),
+		HoldSweepInterval: getEnv("HOLD_SWEEP_INTERVAL", "1m"),
+
+		CaptureSweepInterval: getEnv("CAPTURE_SWEEP_INTERVAL", "5m"),
+
+		PaymentGateway:        getEnv("PAYMENT_GATEWAY", "scripted"),
+		PaymentGatewayURL:     getEnv("PAYMENT_GATEWAY_URL", ""),
+		PaymentGatewayAPIKey:  getEnv("PAYMENT_GATEWAY_API_KEY", ""),
+		PaymentGatewayTimeout: getEnv("PAYMENT_GATEWAY_TIMEOUT", "10s"),
+		PaymentWebhookSecrets: getEnv("PAYMENT_WEBHOOK_SECRETS", ""),
+
+		QuoteTokenSecret: getEnv("QUOTE_TOKEN_SECRET", "change-me-in-production"),
+		QuoteTTL:         getEnv("QUOTE_TTL", "15m"),
+
+		WaitlistOfferTTL:            getEnv("WAITLIST_OFFER_TTL", "30m"),
+		WaitlistExpirySweepInterval: getEnv("WAITLIST_EXPIRY_SWEEP_INTERVAL", "1m"),
+
+		OutboxMaxAttempts:       getEnvInt("OUTBOX_MAX_ATTEMPTS", 5),
+		OutboxMaxAttemptsByType: getEnv("OUTBOX_MAX_ATTEMPTS_BY_TYPE", ""),
+		OutboxRetryBaseDelay:    getEnv("OUTBOX_RETRY_BASE_DELAY", "1m"),
+		OutboxRetryMaxDelay:     getEnv("OUTBOX_RETRY_MAX_DELAY", "30m"),
+	}
+}
+
+// WebhookSecrets parses PaymentWebhookSecrets into a provider → secret map.
+// Malformed entries are skipped.
+func (c *Config) WebhookSecrets() map[string]string {
+	secrets := make(map[string]string)
+	for _, entry := range strings.Split(c.PaymentWebhookSecrets, ",") {
+		provider, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || provider == "" || secret == "" {
+			continue
+		}
+		secrets[provider] = secret
+	}
+	return secrets
+}
+
+// OutboxMaxAttemptsPerType parses OutboxMaxAttemptsByType into an event type
+// → attempts map. Malformed entries and non-positive counts are skipped.
+func (c *Config) OutboxMaxAttemptsPerType() map[string]int {
+	attempts := make(map[string]int)
+	for _, entry := range strings.Split(c.OutboxMaxAttemptsByType, ",") {
+		eventType, count, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || eventType == "" {
+			continue
+		}
+		var n int
+		if _, err := fmt.Sscanf(count, "%d", &n); err != nil || n <= 0 {
+			continue
+		}
+		attempts[eventType] = n
 	}
+	return attempts
 }
 
 // DBConnString builds a PostgreSQL connection string from config fields.
@@ -0,0 +1,117 @@
+package domain
+
+import "time"
+
+// MaxAvailabilityNights is the longest range an availability calendar covers.
+const MaxAvailabilityNights = 90
+
+// HotelAvailability is a hotel's availability calendar for the nights from
+// From up to, but not including, To. Prices are in minor units of Currency,
+// the hotel's.
+type HotelAvailability struct {
+	HotelID  int                     `json:"hotel_id"`
+	From     time.Time               `json:"from"`
+	To       time.Time               `json:"to"`
+	Currency string                  `json:"currency"`
+	Rooms    []*RoomTypeAvailability `json:"rooms"`
+	Days     []*DayAvailability      `json:"days"`
+}
+
+// RoomTypeAvailability summarises one room type over the calendar.
+type RoomTypeAvailability struct {
+	RoomID   int    `json:"room_id"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+	// LowestPrice is the cheapest night of the room that still has units
+	// left, nil when the room is sold out on every night.
+	LowestPrice *int64 `json:"lowest_price,omitempty"`
+}
+
+// DayAvailability is the availability of every room type on one night.
+type DayAvailability struct {
+	Date time.Time `json:"date"`
+	// Available is the number of units left across all room types.
+	Available int `json:"available"`
+	// LowestPrice is the cheapest room type with units left, nil when the
+	// hotel is sold out that night.
+	LowestPrice *int64                   `json:"lowest_price,omitempty"`
+	Rooms       []*RoomNightAvailability `json:"rooms"`
+}
+
+// RoomNightAvailability is how many units of a room type are left on a night
+// and what the night costs.
+type RoomNightAvailability struct {
+	RoomID    int   `json:"room_id"`
+	Available int   `json:"available"`
+	Price     int64 `json:"price"`
+}
+
+// BuildHotelAvailability materialises the calendar of a hotel's rooms for the
+// nights in [from, to). plans holds each room's rate plan by room ID; rooms
+// without one are priced at their price_per_night. A night without an
+// inventory row has no units for sale; a room's overbooking allowance counts
+// as units left.
+func BuildHotelAvailability(hotelID int, currency string, from, to time.Time, rooms []*Room, plans map[int]*RatePlan, inventory []*Inventory) *HotelAvailability {
+	overbooking := make(map[int]Overbooking, len(rooms))
+	for _, room := range rooms {
+		overbooking[room.ID] = room.Overbooking
+	}
+	remaining := make(map[int]map[time.Time]int, len(rooms))
+	for _, inv := range inventory {
+		if remaining[inv.RoomID] == nil {
+			remaining[inv.RoomID] = make(map[time.Time]int)
+		}
+		left := overbooking[inv.RoomID].Sellable(inv.TotalInventory) - inv.BookedCount
+		if left < 0 {
+			left = 0
+		}
+		remaining[inv.RoomID][civilDate(inv.Date)] = left
+	}
+
+	avail := &HotelAvailability{
+		HotelID:  hotelID,
+		From:     civilDate(from),
+		To:       civilDate(to),
+		Currency: NormalizeCurrency(currency),
+		Rooms:    make([]*RoomTypeAvailability, 0, len(rooms)),
+		Days:     []*DayAvailability{},
+	}
+	summaries := make(map[int]*RoomTypeAvailability, len(rooms))
+	for _, room := range rooms {
+		summary := &RoomTypeAvailability{RoomID: room.ID, Name: room.Name, Capacity: room.Capacity}
+		summaries[room.ID] = summary
+		avail.Rooms = append(avail.Rooms, summary)
+	}
+
+	for _, date := range StayNights(avail.From, avail.To) {
+		day := &DayAvailability{Date: date, Rooms: make([]*RoomNightAvailability, 0, len(rooms))}
+		for _, room := range rooms {
+			night := &RoomNightAvailability{
+				RoomID:    room.ID,
+				Available: remaining[room.ID][date],
+				Price:     room.PricePerNight,
+			}
+			if plan := plans[room.ID]; plan != nil {
+				night.Price = plan.RateFor(date)
+			}
+			day.Rooms = append(day.Rooms, night)
+
+			if night.Available == 0 {
+				continue
+			}
+			day.Available += night.Available
+			day.LowestPrice = lowerPrice(day.LowestPrice, night.Price)
+			summaries[room.ID].LowestPrice = lowerPrice(summaries[room.ID].LowestPrice, night.Price)
+		}
+		avail.Days = append(avail.Days, day)
+	}
+	return avail
+}
+
+// lowerPrice returns price when it is lower than lowest or lowest is unset.
+func lowerPrice(lowest *int64, price int64) *int64 {
+	if lowest != nil && *lowest <= price {
+		return lowest
+	}
+	return &price
+}
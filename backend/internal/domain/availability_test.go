@@ -0,0 +1,63 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"testing"
+)
+
+func TestBuildHotelAvailability_CountsUnitsLeftAndLowestPrices(t *testing.T) {
+	weekend := int64(150)
+	rooms := []*domain.Room{
+		{ID: 1, Name: "Double", Capacity: 2, PricePerNight: 100},
+		{ID: 2, Name: "Suite", Capacity: 4, PricePerNight: 300},
+	}
+	plans := map[int]*domain.RatePlan{1: {RoomID: 1, BasePrice: 100, WeekendPrice: &weekend}}
+	inventory := []*domain.Inventory{
+		{RoomID: 1, Date: day(12, 3), TotalInventory: 2, BookedCount: 1},
+		{RoomID: 1, Date: day(12, 4), TotalInventory: 2, BookedCount: 2},
+		{RoomID: 2, Date: day(12, 3), TotalInventory: 1, BookedCount: 0},
+		{RoomID: 2, Date: day(12, 4), TotalInventory: 1, BookedCount: 3}, // overbooked
+	}
+
+	avail := domain.BuildHotelAvailability(7, "eur", day(12, 3), day(12, 6), rooms, plans, inventory)
+
+	if avail.Currency != "EUR" || len(avail.Days) != 3 || len(avail.Rooms) != 2 {
+		t.Fatalf("unexpected calendar shape: %+v", avail)
+	}
+
+	thu := avail.Days[0]
+	if thu.Available != 2 || thu.LowestPrice == nil || *thu.LowestPrice != 100 {
+		t.Errorf("thursday: expected 2 units from 100, got %d from %v", thu.Available, thu.LowestPrice)
+	}
+
+	fri := avail.Days[1]
+	if fri.Available != 0 || fri.LowestPrice != nil {
+		t.Errorf("friday: expected sold out, got %d from %v", fri.Available, fri.LowestPrice)
+	}
+	if fri.Rooms[0].Price != 150 || fri.Rooms[1].Available != 0 {
+		t.Errorf("friday: expected weekend rate and no negative units, got %+v %+v", fri.Rooms[0], fri.Rooms[1])
+	}
+
+	if sat := avail.Days[2]; sat.Available != 0 {
+		t.Errorf("saturday has no inventory rows, expected no units, got %d", sat.Available)
+	}
+
+	if p := avail.Rooms[0].LowestPrice; p == nil || *p != 100 {
+		t.Errorf("double: expected lowest price 100, got %v", p)
+	}
+	if p := avail.Rooms[1].LowestPrice; p == nil || *p != 300 {
+		t.Errorf("suite: expected lowest price 300, got %v", p)
+	}
+}
+
+func TestBuildHotelAvailability_CountsOverbookingAllowance(t *testing.T) {
+	rooms := []*domain.Room{{ID: 1, PricePerNight: 100,
+		Overbooking: domain.Overbooking{Type: domain.OverbookingUnits, Value: 1}}}
+	inventory := []*domain.Inventory{{RoomID: 1, Date: day(12, 1), TotalInventory: 2, BookedCount: 2}}
+
+	avail := domain.BuildHotelAvailability(7, "EUR", day(12, 1), day(12, 2), rooms, nil, inventory)
+
+	if got := avail.Days[0].Available; got != 1 {
+		t.Errorf("expected the allowance left for sale, got %d units", got)
+	}
+}
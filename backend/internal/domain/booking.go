@@ -11,17 +11,66 @@ const (
 	BookingStatusFailed          = "failed"
 	BookingStatusCancelled       = "cancelled"
 	BookingStatusRefunded        = "refunded"
+	// BookingStatusExpired marks a pending or awaiting_payment booking whose
+	// inventory hold outlived its TTL and was released by the hold reaper.
+	BookingStatusExpired = "expired"
 )
 
+// EventTypeBookingExpired is the outbox event_type emitted when a hold expires.
+const EventTypeBookingExpired = "BookingExpired"
+
+// BookingExpiredPayload is the event payload for BookingExpired.
+// It shares booking_id/payment_id with PaymentResultPayload so notification
+// consumers can decode either.
+type BookingExpiredPayload struct {
+	BookingID      int       `json:"booking_id"`
+	PaymentID      string    `json:"payment_id,omitempty"`
+	UserID         string    `json:"user_id"`
+	PreviousStatus string    `json:"previous_status"`
+	ExpiredAt      time.Time `json:"expired_at"`
+}
+
 type Booking struct {
-	ID         int       `json:"id" db:"id"`
-	UserID     string    `json:"user_id" db:"user_id"`
-	RoomID     int       `json:"room_id" db:"room_id"`
-	StartDate  time.Time `json:"start_date" db:"start_date"`
-	EndDate    time.Time `json:"end_date" db:"end_date"`
-	TotalPrice float64   `json:"total_price" db:"total_price"`
+	ID        int       `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	RoomID    int       `json:"room_id" db:"room_id"`
+	StartDate time.Time `json:"start_date" db:"start_date"`
+	EndDate   time.Time `json:"end_date" db:"end_date"`
+	Quantity  int       `json:"quantity" db:"quantity"`
+	// Adults and Children are the guests staying. ExtraGuestCharge is what
+	// the guests in extra beds pay for the stay, included in TotalPrice.
+	Adults           int   `json:"adults" db:"adults"`
+	Children         int   `json:"children" db:"children"`
+	ExtraGuestCharge int64 `json:"extra_guest_charge" db:"extra_guest_charge"`
+	// TotalPrice is the price of the stay in Currency, the hotel's currency,
+	// after the discounts, fees and taxes itemised in Lines.
+	TotalPrice int64     `json:"total_price" db:"total_price"`
+	Currency   string    `json:"currency" db:"currency"`
 	Status     string    `json:"status" db:"status"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	// ItineraryID links the booking to the itinerary it is a leg of; nil for
+	// standalone bookings.
+	ItineraryID *int `json:"itinerary_id,omitempty" db:"itinerary_id"`
+	// ChargeTotal is what the guest pays, in ChargeCurrency. FxRate converts
+	// Currency to ChargeCurrency and is locked when the booking is priced, so
+	// later changes to the booking are charged or refunded at the same rate.
+	ChargeTotal    int64   `json:"charge_total" db:"charge_total"`
+	ChargeCurrency string  `json:"charge_currency" db:"charge_currency"`
+	FxRate         float64 `json:"fx_rate" db:"fx_rate"`
+	// Nights breaks TotalPrice down into the per-unit rate of every night.
+	Nights []NightlyRate `json:"nights" db:"nightly_rates"`
+	Lines  []PriceLine   `json:"lines" db:"price_lines"`
+	// PromotionID and PromoCode identify the promo code redeemed by the
+	// booking; PromoDiscount is what it took off TotalPrice, in Currency.
+	PromotionID   *int   `json:"promotion_id,omitempty" db:"promotion_id"`
+	PromoCode     string `json:"promo_code,omitempty" db:"promo_code"`
+	PromoDiscount int64  `json:"promo_discount" db:"promo_discount"`
+}
+
+// Charge converts an amount in the booking's currency to its charge currency
+// at the locked rate.
+func (b *Booking) Charge(amount int64) int64 {
+	return Convert(amount, b.Currency, b.ChargeCurrency, b.FxRate)
 }
 
 type CreateBookingInput struct {
@@ -29,4 +78,70 @@ type CreateBookingInput struct {
 	RoomID    int       `json:"room_id"`
 	StartDate time.Time `json:"start_date"`
 	EndDate   time.Time `json:"end_date"`
+	// Quantity is the number of units of the room to reserve. Zero means one.
+	Quantity int `json:"quantity"`
+	// Adults and Children are the guests staying. Zero adults means one.
+	Adults   int `json:"adults"`
+	Children int `json:"children"`
+	// Currency is the currency the guest pays in. Empty pays in the hotel's.
+	Currency string `json:"currency"`
+	// QuoteToken, when set, books the stay at the price of a signed quote
+	// for the same room, dates, quantity and guests instead of repricing it.
+	QuoteToken string `json:"quote_token"`
+	// PromoCode, when set, is redeemed by the booking. It is ignored with a
+	// quote token, whose quote already carries any promo code.
+	PromoCode string `json:"promo_code"`
+}
+
+// ModifyBookingInput moves an existing booking to new dates and/or another
+// room of the same hotel. Zero values keep the booking's current value.
+type ModifyBookingInput struct {
+	BookingID int       `json:"booking_id"`
+	UserID    string    `json:"user_id"`
+	RoomID    int       `json:"room_id"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// Itinerary groups several bookings (legs), possibly at different hotels or on
+// consecutive date ranges, that are reserved all-or-nothing and checked out
+// with a single payment.
+type Itinerary struct {
+	ID     int    `json:"id" db:"id"`
+	UserID string `json:"user_id" db:"user_id"`
+	// TotalPrice is the sum of the legs' charge totals, in Currency.
+	TotalPrice int64      `json:"total_price" db:"total_price"`
+	Currency   string     `json:"currency" db:"currency"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	Legs       []*Booking `json:"legs"`
+}
+
+// ItineraryLegInput describes one room reservation inside an itinerary.
+type ItineraryLegInput struct {
+	RoomID    int       `json:"room_id"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	// Quantity is the number of units of the room to reserve. Zero means one.
+	Quantity int `json:"quantity"`
+	// Adults and Children are the guests staying. Zero adults means one.
+	Adults   int `json:"adults"`
+	Children int `json:"children"`
+}
+
+type CreateItineraryInput struct {
+	UserID string              `json:"user_id"`
+	Legs   []ItineraryLegInput `json:"legs"`
+	// Currency is the currency the guest pays every leg in. It may only be
+	// empty when all legs are at hotels pricing in the same currency.
+	Currency string `json:"currency"`
+}
+
+// StayNights returns the date of every night in the stay [start, end).
+// The check-out date itself is not a night. Returns nil when end <= start.
+func StayNights(start, end time.Time) []time.Time {
+	var nights []time.Time
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		nights = append(nights, d)
+	}
+	return nights
 }
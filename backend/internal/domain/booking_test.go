@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestStayNights_ExcludesCheckOutDate(t *testing.T) {
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	nights := domain.StayNights(start, end)
+
+	if len(nights) != 4 {
+		t.Fatalf("expected 4 nights, got %d", len(nights))
+	}
+	if !nights[0].Equal(start) {
+		t.Errorf("expected first night %v, got %v", start, nights[0])
+	}
+	if last := nights[len(nights)-1]; !last.Equal(end.AddDate(0, 0, -1)) {
+		t.Errorf("expected last night to be the day before check-out, got %v", last)
+	}
+}
+
+func TestStayNights_EmptyRange(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	if nights := domain.StayNights(day, day); len(nights) != 0 {
+		t.Errorf("expected no nights for same-day range, got %d", len(nights))
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// CancellationPolicy decides how much of a paid booking is refunded when the
+// guest cancels. A policy is attached to a hotel (RoomID nil) or to a single
+// room; a room policy overrides its hotel's.
+type CancellationPolicy struct {
+	ID      int  `json:"id"       db:"id"`
+	HotelID int  `json:"hotel_id" db:"hotel_id"`
+	RoomID  *int `json:"room_id,omitempty" db:"room_id"`
+	// FreeCancellationDays is how many days before check-in the guest may
+	// still cancel for a full refund.
+	FreeCancellationDays int `json:"free_cancellation_days" db:"free_cancellation_days"`
+	// PenaltyPercent is withheld from the refund when cancelling later than that.
+	PenaltyPercent float64 `json:"penalty_percent" db:"penalty_percent"`
+	// NonRefundable marks a rate that is never refunded.
+	NonRefundable bool `json:"non_refundable" db:"non_refundable"`
+	// CaptureDaysBeforeCheckIn, when set, only authorizes the card at checkout
+	// and captures it that many days before check-in (0 = on the day).
+	CaptureDaysBeforeCheckIn *int      `json:"capture_days_before_check_in,omitempty" db:"capture_days_before_check_in"`
+	CreatedAt                time.Time `json:"created_at"     db:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"     db:"updated_at"`
+}
+
+// RefundableAmount returns how much of paid is refunded when a stay starting
+// on checkIn is cancelled at cancelledAt. A nil policy refunds in full.
+func (p *CancellationPolicy) RefundableAmount(paid int64, checkIn, cancelledAt time.Time) int64 {
+	if p == nil {
+		return paid
+	}
+	if p.NonRefundable {
+		return 0
+	}
+	if !cancelledAt.After(checkIn.AddDate(0, 0, -p.FreeCancellationDays)) {
+		return paid
+	}
+	refund := int64(math.Round(float64(paid) * (100 - p.PenaltyPercent) / 100))
+	if refund < 0 {
+		return 0
+	}
+	return refund
+}
+
+// CaptureAt returns when a stay starting on checkIn and booked at bookedAt
+// should be captured, or nil to capture at checkout. A nil policy, a policy
+// without a capture delay, or a capture date already reached all capture at
+// checkout.
+func (p *CancellationPolicy) CaptureAt(checkIn, bookedAt time.Time) *time.Time {
+	if p == nil || p.CaptureDaysBeforeCheckIn == nil {
+		return nil
+	}
+	at := checkIn.AddDate(0, 0, -*p.CaptureDaysBeforeCheckIn)
+	if !at.After(bookedAt) {
+		return nil
+	}
+	return &at
+}
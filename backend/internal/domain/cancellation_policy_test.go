@@ -0,0 +1,64 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestCancellationPolicy_RefundableAmount(t *testing.T) {
+	checkIn := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	flexible := &domain.CancellationPolicy{FreeCancellationDays: 7, PenaltyPercent: 30}
+
+	tests := []struct {
+		name        string
+		policy      *domain.CancellationPolicy
+		cancelledAt time.Time
+		want        int64
+	}{
+		{"nil policy refunds in full", nil, checkIn, 30001},
+		{"before free window closes", flexible, checkIn.AddDate(0, 0, -8), 30001},
+		{"exactly at free window", flexible, checkIn.AddDate(0, 0, -7), 30001},
+		{"inside penalty window rounds to the minor unit", flexible, checkIn.AddDate(0, 0, -2), 21001},
+		{"non-refundable", &domain.CancellationPolicy{NonRefundable: true}, checkIn.AddDate(0, 0, -30), 0},
+		{"full penalty after check-in", &domain.CancellationPolicy{PenaltyPercent: 100}, checkIn.Add(time.Hour), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.RefundableAmount(30001, checkIn, tt.cancelledAt); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCancellationPolicy_CaptureAt(t *testing.T) {
+	checkIn := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	days := func(n int) *int { return &n }
+
+	tests := []struct {
+		name     string
+		policy   *domain.CancellationPolicy
+		bookedAt time.Time
+		want     *time.Time
+	}{
+		{"nil policy captures at checkout", nil, checkIn.AddDate(0, 0, -30), nil},
+		{"no capture delay", &domain.CancellationPolicy{}, checkIn.AddDate(0, 0, -30), nil},
+		{"capture at check-in", &domain.CancellationPolicy{CaptureDaysBeforeCheckIn: days(0)}, checkIn.AddDate(0, 0, -30), &checkIn},
+		{"capture days before", &domain.CancellationPolicy{CaptureDaysBeforeCheckIn: days(3)}, checkIn.AddDate(0, 0, -30), ptrTime(checkIn.AddDate(0, 0, -3))},
+		{"capture date already reached", &domain.CancellationPolicy{CaptureDaysBeforeCheckIn: days(3)}, checkIn.AddDate(0, 0, -2), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.CaptureAt(checkIn, tt.bookedAt)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("expected capture at checkout, got %v", *got)
+			case tt.want != nil && (got == nil || !got.Equal(*tt.want)):
+				t.Errorf("expected %v, got %v", *tt.want, got)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
@@ -27,6 +27,7 @@ type Hotel struct {
 	StarRating  int         `json:"star_rating" db:"star_rating"`
 	Status      HotelStatus `json:"status"      db:"status"`
 	Description string      `json:"description" db:"description"`
+	Currency    string      `json:"currency"    db:"currency"` // room rates are priced in it
 	CreatedAt   time.Time   `json:"created_at"  db:"created_at"`
 	UpdatedAt   time.Time   `json:"updated_at"  db:"updated_at"`
 }
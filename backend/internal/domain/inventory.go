@@ -8,4 +8,22 @@ type Inventory struct {
 	Date           time.Time `json:"date" db:"date"`
 	TotalInventory int       `json:"total_inventory" db:"total_inventory"`
 	BookedCount    int       `json:"booked_count" db:"booked_count"`
+	// Price and MinStay override the room's rate plan for this night.
+	Price   *int64 `json:"price,omitempty" db:"price"`
+	MinStay *int   `json:"min_stay,omitempty" db:"min_stay"`
+}
+
+// OverbookedNight is a night a room is sold past its inventory. The front
+// desk relocates the guests of the Overbooked units to other hotels.
+type OverbookedNight struct {
+	RoomID         int       `json:"room_id"`
+	RoomName       string    `json:"room_name"`
+	Date           time.Time `json:"date"`
+	TotalInventory int       `json:"total_inventory"`
+	BookedCount    int       `json:"booked_count"`
+}
+
+// Overbooked returns how many units more than the inventory are sold.
+func (n *OverbookedNight) Overbooked() int {
+	return n.BookedCount - n.TotalInventory
 }
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxInventoryImportRows is the most data rows one inventory import takes.
+	MaxInventoryImportRows = 10000
+	// MaxInventoryExportNights is the longest range one inventory export covers.
+	MaxInventoryExportNights = 366
+)
+
+// InventoryCSVHeader is the header row of inventory CSV files. Imports need
+// room_id, date and total; price is optional and booked_count is ignored, so
+// an export can be edited and imported back as is.
+var InventoryCSVHeader = []string{"room_id", "date", "total", "price", "booked_count"}
+
+// InventoryRow is one night of a room's inventory read from a CSV import.
+type InventoryRow struct {
+	Line   int       `json:"line"`
+	RoomID int       `json:"room_id"`
+	Date   time.Time `json:"date"`
+	Total  int       `json:"total"`
+	// Price, when set, overrides the room's rate plan for the night. An
+	// empty price leaves the night's current override in place.
+	Price *int64 `json:"price,omitempty"`
+}
+
+// InventoryRowError explains why a row of an inventory import was rejected.
+type InventoryRowError struct {
+	Line    int    `json:"line"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// InventoryImport is the outcome of an inventory import. Rows are applied
+// only when none of them has errors and the import is not a dry run.
+type InventoryImport struct {
+	DryRun  bool                 `json:"dry_run"`
+	Applied bool                 `json:"applied"`
+	Rows    int                  `json:"rows"`
+	Errors  []*InventoryRowError `json:"errors"`
+}
+
+// ParseInventoryCSV reads inventory rows from CSV with an
+// InventoryCSVHeader-style header row; columns may come in any order.
+// Malformed rows are reported as row errors; a missing header column, an
+// unreadable file or more than MaxInventoryImportRows rows is ErrBadRequest.
+func ParseInventoryCSV(src io.Reader) ([]*InventoryRow, []*InventoryRowError, error) {
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("csv is empty: %w", ErrBadRequest)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("read csv header: %v: %w", err, ErrBadRequest)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"room_id", "date", "total"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("csv header has no %s column: %w", required, ErrBadRequest)
+		}
+	}
+
+	var rows []*InventoryRow
+	var rowErrors []*InventoryRowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if len(rows)+len(rowErrors) >= MaxInventoryImportRows {
+			return nil, nil, fmt.Errorf("csv has more than %d rows: %w", MaxInventoryImportRows, ErrBadRequest)
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("read csv: %v: %w", err, ErrBadRequest)
+			}
+			rowErrors = append(rowErrors, &InventoryRowError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		row, rowErr := parseInventoryRecord(record, columns, line)
+		if rowErr != nil {
+			rowErrors = append(rowErrors, rowErr)
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+// parseInventoryRecord parses one CSV record into an InventoryRow.
+func parseInventoryRecord(record []string, columns map[string]int, line int) (*InventoryRow, *InventoryRowError) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	rowErr := func(column, message string) *InventoryRowError {
+		return &InventoryRowError{Line: line, Column: column, Message: message}
+	}
+
+	row := &InventoryRow{Line: line}
+	roomID, err := strconv.Atoi(field("room_id"))
+	if err != nil || roomID <= 0 {
+		return nil, rowErr("room_id", "must be a positive integer")
+	}
+	row.RoomID = roomID
+
+	date, err := time.Parse("2006-01-02", field("date"))
+	if err != nil {
+		return nil, rowErr("date", "must be a date in YYYY-MM-DD format")
+	}
+	row.Date = date
+
+	total, err := strconv.Atoi(field("total"))
+	if err != nil || total < 0 {
+		return nil, rowErr("total", "must be a non-negative integer")
+	}
+	row.Total = total
+
+	if raw := field("price"); raw != "" {
+		price, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || price <= 0 {
+			return nil, rowErr("price", "must be a positive amount in minor units")
+		}
+		row.Price = &price
+	}
+	return row, nil
+}
+
+// InventoryCSVRecord formats a night of inventory as a row under
+// InventoryCSVHeader.
+func InventoryCSVRecord(inv *Inventory) []string {
+	price := ""
+	if inv.Price != nil {
+		price = strconv.FormatInt(*inv.Price, 10)
+	}
+	return []string{
+		strconv.Itoa(inv.RoomID),
+		inv.Date.Format("2006-01-02"),
+		strconv.Itoa(inv.TotalInventory),
+		price,
+		strconv.Itoa(inv.BookedCount),
+	}
+}
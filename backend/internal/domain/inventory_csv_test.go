@@ -0,0 +1,60 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseInventoryCSV_ReportsRowErrorsByLine(t *testing.T) {
+	src := strings.Join([]string{
+		"date,room_id,total,price",
+		"2026-07-01,5,4,12000",
+		"2026-07-02,5,4,",
+		"07/03/2026,5,4,",
+		"2026-07-04,5,-1,",
+		"2026-07-05,x,4,",
+		"2026-07-06,5,4,0",
+	}, "\n")
+
+	rows, rowErrors, err := domain.ParseInventoryCSV(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 valid rows, got %d", len(rows))
+	}
+	if rows[0].Line != 2 || rows[0].RoomID != 5 || rows[0].Total != 4 || rows[0].Price == nil || *rows[0].Price != 12000 {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Price != nil {
+		t.Errorf("expected an empty price to stay unset, got %d", *rows[1].Price)
+	}
+
+	want := []struct {
+		line   int
+		column string
+	}{{4, "date"}, {5, "total"}, {6, "room_id"}, {7, "price"}}
+	if len(rowErrors) != len(want) {
+		t.Fatalf("expected %d row errors, got %+v", len(want), rowErrors)
+	}
+	for i, w := range want {
+		if rowErrors[i].Line != w.line || rowErrors[i].Column != w.column {
+			t.Errorf("row error %d: expected line %d column %s, got %+v", i, w.line, w.column, rowErrors[i])
+		}
+	}
+}
+
+func TestParseInventoryCSV_RejectsMissingColumns(t *testing.T) {
+	_, _, err := domain.ParseInventoryCSV(strings.NewReader("room_id,total\n5,4\n"))
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest without a date column, got %v", err)
+	}
+
+	_, _, err = domain.ParseInventoryCSV(strings.NewReader(""))
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for an empty file, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Amounts of money are int64 counts of their currency's minor unit: cents for
+// USD, satang for THB, whole yen for JPY and whole dong for VND. A currency is
+// an ISO 4217 code.
+
+// DefaultCurrency is used for hotels that do not set one.
+const DefaultCurrency = "USD"
+
+// zeroDecimalCurrencies have no minor unit; an amount counts whole units.
+var zeroDecimalCurrencies = map[string]bool{
+	"JPY": true,
+	"KRW": true,
+	"VND": true,
+	"IDR": true,
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 code.
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeCurrency upper-cases code and defaults an empty one to DefaultCurrency.
+func NormalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
+
+// CurrencyExponent returns how many decimal places the currency's minor unit
+// has: 0 for zero-decimal currencies, 2 otherwise.
+func CurrencyExponent(currency string) int {
+	if zeroDecimalCurrencies[currency] {
+		return 0
+	}
+	return 2
+}
+
+// FormatMoney renders an amount for people, e.g. "120.50 USD" or "15000 JPY".
+func FormatMoney(amount int64, currency string) string {
+	exp := CurrencyExponent(currency)
+	if exp == 0 {
+		return fmt.Sprintf("%d %s", amount, currency)
+	}
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	unit := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/unit, exp, amount%unit, currency)
+}
+
+// ExchangeRate converts money from Base to Quote: one unit of Base buys Rate
+// units of Quote.
+type ExchangeRate struct {
+	Base      string    `json:"base"       db:"base_currency"`
+	Quote     string    `json:"quote"      db:"quote_currency"`
+	Rate      float64   `json:"rate"       db:"rate"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Convert returns amount, in minor units of from, in minor units of to at
+// rate, rounded half away from zero.
+func Convert(amount int64, from, to string, rate float64) int64 {
+	if from == to {
+		return amount
+	}
+	scale := math.Pow10(CurrencyExponent(to) - CurrencyExponent(from))
+	return int64(math.Round(float64(amount) * rate * scale))
+}
@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"testing"
+)
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{12050, "USD", "120.50 USD"},
+		{5, "THB", "0.05 THB"},
+		{-250, "USD", "-2.50 USD"},
+		{15000, "JPY", "15000 JPY"},
+		{2500000, "VND", "2500000 VND"},
+	}
+	for _, tt := range tests {
+		if got := domain.FormatMoney(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatMoney(%d, %s): expected %q, got %q", tt.amount, tt.currency, tt.want, got)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to string
+		rate     float64
+		want     int64
+	}{
+		{"same currency ignores rate", 12050, "USD", "USD", 2, 12050},
+		{"two-decimal to two-decimal", 10000, "USD", "THB", 36.5, 365000},
+		{"zero-decimal to two-decimal", 15000, "JPY", "USD", 0.0067, 10050},
+		{"two-decimal to zero-decimal", 10000, "USD", "VND", 25400, 2540000},
+		{"rounds half away from zero", 1, "USD", "EUR", 0.5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := domain.Convert(tt.amount, tt.from, tt.to, tt.rate); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidCurrency(t *testing.T) {
+	for _, code := range []string{"USD", "VND", "JPY"} {
+		if !domain.ValidCurrency(code) {
+			t.Errorf("expected %q to be valid", code)
+		}
+	}
+	for _, code := range []string{"", "usd", "US", "USDT", "U$D"} {
+		if domain.ValidCurrency(code) {
+			t.Errorf("expected %q to be invalid", code)
+		}
+	}
+}
+
+func TestBooking_ChargeUsesLockedRate(t *testing.T) {
+	b := &domain.Booking{Currency: "THB", ChargeCurrency: "USD", FxRate: 0.0275}
+	if got := b.Charge(400000); got != 11000 {
+		t.Errorf("expected 11000, got %d", got)
+	}
+}
@@ -12,6 +12,13 @@ const (
 	NotificationTypePaymentSucceeded NotificationType = "payment_succeeded"
 	NotificationTypePaymentFailed    NotificationType = "payment_failed"
 	NotificationTypePaymentTimedOut  NotificationType = "payment_timed_out"
+	NotificationTypeBookingExpired   NotificationType = "booking_expired"
+	NotificationTypeRefundSucceeded  NotificationType = "refund_succeeded"
+	NotificationTypeRefundFailed     NotificationType = "refund_failed"
+	NotificationTypePaymentVoided    NotificationType = "payment_voided"
+	NotificationTypeCaptureFailed    NotificationType = "capture_failed"
+	NotificationTypeWaitlistOffer    NotificationType = "waitlist_offer"
+	NotificationTypeWaitlistExpired  NotificationType = "waitlist_offer_expired"
 )
 
 // validNotificationTypes is the set of allowed notification types.
@@ -22,6 +29,13 @@ var validNotificationTypes = map[NotificationType]struct{}{
 	NotificationTypePaymentSucceeded: {},
 	NotificationTypePaymentFailed:    {},
 	NotificationTypePaymentTimedOut:  {},
+	NotificationTypeBookingExpired:   {},
+	NotificationTypeRefundSucceeded:  {},
+	NotificationTypeRefundFailed:     {},
+	NotificationTypePaymentVoided:    {},
+	NotificationTypeCaptureFailed:    {},
+	NotificationTypeWaitlistOffer:    {},
+	NotificationTypeWaitlistExpired:  {},
 }
 
 // IsValid reports whether the NotificationType is a recognised constant.
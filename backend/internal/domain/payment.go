@@ -15,20 +15,66 @@ const (
 	PaymentStatusFailed     PaymentStatus = "failed"
 	PaymentStatusTimedOut   PaymentStatus = "timed_out"
 	PaymentStatusRefunded   PaymentStatus = "refunded"
+	// PaymentStatusAuthorized holds the amount on the card until the capture
+	// scheduler collects it; PaymentStatusCaptured means it was collected.
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	// PaymentStatusVoided releases an authorization that was never captured.
+	PaymentStatusVoided PaymentStatus = "voided"
+	// PaymentStatusPartiallyRefunded is set when a cancellation penalty kept
+	// part of the charge.
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+)
+
+// PaymentKind distinguishes the checkout charge of a booking from the
+// adjustments created when a paid booking is modified.
+type PaymentKind string
+
+const (
+	PaymentKindCharge PaymentKind = "charge"
+	PaymentKindTopUp  PaymentKind = "top_up"
+	PaymentKindRefund PaymentKind = "refund"
 )
 
 // Payment represents a payment record tied to a booking.
 type Payment struct {
 	ID             string        `json:"id" db:"id"`
 	BookingID      int           `json:"booking_id" db:"booking_id"`
-	Amount         float64       `json:"amount" db:"amount"`
+	Amount         int64         `json:"amount" db:"amount"` // minor units of Currency
 	Currency       string        `json:"currency" db:"currency"`
 	Status         PaymentStatus `json:"status" db:"status"`
 	IdempotencyKey string        `json:"idempotency_key" db:"idempotency_key"`
+	Kind           PaymentKind   `json:"kind" db:"kind"`
+	RefundedAmount int64         `json:"refunded_amount" db:"refunded_amount"`
 	GatewayRef     string        `json:"gateway_ref,omitempty" db:"gateway_ref"`
 	FailedReason   string        `json:"failed_reason,omitempty" db:"failed_reason"`
-	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
+	PaymentMethod  string        `json:"-" db:"payment_method"` // card token used at checkout
+	DeclineCode    string        `json:"decline_code,omitempty" db:"decline_code"`
+	// AuthorizationRef is the gateway reference of the hold to capture or void.
+	AuthorizationRef string `json:"authorization_ref,omitempty" db:"authorization_ref"`
+	// CaptureAt is when an authorized payment is due for capture; nil
+	// captures at checkout. Until then Amount is what will be captured.
+	CaptureAt *time.Time `json:"capture_at,omitempty" db:"capture_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsAdjustment reports whether the payment is a top-up or refund created by a
+// booking modification rather than the booking's checkout charge. Its outcome
+// never changes the booking's status or inventory.
+func (p *Payment) IsAdjustment() bool {
+	return p.Kind == PaymentKindTopUp || p.Kind == PaymentKindRefund
+}
+
+// IsCaptured reports whether the money of a payment in this status was
+// collected, so it can be refunded. PaymentStatusSucceeded is how charges were
+// recorded before authorize-then-capture.
+func (s PaymentStatus) IsCaptured() bool {
+	switch s {
+	case PaymentStatusSucceeded, PaymentStatusCaptured, PaymentStatusPartiallyRefunded:
+		return true
+	}
+	return false
 }
 
 // OutboxEvent represents a domain event stored in the transactional outbox table.
@@ -41,8 +87,28 @@ type OutboxEvent struct {
 	PublishedAt   *time.Time      `json:"published_at,omitempty" db:"published_at"`
 	RetryCount    int             `json:"retry_count" db:"retry_count"`
 	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	// CorrelationID ties the event to the request or message that caused it.
+	CorrelationID string `json:"correlation_id,omitempty" db:"correlation_id"`
+	// Status is pending until the event is published or dead-lettered.
+	Status OutboxStatus `json:"status" db:"status"`
+	// NextAttemptAt is when a failed event may be retried, and LastError why
+	// its latest attempt failed.
+	NextAttemptAt  time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	DeadLetteredAt *time.Time `json:"dead_lettered_at,omitempty" db:"dead_lettered_at"`
 }
 
+// OutboxStatus is where an outbox event is in its delivery.
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusPublished OutboxStatus = "published"
+	// OutboxStatusDeadLettered means the relay gave up on the event and sent
+	// it to the dead-letter exchange instead.
+	OutboxStatusDeadLettered OutboxStatus = "dead_lettered"
+)
+
 // ProcessedEvent tracks consumed events for idempotency.
 type ProcessedEvent struct {
 	EventID     string    `json:"event_id" db:"event_id"`
@@ -55,21 +121,26 @@ const (
 	EventTypePaymentSucceeded        = "PaymentSucceeded"
 	EventTypePaymentFailed           = "PaymentFailed"
 	EventTypePaymentTimedOut         = "PaymentTimedOut"
+	// EventTypePaymentVoidRequested asks the worker to release an authorization.
+	EventTypePaymentVoidRequested = "PaymentVoidRequested"
+	// EventTypePaymentCaptureFailed is emitted when a scheduled capture is declined.
+	EventTypePaymentCaptureFailed = "PaymentCaptureFailed"
 )
 
 // PaymentInitiatedPayload is the event payload for BookingPaymentInitiated.
 type PaymentInitiatedPayload struct {
-	PaymentID  string  `json:"payment_id"`
-	BookingID  int     `json:"booking_id"`
-	Amount     float64 `json:"amount"`
-	Currency   string  `json:"currency"`
-	UserID     string  `json:"user_id"`
+	PaymentID string `json:"payment_id"`
+	BookingID int    `json:"booking_id"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	UserID    string `json:"user_id"`
 }
 
 // PaymentResultPayload is the event payload for success/failure/timeout events.
 type PaymentResultPayload struct {
-	PaymentID  string `json:"payment_id"`
-	BookingID  int    `json:"booking_id"`
-	Reason     string `json:"reason,omitempty"`
-	GatewayRef string `json:"gateway_ref,omitempty"`
+	PaymentID   string `json:"payment_id"`
+	BookingID   int    `json:"booking_id"`
+	Reason      string `json:"reason,omitempty"`
+	GatewayRef  string `json:"gateway_ref,omitempty"`
+	DeclineCode string `json:"decline_code,omitempty"` // set when the gateway declined
 }
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Promotion is a promo code guests enter when booking. It takes either a
+// percentage or a fixed amount off the stay, never both, and can be limited
+// to a hotel or one of its rooms, to a validity window, to stays of a minimum
+// length and to a number of redemptions overall and per guest.
+type Promotion struct {
+	ID          int    `json:"id"          db:"id"`
+	Code        string `json:"code"        db:"code"`
+	Description string `json:"description" db:"description"`
+	// CreatedBy is the owner or admin who created the code.
+	CreatedBy string `json:"created_by" db:"created_by"`
+	// HotelID and RoomID scope the code; both nil makes it valid everywhere.
+	HotelID *int `json:"hotel_id,omitempty" db:"hotel_id"`
+	RoomID  *int `json:"room_id,omitempty"  db:"room_id"`
+	// Percent is taken off the room subtotal after the hotel's own discounts.
+	Percent *float64 `json:"percent,omitempty" db:"percent"`
+	// Amount is a fixed amount off the stay, in minor units of Currency. The
+	// code only applies to stays priced in that currency.
+	Amount   *int64 `json:"amount,omitempty"   db:"amount"`
+	Currency string `json:"currency,omitempty" db:"currency"`
+	// ValidFrom and ValidUntil bound when the code can be redeemed; nil is
+	// unbounded.
+	ValidFrom  *time.Time `json:"valid_from,omitempty"  db:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until,omitempty" db:"valid_until"`
+	MinNights  int        `json:"min_nights"            db:"min_nights"`
+	// MaxRedemptions and MaxPerUser cap the bookings holding the code at
+	// once, overall and per guest; nil is unlimited.
+	MaxRedemptions *int `json:"max_redemptions,omitempty" db:"max_redemptions"`
+	MaxPerUser     *int `json:"max_per_user,omitempty"    db:"max_per_user"`
+	// Redemptions counts the bookings holding the code. A cancelled, expired
+	// or unpaid booking gives its redemption back.
+	Redemptions int       `json:"redemptions" db:"redemptions"`
+	Active      bool      `json:"active"      db:"active"`
+	CreatedAt   time.Time `json:"created_at"  db:"created_at"`
+}
+
+// NormalizePromoCode returns code as stored: trimmed and upper-cased, so codes
+// match case-insensitively.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Redeemable returns ErrBadRequest unless the code is active and within its
+// validity window at t.
+func (p *Promotion) Redeemable(t time.Time) error {
+	if !p.Active {
+		return fmt.Errorf("promo code %s is no longer active: %w", p.Code, ErrBadRequest)
+	}
+	if p.ValidFrom != nil && t.Before(*p.ValidFrom) {
+		return fmt.Errorf("promo code %s is not valid before %s: %w", p.Code, p.ValidFrom.Format(time.RFC3339), ErrBadRequest)
+	}
+	if p.ValidUntil != nil && !t.Before(*p.ValidUntil) {
+		return fmt.Errorf("promo code %s expired at %s: %w", p.Code, p.ValidUntil.Format(time.RFC3339), ErrBadRequest)
+	}
+	return nil
+}
+
+// AppliesTo returns ErrBadRequest unless the code can discount a stay of
+// nights nights in a room of a hotel pricing in currency.
+func (p *Promotion) AppliesTo(hotelID, roomID, nights int, currency string) error {
+	if p.HotelID != nil && *p.HotelID != hotelID {
+		return fmt.Errorf("promo code %s is not valid at this hotel: %w", p.Code, ErrBadRequest)
+	}
+	if p.RoomID != nil && *p.RoomID != roomID {
+		return fmt.Errorf("promo code %s is not valid for this room: %w", p.Code, ErrBadRequest)
+	}
+	if nights < p.MinNights {
+		return fmt.Errorf("promo code %s needs a stay of at least %d nights: %w", p.Code, p.MinNights, ErrBadRequest)
+	}
+	if p.Amount != nil && p.Currency != currency {
+		return fmt.Errorf("promo code %s is in %s and cannot discount a stay priced in %s: %w",
+			p.Code, p.Currency, currency, ErrBadRequest)
+	}
+	return nil
+}
+
+// CheckLimits returns ErrConflict when one more redemption would exceed the
+// code's limits, given the redemptions it has overall and by the guest.
+func (p *Promotion) CheckLimits(total, byUser int) error {
+	if p.MaxRedemptions != nil && total >= *p.MaxRedemptions {
+		return fmt.Errorf("promo code %s has been fully redeemed: %w", p.Code, ErrConflict)
+	}
+	if p.MaxPerUser != nil && byUser >= *p.MaxPerUser {
+		return fmt.Errorf("promo code %s can only be used %d time(s) per guest: %w", p.Code, *p.MaxPerUser, ErrConflict)
+	}
+	return nil
+}
+
+// discountOn returns what the code takes off base, never more than base.
+func (p *Promotion) discountOn(base int64) int64 {
+	var amount int64
+	switch {
+	case p.Percent != nil:
+		amount = int64(math.Round(float64(base) * *p.Percent / 100))
+	case p.Amount != nil:
+		amount = *p.Amount
+	}
+	if amount > base {
+		amount = base
+	}
+	return amount
+}
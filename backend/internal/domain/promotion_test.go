@@ -0,0 +1,90 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPromotion_Redeemable(t *testing.T) {
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	p := &domain.Promotion{Code: "SUMMER", Active: true, ValidFrom: &from, ValidUntil: &until}
+
+	if err := p.Redeemable(time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("expected code redeemable inside its window, got %v", err)
+	}
+	if err := p.Redeemable(time.Date(2026, 5, 31, 23, 0, 0, 0, time.UTC)); !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest before the window, got %v", err)
+	}
+	if err := p.Redeemable(until); !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest at the end of the window, got %v", err)
+	}
+
+	p.Active = false
+	if err := p.Redeemable(time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)); !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for an inactive code, got %v", err)
+	}
+}
+
+func TestPromotion_AppliesTo(t *testing.T) {
+	hotelID, roomID := 10, 5
+	amount := int64(2000)
+	p := &domain.Promotion{Code: "STAY3", HotelID: &hotelID, RoomID: &roomID, Amount: &amount, Currency: "EUR", MinNights: 3}
+
+	if err := p.AppliesTo(10, 5, 3, "EUR"); err != nil {
+		t.Errorf("expected code to apply, got %v", err)
+	}
+	cases := []struct {
+		name             string
+		hotel, room, nts int
+		currency         string
+	}{
+		{"other hotel", 11, 5, 3, "EUR"},
+		{"other room", 10, 6, 3, "EUR"},
+		{"too short", 10, 5, 2, "EUR"},
+		{"other currency", 10, 5, 3, "USD"},
+	}
+	for _, c := range cases {
+		if err := p.AppliesTo(c.hotel, c.room, c.nts, c.currency); !errors.Is(err, domain.ErrBadRequest) {
+			t.Errorf("%s: expected ErrBadRequest, got %v", c.name, err)
+		}
+	}
+}
+
+func TestPromotion_CheckLimits(t *testing.T) {
+	total, perUser := 100, 1
+	p := &domain.Promotion{Code: "LAUNCH", MaxRedemptions: &total, MaxPerUser: &perUser}
+
+	if err := p.CheckLimits(99, 0); err != nil {
+		t.Errorf("expected redemption within limits, got %v", err)
+	}
+	if err := p.CheckLimits(100, 0); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict when fully redeemed, got %v", err)
+	}
+	if err := p.CheckLimits(10, 1); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict past the per-guest limit, got %v", err)
+	}
+}
+
+func TestQuote_ApplyCharges_PromoAfterHotelDiscountsBeforeTaxes(t *testing.T) {
+	weekly, promoPct, vat := 10.0, 20.0, 10.0
+	q := &domain.Quote{Quantity: 1, Nights: make([]domain.NightlyRate, 7), Subtotal: 100000}
+
+	q.ApplyCharges([]*domain.HotelCharge{
+		{Name: "Weekly", Type: domain.ChargeTypeDiscount, Percent: &weekly},
+		{Name: "VAT", Type: domain.ChargeTypeTax, Percent: &vat},
+	}, &domain.Promotion{ID: 3, Code: "SPRING20", Percent: &promoPct})
+
+	// 100000 - 10000 weekly = 90000; promo 20% = 18000 → 72000; VAT 7200
+	if q.PromoDiscount != 18000 || q.Discounts != 28000 || q.Taxes != 7200 || q.Total != 79200 {
+		t.Errorf("unexpected breakdown: promo=%d discounts=%d taxes=%d total=%d", q.PromoDiscount, q.Discounts, q.Taxes, q.Total)
+	}
+	if q.PromotionID == nil || *q.PromotionID != 3 || q.PromoCode != "SPRING20" {
+		t.Errorf("expected promo 3 SPRING20 recorded, got %v %q", q.PromotionID, q.PromoCode)
+	}
+	if len(q.Lines) != 3 || q.Lines[1].Name != "Promo SPRING20" || q.Lines[1].Amount != -18000 {
+		t.Errorf("unexpected lines: %+v", q.Lines)
+	}
+}
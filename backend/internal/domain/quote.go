@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// ChargeType is what a hotel charge does to the price of a stay.
+type ChargeType string
+
+const (
+	ChargeTypeDiscount ChargeType = "discount"
+	ChargeTypeFee      ChargeType = "fee"
+	ChargeTypeTax      ChargeType = "tax"
+)
+
+// Valid reports whether t is a known charge type.
+func (t ChargeType) Valid() bool {
+	return t == ChargeTypeDiscount || t == ChargeTypeFee || t == ChargeTypeTax
+}
+
+// HotelCharge is a tax, service fee or discount a hotel applies to its stays.
+// It is either a percentage or a fixed amount, never both.
+type HotelCharge struct {
+	ID      int        `json:"id"       db:"id"`
+	HotelID int        `json:"hotel_id" db:"hotel_id"`
+	Name    string     `json:"name"     db:"name"`
+	Type    ChargeType `json:"type"     db:"type"`
+	// Percent is a percentage of the amount the charge applies to.
+	Percent *float64 `json:"percent,omitempty" db:"percent"`
+	// Amount is a fixed amount in minor units of the hotel's currency, per
+	// reserved unit and, when PerNight is set, per night.
+	Amount   *int64 `json:"amount,omitempty" db:"amount"`
+	PerNight bool   `json:"per_night"        db:"per_night"`
+	// MinNights limits the charge to stays of at least that many nights,
+	// e.g. a weekly-stay discount.
+	MinNights int `json:"min_nights" db:"min_nights"`
+}
+
+// applies reports whether the charge applies to a stay of nights nights.
+func (c *HotelCharge) applies(nights int) bool {
+	return nights >= c.MinNights
+}
+
+// amountOn returns what the charge comes to on base, for a stay of nights
+// nights of quantity units.
+func (c *HotelCharge) amountOn(base int64, nights, quantity int) int64 {
+	if c.Percent != nil {
+		return int64(math.Round(float64(base) * *c.Percent / 100))
+	}
+	if c.Amount == nil {
+		return 0
+	}
+	units := int64(quantity)
+	if c.PerNight {
+		units *= int64(nights)
+	}
+	return *c.Amount * units
+}
+
+// PriceLine is one itemised adjustment to the room subtotal of a stay.
+// Discounts are negative.
+type PriceLine struct {
+	Type   ChargeType `json:"type"`
+	Name   string     `json:"name"`
+	Amount int64      `json:"amount"`
+}
+
+// Quote is the price of a stay before it is booked. A signed quote can be
+// handed back when booking to get exactly the quoted price.
+type Quote struct {
+	RoomID   int       `json:"room_id"`
+	HotelID  int       `json:"hotel_id"`
+	CheckIn  time.Time `json:"check_in"`
+	CheckOut time.Time `json:"check_out"`
+	Adults   int       `json:"adults"`
+	Children int       `json:"children"`
+	Quantity int       `json:"quantity"`
+	// Currency is the hotel's; every amount but ChargeTotal is in it.
+	Currency string        `json:"currency"`
+	Nights   []NightlyRate `json:"nights"`
+	// ExtraGuests is the number of guests in extra beds; ExtraGuestCharge is
+	// what they pay for the stay, included in Subtotal.
+	ExtraGuests      int   `json:"extra_guests"`
+	ExtraGuestCharge int64 `json:"extra_guest_charge"`
+	// Subtotal is the nightly rates times the quantity, plus ExtraGuestCharge.
+	Subtotal  int64       `json:"subtotal"`
+	Lines     []PriceLine `json:"lines"`
+	Discounts int64       `json:"discounts"`
+	// PromotionID and PromoCode identify the promo code applied, if any;
+	// PromoDiscount is its share of Discounts.
+	PromotionID   *int   `json:"promotion_id,omitempty"`
+	PromoCode     string `json:"promo_code,omitempty"`
+	PromoDiscount int64  `json:"promo_discount,omitempty"`
+	Fees          int64  `json:"fees"`
+	Taxes         int64  `json:"taxes"`
+	Total         int64  `json:"total"`
+	// ChargeTotal is Total in ChargeCurrency at the locked FxRate.
+	ChargeTotal    int64     `json:"charge_total"`
+	ChargeCurrency string    `json:"charge_currency"`
+	FxRate         float64   `json:"fx_rate"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// QuoteInput asks for the price of a stay.
+type QuoteInput struct {
+	RoomID   int
+	CheckIn  time.Time
+	CheckOut time.Time
+	// Adults and Children are the guests staying. Zero adults means one.
+	Adults   int
+	Children int
+	// Quantity is the number of units of the room. Zero means one.
+	Quantity int
+	// Currency is the currency the guest pays in. Empty pays in the hotel's.
+	Currency string
+	// PromoCode, when set, is applied to the stay if it is valid for it.
+	PromoCode string
+}
+
+// ApplyCharges prices the subtotal of q with the hotel's charges and, when
+// promo is not nil, a promo code. Discounts come off the subtotal first,
+// capped at the subtotal, with the promo code taken after the hotel's own;
+// percentage fees are taken on the discounted subtotal and percentage taxes
+// on that plus fees.
+func (q *Quote) ApplyCharges(charges []*HotelCharge, promo *Promotion) {
+	nights := len(q.Nights)
+	q.Lines = []PriceLine{}
+	q.Discounts, q.Fees, q.Taxes = 0, 0, 0
+	q.PromotionID, q.PromoCode, q.PromoDiscount = nil, "", 0
+
+	for _, c := range chargesOfType(charges, ChargeTypeDiscount, nights) {
+		amount := c.amountOn(q.Subtotal, nights, q.Quantity)
+		if amount > q.Subtotal-q.Discounts {
+			amount = q.Subtotal - q.Discounts
+		}
+		q.Discounts += amount
+		q.Lines = append(q.Lines, PriceLine{Type: c.Type, Name: c.Name, Amount: -amount})
+	}
+	if promo != nil {
+		id := promo.ID
+		q.PromotionID, q.PromoCode = &id, promo.Code
+		q.PromoDiscount = promo.discountOn(q.Subtotal - q.Discounts)
+		q.Discounts += q.PromoDiscount
+		q.Lines = append(q.Lines, PriceLine{Type: ChargeTypeDiscount, Name: "Promo " + promo.Code, Amount: -q.PromoDiscount})
+	}
+	discounted := q.Subtotal - q.Discounts
+
+	for _, c := range chargesOfType(charges, ChargeTypeFee, nights) {
+		amount := c.amountOn(discounted, nights, q.Quantity)
+		q.Fees += amount
+		q.Lines = append(q.Lines, PriceLine{Type: c.Type, Name: c.Name, Amount: amount})
+	}
+	for _, c := range chargesOfType(charges, ChargeTypeTax, nights) {
+		amount := c.amountOn(discounted+q.Fees, nights, q.Quantity)
+		q.Taxes += amount
+		q.Lines = append(q.Lines, PriceLine{Type: c.Type, Name: c.Name, Amount: amount})
+	}
+
+	q.Total = discounted + q.Fees + q.Taxes
+}
+
+func chargesOfType(charges []*HotelCharge, t ChargeType, nights int) []*HotelCharge {
+	var matched []*HotelCharge
+	for _, c := range charges {
+		if c.Type == t && c.applies(nights) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
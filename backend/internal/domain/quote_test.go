@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"testing"
+)
+
+func TestQuote_ApplyCharges_OrdersDiscountsFeesAndTaxes(t *testing.T) {
+	weekly, service, vat := 10.0, 5.0, 7.0
+	cleaning := int64(2000)
+	q := &domain.Quote{
+		Quantity: 1,
+		Nights:   make([]domain.NightlyRate, 7),
+		Subtotal: 70000,
+	}
+
+	q.ApplyCharges([]*domain.HotelCharge{
+		{Name: "VAT", Type: domain.ChargeTypeTax, Percent: &vat},
+		{Name: "Cleaning", Type: domain.ChargeTypeFee, Amount: &cleaning},
+		{Name: "Service", Type: domain.ChargeTypeFee, Percent: &service},
+		{Name: "Weekly", Type: domain.ChargeTypeDiscount, Percent: &weekly, MinNights: 7},
+	}, nil)
+
+	// 70000 - 7000 = 63000; fees 2000 + 3150; VAT 7% of 68150 = 4770.5
+	if q.Discounts != 7000 || q.Fees != 5150 || q.Taxes != 4771 {
+		t.Errorf("unexpected breakdown: discounts=%d fees=%d taxes=%d", q.Discounts, q.Fees, q.Taxes)
+	}
+	if q.Total != 72921 {
+		t.Errorf("expected total 72921, got %d", q.Total)
+	}
+	if len(q.Lines) != 4 || q.Lines[0].Name != "Weekly" || q.Lines[0].Amount != -7000 || q.Lines[3].Name != "VAT" {
+		t.Errorf("unexpected lines: %+v", q.Lines)
+	}
+}
+
+func TestQuote_ApplyCharges_SkipsChargesBelowMinNights(t *testing.T) {
+	weekly := 10.0
+	q := &domain.Quote{Quantity: 1, Nights: make([]domain.NightlyRate, 3), Subtotal: 30000}
+
+	q.ApplyCharges([]*domain.HotelCharge{{Name: "Weekly", Type: domain.ChargeTypeDiscount, Percent: &weekly, MinNights: 7}}, nil)
+
+	if q.Total != 30000 || len(q.Lines) != 0 {
+		t.Errorf("expected undiscounted total with no lines, got total=%d lines=%+v", q.Total, q.Lines)
+	}
+}
+
+func TestQuote_ApplyCharges_FixedAmountsPerNightAndUnit(t *testing.T) {
+	cityTax := int64(500)
+	q := &domain.Quote{Quantity: 2, Nights: make([]domain.NightlyRate, 3), Subtotal: 60000}
+
+	q.ApplyCharges([]*domain.HotelCharge{{Name: "City tax", Type: domain.ChargeTypeTax, Amount: &cityTax, PerNight: true}}, nil)
+
+	if q.Taxes != 3000 || q.Total != 63000 {
+		t.Errorf("expected taxes=3000 total=63000, got taxes=%d total=%d", q.Taxes, q.Total)
+	}
+}
+
+func TestQuote_ApplyCharges_CapsDiscountsAtSubtotal(t *testing.T) {
+	voucher := int64(50000)
+	q := &domain.Quote{Quantity: 1, Nights: make([]domain.NightlyRate, 1), Subtotal: 20000}
+
+	q.ApplyCharges([]*domain.HotelCharge{{Name: "Voucher", Type: domain.ChargeTypeDiscount, Amount: &voucher}}, nil)
+
+	if q.Discounts != 20000 || q.Total != 0 {
+		t.Errorf("expected discount capped at 20000 and total 0, got discounts=%d total=%d", q.Discounts, q.Total)
+	}
+}
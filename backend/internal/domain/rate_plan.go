@@ -0,0 +1,125 @@
+package domain
+
+import "time"
+
+// RatePlan prices the nights of a room. Starting from the room's base rate,
+// a night is priced by the most specific rule that covers it: a per-date
+// override, then the season it falls in, then the weekend rate, then the base
+// rate. The minimum stay is looked up the same way for the arrival date.
+type RatePlan struct {
+	RoomID int `json:"room_id" db:"room_id"`
+	// BasePrice and Currency come from the room: its standing nightly rate.
+	BasePrice int64  `json:"base_price" db:"price_per_night"`
+	Currency  string `json:"currency"   db:"currency"`
+	// WeekendPrice, when set, prices Friday and Saturday nights.
+	WeekendPrice *int64 `json:"weekend_price,omitempty" db:"weekend_price"`
+	// MinStay is the minimum number of nights of a stay; zero means one.
+	MinStay   int             `json:"min_stay"  db:"min_stay"`
+	Seasons   []*SeasonalRate `json:"seasons"`
+	Overrides []*DateRate     `json:"overrides"`
+}
+
+// SeasonalRate prices the nights from StartDate up to, but not including,
+// EndDate. Seasons of a room never overlap.
+type SeasonalRate struct {
+	ID        int       `json:"id"         db:"id"`
+	Name      string    `json:"name"       db:"name"`
+	StartDate time.Time `json:"start_date" db:"start_date"`
+	EndDate   time.Time `json:"end_date"   db:"end_date"`
+	Price     int64     `json:"price"      db:"price"`
+	// WeekendPrice, when set, prices Friday and Saturday nights of the season.
+	WeekendPrice *int64 `json:"weekend_price,omitempty" db:"weekend_price"`
+	// MinStay, when set, applies to stays arriving during the season.
+	MinStay *int `json:"min_stay,omitempty" db:"min_stay"`
+}
+
+// DateRate overrides the price and/or minimum stay of a single night. It is
+// stored on the inventory row of that night.
+type DateRate struct {
+	Date    time.Time `json:"date"               db:"date"`
+	Price   *int64    `json:"price,omitempty"    db:"price"`
+	MinStay *int      `json:"min_stay,omitempty" db:"min_stay"`
+}
+
+// NightlyRate is the price of one night of a stay, per unit, in the hotel's
+// currency.
+type NightlyRate struct {
+	Date  time.Time `json:"date"`
+	Price int64     `json:"price"`
+}
+
+// IsWeekendNight reports whether the night starting on date is a Friday or
+// Saturday night.
+func IsWeekendNight(date time.Time) bool {
+	day := date.Weekday()
+	return day == time.Friday || day == time.Saturday
+}
+
+// NightlyRates returns the price of every night from checkIn up to, but not
+// including, checkOut.
+func (p *RatePlan) NightlyRates(checkIn, checkOut time.Time) []NightlyRate {
+	var nights []NightlyRate
+	for _, d := range StayNights(civilDate(checkIn), civilDate(checkOut)) {
+		nights = append(nights, NightlyRate{Date: d, Price: p.RateFor(d)})
+	}
+	return nights
+}
+
+// RateFor returns the price of the night starting on date.
+func (p *RatePlan) RateFor(date time.Time) int64 {
+	date = civilDate(date)
+	if o := p.override(date); o != nil && o.Price != nil {
+		return *o.Price
+	}
+	weekend := IsWeekendNight(date)
+	if s := p.season(date); s != nil {
+		if weekend && s.WeekendPrice != nil {
+			return *s.WeekendPrice
+		}
+		return s.Price
+	}
+	if weekend && p.WeekendPrice != nil {
+		return *p.WeekendPrice
+	}
+	return p.BasePrice
+}
+
+// MinStayFor returns the minimum number of nights of a stay arriving on checkIn.
+func (p *RatePlan) MinStayFor(checkIn time.Time) int {
+	checkIn = civilDate(checkIn)
+	if o := p.override(checkIn); o != nil && o.MinStay != nil {
+		return *o.MinStay
+	}
+	if s := p.season(checkIn); s != nil && s.MinStay != nil {
+		return *s.MinStay
+	}
+	if p.MinStay > 0 {
+		return p.MinStay
+	}
+	return 1
+}
+
+func (p *RatePlan) override(date time.Time) *DateRate {
+	for _, o := range p.Overrides {
+		if civilDate(o.Date).Equal(date) {
+			return o
+		}
+	}
+	return nil
+}
+
+func (p *RatePlan) season(date time.Time) *SeasonalRate {
+	for _, s := range p.Seasons {
+		if !date.Before(civilDate(s.StartDate)) && date.Before(civilDate(s.EndDate)) {
+			return s
+		}
+	}
+	return nil
+}
+
+// civilDate strips the time of day and location from t, so dates parsed from
+// requests and scanned from DATE columns compare equal.
+func civilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,78 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"testing"
+	"time"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRatePlan_RateFor_MostSpecificRuleWins(t *testing.T) {
+	weekend, seasonWeekend, override := int64(150), int64(280), int64(500)
+	plan := &domain.RatePlan{
+		BasePrice:    100,
+		WeekendPrice: &weekend,
+		Seasons: []*domain.SeasonalRate{
+			{Name: "Winter", StartDate: day(12, 20), EndDate: day(12, 31), Price: 250, WeekendPrice: &seasonWeekend},
+		},
+		Overrides: []*domain.DateRate{{Date: day(12, 24), Price: &override}},
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want int64
+	}{
+		{"weekday uses base rate", day(12, 1), 100},
+		{"friday uses weekend rate", day(12, 4), 150},
+		{"season weekday", day(12, 21), 250},
+		{"season weekend", day(12, 26), 280},
+		{"override beats season", day(12, 24), 500},
+		{"season end date is excluded", day(12, 31), 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plan.RateFor(tt.date); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRatePlan_NightlyRates_ExcludesCheckOut(t *testing.T) {
+	plan := &domain.RatePlan{BasePrice: 100}
+
+	nights := plan.NightlyRates(day(3, 1), day(3, 4))
+
+	if len(nights) != 3 {
+		t.Fatalf("expected 3 nights, got %d", len(nights))
+	}
+	if !nights[2].Date.Equal(day(3, 3)) {
+		t.Errorf("expected last night on the day before check-out, got %v", nights[2].Date)
+	}
+}
+
+func TestRatePlan_MinStayFor(t *testing.T) {
+	seasonMin, dateMin := 3, 5
+	plan := &domain.RatePlan{
+		MinStay:   2,
+		Seasons:   []*domain.SeasonalRate{{StartDate: day(7, 1), EndDate: day(9, 1), Price: 200, MinStay: &seasonMin}},
+		Overrides: []*domain.DateRate{{Date: day(8, 15), MinStay: &dateMin}},
+	}
+
+	if got := plan.MinStayFor(day(6, 10)); got != 2 {
+		t.Errorf("outside season: expected 2, got %d", got)
+	}
+	if got := plan.MinStayFor(day(7, 10)); got != 3 {
+		t.Errorf("in season: expected 3, got %d", got)
+	}
+	if got := plan.MinStayFor(day(8, 15)); got != 5 {
+		t.Errorf("overridden date: expected 5, got %d", got)
+	}
+	if got := (&domain.RatePlan{}).MinStayFor(day(6, 10)); got != 1 {
+		t.Errorf("empty plan: expected 1, got %d", got)
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// RefundStatus represents the lifecycle state of a refund.
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+// Refund is money sent back against a succeeded charge. A charge may be
+// refunded several times in part; the refunds' total never exceeds its amount.
+type Refund struct {
+	ID             string       `json:"id" db:"id"`
+	PaymentID      string       `json:"payment_id" db:"payment_id"`
+	BookingID      int          `json:"booking_id" db:"booking_id"`
+	Amount         int64        `json:"amount" db:"amount"` // minor units of Currency
+	Currency       string       `json:"currency" db:"currency"`
+	Status         RefundStatus `json:"status" db:"status"`
+	Reason         string       `json:"reason,omitempty" db:"reason"`
+	IdempotencyKey string       `json:"idempotency_key" db:"idempotency_key"`
+	GatewayRef     string       `json:"gateway_ref,omitempty" db:"gateway_ref"`
+	FailedReason   string       `json:"failed_reason,omitempty" db:"failed_reason"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Refund event type constants used as outbox event_type values.
+const (
+	EventTypeRefundRequested = "RefundRequested"
+	EventTypeRefundSucceeded = "RefundSucceeded"
+	EventTypeRefundFailed    = "RefundFailed"
+)
+
+// RefundRequestedPayload is the event payload for RefundRequested.
+type RefundRequestedPayload struct {
+	RefundID  string `json:"refund_id"`
+	PaymentID string `json:"payment_id"`
+	BookingID int    `json:"booking_id"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	UserID    string `json:"user_id"`
+}
+
+// RefundResultPayload is the event payload for RefundSucceeded and RefundFailed.
+type RefundResultPayload struct {
+	RefundID   string `json:"refund_id"`
+	PaymentID  string `json:"payment_id"`
+	BookingID  int    `json:"booking_id"`
+	Reason     string `json:"reason,omitempty"`
+	GatewayRef string `json:"gateway_ref,omitempty"`
+}
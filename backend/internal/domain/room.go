@@ -1,18 +1,106 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Room represents a bookable room within a hotel.
 type Room struct {
-	ID            int       `json:"id"             db:"id"`
-	HotelID       int       `json:"hotel_id"       db:"hotel_id"`
-	Name          string    `json:"name"           db:"name"`
-	Description   string    `json:"description"    db:"description"`
-	Capacity      int       `json:"capacity"       db:"capacity"`
-	PricePerNight float64   `json:"price_per_night" db:"price_per_night"`
+	ID          int    `json:"id"             db:"id"`
+	HotelID     int    `json:"hotel_id"       db:"hotel_id"`
+	Name        string `json:"name"           db:"name"`
+	Description string `json:"description"    db:"description"`
+	// Capacity is the number of guests a unit sleeps at its base rate; zero
+	// is unlimited. Each unit can take up to MaxExtraBeds more guests, each
+	// charged ExtraGuestFee (minor units of Currency) per night.
+	Capacity      int   `json:"capacity"        db:"capacity"`
+	MaxExtraBeds  int   `json:"max_extra_beds"  db:"max_extra_beds"`
+	ExtraGuestFee int64 `json:"extra_guest_fee" db:"extra_guest_fee"`
+	// PricePerNight is in minor units of Currency, the hotel's currency.
+	PricePerNight int64     `json:"price_per_night" db:"price_per_night"`
+	Currency      string    `json:"currency"       db:"currency"`
 	Amenities     []string  `json:"amenities"      db:"amenities"`
 	Images        []string  `json:"images"         db:"images"`
 	IsActive      bool      `json:"is_active"      db:"is_active"`
 	CreatedAt     time.Time `json:"created_at"     db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"     db:"updated_at"`
+	// Overbooking is how far past its inventory the room may be sold.
+	Overbooking Overbooking `json:"overbooking"`
+}
+
+// OverbookingType is how a room's overbooking allowance is expressed.
+type OverbookingType string
+
+const (
+	OverbookingUnits   OverbookingType = "units"
+	OverbookingPercent OverbookingType = "percent"
+)
+
+// Overbooking is how far past its inventory a room may be sold each night:
+// Value units, or Value percent of the night's inventory.
+type Overbooking struct {
+	Type  OverbookingType `json:"type"  db:"overbooking_type"`
+	Value int             `json:"value" db:"overbooking_value"`
+}
+
+// Validate returns ErrBadRequest unless the allowance is a known type with a
+// non-negative value, at most 100 percent.
+func (o Overbooking) Validate() error {
+	switch o.Type {
+	case OverbookingUnits:
+	case OverbookingPercent:
+		if o.Value > 100 {
+			return fmt.Errorf("overbooking percent must be at most 100: %w", ErrBadRequest)
+		}
+	default:
+		return fmt.Errorf("overbooking type must be %q or %q: %w", OverbookingUnits, OverbookingPercent, ErrBadRequest)
+	}
+	if o.Value < 0 {
+		return fmt.Errorf("overbooking value must be non-negative: %w", ErrBadRequest)
+	}
+	return nil
+}
+
+// Allowance returns how many units past total may be sold on a night with
+// total units. Percentages round down, and a night with no inventory is
+// never oversold.
+func (o Overbooking) Allowance(total int) int {
+	if total <= 0 || o.Value <= 0 {
+		return 0
+	}
+	if o.Type == OverbookingPercent {
+		return total * o.Value / 100
+	}
+	return o.Value
+}
+
+// Sellable returns how many units may be sold on a night with total units.
+func (o Overbooking) Sellable(total int) int {
+	return total + o.Allowance(total)
+}
+
+// CheckOccupancy returns ErrBadRequest unless adults and children fit in
+// quantity units of the room, extra beds included, with at least one adult.
+// It returns how many of the guests sleep in extra beds.
+func (r *Room) CheckOccupancy(adults, children, quantity int) (int, error) {
+	if adults < 1 {
+		return 0, fmt.Errorf("at least one adult is required: %w", ErrBadRequest)
+	}
+	if children < 0 {
+		return 0, fmt.Errorf("children must be non-negative: %w", ErrBadRequest)
+	}
+	if r.Capacity <= 0 {
+		return 0, nil
+	}
+
+	guests := adults + children
+	if limit := (r.Capacity + r.MaxExtraBeds) * quantity; guests > limit {
+		return 0, fmt.Errorf("%d guests exceed the %d that %d unit(s) of the room can sleep: %w",
+			guests, limit, quantity, ErrBadRequest)
+	}
+	if extra := guests - r.Capacity*quantity; extra > 0 {
+		return extra, nil
+	}
+	return 0, nil
 }
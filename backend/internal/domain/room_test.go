@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"errors"
+	"testing"
+)
+
+func TestRoom_CheckOccupancy(t *testing.T) {
+	room := &domain.Room{Capacity: 2, MaxExtraBeds: 1}
+	tests := []struct {
+		name                       string
+		adults, children, quantity int
+		wantExtra                  int
+		wantErr                    bool
+	}{
+		{"within capacity", 2, 0, 1, 0, false},
+		{"child in an extra bed", 2, 1, 1, 1, false},
+		{"more than the extra beds", 2, 2, 1, 0, true},
+		{"spread over two units", 3, 2, 2, 1, false},
+		{"no adult", 0, 2, 1, 0, true},
+		{"negative children", 1, -1, 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extra, err := room.CheckOccupancy(tt.adults, tt.children, tt.quantity)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrBadRequest) {
+					t.Errorf("expected ErrBadRequest, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if extra != tt.wantExtra {
+				t.Errorf("expected %d extra guests, got %d", tt.wantExtra, extra)
+			}
+		})
+	}
+}
+
+func TestRoom_CheckOccupancy_UnlimitedCapacity(t *testing.T) {
+	room := &domain.Room{}
+	extra, err := room.CheckOccupancy(8, 4, 1)
+	if err != nil || extra != 0 {
+		t.Errorf("expected no limit and no extra guests, got %d, %v", extra, err)
+	}
+}
+
+func TestOverbooking_Allowance(t *testing.T) {
+	tests := []struct {
+		name        string
+		overbooking domain.Overbooking
+		total       int
+		want        int
+	}{
+		{"no allowance", domain.Overbooking{}, 10, 0},
+		{"units", domain.Overbooking{Type: domain.OverbookingUnits, Value: 2}, 10, 2},
+		{"percent", domain.Overbooking{Type: domain.OverbookingPercent, Value: 10}, 20, 2},
+		{"percent rounds down", domain.Overbooking{Type: domain.OverbookingPercent, Value: 10}, 19, 1},
+		{"closed night is never oversold", domain.Overbooking{Type: domain.OverbookingUnits, Value: 2}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.overbooking.Allowance(tt.total); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+			if got := tt.overbooking.Sellable(tt.total); got != tt.total+tt.want {
+				t.Errorf("expected %d sellable, got %d", tt.total+tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOverbooking_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		overbooking domain.Overbooking
+		wantErr     bool
+	}{
+		{"units", domain.Overbooking{Type: domain.OverbookingUnits, Value: 3}, false},
+		{"full percent", domain.Overbooking{Type: domain.OverbookingPercent, Value: 100}, false},
+		{"percent above 100", domain.Overbooking{Type: domain.OverbookingPercent, Value: 101}, true},
+		{"negative", domain.Overbooking{Type: domain.OverbookingUnits, Value: -1}, true},
+		{"unknown type", domain.Overbooking{Type: "rooms", Value: 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.overbooking.Validate()
+			if tt.wantErr && !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+// bookingTransitions maps each booking status to the statuses a booking may
+// move to it from. Every booking starts pending.
+var bookingTransitions = map[string][]string{
+	BookingStatusAwaitingPayment: {BookingStatusPending},
+	BookingStatusProcessing:      {BookingStatusAwaitingPayment},
+	BookingStatusConfirmed:       {BookingStatusAwaitingPayment, BookingStatusProcessing},
+	BookingStatusFailed:          {BookingStatusAwaitingPayment, BookingStatusProcessing},
+	BookingStatusCancelled:       {BookingStatusPending, BookingStatusAwaitingPayment, BookingStatusProcessing, BookingStatusConfirmed},
+	BookingStatusRefunded:        {BookingStatusConfirmed},
+	BookingStatusExpired:         {BookingStatusPending, BookingStatusAwaitingPayment},
+}
+
+// paymentTransitions maps each payment status to the statuses a payment may
+// move to it from. Every payment starts pending. A payment timed out while
+// the gateway was still working on it may yet be authorized or captured; the
+// saga then gives the money back if its booking is gone.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusProcessing:        {PaymentStatusPending},
+	PaymentStatusAuthorized:        {PaymentStatusPending, PaymentStatusProcessing, PaymentStatusTimedOut},
+	PaymentStatusCaptured:          {PaymentStatusPending, PaymentStatusProcessing, PaymentStatusTimedOut, PaymentStatusAuthorized},
+	PaymentStatusSucceeded:         {PaymentStatusPending, PaymentStatusProcessing},
+	PaymentStatusFailed:            {PaymentStatusPending, PaymentStatusProcessing, PaymentStatusAuthorized},
+	PaymentStatusTimedOut:          {PaymentStatusPending, PaymentStatusProcessing},
+	PaymentStatusVoided:            {PaymentStatusAuthorized},
+	PaymentStatusPartiallyRefunded: {PaymentStatusSucceeded, PaymentStatusCaptured, PaymentStatusPartiallyRefunded},
+	PaymentStatusRefunded:          {PaymentStatusSucceeded, PaymentStatusCaptured, PaymentStatusPartiallyRefunded},
+}
+
+// BookingStatusesBefore returns the statuses a booking may move to status
+// from; none for pending or an unknown status.
+func BookingStatusesBefore(status string) []string {
+	return bookingTransitions[status]
+}
+
+// CanMoveBooking reports whether a booking may move from one status to
+// another.
+func CanMoveBooking(from, to string) bool {
+	for _, allowed := range bookingTransitions[to] {
+		if allowed == from {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentStatusesBefore returns the statuses a payment may move to status
+// from; none for pending or an unknown status.
+func PaymentStatusesBefore(status PaymentStatus) []string {
+	before := make([]string, 0, len(paymentTransitions[status]))
+	for _, from := range paymentTransitions[status] {
+		before = append(before, string(from))
+	}
+	return before
+}
+
+// CanMovePayment reports whether a payment may move from one status to
+// another.
+func CanMovePayment(from, to PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[to] {
+		if allowed == from {
+			return true
+		}
+	}
+	return false
+}
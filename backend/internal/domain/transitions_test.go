@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"booking-app/internal/domain"
+	"testing"
+)
+
+func TestCanMoveBooking(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{domain.BookingStatusPending, domain.BookingStatusAwaitingPayment, true},
+		{domain.BookingStatusAwaitingPayment, domain.BookingStatusConfirmed, true},
+		{domain.BookingStatusConfirmed, domain.BookingStatusRefunded, true},
+		{domain.BookingStatusAwaitingPayment, domain.BookingStatusExpired, true},
+		{domain.BookingStatusCancelled, domain.BookingStatusConfirmed, false},
+		{domain.BookingStatusExpired, domain.BookingStatusFailed, false},
+		{domain.BookingStatusPending, domain.BookingStatusConfirmed, false},
+		{domain.BookingStatusConfirmed, domain.BookingStatusPending, false},
+	}
+	for _, tt := range tests {
+		if got := domain.CanMoveBooking(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanMoveBooking(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCanMovePayment(t *testing.T) {
+	tests := []struct {
+		from, to domain.PaymentStatus
+		want     bool
+	}{
+		{domain.PaymentStatusProcessing, domain.PaymentStatusCaptured, true},
+		{domain.PaymentStatusAuthorized, domain.PaymentStatusVoided, true},
+		{domain.PaymentStatusCaptured, domain.PaymentStatusPartiallyRefunded, true},
+		{domain.PaymentStatusTimedOut, domain.PaymentStatusCaptured, true},
+		{domain.PaymentStatusTimedOut, domain.PaymentStatusFailed, false},
+		{domain.PaymentStatusCaptured, domain.PaymentStatusFailed, false},
+		{domain.PaymentStatusRefunded, domain.PaymentStatusPartiallyRefunded, false},
+	}
+	for _, tt := range tests {
+		if got := domain.CanMovePayment(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanMovePayment(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestStatusesBefore_PendingHasNone(t *testing.T) {
+	if before := domain.BookingStatusesBefore(domain.BookingStatusPending); len(before) != 0 {
+		t.Errorf("expected no way back into pending for bookings, got %v", before)
+	}
+	if before := domain.PaymentStatusesBefore(domain.PaymentStatusPending); len(before) != 0 {
+		t.Errorf("expected no way back into pending for payments, got %v", before)
+	}
+}
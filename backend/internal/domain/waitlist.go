@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// WaitlistStatus constants define the lifecycle of a waitlist entry.
+const (
+	// WaitlistStatusWaiting entries wait for their stay to free up.
+	WaitlistStatusWaiting = "waiting"
+	// WaitlistStatusOffered entries hold the stay's inventory until
+	// OfferExpiresAt; the guest can claim it as a booking meanwhile.
+	WaitlistStatusOffered = "offered"
+	// WaitlistStatusClaimed entries were turned into BookingID.
+	WaitlistStatusClaimed = "claimed"
+	// WaitlistStatusExpired entries let their offer lapse; the held
+	// inventory was offered to the next guest in line.
+	WaitlistStatusExpired = "expired"
+	// WaitlistStatusCancelled entries were withdrawn by the guest.
+	WaitlistStatusCancelled = "cancelled"
+)
+
+// WaitlistEntry is a guest waiting for a sold-out stay. Whenever inventory of
+// the room is released for the stay's dates, the oldest waiting entries that
+// fit are offered a time-limited hold on it.
+type WaitlistEntry struct {
+	ID        int       `json:"id"         db:"id"`
+	UserID    string    `json:"user_id"    db:"user_id"`
+	RoomID    int       `json:"room_id"    db:"room_id"`
+	StartDate time.Time `json:"start_date" db:"start_date"`
+	EndDate   time.Time `json:"end_date"   db:"end_date"`
+	Quantity  int       `json:"quantity"   db:"quantity"`
+	Adults    int       `json:"adults"     db:"adults"`
+	Children  int       `json:"children"   db:"children"`
+	Status    string    `json:"status"     db:"status"`
+	// OfferExpiresAt is when an offered hold lapses.
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty" db:"offer_expires_at"`
+	// BookingID is the booking a claimed offer was turned into.
+	BookingID *int      `json:"booking_id,omitempty" db:"booking_id"`
+	CreatedAt time.Time `json:"created_at"           db:"created_at"`
+}
+
+// HoldsInventory reports whether the entry holds its stay's inventory.
+func (e *WaitlistEntry) HoldsInventory() bool {
+	return e.Status == WaitlistStatusOffered
+}
+
+// JoinWaitlistInput is the data a guest provides to wait for a stay.
+// Zero quantity and adults mean one.
+type JoinWaitlistInput struct {
+	UserID    string
+	RoomID    int
+	StartDate time.Time
+	EndDate   time.Time
+	Quantity  int
+	Adults    int
+	Children  int
+}
+
+// ClaimWaitlistOfferInput is the data a guest provides to book an offer.
+// An empty currency pays in the hotel's.
+type ClaimWaitlistOfferInput struct {
+	UserID   string
+	EntryID  int
+	Currency string
+}
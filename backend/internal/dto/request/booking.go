@@ -6,6 +6,17 @@ type CreateBookingRequest struct {
 	RoomID    int    `json:"room_id" binding:"required"`
 	StartDate string `json:"start_date" binding:"required"`
 	EndDate   string `json:"end_date" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"omitempty,min=1"`
+	// Adults defaults to one; adults and children must fit in the room.
+	Adults   int `json:"adults" binding:"omitempty,min=1"`
+	Children int `json:"children" binding:"omitempty,min=0"`
+	// Currency is the ISO 4217 code to pay in; omit to pay in the hotel's.
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	// QuoteToken books the stay at the price of a quote from
+	// GET /rooms/:id/quote for the same room, dates, quantity and guests.
+	QuoteToken string `json:"quote_token"`
+	// PromoCode is a promo code to redeem; ignored with a quote token.
+	PromoCode string `json:"promo_code" binding:"omitempty,max=50"`
 }
 
 // LegacyCreateBookingRequest is used by the legacy /api/bookings endpoint
@@ -15,4 +26,31 @@ type LegacyCreateBookingRequest struct {
 	RoomID    int    `json:"room_id" binding:"required"`
 	StartDate string `json:"start_date" binding:"required"`
 	EndDate   string `json:"end_date" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"omitempty,min=1"`
+}
+
+// ModifyBookingRequest changes the dates and/or room of an existing booking.
+// Omitted fields keep their current value; the room must be in the same hotel.
+type ModifyBookingRequest struct {
+	RoomID    int    `json:"room_id" binding:"omitempty,min=1"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// ItineraryLegRequest is a single room reservation inside CreateItineraryRequest.
+type ItineraryLegRequest struct {
+	RoomID    int    `json:"room_id" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"omitempty,min=1"`
+	Adults    int    `json:"adults" binding:"omitempty,min=1"`
+	Children  int    `json:"children" binding:"omitempty,min=0"`
+}
+
+// CreateItineraryRequest contains the legs of a multi-room itinerary.
+// UserID is intentionally absent — it is taken from the JWT context.
+type CreateItineraryRequest struct {
+	Legs []ItineraryLegRequest `json:"legs" binding:"required,min=1,dive"`
+	// Currency is required when the legs' hotels price in different currencies.
+	Currency string `json:"currency" binding:"omitempty,len=3"`
 }
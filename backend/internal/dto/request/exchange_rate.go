@@ -0,0 +1,14 @@
+package request
+
+// ExchangeRateRequest is one rate in SetExchangeRatesRequest: one unit of
+// base buys rate units of quote.
+type ExchangeRateRequest struct {
+	Base  string  `json:"base"  binding:"required,len=3"`
+	Quote string  `json:"quote" binding:"required,len=3"`
+	Rate  float64 `json:"rate"  binding:"required,gt=0"`
+}
+
+// SetExchangeRatesRequest is the body for PUT /admin/exchange-rates.
+type SetExchangeRatesRequest struct {
+	Rates []ExchangeRateRequest `json:"rates" binding:"required,min=1,dive"`
+}
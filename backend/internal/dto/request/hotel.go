@@ -13,6 +13,7 @@ type CreateHotelRequest struct {
 	Images      []string `json:"images"`
 	StarRating  int      `json:"star_rating"`
 	Description string   `json:"description"`
+	Currency    string   `json:"currency"` // ISO 4217; defaults to USD
 }
 
 // UpdateHotelRequest is the body for PUT /owner/hotels/:id.
@@ -28,6 +29,7 @@ type UpdateHotelRequest struct {
 	Images      []string `json:"images"`
 	StarRating  int      `json:"star_rating"`
 	Description string   `json:"description"`
+	Currency    string   `json:"currency"`
 }
 
 // CreateRoomRequest is the body for POST /owner/hotels/:id/rooms.
@@ -35,7 +37,9 @@ type CreateRoomRequest struct {
 	Name          string   `json:"name"           binding:"required"`
 	Description   string   `json:"description"`
 	Capacity      int      `json:"capacity"`
-	PricePerNight float64  `json:"price_per_night"`
+	MaxExtraBeds  int      `json:"max_extra_beds"`
+	ExtraGuestFee int64    `json:"extra_guest_fee"` // per guest in an extra bed per night
+	PricePerNight int64    `json:"price_per_night"` // minor units of the hotel's currency
 	Amenities     []string `json:"amenities"`
 	Images        []string `json:"images"`
 }
@@ -45,7 +49,9 @@ type UpdateRoomRequest struct {
 	Name          string   `json:"name"`
 	Description   string   `json:"description"`
 	Capacity      int      `json:"capacity"`
-	PricePerNight float64  `json:"price_per_night"`
+	MaxExtraBeds  int      `json:"max_extra_beds"`
+	ExtraGuestFee int64    `json:"extra_guest_fee"` // per guest in an extra bed per night
+	PricePerNight int64    `json:"price_per_night"` // minor units of the hotel's currency
 	Amenities     []string `json:"amenities"`
 	Images        []string `json:"images"`
 	IsActive      bool     `json:"is_active"`
@@ -58,6 +64,71 @@ type SetInventoryRequest struct {
 	Total     int    `json:"total"      binding:"required,min=0"`
 }
 
+// SetRatePlanRequest is the body for PUT /owner/rooms/:id/rates. It replaces
+// every rule of the room's rate plan. Prices are in minor units of the
+// hotel's currency and dates are YYYY-MM-DD.
+type SetRatePlanRequest struct {
+	// WeekendPrice prices Friday and Saturday nights. Omit to use the base rate.
+	WeekendPrice *int64 `json:"weekend_price" binding:"omitempty,min=1"`
+	// MinStay is the minimum number of nights of a stay. Omit for one.
+	MinStay   int                   `json:"min_stay"  binding:"min=0"`
+	Seasons   []SeasonalRateRequest `json:"seasons"   binding:"dive"`
+	Overrides []DateRateRequest     `json:"overrides" binding:"dive"`
+}
+
+// SeasonalRateRequest prices the nights from StartDate up to, but not
+// including, EndDate.
+type SeasonalRateRequest struct {
+	Name         string `json:"name"          binding:"max=100"`
+	StartDate    string `json:"start_date"    binding:"required"`
+	EndDate      string `json:"end_date"      binding:"required"`
+	Price        int64  `json:"price"         binding:"required,min=1"`
+	WeekendPrice *int64 `json:"weekend_price" binding:"omitempty,min=1"`
+	MinStay      *int   `json:"min_stay"      binding:"omitempty,min=1"`
+}
+
+// DateRateRequest overrides the price and/or minimum stay of a single night.
+type DateRateRequest struct {
+	Date    string `json:"date"     binding:"required"`
+	Price   *int64 `json:"price"    binding:"omitempty,min=1"`
+	MinStay *int   `json:"min_stay" binding:"omitempty,min=1"`
+}
+
+// SetOverbookingRequest is the body for PUT /owner/rooms/:id/overbooking.
+// Value is a number of units, or a percentage of each night's inventory.
+type SetOverbookingRequest struct {
+	Type  string `json:"type"  binding:"required,oneof=units percent"`
+	Value int    `json:"value" binding:"min=0"`
+}
+
+// HotelChargeRequest is a tax, service fee or discount of a hotel. Exactly
+// one of Percent and Amount (minor units of the hotel's currency) is set.
+type HotelChargeRequest struct {
+	Name      string   `json:"name"       binding:"required,max=100"`
+	Type      string   `json:"type"       binding:"required,oneof=discount fee tax"`
+	Percent   *float64 `json:"percent"    binding:"omitempty,gt=0,lte=100"`
+	Amount    *int64   `json:"amount"     binding:"omitempty,min=1"`
+	PerNight  bool     `json:"per_night"`
+	MinNights int      `json:"min_nights" binding:"min=0"`
+}
+
+// SetHotelChargesRequest is the body for PUT /owner/hotels/:id/charges. It
+// replaces every charge of the hotel.
+type SetHotelChargesRequest struct {
+	Charges []HotelChargeRequest `json:"charges" binding:"dive"`
+}
+
+// SetCancellationPolicyRequest is the body for
+// PUT /owner/hotels/:id/cancellation-policy and PUT /owner/rooms/:id/cancellation-policy.
+type SetCancellationPolicyRequest struct {
+	FreeCancellationDays int     `json:"free_cancellation_days" binding:"min=0"`
+	PenaltyPercent       float64 `json:"penalty_percent"        binding:"min=0,max=100"`
+	NonRefundable        bool    `json:"non_refundable"`
+	// CaptureDaysBeforeCheckIn only authorizes the card at checkout and
+	// captures it that many days before check-in. Omit to capture at checkout.
+	CaptureDaysBeforeCheckIn *int `json:"capture_days_before_check_in" binding:"omitempty,min=0"`
+}
+
 // RejectHotelRequest is the body for PUT /admin/hotels/:id/reject.
 type RejectHotelRequest struct {
 	Reason string `json:"reason"`
@@ -0,0 +1,22 @@
+package request
+
+import "time"
+
+// CreatePromotionRequest is the body for POST /owner/promotions and
+// POST /admin/promotions. Exactly one of Percent and Amount (minor units of
+// Currency) is set. Owners must scope the code to one of their hotels.
+type CreatePromotionRequest struct {
+	Code        string   `json:"code"        binding:"required,max=50"`
+	Description string   `json:"description" binding:"max=500"`
+	HotelID     *int     `json:"hotel_id"    binding:"omitempty,min=1"`
+	RoomID      *int     `json:"room_id"     binding:"omitempty,min=1"`
+	Percent     *float64 `json:"percent"     binding:"omitempty,gt=0,lte=100"`
+	Amount      *int64   `json:"amount"      binding:"omitempty,min=1"`
+	// Currency of Amount; defaults to the hotel's for scoped codes.
+	Currency       string     `json:"currency"        binding:"omitempty,len=3"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	MinNights      int        `json:"min_nights"      binding:"min=0"`
+	MaxRedemptions *int       `json:"max_redemptions" binding:"omitempty,min=1"`
+	MaxPerUser     *int       `json:"max_per_user"    binding:"omitempty,min=1"`
+}
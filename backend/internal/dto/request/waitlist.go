@@ -0,0 +1,18 @@
+package request
+
+// JoinWaitlistRequest contains the stay a guest waits for.
+// UserID is intentionally absent — it is taken from the JWT context.
+type JoinWaitlistRequest struct {
+	RoomID    int    `json:"room_id" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"omitempty,min=1"`
+	Adults    int    `json:"adults" binding:"omitempty,min=1"`
+	Children  int    `json:"children" binding:"omitempty,min=0"`
+}
+
+// ClaimWaitlistOfferRequest books a waitlist offer.
+type ClaimWaitlistOfferRequest struct {
+	// Currency is the ISO 4217 code to pay in; omit to pay in the hotel's.
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+}
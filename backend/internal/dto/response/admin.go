@@ -16,24 +16,28 @@ type AdminUserResponse struct {
 
 // AdminBookingResponse is the public admin view of a booking.
 type AdminBookingResponse struct {
-	ID         int     `json:"id"`
-	UserID     string  `json:"user_id"`
-	RoomID     int     `json:"room_id"`
-	TotalPrice float64 `json:"total_price"`
-	Status     string  `json:"status"`
-	StartDate  string  `json:"start_date"`
-	EndDate    string  `json:"end_date"`
-	CreatedAt  string  `json:"created_at"`
+	ID         int    `json:"id"`
+	UserID     string `json:"user_id"`
+	RoomID     int    `json:"room_id"`
+	TotalPrice int64  `json:"total_price"`
+	Currency   string `json:"currency"`
+	Status     string `json:"status"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	CreatedAt  string `json:"created_at"`
 }
 
 // DLQEventResponse is the public admin view of a dead-letter queue event.
+// LastError is why the relay's final attempt to publish it failed.
 type DLQEventResponse struct {
-	ID            string `json:"id"`
-	AggregateType string `json:"aggregate_type"`
-	AggregateID   string `json:"aggregate_id"`
-	EventType     string `json:"event_type"`
-	RetryCount    int    `json:"retry_count"`
-	CreatedAt     string `json:"created_at"`
+	ID             string `json:"id"`
+	AggregateType  string `json:"aggregate_type"`
+	AggregateID    string `json:"aggregate_id"`
+	EventType      string `json:"event_type"`
+	RetryCount     int    `json:"retry_count"`
+	LastError      string `json:"last_error,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	DeadLetteredAt string `json:"dead_lettered_at,omitempty"`
 }
 
 // NewAdminUserResponse converts a domain User to an AdminUserResponse.
@@ -64,6 +68,7 @@ func NewAdminBookingResponse(b *domain.Booking) *AdminBookingResponse {
 		UserID:     b.UserID,
 		RoomID:     b.RoomID,
 		TotalPrice: b.TotalPrice,
+		Currency:   b.Currency,
 		Status:     b.Status,
 		StartDate:  b.StartDate.Format("2006-01-02"),
 		EndDate:    b.EndDate.Format("2006-01-02"),
@@ -82,14 +87,19 @@ func NewAdminBookingListResponse(bookings []*domain.Booking) []*AdminBookingResp
 
 // NewDLQEventResponse converts a domain OutboxEvent to a DLQEventResponse.
 func NewDLQEventResponse(e *domain.OutboxEvent) *DLQEventResponse {
-	return &DLQEventResponse{
+	resp := &DLQEventResponse{
 		ID:            e.ID,
 		AggregateType: e.AggregateType,
 		AggregateID:   e.AggregateID,
 		EventType:     e.EventType,
 		RetryCount:    e.RetryCount,
+		LastError:     e.LastError,
 		CreatedAt:     e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
+	if e.DeadLetteredAt != nil {
+		resp.DeadLetteredAt = e.DeadLetteredAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
 }
 
 // NewDLQEventListResponse converts a slice of domain OutboxEvents to DLQEventResponses.
@@ -0,0 +1,75 @@
+package response
+
+import "booking-app/internal/domain"
+
+// HotelAvailabilityResponse is the public availability calendar of a hotel.
+// Prices are in minor units of Currency, the hotel's.
+type HotelAvailabilityResponse struct {
+	HotelID  int                            `json:"hotel_id"`
+	From     string                         `json:"from"` // YYYY-MM-DD
+	To       string                         `json:"to"`   // YYYY-MM-DD, exclusive
+	Currency string                         `json:"currency"`
+	Rooms    []RoomTypeAvailabilityResponse `json:"rooms"`
+	Days     []DayAvailabilityResponse      `json:"days"`
+}
+
+// RoomTypeAvailabilityResponse summarises one room type over the calendar.
+type RoomTypeAvailabilityResponse struct {
+	RoomID      int    `json:"room_id"`
+	Name        string `json:"name"`
+	Capacity    int    `json:"capacity"`
+	LowestPrice *int64 `json:"lowest_price,omitempty"`
+}
+
+// DayAvailabilityResponse is the availability of every room type on a night.
+type DayAvailabilityResponse struct {
+	Date        string                          `json:"date"` // YYYY-MM-DD
+	Available   int                             `json:"available"`
+	LowestPrice *int64                          `json:"lowest_price,omitempty"`
+	Rooms       []RoomNightAvailabilityResponse `json:"rooms"`
+}
+
+// RoomNightAvailabilityResponse is one room type's units left and price on a night.
+type RoomNightAvailabilityResponse struct {
+	RoomID    int   `json:"room_id"`
+	Available int   `json:"available"`
+	Price     int64 `json:"price"`
+}
+
+// NewHotelAvailabilityResponse converts a domain HotelAvailability to a HotelAvailabilityResponse.
+func NewHotelAvailabilityResponse(a *domain.HotelAvailability) HotelAvailabilityResponse {
+	rooms := make([]RoomTypeAvailabilityResponse, 0, len(a.Rooms))
+	for _, r := range a.Rooms {
+		rooms = append(rooms, RoomTypeAvailabilityResponse{
+			RoomID:      r.RoomID,
+			Name:        r.Name,
+			Capacity:    r.Capacity,
+			LowestPrice: r.LowestPrice,
+		})
+	}
+	days := make([]DayAvailabilityResponse, 0, len(a.Days))
+	for _, d := range a.Days {
+		nights := make([]RoomNightAvailabilityResponse, 0, len(d.Rooms))
+		for _, n := range d.Rooms {
+			nights = append(nights, RoomNightAvailabilityResponse{
+				RoomID:    n.RoomID,
+				Available: n.Available,
+				Price:     n.Price,
+			})
+		}
+		days = append(days, DayAvailabilityResponse{
+			Date:        d.Date.Format("2006-01-02"),
+			Available:   d.Available,
+			LowestPrice: d.LowestPrice,
+			Rooms:       nights,
+		})
+	}
+	return HotelAvailabilityResponse{
+		HotelID:  a.HotelID,
+		From:     a.From.Format("2006-01-02"),
+		To:       a.To.Format("2006-01-02"),
+		Currency: a.Currency,
+		Rooms:    rooms,
+		Days:     days,
+	}
+}
@@ -12,9 +12,47 @@ type BookingResponse struct {
 	RoomID     int       `json:"room_id"`
 	StartDate  time.Time `json:"start_date"`
 	EndDate    time.Time `json:"end_date"`
-	TotalPrice float64   `json:"total_price"`
+	Quantity   int       `json:"quantity"`
+	Adults     int       `json:"adults"`
+	Children   int       `json:"children"`
+	TotalPrice int64     `json:"total_price"` // minor units of Currency, the hotel's
+	Currency   string    `json:"currency"`
 	Status     string    `json:"status"`
 	CreatedAt  time.Time `json:"created_at"`
+	// ItineraryID is set when the booking is a leg of an itinerary.
+	ItineraryID *int `json:"itinerary_id,omitempty"`
+	// ChargeTotal is what the guest pays, in minor units of ChargeCurrency,
+	// converted from TotalPrice at FxRate.
+	ChargeTotal    int64   `json:"charge_total"`
+	ChargeCurrency string  `json:"charge_currency"`
+	FxRate         float64 `json:"fx_rate"`
+	// Nights breaks TotalPrice down into the per-unit rate of every night.
+	Nights []NightlyRateResponse `json:"nights"`
+	// Lines itemises the discounts, fees and taxes included in TotalPrice.
+	Lines []PriceLineResponse `json:"lines"`
+	// ExtraGuestCharge is what the guests in extra beds pay, included in
+	// TotalPrice.
+	ExtraGuestCharge int64 `json:"extra_guest_charge"`
+	// PromoCode is the promo code redeemed, if any; PromoDiscount is its share
+	// of the discounts in Lines.
+	PromoCode     string `json:"promo_code,omitempty"`
+	PromoDiscount int64  `json:"promo_discount,omitempty"`
+}
+
+// NightlyRateResponse is the price of one night of a booking, per unit.
+type NightlyRateResponse struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Price int64  `json:"price"`
+}
+
+// ItineraryResponse is the public representation of an itinerary and its legs.
+type ItineraryResponse struct {
+	ID         int               `json:"id"`
+	UserID     string            `json:"user_id"`
+	TotalPrice int64             `json:"total_price"`
+	Currency   string            `json:"currency"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Legs       []BookingResponse `json:"legs"`
 }
 
 // BookingStatusResponse wraps just the status string.
@@ -26,15 +64,38 @@ type BookingStatusResponse struct {
 // NewBookingResponse converts a domain Booking to a BookingResponse.
 func NewBookingResponse(b *domain.Booking) BookingResponse {
 	return BookingResponse{
-		ID:         b.ID,
-		UserID:     b.UserID,
-		RoomID:     b.RoomID,
-		StartDate:  b.StartDate,
-		EndDate:    b.EndDate,
-		TotalPrice: b.TotalPrice,
-		Status:     b.Status,
-		CreatedAt:  b.CreatedAt,
+		ID:             b.ID,
+		UserID:         b.UserID,
+		RoomID:         b.RoomID,
+		StartDate:      b.StartDate,
+		EndDate:        b.EndDate,
+		Quantity:       b.Quantity,
+		Adults:         b.Adults,
+		Children:       b.Children,
+		TotalPrice:     b.TotalPrice,
+		Currency:       b.Currency,
+		Status:         b.Status,
+		CreatedAt:      b.CreatedAt,
+		ItineraryID:    b.ItineraryID,
+		ChargeTotal:    b.ChargeTotal,
+		ChargeCurrency: b.ChargeCurrency,
+		FxRate:         b.FxRate,
+		Nights:         NewNightlyRateListResponse(b.Nights),
+		Lines:          NewPriceLineListResponse(b.Lines),
+		PromoCode:      b.PromoCode,
+		PromoDiscount:  b.PromoDiscount,
+
+		ExtraGuestCharge: b.ExtraGuestCharge,
+	}
+}
+
+// NewNightlyRateListResponse converts a booking's nightly breakdown.
+func NewNightlyRateListResponse(nights []domain.NightlyRate) []NightlyRateResponse {
+	result := make([]NightlyRateResponse, 0, len(nights))
+	for _, n := range nights {
+		result = append(result, NightlyRateResponse{Date: n.Date.Format("2006-01-02"), Price: n.Price})
 	}
+	return result
 }
 
 // NewBookingListResponse converts a slice of domain Bookings to BookingResponses.
@@ -45,3 +106,15 @@ func NewBookingListResponse(bookings []*domain.Booking) []BookingResponse {
 	}
 	return result
 }
+
+// NewItineraryResponse converts a domain Itinerary to an ItineraryResponse.
+func NewItineraryResponse(it *domain.Itinerary) ItineraryResponse {
+	return ItineraryResponse{
+		ID:         it.ID,
+		UserID:     it.UserID,
+		TotalPrice: it.TotalPrice,
+		Currency:   it.Currency,
+		CreatedAt:  it.CreatedAt,
+		Legs:       NewBookingListResponse(it.Legs),
+	}
+}
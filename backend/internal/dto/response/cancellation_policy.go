@@ -0,0 +1,34 @@
+package response
+
+import (
+	"booking-app/internal/domain"
+	"time"
+)
+
+// CancellationPolicyResponse is the public representation of a cancellation policy.
+type CancellationPolicyResponse struct {
+	ID                       int       `json:"id"`
+	HotelID                  int       `json:"hotel_id"`
+	RoomID                   *int      `json:"room_id,omitempty"`
+	FreeCancellationDays     int       `json:"free_cancellation_days"`
+	PenaltyPercent           float64   `json:"penalty_percent"`
+	NonRefundable            bool      `json:"non_refundable"`
+	CaptureDaysBeforeCheckIn *int      `json:"capture_days_before_check_in,omitempty"`
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
+}
+
+// NewCancellationPolicyResponse converts a domain CancellationPolicy to a response.
+func NewCancellationPolicyResponse(p *domain.CancellationPolicy) CancellationPolicyResponse {
+	return CancellationPolicyResponse{
+		ID:                       p.ID,
+		HotelID:                  p.HotelID,
+		RoomID:                   p.RoomID,
+		FreeCancellationDays:     p.FreeCancellationDays,
+		PenaltyPercent:           p.PenaltyPercent,
+		NonRefundable:            p.NonRefundable,
+		CaptureDaysBeforeCheckIn: p.CaptureDaysBeforeCheckIn,
+		CreatedAt:                p.CreatedAt,
+		UpdatedAt:                p.UpdatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package response
+
+import (
+	"booking-app/internal/domain"
+	"time"
+)
+
+// ExchangeRateResponse is the public representation of an exchange rate.
+type ExchangeRateResponse struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewExchangeRateListResponse converts domain exchange rates to responses.
+func NewExchangeRateListResponse(rates []*domain.ExchangeRate) []ExchangeRateResponse {
+	out := make([]ExchangeRateResponse, 0, len(rates))
+	for _, r := range rates {
+		out = append(out, ExchangeRateResponse{
+			Base:      r.Base,
+			Quote:     r.Quote,
+			Rate:      r.Rate,
+			UpdatedAt: r.UpdatedAt,
+		})
+	}
+	return out
+}
@@ -21,6 +21,7 @@ type HotelResponse struct {
 	StarRating  int                `json:"star_rating"`
 	Status      domain.HotelStatus `json:"status"`
 	Description string             `json:"description"`
+	Currency    string             `json:"currency"`
 	CreatedAt   time.Time          `json:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at"`
 }
@@ -32,7 +33,10 @@ type RoomResponse struct {
 	Name          string    `json:"name"`
 	Description   string    `json:"description"`
 	Capacity      int       `json:"capacity"`
-	PricePerNight float64   `json:"price_per_night"`
+	MaxExtraBeds  int       `json:"max_extra_beds"`
+	ExtraGuestFee int64     `json:"extra_guest_fee"`
+	PricePerNight int64     `json:"price_per_night"`
+	Currency      string    `json:"currency"`
 	Amenities     []string  `json:"amenities"`
 	Images        []string  `json:"images"`
 	IsActive      bool      `json:"is_active"`
@@ -48,6 +52,9 @@ type InventoryResponse struct {
 	TotalInventory int       `json:"total_inventory"`
 	BookedCount    int       `json:"booked_count"`
 	Available      int       `json:"available"`
+	// Price and MinStay are set when the night overrides the room's rate plan.
+	Price   *int64 `json:"price,omitempty"`
+	MinStay *int   `json:"min_stay,omitempty"`
 }
 
 // OwnerDashboard holds aggregate statistics for an owner.
@@ -81,6 +88,7 @@ func NewHotelResponse(h *domain.Hotel) HotelResponse {
 		StarRating:  h.StarRating,
 		Status:      h.Status,
 		Description: h.Description,
+		Currency:    h.Currency,
 		CreatedAt:   h.CreatedAt,
 		UpdatedAt:   h.UpdatedAt,
 	}
@@ -102,7 +110,10 @@ func NewRoomResponse(r *domain.Room) RoomResponse {
 		Name:          r.Name,
 		Description:   r.Description,
 		Capacity:      r.Capacity,
+		MaxExtraBeds:  r.MaxExtraBeds,
+		ExtraGuestFee: r.ExtraGuestFee,
 		PricePerNight: r.PricePerNight,
+		Currency:      r.Currency,
 		Amenities:     amenities,
 		Images:        images,
 		IsActive:      r.IsActive,
@@ -124,6 +135,8 @@ func NewInventoryResponse(inv *domain.Inventory) InventoryResponse {
 		TotalInventory: inv.TotalInventory,
 		BookedCount:    inv.BookedCount,
 		Available:      available,
+		Price:          inv.Price,
+		MinStay:        inv.MinStay,
 	}
 }
 
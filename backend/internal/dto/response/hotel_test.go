@@ -79,7 +79,7 @@ func TestNewRoomResponse_MapsAllFields(t *testing.T) {
 		Name:          "Deluxe King",
 		Description:   "Spacious",
 		Capacity:      2,
-		PricePerNight: 150.0,
+		PricePerNight: 15000,
 		Amenities:     []string{"ac", "tv"},
 		Images:        []string{"room.jpg"},
 		IsActive:      true,
@@ -96,7 +96,7 @@ func TestNewRoomResponse_MapsAllFields(t *testing.T) {
 		t.Errorf("expected HotelID %d, got %d", r.HotelID, resp.HotelID)
 	}
 	if resp.PricePerNight != r.PricePerNight {
-		t.Errorf("expected PricePerNight %f, got %f", r.PricePerNight, resp.PricePerNight)
+		t.Errorf("expected PricePerNight %d, got %d", r.PricePerNight, resp.PricePerNight)
 	}
 	if !resp.IsActive {
 		t.Error("expected IsActive=true")
@@ -0,0 +1,46 @@
+package response
+
+import "booking-app/internal/domain"
+
+// OverbookingResponse is a room's overbooking allowance.
+type OverbookingResponse struct {
+	RoomID int    `json:"room_id"`
+	Type   string `json:"type"`
+	Value  int    `json:"value"`
+}
+
+// OverbookedNightResponse is a night a room is sold past its inventory.
+type OverbookedNightResponse struct {
+	RoomID         int    `json:"room_id"`
+	RoomName       string `json:"room_name"`
+	Date           string `json:"date"` // YYYY-MM-DD
+	TotalInventory int    `json:"total_inventory"`
+	BookedCount    int    `json:"booked_count"`
+	// Overbooked is how many units have to be relocated.
+	Overbooked int `json:"overbooked"`
+}
+
+// NewOverbookingResponse converts a room's overbooking allowance to an OverbookingResponse.
+func NewOverbookingResponse(r *domain.Room) OverbookingResponse {
+	return OverbookingResponse{
+		RoomID: r.ID,
+		Type:   string(r.Overbooking.Type),
+		Value:  r.Overbooking.Value,
+	}
+}
+
+// NewOverbookedNightListResponse converts overbooked nights to OverbookedNightResponses.
+func NewOverbookedNightListResponse(nights []*domain.OverbookedNight) []OverbookedNightResponse {
+	result := make([]OverbookedNightResponse, 0, len(nights))
+	for _, n := range nights {
+		result = append(result, OverbookedNightResponse{
+			RoomID:         n.RoomID,
+			RoomName:       n.RoomName,
+			Date:           n.Date.Format("2006-01-02"),
+			TotalInventory: n.TotalInventory,
+			BookedCount:    n.BookedCount,
+			Overbooked:     n.Overbooked(),
+		})
+	}
+	return result
+}
@@ -9,12 +9,15 @@ import (
 type PaymentResponse struct {
 	ID             string                `json:"id"`
 	BookingID      int                   `json:"booking_id"`
-	Amount         float64               `json:"amount"`
+	Amount         int64                 `json:"amount"`
 	Currency       string                `json:"currency"`
 	Status         domain.PaymentStatus  `json:"status"`
 	IdempotencyKey string                `json:"idempotency_key"`
+	Kind           domain.PaymentKind    `json:"kind"`
+	RefundedAmount int64                 `json:"refunded_amount"`
 	GatewayRef     string                `json:"gateway_ref,omitempty"`
 	FailedReason   string                `json:"failed_reason,omitempty"`
+	DeclineCode    string                `json:"decline_code,omitempty"`
 	CreatedAt      time.Time             `json:"created_at"`
 	UpdatedAt      time.Time             `json:"updated_at"`
 }
@@ -28,8 +31,11 @@ func NewPaymentResponse(p *domain.Payment) PaymentResponse {
 		Currency:       p.Currency,
 		Status:         p.Status,
 		IdempotencyKey: p.IdempotencyKey,
+		Kind:           p.Kind,
+		RefundedAmount: p.RefundedAmount,
 		GatewayRef:     p.GatewayRef,
 		FailedReason:   p.FailedReason,
+		DeclineCode:    p.DeclineCode,
 		CreatedAt:      p.CreatedAt,
 		UpdatedAt:      p.UpdatedAt,
 	}
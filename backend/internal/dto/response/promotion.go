@@ -0,0 +1,59 @@
+package response
+
+import (
+	"booking-app/internal/domain"
+	"time"
+)
+
+// PromotionResponse is the public representation of a promo code.
+type PromotionResponse struct {
+	ID             int        `json:"id"`
+	Code           string     `json:"code"`
+	Description    string     `json:"description"`
+	CreatedBy      string     `json:"created_by"`
+	HotelID        *int       `json:"hotel_id,omitempty"`
+	RoomID         *int       `json:"room_id,omitempty"`
+	Percent        *float64   `json:"percent,omitempty"`
+	Amount         *int64     `json:"amount,omitempty"`
+	Currency       string     `json:"currency,omitempty"`
+	ValidFrom      *time.Time `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time `json:"valid_until,omitempty"`
+	MinNights      int        `json:"min_nights"`
+	MaxRedemptions *int       `json:"max_redemptions,omitempty"`
+	MaxPerUser     *int       `json:"max_per_user,omitempty"`
+	Redemptions    int        `json:"redemptions"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NewPromotionResponse converts a domain Promotion to a PromotionResponse.
+func NewPromotionResponse(p *domain.Promotion) PromotionResponse {
+	return PromotionResponse{
+		ID:             p.ID,
+		Code:           p.Code,
+		Description:    p.Description,
+		CreatedBy:      p.CreatedBy,
+		HotelID:        p.HotelID,
+		RoomID:         p.RoomID,
+		Percent:        p.Percent,
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		ValidFrom:      p.ValidFrom,
+		ValidUntil:     p.ValidUntil,
+		MinNights:      p.MinNights,
+		MaxRedemptions: p.MaxRedemptions,
+		MaxPerUser:     p.MaxPerUser,
+		Redemptions:    p.Redemptions,
+		Active:         p.Active,
+		CreatedAt:      p.CreatedAt,
+	}
+}
+
+// NewPromotionListResponse converts promotions to PromotionResponses.
+func NewPromotionListResponse(promotions []*domain.Promotion) []PromotionResponse {
+	result := make([]PromotionResponse, 0, len(promotions))
+	for _, p := range promotions {
+		result = append(result, NewPromotionResponse(p))
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package response
+
+import (
+	"booking-app/internal/domain"
+	"time"
+)
+
+// QuoteResponse is the public representation of a price quote. Amounts are
+// in minor units of Currency, the hotel's, except ChargeTotal.
+type QuoteResponse struct {
+	RoomID   int                   `json:"room_id"`
+	HotelID  int                   `json:"hotel_id"`
+	CheckIn  string                `json:"check_in"`  // YYYY-MM-DD
+	CheckOut string                `json:"check_out"` // YYYY-MM-DD
+	Adults   int                   `json:"adults"`
+	Children int                   `json:"children"`
+	Quantity int                   `json:"quantity"`
+	Currency string                `json:"currency"`
+	Nights   []NightlyRateResponse `json:"nights"`
+	// ExtraGuestCharge is what the ExtraGuests in extra beds pay, included
+	// in Subtotal.
+	ExtraGuests      int                 `json:"extra_guests"`
+	ExtraGuestCharge int64               `json:"extra_guest_charge"`
+	Subtotal         int64               `json:"subtotal"`
+	Lines            []PriceLineResponse `json:"lines"`
+	Discounts        int64               `json:"discounts"`
+	PromoCode        string              `json:"promo_code,omitempty"`
+	PromoDiscount    int64               `json:"promo_discount,omitempty"`
+	Fees             int64               `json:"fees"`
+	Taxes            int64               `json:"taxes"`
+	Total            int64               `json:"total"`
+	ChargeTotal      int64               `json:"charge_total"`
+	ChargeCurrency   string              `json:"charge_currency"`
+	FxRate           float64             `json:"fx_rate"`
+	// QuoteToken books the stay at this price via POST /bookings until ExpiresAt.
+	QuoteToken string     `json:"quote_token,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// PriceLineResponse is one discount, fee or tax; discounts are negative.
+type PriceLineResponse struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Amount int64  `json:"amount"`
+}
+
+// HotelChargeResponse is the public representation of a hotel charge.
+type HotelChargeResponse struct {
+	ID        int      `json:"id"`
+	HotelID   int      `json:"hotel_id"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Percent   *float64 `json:"percent,omitempty"`
+	Amount    *int64   `json:"amount,omitempty"`
+	PerNight  bool     `json:"per_night"`
+	MinNights int      `json:"min_nights"`
+}
+
+// NewQuoteResponse converts a domain Quote and its token to a QuoteResponse.
+func NewQuoteResponse(q *domain.Quote, token string) QuoteResponse {
+	resp := QuoteResponse{
+		RoomID:           q.RoomID,
+		HotelID:          q.HotelID,
+		CheckIn:          q.CheckIn.Format("2006-01-02"),
+		CheckOut:         q.CheckOut.Format("2006-01-02"),
+		Adults:           q.Adults,
+		Children:         q.Children,
+		Quantity:         q.Quantity,
+		Currency:         q.Currency,
+		Nights:           NewNightlyRateListResponse(q.Nights),
+		ExtraGuests:      q.ExtraGuests,
+		ExtraGuestCharge: q.ExtraGuestCharge,
+		Subtotal:         q.Subtotal,
+		Lines:            NewPriceLineListResponse(q.Lines),
+		Discounts:        q.Discounts,
+		PromoCode:        q.PromoCode,
+		PromoDiscount:    q.PromoDiscount,
+		Fees:             q.Fees,
+		Taxes:            q.Taxes,
+		Total:            q.Total,
+		ChargeTotal:      q.ChargeTotal,
+		ChargeCurrency:   q.ChargeCurrency,
+		FxRate:           q.FxRate,
+		QuoteToken:       token,
+	}
+	if token != "" {
+		expiresAt := q.ExpiresAt
+		resp.ExpiresAt = &expiresAt
+	}
+	return resp
+}
+
+// NewPriceLineListResponse converts price lines to PriceLineResponses.
+func NewPriceLineListResponse(lines []domain.PriceLine) []PriceLineResponse {
+	result := make([]PriceLineResponse, 0, len(lines))
+	for _, l := range lines {
+		result = append(result, PriceLineResponse{Type: string(l.Type), Name: l.Name, Amount: l.Amount})
+	}
+	return result
+}
+
+// NewHotelChargeListResponse converts hotel charges to HotelChargeResponses.
+func NewHotelChargeListResponse(charges []*domain.HotelCharge) []HotelChargeResponse {
+	result := make([]HotelChargeResponse, 0, len(charges))
+	for _, c := range charges {
+		result = append(result, HotelChargeResponse{
+			ID:        c.ID,
+			HotelID:   c.HotelID,
+			Name:      c.Name,
+			Type:      string(c.Type),
+			Percent:   c.Percent,
+			Amount:    c.Amount,
+			PerNight:  c.PerNight,
+			MinNights: c.MinNights,
+		})
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package response
+
+import "booking-app/internal/domain"
+
+// RatePlanResponse is the public representation of a room's rate plan.
+// Prices are in minor units of Currency, the hotel's.
+type RatePlanResponse struct {
+	RoomID       int                    `json:"room_id"`
+	BasePrice    int64                  `json:"base_price"`
+	Currency     string                 `json:"currency"`
+	WeekendPrice *int64                 `json:"weekend_price,omitempty"`
+	MinStay      int                    `json:"min_stay"`
+	Seasons      []SeasonalRateResponse `json:"seasons"`
+	Overrides    []DateRateResponse     `json:"overrides"`
+}
+
+// SeasonalRateResponse prices the nights from StartDate up to, but not
+// including, EndDate.
+type SeasonalRateResponse struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	StartDate    string `json:"start_date"` // YYYY-MM-DD
+	EndDate      string `json:"end_date"`   // YYYY-MM-DD
+	Price        int64  `json:"price"`
+	WeekendPrice *int64 `json:"weekend_price,omitempty"`
+	MinStay      *int   `json:"min_stay,omitempty"`
+}
+
+// DateRateResponse overrides a single night.
+type DateRateResponse struct {
+	Date    string `json:"date"` // YYYY-MM-DD
+	Price   *int64 `json:"price,omitempty"`
+	MinStay *int   `json:"min_stay,omitempty"`
+}
+
+// NewRatePlanResponse converts a domain RatePlan to a RatePlanResponse.
+func NewRatePlanResponse(p *domain.RatePlan) RatePlanResponse {
+	seasons := make([]SeasonalRateResponse, 0, len(p.Seasons))
+	for _, s := range p.Seasons {
+		seasons = append(seasons, SeasonalRateResponse{
+			ID:           s.ID,
+			Name:         s.Name,
+			StartDate:    s.StartDate.Format("2006-01-02"),
+			EndDate:      s.EndDate.Format("2006-01-02"),
+			Price:        s.Price,
+			WeekendPrice: s.WeekendPrice,
+			MinStay:      s.MinStay,
+		})
+	}
+	overrides := make([]DateRateResponse, 0, len(p.Overrides))
+	for _, o := range p.Overrides {
+		overrides = append(overrides, DateRateResponse{
+			Date:    o.Date.Format("2006-01-02"),
+			Price:   o.Price,
+			MinStay: o.MinStay,
+		})
+	}
+	return RatePlanResponse{
+		RoomID:       p.RoomID,
+		BasePrice:    p.BasePrice,
+		Currency:     p.Currency,
+		WeekendPrice: p.WeekendPrice,
+		MinStay:      p.MinStay,
+		Seasons:      seasons,
+		Overrides:    overrides,
+	}
+}
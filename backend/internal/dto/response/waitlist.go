@@ -0,0 +1,47 @@
+package response
+
+import (
+	"booking-app/internal/domain"
+	"time"
+)
+
+// WaitlistEntryResponse is the public representation of a waitlist entry.
+type WaitlistEntryResponse struct {
+	ID             int        `json:"id"`
+	RoomID         int        `json:"room_id"`
+	StartDate      time.Time  `json:"start_date"`
+	EndDate        time.Time  `json:"end_date"`
+	Quantity       int        `json:"quantity"`
+	Adults         int        `json:"adults"`
+	Children       int        `json:"children"`
+	Status         string     `json:"status"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	BookingID      *int       `json:"booking_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NewWaitlistEntryResponse converts a domain WaitlistEntry to a WaitlistEntryResponse.
+func NewWaitlistEntryResponse(e *domain.WaitlistEntry) WaitlistEntryResponse {
+	return WaitlistEntryResponse{
+		ID:             e.ID,
+		RoomID:         e.RoomID,
+		StartDate:      e.StartDate,
+		EndDate:        e.EndDate,
+		Quantity:       e.Quantity,
+		Adults:         e.Adults,
+		Children:       e.Children,
+		Status:         e.Status,
+		OfferExpiresAt: e.OfferExpiresAt,
+		BookingID:      e.BookingID,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+// NewWaitlistEntryListResponse converts entries to WaitlistEntryResponses.
+func NewWaitlistEntryListResponse(entries []*domain.WaitlistEntry) []WaitlistEntryResponse {
+	result := make([]WaitlistEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, NewWaitlistEntryResponse(e))
+	}
+	return result
+}
@@ -117,7 +117,7 @@ func sampleBooking() *domain.Booking {
 		ID:         1,
 		UserID:     "user-abc",
 		RoomID:     10,
-		TotalPrice: 150.0,
+		TotalPrice: 150,
 		Status:     "confirmed",
 		StartDate:  time.Now(),
 		EndDate:    time.Now().Add(48 * time.Hour),
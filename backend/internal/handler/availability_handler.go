@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/dto/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AvailabilityServiceInterface defines what the availability handler needs from the service.
+type AvailabilityServiceInterface interface {
+	GetHotelAvailability(ctx context.Context, hotelID int, from, to time.Time) (*domain.HotelAvailability, error)
+}
+
+// AvailabilityHandler handles HTTP requests for hotel availability calendars.
+type AvailabilityHandler struct {
+	svc AvailabilityServiceInterface
+}
+
+// NewAvailabilityHandler creates a new AvailabilityHandler.
+func NewAvailabilityHandler(svc AvailabilityServiceInterface) *AvailabilityHandler {
+	return &AvailabilityHandler{svc: svc}
+}
+
+// GetHotelAvailability handles GET /api/v1/hotels/:id/availability?from&to.
+// It returns the units left of every room type on each night in [from, to),
+// with the night's prices and the lowest price per room type.
+func (h *AvailabilityHandler) GetHotelAvailability(c *gin.Context) {
+	hotelID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid hotel id"))
+		return
+	}
+
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	if fromStr == "" || toStr == "" {
+		c.JSON(http.StatusBadRequest, response.Fail("from and to query params are required"))
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid from format, use YYYY-MM-DD"))
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid to format, use YYYY-MM-DD"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	avail, err := h.svc.GetHotelAvailability(ctx, hotelID, from, to)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewHotelAvailabilityResponse(avail)))
+}
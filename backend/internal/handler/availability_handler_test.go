@@ -0,0 +1,91 @@
+package handler_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/handler"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Mock AvailabilityService ---
+
+type mockAvailabilitySvc struct {
+	getHotelAvailabilityFn func(ctx context.Context, hotelID int, from, to time.Time) (*domain.HotelAvailability, error)
+}
+
+func (m *mockAvailabilitySvc) GetHotelAvailability(ctx context.Context, hotelID int, from, to time.Time) (*domain.HotelAvailability, error) {
+	if m.getHotelAvailabilityFn != nil {
+		return m.getHotelAvailabilityFn(ctx, hotelID, from, to)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func buildAvailabilityRouter(svc *mockAvailabilitySvc) *gin.Engine {
+	r := gin.New()
+	h := handler.NewAvailabilityHandler(svc)
+	r.GET("/api/v1/hotels/:id/availability", h.GetHotelAvailability)
+	return r
+}
+
+// --- Tests ---
+
+func TestAvailabilityHandler_GetHotelAvailability_Returns200(t *testing.T) {
+	var gotHotel int
+	var gotFrom, gotTo time.Time
+	price := int64(12000)
+	svc := &mockAvailabilitySvc{
+		getHotelAvailabilityFn: func(_ context.Context, hotelID int, from, to time.Time) (*domain.HotelAvailability, error) {
+			gotHotel, gotFrom, gotTo = hotelID, from, to
+			return &domain.HotelAvailability{
+				HotelID: hotelID, From: from, To: to, Currency: "EUR",
+				Rooms: []*domain.RoomTypeAvailability{{RoomID: 5, Name: "Double", LowestPrice: &price}},
+				Days: []*domain.DayAvailability{{Date: from, Available: 2, LowestPrice: &price,
+					Rooms: []*domain.RoomNightAvailability{{RoomID: 5, Available: 2, Price: price}}}},
+			}, nil
+		},
+	}
+	r := buildAvailabilityRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/hotels/7/availability?from=2026-07-01&to=2026-07-02", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotHotel != 7 || !gotFrom.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) || !gotTo.Equal(time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected arguments passed to service: %d %v %v", gotHotel, gotFrom, gotTo)
+	}
+	if !strings.Contains(w.Body.String(), `"date":"2026-07-01","available":2,"lowest_price":12000`) {
+		t.Errorf("expected the night's availability in body, got %s", w.Body.String())
+	}
+}
+
+func TestAvailabilityHandler_GetHotelAvailability_MissingRange_Returns400(t *testing.T) {
+	r := buildAvailabilityRouter(&mockAvailabilitySvc{})
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/hotels/7/availability?from=2026-07-01", nil)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestAvailabilityHandler_GetHotelAvailability_UnknownHotel_Returns404(t *testing.T) {
+	svc := &mockAvailabilitySvc{
+		getHotelAvailabilityFn: func(context.Context, int, time.Time, time.Time) (*domain.HotelAvailability, error) {
+			return nil, fmt.Errorf("hotel not found: %w", domain.ErrNotFound)
+		},
+	}
+	r := buildAvailabilityRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/hotels/7/availability?from=2026-07-01&to=2026-07-08", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
@@ -19,8 +19,12 @@ type BookingServiceInterface interface {
 	GetBooking(ctx context.Context, id int, callerUserID string) (*domain.Booking, error)
 	ListMyBookings(ctx context.Context, userID string, page, limit int) ([]*domain.Booking, int, error)
 	CancelBooking(ctx context.Context, id int, userID string) error
+	ModifyBooking(ctx context.Context, input domain.ModifyBookingInput) (*domain.Booking, error)
 	GetBookingStatus(ctx context.Context, id int, callerUserID string) (string, error)
 	InitializeInventory(ctx context.Context, roomID int, startDate time.Time, days int, total int) error
+	CreateItinerary(ctx context.Context, input domain.CreateItineraryInput) (*domain.Itinerary, error)
+	GetItinerary(ctx context.Context, id int, callerUserID string) (*domain.Itinerary, error)
+	QuoteStay(ctx context.Context, input domain.QuoteInput) (*domain.Quote, string, error)
 }
 
 // BookingHandler handles HTTP requests for bookings.
@@ -57,10 +61,16 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 	defer cancel()
 
 	booking, err := h.svc.CreateBooking(ctx, domain.CreateBookingInput{
-		UserID:    userID,
-		RoomID:    req.RoomID,
-		StartDate: startDate,
-		EndDate:   endDate,
+		UserID:     userID,
+		RoomID:     req.RoomID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Quantity:   req.Quantity,
+		Adults:     req.Adults,
+		Children:   req.Children,
+		Currency:   req.Currency,
+		QuoteToken: req.QuoteToken,
+		PromoCode:  req.PromoCode,
 	})
 	if err != nil {
 		handleBookingError(c, err)
@@ -70,6 +80,58 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 	c.JSON(http.StatusCreated, response.OK(response.NewBookingResponse(booking)))
 }
 
+// QuoteStay handles GET /api/v1/rooms/:id/quote?check_in&check_out.
+// Optional adults (default 1), children, quantity, currency and promo_code
+// query params price the guests and several units, convert the total and
+// apply a promo code. The response carries a
+// quote token for POST /bookings.
+func (h *BookingHandler) QuoteStay(c *gin.Context) {
+	roomID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid room id"))
+		return
+	}
+
+	checkInStr := c.Query("check_in")
+	checkOutStr := c.Query("check_out")
+	if checkInStr == "" || checkOutStr == "" {
+		c.JSON(http.StatusBadRequest, response.Fail("check_in and check_out query params are required"))
+		return
+	}
+
+	checkIn, err := time.Parse("2006-01-02", checkInStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid check_in format, use YYYY-MM-DD"))
+		return
+	}
+
+	checkOut, err := time.Parse("2006-01-02", checkOutStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid check_out format, use YYYY-MM-DD"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	quote, token, err := h.svc.QuoteStay(ctx, domain.QuoteInput{
+		RoomID:    roomID,
+		CheckIn:   checkIn,
+		CheckOut:  checkOut,
+		Adults:    queryIntDefault(c, "adults", 1),
+		Children:  queryIntDefault(c, "children", 0),
+		Quantity:  queryIntDefault(c, "quantity", 1),
+		Currency:  c.Query("currency"),
+		PromoCode: c.Query("promo_code"),
+	})
+	if err != nil {
+		handleBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewQuoteResponse(quote, token)))
+}
+
 // CreateBookingLegacy handles POST /api/bookings.
 // This legacy endpoint accepts user_id in the request body for backward compatibility
 // with existing k6 load tests that do not send JWT tokens.
@@ -93,6 +155,7 @@ func (h *BookingHandler) CreateBookingLegacy(c *gin.Context) {
 		RoomID:    req.RoomID,
 		StartDate: startDate,
 		EndDate:   endDate,
+		Quantity:  req.Quantity,
 	})
 	if err != nil {
 		handleBookingError(c, err)
@@ -210,6 +273,129 @@ func (h *BookingHandler) CancelBooking(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// ModifyBooking handles PATCH /api/v1/bookings/:id.
+// Moves a pending or confirmed booking to new dates and/or another room in the
+// same hotel. Only the booking owner can modify a booking.
+func (h *BookingHandler) ModifyBooking(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, response.Fail("authentication required"))
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid booking id"))
+		return
+	}
+
+	var req request.ModifyBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	input := domain.ModifyBookingInput{BookingID: id, UserID: userID, RoomID: req.RoomID}
+	if req.StartDate != "" {
+		if input.StartDate, err = time.Parse("2006-01-02", req.StartDate); err != nil {
+			c.JSON(http.StatusBadRequest, response.Fail("invalid start_date format, use YYYY-MM-DD"))
+			return
+		}
+	}
+	if req.EndDate != "" {
+		if input.EndDate, err = time.Parse("2006-01-02", req.EndDate); err != nil {
+			c.JSON(http.StatusBadRequest, response.Fail("invalid end_date format, use YYYY-MM-DD"))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	booking, err := h.svc.ModifyBooking(ctx, input)
+	if err != nil {
+		handleBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewBookingResponse(booking)))
+}
+
+// CreateItinerary handles POST /api/v1/itineraries.
+// All legs are reserved together; if any leg is unavailable none are booked.
+// The itinerary is paid for by checking out any one of its legs.
+func (h *BookingHandler) CreateItinerary(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, response.Fail("authentication required"))
+		return
+	}
+
+	var req request.CreateItineraryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	legs := make([]domain.ItineraryLegInput, 0, len(req.Legs))
+	for _, leg := range req.Legs {
+		startDate, endDate, ok := parseDateRange(c, leg.StartDate, leg.EndDate)
+		if !ok {
+			return
+		}
+		legs = append(legs, domain.ItineraryLegInput{
+			RoomID:    leg.RoomID,
+			StartDate: startDate,
+			EndDate:   endDate,
+			Quantity:  leg.Quantity,
+			Adults:    leg.Adults,
+			Children:  leg.Children,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	itinerary, err := h.svc.CreateItinerary(ctx, domain.CreateItineraryInput{
+		UserID:   userID,
+		Legs:     legs,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		handleBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.OK(response.NewItineraryResponse(itinerary)))
+}
+
+// GetItinerary handles GET /api/v1/itineraries/:id.
+// Only the itinerary owner can retrieve it.
+func (h *BookingHandler) GetItinerary(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, response.Fail("authentication required"))
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid itinerary id"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	itinerary, err := h.svc.GetItinerary(ctx, id, userID)
+	if err != nil {
+		handleBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewItineraryResponse(itinerary)))
+}
+
 // InitializeInventory handles POST /api/v1/admin/init.
 func (h *BookingHandler) InitializeInventory(c *gin.Context) {
 	ctx := context.Background()
@@ -25,6 +25,10 @@ type mockBookingSvc struct {
 	cancelBookingFn   func(ctx context.Context, id int, userID string) error
 	getStatusFn       func(ctx context.Context, id int, callerUserID string) (string, error)
 	initInventoryFn   func(ctx context.Context, roomID int, startDate time.Time, days int, total int) error
+	modifyBookingFn   func(ctx context.Context, input domain.ModifyBookingInput) (*domain.Booking, error)
+	createItineraryFn func(ctx context.Context, input domain.CreateItineraryInput) (*domain.Itinerary, error)
+	getItineraryFn    func(ctx context.Context, id int, callerUserID string) (*domain.Itinerary, error)
+	quoteStayFn       func(ctx context.Context, input domain.QuoteInput) (*domain.Quote, string, error)
 }
 
 func (m *mockBookingSvc) CreateBooking(ctx context.Context, input domain.CreateBookingInput) (*domain.Booking, error) {
@@ -69,6 +73,34 @@ func (m *mockBookingSvc) InitializeInventory(ctx context.Context, roomID int, st
 	return nil
 }
 
+func (m *mockBookingSvc) ModifyBooking(ctx context.Context, input domain.ModifyBookingInput) (*domain.Booking, error) {
+	if m.modifyBookingFn != nil {
+		return m.modifyBookingFn(ctx, input)
+	}
+	return nil, errors.New("not configured")
+}
+
+func (m *mockBookingSvc) CreateItinerary(ctx context.Context, input domain.CreateItineraryInput) (*domain.Itinerary, error) {
+	if m.createItineraryFn != nil {
+		return m.createItineraryFn(ctx, input)
+	}
+	return nil, errors.New("not configured")
+}
+
+func (m *mockBookingSvc) GetItinerary(ctx context.Context, id int, callerUserID string) (*domain.Itinerary, error) {
+	if m.getItineraryFn != nil {
+		return m.getItineraryFn(ctx, id, callerUserID)
+	}
+	return nil, errors.New("not configured")
+}
+
+func (m *mockBookingSvc) QuoteStay(ctx context.Context, input domain.QuoteInput) (*domain.Quote, string, error) {
+	if m.quoteStayFn != nil {
+		return m.quoteStayFn(ctx, input)
+	}
+	return nil, "", errors.New("not configured")
+}
+
 // buildBookingRouterWithAuth builds a test router that injects userID into context.
 func buildBookingRouterWithAuth(svc handler.BookingServiceInterface, userID string) *gin.Engine {
 	r := gin.New()
@@ -86,7 +118,11 @@ func buildBookingRouterWithAuth(svc handler.BookingServiceInterface, userID stri
 	v1.GET("/bookings", h.ListMyBookings)
 	v1.GET("/bookings/:id", h.GetBooking)
 	v1.GET("/bookings/:id/status", h.GetBookingStatus)
+	v1.PATCH("/bookings/:id", h.ModifyBooking)
 	v1.DELETE("/bookings/:id", h.CancelBooking)
+	v1.POST("/itineraries", h.CreateItinerary)
+	v1.GET("/itineraries/:id", h.GetItinerary)
+	v1.GET("/rooms/:id/quote", h.QuoteStay)
 
 	// Legacy route (no auth)
 	api := r.Group("/api")
@@ -137,7 +173,7 @@ func TestBookingHandler_CreateBooking_Success(t *testing.T) {
 				RoomID:     input.RoomID,
 				StartDate:  input.StartDate,
 				EndDate:    input.EndDate,
-				TotalPrice: 300.0,
+				TotalPrice: 300,
 				Status:     "confirmed",
 				CreatedAt:  time.Now(),
 			}, nil
@@ -508,6 +544,136 @@ func TestBookingHandler_CancelBooking_NoAuth_Returns401(t *testing.T) {
 	}
 }
 
+// ---- PATCH /api/v1/bookings/:id ----
+
+func TestBookingHandler_ModifyBooking_Success(t *testing.T) {
+	var captured domain.ModifyBookingInput
+	svc := &mockBookingSvc{
+		modifyBookingFn: func(_ context.Context, input domain.ModifyBookingInput) (*domain.Booking, error) {
+			captured = input
+			return &domain.Booking{ID: input.BookingID, UserID: input.UserID, RoomID: 2, TotalPrice: 360}, nil
+		},
+	}
+	r := buildBookingRouterWithAuth(svc, "user-jwt-1")
+	body := strings.NewReader(`{"room_id":2,"end_date":"2026-03-05"}`)
+	w := makeBookingRequest(r, http.MethodPatch, "/api/v1/bookings/5", body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if captured.BookingID != 5 || captured.UserID != "user-jwt-1" || captured.RoomID != 2 {
+		t.Errorf("unexpected input %+v", captured)
+	}
+	if !captured.StartDate.IsZero() {
+		t.Errorf("expected omitted start_date to stay zero, got %v", captured.StartDate)
+	}
+	if captured.EndDate.Format("2006-01-02") != "2026-03-05" {
+		t.Errorf("expected end_date 2026-03-05, got %v", captured.EndDate)
+	}
+}
+
+func TestBookingHandler_ModifyBooking_InvalidDate_Returns400(t *testing.T) {
+	r := buildBookingRouterWithAuth(&mockBookingSvc{}, "user-jwt-1")
+	w := makeBookingRequest(r, http.MethodPatch, "/api/v1/bookings/5", strings.NewReader(`{"start_date":"03/01/2026"}`))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestBookingHandler_ModifyBooking_NotAvailable_Returns409(t *testing.T) {
+	svc := &mockBookingSvc{
+		modifyBookingFn: func(_ context.Context, _ domain.ModifyBookingInput) (*domain.Booking, error) {
+			return nil, domain.ErrNotAvailable
+		},
+	}
+	r := buildBookingRouterWithAuth(svc, "user-jwt-1")
+	w := makeBookingRequest(r, http.MethodPatch, "/api/v1/bookings/5", strings.NewReader(`{"end_date":"2026-03-09"}`))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d", w.Code)
+	}
+}
+
+// ---- POST /api/v1/itineraries ----
+
+func TestBookingHandler_CreateItinerary_Success(t *testing.T) {
+	var captured domain.CreateItineraryInput
+	svc := &mockBookingSvc{
+		createItineraryFn: func(_ context.Context, input domain.CreateItineraryInput) (*domain.Itinerary, error) {
+			captured = input
+			itinerary := &domain.Itinerary{ID: 5, UserID: input.UserID, TotalPrice: 500, CreatedAt: time.Now()}
+			for i, leg := range input.Legs {
+				itinerary.Legs = append(itinerary.Legs, &domain.Booking{
+					ID: 10 + i, UserID: input.UserID, RoomID: leg.RoomID,
+					StartDate: leg.StartDate, EndDate: leg.EndDate, Status: domain.BookingStatusPending,
+					ItineraryID: &itinerary.ID,
+				})
+			}
+			return itinerary, nil
+		},
+	}
+
+	r := buildBookingRouterWithAuth(svc, "user-jwt-1")
+	body := strings.NewReader(`{"legs":[` +
+		`{"room_id":1,"start_date":"2026-03-01","end_date":"2026-03-03"},` +
+		`{"room_id":7,"start_date":"2026-03-03","end_date":"2026-03-05","quantity":2}]}`)
+	w := makeBookingRequest(r, http.MethodPost, "/api/v1/itineraries", body)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if captured.UserID != "user-jwt-1" {
+		t.Errorf("expected userID from JWT, got %q", captured.UserID)
+	}
+	if len(captured.Legs) != 2 || captured.Legs[1].RoomID != 7 || captured.Legs[1].Quantity != 2 {
+		t.Errorf("unexpected legs: %+v", captured.Legs)
+	}
+	if !strings.Contains(w.Body.String(), `"itinerary_id":5`) {
+		t.Errorf("expected legs to carry itinerary_id, got %s", w.Body.String())
+	}
+}
+
+func TestBookingHandler_CreateItinerary_NoLegs_Returns400(t *testing.T) {
+	r := buildBookingRouterWithAuth(&mockBookingSvc{}, "user-jwt-1")
+	w := makeBookingRequest(r, http.MethodPost, "/api/v1/itineraries", strings.NewReader(`{"legs":[]}`))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestBookingHandler_CreateItinerary_LegUnavailable_Returns409(t *testing.T) {
+	svc := &mockBookingSvc{
+		createItineraryFn: func(_ context.Context, _ domain.CreateItineraryInput) (*domain.Itinerary, error) {
+			return nil, domain.ErrNotAvailable
+		},
+	}
+	r := buildBookingRouterWithAuth(svc, "user-jwt-1")
+	body := strings.NewReader(`{"legs":[{"room_id":1,"start_date":"2026-03-01","end_date":"2026-03-03"}]}`)
+	w := makeBookingRequest(r, http.MethodPost, "/api/v1/itineraries", body)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d", w.Code)
+	}
+}
+
+// ---- GET /api/v1/itineraries/:id ----
+
+func TestBookingHandler_GetItinerary_Forbidden_Returns403(t *testing.T) {
+	svc := &mockBookingSvc{
+		getItineraryFn: func(_ context.Context, _ int, _ string) (*domain.Itinerary, error) {
+			return nil, domain.ErrForbidden
+		},
+	}
+	r := buildBookingRouterWithAuth(svc, "user-jwt-1")
+	w := makeBookingRequest(r, http.MethodGet, "/api/v1/itineraries/5", nil)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
 // ---- Legacy POST /api/bookings ----
 
 func TestBookingHandler_LegacyCreateBooking_Success(t *testing.T) {
@@ -517,7 +683,7 @@ func TestBookingHandler_LegacyCreateBooking_Success(t *testing.T) {
 				ID:         99,
 				UserID:     input.UserID,
 				RoomID:     input.RoomID,
-				TotalPrice: 200.0,
+				TotalPrice: 200,
 				Status:     "confirmed",
 				CreatedAt:  time.Now(),
 			}, nil
@@ -542,3 +708,63 @@ func TestBookingHandler_LegacyCreateBooking_MissingUserID_Returns400(t *testing.
 		t.Errorf("expected 400, got %d", w.Code)
 	}
 }
+
+// ---- GET /api/v1/rooms/:id/quote ----
+
+func TestBookingHandler_QuoteStay_Returns200WithToken(t *testing.T) {
+	var got domain.QuoteInput
+	svc := &mockBookingSvc{
+		quoteStayFn: func(_ context.Context, input domain.QuoteInput) (*domain.Quote, string, error) {
+			got = input
+			return &domain.Quote{
+				RoomID:   input.RoomID,
+				CheckIn:  input.CheckIn,
+				CheckOut: input.CheckOut,
+				Currency: "USD",
+				Subtotal: 20000,
+				Lines:    []domain.PriceLine{{Type: domain.ChargeTypeTax, Name: "VAT", Amount: 2000}},
+				Taxes:    2000,
+				Total:    22000,
+			}, "signed-quote", nil
+		},
+	}
+	r := buildBookingRouterWithAuth(svc, "user-jwt-1")
+
+	w := makeBookingRequest(r, http.MethodGet, "/api/v1/rooms/3/quote?check_in=2030-06-01&check_out=2030-06-03&adults=2&children=1&currency=EUR", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.RoomID != 3 || got.Adults != 2 || got.Children != 1 || got.Quantity != 1 || got.Currency != "EUR" {
+		t.Errorf("unexpected input passed to service: %+v", got)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `"quote_token":"signed-quote"`) || !strings.Contains(body, `"name":"VAT"`) {
+		t.Errorf("expected token and tax line in body, got %s", body)
+	}
+}
+
+func TestBookingHandler_QuoteStay_MissingDates_Returns400(t *testing.T) {
+	r := buildBookingRouterWithAuth(&mockBookingSvc{}, "user-jwt-1")
+
+	w := makeBookingRequest(r, http.MethodGet, "/api/v1/rooms/3/quote?check_in=2030-06-01", nil)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestBookingHandler_QuoteStay_RoomNotFound_Returns404(t *testing.T) {
+	svc := &mockBookingSvc{
+		quoteStayFn: func(_ context.Context, _ domain.QuoteInput) (*domain.Quote, string, error) {
+			return nil, "", fmt.Errorf("room 3: %w", domain.ErrNotFound)
+		},
+	}
+	r := buildBookingRouterWithAuth(svc, "user-jwt-1")
+
+	w := makeBookingRequest(r, http.MethodGet, "/api/v1/rooms/3/quote?check_in=2030-06-01&check_out=2030-06-03", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
@@ -20,7 +20,7 @@ type BookingBroadcastRepo interface {
 // booking status updates to connected WebSocket clients via the Hub.
 //
 // It consumes from the booking.notifications queue (payment.succeeded / payment.failed
-// / payment.timed_out routing keys) and pushes a WSMessage to the booking owner.
+// / payment.timed_out / booking.expired routing keys) and pushes a WSMessage to the booking owner.
 func NewPaymentBroadcastHandler(hub *Hub, payRepo repository.PaymentRepository, bookingRepo BookingBroadcastRepo, logger *zap.Logger) rabbitmq.DeliveryHandler {
 	return func(ctx context.Context, delivery amqp.Delivery) bool {
 		var payload domain.PaymentResultPayload
@@ -42,8 +42,14 @@ func NewPaymentBroadcastHandler(hub *Hub, payRepo repository.PaymentRepository,
 			return false
 		}
 
-		// Map routing key to booking status.
+		// Map routing key to booking status. Top-ups and refunds from booking
+		// modifications never change the booking, so report its current status.
 		bookingStatus := routingKeyToBookingStatus(delivery.RoutingKey)
+		if payload.PaymentID != "" {
+			if payment, payErr := payRepo.GetPaymentByID(ctx, payload.PaymentID); payErr == nil && payment.IsAdjustment() {
+				bookingStatus = booking.Status
+			}
+		}
 
 		msg := WSMessage{
 			Type: "booking_status_updated",
@@ -78,6 +84,8 @@ func routingKeyToBookingStatus(routingKey string) string {
 		return domain.BookingStatusFailed
 	case "payment.timed_out":
 		return domain.BookingStatusCancelled
+	case "booking.expired":
+		return domain.BookingStatusExpired
 	default:
 		return "unknown"
 	}
@@ -31,12 +31,39 @@ func (m *mockBroadcastPayRepo) GetPaymentByID(ctx context.Context, id string) (*
 func (m *mockBroadcastPayRepo) GetPaymentByBookingID(ctx context.Context, bookingID int) (*domain.Payment, error) {
 	return nil, nil
 }
+func (m *mockBroadcastPayRepo) ListTopUps(ctx context.Context, bookingID int) ([]*domain.Payment, error) {
+	return nil, nil
+}
 func (m *mockBroadcastPayRepo) UpdatePaymentStatus(ctx context.Context, id string, status domain.PaymentStatus, ref, reason string) error {
 	return nil
 }
 func (m *mockBroadcastPayRepo) GetPaymentByIdempotencyKey(ctx context.Context, key string) (*domain.Payment, error) {
 	return nil, domain.ErrNotFound
 }
+func (m *mockBroadcastPayRepo) DeclinePayment(ctx context.Context, id, declineCode, reason string) error {
+	return nil
+}
+func (m *mockBroadcastPayRepo) MarkAuthorized(ctx context.Context, id, authorizationRef string) error {
+	return nil
+}
+func (m *mockBroadcastPayRepo) GetPaymentForUpdate(ctx context.Context, id string) (*domain.Payment, error) {
+	return m.GetPaymentByID(ctx, id)
+}
+func (m *mockBroadcastPayRepo) CapturePayment(ctx context.Context, id, captureRef string, amount int64) error {
+	return nil
+}
+func (m *mockBroadcastPayRepo) VoidPayment(ctx context.Context, id string) error {
+	return nil
+}
+func (m *mockBroadcastPayRepo) ReduceAuthorization(ctx context.Context, id string, amount int64) (int64, error) {
+	return 0, nil
+}
+func (m *mockBroadcastPayRepo) ReduceAuthorizedAmount(ctx context.Context, id string, amount int64) (int64, error) {
+	return 0, nil
+}
+func (m *mockBroadcastPayRepo) ListDueCaptures(ctx context.Context, before time.Time, limit int) ([]*domain.Payment, error) {
+	return nil, nil
+}
 
 // --- Mock BookingBroadcastRepo ---
 
@@ -118,6 +145,21 @@ func TestPaymentBroadcastHandler_TimedOutRoutingKey_BroadcastsCancelled(t *testi
 	}
 }
 
+func TestPaymentBroadcastHandler_BookingExpiredRoutingKey_Acks(t *testing.T) {
+	hub := handler.NewHub()
+	payRepo := &mockBroadcastPayRepo{}
+	bookingRepo := &mockBroadcastBookingRepo{}
+
+	h := handler.NewPaymentBroadcastHandler(hub, payRepo, bookingRepo, zap.NewNop())
+
+	body, _ := json.Marshal(domain.BookingExpiredPayload{BookingID: 42, UserID: "user-1"})
+	ack := h(context.Background(), amqp.Delivery{RoutingKey: "booking.expired", Body: body})
+
+	if !ack {
+		t.Error("expected ack=true on successful broadcast")
+	}
+}
+
 func TestPaymentBroadcastHandler_BookingNotFound_Nacks(t *testing.T) {
 	hub := handler.NewHub()
 	payRepo := &mockBroadcastPayRepo{}
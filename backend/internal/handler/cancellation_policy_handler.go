@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/dto/request"
+	"booking-app/internal/dto/response"
+	"booking-app/internal/service"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancellationPolicyServiceInterface defines what the policy handler needs from the service.
+type CancellationPolicyServiceInterface interface {
+	SetHotelPolicy(ctx context.Context, ownerID string, hotelID int, input service.CancellationPolicyInput) (*domain.CancellationPolicy, error)
+	SetRoomPolicy(ctx context.Context, ownerID string, roomID int, input service.CancellationPolicyInput) (*domain.CancellationPolicy, error)
+	GetRoomPolicy(ctx context.Context, roomID int) (*domain.CancellationPolicy, error)
+}
+
+// CancellationPolicyHandler handles HTTP requests for cancellation policies.
+type CancellationPolicyHandler struct {
+	svc CancellationPolicyServiceInterface
+}
+
+// NewCancellationPolicyHandler creates a new CancellationPolicyHandler.
+func NewCancellationPolicyHandler(svc CancellationPolicyServiceInterface) *CancellationPolicyHandler {
+	return &CancellationPolicyHandler{svc: svc}
+}
+
+// SetHotelPolicy handles PUT /api/v1/owner/hotels/:id/cancellation-policy.
+func (h *CancellationPolicyHandler) SetHotelPolicy(c *gin.Context) {
+	hotelID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid hotel id"))
+		return
+	}
+
+	var req request.SetCancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	policy, err := h.svc.SetHotelPolicy(ctx, getUserIDFromContext(c), hotelID, policyInput(req))
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewCancellationPolicyResponse(policy)))
+}
+
+// SetRoomPolicy handles PUT /api/v1/owner/rooms/:id/cancellation-policy.
+func (h *CancellationPolicyHandler) SetRoomPolicy(c *gin.Context) {
+	roomID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid room id"))
+		return
+	}
+
+	var req request.SetCancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	policy, err := h.svc.SetRoomPolicy(ctx, getUserIDFromContext(c), roomID, policyInput(req))
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewCancellationPolicyResponse(policy)))
+}
+
+// GetRoomPolicy handles GET /api/v1/rooms/:id/cancellation-policy.
+// Returns the room's own policy or, failing that, its hotel's.
+func (h *CancellationPolicyHandler) GetRoomPolicy(c *gin.Context) {
+	roomID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid room id"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	policy, err := h.svc.GetRoomPolicy(ctx, roomID)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewCancellationPolicyResponse(policy)))
+}
+
+func policyInput(req request.SetCancellationPolicyRequest) service.CancellationPolicyInput {
+	return service.CancellationPolicyInput{
+		FreeCancellationDays:     req.FreeCancellationDays,
+		PenaltyPercent:           req.PenaltyPercent,
+		NonRefundable:            req.NonRefundable,
+		CaptureDaysBeforeCheckIn: req.CaptureDaysBeforeCheckIn,
+	}
+}
@@ -0,0 +1,127 @@
+package handler_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/handler"
+	"booking-app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Mock CancellationPolicyService ---
+
+type mockPolicySvc struct {
+	setHotelPolicyFn func(ctx context.Context, ownerID string, hotelID int, input service.CancellationPolicyInput) (*domain.CancellationPolicy, error)
+	setRoomPolicyFn  func(ctx context.Context, ownerID string, roomID int, input service.CancellationPolicyInput) (*domain.CancellationPolicy, error)
+	getRoomPolicyFn  func(ctx context.Context, roomID int) (*domain.CancellationPolicy, error)
+}
+
+func (m *mockPolicySvc) SetHotelPolicy(ctx context.Context, ownerID string, hotelID int, input service.CancellationPolicyInput) (*domain.CancellationPolicy, error) {
+	if m.setHotelPolicyFn != nil {
+		return m.setHotelPolicyFn(ctx, ownerID, hotelID, input)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockPolicySvc) SetRoomPolicy(ctx context.Context, ownerID string, roomID int, input service.CancellationPolicyInput) (*domain.CancellationPolicy, error) {
+	if m.setRoomPolicyFn != nil {
+		return m.setRoomPolicyFn(ctx, ownerID, roomID, input)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockPolicySvc) GetRoomPolicy(ctx context.Context, roomID int) (*domain.CancellationPolicy, error) {
+	if m.getRoomPolicyFn != nil {
+		return m.getRoomPolicyFn(ctx, roomID)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func buildPolicyRouter(svc handler.CancellationPolicyServiceInterface) *gin.Engine {
+	r := gin.New()
+	h := handler.NewCancellationPolicyHandler(svc)
+
+	r.GET("/api/v1/rooms/:id/cancellation-policy", h.GetRoomPolicy)
+
+	owner := r.Group("/api/v1/owner")
+	owner.Use(func(c *gin.Context) {
+		c.Set("userID", "owner-uuid-test")
+		c.Set("userRole", "owner")
+		c.Next()
+	})
+	owner.PUT("/hotels/:id/cancellation-policy", h.SetHotelPolicy)
+	owner.PUT("/rooms/:id/cancellation-policy", h.SetRoomPolicy)
+
+	return r
+}
+
+// --- Tests ---
+
+func TestPolicyHandler_SetHotelPolicy_Returns200(t *testing.T) {
+	var gotOwner string
+	var gotInput service.CancellationPolicyInput
+	svc := &mockPolicySvc{
+		setHotelPolicyFn: func(_ context.Context, ownerID string, hotelID int, input service.CancellationPolicyInput) (*domain.CancellationPolicy, error) {
+			gotOwner, gotInput = ownerID, input
+			return &domain.CancellationPolicy{ID: 1, HotelID: hotelID, FreeCancellationDays: input.FreeCancellationDays, PenaltyPercent: input.PenaltyPercent}, nil
+		},
+	}
+	r := buildPolicyRouter(svc)
+
+	body := strings.NewReader(`{"free_cancellation_days":5,"penalty_percent":40}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/hotels/3/cancellation-policy", body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotOwner != "owner-uuid-test" || gotInput.FreeCancellationDays != 5 || gotInput.PenaltyPercent != 40 {
+		t.Errorf("unexpected call owner=%q input=%+v", gotOwner, gotInput)
+	}
+}
+
+func TestPolicyHandler_SetRoomPolicy_PenaltyOutOfRange_Returns400(t *testing.T) {
+	r := buildPolicyRouter(&mockPolicySvc{})
+
+	body := strings.NewReader(`{"penalty_percent":150}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/rooms/3/cancellation-policy", body)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestPolicyHandler_SetRoomPolicy_NotOwner_Returns403(t *testing.T) {
+	svc := &mockPolicySvc{
+		setRoomPolicyFn: func(_ context.Context, _ string, _ int, _ service.CancellationPolicyInput) (*domain.CancellationPolicy, error) {
+			return nil, domain.ErrUnauthorized
+		},
+	}
+	r := buildPolicyRouter(svc)
+
+	body := strings.NewReader(`{"non_refundable":true}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/rooms/3/cancellation-policy", body)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestPolicyHandler_GetRoomPolicy_None_Returns404(t *testing.T) {
+	svc := &mockPolicySvc{
+		getRoomPolicyFn: func(_ context.Context, _ int) (*domain.CancellationPolicy, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+	r := buildPolicyRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/rooms/3/cancellation-policy", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/dto/request"
+	"booking-app/internal/dto/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExchangeRateServiceInterface defines what the exchange rate handler needs from the service.
+type ExchangeRateServiceInterface interface {
+	SetRates(ctx context.Context, rates []*domain.ExchangeRate) ([]*domain.ExchangeRate, error)
+	ListRates(ctx context.Context) ([]*domain.ExchangeRate, error)
+}
+
+// ExchangeRateHandler handles the admin endpoints for exchange rates.
+type ExchangeRateHandler struct {
+	svc ExchangeRateServiceInterface
+}
+
+// NewExchangeRateHandler creates a new ExchangeRateHandler.
+func NewExchangeRateHandler(svc ExchangeRateServiceInterface) *ExchangeRateHandler {
+	return &ExchangeRateHandler{svc: svc}
+}
+
+// SetRates handles PUT /api/v1/admin/exchange-rates.
+// The upload is applied as a whole; pairs not in it keep their current rate.
+func (h *ExchangeRateHandler) SetRates(c *gin.Context) {
+	var req request.SetExchangeRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	rates := make([]*domain.ExchangeRate, 0, len(req.Rates))
+	for _, r := range req.Rates {
+		rates = append(rates, &domain.ExchangeRate{Base: r.Base, Quote: r.Quote, Rate: r.Rate})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	saved, err := h.svc.SetRates(ctx, rates)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewExchangeRateListResponse(saved)))
+}
+
+// ListRates handles GET /api/v1/admin/exchange-rates.
+func (h *ExchangeRateHandler) ListRates(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	rates, err := h.svc.ListRates(ctx)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewExchangeRateListResponse(rates)))
+}
@@ -0,0 +1,110 @@
+package handler_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/handler"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Mock ExchangeRateService ---
+
+type mockExchangeRateSvc struct {
+	setRatesFn  func(ctx context.Context, rates []*domain.ExchangeRate) ([]*domain.ExchangeRate, error)
+	listRatesFn func(ctx context.Context) ([]*domain.ExchangeRate, error)
+}
+
+func (m *mockExchangeRateSvc) SetRates(ctx context.Context, rates []*domain.ExchangeRate) ([]*domain.ExchangeRate, error) {
+	if m.setRatesFn != nil {
+		return m.setRatesFn(ctx, rates)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockExchangeRateSvc) ListRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	if m.listRatesFn != nil {
+		return m.listRatesFn(ctx)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func buildExchangeRateRouter(svc handler.ExchangeRateServiceInterface) *gin.Engine {
+	r := gin.New()
+	h := handler.NewExchangeRateHandler(svc)
+	r.GET("/api/v1/admin/exchange-rates", h.ListRates)
+	r.PUT("/api/v1/admin/exchange-rates", h.SetRates)
+	return r
+}
+
+// --- Tests ---
+
+func TestExchangeRateHandler_SetRates_Returns200(t *testing.T) {
+	var got []*domain.ExchangeRate
+	svc := &mockExchangeRateSvc{
+		setRatesFn: func(_ context.Context, rates []*domain.ExchangeRate) ([]*domain.ExchangeRate, error) {
+			got = rates
+			return rates, nil
+		},
+	}
+	r := buildExchangeRateRouter(svc)
+
+	body := strings.NewReader(`{"rates":[{"base":"USD","quote":"THB","rate":36.5},{"base":"JPY","quote":"USD","rate":0.0067}]}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/admin/exchange-rates", body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(got) != 2 || got[0].Base != "USD" || got[0].Quote != "THB" || got[0].Rate != 36.5 {
+		t.Errorf("unexpected rates passed to service: %+v", got)
+	}
+}
+
+func TestExchangeRateHandler_SetRates_NonPositiveRate_Returns400(t *testing.T) {
+	r := buildExchangeRateRouter(&mockExchangeRateSvc{})
+
+	body := strings.NewReader(`{"rates":[{"base":"USD","quote":"THB","rate":0}]}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/admin/exchange-rates", body)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestExchangeRateHandler_SetRates_ServiceBadRequest_Returns400(t *testing.T) {
+	svc := &mockExchangeRateSvc{
+		setRatesFn: func(_ context.Context, _ []*domain.ExchangeRate) ([]*domain.ExchangeRate, error) {
+			return nil, fmt.Errorf("rate USD/USD converts a currency to itself: %w", domain.ErrBadRequest)
+		},
+	}
+	r := buildExchangeRateRouter(svc)
+
+	body := strings.NewReader(`{"rates":[{"base":"USD","quote":"USD","rate":1}]}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/admin/exchange-rates", body)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestExchangeRateHandler_ListRates_Returns200(t *testing.T) {
+	svc := &mockExchangeRateSvc{
+		listRatesFn: func(_ context.Context) ([]*domain.ExchangeRate, error) {
+			return []*domain.ExchangeRate{{Base: "USD", Quote: "VND", Rate: 25400}}, nil
+		},
+	}
+	r := buildExchangeRateRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/admin/exchange-rates", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"quote":"VND"`) {
+		t.Errorf("expected rate in body, got %s", w.Body.String())
+	}
+}
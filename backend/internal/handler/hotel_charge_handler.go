@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/dto/request"
+	"booking-app/internal/dto/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HotelChargeServiceInterface defines what the charge handler needs from the service.
+type HotelChargeServiceInterface interface {
+	SetCharges(ctx context.Context, ownerID string, hotelID int, charges []*domain.HotelCharge) ([]*domain.HotelCharge, error)
+	ListCharges(ctx context.Context, hotelID int) ([]*domain.HotelCharge, error)
+}
+
+// HotelChargeHandler handles HTTP requests for hotel taxes, fees and discounts.
+type HotelChargeHandler struct {
+	svc HotelChargeServiceInterface
+}
+
+// NewHotelChargeHandler creates a new HotelChargeHandler.
+func NewHotelChargeHandler(svc HotelChargeServiceInterface) *HotelChargeHandler {
+	return &HotelChargeHandler{svc: svc}
+}
+
+// SetCharges handles PUT /api/v1/owner/hotels/:id/charges.
+func (h *HotelChargeHandler) SetCharges(c *gin.Context) {
+	hotelID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid hotel id"))
+		return
+	}
+
+	var req request.SetHotelChargesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	charges := make([]*domain.HotelCharge, 0, len(req.Charges))
+	for _, rc := range req.Charges {
+		charges = append(charges, &domain.HotelCharge{
+			Name:      rc.Name,
+			Type:      domain.ChargeType(rc.Type),
+			Percent:   rc.Percent,
+			Amount:    rc.Amount,
+			PerNight:  rc.PerNight,
+			MinNights: rc.MinNights,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	saved, err := h.svc.SetCharges(ctx, getUserIDFromContext(c), hotelID, charges)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewHotelChargeListResponse(saved)))
+}
+
+// ListCharges handles GET /api/v1/hotels/:id/charges.
+func (h *HotelChargeHandler) ListCharges(c *gin.Context) {
+	hotelID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid hotel id"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	charges, err := h.svc.ListCharges(ctx, hotelID)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewHotelChargeListResponse(charges)))
+}
@@ -0,0 +1,116 @@
+package handler_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/handler"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Mock HotelChargeService ---
+
+type mockHotelChargeSvc struct {
+	setChargesFn  func(ctx context.Context, ownerID string, hotelID int, charges []*domain.HotelCharge) ([]*domain.HotelCharge, error)
+	listChargesFn func(ctx context.Context, hotelID int) ([]*domain.HotelCharge, error)
+}
+
+func (m *mockHotelChargeSvc) SetCharges(ctx context.Context, ownerID string, hotelID int, charges []*domain.HotelCharge) ([]*domain.HotelCharge, error) {
+	if m.setChargesFn != nil {
+		return m.setChargesFn(ctx, ownerID, hotelID, charges)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockHotelChargeSvc) ListCharges(ctx context.Context, hotelID int) ([]*domain.HotelCharge, error) {
+	if m.listChargesFn != nil {
+		return m.listChargesFn(ctx, hotelID)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func buildHotelChargeRouter(svc handler.HotelChargeServiceInterface) *gin.Engine {
+	r := gin.New()
+	h := handler.NewHotelChargeHandler(svc)
+	r.GET("/api/v1/hotels/:id/charges", h.ListCharges)
+	owner := r.Group("/api/v1/owner")
+	owner.Use(func(c *gin.Context) {
+		c.Set("userID", "owner-uuid")
+		c.Next()
+	})
+	owner.PUT("/hotels/:id/charges", h.SetCharges)
+	return r
+}
+
+// --- Tests ---
+
+func TestHotelChargeHandler_SetCharges_Returns200(t *testing.T) {
+	var gotOwner string
+	var got []*domain.HotelCharge
+	svc := &mockHotelChargeSvc{
+		setChargesFn: func(_ context.Context, ownerID string, _ int, charges []*domain.HotelCharge) ([]*domain.HotelCharge, error) {
+			gotOwner, got = ownerID, charges
+			return charges, nil
+		},
+	}
+	r := buildHotelChargeRouter(svc)
+
+	body := strings.NewReader(`{"charges":[{"name":"VAT","type":"tax","percent":7},{"name":"City tax","type":"tax","amount":500,"per_night":true}]}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/hotels/10/charges", body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotOwner != "owner-uuid" || len(got) != 2 || got[1].Amount == nil || *got[1].Amount != 500 || !got[1].PerNight {
+		t.Errorf("unexpected charges passed to service by %q: %+v", gotOwner, got)
+	}
+}
+
+func TestHotelChargeHandler_SetCharges_UnknownType_Returns400(t *testing.T) {
+	r := buildHotelChargeRouter(&mockHotelChargeSvc{})
+
+	body := strings.NewReader(`{"charges":[{"name":"Resort","type":"surcharge","amount":500}]}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/hotels/10/charges", body)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestHotelChargeHandler_SetCharges_NotOwner_Returns403(t *testing.T) {
+	svc := &mockHotelChargeSvc{
+		setChargesFn: func(_ context.Context, _ string, _ int, _ []*domain.HotelCharge) ([]*domain.HotelCharge, error) {
+			return nil, domain.ErrUnauthorized
+		},
+	}
+	r := buildHotelChargeRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/hotels/10/charges", strings.NewReader(`{"charges":[]}`))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestHotelChargeHandler_ListCharges_Returns200(t *testing.T) {
+	pct := 10.0
+	svc := &mockHotelChargeSvc{
+		listChargesFn: func(_ context.Context, hotelID int) ([]*domain.HotelCharge, error) {
+			return []*domain.HotelCharge{{ID: 1, HotelID: hotelID, Name: "Weekly", Type: domain.ChargeTypeDiscount, Percent: &pct, MinNights: 7}}, nil
+		},
+	}
+	r := buildHotelChargeRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/hotels/10/charges", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"min_nights":7`) {
+		t.Errorf("expected charge in body, got %s", w.Body.String())
+	}
+}
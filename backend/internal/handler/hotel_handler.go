@@ -129,6 +129,7 @@ func (h *HotelHandler) CreateHotel(c *gin.Context) {
 		Images:      req.Images,
 		StarRating:  req.StarRating,
 		Description: req.Description,
+		Currency:    req.Currency,
 	})
 	if err != nil {
 		handleHotelError(c, err)
@@ -191,6 +192,7 @@ func (h *HotelHandler) UpdateHotel(c *gin.Context) {
 		Images:      req.Images,
 		StarRating:  req.StarRating,
 		Description: req.Description,
+		Currency:    req.Currency,
 	})
 	if err != nil {
 		handleHotelError(c, err)
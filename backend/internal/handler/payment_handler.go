@@ -15,6 +15,8 @@ import (
 // checkoutRequest is the body for POST /api/v1/checkout.
 type checkoutRequest struct {
 	BookingID int `json:"booking_id" binding:"required,min=1"`
+	// PaymentMethod is the card token to charge.
+	PaymentMethod string `json:"payment_method" binding:"max=64"`
 }
 
 // PaymentHandler handles HTTP requests for payment endpoints.
@@ -49,7 +51,7 @@ func (h *PaymentHandler) Checkout(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
 	defer cancel()
 
-	payment, err := h.sagaOrch.StartCheckout(ctx, req.BookingID, userID)
+	payment, err := h.sagaOrch.StartCheckout(ctx, req.BookingID, userID, req.PaymentMethod)
 	if err != nil {
 		handlePaymentError(c, err)
 		return
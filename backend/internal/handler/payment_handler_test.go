@@ -41,15 +41,15 @@ func (m *mockPaymentSvc) GetPayment(ctx context.Context, id string, callerUserID
 // --- Mock SagaOrchestrator ---
 
 type mockSagaOrch struct {
-	startCheckoutFn         func(ctx context.Context, bookingID int, userID string) (*domain.Payment, error)
+	startCheckoutFn         func(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error)
 	handlePaymentSuccessFn  func(ctx context.Context, paymentID string) error
 	handlePaymentFailureFn  func(ctx context.Context, paymentID string, reason string) error
 	handlePaymentTimeoutFn  func(ctx context.Context, paymentID string) error
 }
 
-func (m *mockSagaOrch) StartCheckout(ctx context.Context, bookingID int, userID string) (*domain.Payment, error) {
+func (m *mockSagaOrch) StartCheckout(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error) {
 	if m.startCheckoutFn != nil {
-		return m.startCheckoutFn(ctx, bookingID, userID)
+		return m.startCheckoutFn(ctx, bookingID, userID, paymentMethod)
 	}
 	return nil, fmt.Errorf("not configured")
 }
@@ -61,6 +61,10 @@ func (m *mockSagaOrch) HandlePaymentSuccess(ctx context.Context, paymentID strin
 	return fmt.Errorf("not configured")
 }
 
+func (m *mockSagaOrch) HandleLateSuccess(ctx context.Context, paymentID string, status domain.PaymentStatus, gatewayRef string) error {
+	return fmt.Errorf("not configured")
+}
+
 func (m *mockSagaOrch) HandlePaymentFailure(ctx context.Context, paymentID string, reason string) error {
 	if m.handlePaymentFailureFn != nil {
 		return m.handlePaymentFailureFn(ctx, paymentID, reason)
@@ -75,6 +79,34 @@ func (m *mockSagaOrch) HandlePaymentTimeout(ctx context.Context, paymentID strin
 	return fmt.Errorf("not configured")
 }
 
+func (m *mockSagaOrch) StartTopUp(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Payment, error) {
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockSagaOrch) StartPartialRefund(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Refund, error) {
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockSagaOrch) StartRefund(ctx context.Context, paymentID string, bookingID int, amount int64, reason string) (*domain.Refund, error) {
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockSagaOrch) HandleRefundSucceeded(ctx context.Context, refundID, gatewayRef string) error {
+	return nil
+}
+
+func (m *mockSagaOrch) HandleRefundFailed(ctx context.Context, refundID, reason string) error {
+	return nil
+}
+
+func (m *mockSagaOrch) ReleaseAuthorization(ctx context.Context, paymentID string, bookingID int, amount int64) error {
+	return nil
+}
+
+func (m *mockSagaOrch) HandleCaptureFailed(ctx context.Context, paymentID, reason string) error {
+	return nil
+}
+
 // --- Helpers ---
 
 func setupPaymentRouter(paymentSvc service.PaymentServiceInterface, sagaOrch service.SagaOrchestratorInterface, userID, role string) *gin.Engine {
@@ -112,7 +144,7 @@ func samplePayment() *domain.Payment {
 
 func TestPaymentHandler_Checkout_Returns201(t *testing.T) {
 	sagaOrch := &mockSagaOrch{
-		startCheckoutFn: func(ctx context.Context, bookingID int, userID string) (*domain.Payment, error) {
+		startCheckoutFn: func(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error) {
 			return samplePayment(), nil
 		},
 	}
@@ -138,6 +170,30 @@ func TestPaymentHandler_Checkout_Returns201(t *testing.T) {
 	}
 }
 
+func TestPaymentHandler_Checkout_PassesPaymentMethod(t *testing.T) {
+	var gotMethod string
+	sagaOrch := &mockSagaOrch{
+		startCheckoutFn: func(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error) {
+			gotMethod = paymentMethod
+			return samplePayment(), nil
+		},
+	}
+	r := setupPaymentRouter(&mockPaymentSvc{}, sagaOrch, "user-1", "guest")
+
+	body := `{"booking_id":5,"payment_method":"4000000000000002"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/checkout", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if gotMethod != "4000000000000002" {
+		t.Errorf("expected payment method passed to saga, got %q", gotMethod)
+	}
+}
+
 func TestPaymentHandler_Checkout_InvalidJSON_Returns400(t *testing.T) {
 	sagaOrch := &mockSagaOrch{}
 	paymentSvc := &mockPaymentSvc{}
@@ -172,7 +228,7 @@ func TestPaymentHandler_Checkout_MissingUserID_Returns401(t *testing.T) {
 
 func TestPaymentHandler_Checkout_BookingNotFound_Returns404(t *testing.T) {
 	sagaOrch := &mockSagaOrch{
-		startCheckoutFn: func(ctx context.Context, bookingID int, userID string) (*domain.Payment, error) {
+		startCheckoutFn: func(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error) {
 			return nil, domain.ErrNotFound
 		},
 	}
@@ -192,7 +248,7 @@ func TestPaymentHandler_Checkout_BookingNotFound_Returns404(t *testing.T) {
 
 func TestPaymentHandler_Checkout_Conflict_Returns409(t *testing.T) {
 	sagaOrch := &mockSagaOrch{
-		startCheckoutFn: func(ctx context.Context, bookingID int, userID string) (*domain.Payment, error) {
+		startCheckoutFn: func(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error) {
 			return nil, domain.ErrConflict
 		},
 	}
@@ -212,7 +268,7 @@ func TestPaymentHandler_Checkout_Conflict_Returns409(t *testing.T) {
 
 func TestPaymentHandler_Checkout_Forbidden_Returns403(t *testing.T) {
 	sagaOrch := &mockSagaOrch{
-		startCheckoutFn: func(ctx context.Context, bookingID int, userID string) (*domain.Payment, error) {
+		startCheckoutFn: func(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error) {
 			return nil, domain.ErrForbidden
 		},
 	}
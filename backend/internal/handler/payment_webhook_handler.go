@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/dto/response"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	maxWebhookBodyBytes    = 64 << 10
+)
+
+// PaymentWebhookHandler receives payment provider notifications.
+type PaymentWebhookHandler struct {
+	webhookSvc service.PaymentWebhookServiceInterface
+}
+
+// NewPaymentWebhookHandler creates a new PaymentWebhookHandler.
+func NewPaymentWebhookHandler(webhookSvc service.PaymentWebhookServiceInterface) *PaymentWebhookHandler {
+	return &PaymentWebhookHandler{webhookSvc: webhookSvc}
+}
+
+// Receive handles POST /api/v1/payments/webhooks/:provider.
+// The raw body is verified against the signature header before it is parsed;
+// a 2xx tells the provider not to send the event again.
+func (h *PaymentWebhookHandler) Receive(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodyBytes))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, response.Fail("webhook body too large"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	err = h.webhookSvc.HandleWebhook(ctx, c.Param("provider"), body, c.GetHeader(WebhookSignatureHeader))
+	if err != nil {
+		if errors.Is(err, domain.ErrUnauthorized) {
+			c.JSON(http.StatusUnauthorized, response.Fail(err.Error()))
+			return
+		}
+		handlePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(gin.H{"message": "webhook received"}))
+}
@@ -0,0 +1,105 @@
+package handler_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/handler"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Mock PaymentWebhookService ---
+
+type mockWebhookSvc struct {
+	handleWebhookFn func(ctx context.Context, provider string, body []byte, signature string) error
+}
+
+func (m *mockWebhookSvc) HandleWebhook(ctx context.Context, provider string, body []byte, signature string) error {
+	if m.handleWebhookFn != nil {
+		return m.handleWebhookFn(ctx, provider, body, signature)
+	}
+	return fmt.Errorf("not configured")
+}
+
+func setupWebhookRouter(svc *mockWebhookSvc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := handler.NewPaymentWebhookHandler(svc)
+	r.POST("/api/v1/payments/webhooks/:provider", h.Receive)
+	return r
+}
+
+// --- Tests: POST /api/v1/payments/webhooks/:provider ---
+
+func TestPaymentWebhookHandler_Receive_PassesRawBodyAndSignature(t *testing.T) {
+	var gotProvider, gotBody, gotSig string
+	svc := &mockWebhookSvc{
+		handleWebhookFn: func(_ context.Context, provider string, body []byte, signature string) error {
+			gotProvider, gotBody, gotSig = provider, string(body), signature
+			return nil
+		},
+	}
+	r := setupWebhookRouter(svc)
+
+	body := `{"id":"evt_1","type":"payment.succeeded"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payments/webhooks/acme", strings.NewReader(body))
+	req.Header.Set(handler.WebhookSignatureHeader, "sha256=abc")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if gotProvider != "acme" || gotBody != body || gotSig != "sha256=abc" {
+		t.Errorf("unexpected call: provider=%q body=%q sig=%q", gotProvider, gotBody, gotSig)
+	}
+}
+
+func TestPaymentWebhookHandler_Receive_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("bad signature: %w", domain.ErrUnauthorized), http.StatusUnauthorized},
+		{fmt.Errorf("unknown provider: %w", domain.ErrNotFound), http.StatusNotFound},
+		{fmt.Errorf("malformed: %w", domain.ErrBadRequest), http.StatusBadRequest},
+		{fmt.Errorf("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		svc := &mockWebhookSvc{
+			handleWebhookFn: func(_ context.Context, _ string, _ []byte, _ string) error { return tt.err },
+		}
+		r := setupWebhookRouter(svc)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/payments/webhooks/acme", strings.NewReader(`{}`))
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.want {
+			t.Errorf("%v: expected %d, got %d", tt.err, tt.want, w.Code)
+		}
+	}
+}
+
+func TestPaymentWebhookHandler_Receive_BodyTooLarge_Returns413(t *testing.T) {
+	svc := &mockWebhookSvc{
+		handleWebhookFn: func(_ context.Context, _ string, _ []byte, _ string) error {
+			t.Error("oversized body should not reach the service")
+			return nil
+		},
+	}
+	r := setupWebhookRouter(svc)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payments/webhooks/acme", strings.NewReader(strings.Repeat("x", 65<<10)))
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
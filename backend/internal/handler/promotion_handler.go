@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/dto/request"
+	"booking-app/internal/dto/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromotionServiceInterface defines what the promotion handler needs from the service.
+type PromotionServiceInterface interface {
+	CreatePromotion(ctx context.Context, ownerID string, p *domain.Promotion) (*domain.Promotion, error)
+	CreateAdminPromotion(ctx context.Context, adminID string, p *domain.Promotion) (*domain.Promotion, error)
+	ListMyPromotions(ctx context.Context, ownerID string, page, limit int) ([]*domain.Promotion, int, error)
+	ListAllPromotions(ctx context.Context, page, limit int) ([]*domain.Promotion, int, error)
+	DeactivatePromotion(ctx context.Context, ownerID string, id int) error
+	AdminDeactivatePromotion(ctx context.Context, id int) error
+}
+
+// PromotionHandler handles HTTP requests for promo codes, for owners and admins.
+type PromotionHandler struct {
+	svc PromotionServiceInterface
+}
+
+// NewPromotionHandler creates a new PromotionHandler.
+func NewPromotionHandler(svc PromotionServiceInterface) *PromotionHandler {
+	return &PromotionHandler{svc: svc}
+}
+
+// CreatePromotion handles POST /api/v1/owner/promotions.
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	h.create(c, h.svc.CreatePromotion)
+}
+
+// CreateAdminPromotion handles POST /api/v1/admin/promotions.
+func (h *PromotionHandler) CreateAdminPromotion(c *gin.Context) {
+	h.create(c, h.svc.CreateAdminPromotion)
+}
+
+func (h *PromotionHandler) create(c *gin.Context, create func(context.Context, string, *domain.Promotion) (*domain.Promotion, error)) {
+	var req request.CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	created, err := create(ctx, getUserIDFromContext(c), &domain.Promotion{
+		Code:           req.Code,
+		Description:    req.Description,
+		HotelID:        req.HotelID,
+		RoomID:         req.RoomID,
+		Percent:        req.Percent,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		ValidFrom:      req.ValidFrom,
+		ValidUntil:     req.ValidUntil,
+		MinNights:      req.MinNights,
+		MaxRedemptions: req.MaxRedemptions,
+		MaxPerUser:     req.MaxPerUser,
+	})
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.OK(response.NewPromotionResponse(created)))
+}
+
+// ListMyPromotions handles GET /api/v1/owner/promotions.
+func (h *PromotionHandler) ListMyPromotions(c *gin.Context) {
+	ownerID := getUserIDFromContext(c)
+	h.list(c, func(ctx context.Context, page, limit int) ([]*domain.Promotion, int, error) {
+		return h.svc.ListMyPromotions(ctx, ownerID, page, limit)
+	})
+}
+
+// ListAllPromotions handles GET /api/v1/admin/promotions.
+func (h *PromotionHandler) ListAllPromotions(c *gin.Context) {
+	h.list(c, h.svc.ListAllPromotions)
+}
+
+func (h *PromotionHandler) list(c *gin.Context, list func(context.Context, int, int) ([]*domain.Promotion, int, error)) {
+	page := queryIntDefault(c, "page", 1)
+	limit := queryIntDefault(c, "limit", 20)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	promotions, total, err := list(ctx, page, limit)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OKList(
+		response.NewPromotionListResponse(promotions),
+		response.Meta{Total: total, Page: page, Limit: limit, Pages: calculatePages(total, limit)},
+	))
+}
+
+// DeactivatePromotion handles DELETE /api/v1/owner/promotions/:id.
+func (h *PromotionHandler) DeactivatePromotion(c *gin.Context) {
+	ownerID := getUserIDFromContext(c)
+	h.deactivate(c, func(ctx context.Context, id int) error {
+		return h.svc.DeactivatePromotion(ctx, ownerID, id)
+	})
+}
+
+// AdminDeactivatePromotion handles DELETE /api/v1/admin/promotions/:id.
+func (h *PromotionHandler) AdminDeactivatePromotion(c *gin.Context) {
+	h.deactivate(c, h.svc.AdminDeactivatePromotion)
+}
+
+func (h *PromotionHandler) deactivate(c *gin.Context, deactivate func(context.Context, int) error) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid promotion id"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := deactivate(ctx, id); err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(gin.H{"message": "promotion deactivated"}))
+}
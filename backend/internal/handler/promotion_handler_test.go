@@ -0,0 +1,150 @@
+package handler_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/handler"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Mock PromotionService ---
+
+type mockPromotionSvc struct {
+	createPromotionFn          func(ctx context.Context, ownerID string, p *domain.Promotion) (*domain.Promotion, error)
+	createAdminPromotionFn     func(ctx context.Context, adminID string, p *domain.Promotion) (*domain.Promotion, error)
+	listMyPromotionsFn         func(ctx context.Context, ownerID string, page, limit int) ([]*domain.Promotion, int, error)
+	listAllPromotionsFn        func(ctx context.Context, page, limit int) ([]*domain.Promotion, int, error)
+	deactivatePromotionFn      func(ctx context.Context, ownerID string, id int) error
+	adminDeactivatePromotionFn func(ctx context.Context, id int) error
+}
+
+func (m *mockPromotionSvc) CreatePromotion(ctx context.Context, ownerID string, p *domain.Promotion) (*domain.Promotion, error) {
+	if m.createPromotionFn != nil {
+		return m.createPromotionFn(ctx, ownerID, p)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockPromotionSvc) CreateAdminPromotion(ctx context.Context, adminID string, p *domain.Promotion) (*domain.Promotion, error) {
+	if m.createAdminPromotionFn != nil {
+		return m.createAdminPromotionFn(ctx, adminID, p)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockPromotionSvc) ListMyPromotions(ctx context.Context, ownerID string, page, limit int) ([]*domain.Promotion, int, error) {
+	if m.listMyPromotionsFn != nil {
+		return m.listMyPromotionsFn(ctx, ownerID, page, limit)
+	}
+	return nil, 0, fmt.Errorf("not configured")
+}
+
+func (m *mockPromotionSvc) ListAllPromotions(ctx context.Context, page, limit int) ([]*domain.Promotion, int, error) {
+	if m.listAllPromotionsFn != nil {
+		return m.listAllPromotionsFn(ctx, page, limit)
+	}
+	return nil, 0, fmt.Errorf("not configured")
+}
+
+func (m *mockPromotionSvc) DeactivatePromotion(ctx context.Context, ownerID string, id int) error {
+	if m.deactivatePromotionFn != nil {
+		return m.deactivatePromotionFn(ctx, ownerID, id)
+	}
+	return fmt.Errorf("not configured")
+}
+
+func (m *mockPromotionSvc) AdminDeactivatePromotion(ctx context.Context, id int) error {
+	if m.adminDeactivatePromotionFn != nil {
+		return m.adminDeactivatePromotionFn(ctx, id)
+	}
+	return fmt.Errorf("not configured")
+}
+
+func buildPromotionRouter(svc handler.PromotionServiceInterface) *gin.Engine {
+	r := gin.New()
+	h := handler.NewPromotionHandler(svc)
+	owner := r.Group("/api/v1/owner")
+	owner.Use(func(c *gin.Context) {
+		c.Set("userID", "owner-uuid")
+		c.Next()
+	})
+	owner.POST("/promotions", h.CreatePromotion)
+	owner.DELETE("/promotions/:id", h.DeactivatePromotion)
+	admin := r.Group("/api/v1/admin")
+	admin.GET("/promotions", h.ListAllPromotions)
+	return r
+}
+
+// --- Tests ---
+
+func TestPromotionHandler_CreatePromotion_Returns201(t *testing.T) {
+	var gotOwner string
+	var got *domain.Promotion
+	svc := &mockPromotionSvc{
+		createPromotionFn: func(_ context.Context, ownerID string, p *domain.Promotion) (*domain.Promotion, error) {
+			gotOwner, got = ownerID, p
+			p.ID, p.Active = 1, true
+			return p, nil
+		},
+	}
+	r := buildPromotionRouter(svc)
+
+	body := strings.NewReader(`{"code":"SUMMER15","hotel_id":10,"percent":15,"min_nights":2}`)
+	w := makeHotelRequest(r, http.MethodPost, "/api/v1/owner/promotions", body)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotOwner != "owner-uuid" || got.HotelID == nil || *got.HotelID != 10 || got.Percent == nil || *got.Percent != 15 || got.MinNights != 2 {
+		t.Errorf("unexpected promotion passed to service by %q: %+v", gotOwner, got)
+	}
+}
+
+func TestPromotionHandler_CreatePromotion_InvalidPercent_Returns400(t *testing.T) {
+	r := buildPromotionRouter(&mockPromotionSvc{})
+
+	body := strings.NewReader(`{"code":"HALFOFF","hotel_id":10,"percent":150}`)
+	w := makeHotelRequest(r, http.MethodPost, "/api/v1/owner/promotions", body)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestPromotionHandler_ListAllPromotions_Returns200(t *testing.T) {
+	svc := &mockPromotionSvc{
+		listAllPromotionsFn: func(_ context.Context, page, limit int) ([]*domain.Promotion, int, error) {
+			return []*domain.Promotion{{ID: 1, Code: "WELCOME5", Redemptions: 4, Active: true}}, 1, nil
+		},
+	}
+	r := buildPromotionRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/admin/promotions", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"redemptions":4`) || !strings.Contains(w.Body.String(), `"total":1`) {
+		t.Errorf("expected promotion and meta in body, got %s", w.Body.String())
+	}
+}
+
+func TestPromotionHandler_DeactivatePromotion_NotCreator_Returns403(t *testing.T) {
+	svc := &mockPromotionSvc{
+		deactivatePromotionFn: func(_ context.Context, _ string, _ int) error {
+			return domain.ErrUnauthorized
+		},
+	}
+	r := buildPromotionRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodDelete, "/api/v1/owner/promotions/3", nil)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
@@ -5,8 +5,13 @@ import (
 	"booking-app/internal/dto/request"
 	"booking-app/internal/dto/response"
 	"booking-app/internal/service"
+	"bytes"
 	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -25,8 +30,16 @@ type RoomServiceInterface interface {
 type InventoryServiceInterface interface {
 	SetInventoryRange(ctx context.Context, ownerID string, roomID int, startDate time.Time, days int, total int) error
 	GetInventoryRange(ctx context.Context, roomID int, startDate time.Time, endDate time.Time) ([]*domain.Inventory, error)
+	SetRatePlan(ctx context.Context, ownerID string, roomID int, plan *domain.RatePlan) (*domain.RatePlan, error)
+	SetOverbooking(ctx context.Context, ownerID string, roomID int, overbooking domain.Overbooking) (*domain.Room, error)
+	ListOverbookedNights(ctx context.Context, ownerID string, hotelID int, startDate time.Time, endDate time.Time) ([]*domain.OverbookedNight, error)
+	ImportInventory(ctx context.Context, ownerID string, hotelID int, src io.Reader, dryRun bool) (*domain.InventoryImport, error)
+	ExportInventory(ctx context.Context, ownerID string, hotelID int, startDate time.Time, endDate time.Time, fn func(*domain.Inventory) error) error
 }
 
+// maxInventoryImportBytes caps the size of an inventory CSV upload.
+const maxInventoryImportBytes = 2 << 20
+
 // RoomHandler handles HTTP requests for room and inventory endpoints.
 type RoomHandler struct {
 	roomSvc      RoomServiceInterface
@@ -82,6 +95,8 @@ func (h *RoomHandler) CreateRoom(c *gin.Context) {
 		Name:          req.Name,
 		Description:   req.Description,
 		Capacity:      req.Capacity,
+		MaxExtraBeds:  req.MaxExtraBeds,
+		ExtraGuestFee: req.ExtraGuestFee,
 		PricePerNight: req.PricePerNight,
 		Amenities:     req.Amenities,
 		Images:        req.Images,
@@ -117,6 +132,8 @@ func (h *RoomHandler) UpdateRoom(c *gin.Context) {
 		Name:          req.Name,
 		Description:   req.Description,
 		Capacity:      req.Capacity,
+		MaxExtraBeds:  req.MaxExtraBeds,
+		ExtraGuestFee: req.ExtraGuestFee,
 		PricePerNight: req.PricePerNight,
 		Amenities:     req.Amenities,
 		Images:        req.Images,
@@ -223,3 +240,246 @@ func (h *RoomHandler) GetInventory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.OK(response.NewInventoryListResponse(invs)))
 }
+
+// SetRates handles PUT /api/v1/owner/rooms/:id/rates.
+// Replaces the room's weekend rate, minimum stay, seasons and date overrides.
+func (h *RoomHandler) SetRates(c *gin.Context) {
+	roomID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid room id"))
+		return
+	}
+
+	var req request.SetRatePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	plan, err := ratePlanFromRequest(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	saved, err := h.inventorySvc.SetRatePlan(ctx, getUserIDFromContext(c), roomID, plan)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewRatePlanResponse(saved)))
+}
+
+// SetOverbooking handles PUT /api/v1/owner/rooms/:id/overbooking.
+// Bookings may take the room's nights past their inventory by the allowance.
+func (h *RoomHandler) SetOverbooking(c *gin.Context) {
+	roomID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid room id"))
+		return
+	}
+
+	var req request.SetOverbookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	room, err := h.inventorySvc.SetOverbooking(ctx, getUserIDFromContext(c), roomID, domain.Overbooking{
+		Type:  domain.OverbookingType(req.Type),
+		Value: req.Value,
+	})
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewOverbookingResponse(room)))
+}
+
+// ListOverbookedNights handles GET /api/v1/owner/hotels/:id/overbooked-nights.
+// It lists the nights between start_date and end_date on which the hotel's
+// rooms are sold past their inventory, so the front desk can plan relocations.
+func (h *RoomHandler) ListOverbookedNights(c *gin.Context) {
+	hotelID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid hotel id"))
+		return
+	}
+
+	startStr := c.Query("start_date")
+	endStr := c.Query("end_date")
+	if startStr == "" || endStr == "" {
+		c.JSON(http.StatusBadRequest, response.Fail("start_date and end_date query params are required"))
+		return
+	}
+
+	startDate, endDate, ok := parseDateRange(c, startStr, endStr)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	nights, err := h.inventorySvc.ListOverbookedNights(ctx, getUserIDFromContext(c), hotelID, startDate, endDate)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(response.NewOverbookedNightListResponse(nights)))
+}
+
+// ImportInventory handles POST /api/v1/owner/hotels/:id/inventory/import.
+// The body is CSV with a header row of room_id, date, total and an optional
+// price. With ?dry_run=true the rows are only checked. Rows with errors are
+// listed by line in a 422 response and nothing is applied.
+func (h *RoomHandler) ImportInventory(c *gin.Context) {
+	hotelID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid hotel id"))
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("dry_run must be true or false"))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxInventoryImportBytes))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, response.Fail("inventory csv too large"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := h.inventorySvc.ImportInventory(ctx, getUserIDFromContext(c), hotelID, bytes.NewReader(body), dryRun)
+	if err != nil {
+		handleHotelError(c, err)
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, response.APIResponse{
+			Success: false,
+			Data:    result,
+			Error:   fmt.Sprintf("%d rows have errors, nothing was imported", len(result.Errors)),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.OK(result))
+}
+
+// ExportInventory handles GET /api/v1/owner/hotels/:id/inventory/export?from&to.
+// It streams the inventory of the hotel's rooms for the nights in [from, to)
+// as CSV in the format ImportInventory takes.
+func (h *RoomHandler) ExportInventory(c *gin.Context) {
+	hotelID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid hotel id"))
+		return
+	}
+
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	if fromStr == "" || toStr == "" {
+		c.JSON(http.StatusBadRequest, response.Fail("from and to query params are required"))
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid from format, use YYYY-MM-DD"))
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid to format, use YYYY-MM-DD"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	// The CSV starts on the first row so that a rejected export still gets
+	// a JSON error; once rows are sent the status can no longer change.
+	out := csv.NewWriter(c.Writer)
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="hotel-%d-inventory-%s-%s.csv"`, hotelID, fromStr, toStr))
+		c.Status(http.StatusOK)
+		return out.Write(domain.InventoryCSVHeader)
+	}
+
+	err = h.inventorySvc.ExportInventory(ctx, getUserIDFromContext(c), hotelID, from, to, func(inv *domain.Inventory) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return out.Write(domain.InventoryCSVRecord(inv))
+	})
+	if err != nil {
+		if !started {
+			handleHotelError(c, err)
+			return
+		}
+		// Rows already went out: the client sees a truncated file.
+		_ = c.Error(err)
+		return
+	}
+
+	if !started {
+		_ = start()
+	}
+	out.Flush()
+}
+
+func ratePlanFromRequest(req request.SetRatePlanRequest) (*domain.RatePlan, error) {
+	plan := &domain.RatePlan{
+		WeekendPrice: req.WeekendPrice,
+		MinStay:      req.MinStay,
+		Seasons:      make([]*domain.SeasonalRate, 0, len(req.Seasons)),
+		Overrides:    make([]*domain.DateRate, 0, len(req.Overrides)),
+	}
+	for _, s := range req.Seasons {
+		start, err := time.Parse("2006-01-02", s.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_date %q, use YYYY-MM-DD", s.StartDate)
+		}
+		end, err := time.Parse("2006-01-02", s.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end_date %q, use YYYY-MM-DD", s.EndDate)
+		}
+		plan.Seasons = append(plan.Seasons, &domain.SeasonalRate{
+			Name:         s.Name,
+			StartDate:    start,
+			EndDate:      end,
+			Price:        s.Price,
+			WeekendPrice: s.WeekendPrice,
+			MinStay:      s.MinStay,
+		})
+	}
+	for _, o := range req.Overrides {
+		date, err := time.Parse("2006-01-02", o.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, use YYYY-MM-DD", o.Date)
+		}
+		plan.Overrides = append(plan.Overrides, &domain.DateRate{Date: date, Price: o.Price, MinStay: o.MinStay})
+	}
+	return plan, nil
+}
@@ -6,6 +6,7 @@ import (
 	"booking-app/internal/service"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -62,8 +63,13 @@ func (m *mockRoomSvc) DeleteRoom(ctx context.Context, roomID int, ownerID string
 // --- Mock InventoryService ---
 
 type mockInventorySvc struct {
-	setInventoryRangeFn func(ctx context.Context, ownerID string, roomID int, startDate time.Time, days int, total int) error
-	getInventoryRangeFn func(ctx context.Context, roomID int, startDate time.Time, endDate time.Time) ([]*domain.Inventory, error)
+	setInventoryRangeFn    func(ctx context.Context, ownerID string, roomID int, startDate time.Time, days int, total int) error
+	getInventoryRangeFn    func(ctx context.Context, roomID int, startDate time.Time, endDate time.Time) ([]*domain.Inventory, error)
+	setRatePlanFn          func(ctx context.Context, ownerID string, roomID int, plan *domain.RatePlan) (*domain.RatePlan, error)
+	setOverbookingFn       func(ctx context.Context, ownerID string, roomID int, overbooking domain.Overbooking) (*domain.Room, error)
+	listOverbookedNightsFn func(ctx context.Context, ownerID string, hotelID int, startDate time.Time, endDate time.Time) ([]*domain.OverbookedNight, error)
+	importInventoryFn      func(ctx context.Context, ownerID string, hotelID int, src io.Reader, dryRun bool) (*domain.InventoryImport, error)
+	exportInventoryFn      func(ctx context.Context, ownerID string, hotelID int, startDate time.Time, endDate time.Time, fn func(*domain.Inventory) error) error
 }
 
 func (m *mockInventorySvc) SetInventoryRange(ctx context.Context, ownerID string, roomID int, startDate time.Time, days int, total int) error {
@@ -80,6 +86,41 @@ func (m *mockInventorySvc) GetInventoryRange(ctx context.Context, roomID int, st
 	return nil, fmt.Errorf("not configured")
 }
 
+func (m *mockInventorySvc) SetRatePlan(ctx context.Context, ownerID string, roomID int, plan *domain.RatePlan) (*domain.RatePlan, error) {
+	if m.setRatePlanFn != nil {
+		return m.setRatePlanFn(ctx, ownerID, roomID, plan)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockInventorySvc) SetOverbooking(ctx context.Context, ownerID string, roomID int, overbooking domain.Overbooking) (*domain.Room, error) {
+	if m.setOverbookingFn != nil {
+		return m.setOverbookingFn(ctx, ownerID, roomID, overbooking)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockInventorySvc) ListOverbookedNights(ctx context.Context, ownerID string, hotelID int, startDate time.Time, endDate time.Time) ([]*domain.OverbookedNight, error) {
+	if m.listOverbookedNightsFn != nil {
+		return m.listOverbookedNightsFn(ctx, ownerID, hotelID, startDate, endDate)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockInventorySvc) ImportInventory(ctx context.Context, ownerID string, hotelID int, src io.Reader, dryRun bool) (*domain.InventoryImport, error) {
+	if m.importInventoryFn != nil {
+		return m.importInventoryFn(ctx, ownerID, hotelID, src, dryRun)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockInventorySvc) ExportInventory(ctx context.Context, ownerID string, hotelID int, startDate time.Time, endDate time.Time, fn func(*domain.Inventory) error) error {
+	if m.exportInventoryFn != nil {
+		return m.exportInventoryFn(ctx, ownerID, hotelID, startDate, endDate, fn)
+	}
+	return fmt.Errorf("not configured")
+}
+
 func buildRoomRouter(roomSvc handler.RoomServiceInterface, invSvc handler.InventoryServiceInterface) *gin.Engine {
 	r := gin.New()
 	h := handler.NewRoomHandler(roomSvc, invSvc)
@@ -98,6 +139,11 @@ func buildRoomRouter(roomSvc handler.RoomServiceInterface, invSvc handler.Invent
 	owner.DELETE("/rooms/:id", h.DeleteRoom)
 	owner.PUT("/rooms/:id/inventory", h.SetInventory)
 	owner.GET("/rooms/:id/inventory", h.GetInventory)
+	owner.PUT("/rooms/:id/rates", h.SetRates)
+	owner.PUT("/rooms/:id/overbooking", h.SetOverbooking)
+	owner.GET("/hotels/:id/overbooked-nights", h.ListOverbookedNights)
+	owner.POST("/hotels/:id/inventory/import", h.ImportInventory)
+	owner.GET("/hotels/:id/inventory/export", h.ExportInventory)
 
 	return r
 }
@@ -108,7 +154,7 @@ func newTestRoom() *domain.Room {
 		HotelID:       1,
 		Name:          "Deluxe King",
 		Capacity:      2,
-		PricePerNight: 150.0,
+		PricePerNight: 150,
 		IsActive:      true,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
@@ -167,7 +213,7 @@ func TestRoomHandler_CreateRoom_Returns201(t *testing.T) {
 	}
 	r := buildRoomRouter(roomSvc, &mockInventorySvc{})
 
-	body := strings.NewReader(`{"name":"Deluxe King","capacity":2,"price_per_night":150.0}`)
+	body := strings.NewReader(`{"name":"Deluxe King","capacity":2,"price_per_night":15000}`)
 	w := makeHotelRequest(r, http.MethodPost, "/api/v1/owner/hotels/1/rooms", body)
 
 	if w.Code != http.StatusCreated {
@@ -183,7 +229,7 @@ func TestRoomHandler_CreateRoom_Unauthorized_Returns403(t *testing.T) {
 	}
 	r := buildRoomRouter(roomSvc, &mockInventorySvc{})
 
-	body := strings.NewReader(`{"name":"Room","capacity":2,"price_per_night":100.0}`)
+	body := strings.NewReader(`{"name":"Room","capacity":2,"price_per_night":10000}`)
 	w := makeHotelRequest(r, http.MethodPost, "/api/v1/owner/hotels/1/rooms", body)
 
 	if w.Code != http.StatusForbidden {
@@ -214,7 +260,7 @@ func TestRoomHandler_UpdateRoom_Returns200(t *testing.T) {
 	}
 	r := buildRoomRouter(roomSvc, &mockInventorySvc{})
 
-	body := strings.NewReader(`{"name":"Updated Room","price_per_night":200.0,"is_active":true}`)
+	body := strings.NewReader(`{"name":"Updated Room","price_per_night":20000,"is_active":true}`)
 	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/rooms/5", body)
 
 	if w.Code != http.StatusOK {
@@ -347,3 +393,222 @@ func TestRoomHandler_GetInventory_InvalidStartDate_Returns400(t *testing.T) {
 		t.Errorf("expected 400, got %d", w.Code)
 	}
 }
+
+// --- Tests: PUT /owner/rooms/:id/rates ---
+
+func TestRoomHandler_SetRates_Returns200(t *testing.T) {
+	var got *domain.RatePlan
+	invSvc := &mockInventorySvc{
+		setRatePlanFn: func(ctx context.Context, ownerID string, roomID int, plan *domain.RatePlan) (*domain.RatePlan, error) {
+			got = plan
+			plan.RoomID = roomID
+			plan.BasePrice = 15000
+			plan.Currency = "USD"
+			return plan, nil
+		},
+	}
+	r := buildRoomRouter(&mockRoomSvc{}, invSvc)
+
+	body := strings.NewReader(`{
+		"weekend_price": 18000,
+		"min_stay": 2,
+		"seasons": [{"name": "High season", "start_date": "2026-12-20", "end_date": "2027-01-05", "price": 25000}],
+		"overrides": [{"date": "2026-12-31", "price": 40000, "min_stay": 3}]
+	}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/rooms/5/rates", body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got == nil || len(got.Seasons) != 1 || len(got.Overrides) != 1 || got.MinStay != 2 {
+		t.Fatalf("unexpected plan passed to service: %+v", got)
+	}
+	if want := time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC); !got.Seasons[0].EndDate.Equal(want) {
+		t.Errorf("expected season end %v, got %v", want, got.Seasons[0].EndDate)
+	}
+	if !strings.Contains(w.Body.String(), `"date":"2026-12-31"`) {
+		t.Errorf("expected override in body, got %s", w.Body.String())
+	}
+}
+
+func TestRoomHandler_SetRates_InvalidSeasonDate_Returns400(t *testing.T) {
+	r := buildRoomRouter(&mockRoomSvc{}, &mockInventorySvc{})
+
+	body := strings.NewReader(`{"seasons": [{"start_date": "20-12-2026", "end_date": "2027-01-05", "price": 25000}]}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/rooms/5/rates", body)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestRoomHandler_SetRates_NonPositivePrice_Returns400(t *testing.T) {
+	r := buildRoomRouter(&mockRoomSvc{}, &mockInventorySvc{})
+
+	body := strings.NewReader(`{"overrides": [{"date": "2026-12-31", "price": 0}]}`)
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/rooms/5/rates", body)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+// --- Tests: Overbooking ---
+
+func TestRoomHandler_SetOverbooking_Returns200(t *testing.T) {
+	var got domain.Overbooking
+	invSvc := &mockInventorySvc{
+		setOverbookingFn: func(_ context.Context, _ string, roomID int, overbooking domain.Overbooking) (*domain.Room, error) {
+			got = overbooking
+			return &domain.Room{ID: roomID, Overbooking: overbooking}, nil
+		},
+	}
+	r := buildRoomRouter(&mockRoomSvc{}, invSvc)
+
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/rooms/5/overbooking", strings.NewReader(`{"type":"percent","value":10}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.Type != domain.OverbookingPercent || got.Value != 10 {
+		t.Errorf("unexpected allowance passed to service: %+v", got)
+	}
+	if !strings.Contains(w.Body.String(), `"room_id":5,"type":"percent","value":10`) {
+		t.Errorf("expected the allowance in body, got %s", w.Body.String())
+	}
+}
+
+func TestRoomHandler_SetOverbooking_UnknownType_Returns400(t *testing.T) {
+	r := buildRoomRouter(&mockRoomSvc{}, &mockInventorySvc{})
+
+	w := makeHotelRequest(r, http.MethodPut, "/api/v1/owner/rooms/5/overbooking", strings.NewReader(`{"type":"rooms","value":2}`))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestRoomHandler_ListOverbookedNights_Returns200(t *testing.T) {
+	var gotHotel int
+	invSvc := &mockInventorySvc{
+		listOverbookedNightsFn: func(_ context.Context, _ string, hotelID int, startDate, _ time.Time) ([]*domain.OverbookedNight, error) {
+			gotHotel = hotelID
+			return []*domain.OverbookedNight{
+				{RoomID: 5, RoomName: "Double", Date: startDate, TotalInventory: 10, BookedCount: 12},
+			}, nil
+		},
+	}
+	r := buildRoomRouter(&mockRoomSvc{}, invSvc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/owner/hotels/1/overbooked-nights?start_date=2026-07-01&end_date=2026-08-01", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotHotel != 1 {
+		t.Errorf("expected hotel 1, got %d", gotHotel)
+	}
+	if !strings.Contains(w.Body.String(), `"date":"2026-07-01","total_inventory":10,"booked_count":12,"overbooked":2`) {
+		t.Errorf("expected the overbooked night in body, got %s", w.Body.String())
+	}
+}
+
+func TestRoomHandler_ListOverbookedNights_NotOwner_Returns403(t *testing.T) {
+	invSvc := &mockInventorySvc{
+		listOverbookedNightsFn: func(context.Context, string, int, time.Time, time.Time) ([]*domain.OverbookedNight, error) {
+			return nil, fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
+		},
+	}
+	r := buildRoomRouter(&mockRoomSvc{}, invSvc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/owner/hotels/1/overbooked-nights?start_date=2026-07-01&end_date=2026-08-01", nil)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestRoomHandler_ImportInventory_DryRun_Returns200(t *testing.T) {
+	var gotCSV string
+	var gotDryRun bool
+	invSvc := &mockInventorySvc{
+		importInventoryFn: func(_ context.Context, _ string, hotelID int, src io.Reader, dryRun bool) (*domain.InventoryImport, error) {
+			raw, _ := io.ReadAll(src)
+			gotCSV, gotDryRun = string(raw), dryRun
+			return &domain.InventoryImport{DryRun: dryRun, Rows: 1, Errors: []*domain.InventoryRowError{}}, nil
+		},
+	}
+	r := buildRoomRouter(&mockRoomSvc{}, invSvc)
+
+	body := "room_id,date,total\n5,2026-07-01,4\n"
+	w := makeHotelRequest(r, http.MethodPost, "/api/v1/owner/hotels/1/inventory/import?dry_run=true", strings.NewReader(body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotCSV != body || !gotDryRun {
+		t.Errorf("expected the csv passed on as a dry run, got %q dry_run=%v", gotCSV, gotDryRun)
+	}
+}
+
+func TestRoomHandler_ImportInventory_RowErrors_Returns422(t *testing.T) {
+	invSvc := &mockInventorySvc{
+		importInventoryFn: func(context.Context, string, int, io.Reader, bool) (*domain.InventoryImport, error) {
+			return &domain.InventoryImport{Rows: 2, Errors: []*domain.InventoryRowError{
+				{Line: 3, Column: "total", Message: "must be a non-negative integer"},
+			}}, nil
+		},
+	}
+	r := buildRoomRouter(&mockRoomSvc{}, invSvc)
+
+	w := makeHotelRequest(r, http.MethodPost, "/api/v1/owner/hotels/1/inventory/import",
+		strings.NewReader("room_id,date,total\n5,2026-07-01,4\n5,2026-07-02,-1\n"))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"line":3,"column":"total"`) {
+		t.Errorf("expected the row error in body, got %s", w.Body.String())
+	}
+}
+
+func TestRoomHandler_ExportInventory_StreamsCSV(t *testing.T) {
+	price := int64(12000)
+	invSvc := &mockInventorySvc{
+		exportInventoryFn: func(_ context.Context, _ string, _ int, from, _ time.Time, fn func(*domain.Inventory) error) error {
+			if err := fn(&domain.Inventory{RoomID: 5, Date: from, TotalInventory: 4, BookedCount: 1, Price: &price}); err != nil {
+				return err
+			}
+			return fn(&domain.Inventory{RoomID: 5, Date: from.AddDate(0, 0, 1), TotalInventory: 4})
+		},
+	}
+	r := buildRoomRouter(&mockRoomSvc{}, invSvc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/owner/hotels/1/inventory/export?from=2026-07-01&to=2026-07-03", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("expected text/csv, got %q", ct)
+	}
+	want := "room_id,date,total,price,booked_count\n5,2026-07-01,4,12000,1\n5,2026-07-02,4,,0\n"
+	if w.Body.String() != want {
+		t.Errorf("expected %q, got %q", want, w.Body.String())
+	}
+}
+
+func TestRoomHandler_ExportInventory_NotOwner_Returns403(t *testing.T) {
+	invSvc := &mockInventorySvc{
+		exportInventoryFn: func(context.Context, string, int, time.Time, time.Time, func(*domain.Inventory) error) error {
+			return fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
+		},
+	}
+	r := buildRoomRouter(&mockRoomSvc{}, invSvc)
+
+	w := makeHotelRequest(r, http.MethodGet, "/api/v1/owner/hotels/1/inventory/export?from=2026-07-01&to=2026-07-03", nil)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
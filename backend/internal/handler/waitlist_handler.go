@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/dto/request"
+	"booking-app/internal/dto/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WaitlistServiceInterface defines what the waitlist handler needs from the service.
+type WaitlistServiceInterface interface {
+	JoinWaitlist(ctx context.Context, input domain.JoinWaitlistInput) (*domain.WaitlistEntry, error)
+	ListMyEntries(ctx context.Context, userID string, page, limit int) ([]*domain.WaitlistEntry, int, error)
+	LeaveWaitlist(ctx context.Context, id int, userID string) error
+}
+
+// WaitlistClaimer books waitlist offers.
+type WaitlistClaimer interface {
+	ClaimWaitlistOffer(ctx context.Context, input domain.ClaimWaitlistOfferInput) (*domain.Booking, error)
+}
+
+// WaitlistHandler handles HTTP requests for the waitlist of sold-out stays.
+type WaitlistHandler struct {
+	svc      WaitlistServiceInterface
+	bookings WaitlistClaimer
+}
+
+// NewWaitlistHandler creates a new WaitlistHandler.
+func NewWaitlistHandler(svc WaitlistServiceInterface, bookings WaitlistClaimer) *WaitlistHandler {
+	return &WaitlistHandler{svc: svc, bookings: bookings}
+}
+
+// JoinWaitlist handles POST /api/v1/waitlist.
+// When the stay is already free the entry comes back offered.
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	var req request.JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+		return
+	}
+
+	startDate, endDate, ok := parseDateRange(c, req.StartDate, req.EndDate)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	entry, err := h.svc.JoinWaitlist(ctx, domain.JoinWaitlistInput{
+		UserID:    getUserIDFromContext(c),
+		RoomID:    req.RoomID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Quantity:  req.Quantity,
+		Adults:    req.Adults,
+		Children:  req.Children,
+	})
+	if err != nil {
+		handleBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.OK(response.NewWaitlistEntryResponse(entry)))
+}
+
+// ListMyEntries handles GET /api/v1/waitlist.
+func (h *WaitlistHandler) ListMyEntries(c *gin.Context) {
+	page := queryIntDefault(c, "page", 1)
+	limit := queryIntDefault(c, "limit", 20)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	entries, total, err := h.svc.ListMyEntries(ctx, getUserIDFromContext(c), page, limit)
+	if err != nil {
+		handleBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OKList(
+		response.NewWaitlistEntryListResponse(entries),
+		response.Meta{Total: total, Page: page, Limit: limit, Pages: calculatePages(total, limit)},
+	))
+}
+
+// LeaveWaitlist handles DELETE /api/v1/waitlist/:id. Leaving with an open
+// offer declines it.
+func (h *WaitlistHandler) LeaveWaitlist(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid waitlist entry id"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.svc.LeaveWaitlist(ctx, id, getUserIDFromContext(c)); err != nil {
+		handleBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.OK(gin.H{"message": "left the waitlist"}))
+}
+
+// ClaimOffer handles POST /api/v1/waitlist/:id/claim. The offered stay is
+// booked as a pending booking, ready for checkout.
+func (h *WaitlistHandler) ClaimOffer(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Fail("invalid waitlist entry id"))
+		return
+	}
+
+	var req request.ClaimWaitlistOfferRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Fail(err.Error()))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	booking, err := h.bookings.ClaimWaitlistOffer(ctx, domain.ClaimWaitlistOfferInput{
+		UserID:   getUserIDFromContext(c),
+		EntryID:  id,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		handleBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.OK(response.NewBookingResponse(booking)))
+}
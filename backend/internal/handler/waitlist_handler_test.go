@@ -0,0 +1,144 @@
+package handler_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/handler"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Mock WaitlistService and WaitlistClaimer ---
+
+type mockWaitlistSvc struct {
+	joinWaitlistFn       func(ctx context.Context, input domain.JoinWaitlistInput) (*domain.WaitlistEntry, error)
+	listMyEntriesFn      func(ctx context.Context, userID string, page, limit int) ([]*domain.WaitlistEntry, int, error)
+	leaveWaitlistFn      func(ctx context.Context, id int, userID string) error
+	claimWaitlistOfferFn func(ctx context.Context, input domain.ClaimWaitlistOfferInput) (*domain.Booking, error)
+}
+
+func (m *mockWaitlistSvc) JoinWaitlist(ctx context.Context, input domain.JoinWaitlistInput) (*domain.WaitlistEntry, error) {
+	if m.joinWaitlistFn != nil {
+		return m.joinWaitlistFn(ctx, input)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func (m *mockWaitlistSvc) ListMyEntries(ctx context.Context, userID string, page, limit int) ([]*domain.WaitlistEntry, int, error) {
+	if m.listMyEntriesFn != nil {
+		return m.listMyEntriesFn(ctx, userID, page, limit)
+	}
+	return nil, 0, fmt.Errorf("not configured")
+}
+
+func (m *mockWaitlistSvc) LeaveWaitlist(ctx context.Context, id int, userID string) error {
+	if m.leaveWaitlistFn != nil {
+		return m.leaveWaitlistFn(ctx, id, userID)
+	}
+	return fmt.Errorf("not configured")
+}
+
+func (m *mockWaitlistSvc) ClaimWaitlistOffer(ctx context.Context, input domain.ClaimWaitlistOfferInput) (*domain.Booking, error) {
+	if m.claimWaitlistOfferFn != nil {
+		return m.claimWaitlistOfferFn(ctx, input)
+	}
+	return nil, fmt.Errorf("not configured")
+}
+
+func buildWaitlistRouter(svc *mockWaitlistSvc) *gin.Engine {
+	r := gin.New()
+	h := handler.NewWaitlistHandler(svc, svc)
+	g := r.Group("/api/v1/waitlist")
+	g.Use(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Next()
+	})
+	g.POST("", h.JoinWaitlist)
+	g.GET("", h.ListMyEntries)
+	g.DELETE("/:id", h.LeaveWaitlist)
+	g.POST("/:id/claim", h.ClaimOffer)
+	return r
+}
+
+// --- Tests ---
+
+func TestWaitlistHandler_JoinWaitlist_Returns201(t *testing.T) {
+	var got domain.JoinWaitlistInput
+	svc := &mockWaitlistSvc{
+		joinWaitlistFn: func(_ context.Context, input domain.JoinWaitlistInput) (*domain.WaitlistEntry, error) {
+			got = input
+			return &domain.WaitlistEntry{ID: 3, UserID: input.UserID, RoomID: input.RoomID,
+				StartDate: input.StartDate, EndDate: input.EndDate, Status: domain.WaitlistStatusWaiting}, nil
+		},
+	}
+	r := buildWaitlistRouter(svc)
+
+	body := strings.NewReader(`{"room_id":5,"start_date":"2026-07-01","end_date":"2026-07-03","adults":2}`)
+	w := makeHotelRequest(r, http.MethodPost, "/api/v1/waitlist", body)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.UserID != "user-1" || got.RoomID != 5 || got.Adults != 2 || !got.EndDate.Equal(time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected input passed to service: %+v", got)
+	}
+	if !strings.Contains(w.Body.String(), `"status":"waiting"`) {
+		t.Errorf("expected waiting entry in body, got %s", w.Body.String())
+	}
+}
+
+func TestWaitlistHandler_JoinWaitlist_AlreadyWaiting_Returns409(t *testing.T) {
+	svc := &mockWaitlistSvc{
+		joinWaitlistFn: func(context.Context, domain.JoinWaitlistInput) (*domain.WaitlistEntry, error) {
+			return nil, fmt.Errorf("already on the waitlist for this stay: %w", domain.ErrConflict)
+		},
+	}
+	r := buildWaitlistRouter(svc)
+
+	body := strings.NewReader(`{"room_id":5,"start_date":"2026-07-01","end_date":"2026-07-03"}`)
+	w := makeHotelRequest(r, http.MethodPost, "/api/v1/waitlist", body)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestWaitlistHandler_ClaimOffer_Returns201(t *testing.T) {
+	var got domain.ClaimWaitlistOfferInput
+	svc := &mockWaitlistSvc{
+		claimWaitlistOfferFn: func(_ context.Context, input domain.ClaimWaitlistOfferInput) (*domain.Booking, error) {
+			got = input
+			return &domain.Booking{ID: 77, UserID: input.UserID, RoomID: 5, Status: domain.BookingStatusPending}, nil
+		},
+	}
+	r := buildWaitlistRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodPost, "/api/v1/waitlist/4/claim", strings.NewReader(`{"currency":"usd"}`))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.UserID != "user-1" || got.EntryID != 4 || got.Currency != "usd" {
+		t.Errorf("unexpected input passed to service: %+v", got)
+	}
+}
+
+func TestWaitlistHandler_ClaimOffer_Expired_Returns409(t *testing.T) {
+	svc := &mockWaitlistSvc{
+		claimWaitlistOfferFn: func(context.Context, domain.ClaimWaitlistOfferInput) (*domain.Booking, error) {
+			return nil, fmt.Errorf("waitlist offer expired: %w", domain.ErrConflict)
+		},
+	}
+	r := buildWaitlistRouter(svc)
+
+	w := makeHotelRequest(r, http.MethodPost, "/api/v1/waitlist/4/claim", nil)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d", w.Code)
+	}
+}
@@ -113,6 +113,16 @@ func (h *Hub) Broadcast(userID string, msg []byte) {
 	}
 }
 
+// Push sends a WSMessage of msgType to all active connections for userID.
+// It lets services push to clients without depending on the handler package.
+func (h *Hub) Push(userID, msgType string, payload map[string]any) {
+	raw, err := json.Marshal(WSMessage{Type: msgType, Payload: payload})
+	if err != nil {
+		return
+	}
+	h.Broadcast(userID, raw)
+}
+
 // BroadcastAll sends msg to every currently connected user.
 func (h *Hub) BroadcastAll(msg []byte) {
 	h.mu.RLock()
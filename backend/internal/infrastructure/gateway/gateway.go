@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned when the gateway did not answer in time. The outcome
+// of the operation is unknown; retrying with the same idempotency key is safe.
+var ErrTimeout = errors.New("payment gateway timeout")
+
+// DeclineCode is the machine-readable reason a gateway declined an operation.
+type DeclineCode string
+
+const (
+	DeclineCodeCardDeclined      DeclineCode = "card_declined"
+	DeclineCodeInsufficientFunds DeclineCode = "insufficient_funds"
+	DeclineCodeExpiredCard       DeclineCode = "expired_card"
+	DeclineCodeIncorrectCVC      DeclineCode = "incorrect_cvc"
+	DeclineCodeFraudSuspected    DeclineCode = "fraud_suspected"
+	DeclineCodeProcessingError   DeclineCode = "processing_error"
+	DeclineCodeRefundRejected    DeclineCode = "refund_rejected"
+)
+
+// AuthorizeRequest asks the gateway to hold an amount on a payment method.
+// Amounts are in minor units of Currency.
+type AuthorizeRequest struct {
+	// PaymentMethod is the card token (or, for the scripted gateway, a test
+	// card number) the customer checked out with.
+	PaymentMethod  string
+	Amount         int64
+	Currency       string
+	IdempotencyKey string
+}
+
+// RefundRequest asks the gateway to return part or all of a captured charge.
+type RefundRequest struct {
+	// ChargeRef is the gateway reference of the captured charge.
+	ChargeRef      string
+	Amount         int64
+	Currency       string
+	IdempotencyKey string
+}
+
+// Result is the gateway's answer to an operation. A declined operation is a
+// Result with Approved false, not an error; errors mean the outcome is unknown.
+type Result struct {
+	Approved bool
+	// Ref is the gateway reference of the authorization, capture or refund.
+	Ref         string
+	DeclineCode DeclineCode
+	Message     string
+}
+
+// Reason describes a declined result for failed_reason columns and events.
+func (r *Result) Reason() string {
+	if r.Message == "" {
+		return string(r.DeclineCode)
+	}
+	return fmt.Sprintf("%s: %s", r.DeclineCode, r.Message)
+}
+
+// PaymentGateway moves money through a payment provider.
+type PaymentGateway interface {
+	// Authorize places a hold for the amount on the payment method.
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Result, error)
+	// Capture collects a previously authorized amount.
+	Capture(ctx context.Context, authRef string, amount int64) (*Result, error)
+	// Void releases an authorization that will not be captured.
+	Void(ctx context.Context, authRef string) (*Result, error)
+	// Refund returns money from a captured charge.
+	Refund(ctx context.Context, req RefundRequest) (*Result, error)
+}
+
+// Config selects and configures the gateway used by the payment service.
+type Config struct {
+	// Provider is "scripted" (default) or "http".
+	Provider string
+	BaseURL  string
+	APIKey   string
+	Timeout  time.Duration
+}
+
+// New builds the gateway described by cfg.
+func New(cfg Config) (PaymentGateway, error) {
+	switch cfg.Provider {
+	case "", "scripted":
+		return NewScriptedGateway(), nil
+	case "http":
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("http payment gateway requires a base URL")
+		}
+		return NewHTTPGateway(cfg.BaseURL, cfg.APIKey, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown payment gateway provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// HTTPGateway talks to a payment provider's JSON API:
+//
+//	POST /v1/authorizations               {payment_method, amount, currency}
+//	POST /v1/authorizations/{ref}/capture {amount}
+//	POST /v1/authorizations/{ref}/void
+//	POST /v1/refunds                      {charge_ref, amount, currency}
+//
+// Amounts are sent in minor units. Every operation answers with
+// {id, status: "approved"|"declined", decline_code, message}; a declined
+// operation may use HTTP 402.
+type HTTPGateway struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewHTTPGateway creates an HTTPGateway. A zero timeout uses 10s.
+func NewHTTPGateway(baseURL, apiKey string, timeout time.Duration) *HTTPGateway {
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	return &HTTPGateway{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type httpGatewayResponse struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	DeclineCode string `json:"decline_code"`
+	Message     string `json:"message"`
+}
+
+// Authorize implements PaymentGateway.
+func (g *HTTPGateway) Authorize(ctx context.Context, req AuthorizeRequest) (*Result, error) {
+	body := map[string]any{
+		"payment_method": req.PaymentMethod,
+		"amount":         req.Amount,
+		"currency":       req.Currency,
+	}
+	return g.post(ctx, "/v1/authorizations", req.IdempotencyKey, body)
+}
+
+// Capture implements PaymentGateway.
+func (g *HTTPGateway) Capture(ctx context.Context, authRef string, amount int64) (*Result, error) {
+	path := "/v1/authorizations/" + url.PathEscape(authRef) + "/capture"
+	return g.post(ctx, path, "capture:"+authRef, map[string]any{"amount": amount})
+}
+
+// Void implements PaymentGateway.
+func (g *HTTPGateway) Void(ctx context.Context, authRef string) (*Result, error) {
+	path := "/v1/authorizations/" + url.PathEscape(authRef) + "/void"
+	return g.post(ctx, path, "void:"+authRef, map[string]any{})
+}
+
+// Refund implements PaymentGateway.
+func (g *HTTPGateway) Refund(ctx context.Context, req RefundRequest) (*Result, error) {
+	body := map[string]any{
+		"charge_ref": req.ChargeRef,
+		"amount":     req.Amount,
+		"currency":   req.Currency,
+	}
+	return g.post(ctx, "/v1/refunds", req.IdempotencyKey, body)
+}
+
+func (g *HTTPGateway) post(ctx context.Context, path, idempotencyKey string, body any) (*Result, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal gateway request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+path, bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("build gateway request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return nil, fmt.Errorf("POST %s: %w", path, ErrTimeout)
+		}
+		return nil, fmt.Errorf("POST %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusPaymentRequired {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("POST %s: gateway returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var out httpGatewayResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode gateway response: %w", err)
+	}
+	switch out.Status {
+	case "approved":
+		return &Result{Approved: true, Ref: out.ID}, nil
+	case "declined":
+		code := DeclineCode(out.DeclineCode)
+		if code == "" {
+			code = DeclineCodeCardDeclined
+		}
+		return &Result{Ref: out.ID, DeclineCode: code, Message: out.Message}, nil
+	default:
+		return nil, fmt.Errorf("gateway returned unknown status %q", out.Status)
+	}
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
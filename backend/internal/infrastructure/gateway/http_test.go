@@ -0,0 +1,129 @@
+package gateway_test
+
+import (
+	"booking-app/internal/infrastructure/gateway"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// providerStub is a local stand-in for the provider API.
+type providerStub struct {
+	t        *testing.T
+	path     string
+	body     map[string]any
+	idemKey  string
+	auth     string
+	status   int
+	response string
+}
+
+func (p *providerStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.path = r.URL.Path
+	p.idemKey = r.Header.Get("Idempotency-Key")
+	p.auth = r.Header.Get("Authorization")
+	if err := json.NewDecoder(r.Body).Decode(&p.body); err != nil {
+		p.t.Errorf("decode request body: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(p.status)
+	_, _ = w.Write([]byte(p.response))
+}
+
+func newProvider(t *testing.T, status int, response string) (*providerStub, *gateway.HTTPGateway) {
+	stub := &providerStub{t: t, status: status, response: response}
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	return stub, gateway.NewHTTPGateway(srv.URL+"/", "sk_test", time.Second)
+}
+
+func TestHTTPGateway_Authorize_Approved(t *testing.T) {
+	stub, gw := newProvider(t, http.StatusOK, `{"id":"auth_123","status":"approved"}`)
+
+	res, err := gw.Authorize(context.Background(), gateway.AuthorizeRequest{
+		PaymentMethod: "tok_visa", Amount: 12050, Currency: "USD", IdempotencyKey: "booking-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Approved || res.Ref != "auth_123" {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if stub.path != "/v1/authorizations" {
+		t.Errorf("unexpected path %q", stub.path)
+	}
+	if stub.idemKey != "booking-1" || stub.auth != "Bearer sk_test" {
+		t.Errorf("unexpected headers: key=%q auth=%q", stub.idemKey, stub.auth)
+	}
+	if stub.body["amount"] != float64(12050) || stub.body["payment_method"] != "tok_visa" {
+		t.Errorf("unexpected request body %v", stub.body)
+	}
+}
+
+func TestHTTPGateway_Authorize_DeclinedWith402(t *testing.T) {
+	_, gw := newProvider(t, http.StatusPaymentRequired,
+		`{"id":"auth_9","status":"declined","decline_code":"insufficient_funds","message":"not enough"}`)
+
+	res, err := gw.Authorize(context.Background(), gateway.AuthorizeRequest{Amount: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Approved || res.DeclineCode != gateway.DeclineCodeInsufficientFunds {
+		t.Errorf("expected insufficient_funds decline, got %+v", res)
+	}
+	if res.Reason() != "insufficient_funds: not enough" {
+		t.Errorf("unexpected reason %q", res.Reason())
+	}
+}
+
+func TestHTTPGateway_CaptureVoidRefundPaths(t *testing.T) {
+	stub, gw := newProvider(t, http.StatusOK, `{"id":"x","status":"approved"}`)
+	ctx := context.Background()
+
+	if _, err := gw.Capture(ctx, "auth_1", 2000); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if stub.path != "/v1/authorizations/auth_1/capture" || stub.body["amount"] != float64(2000) {
+		t.Errorf("unexpected capture request %q %v", stub.path, stub.body)
+	}
+
+	if _, err := gw.Void(ctx, "auth_1"); err != nil {
+		t.Fatalf("void: %v", err)
+	}
+	if stub.path != "/v1/authorizations/auth_1/void" {
+		t.Errorf("unexpected void path %q", stub.path)
+	}
+
+	if _, err := gw.Refund(ctx, gateway.RefundRequest{ChargeRef: "ch_1", Amount: 5, IdempotencyKey: "refund:1"}); err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if stub.path != "/v1/refunds" || stub.body["charge_ref"] != "ch_1" || stub.idemKey != "refund:1" {
+		t.Errorf("unexpected refund request %q %v key=%q", stub.path, stub.body, stub.idemKey)
+	}
+}
+
+func TestHTTPGateway_ServerErrorIsError(t *testing.T) {
+	_, gw := newProvider(t, http.StatusBadGateway, `upstream down`)
+
+	_, err := gw.Authorize(context.Background(), gateway.AuthorizeRequest{Amount: 10})
+	if err == nil || errors.Is(err, gateway.ErrTimeout) {
+		t.Errorf("expected non-timeout error, got %v", err)
+	}
+}
+
+func TestHTTPGateway_SlowProviderTimesOut(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+	gw := gateway.NewHTTPGateway(srv.URL, "", 20*time.Millisecond)
+
+	_, err := gw.Authorize(context.Background(), gateway.AuthorizeRequest{Amount: 10})
+	if !errors.Is(err, gateway.ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
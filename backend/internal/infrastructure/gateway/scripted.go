@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // references only need to be stable, not secret
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Test cards understood by the scripted gateway. Any other payment method,
+// including an empty one, is approved.
+const (
+	TestCardApproved          = "4242424242424242"
+	TestCardDeclined          = "4000000000000002"
+	TestCardInsufficientFunds = "4000000000009995"
+	TestCardExpired           = "4000000000000069"
+	TestCardIncorrectCVC      = "4000000000000127"
+	TestCardFraudSuspected    = "4100000000000019"
+	TestCardProcessingError   = "4000000000000119"
+	TestCardTimeout           = "4000000000000408"
+	// TestCardCaptureDeclined authorizes but declines the capture.
+	TestCardCaptureDeclined = "4000000000000341"
+	// TestCardRefundRejected charges normally but rejects every refund.
+	TestCardRefundRejected = "4000000000005126"
+)
+
+// Amounts whose last two minor-unit digits select an outcome regardless of
+// the card, so a whole flow can be scripted from the booking price alone.
+const (
+	// AmountCentsInsufficientFunds declines the authorization (e.g. 12002).
+	AmountCentsInsufficientFunds = 2
+	// AmountCentsTimeout times out the authorization (e.g. 12008).
+	AmountCentsTimeout = 8
+	// AmountCentsRefundRejected rejects a refund of that amount (e.g. 4013).
+	AmountCentsRefundRejected = 13
+)
+
+// Scripts carried in references, so a capture or refund days later, or in
+// another process, follows the card it was authorized with.
+const (
+	scriptCaptureDeclined = "cd"
+	scriptRefundRejected  = "rr"
+)
+
+var scriptedCardScripts = map[string]string{
+	TestCardCaptureDeclined: scriptCaptureDeclined,
+	TestCardRefundRejected:  scriptRefundRejected,
+}
+
+var scriptedCardDeclines = map[string]DeclineCode{
+	TestCardDeclined:          DeclineCodeCardDeclined,
+	TestCardInsufficientFunds: DeclineCodeInsufficientFunds,
+	TestCardExpired:           DeclineCodeExpiredCard,
+	TestCardIncorrectCVC:      DeclineCodeIncorrectCVC,
+	TestCardFraudSuspected:    DeclineCodeFraudSuspected,
+	TestCardProcessingError:   DeclineCodeProcessingError,
+}
+
+// ScriptedGateway is a deterministic, stateless PaymentGateway for
+// development, staging and end-to-end tests. The outcome of each call depends
+// only on the payment method and amount, and references are derived from the
+// idempotency key, so replaying a booking yields the same result every time.
+// A card whose script affects later captures or refunds is recorded in the
+// references themselves, e.g. sg_auth_cd_<hash>.
+type ScriptedGateway struct{}
+
+// NewScriptedGateway creates a ScriptedGateway.
+func NewScriptedGateway() *ScriptedGateway {
+	return &ScriptedGateway{}
+}
+
+// Authorize approves unless the card or amount scripts a decline or timeout.
+func (g *ScriptedGateway) Authorize(_ context.Context, req AuthorizeRequest) (*Result, error) {
+	if req.PaymentMethod == TestCardTimeout || cents(req.Amount) == AmountCentsTimeout {
+		return nil, ErrTimeout
+	}
+	if code, ok := scriptedCardDeclines[req.PaymentMethod]; ok {
+		return declined(code, "declined by scripted gateway"), nil
+	}
+	if cents(req.Amount) == AmountCentsInsufficientFunds {
+		return declined(DeclineCodeInsufficientFunds, "declined by scripted gateway"), nil
+	}
+
+	ref := scriptedRef("auth", scriptedCardScripts[req.PaymentMethod], req.IdempotencyKey)
+	return &Result{Approved: true, Ref: ref}, nil
+}
+
+// Capture approves unless the authorization was made with TestCardCaptureDeclined.
+func (g *ScriptedGateway) Capture(_ context.Context, authRef string, _ int64) (*Result, error) {
+	script := refScript(authRef)
+	if script == scriptCaptureDeclined {
+		return declined(DeclineCodeCardDeclined, "capture declined by scripted gateway"), nil
+	}
+	return &Result{Approved: true, Ref: scriptedRef("ch", script, authRef)}, nil
+}
+
+// Void always succeeds.
+func (g *ScriptedGateway) Void(_ context.Context, authRef string) (*Result, error) {
+	return &Result{Approved: true, Ref: scriptedRef("void", "", authRef)}, nil
+}
+
+// Refund approves unless the card or amount scripts a rejection.
+func (g *ScriptedGateway) Refund(_ context.Context, req RefundRequest) (*Result, error) {
+	if refScript(req.ChargeRef) == scriptRefundRejected || cents(req.Amount) == AmountCentsRefundRejected {
+		return declined(DeclineCodeRefundRejected, "refund rejected by scripted gateway"), nil
+	}
+	return &Result{Approved: true, Ref: scriptedRef("re", "", req.IdempotencyKey)}, nil
+}
+
+func declined(code DeclineCode, message string) *Result {
+	return &Result{DeclineCode: code, Message: message}
+}
+
+// scriptedRef derives a stable reference from seed, tagged with script when
+// the card has one.
+func scriptedRef(kind, script, seed string) string {
+	sum := sha1.Sum([]byte(kind + ":" + seed)) //nolint:gosec
+	if script == "" {
+		return fmt.Sprintf("sg_%s_%s", kind, hex.EncodeToString(sum[:8]))
+	}
+	return fmt.Sprintf("sg_%s_%s_%s", kind, script, hex.EncodeToString(sum[:8]))
+}
+
+// refScript returns the script tag of a reference made by scriptedRef, or ""
+// for untagged and foreign references.
+func refScript(ref string) string {
+	parts := strings.Split(ref, "_")
+	if len(parts) != 4 || parts[0] != "sg" {
+		return ""
+	}
+	return parts[2]
+}
+
+// cents returns the last two digits of amount in minor units.
+func cents(amount int64) int {
+	return int(amount % 100)
+}
@@ -0,0 +1,132 @@
+package gateway_test
+
+import (
+	"booking-app/internal/infrastructure/gateway"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScriptedGateway_Authorize_CardSelectsOutcome(t *testing.T) {
+	tests := []struct {
+		card     string
+		approved bool
+		code     gateway.DeclineCode
+	}{
+		{gateway.TestCardApproved, true, ""},
+		{"", true, ""},
+		{gateway.TestCardDeclined, false, gateway.DeclineCodeCardDeclined},
+		{gateway.TestCardInsufficientFunds, false, gateway.DeclineCodeInsufficientFunds},
+		{gateway.TestCardExpired, false, gateway.DeclineCodeExpiredCard},
+		{gateway.TestCardFraudSuspected, false, gateway.DeclineCodeFraudSuspected},
+	}
+	gw := gateway.NewScriptedGateway()
+	for _, tt := range tests {
+		t.Run(tt.card, func(t *testing.T) {
+			res, err := gw.Authorize(context.Background(), gateway.AuthorizeRequest{
+				PaymentMethod: tt.card, Amount: 12000, Currency: "USD", IdempotencyKey: "booking-1",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.Approved != tt.approved || res.DeclineCode != tt.code {
+				t.Errorf("expected approved=%v code=%q, got %+v", tt.approved, tt.code, res)
+			}
+		})
+	}
+}
+
+func TestScriptedGateway_Authorize_AmountSelectsOutcome(t *testing.T) {
+	gw := gateway.NewScriptedGateway()
+
+	res, err := gw.Authorize(context.Background(), gateway.AuthorizeRequest{Amount: 12002, IdempotencyKey: "k"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Approved || res.DeclineCode != gateway.DeclineCodeInsufficientFunds {
+		t.Errorf("expected insufficient_funds, got %+v", res)
+	}
+
+	_, err = gw.Authorize(context.Background(), gateway.AuthorizeRequest{Amount: 12008, IdempotencyKey: "k"})
+	if !errors.Is(err, gateway.ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestScriptedGateway_ReferencesAreReproducible(t *testing.T) {
+	req := gateway.AuthorizeRequest{PaymentMethod: gateway.TestCardApproved, Amount: 8000, IdempotencyKey: "booking-7"}
+
+	first, _ := gateway.NewScriptedGateway().Authorize(context.Background(), req)
+	second, _ := gateway.NewScriptedGateway().Authorize(context.Background(), req)
+	if first.Ref == "" || first.Ref != second.Ref {
+		t.Errorf("expected the same reference for the same key, got %q and %q", first.Ref, second.Ref)
+	}
+}
+
+func TestScriptedGateway_CaptureDeclinedCard(t *testing.T) {
+	auth, _ := gateway.NewScriptedGateway().Authorize(context.Background(), gateway.AuthorizeRequest{
+		PaymentMethod: gateway.TestCardCaptureDeclined, Amount: 5000, IdempotencyKey: "k",
+	})
+	if !auth.Approved {
+		t.Fatalf("expected authorization approved, got %+v", auth)
+	}
+
+	// Captured by another gateway instance, as after a worker restart.
+	res, err := gateway.NewScriptedGateway().Capture(context.Background(), auth.Ref, 5000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Approved {
+		t.Error("expected capture declined")
+	}
+}
+
+func TestScriptedGateway_Refund(t *testing.T) {
+	charge := func(card string) string {
+		gw := gateway.NewScriptedGateway()
+		auth, _ := gw.Authorize(context.Background(), gateway.AuthorizeRequest{PaymentMethod: card, Amount: 10000, IdempotencyKey: card})
+		ch, _ := gw.Capture(context.Background(), auth.Ref, 10000)
+		return ch.Ref
+	}
+	// Refunded by another gateway instance than the one that charged.
+	gw := gateway.NewScriptedGateway()
+
+	tests := []struct {
+		name      string
+		chargeRef string
+		amount    int64
+		approved  bool
+	}{
+		{"approved", charge(gateway.TestCardApproved), 4000, true},
+		{"card rejects refunds", charge(gateway.TestCardRefundRejected), 4000, false},
+		{"amount rejects refund", charge(gateway.TestCardApproved), 4013, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := gw.Refund(context.Background(), gateway.RefundRequest{ChargeRef: tt.chargeRef, Amount: tt.amount, IdempotencyKey: "r"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.Approved != tt.approved {
+				t.Errorf("expected approved=%v, got %+v", tt.approved, res)
+			}
+			if !tt.approved && res.DeclineCode != gateway.DeclineCodeRefundRejected {
+				t.Errorf("expected refund_rejected, got %q", res.DeclineCode)
+			}
+		})
+	}
+}
+
+func TestNew_SelectsProvider(t *testing.T) {
+	if gw, err := gateway.New(gateway.Config{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if _, ok := gw.(*gateway.ScriptedGateway); !ok {
+		t.Errorf("expected scripted gateway by default, got %T", gw)
+	}
+	if _, err := gateway.New(gateway.Config{Provider: "http"}); err == nil {
+		t.Error("expected error for http gateway without base URL")
+	}
+	if _, err := gateway.New(gateway.Config{Provider: "carrier-pigeon"}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
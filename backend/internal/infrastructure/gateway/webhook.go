@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Webhook event types a provider sends once an operation settles.
+const (
+	WebhookPaymentSucceeded = "payment.succeeded"
+	WebhookPaymentFailed    = "payment.failed"
+	WebhookRefundSucceeded  = "refund.succeeded"
+	WebhookRefundFailed     = "refund.failed"
+)
+
+// WebhookEvent is a provider notification about an operation:
+//
+//	{"id": "evt_1", "type": "payment.succeeded",
+//	 "data": {"id": ref, "idempotency_key": key, "decline_code": ..., "message": ...}}
+//
+// IdempotencyKey is the key we sent with the original request, which is how
+// the event is matched to a payment or refund.
+type WebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Ref            string `json:"id"`
+		IdempotencyKey string `json:"idempotency_key"`
+		DeclineCode    string `json:"decline_code"`
+		Message        string `json:"message"`
+	} `json:"data"`
+}
+
+// Result converts the event into the Result the operation would have
+// returned synchronously.
+func (e *WebhookEvent) Result() *Result {
+	if e.Type == WebhookPaymentSucceeded || e.Type == WebhookRefundSucceeded {
+		return &Result{Approved: true, Ref: e.Data.Ref}
+	}
+	code := DeclineCode(e.Data.DeclineCode)
+	if code == "" {
+		code = DeclineCodeCardDeclined
+	}
+	return &Result{Ref: e.Data.Ref, DeclineCode: code, Message: e.Data.Message}
+}
+
+// ParseWebhookEvent decodes a webhook body. The event ID and idempotency key
+// are required.
+func ParseWebhookEvent(body []byte) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("decode webhook event: %w", err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, fmt.Errorf("webhook event is missing id or type")
+	}
+	if event.Data.IdempotencyKey == "" {
+		return nil, fmt.Errorf("webhook event %s is missing data.idempotency_key", event.ID)
+	}
+	return &event, nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of body under secret.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether signature, the hex HMAC-SHA256 of
+// body optionally prefixed with "sha256=", was made with secret. The
+// comparison is constant-time.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	want, _ := hex.DecodeString(SignWebhook(secret, body))
+	return hmac.Equal(got, want)
+}
@@ -0,0 +1,73 @@
+package gateway_test
+
+import (
+	"booking-app/internal/infrastructure/gateway"
+	"testing"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded"}`)
+	sig := gateway.SignWebhook("whsec", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"valid", "whsec", body, sig, true},
+		{"valid with prefix", "whsec", body, "sha256=" + sig, true},
+		{"wrong secret", "other", body, sig, false},
+		{"tampered body", "whsec", []byte(`{"id":"evt_2"}`), sig, false},
+		{"not hex", "whsec", body, "zz", false},
+		{"missing signature", "whsec", body, "", false},
+		{"missing secret", "", body, sig, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gateway.VerifyWebhookSignature(tt.secret, tt.body, tt.signature); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	event, err := gateway.ParseWebhookEvent([]byte(`{"id":"evt_1","type":"payment.failed",
+		"data":{"id":"ch_1","idempotency_key":"booking-1","decline_code":"insufficient_funds","message":"low balance"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ID != "evt_1" || event.Type != gateway.WebhookPaymentFailed || event.Data.IdempotencyKey != "booking-1" {
+		t.Errorf("unexpected event %+v", event)
+	}
+	res := event.Result()
+	if res.Approved || res.Ref != "ch_1" || res.DeclineCode != gateway.DeclineCodeInsufficientFunds || res.Message != "low balance" {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestParseWebhookEvent_Invalid(t *testing.T) {
+	bodies := []string{
+		`not json`,
+		`{"type":"payment.succeeded","data":{"idempotency_key":"k"}}`,
+		`{"id":"evt_1","data":{"idempotency_key":"k"}}`,
+		`{"id":"evt_1","type":"payment.succeeded","data":{"id":"ch_1"}}`,
+	}
+	for _, body := range bodies {
+		if _, err := gateway.ParseWebhookEvent([]byte(body)); err == nil {
+			t.Errorf("expected error for %s", body)
+		}
+	}
+}
+
+func TestWebhookEvent_Result_DefaultsDeclineCode(t *testing.T) {
+	event, err := gateway.ParseWebhookEvent([]byte(`{"id":"evt_1","type":"refund.failed","data":{"id":"re_1","idempotency_key":"k"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := event.Result(); res.Approved || res.DeclineCode != gateway.DeclineCodeCardDeclined {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"booking-app/internal/domain"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// quoteAudience keeps quote tokens and access tokens from being accepted in
+// place of each other.
+const quoteAudience = "quote"
+
+// QuoteClaims holds a price quote signed into a quote token.
+type QuoteClaims struct {
+	Quote domain.Quote `json:"quote"`
+	jwt.RegisteredClaims
+}
+
+// QuoteSigner signs price quotes into tamper-proof tokens and verifies them.
+type QuoteSigner struct {
+	secret []byte
+}
+
+// NewQuoteSigner creates a QuoteSigner with the given HMAC secret.
+func NewQuoteSigner(secret string) *QuoteSigner {
+	return &QuoteSigner{secret: []byte(secret)}
+}
+
+// SignQuote returns a token carrying q that expires at q.ExpiresAt.
+func (s *QuoteSigner) SignQuote(q *domain.Quote) (string, error) {
+	claims := QuoteClaims{
+		Quote: *q,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{quoteAudience},
+			ExpiresAt: jwt.NewNumericDate(q.ExpiresAt),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return "", fmt.Errorf("sign quote token: %w", err)
+	}
+	return signed, nil
+}
+
+// VerifyQuote checks the signature and expiry of a quote token and returns
+// the quote it carries.
+func (s *QuoteSigner) VerifyQuote(tokenStr string) (*domain.Quote, error) {
+	if tokenStr == "" {
+		return nil, fmt.Errorf("quote token must not be empty")
+	}
+
+	parsed, err := jwt.ParseWithClaims(tokenStr, &QuoteClaims{}, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return s.secret, nil
+	}, jwt.WithAudience(quoteAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("parse quote token: %w", err)
+	}
+
+	claims, ok := parsed.Claims.(*QuoteClaims)
+	if !ok || !parsed.Valid {
+		return nil, fmt.Errorf("invalid quote token claims")
+	}
+	return &claims.Quote, nil
+}
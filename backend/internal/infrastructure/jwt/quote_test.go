@@ -0,0 +1,78 @@
+package jwt_test
+
+import (
+	"booking-app/internal/domain"
+	tokenpkg "booking-app/internal/infrastructure/jwt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleQuote(expiresAt time.Time) *domain.Quote {
+	return &domain.Quote{
+		RoomID:         5,
+		CheckIn:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		CheckOut:       time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		Quantity:       1,
+		Currency:       "THB",
+		Total:          423000,
+		ChargeCurrency: "USD",
+		FxRate:         0.0275,
+		ChargeTotal:    11633,
+		ExpiresAt:      expiresAt,
+	}
+}
+
+func TestQuoteSigner_RoundTrip(t *testing.T) {
+	signer := tokenpkg.NewQuoteSigner("quote-secret")
+	token, err := signer.SignQuote(sampleQuote(time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	q, err := signer.VerifyQuote(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.RoomID != 5 || q.Total != 423000 || q.FxRate != 0.0275 || q.ChargeTotal != 11633 {
+		t.Errorf("quote did not round-trip: %+v", q)
+	}
+}
+
+func TestQuoteSigner_RejectsTamperedToken(t *testing.T) {
+	signer := tokenpkg.NewQuoteSigner("quote-secret")
+	expiresAt := time.Now().Add(time.Hour)
+	token, _ := signer.SignQuote(sampleQuote(expiresAt))
+
+	cheaper := sampleQuote(expiresAt)
+	cheaper.Total, cheaper.ChargeTotal = 1, 1
+	forged, _ := tokenpkg.NewQuoteSigner("other-secret").SignQuote(cheaper)
+
+	// The cheaper quote's payload with the genuine token's signature.
+	sig := token[strings.LastIndex(token, ".")+1:]
+	tampered := forged[:strings.LastIndex(forged, ".")+1] + sig
+	if _, err := signer.VerifyQuote(tampered); err == nil {
+		t.Error("expected error for tampered quote")
+	}
+	if _, err := signer.VerifyQuote(forged); err == nil {
+		t.Error("expected error for quote signed with another secret")
+	}
+}
+
+func TestQuoteSigner_RejectsExpiredQuote(t *testing.T) {
+	signer := tokenpkg.NewQuoteSigner("quote-secret")
+	token, _ := signer.SignQuote(sampleQuote(time.Now().Add(-time.Minute)))
+
+	if _, err := signer.VerifyQuote(token); err == nil {
+		t.Error("expected error for expired quote")
+	}
+}
+
+func TestQuoteSigner_RejectsAccessToken(t *testing.T) {
+	mgr := tokenpkg.NewTokenManager("quote-secret", 15*time.Minute, time.Hour)
+	access, _ := mgr.GenerateAccessToken("user-123", string(domain.RoleGuest))
+
+	if _, err := tokenpkg.NewQuoteSigner("quote-secret").VerifyQuote(access); err == nil {
+		t.Error("expected an access token to be rejected as a quote token")
+	}
+}
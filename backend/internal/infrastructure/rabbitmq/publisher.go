@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"booking-app/internal/domain"
 	"context"
 	"fmt"
 	"time"
@@ -22,9 +23,19 @@ func NewPublisher(conn *Connection, logger *zap.Logger) *Publisher {
 	return &Publisher{conn: conn, logger: logger}
 }
 
-// Publish sends a message to the specified exchange with the given routing key.
+// Headers set on every published outbox event.
+const (
+	HeaderEventType     = "x-event-type"
+	HeaderAggregateType = "x-aggregate-type"
+	HeaderAggregateID   = "x-aggregate-id"
+	HeaderCorrelationID = "x-correlation-id"
+)
+
+// Publish sends an outbox event to the specified exchange with the given
+// routing key. The event's ID is the message ID, so consumers can recognise
+// redeliveries; its type, aggregate and correlation ID go in the headers.
 // It uses publisher confirms to verify delivery.
-func (p *Publisher) Publish(ctx context.Context, exchange, routingKey string, body []byte) error {
+func (p *Publisher) Publish(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
 	ch, err := p.conn.Channel()
 	if err != nil {
 		return fmt.Errorf("open channel: %w", err)
@@ -39,10 +50,19 @@ func (p *Publisher) Publish(ctx context.Context, exchange, routingKey string, bo
 	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, 1))
 
 	err = ch.PublishWithContext(ctx, exchange, routingKey, false, false, amqp.Publishing{
-		ContentType:  "application/json",
-		DeliveryMode: amqp.Persistent,
-		Timestamp:    time.Now(),
-		Body:         body,
+		ContentType:   "application/json",
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     event.ID,
+		CorrelationId: event.CorrelationID,
+		Type:          event.EventType,
+		Timestamp:     time.Now(),
+		Headers: amqp.Table{
+			HeaderEventType:     event.EventType,
+			HeaderAggregateType: event.AggregateType,
+			HeaderAggregateID:   event.AggregateID,
+			HeaderCorrelationID: event.CorrelationID,
+		},
+		Body: event.Payload,
 	})
 	if err != nil {
 		return fmt.Errorf("publish message: %w", err)
@@ -56,6 +76,7 @@ func (p *Publisher) Publish(ctx context.Context, exchange, routingKey string, bo
 		p.logger.Debug("message published",
 			zap.String("exchange", exchange),
 			zap.String("routing_key", routingKey),
+			zap.String("message_id", event.ID),
 		)
 		return nil
 	case <-ctx.Done():
@@ -97,11 +118,11 @@ func SetupTopology(ch *amqp.Channel) error {
 	}
 
 	// Notification fan-out queue: API server subscribes to broadcast via WebSocket.
-	// Binds only to result events (succeeded/failed/timed_out) — not payment.initiated.
+	// Binds only to result events (succeeded/failed/timed_out/expired) — not payment.initiated.
 	if _, err := ch.QueueDeclare("booking.notifications", true, false, false, false, args); err != nil {
 		return fmt.Errorf("declare notifications queue: %w", err)
 	}
-	for _, key := range []string{"payment.succeeded", "payment.failed", "payment.timed_out"} {
+	for _, key := range []string{"payment.succeeded", "payment.failed", "payment.timed_out", "booking.expired"} {
 		if err := ch.QueueBind("booking.notifications", key, "booking.events", false, nil); err != nil {
 			return fmt.Errorf("bind notifications queue (key=%s): %w", key, err)
 		}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AvailabilityCache wraps a Redis client and implements
+// service.AvailabilityCache. A hotel's generation is a counter that
+// invalidation increments; it never expires, while the calendars cached under
+// each generation do.
+type AvailabilityCache struct {
+	client *redis.Client
+}
+
+// NewAvailabilityCache creates an AvailabilityCache backed by the given Redis client.
+func NewAvailabilityCache(client *redis.Client) *AvailabilityCache {
+	return &AvailabilityCache{client: client}
+}
+
+// Generation returns the current generation of a hotel's calendars, zero
+// until the hotel is first invalidated.
+func (c *AvailabilityCache) Generation(ctx context.Context, hotelID int) (int64, error) {
+	gen, err := c.client.Get(ctx, generationKey(hotelID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return gen, nil
+}
+
+// Get retrieves a cached calendar. Returns (nil, false, nil) on cache miss.
+func (c *AvailabilityCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	val, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+// Set stores a calendar with the given TTL.
+func (c *AvailabilityCache) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, val, ttl).Err()
+}
+
+// Invalidate starts a new generation of a hotel's calendars.
+func (c *AvailabilityCache) Invalidate(ctx context.Context, hotelID int) error {
+	return c.client.Incr(ctx, generationKey(hotelID)).Err()
+}
+
+func generationKey(hotelID int) string {
+	return fmt.Sprintf("availability:%d:generation", hotelID)
+}
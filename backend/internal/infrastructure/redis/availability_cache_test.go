@@ -0,0 +1,48 @@
+package redis_test
+
+import (
+	redisinfra "booking-app/internal/infrastructure/redis"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAvailabilityCache_InvalidateStartsNewGeneration(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	cache := redisinfra.NewAvailabilityCache(client)
+	ctx := context.Background()
+
+	gen, err := cache.Generation(ctx, 7)
+	if err != nil || gen != 0 {
+		t.Fatalf("expected generation 0 before any invalidation, got %d (err=%v)", gen, err)
+	}
+
+	if err := cache.Set(ctx, "availability:7:0:2026-07-01:2026-07-08", []byte(`{}`), time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok, err := cache.Get(ctx, "availability:7:0:2026-07-01:2026-07-08"); err != nil || !ok {
+		t.Fatalf("expected cache hit, got ok=%v err=%v", ok, err)
+	}
+	if ttl := mr.TTL("availability:7:0:2026-07-01:2026-07-08"); ttl != time.Minute {
+		t.Errorf("expected calendar to expire in a minute, got %v", ttl)
+	}
+
+	if err := cache.Invalidate(ctx, 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gen, _ := cache.Generation(ctx, 7); gen != 1 {
+		t.Errorf("expected generation 1 after invalidation, got %d", gen)
+	}
+	if gen, _ := cache.Generation(ctx, 8); gen != 0 {
+		t.Errorf("other hotels keep their generation, got %d", gen)
+	}
+}
@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,6 +13,11 @@ import (
 type Locker interface {
 	AcquireLock(ctx context.Context, roomID int, date string) (string, error)
 	ReleaseLock(ctx context.Context, roomID int, date string, lockValue string) error
+	// AcquireLocks takes the lock for every date of a room in one atomic step.
+	// Either all keys are acquired or none are.
+	AcquireLocks(ctx context.Context, roomID int, dates []string) (string, error)
+	// ReleaseLocks releases every key taken by AcquireLocks with the same lockValue.
+	ReleaseLocks(ctx context.Context, roomID int, dates []string, lockValue string) error
 }
 
 const (
@@ -21,6 +27,31 @@ const (
 	lockMaxRetries = 10
 )
 
+// acquireAllScript sets every key in KEYS to ARGV[1] with a PX of ARGV[2],
+// but only when none of them exists. Returns 1 on success, 0 if any key is held.
+const acquireAllScript = `
+	for i = 1, #KEYS do
+		if redis.call("EXISTS", KEYS[i]) == 1 then
+			return 0
+		end
+	end
+	for i = 1, #KEYS do
+		redis.call("SET", KEYS[i], ARGV[1], "PX", ARGV[2])
+	end
+	return 1
+`
+
+// releaseAllScript deletes every key in KEYS still owned by ARGV[1].
+const releaseAllScript = `
+	local n = 0
+	for i = 1, #KEYS do
+		if redis.call("GET", KEYS[i]) == ARGV[1] then
+			n = n + redis.call("DEL", KEYS[i])
+		end
+	end
+	return n
+`
+
 // RedisLocker implements Locker using Redis SETNX.
 type RedisLocker struct {
 	client *redis.Client
@@ -71,3 +102,62 @@ func (l *RedisLocker) ReleaseLock(ctx context.Context, roomID int, date string,
 
 	return nil
 }
+
+// AcquireLocks acquires the lock keys for all given dates of a room atomically.
+// Dates are de-duplicated and sorted so every caller builds the same key list
+// in the same order. A single Lua script checks and sets all keys, so two
+// overlapping stays (e.g. 10→14 and 12→15) can never both hold their ranges.
+// Retries up to lockMaxRetries times with lockRetryDelay between attempts.
+func (l *RedisLocker) AcquireLocks(ctx context.Context, roomID int, dates []string) (string, error) {
+	keys := lockKeys(roomID, dates)
+	if len(keys) == 0 {
+		return "", fmt.Errorf("no dates to lock for room %d", roomID)
+	}
+	lockValue := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	for i := 0; i < lockMaxRetries; i++ {
+		ok, err := l.client.Eval(ctx, acquireAllScript, keys, lockValue, lockTTL.Milliseconds()).Int()
+		if err != nil {
+			return "", fmt.Errorf("redis multi-key lock failed: %w", err)
+		}
+		if ok == 1 {
+			return lockValue, nil
+		}
+		time.Sleep(lockRetryDelay)
+	}
+
+	return "", fmt.Errorf("could not acquire %d locks after %d retries: room=%d", len(keys), lockMaxRetries, roomID)
+}
+
+// ReleaseLocks releases all lock keys taken by AcquireLocks in a single round trip.
+// Keys held by another owner are left untouched.
+func (l *RedisLocker) ReleaseLocks(ctx context.Context, roomID int, dates []string, lockValue string) error {
+	keys := lockKeys(roomID, dates)
+	if len(keys) == 0 {
+		return nil
+	}
+	if _, err := l.client.Eval(ctx, releaseAllScript, keys, lockValue).Result(); err != nil {
+		return fmt.Errorf("redis multi-key lock release failed: %w", err)
+	}
+	return nil
+}
+
+// lockKeys builds the sorted, de-duplicated list of lock keys for a room's dates.
+func lockKeys(roomID int, dates []string) []string {
+	sorted := make([]string, 0, len(dates))
+	seen := make(map[string]struct{}, len(dates))
+	for _, d := range dates {
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		sorted = append(sorted, d)
+	}
+	sort.Strings(sorted)
+
+	keys := make([]string, len(sorted))
+	for i, d := range sorted {
+		keys[i] = fmt.Sprintf(lockKeyFormat, roomID, d)
+	}
+	return keys
+}
@@ -5,7 +5,12 @@ import (
 	redisinfra "booking-app/internal/infrastructure/redis"
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
 )
 
 // mockLocker is a test double for Locker.
@@ -29,6 +34,14 @@ func (m *mockLocker) ReleaseLock(_ context.Context, _ int, _ string, _ string) e
 	return m.releaseErr
 }
 
+func (m *mockLocker) AcquireLocks(ctx context.Context, roomID int, _ []string) (string, error) {
+	return m.AcquireLock(ctx, roomID, "")
+}
+
+func (m *mockLocker) ReleaseLocks(ctx context.Context, roomID int, _ []string, lockValue string) error {
+	return m.ReleaseLock(ctx, roomID, "", lockValue)
+}
+
 // Ensure mockLocker satisfies the interface at compile time.
 var _ redisinfra.Locker = (*mockLocker)(nil)
 
@@ -64,3 +77,163 @@ func TestMockLocker_Release(t *testing.T) {
 		t.Error("expected released=true")
 	}
 }
+
+// --- RedisLocker (miniredis) ---
+
+func newTestLocker(t *testing.T) (*miniredis.Miniredis, redisinfra.Locker) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, redisinfra.NewRedisLocker(client)
+}
+
+// stayDates returns the YYYY-MM-DD of every night in [checkIn, checkIn+nights).
+func stayDates(checkIn time.Time, nights int) []string {
+	dates := make([]string, nights)
+	for i := range dates {
+		dates[i] = checkIn.AddDate(0, 0, i).Format("2006-01-02")
+	}
+	return dates
+}
+
+func TestRedisLocker_AcquireLocks_SetsEveryNight(t *testing.T) {
+	mr, l := newTestLocker(t)
+	dates := stayDates(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), 4)
+
+	val, err := l.AcquireLocks(context.Background(), 7, dates)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, d := range dates {
+		got, getErr := mr.Get("lock:room:7:" + d)
+		if getErr != nil || got != val {
+			t.Errorf("expected key for %s to hold %q, got %q (err=%v)", d, val, got, getErr)
+		}
+	}
+
+	if err := l.ReleaseLocks(context.Background(), 7, dates, val); err != nil {
+		t.Fatalf("unexpected release error: %v", err)
+	}
+	for _, d := range dates {
+		if mr.Exists("lock:room:7:" + d) {
+			t.Errorf("expected key for %s to be released", d)
+		}
+	}
+}
+
+func TestRedisLocker_AcquireLocks_OverlappingRangeIsAllOrNothing(t *testing.T) {
+	mr, l := newTestLocker(t)
+	ctx := context.Background()
+
+	// Holder of 10→14 (nights 10, 11, 12, 13).
+	first := stayDates(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), 4)
+	if _, err := l.AcquireLocks(ctx, 1, first); err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+
+	// 12→15 starts on a different day but overlaps on 12 and 13.
+	second := stayDates(time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC), 3)
+	if _, err := l.AcquireLocks(ctx, 1, second); err == nil {
+		t.Fatal("expected overlapping range to fail to acquire")
+	}
+
+	// The non-overlapping night 14 must not have been left locked.
+	if mr.Exists("lock:room:1:2026-03-14") {
+		t.Error("expected partial acquisition to leave no keys behind")
+	}
+}
+
+func TestRedisLocker_ReleaseLocks_KeepsOtherOwnersKeys(t *testing.T) {
+	mr, l := newTestLocker(t)
+	ctx := context.Background()
+	dates := stayDates(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), 2)
+
+	if _, err := l.AcquireLocks(ctx, 1, dates); err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	if err := l.ReleaseLocks(ctx, 1, dates, "someone-else"); err != nil {
+		t.Fatalf("unexpected release error: %v", err)
+	}
+	if !mr.Exists("lock:room:1:2026-03-10") {
+		t.Error("expected lock held by another owner to survive release")
+	}
+}
+
+// TestRedisLocker_OverlappingStays_NoOverbooking reproduces the race between
+// two overlapping stays with different check-in dates (10→14 and 12→15) on a
+// room with a single unit. Each booking runs a check-then-increment against a
+// shared inventory while holding its locks. Locking only the check-in date
+// lets both pass and overbooks nights 12 and 13; locking every night must
+// serialise them so at most one booking succeeds.
+func TestRedisLocker_OverlappingStays_NoOverbooking(t *testing.T) {
+	_, l := newTestLocker(t)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	booked := map[string]int{}
+	const total = 1
+
+	book := func(dates []string) bool {
+		val, err := l.AcquireLocks(ctx, 1, dates)
+		if err != nil {
+			return false
+		}
+		defer func() { _ = l.ReleaseLocks(ctx, 1, dates, val) }()
+
+		mu.Lock()
+		for _, d := range dates {
+			if booked[d] >= total {
+				mu.Unlock()
+				return false
+			}
+		}
+		mu.Unlock()
+
+		// Widen the window between availability check and increment.
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		for _, d := range dates {
+			booked[d]++
+		}
+		mu.Unlock()
+		return true
+	}
+
+	stays := [][]string{
+		stayDates(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), 4),
+		stayDates(time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC), 3),
+	}
+
+	var wg sync.WaitGroup
+	results := make([]bool, len(stays))
+	for i, dates := range stays {
+		wg.Add(1)
+		go func(i int, dates []string) {
+			defer wg.Done()
+			results[i] = book(dates)
+		}(i, dates)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, ok := range results {
+		if ok {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("expected exactly one overlapping booking to succeed, got %d", succeeded)
+	}
+	for d, n := range booked {
+		if n > total {
+			t.Errorf("night %s overbooked: booked=%d total=%d", d, n, total)
+		}
+	}
+}
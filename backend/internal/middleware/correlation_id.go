@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"booking-app/internal/observability"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -9,6 +11,8 @@ const CorrelationIDHeader = "X-Correlation-ID"
 
 // CorrelationID injects a correlation ID into each request.
 // It reads from the incoming header if present, otherwise generates a new UUID.
+// The ID is also stored in the request context, so outbox events written
+// while handling the request carry it.
 func CorrelationID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.GetHeader(CorrelationIDHeader)
@@ -17,6 +21,7 @@ func CorrelationID() gin.HandlerFunc {
 		}
 		c.Set(CorrelationIDHeader, id)
 		c.Header(CorrelationIDHeader, id)
+		c.Request = c.Request.WithContext(observability.WithCorrelationID(c.Request.Context(), id))
 		c.Next()
 	}
 }
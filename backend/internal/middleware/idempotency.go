@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"booking-app/internal/dto/response"
+	"booking-app/internal/observability"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader is set on responses replayed from the store.
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize bounds the request body read into memory to be
+	// fingerprinted.
+	maxIdempotentBodySize = 1 << 20
+	// idempotencyLockTTL bounds how long a crashed request can block its key.
+	idempotencyLockTTL = time.Minute
+)
+
+// idempotencyRecord is what is stored under an idempotency key. A record
+// without a status belongs to a request still in flight.
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotency returns a Gin middleware that makes retries of a request safe.
+// Requests must carry an Idempotency-Key header; those without one are
+// rejected with 400 before the handler runs.
+//
+// The first request with a key runs normally and its response is stored for
+// ttl; retries with the same key and body get that response replayed with an
+// Idempotent-Replayed header instead of running again. Reusing a key for a
+// different method, path or body is rejected with 422, and a retry that
+// arrives while the original is still running gets 409. Server errors (5xx)
+// are not stored, so the client may retry them.
+//
+// Keys are scoped to the authenticated user, so the middleware must run after
+// JWTAuth. keyPrefix is the Redis key namespace, e.g. "idem".
+//
+// Behaviour on Redis outage: fail-open (request runs, warning is logged).
+func Idempotency(redisClient *redis.Client, ttl time.Duration, keyPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idemKey := c.GetHeader(IdempotencyKeyHeader)
+		if idemKey == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Fail("Idempotency-Key header is required"))
+			return
+		}
+		if len(idemKey) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Fail("Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response.Fail("request body too large"))
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Fail("failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		key := keyPrefix + ":" + c.GetString(contextKeyUserID) + ":" + idemKey
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		acquired, existing, err := acquireIdempotencyKey(ctx, redisClient, key, fingerprint)
+		if err != nil {
+			observability.Global().Warn("idempotency: Redis error, running request",
+				zap.String("key", key),
+				zap.Error(err),
+			)
+			c.Next()
+			return
+		}
+
+		if !acquired {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity,
+					response.Fail("Idempotency-Key was already used with a different request"))
+			case existing.Status == 0:
+				c.AbortWithStatusJSON(http.StatusConflict,
+					response.Fail("a request with this Idempotency-Key is still being processed"))
+			default:
+				c.Header(IdempotentReplayHeader, "true")
+				c.Data(existing.Status, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Store with a fresh context so a cancelled client does not leave the
+		// key locked until the lock expires.
+		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := redisClient.Del(storeCtx, key).Err(); err != nil {
+				observability.Global().Warn("idempotency: failed to release key", zap.String("key", key), zap.Error(err))
+			}
+			return
+		}
+		record := idempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := storeIdempotencyRecord(storeCtx, redisClient, key, record, ttl); err != nil {
+			observability.Global().Warn("idempotency: failed to store response", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// acquireIdempotencyKey claims key for a new request. When the key is already
+// taken it returns the stored record instead.
+func acquireIdempotencyKey(ctx context.Context, client *redis.Client, key, fingerprint string) (bool, *idempotencyRecord, error) {
+	lock, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return false, nil, err
+	}
+	acquired, err := client.SetNX(ctx, key, lock, idempotencyLockTTL).Result()
+	if err != nil || acquired {
+		return acquired, nil, err
+	}
+
+	raw, err := client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The key expired between SETNX and GET: try once more.
+		acquired, err = client.SetNX(ctx, key, lock, idempotencyLockTTL).Result()
+		if err != nil || acquired {
+			return acquired, nil, err
+		}
+		raw, err = client.Get(ctx, key).Bytes()
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	var existing idempotencyRecord
+	if err := json.Unmarshal(raw, &existing); err != nil {
+		return false, nil, err
+	}
+	return false, &existing, nil
+}
+
+// storeIdempotencyRecord saves the completed response under key.
+func storeIdempotencyRecord(ctx context.Context, client *redis.Client, key string, record idempotencyRecord, ttl time.Duration) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return client.Set(ctx, key, raw, ttl).Err()
+}
+
+// requestFingerprint identifies a request by method, path and body.
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder copies the response body while it is written.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
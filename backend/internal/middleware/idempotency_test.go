@@ -0,0 +1,228 @@
+package middleware_test
+
+import (
+	"booking-app/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// newIdempotentRouter counts how often the handler really runs.
+func newIdempotentRouter(client *redis.Client, userID string, status int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+	r.Use(middleware.Idempotency(client, time.Hour, "idem:test"))
+	handler := func(c *gin.Context) {
+		*calls++
+		c.JSON(status, gin.H{"call": *calls})
+	}
+	r.POST("/bookings", handler)
+	r.DELETE("/bookings/:id", handler)
+	return r
+}
+
+func sendIdempotent(r *gin.Engine, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusCreated, &calls)
+
+	first := sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{"room_id":1}`)
+	second := sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{"room_id":1}`)
+
+	if calls != 1 {
+		t.Errorf("expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("expected replay of %d %s, got %d %s", first.Code, first.Body, second.Code, second.Body)
+	}
+	if second.Header().Get(middleware.IdempotentReplayHeader) != "true" {
+		t.Error("expected replayed response to be marked")
+	}
+	if first.Header().Get(middleware.IdempotentReplayHeader) != "" {
+		t.Error("original response must not be marked as replayed")
+	}
+	if ct := second.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("expected JSON content type on replay, got %q", ct)
+	}
+}
+
+func TestIdempotency_DifferentBodyReturns422(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusCreated, &calls)
+
+	sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{"room_id":1}`)
+	w := sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{"room_id":2}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("expected handler to run once, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_DifferentPathReturns422(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusOK, &calls)
+
+	sendIdempotent(r, http.MethodDelete, "/bookings/1", "key-1", "")
+	w := sendIdempotent(r, http.MethodDelete, "/bookings/2", "key-1", "")
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", w.Code)
+	}
+}
+
+func TestIdempotency_KeysAreScopedPerUser(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	alice := newIdempotentRouter(client, "user-1", http.StatusCreated, &calls)
+	bob := newIdempotentRouter(client, "user-2", http.StatusCreated, &calls)
+
+	sendIdempotent(alice, http.MethodPost, "/bookings", "key-1", `{}`)
+	w := sendIdempotent(bob, http.MethodPost, "/bookings", "key-1", `{}`)
+
+	if calls != 2 || w.Header().Get(middleware.IdempotentReplayHeader) != "" {
+		t.Errorf("expected both users' requests to run, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_InFlightReturns409(t *testing.T) {
+	_, client := newTestRedis(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Idempotency(client, time.Hour, "idem:test"))
+
+	// The handler retries its own request while the original still holds the key.
+	var retry *httptest.ResponseRecorder
+	r.POST("/checkout", func(c *gin.Context) {
+		if retry == nil {
+			retry = sendIdempotent(r, http.MethodPost, "/checkout", "key-1", `{}`)
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	sendIdempotent(r, http.MethodPost, "/checkout", "key-1", `{}`)
+	if retry == nil || retry.Code != http.StatusConflict {
+		t.Errorf("expected 409 for a retry in flight, got %v", retry)
+	}
+}
+
+func TestIdempotency_ServerErrorIsNotStored(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusInternalServerError, &calls)
+
+	sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{}`)
+	sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{}`)
+
+	if calls != 2 {
+		t.Errorf("expected a 5xx to be retried, handler ran %d times", calls)
+	}
+}
+
+func TestIdempotency_ClientErrorIsStored(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusConflict, &calls)
+
+	sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{}`)
+	w := sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{}`)
+
+	if calls != 1 || w.Code != http.StatusConflict {
+		t.Errorf("expected the 409 to be replayed, ran %d times, got %d", calls, w.Code)
+	}
+}
+
+func TestIdempotency_MissingKeyReturns400(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusCreated, &calls)
+
+	for _, method := range []string{http.MethodPost, http.MethodDelete} {
+		path := "/bookings"
+		if method == http.MethodDelete {
+			path = "/bookings/1"
+		}
+		w := sendIdempotent(r, method, path, "", `{}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected 400 without a key, got %d", method, path, w.Code)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("expected the handler not to run without a key, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_KeyTooLongReturns400(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusCreated, &calls)
+
+	w := sendIdempotent(r, http.MethodPost, "/bookings", strings.Repeat("k", 256), `{}`)
+	if w.Code != http.StatusBadRequest || calls != 0 {
+		t.Errorf("expected 400 without running the handler, got %d (%d calls)", w.Code, calls)
+	}
+}
+
+func TestIdempotency_OversizedBodyReturns413(t *testing.T) {
+	_, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusCreated, &calls)
+
+	w := sendIdempotent(r, http.MethodPost, "/bookings", "key-1", strings.Repeat("x", 2<<20))
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+	if calls != 0 {
+		t.Errorf("expected handler not to run, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_StoredResponseExpires(t *testing.T) {
+	mr, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusCreated, &calls)
+
+	sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{}`)
+	mr.FastForward(2 * time.Hour)
+	sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{}`)
+
+	if calls != 2 {
+		t.Errorf("expected the key to be reusable after its TTL, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_RedisDownFailsOpen(t *testing.T) {
+	mr, client := newTestRedis(t)
+	calls := 0
+	r := newIdempotentRouter(client, "user-1", http.StatusCreated, &calls)
+	mr.Close()
+
+	w := sendIdempotent(r, http.MethodPost, "/bookings", "key-1", `{}`)
+	if w.Code != http.StatusCreated || calls != 1 {
+		t.Errorf("expected request to run when Redis is down, got %d (%d calls)", w.Code, calls)
+	}
+}
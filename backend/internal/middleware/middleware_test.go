@@ -61,6 +61,23 @@ func TestCorrelationID_PropagatesExisting(t *testing.T) {
 	}
 }
 
+func TestCorrelationID_StoredInRequestContext(t *testing.T) {
+	const existingID = "test-correlation-id-123"
+
+	r := gin.New()
+	r.Use(middleware.CorrelationID())
+	r.GET("/", func(c *gin.Context) {
+		if id := observability.CorrelationID(c.Request.Context()); id != existingID {
+			t.Errorf("expected %q in the request context, got %q", existingID, id)
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.CorrelationIDHeader, existingID)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+}
+
 func TestRecovery_CatchesPanic(t *testing.T) {
 	r := gin.New()
 	r.Use(middleware.Recovery())
@@ -8,7 +8,10 @@ import (
 
 type contextKey string
 
-const loggerKey contextKey = "logger"
+const (
+	loggerKey        contextKey = "logger"
+	correlationIDKey contextKey = "correlation_id"
+)
 
 var globalLogger *zap.Logger
 
@@ -42,6 +45,21 @@ func WithLogger(ctx context.Context, l *zap.Logger) context.Context {
 	return context.WithValue(ctx, loggerKey, l)
 }
 
+// WithCorrelationID stores the correlation ID of the request or message being
+// handled in the context.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationID returns the correlation ID stored in the context, or "".
+func CorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
 // Global returns the global logger (for use outside request context).
 func Global() *zap.Logger {
 	if globalLogger != nil {
@@ -35,3 +35,26 @@ var ActiveConnections = promauto.NewGauge(prometheus.GaugeOpts{
 	Name: "http_active_connections",
 	Help: "Number of active HTTP connections",
 })
+
+// OutboxRelayLag records how long outbox events waited between being written
+// and being published.
+var OutboxRelayLag = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name:    "outbox_relay_lag_seconds",
+	Help:    "Time from an outbox event being written to it being published",
+	Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+})
+
+// OutboxRelayBatchSize records how many events each relay pass claimed.
+var OutboxRelayBatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name:    "outbox_relay_batch_size",
+	Help:    "Number of outbox events claimed per relay pass",
+	Buckets: prometheus.ExponentialBuckets(1, 2, 7),
+})
+
+// OutboxEventsRelayedTotal counts claimed outbox events by what the relay did
+// with them: published, failed, dead_lettered or deferred (held back behind
+// a failed event of the same aggregate).
+var OutboxEventsRelayedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "outbox_events_relayed_total",
+	Help: "Total number of outbox events handled by the relay, by result",
+}, []string{"result"})
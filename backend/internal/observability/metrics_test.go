@@ -27,6 +27,15 @@ func TestMetrics_RegisteredWithoutPanic(t *testing.T) {
 	if observability.ActiveConnections == nil {
 		t.Fatal("ActiveConnections should not be nil")
 	}
+	if observability.OutboxRelayLag == nil {
+		t.Fatal("OutboxRelayLag should not be nil")
+	}
+	if observability.OutboxRelayBatchSize == nil {
+		t.Fatal("OutboxRelayBatchSize should not be nil")
+	}
+	if observability.OutboxEventsRelayedTotal == nil {
+		t.Fatal("OutboxEventsRelayedTotal should not be nil")
+	}
 }
 
 func TestHTTPRequestsTotal_Increment(t *testing.T) {
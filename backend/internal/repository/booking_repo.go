@@ -3,12 +3,17 @@ package repository
 import (
 	"booking-app/internal/domain"
 	redisinfra "booking-app/internal/infrastructure/redis"
+	"booking-app/internal/observability"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // BookingRepo handles all database operations for bookings.
@@ -27,26 +32,99 @@ func NewBookingRepo(db *sql.DB, locker redisinfra.Locker) *BookingRepo {
 }
 
 // CreateBooking implements the full booking flow with distributed locking:
-//  1. Acquire lock for the room+date combination
+//  1. Acquire locks for every night of the stay (room+date keys)
 //  2. Begin database transaction
-//  3. Check inventory availability
-//  4. Update inventory (increment booked_count)
-//  5. Insert booking record
-//  6. Commit transaction
-//  7. Release lock (via defer)
+//  3. Lock the inventory rows for the stay (SELECT ... FOR UPDATE)
+//  4. Check inventory availability for every night
+//  5. Update inventory (increment booked_count)
+//  6. Insert booking record
+//  7. Redeem the booking's promo code, if any
+//  8. Commit transaction
+//  9. Release locks (via defer)
+//
+// The row-level lock in step 3 keeps the check-then-increment correct even if
+// Redis is unavailable or a lock expires mid-transaction. Step 7 locks the
+// promotion row, so its redemption limits hold under concurrent bookings; if
+// they are reached the whole booking rolls back.
 func (r *BookingRepo) CreateBooking(ctx context.Context, booking *domain.Booking) error {
-	dateStr := booking.StartDate.Format("2006-01-02")
+	nights := domain.StayNights(booking.StartDate, booking.EndDate)
+	if len(nights) == 0 {
+		return fmt.Errorf("booking has no nights: %w", domain.ErrBadRequest)
+	}
+
+	release, err := r.lockNights(ctx, booking.RoomID, nights)
+	if err != nil {
+		return err
+	}
+	defer release()
 
-	lockValue, err := r.Locker.AcquireLock(ctx, booking.RoomID, dateStr)
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return domain.ErrLockFailed
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	defer func() {
-		if releaseErr := r.Locker.ReleaseLock(ctx, booking.RoomID, dateStr, lockValue); releaseErr != nil {
-			log.Printf("ERROR releasing lock: %v", releaseErr)
+	if booking.Quantity <= 0 {
+		booking.Quantity = 1
+	}
+
+	if err = reserveInventory(ctx, tx, booking.RoomID, booking.StartDate, booking.EndDate, len(nights), booking.Quantity); err != nil {
+		return err
+	}
+
+	if err = insertBooking(ctx, tx, booking); err != nil {
+		return err
+	}
+
+	if booking.PromotionID != nil {
+		if err = redeemPromotion(ctx, tx, booking, time.Now()); err != nil {
+			return err
 		}
-	}()
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+
+	log.Printf("✅ Booking created: id=%d, user=%s, room=%d, qty=%d, dates=%s→%s",
+		booking.ID, booking.UserID, booking.RoomID, booking.Quantity,
+		booking.StartDate.Format("2006-01-02"), booking.EndDate.Format("2006-01-02"))
+
+	return nil
+}
+
+// CreateItinerary reserves every leg of an itinerary all-or-nothing:
+//  1. Acquire locks for every night of every leg, one room at a time in room order
+//  2. Begin database transaction
+//  3. Lock, check and increment inventory for each leg in (room, start date) order
+//  4. Insert the itinerary and one booking per leg linked to it
+//  5. Commit transaction
+//
+// If any leg is unavailable the transaction rolls back and nothing is reserved.
+// Ordering both the Redis locks and the row locks keeps concurrent itineraries
+// that share rooms from deadlocking.
+func (r *BookingRepo) CreateItinerary(ctx context.Context, itinerary *domain.Itinerary) error {
+	if len(itinerary.Legs) == 0 {
+		return fmt.Errorf("itinerary has no legs: %w", domain.ErrBadRequest)
+	}
+
+	nightsByRoom := make(map[int][]time.Time)
+	for _, leg := range itinerary.Legs {
+		nights := domain.StayNights(leg.StartDate, leg.EndDate)
+		if len(nights) == 0 {
+			return fmt.Errorf("itinerary leg has no nights: %w", domain.ErrBadRequest)
+		}
+		if leg.Quantity <= 0 {
+			leg.Quantity = 1
+		}
+		nightsByRoom[leg.RoomID] = append(nightsByRoom[leg.RoomID], nights...)
+	}
+
+	release, err := r.lockRooms(ctx, nightsByRoom)
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -54,67 +132,310 @@ func (r *BookingRepo) CreateBooking(ctx context.Context, booking *domain.Booking
 	}
 	defer tx.Rollback()
 
-	var fullDays int
+	ordered := make([]*domain.Booking, len(itinerary.Legs))
+	copy(ordered, itinerary.Legs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].RoomID != ordered[j].RoomID {
+			return ordered[i].RoomID < ordered[j].RoomID
+		}
+		return ordered[i].StartDate.Before(ordered[j].StartDate)
+	})
+	for _, leg := range ordered {
+		nights := len(domain.StayNights(leg.StartDate, leg.EndDate))
+		if err = reserveInventory(ctx, tx, leg.RoomID, leg.StartDate, leg.EndDate, nights, leg.Quantity); err != nil {
+			return err
+		}
+	}
+
 	err = tx.QueryRowContext(ctx, `
-		SELECT count(*)
-		FROM inventory
-		WHERE room_id = $1
-		  AND date >= $2 AND date < $3
-		  AND booked_count >= total_inventory
-	`, booking.RoomID, booking.StartDate, booking.EndDate).Scan(&fullDays)
+		INSERT INTO itineraries (user_id, total_price, currency)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, itinerary.UserID, itinerary.TotalPrice, itinerary.Currency).Scan(&itinerary.ID, &itinerary.CreatedAt)
 	if err != nil {
-		return fmt.Errorf("availability check failed: %w", err)
+		return fmt.Errorf("itinerary insert failed: %w", err)
 	}
 
-	if fullDays > 0 {
-		return domain.ErrNotAvailable
+	for _, leg := range itinerary.Legs {
+		itineraryID := itinerary.ID
+		leg.UserID = itinerary.UserID
+		leg.ItineraryID = &itineraryID
+		if err = insertBooking(ctx, tx, leg); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+
+	log.Printf("✅ Itinerary created: id=%d, user=%s, legs=%d",
+		itinerary.ID, itinerary.UserID, len(itinerary.Legs))
+
+	return nil
+}
+
+// insertBooking inserts a pending booking row inside tx and fills in the
+// generated id, status and created_at.
+func insertBooking(ctx context.Context, tx *sql.Tx, booking *domain.Booking) error {
+	nights, lines, err := marshalPricing(booking)
+	if err != nil {
+		return err
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO bookings (user_id, room_id, start_date, end_date, quantity, total_price, currency,
+		                      charge_total, charge_currency, fx_rate, status, itinerary_id, nightly_rates,
+		                      price_lines, promotion_id, promo_code, promo_discount, adults, children,
+		                      extra_guest_charge)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'pending', $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING id, status, created_at
+	`, booking.UserID, booking.RoomID, booking.StartDate, booking.EndDate, booking.Quantity, booking.TotalPrice, booking.Currency,
+		booking.ChargeTotal, booking.ChargeCurrency, booking.FxRate, booking.ItineraryID, nights, lines,
+		booking.PromotionID, booking.PromoCode, booking.PromoDiscount, booking.Adults, booking.Children,
+		booking.ExtraGuestCharge).
+		Scan(&booking.ID, &booking.Status, &booking.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("booking insert failed: %w", err)
+	}
+	return recordBookingStatus(ctx, tx, booking.ID, "", booking.Status)
+}
+
+// ModifyBooking moves a pending or confirmed booking to updated's room, dates
+// and total price, returning the booking as it was before the change:
+//  1. Acquire locks for every old and new night, one room at a time in room order
+//  2. Begin database transaction and lock the booking row
+//  3. Verify ownership, status, and that the stay did not change concurrently
+//  4. Release the old nights, then lock, check and claim the new ones
+//  5. Update the booking (and its itinerary's total) and commit
+//
+// Old nights are released before new ones are checked, so shifting a stay by a
+// day only needs inventory for the nights that are actually new.
+func (r *BookingRepo) ModifyBooking(ctx context.Context, updated *domain.Booking) (*domain.Booking, error) {
+	newNights := domain.StayNights(updated.StartDate, updated.EndDate)
+	if len(newNights) == 0 {
+		return nil, fmt.Errorf("booking has no nights: %w", domain.ErrBadRequest)
+	}
+
+	current, err := r.FindBookingByID(ctx, updated.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	nightsByRoom := map[int][]time.Time{
+		current.RoomID: domain.StayNights(current.StartDate, current.EndDate),
+	}
+	nightsByRoom[updated.RoomID] = append(nightsByRoom[updated.RoomID], newNights...)
+
+	release, err := r.lockRooms(ctx, nightsByRoom)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction for modify: %w", err)
+	}
+	defer tx.Rollback()
+
+	previous, err := scanBooking(tx.QueryRowContext(ctx, `
+		SELECT`+bookingColumns+`
+		FROM bookings WHERE id = $1
+		FOR UPDATE
+	`, updated.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("booking not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("find booking for modify: %w", err)
+	}
+
+	if previous.UserID != updated.UserID {
+		return nil, fmt.Errorf("booking does not belong to user: %w", domain.ErrUnauthorized)
+	}
+	if previous.Status != domain.BookingStatusPending && previous.Status != domain.BookingStatusConfirmed {
+		return nil, fmt.Errorf("booking status %q cannot be modified: %w", previous.Status, domain.ErrConflict)
+	}
+	// The locks above cover the stay read before the transaction; if it moved
+	// in between, they may not cover the nights being released.
+	if previous.RoomID != current.RoomID || !previous.StartDate.Equal(current.StartDate) || !previous.EndDate.Equal(current.EndDate) {
+		return nil, fmt.Errorf("booking was modified concurrently: %w", domain.ErrConflict)
 	}
 
 	_, err = tx.ExecContext(ctx, `
 		UPDATE inventory
-		SET booked_count = booked_count + 1
+		SET booked_count = GREATEST(0, booked_count - $4)
 		WHERE room_id = $1 AND date >= $2 AND date < $3
-	`, booking.RoomID, booking.StartDate, booking.EndDate)
+	`, previous.RoomID, previous.StartDate, previous.EndDate, previous.Quantity)
 	if err != nil {
-		return fmt.Errorf("inventory update failed: %w", err)
+		return nil, fmt.Errorf("release old inventory: %w", err)
 	}
 
-	err = tx.QueryRowContext(ctx, `
-		INSERT INTO bookings (user_id, room_id, start_date, end_date, total_price, status)
-		VALUES ($1, $2, $3, $4, $5, 'pending')
-		RETURNING id, created_at
-	`, booking.UserID, booking.RoomID, booking.StartDate, booking.EndDate, booking.TotalPrice).
-		Scan(&booking.ID, &booking.CreatedAt)
+	if err = reserveInventory(ctx, tx, updated.RoomID, updated.StartDate, updated.EndDate, len(newNights), previous.Quantity); err != nil {
+		return nil, err
+	}
+
+	nights, lines, err := marshalPricing(updated)
 	if err != nil {
-		return fmt.Errorf("booking insert failed: %w", err)
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE bookings
+		SET room_id = $1, start_date = $2, end_date = $3, total_price = $4, charge_total = $5,
+		    nightly_rates = $6, price_lines = $7, promo_discount = $8, extra_guest_charge = $9
+		WHERE id = $10
+	`, updated.RoomID, updated.StartDate, updated.EndDate, updated.TotalPrice, updated.ChargeTotal, nights, lines,
+		updated.PromoDiscount, updated.ExtraGuestCharge, updated.ID)
+	if err != nil {
+		return nil, fmt.Errorf("modify booking update: %w", err)
+	}
+
+	if previous.PromotionID != nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE promotion_redemptions SET amount = $1
+			WHERE booking_id = $2 AND released_at IS NULL
+		`, updated.PromoDiscount, updated.ID)
+		if err != nil {
+			return nil, fmt.Errorf("update promotion redemption: %w", err)
+		}
+	}
+
+	if previous.ItineraryID != nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE itineraries SET total_price = total_price + $1 WHERE id = $2
+		`, updated.ChargeTotal-previous.ChargeTotal, *previous.ItineraryID)
+		if err != nil {
+			return nil, fmt.Errorf("update itinerary total: %w", err)
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("transaction commit failed: %w", err)
+		return nil, fmt.Errorf("commit modify transaction: %w", err)
+	}
+
+	updated.Quantity = previous.Quantity
+	updated.Status = previous.Status
+	updated.CreatedAt = previous.CreatedAt
+	updated.ItineraryID = previous.ItineraryID
+	updated.PromotionID = previous.PromotionID
+	updated.PromoCode = previous.PromoCode
+
+	log.Printf("Booking modified: id=%d, room=%d→%d, dates=%s→%s",
+		updated.ID, previous.RoomID, updated.RoomID,
+		updated.StartDate.Format("2006-01-02"), updated.EndDate.Format("2006-01-02"))
+
+	return previous, nil
+}
+
+// lockRooms acquires the night locks of several rooms, one room at a time in
+// ascending room order so concurrent multi-room operations cannot deadlock.
+// The returned function releases every lock taken.
+func (r *BookingRepo) lockRooms(ctx context.Context, nightsByRoom map[int][]time.Time) (func(), error) {
+	roomIDs := make([]int, 0, len(nightsByRoom))
+	for roomID := range nightsByRoom {
+		roomIDs = append(roomIDs, roomID)
+	}
+	sort.Ints(roomIDs)
+
+	releases := make([]func(), 0, len(roomIDs))
+	releaseAll := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
 	}
+	for _, roomID := range roomIDs {
+		release, err := r.lockNights(ctx, roomID, nightsByRoom[roomID])
+		if err != nil {
+			releaseAll()
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	return releaseAll, nil
+}
 
-	log.Printf("✅ Booking created: id=%d, user=%s, room=%d, date=%s",
-		booking.ID, booking.UserID, booking.RoomID, dateStr)
+// lockNights acquires the distributed lock for every night of a stay and
+// returns a function that releases them all. When no Locker is configured
+// (e.g. in the worker) it is a no-op and callers rely on row-level locks.
+func (r *BookingRepo) lockNights(ctx context.Context, roomID int, nights []time.Time) (func(), error) {
+	if r.Locker == nil {
+		return func() {}, nil
+	}
+
+	dates := make([]string, len(nights))
+	for i, n := range nights {
+		dates[i] = n.Format("2006-01-02")
+	}
+
+	lockValue, err := r.Locker.AcquireLocks(ctx, roomID, dates)
+	if err != nil {
+		return nil, domain.ErrLockFailed
+	}
+
+	return func() {
+		if releaseErr := r.Locker.ReleaseLocks(ctx, roomID, dates, lockValue); releaseErr != nil {
+			log.Printf("ERROR releasing locks: %v", releaseErr)
+		}
+	}, nil
+}
 
+// reserveInventory locks the inventory rows of a stay with SELECT ... FOR UPDATE,
+// verifies every night exists and has quantity free units, counting the
+// room's overbooking allowance, then increments booked_count by quantity.
+// Rows are locked in date order so concurrent transactions cannot deadlock.
+func reserveInventory(ctx context.Context, tx dbConn, roomID int, startDate, endDate time.Time, nights, quantity int) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT i.booked_count, i.total_inventory, r.overbooking_type, r.overbooking_value
+		FROM inventory i
+		JOIN rooms r ON r.id = i.room_id
+		WHERE i.room_id = $1 AND i.date >= $2 AND i.date < $3
+		ORDER BY i.date
+		FOR UPDATE OF i
+	`, roomID, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("availability check failed: %w", err)
+	}
+
+	var lockedDays, fullDays int
+	for rows.Next() {
+		var booked, total int
+		var overbooking domain.Overbooking
+		if err := rows.Scan(&booked, &total, &overbooking.Type, &overbooking.Value); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan inventory row: %w", err)
+		}
+		lockedDays++
+		if booked+quantity > overbooking.Sellable(total) {
+			fullDays++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("iterate inventory rows: %w", err)
+	}
+	rows.Close()
+
+	if lockedDays < nights || fullDays > 0 {
+		return domain.ErrNotAvailable
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE inventory
+		SET booked_count = booked_count + $4
+		WHERE room_id = $1 AND date >= $2 AND date < $3
+	`, roomID, startDate, endDate, quantity)
+	if err != nil {
+		return fmt.Errorf("inventory update failed: %w", err)
+	}
 	return nil
 }
 
 // FindBookingByID retrieves a single booking by ID.
 func (r *BookingRepo) FindBookingByID(ctx context.Context, id int) (*domain.Booking, error) {
-	booking := &domain.Booking{}
-	err := r.DB.QueryRowContext(ctx, `
-		SELECT id, user_id, room_id, start_date, end_date, total_price, status, created_at
+	booking, err := scanBooking(conn(ctx, r.DB).QueryRowContext(ctx, `
+		SELECT`+bookingColumns+`
 		FROM bookings WHERE id = $1
-	`, id).Scan(
-		&booking.ID,
-		&booking.UserID,
-		&booking.RoomID,
-		&booking.StartDate,
-		&booking.EndDate,
-		&booking.TotalPrice,
-		&booking.Status,
-		&booking.CreatedAt,
-	)
+	`, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("booking not found: %w", domain.ErrNotFound)
@@ -136,7 +457,7 @@ func (r *BookingRepo) ListBookingsByUser(ctx context.Context, userID string, pag
 	}
 
 	rows, err := r.DB.QueryContext(ctx, `
-		SELECT id, user_id, room_id, start_date, end_date, total_price, status, created_at
+		SELECT`+bookingColumns+`
 		FROM bookings WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
@@ -153,79 +474,289 @@ func (r *BookingRepo) ListBookingsByUser(ctx context.Context, userID string, pag
 	return bookings, total, nil
 }
 
-// UpdateBookingStatus updates the status of a booking.
+// FindItineraryByID retrieves an itinerary together with all of its legs.
+func (r *BookingRepo) FindItineraryByID(ctx context.Context, id int) (*domain.Itinerary, error) {
+	itinerary := &domain.Itinerary{}
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT id, user_id, total_price, currency, created_at
+		FROM itineraries WHERE id = $1
+	`, id).Scan(
+		&itinerary.ID,
+		&itinerary.UserID,
+		&itinerary.TotalPrice,
+		&itinerary.Currency,
+		&itinerary.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("itinerary not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("find itinerary by id: %w", err)
+	}
+
+	legs, err := r.ListBookingsByItinerary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	itinerary.Legs = legs
+	return itinerary, nil
+}
+
+// ListBookingsByItinerary returns every leg of an itinerary ordered by booking ID,
+// so the first element is the lead booking the itinerary's payment is attached to.
+func (r *BookingRepo) ListBookingsByItinerary(ctx context.Context, itineraryID int) ([]*domain.Booking, error) {
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `
+		SELECT`+bookingColumns+`
+		FROM bookings WHERE itinerary_id = $1
+		ORDER BY id
+	`, itineraryID)
+	if err != nil {
+		return nil, fmt.Errorf("list bookings by itinerary: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBookingRows(rows)
+}
+
+// UpdateBookingStatus moves a booking to status and records the move in its
+// status history. A booking already in status is left as it is. Returns
+// ErrConflict when the booking state machine does not allow the move from the
+// booking's current status.
 func (r *BookingRepo) UpdateBookingStatus(ctx context.Context, id int, status string) error {
-	res, err := r.DB.ExecContext(ctx, `
-		UPDATE bookings SET status = $1 WHERE id = $2
-	`, status, id)
+	tx, err := beginTx(ctx, r.DB)
 	if err != nil {
-		return fmt.Errorf("update booking status: %w", err)
+		return fmt.Errorf("begin transaction for booking status: %w", err)
 	}
-	n, _ := res.RowsAffected()
-	if n == 0 {
-		return fmt.Errorf("booking not found: %w", domain.ErrNotFound)
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM bookings WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("booking not found: %w", domain.ErrNotFound)
+		}
+		return fmt.Errorf("lock booking for status update: %w", err)
+	}
+	if current == status {
+		return nil
+	}
+
+	if err := moveBooking(ctx, tx, id, current, status); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit booking status: %w", err)
 	}
 	return nil
 }
 
-// CancelBooking cancels a booking and restores inventory in a transaction.
-// It verifies the booking belongs to the given userID before cancelling.
+// ListExpiredHolds returns bookings whose inventory hold has outlived its TTL:
+// pending bookings created before pendingBefore, and awaiting_payment bookings
+// with no gateway result on a checkout payment newer than awaitingBefore.
+// Payments of an itinerary live on its lead leg, so every leg of the
+// itinerary is considered when looking for a live payment.
+func (r *BookingRepo) ListExpiredHolds(ctx context.Context, pendingBefore, awaitingBefore time.Time, limit int) ([]*domain.Booking, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT`+bookingColumns+`
+		FROM bookings b
+		WHERE (b.status = 'pending' AND b.created_at < $1)
+		   OR (b.status = 'awaiting_payment' AND NOT EXISTS (
+				SELECT 1
+				FROM payments p
+				JOIN bookings l ON l.id = p.booking_id
+				WHERE (l.id = b.id OR l.itinerary_id = b.itinerary_id)
+				  AND (p.created_at >= $2 OR p.status NOT IN ('pending', 'processing'))
+		   ))
+		ORDER BY b.id
+		LIMIT $3
+	`, pendingBefore, awaitingBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list expired holds: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBookingRows(rows)
+}
+
+// ExpireBooking moves a booking that is still holding inventory (pending or
+// awaiting_payment) to expired and releases its promo code. Returns
+// ErrConflict when the booking has already left those states, e.g. because
+// its payment completed meanwhile.
+func (r *BookingRepo) ExpireBooking(ctx context.Context, id int) error {
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("begin transaction for expiry: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM bookings WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("booking not found: %w", domain.ErrNotFound)
+		}
+		return fmt.Errorf("lock booking for expiry: %w", err)
+	}
+	if err := moveBooking(ctx, tx, id, current, domain.BookingStatusExpired); err != nil {
+		return fmt.Errorf("booking %d no longer holds inventory: %w", id, err)
+	}
+
+	if err := releaseRedemption(ctx, tx, id); err != nil {
+		return fmt.Errorf("release promotion after expiry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit expiry: %w", err)
+	}
+	return nil
+}
+
+// CancelBooking cancels a booking, restores inventory and releases its promo
+// code in a transaction. It verifies the booking belongs to the given userID before cancelling.
+// Only bookings that still hold inventory, those the booking state machine
+// lets move to cancelled, can be cancelled.
 func (r *BookingRepo) CancelBooking(ctx context.Context, id int, userID string) error {
-	tx, err := r.DB.BeginTx(ctx, nil)
+	tx, err := beginTx(ctx, r.DB)
 	if err != nil {
 		return fmt.Errorf("begin transaction for cancel: %w", err)
 	}
 	defer tx.Rollback()
 
+	booking, err := lockBookingForCancel(ctx, tx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := moveBooking(ctx, tx, id, booking.Status, domain.BookingStatusCancelled); err != nil {
+		return fmt.Errorf("cancel booking: %w", err)
+	}
+
+	if err := releaseInventory(ctx, tx, booking); err != nil {
+		return fmt.Errorf("restore inventory after cancel: %w", err)
+	}
+
+	if err := releaseRedemption(ctx, tx, id); err != nil {
+		return fmt.Errorf("release promotion after cancel: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit cancel transaction: %w", err)
+	}
+
+	log.Printf("Booking cancelled: id=%d, user=%s, room=%d", id, userID, booking.RoomID)
+	return nil
+}
+
+// RefundBooking cancels a confirmed booking, restores its inventory and
+// releases its promo code in a transaction. The booking moves to refunded when amount is owed back, or to
+// cancelled when nothing is (non-refundable rate). The money itself is
+// returned by the refund saga.
+func (r *BookingRepo) RefundBooking(ctx context.Context, id int, userID string, amount int64) error {
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("begin transaction for refund: %w", err)
+	}
+	defer tx.Rollback()
+
+	booking, err := lockBookingForCancel(ctx, tx, id, userID)
+	if err != nil {
+		return err
+	}
+	if booking.Status != domain.BookingStatusConfirmed {
+		return fmt.Errorf("booking status %q cannot be refunded: %w", booking.Status, domain.ErrConflict)
+	}
+
+	status := domain.BookingStatusRefunded
+	if amount <= 0 {
+		status = domain.BookingStatusCancelled
+	}
+	if err := moveBooking(ctx, tx, id, booking.Status, status); err != nil {
+		return fmt.Errorf("refund booking: %w", err)
+	}
+
+	if err := releaseInventory(ctx, tx, booking); err != nil {
+		return fmt.Errorf("restore inventory after refund: %w", err)
+	}
+
+	if err := releaseRedemption(ctx, tx, id); err != nil {
+		return fmt.Errorf("release promotion after refund: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit refund transaction: %w", err)
+	}
+
+	log.Printf("Booking %s: id=%d, user=%s, refund=%d", status, id, userID, amount)
+	return nil
+}
+
+// lockBookingForCancel row-locks a booking inside tx and verifies ownership.
+func lockBookingForCancel(ctx context.Context, tx dbConn, id int, userID string) (*domain.Booking, error) {
 	var booking domain.Booking
-	err = tx.QueryRowContext(ctx, `
-		SELECT id, user_id, room_id, start_date, end_date, status
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, user_id, room_id, start_date, end_date, quantity, status
 		FROM bookings WHERE id = $1
+		FOR UPDATE
 	`, id).Scan(
 		&booking.ID,
 		&booking.UserID,
 		&booking.RoomID,
 		&booking.StartDate,
 		&booking.EndDate,
+		&booking.Quantity,
 		&booking.Status,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("booking not found: %w", domain.ErrNotFound)
+			return nil, fmt.Errorf("booking not found: %w", domain.ErrNotFound)
 		}
-		return fmt.Errorf("find booking for cancel: %w", err)
+		return nil, fmt.Errorf("find booking for cancel: %w", err)
 	}
 
 	if booking.UserID != userID {
-		return fmt.Errorf("booking does not belong to user: %w", domain.ErrUnauthorized)
+		return nil, fmt.Errorf("booking does not belong to user: %w", domain.ErrUnauthorized)
 	}
+	return &booking, nil
+}
 
-	if booking.Status == "cancelled" {
-		return fmt.Errorf("booking already cancelled: %w", domain.ErrConflict)
+// moveBooking moves a booking locked in tx from status from to status to, if
+// the booking state machine allows it, and records the move in the booking's
+// status history. Returns ErrConflict when the move is not allowed.
+func moveBooking(ctx context.Context, tx dbConn, id int, from, to string) error {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE bookings SET status = $1 WHERE id = $2 AND status = ANY($3)
+	`, to, id, pq.Array(domain.BookingStatusesBefore(to)))
+	if err != nil {
+		return fmt.Errorf("update booking status: %w", err)
 	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("booking %d cannot move from %s to %s: %w", id, from, to, domain.ErrConflict)
+	}
+	return recordBookingStatus(ctx, tx, id, from, to)
+}
 
-	_, err = tx.ExecContext(ctx, `
-		UPDATE bookings SET status = 'cancelled' WHERE id = $1
-	`, id)
+// recordBookingStatus appends a booking's move from one status to another to
+// its status history; from is empty for a new booking.
+func recordBookingStatus(ctx context.Context, tx dbConn, id int, from, to string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO booking_status_history (booking_id, from_status, to_status, correlation_id)
+		VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, ''))
+	`, id, from, to, observability.CorrelationID(ctx))
 	if err != nil {
-		return fmt.Errorf("cancel booking update: %w", err)
+		return fmt.Errorf("record booking status: %w", err)
 	}
+	return nil
+}
 
-	_, err = tx.ExecContext(ctx, `
+// releaseInventory gives a booking's units back for every night of its stay.
+func releaseInventory(ctx context.Context, tx dbConn, booking *domain.Booking) error {
+	_, err := tx.ExecContext(ctx, `
 		UPDATE inventory
-		SET booked_count = booked_count - 1
+		SET booked_count = GREATEST(0, booked_count - $4)
 		WHERE room_id = $1 AND date >= $2 AND date < $3
-	`, booking.RoomID, booking.StartDate, booking.EndDate)
-	if err != nil {
-		return fmt.Errorf("restore inventory after cancel: %w", err)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("commit cancel transaction: %w", err)
-	}
-
-	log.Printf("Booking cancelled: id=%d, user=%s, room=%d", id, userID, booking.RoomID)
-	return nil
+	`, booking.RoomID, booking.StartDate, booking.EndDate, booking.Quantity)
+	return err
 }
 
 // ListAllBookings returns all bookings across all users, paginated by created_at DESC.
@@ -238,7 +769,7 @@ func (r *BookingRepo) ListAllBookings(ctx context.Context, page, limit int) ([]*
 	}
 
 	rows, err := r.DB.QueryContext(ctx, `
-		SELECT id, user_id, room_id, start_date, end_date, total_price, status, created_at
+		SELECT`+bookingColumns+`
 		FROM bookings
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -255,21 +786,83 @@ func (r *BookingRepo) ListAllBookings(ctx context.Context, page, limit int) ([]*
 	return bookings, total, nil
 }
 
+// bookingColumns is the column list read by scanBooking.
+const bookingColumns = `
+		id, user_id, room_id, start_date, end_date, quantity, total_price, currency,
+		charge_total, charge_currency, fx_rate, status, created_at, itinerary_id, nightly_rates,
+		price_lines, promotion_id, promo_code, promo_discount, adults, children, extra_guest_charge`
+
+func scanBooking(row rowScanner) (*domain.Booking, error) {
+	b := &domain.Booking{}
+	var nights, lines []byte
+	var promotionID sql.NullInt64
+	if err := row.Scan(
+		&b.ID,
+		&b.UserID,
+		&b.RoomID,
+		&b.StartDate,
+		&b.EndDate,
+		&b.Quantity,
+		&b.TotalPrice,
+		&b.Currency,
+		&b.ChargeTotal,
+		&b.ChargeCurrency,
+		&b.FxRate,
+		&b.Status,
+		&b.CreatedAt,
+		&b.ItineraryID,
+		&nights,
+		&lines,
+		&promotionID,
+		&b.PromoCode,
+		&b.PromoDiscount,
+		&b.Adults,
+		&b.Children,
+		&b.ExtraGuestCharge,
+	); err != nil {
+		return nil, err
+	}
+	b.PromotionID = nullIntPtr(promotionID)
+	if len(nights) > 0 {
+		if err := json.Unmarshal(nights, &b.Nights); err != nil {
+			return nil, fmt.Errorf("decode nightly rates: %w", err)
+		}
+	}
+	if len(lines) > 0 {
+		if err := json.Unmarshal(lines, &b.Lines); err != nil {
+			return nil, fmt.Errorf("decode price lines: %w", err)
+		}
+	}
+	return b, nil
+}
+
+// marshalPricing encodes a booking's nightly breakdown and price lines for
+// the nightly_rates and price_lines columns. Missing ones are stored as empty
+// lists.
+func marshalPricing(b *domain.Booking) (nights, lines []byte, err error) {
+	nightly := b.Nights
+	if nightly == nil {
+		nightly = []domain.NightlyRate{}
+	}
+	if nights, err = json.Marshal(nightly); err != nil {
+		return nil, nil, fmt.Errorf("encode nightly rates: %w", err)
+	}
+	priceLines := b.Lines
+	if priceLines == nil {
+		priceLines = []domain.PriceLine{}
+	}
+	if lines, err = json.Marshal(priceLines); err != nil {
+		return nil, nil, fmt.Errorf("encode price lines: %w", err)
+	}
+	return nights, lines, nil
+}
+
 // scanBookingRows scans multiple booking rows into a slice.
 func scanBookingRows(rows *sql.Rows) ([]*domain.Booking, error) {
 	var bookings []*domain.Booking
 	for rows.Next() {
-		b := &domain.Booking{}
-		if err := rows.Scan(
-			&b.ID,
-			&b.UserID,
-			&b.RoomID,
-			&b.StartDate,
-			&b.EndDate,
-			&b.TotalPrice,
-			&b.Status,
-			&b.CreatedAt,
-		); err != nil {
+		b, err := scanBooking(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan booking row: %w", err)
 		}
 		bookings = append(bookings, b)
@@ -0,0 +1,232 @@
+package repository_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+)
+
+// newTestDB opens the database named by TEST_DATABASE_URL, creates a
+// throwaway schema for the test and applies every up migration to it. Tests
+// that need it are skipped when the variable is not set.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { _, _ = admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("parse TEST_DATABASE_URL: %v", err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatalf("open schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob("../../migrations/*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("find migrations: %v", err)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		stmt, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatalf("read %s: %v", f, err)
+		}
+		if _, err := db.Exec(string(stmt)); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(f), err)
+		}
+	}
+	return db
+}
+
+// seedRoom inserts a hotel and a room with units rooms available on each of
+// nights days starting at from, returning the room ID.
+func seedRoom(t *testing.T, db *sql.DB, from time.Time, nights, units int) int {
+	t.Helper()
+
+	var hotelID, roomID int
+	if err := db.QueryRow(`INSERT INTO hotels (name, location) VALUES ('Test Hotel', 'Test City') RETURNING id`).
+		Scan(&hotelID); err != nil {
+		t.Fatalf("insert hotel: %v", err)
+	}
+	if err := db.QueryRow(`INSERT INTO rooms (hotel_id, name, price_per_night) VALUES ($1, 'Deluxe King', 100) RETURNING id`, hotelID).
+		Scan(&roomID); err != nil {
+		t.Fatalf("insert room: %v", err)
+	}
+	for i := 0; i < nights; i++ {
+		if _, err := db.Exec(`INSERT INTO inventory (room_id, date, total_inventory) VALUES ($1, $2, $3)`,
+			roomID, from.AddDate(0, 0, i), units); err != nil {
+			t.Fatalf("insert inventory: %v", err)
+		}
+	}
+	return roomID
+}
+
+// TestBookingRepo_CreateBooking_OverlappingStays_NoOverbooking races two
+// overlapping stays with different check-in dates (10→14 and 12→15) for a room
+// with a single unit through the real booking SQL. The repository runs without
+// a Locker so only the inventory row locks stand between the two bookings:
+// exactly one must succeed and the other must see the room as unavailable.
+func TestBookingRepo_CreateBooking_OverlappingStays_NoOverbooking(t *testing.T) {
+	db := newTestDB(t)
+	from := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	roomID := seedRoom(t, db, from, 5, 1)
+	repo := repository.NewBookingRepo(db, nil)
+
+	stays := [][2]time.Time{
+		{from, from.AddDate(0, 0, 4)},
+		{from.AddDate(0, 0, 2), from.AddDate(0, 0, 5)},
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, len(stays))
+	for i, stay := range stays {
+		wg.Add(1)
+		go func(i int, stay [2]time.Time) {
+			defer wg.Done()
+			<-start
+			errs[i] = repo.CreateBooking(context.Background(), &domain.Booking{
+				UserID:         "00000000-0000-0000-0000-000000000001",
+				RoomID:         roomID,
+				StartDate:      stay[0],
+				EndDate:        stay[1],
+				Quantity:       1,
+				TotalPrice:     100,
+				Currency:       "USD",
+				ChargeTotal:    100,
+				ChargeCurrency: "USD",
+				FxRate:         1,
+			})
+		}(i, stay)
+	}
+	close(start)
+	wg.Wait()
+
+	var succeeded, unavailable int
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, domain.ErrNotAvailable):
+			unavailable++
+		default:
+			t.Errorf("stay %d: unexpected error: %v", i, err)
+		}
+	}
+	if succeeded != 1 || unavailable != 1 {
+		t.Fatalf("expected one booking and one ErrNotAvailable, got %d and %d (errs=%v)", succeeded, unavailable, errs)
+	}
+
+	var overbooked int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM inventory WHERE room_id = $1 AND booked_count > total_inventory`, roomID).
+		Scan(&overbooked); err != nil {
+		t.Fatalf("count overbooked nights: %v", err)
+	}
+	if overbooked != 0 {
+		t.Errorf("expected no overbooked nights, got %d", overbooked)
+	}
+}
+
+// inventoryLockQuery matches the row-locking read of a stay's inventory.
+const inventoryLockQuery = `FROM inventory i\s+JOIN rooms r ON r.id = i.room_id\s+` +
+	`WHERE i.room_id = \$1 AND i.date >= \$2 AND i.date < \$3\s+ORDER BY i.date\s+FOR UPDATE OF i`
+
+func inventoryRows(booked ...int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"booked_count", "total_inventory", "overbooking_type", "overbooking_value"})
+	for _, b := range booked {
+		rows.AddRow(b, 1, "units", 0)
+	}
+	return rows
+}
+
+// TestBookingRepo_CreateBooking_LocksEveryNightBeforeWriting checks, without
+// Redis or Postgres, that CreateBooking locks the inventory rows of the whole
+// stay with FOR UPDATE before it increments them, and that a stay overlapping
+// a full night on any date, not just its check-in, is refused without writes.
+func TestBookingRepo_CreateBooking_LocksEveryNightBeforeWriting(t *testing.T) {
+	from := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("free stay", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock.New: %v", err)
+		}
+		defer db.Close()
+
+		start, end := from, from.AddDate(0, 0, 4)
+		mock.ExpectBegin()
+		mock.ExpectQuery(inventoryLockQuery).WithArgs(7, start, end).WillReturnRows(inventoryRows(0, 0, 0, 0))
+		mock.ExpectExec(`UPDATE inventory\s+SET booked_count = booked_count \+ \$4`).
+			WithArgs(7, start, end, 1).WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectQuery(`INSERT INTO bookings`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, "pending", time.Now()))
+		mock.ExpectExec(`INSERT INTO booking_status_history`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := repository.NewBookingRepo(db, nil)
+		if err := repo.CreateBooking(context.Background(), &domain.Booking{RoomID: 7, StartDate: start, EndDate: end}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("overlapping a booked night", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock.New: %v", err)
+		}
+		defer db.Close()
+
+		// 12→15 overlaps a 10→14 stay on the 12th and 13th.
+		start, end := from.AddDate(0, 0, 2), from.AddDate(0, 0, 5)
+		mock.ExpectBegin()
+		mock.ExpectQuery(inventoryLockQuery).WithArgs(7, start, end).WillReturnRows(inventoryRows(1, 1, 0))
+		mock.ExpectRollback()
+
+		repo := repository.NewBookingRepo(db, nil)
+		err = repo.CreateBooking(context.Background(), &domain.Booking{RoomID: 7, StartDate: start, EndDate: end})
+		if !errors.Is(err, domain.ErrNotAvailable) {
+			t.Fatalf("expected ErrNotAvailable, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
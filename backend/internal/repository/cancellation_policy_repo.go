@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"booking-app/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// pgCancellationPolicyRepo implements CancellationPolicyRepository using PostgreSQL.
+type pgCancellationPolicyRepo struct {
+	db *sql.DB
+}
+
+// NewCancellationPolicyRepo creates a new PostgreSQL-backed CancellationPolicyRepository.
+func NewCancellationPolicyRepo(db *sql.DB) CancellationPolicyRepository {
+	return &pgCancellationPolicyRepo{db: db}
+}
+
+const cancellationPolicyColumns = `
+	id, hotel_id, room_id, free_cancellation_days, penalty_percent,
+	non_refundable, capture_days_before_check_in, created_at, updated_at`
+
+// UpsertPolicy creates or replaces the policy of a hotel (RoomID nil) or room.
+func (r *pgCancellationPolicyRepo) UpsertPolicy(ctx context.Context, policy *domain.CancellationPolicy) (*domain.CancellationPolicy, error) {
+	// Each scope has its own partial unique index, so the conflict target
+	// differs between hotel and room policies.
+	conflict := `(hotel_id) WHERE room_id IS NULL`
+	if policy.RoomID != nil {
+		conflict = `(room_id) WHERE room_id IS NOT NULL`
+	}
+	q := `
+		INSERT INTO cancellation_policies
+		    (hotel_id, room_id, free_cancellation_days, penalty_percent, non_refundable,
+		     capture_days_before_check_in)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT ` + conflict + ` DO UPDATE
+		SET free_cancellation_days       = EXCLUDED.free_cancellation_days,
+		    penalty_percent              = EXCLUDED.penalty_percent,
+		    non_refundable               = EXCLUDED.non_refundable,
+		    capture_days_before_check_in = EXCLUDED.capture_days_before_check_in,
+		    updated_at                   = NOW()
+		RETURNING` + cancellationPolicyColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		policy.HotelID,
+		policy.RoomID,
+		policy.FreeCancellationDays,
+		policy.PenaltyPercent,
+		policy.NonRefundable,
+		policy.CaptureDaysBeforeCheckIn,
+	)
+	saved, err := scanCancellationPolicy(row)
+	if err != nil {
+		return nil, fmt.Errorf("upsert cancellation policy: %w", err)
+	}
+	return saved, nil
+}
+
+// GetPolicyForRoom returns the policy that applies to a room: its own policy
+// if it has one, otherwise its hotel's. Returns ErrNotFound when neither exists.
+func (r *pgCancellationPolicyRepo) GetPolicyForRoom(ctx context.Context, roomID int) (*domain.CancellationPolicy, error) {
+	q := `
+		SELECT` + cancellationPolicyColumns + `
+		FROM cancellation_policies cp
+		JOIN rooms rm ON rm.id = $1
+		WHERE cp.room_id = rm.id
+		   OR (cp.room_id IS NULL AND cp.hotel_id = rm.hotel_id)
+		ORDER BY cp.room_id IS NULL
+		LIMIT 1`
+
+	policy, err := scanCancellationPolicy(r.db.QueryRowContext(ctx, q, roomID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no cancellation policy for room %d: %w", roomID, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get cancellation policy: %w", err)
+	}
+	return policy, nil
+}
+
+func scanCancellationPolicy(row *sql.Row) (*domain.CancellationPolicy, error) {
+	p := &domain.CancellationPolicy{}
+	var roomID, captureDays sql.NullInt64
+	if err := row.Scan(
+		&p.ID,
+		&p.HotelID,
+		&roomID,
+		&p.FreeCancellationDays,
+		&p.PenaltyPercent,
+		&p.NonRefundable,
+		&captureDays,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if roomID.Valid {
+		id := int(roomID.Int64)
+		p.RoomID = &id
+	}
+	if captureDays.Valid {
+		days := int(captureDays.Int64)
+		p.CaptureDaysBeforeCheckIn = &days
+	}
+	return p, nil
+}
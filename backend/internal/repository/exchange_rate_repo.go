@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"booking-app/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// pgExchangeRateRepo implements ExchangeRateRepository using PostgreSQL.
+type pgExchangeRateRepo struct {
+	db *sql.DB
+}
+
+// NewExchangeRateRepo creates a new PostgreSQL-backed ExchangeRateRepository.
+func NewExchangeRateRepo(db *sql.DB) ExchangeRateRepository {
+	return &pgExchangeRateRepo{db: db}
+}
+
+// UpsertRates stores every rate in one transaction, so an upload is applied
+// completely or not at all.
+func (r *pgExchangeRateRepo) UpsertRates(ctx context.Context, rates []*domain.ExchangeRate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction for exchange rates: %w", err)
+	}
+	defer tx.Rollback()
+
+	const q = `
+		INSERT INTO exchange_rates (base_currency, quote_currency, rate)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (base_currency, quote_currency) DO UPDATE
+		SET rate       = EXCLUDED.rate,
+		    updated_at = NOW()
+		RETURNING updated_at`
+	for _, rate := range rates {
+		if err := tx.QueryRowContext(ctx, q, rate.Base, rate.Quote, rate.Rate).Scan(&rate.UpdatedAt); err != nil {
+			return fmt.Errorf("upsert exchange rate %s/%s: %w", rate.Base, rate.Quote, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit exchange rates: %w", err)
+	}
+	return nil
+}
+
+// ListRates returns every stored rate ordered by currency pair.
+func (r *pgExchangeRateRepo) ListRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	const q = `
+		SELECT base_currency, quote_currency, rate, updated_at
+		FROM exchange_rates
+		ORDER BY base_currency, quote_currency`
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("list exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []*domain.ExchangeRate
+	for rows.Next() {
+		rate := &domain.ExchangeRate{}
+		if err := rows.Scan(&rate.Base, &rate.Quote, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan exchange rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// GetRate returns the rate from base to quote. A directly stored pair wins
+// over the inverse of the opposite pair.
+func (r *pgExchangeRateRepo) GetRate(ctx context.Context, base, quote string) (*domain.ExchangeRate, error) {
+	const q = `
+		SELECT rate, updated_at
+		FROM (
+		    SELECT rate, updated_at, 0 AS pref
+		    FROM exchange_rates WHERE base_currency = $1 AND quote_currency = $2
+		    UNION ALL
+		    SELECT 1 / rate, updated_at, 1 AS pref
+		    FROM exchange_rates WHERE base_currency = $2 AND quote_currency = $1
+		) r
+		ORDER BY pref
+		LIMIT 1`
+
+	rate := &domain.ExchangeRate{Base: base, Quote: quote}
+	if err := r.db.QueryRowContext(ctx, q, base, quote).Scan(&rate.Rate, &rate.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no exchange rate from %s to %s: %w", base, quote, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get exchange rate: %w", err)
+	}
+	return rate, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"booking-app/internal/domain"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// pgHotelChargeRepo implements HotelChargeRepository using PostgreSQL.
+type pgHotelChargeRepo struct {
+	db *sql.DB
+}
+
+// NewHotelChargeRepo creates a new PostgreSQL-backed HotelChargeRepository.
+func NewHotelChargeRepo(db *sql.DB) HotelChargeRepository {
+	return &pgHotelChargeRepo{db: db}
+}
+
+// ReplaceCharges deletes a hotel's charges and inserts the given ones in one
+// transaction, filling in their ids.
+func (r *pgHotelChargeRepo) ReplaceCharges(ctx context.Context, hotelID int, charges []*domain.HotelCharge) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx for hotel charges: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM hotel_charges WHERE hotel_id = $1`, hotelID); err != nil {
+		return fmt.Errorf("clear hotel charges: %w", err)
+	}
+
+	const q = `
+		INSERT INTO hotel_charges (hotel_id, name, type, percent, amount, per_night, min_nights)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+	for _, c := range charges {
+		c.HotelID = hotelID
+		if err := tx.QueryRowContext(ctx, q,
+			hotelID, c.Name, c.Type, c.Percent, c.Amount, c.PerNight, c.MinNights,
+		).Scan(&c.ID); err != nil {
+			return fmt.Errorf("insert hotel charge %q: %w", c.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit hotel charges: %w", err)
+	}
+	return nil
+}
+
+// ListCharges returns a hotel's charges in the order they were configured.
+func (r *pgHotelChargeRepo) ListCharges(ctx context.Context, hotelID int) ([]*domain.HotelCharge, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, hotel_id, name, type, percent, amount, per_night, min_nights
+		FROM hotel_charges
+		WHERE hotel_id = $1
+		ORDER BY id`, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("list hotel charges: %w", err)
+	}
+	defer rows.Close()
+
+	charges := []*domain.HotelCharge{}
+	for rows.Next() {
+		c := &domain.HotelCharge{}
+		var percent sql.NullFloat64
+		var amount sql.NullInt64
+		if err := rows.Scan(&c.ID, &c.HotelID, &c.Name, &c.Type, &percent, &amount, &c.PerNight, &c.MinNights); err != nil {
+			return nil, fmt.Errorf("scan hotel charge: %w", err)
+		}
+		if percent.Valid {
+			c.Percent = &percent.Float64
+		}
+		c.Amount = nullInt64Ptr(amount)
+		charges = append(charges, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate hotel charges: %w", err)
+	}
+	return charges, nil
+}
@@ -26,8 +26,8 @@ func (r *pgHotelRepo) CreateHotel(ctx context.Context, hotel *domain.Hotel) (*do
 	const q = `
 		INSERT INTO hotels (owner_id, name, location, address, city, country,
 		                    latitude, longitude, amenities, images, star_rating,
-		                    status, description)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		                    status, description, currency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at, updated_at`
 
 	result := *hotel
@@ -45,6 +45,7 @@ func (r *pgHotelRepo) CreateHotel(ctx context.Context, hotel *domain.Hotel) (*do
 		hotel.StarRating,
 		string(hotel.Status),
 		hotel.Description,
+		hotel.Currency,
 	).Scan(&result.ID, &result.CreatedAt, &result.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("insert hotel: %w", err)
@@ -60,7 +61,7 @@ func (r *pgHotelRepo) GetHotelByID(ctx context.Context, id int) (*domain.Hotel,
 		       COALESCE(latitude, 0), COALESCE(longitude, 0),
 		       COALESCE(amenities, '{}'), COALESCE(images, '{}'),
 		       COALESCE(star_rating, 0), COALESCE(status, 'pending'), COALESCE(description, ''),
-		       COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
+		       COALESCE(currency, 'USD'), COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
 		FROM hotels WHERE id = $1`
 
 	hotel := &domain.Hotel{}
@@ -80,6 +81,7 @@ func (r *pgHotelRepo) GetHotelByID(ctx context.Context, id int) (*domain.Hotel,
 		&hotel.StarRating,
 		&hotel.Status,
 		&hotel.Description,
+		&hotel.Currency,
 		&hotel.CreatedAt,
 		&hotel.UpdatedAt,
 	)
@@ -111,7 +113,7 @@ func (r *pgHotelRepo) ListApprovedHotels(ctx context.Context, page, limit int) (
 		       COALESCE(latitude, 0), COALESCE(longitude, 0),
 		       COALESCE(amenities, '{}'), COALESCE(images, '{}'),
 		       COALESCE(star_rating, 0), COALESCE(status, 'pending'), COALESCE(description, ''),
-		       COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
+		       COALESCE(currency, 'USD'), COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
 		FROM hotels WHERE status = 'approved'
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2`, limit, offset)
@@ -144,7 +146,7 @@ func (r *pgHotelRepo) ListHotelsByOwner(ctx context.Context, ownerID string, pag
 		       COALESCE(latitude, 0), COALESCE(longitude, 0),
 		       COALESCE(amenities, '{}'), COALESCE(images, '{}'),
 		       COALESCE(star_rating, 0), COALESCE(status, 'pending'), COALESCE(description, ''),
-		       COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
+		       COALESCE(currency, 'USD'), COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
 		FROM hotels WHERE owner_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3`, ownerID, limit, offset)
@@ -177,7 +179,7 @@ func (r *pgHotelRepo) ListPendingHotels(ctx context.Context, page, limit int) ([
 		       COALESCE(latitude, 0), COALESCE(longitude, 0),
 		       COALESCE(amenities, '{}'), COALESCE(images, '{}'),
 		       COALESCE(star_rating, 0), COALESCE(status, 'pending'), COALESCE(description, ''),
-		       COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
+		       COALESCE(currency, 'USD'), COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
 		FROM hotels WHERE status = 'pending'
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2`, limit, offset)
@@ -199,8 +201,8 @@ func (r *pgHotelRepo) UpdateHotel(ctx context.Context, hotel *domain.Hotel) (*do
 		UPDATE hotels SET
 			name = $1, location = $2, address = $3, city = $4, country = $5,
 			latitude = $6, longitude = $7, amenities = $8, images = $9,
-			star_rating = $10, description = $11, updated_at = NOW()
-		WHERE id = $12
+			star_rating = $10, description = $11, currency = $12, updated_at = NOW()
+		WHERE id = $13
 		RETURNING updated_at`
 
 	result := *hotel
@@ -216,6 +218,7 @@ func (r *pgHotelRepo) UpdateHotel(ctx context.Context, hotel *domain.Hotel) (*do
 		pq.Array(hotel.Images),
 		hotel.StarRating,
 		hotel.Description,
+		hotel.Currency,
 		hotel.ID,
 	).Scan(&result.UpdatedAt)
 	if err != nil {
@@ -287,6 +290,7 @@ func scanHotelRows(rows *sql.Rows) ([]*domain.Hotel, error) {
 			&hotel.StarRating,
 			&hotel.Status,
 			&hotel.Description,
+			&hotel.Currency,
 			&hotel.CreatedAt,
 			&hotel.UpdatedAt,
 		); err != nil {
@@ -318,10 +322,11 @@ func NewRoomRepo(db *sql.DB) RoomRepository {
 // CreateRoom inserts a new room and returns the created record.
 func (r *pgRoomRepo) CreateRoom(ctx context.Context, room *domain.Room) (*domain.Room, error) {
 	const q = `
-		INSERT INTO rooms (hotel_id, name, description, capacity, price_per_night,
-		                   amenities, images, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, created_at, updated_at`
+		INSERT INTO rooms (hotel_id, name, description, capacity, max_extra_beds, extra_guest_fee,
+		                   price_per_night, amenities, images, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at, overbooking_type, overbooking_value,
+		          (SELECT currency FROM hotels WHERE id = hotel_id)`
 
 	result := *room
 	err := r.db.QueryRowContext(ctx, q,
@@ -329,11 +334,14 @@ func (r *pgRoomRepo) CreateRoom(ctx context.Context, room *domain.Room) (*domain
 		room.Name,
 		room.Description,
 		room.Capacity,
+		room.MaxExtraBeds,
+		room.ExtraGuestFee,
 		room.PricePerNight,
 		pq.Array(room.Amenities),
 		pq.Array(room.Images),
 		room.IsActive,
-	).Scan(&result.ID, &result.CreatedAt, &result.UpdatedAt)
+	).Scan(&result.ID, &result.CreatedAt, &result.UpdatedAt,
+		&result.Overbooking.Type, &result.Overbooking.Value, &result.Currency)
 	if err != nil {
 		return nil, fmt.Errorf("insert room: %w", err)
 	}
@@ -343,10 +351,14 @@ func (r *pgRoomRepo) CreateRoom(ctx context.Context, room *domain.Room) (*domain
 // GetRoomByID retrieves a room by its ID.
 func (r *pgRoomRepo) GetRoomByID(ctx context.Context, id int) (*domain.Room, error) {
 	const q = `
-		SELECT id, hotel_id, name, COALESCE(description, ''), capacity, price_per_night,
-		       COALESCE(amenities, '{}'), COALESCE(images, '{}'),
-		       COALESCE(is_active, true), COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
-		FROM rooms WHERE id = $1`
+		SELECT r.id, r.hotel_id, r.name, COALESCE(r.description, ''), r.capacity, r.max_extra_beds,
+		       r.extra_guest_fee, r.price_per_night, h.currency, COALESCE(r.amenities, '{}'),
+		       COALESCE(r.images, '{}'), COALESCE(r.is_active, true),
+		       COALESCE(r.created_at, NOW()), COALESCE(r.updated_at, NOW()),
+		       r.overbooking_type, r.overbooking_value
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.id = $1`
 
 	room := &domain.Room{}
 	var amenities, images pq.StringArray
@@ -356,12 +368,17 @@ func (r *pgRoomRepo) GetRoomByID(ctx context.Context, id int) (*domain.Room, err
 		&room.Name,
 		&room.Description,
 		&room.Capacity,
+		&room.MaxExtraBeds,
+		&room.ExtraGuestFee,
 		&room.PricePerNight,
+		&room.Currency,
 		&amenities,
 		&images,
 		&room.IsActive,
 		&room.CreatedAt,
 		&room.UpdatedAt,
+		&room.Overbooking.Type,
+		&room.Overbooking.Value,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -377,11 +394,15 @@ func (r *pgRoomRepo) GetRoomByID(ctx context.Context, id int) (*domain.Room, err
 // ListRoomsByHotel returns all active rooms for a given hotel.
 func (r *pgRoomRepo) ListRoomsByHotel(ctx context.Context, hotelID int) ([]*domain.Room, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, hotel_id, name, COALESCE(description, ''), capacity, price_per_night,
-		       COALESCE(amenities, '{}'), COALESCE(images, '{}'),
-		       COALESCE(is_active, true), COALESCE(created_at, NOW()), COALESCE(updated_at, NOW())
-		FROM rooms WHERE hotel_id = $1 AND COALESCE(is_active, true) = true
-		ORDER BY id`, hotelID)
+		SELECT r.id, r.hotel_id, r.name, COALESCE(r.description, ''), r.capacity, r.max_extra_beds,
+		       r.extra_guest_fee, r.price_per_night, h.currency, COALESCE(r.amenities, '{}'),
+		       COALESCE(r.images, '{}'), COALESCE(r.is_active, true),
+		       COALESCE(r.created_at, NOW()), COALESCE(r.updated_at, NOW()),
+		       r.overbooking_type, r.overbooking_value
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.hotel_id = $1 AND COALESCE(r.is_active, true) = true
+		ORDER BY r.id`, hotelID)
 	if err != nil {
 		return nil, fmt.Errorf("list rooms by hotel: %w", err)
 	}
@@ -393,22 +414,25 @@ func (r *pgRoomRepo) ListRoomsByHotel(ctx context.Context, hotelID int) ([]*doma
 func (r *pgRoomRepo) UpdateRoom(ctx context.Context, room *domain.Room) (*domain.Room, error) {
 	const q = `
 		UPDATE rooms SET
-			name = $1, description = $2, capacity = $3, price_per_night = $4,
-			amenities = $5, images = $6, is_active = $7, updated_at = NOW()
-		WHERE id = $8
-		RETURNING updated_at`
+			name = $1, description = $2, capacity = $3, max_extra_beds = $4, extra_guest_fee = $5,
+			price_per_night = $6, amenities = $7, images = $8, is_active = $9, updated_at = NOW()
+		WHERE id = $10
+		RETURNING updated_at, overbooking_type, overbooking_value,
+		          (SELECT currency FROM hotels WHERE id = hotel_id)`
 
 	result := *room
 	err := r.db.QueryRowContext(ctx, q,
 		room.Name,
 		room.Description,
 		room.Capacity,
+		room.MaxExtraBeds,
+		room.ExtraGuestFee,
 		room.PricePerNight,
 		pq.Array(room.Amenities),
 		pq.Array(room.Images),
 		room.IsActive,
 		room.ID,
-	).Scan(&result.UpdatedAt)
+	).Scan(&result.UpdatedAt, &result.Overbooking.Type, &result.Overbooking.Value, &result.Currency)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("room not found: %w", domain.ErrNotFound)
@@ -418,6 +442,22 @@ func (r *pgRoomRepo) UpdateRoom(ctx context.Context, room *domain.Room) (*domain
 	return &result, nil
 }
 
+// SetOverbooking replaces a room's overbooking allowance.
+func (r *pgRoomRepo) SetOverbooking(ctx context.Context, roomID int, overbooking domain.Overbooking) error {
+	const q = `
+		UPDATE rooms SET overbooking_type = $1, overbooking_value = $2, updated_at = NOW()
+		WHERE id = $3`
+	res, err := r.db.ExecContext(ctx, q, string(overbooking.Type), overbooking.Value, roomID)
+	if err != nil {
+		return fmt.Errorf("set room overbooking: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("room not found: %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
 // DeleteRoom soft-deletes a room by setting is_active = false.
 func (r *pgRoomRepo) DeleteRoom(ctx context.Context, id int, hotelID int) error {
 	const q = `UPDATE rooms SET is_active = false, updated_at = NOW() WHERE id = $1 AND hotel_id = $2`
@@ -458,12 +498,17 @@ func scanRoomRows(rows *sql.Rows) ([]*domain.Room, error) {
 			&room.Name,
 			&room.Description,
 			&room.Capacity,
+			&room.MaxExtraBeds,
+			&room.ExtraGuestFee,
 			&room.PricePerNight,
+			&room.Currency,
 			&amenities,
 			&images,
 			&room.IsActive,
 			&room.CreatedAt,
 			&room.UpdatedAt,
+			&room.Overbooking.Type,
+			&room.Overbooking.Value,
 		); err != nil {
 			return nil, fmt.Errorf("scan room row: %w", err)
 		}
@@ -505,7 +550,7 @@ func (r *pgInventoryRepo) SetInventory(ctx context.Context, roomID int, date tim
 // GetInventoryForRoom returns inventory records for a room within a date range.
 func (r *pgInventoryRepo) GetInventoryForRoom(ctx context.Context, roomID int, startDate, endDate time.Time) ([]*domain.Inventory, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, room_id, date, total_inventory, booked_count
+		SELECT id, room_id, date, total_inventory, booked_count, price, min_stay
 		FROM inventory
 		WHERE room_id = $1 AND date >= $2 AND date < $3
 		ORDER BY date`, roomID, startDate, endDate)
@@ -513,13 +558,100 @@ func (r *pgInventoryRepo) GetInventoryForRoom(ctx context.Context, roomID int, s
 		return nil, fmt.Errorf("get inventory for room: %w", err)
 	}
 	defer rows.Close()
+	return scanInventoryRows(rows)
+}
+
+// GetInventoryForHotel returns inventory records of a hotel's active rooms
+// within a date range.
+func (r *pgInventoryRepo) GetInventoryForHotel(ctx context.Context, hotelID int, startDate, endDate time.Time) ([]*domain.Inventory, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.id, i.room_id, i.date, i.total_inventory, i.booked_count, i.price, i.min_stay
+		FROM inventory i
+		JOIN rooms r ON r.id = i.room_id
+		WHERE r.hotel_id = $1 AND COALESCE(r.is_active, true) = true
+		  AND i.date >= $2 AND i.date < $3
+		ORDER BY i.room_id, i.date`, hotelID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("get inventory for hotel: %w", err)
+	}
+	defer rows.Close()
+	return scanInventoryRows(rows)
+}
 
+// StreamInventoryForHotel calls fn with each inventory record of a hotel's
+// active rooms within a date range as it is read.
+func (r *pgInventoryRepo) StreamInventoryForHotel(ctx context.Context, hotelID int, startDate, endDate time.Time, fn func(*domain.Inventory) error) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.id, i.room_id, i.date, i.total_inventory, i.booked_count, i.price, i.min_stay
+		FROM inventory i
+		JOIN rooms r ON r.id = i.room_id
+		WHERE r.hotel_id = $1 AND COALESCE(r.is_active, true) = true
+		  AND i.date >= $2 AND i.date < $3
+		ORDER BY i.room_id, i.date`, hotelID, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("stream inventory for hotel: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		inv := &domain.Inventory{}
+		var price, minStay sql.NullInt64
+		if err := rows.Scan(&inv.ID, &inv.RoomID, &inv.Date, &inv.TotalInventory, &inv.BookedCount, &price, &minStay); err != nil {
+			return fmt.Errorf("scan inventory row: %w", err)
+		}
+		inv.Price = nullInt64Ptr(price)
+		inv.MinStay = nullIntPtr(minStay)
+		if err := fn(inv); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate inventory rows: %w", err)
+	}
+	return nil
+}
+
+// ListOverbookedNights returns the nights within a date range on which a
+// hotel's active rooms are sold past their inventory, by date and room.
+func (r *pgInventoryRepo) ListOverbookedNights(ctx context.Context, hotelID int, startDate, endDate time.Time) ([]*domain.OverbookedNight, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.room_id, r.name, i.date, i.total_inventory, i.booked_count
+		FROM inventory i
+		JOIN rooms r ON r.id = i.room_id
+		WHERE r.hotel_id = $1 AND COALESCE(r.is_active, true) = true
+		  AND i.date >= $2 AND i.date < $3
+		  AND i.booked_count > i.total_inventory
+		ORDER BY i.date, i.room_id`, hotelID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("list overbooked nights: %w", err)
+	}
+	defer rows.Close()
+
+	nights := []*domain.OverbookedNight{}
+	for rows.Next() {
+		n := &domain.OverbookedNight{}
+		if err := rows.Scan(&n.RoomID, &n.RoomName, &n.Date, &n.TotalInventory, &n.BookedCount); err != nil {
+			return nil, fmt.Errorf("scan overbooked night: %w", err)
+		}
+		nights = append(nights, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate overbooked nights: %w", err)
+	}
+	return nights, nil
+}
+
+// scanInventoryRows scans multiple inventory rows into a slice.
+func scanInventoryRows(rows *sql.Rows) ([]*domain.Inventory, error) {
 	var invs []*domain.Inventory
 	for rows.Next() {
 		inv := &domain.Inventory{}
-		if err := rows.Scan(&inv.ID, &inv.RoomID, &inv.Date, &inv.TotalInventory, &inv.BookedCount); err != nil {
+		var price, minStay sql.NullInt64
+		if err := rows.Scan(&inv.ID, &inv.RoomID, &inv.Date, &inv.TotalInventory, &inv.BookedCount, &price, &minStay); err != nil {
 			return nil, fmt.Errorf("scan inventory row: %w", err)
 		}
+		inv.Price = nullInt64Ptr(price)
+		inv.MinStay = nullIntPtr(minStay)
 		invs = append(invs, inv)
 	}
 	if err := rows.Err(); err != nil {
@@ -535,7 +667,7 @@ func (r *pgInventoryRepo) GetInventoryForRoom(ctx context.Context, roomID int, s
 // Uses GREATEST(0, booked_count - amount) to prevent negative values.
 // This is the correct way to restore inventory after a failed or timed-out payment.
 func (r *pgInventoryRepo) BulkDecrementBookedCount(ctx context.Context, roomID int, startDate time.Time, days, amount int) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("begin tx for bulk decrement: %w", err)
 	}
@@ -584,3 +716,31 @@ func (r *pgInventoryRepo) BulkSetInventory(ctx context.Context, roomID int, star
 	}
 	return nil
 }
+
+// ImportInventory upserts imported inventory rows in one transaction. A row
+// without a price keeps the night's current price override.
+func (r *pgInventoryRepo) ImportInventory(ctx context.Context, rows []*domain.InventoryRow) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx for inventory import: %w", err)
+	}
+	defer tx.Rollback()
+
+	const q = `
+		INSERT INTO inventory (room_id, date, total_inventory, booked_count, price)
+		VALUES ($1, $2, $3, 0, $4)
+		ON CONFLICT (room_id, date) DO UPDATE
+		SET total_inventory = EXCLUDED.total_inventory,
+		    price           = COALESCE(EXCLUDED.price, inventory.price)`
+
+	for _, row := range rows {
+		if _, err := tx.ExecContext(ctx, q, row.RoomID, row.Date, row.Total, row.Price); err != nil {
+			return fmt.Errorf("import inventory line %d: %w", row.Line, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit inventory import: %w", err)
+	}
+	return nil
+}
@@ -10,32 +10,92 @@ import (
 type PaymentRepository interface {
 	CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error)
 	GetPaymentByID(ctx context.Context, id string) (*domain.Payment, error)
+	// GetPaymentForUpdate fetches a payment and locks its row until the
+	// surrounding unit of work ends.
+	GetPaymentForUpdate(ctx context.Context, id string) (*domain.Payment, error)
 	GetPaymentByBookingID(ctx context.Context, bookingID int) (*domain.Payment, error)
+	// ListTopUps returns the top-ups charged for a booking's modifications.
+	ListTopUps(ctx context.Context, bookingID int) ([]*domain.Payment, error)
 	UpdatePaymentStatus(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error
+	// DeclinePayment marks a payment failed with the gateway's decline code.
+	DeclinePayment(ctx context.Context, id, declineCode, reason string) error
+	// MarkAuthorized records an authorization left for the capture scheduler.
+	MarkAuthorized(ctx context.Context, id, authorizationRef string) error
+	// CapturePayment and VoidPayment settle an authorized payment; both
+	// return ErrConflict when it is no longer authorized. CapturePayment
+	// also returns it when amount is no longer what is authorized.
+	CapturePayment(ctx context.Context, id, captureRef string, amount int64) error
+	VoidPayment(ctx context.Context, id string) error
+	// ReduceAuthorization lowers the amount still to be captured and returns
+	// what is left.
+	ReduceAuthorization(ctx context.Context, id string, amount int64) (int64, error)
+	// ReduceAuthorizedAmount does the same but keeps the scheduled capture time.
+	ReduceAuthorizedAmount(ctx context.Context, id string, amount int64) (int64, error)
+	// ListDueCaptures returns authorized payments due for capture by before.
+	ListDueCaptures(ctx context.Context, before time.Time, limit int) ([]*domain.Payment, error)
 	GetPaymentByIdempotencyKey(ctx context.Context, key string) (*domain.Payment, error)
 }
 
+// RefundRepository defines data access operations for refunds against a charge.
+type RefundRepository interface {
+	// CreateRefund inserts a pending refund. Returns ErrConflict when the
+	// payment is not refundable or amount exceeds what is left on it.
+	CreateRefund(ctx context.Context, refund *domain.Refund) (*domain.Refund, error)
+	GetRefundByID(ctx context.Context, id string) (*domain.Refund, error)
+	GetRefundByIdempotencyKey(ctx context.Context, key string) (*domain.Refund, error)
+	ListRefundsByPayment(ctx context.Context, paymentID string) ([]*domain.Refund, error)
+	// CompleteRefund marks a pending refund succeeded and adds it to the
+	// payment's refunded total.
+	CompleteRefund(ctx context.Context, id, gatewayRef string) error
+	FailRefund(ctx context.Context, id, reason string) error
+}
+
 // OutboxRepository defines operations for the transactional outbox pattern.
 type OutboxRepository interface {
 	CreateEvent(ctx context.Context, event *domain.OutboxEvent) error
-	ListUnpublishedEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	// ClaimBatch leases pending events that are due to a relay, oldest first,
+	// taking no event while an earlier one of its aggregate is leased or
+	// backing off. ReleaseEvents hands back claimed events left unpublished.
+	ClaimBatch(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error)
+	ReleaseEvents(ctx context.Context, relayID string, ids []string) error
 	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
-	IncrementRetry(ctx context.Context, id string) error
+	// ScheduleRetry records a failed publish and when to try again;
+	// MarkDeadLettered records that the relay gave up on the event.
+	ScheduleRetry(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error
+	MarkDeadLettered(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error
 	IsEventProcessed(ctx context.Context, eventID string) (bool, error)
 	MarkProcessed(ctx context.Context, eventID string) error
+	// ClaimEvent records an event as processed, reporting false when it
+	// already was. Claimed inside a unit of work, the record commits or
+	// rolls back with the work the event caused.
+	ClaimEvent(ctx context.Context, eventID string) (bool, error)
 	// Admin DLQ operations
-	ListDLQEvents(ctx context.Context, maxRetries, page, limit int) ([]*domain.OutboxEvent, int, error)
+	ListDLQEvents(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error)
 	ResetDLQEvent(ctx context.Context, id string) error
 }
 
 // BookingRepository defines data access operations for bookings.
 type BookingRepository interface {
+	// CreateBooking reserves the stay and inserts the booking. When the
+	// booking carries a promotion, the code is redeemed in the same
+	// transaction; ErrConflict means its redemption limits were reached.
 	CreateBooking(ctx context.Context, booking *domain.Booking) error
 	InitializeInventory(ctx context.Context, roomID int, startDate time.Time, days int, total int) error
 	FindBookingByID(ctx context.Context, id int) (*domain.Booking, error)
 	ListBookingsByUser(ctx context.Context, userID string, page, limit int) ([]*domain.Booking, int, error)
 	UpdateBookingStatus(ctx context.Context, id int, status string) error
+	// CancelBooking and RefundBooking also release the booking's promo code.
 	CancelBooking(ctx context.Context, id int, userID string) error
+	// RefundBooking cancels a confirmed booking that is owed amount back; the
+	// money itself is returned by the refund saga.
+	RefundBooking(ctx context.Context, id int, userID string, amount int64) error
+	// ModifyBooking moves a booking to updated's room, dates and price and
+	// returns the booking as it was before the change.
+	ModifyBooking(ctx context.Context, updated *domain.Booking) (*domain.Booking, error)
+	// Itinerary operations: all legs are reserved in a single transaction.
+	CreateItinerary(ctx context.Context, itinerary *domain.Itinerary) error
+	FindItineraryByID(ctx context.Context, id int) (*domain.Itinerary, error)
+	ListBookingsByItinerary(ctx context.Context, itineraryID int) ([]*domain.Booking, error)
 	// Admin operations
 	ListAllBookings(ctx context.Context, page, limit int) ([]*domain.Booking, int, error)
 }
@@ -77,20 +137,113 @@ type RoomRepository interface {
 	GetRoomByID(ctx context.Context, id int) (*domain.Room, error)
 	ListRoomsByHotel(ctx context.Context, hotelID int) ([]*domain.Room, error)
 	UpdateRoom(ctx context.Context, room *domain.Room) (*domain.Room, error)
+	// SetOverbooking replaces a room's overbooking allowance.
+	SetOverbooking(ctx context.Context, roomID int, overbooking domain.Overbooking) error
 	DeleteRoom(ctx context.Context, id int, hotelID int) error
 }
 
+// CancellationPolicyRepository defines data access operations for cancellation policies.
+type CancellationPolicyRepository interface {
+	UpsertPolicy(ctx context.Context, policy *domain.CancellationPolicy) (*domain.CancellationPolicy, error)
+	// GetPolicyForRoom returns the room's own policy, falling back to its
+	// hotel's. Returns ErrNotFound when neither exists.
+	GetPolicyForRoom(ctx context.Context, roomID int) (*domain.CancellationPolicy, error)
+}
+
+// ExchangeRateRepository defines data access operations for exchange rates.
+type ExchangeRateRepository interface {
+	// UpsertRates stores rates, replacing any existing rate for the same pair.
+	UpsertRates(ctx context.Context, rates []*domain.ExchangeRate) error
+	ListRates(ctx context.Context) ([]*domain.ExchangeRate, error)
+	// GetRate returns the rate converting base to quote, derived from the
+	// inverse pair when only that is stored. Returns ErrNotFound otherwise.
+	GetRate(ctx context.Context, base, quote string) (*domain.ExchangeRate, error)
+}
+
 // InventoryRepository defines data access operations for room inventory.
 type InventoryRepository interface {
 	SetInventory(ctx context.Context, roomID int, date time.Time, total int) error
 	GetInventoryForRoom(ctx context.Context, roomID int, startDate, endDate time.Time) ([]*domain.Inventory, error)
+	// GetInventoryForHotel returns the inventory of every active room of a
+	// hotel within [startDate, endDate), ordered by room and date.
+	GetInventoryForHotel(ctx context.Context, hotelID int, startDate, endDate time.Time) ([]*domain.Inventory, error)
+	// ListOverbookedNights returns the nights within [startDate, endDate) on
+	// which a hotel's active rooms are sold past their inventory.
+	ListOverbookedNights(ctx context.Context, hotelID int, startDate, endDate time.Time) ([]*domain.OverbookedNight, error)
 	BulkSetInventory(ctx context.Context, roomID int, startDate time.Time, days, total int) error
+	// ImportInventory upserts the total and, where set, the price of every
+	// row in one transaction.
+	ImportInventory(ctx context.Context, rows []*domain.InventoryRow) error
+	// StreamInventoryForHotel calls fn with each inventory record of a
+	// hotel's active rooms within [startDate, endDate), ordered by room and
+	// date, without loading the range into memory. An error from fn stops
+	// the stream and is returned.
+	StreamInventoryForHotel(ctx context.Context, hotelID int, startDate, endDate time.Time, fn func(*domain.Inventory) error) error
 	// BulkDecrementBookedCount atomically decrements booked_count by amount for each
 	// day in the range [startDate, startDate+days). Used by the payment saga to restore
 	// inventory when a payment fails or times out. Never goes below zero.
 	BulkDecrementBookedCount(ctx context.Context, roomID int, startDate time.Time, days, amount int) error
 }
 
+// RatePlanRepository defines data access operations for room rate plans.
+type RatePlanRepository interface {
+	// GetRatePlan returns the rules of a room's rate plan that can price the
+	// nights in [from, to): its weekend rate and minimum stay, the seasons
+	// overlapping the range and the per-date overrides inside it. BasePrice
+	// and Currency are left for the caller to fill in from the room.
+	GetRatePlan(ctx context.Context, roomID int, from, to time.Time) (*domain.RatePlan, error)
+	// ReplaceRatePlan atomically replaces every rule of a room's rate plan.
+	ReplaceRatePlan(ctx context.Context, plan *domain.RatePlan) error
+}
+
+// HotelChargeRepository defines data access operations for the taxes, fees
+// and discounts hotels apply to their stays.
+type HotelChargeRepository interface {
+	// ReplaceCharges atomically replaces every charge of a hotel.
+	ReplaceCharges(ctx context.Context, hotelID int, charges []*domain.HotelCharge) error
+	ListCharges(ctx context.Context, hotelID int) ([]*domain.HotelCharge, error)
+}
+
+// PromotionRepository defines data access operations for promo codes. Codes
+// are redeemed by BookingRepository.CreateBooking, in the booking's
+// transaction.
+type PromotionRepository interface {
+	// CreatePromotion returns ErrConflict when the code is already taken.
+	CreatePromotion(ctx context.Context, p *domain.Promotion) error
+	GetPromotionByID(ctx context.Context, id int) (*domain.Promotion, error)
+	GetPromotionByCode(ctx context.Context, code string) (*domain.Promotion, error)
+	// ListPromotions lists the promotions createdBy created, or every
+	// promotion when createdBy is empty.
+	ListPromotions(ctx context.Context, createdBy string, page, limit int) ([]*domain.Promotion, int, error)
+	DeactivatePromotion(ctx context.Context, id int) error
+	// ReleaseRedemption gives back the code redeemed by a booking, if any.
+	ReleaseRedemption(ctx context.Context, bookingID int) error
+}
+
+// WaitlistRepository defines data access operations for the waitlist. An
+// offer holds its stay's inventory like a booking does, so a claimed offer
+// becomes a booking without reserving the stay again.
+type WaitlistRepository interface {
+	// CreateEntry returns ErrConflict when the guest already waits for the stay.
+	CreateEntry(ctx context.Context, e *domain.WaitlistEntry) error
+	GetEntryByID(ctx context.Context, id int) (*domain.WaitlistEntry, error)
+	ListEntriesByUser(ctx context.Context, userID string, page, limit int) ([]*domain.WaitlistEntry, int, error)
+	// CancelEntry withdraws a waiting or offered entry, releasing an offer's
+	// inventory, and returns the entry as it was before.
+	CancelEntry(ctx context.Context, id int, userID string) (*domain.WaitlistEntry, error)
+	// OfferNext offers the oldest waiting entries of a room whose stay
+	// overlaps [startDate, endDate), and is entirely free, a hold on it until
+	// expiresAt. Returns the offered entries.
+	OfferNext(ctx context.Context, roomID int, startDate, endDate, expiresAt time.Time) ([]*domain.WaitlistEntry, error)
+	// ClaimOffer inserts booking on the inventory an offered entry holds and
+	// marks the entry claimed. Returns ErrConflict when the offer is gone.
+	ClaimOffer(ctx context.Context, id int, booking *domain.Booking) error
+	ListExpiredOffers(ctx context.Context, before time.Time, limit int) ([]*domain.WaitlistEntry, error)
+	// ExpireOffer lapses an offer and releases its inventory. Returns
+	// ErrConflict when the entry is no longer offered.
+	ExpireOffer(ctx context.Context, id int) error
+}
+
 // SearchRepository defines hotel search operations backed by Elasticsearch.
 type SearchRepository interface {
 	// IndexHotel upserts a single hotel document.
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// OutboxChannel is the Postgres notification channel new outbox events are
+// announced on (see the outbox_events_notify trigger).
+const OutboxChannel = "outbox_events"
+
+const (
+	outboxListenerMinReconnect = 1 * time.Second
+	outboxListenerMaxReconnect = 30 * time.Second
+)
+
+// OutboxListener turns outbox_events notifications into relay wake-ups.
+// Notifications arriving while a wake-up is pending are folded into it, and
+// a reconnect also wakes the relay since notifications sent while the
+// connection was down are lost.
+type OutboxListener struct {
+	listener *pq.Listener
+	wakeups  chan struct{}
+}
+
+// ListenForOutboxEvents opens a dedicated connection to dsn and listens on
+// OutboxChannel until Close is called.
+func ListenForOutboxEvents(dsn string) (*OutboxListener, error) {
+	l := pq.NewListener(dsn, outboxListenerMinReconnect, outboxListenerMaxReconnect, nil)
+	if err := l.Listen(OutboxChannel); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("listen on %s: %w", OutboxChannel, err)
+	}
+
+	o := &OutboxListener{listener: l, wakeups: make(chan struct{}, 1)}
+	go o.forward()
+	return o, nil
+}
+
+// Wakeups delivers a value whenever outbox events may be waiting. It is
+// closed once the listener is.
+func (o *OutboxListener) Wakeups() <-chan struct{} {
+	return o.wakeups
+}
+
+// Close stops listening and releases the connection.
+func (o *OutboxListener) Close() error {
+	return o.listener.Close()
+}
+
+func (o *OutboxListener) forward() {
+	defer close(o.wakeups)
+	// The Notify channel is closed by Close and receives nil after a reconnect.
+	for range o.listener.Notify {
+		select {
+		case o.wakeups <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"booking-app/internal/domain"
+	"booking-app/internal/observability"
 	"context"
 	"database/sql"
 	"errors"
@@ -21,35 +22,32 @@ func NewPaymentRepo(db *sql.DB) PaymentRepository {
 	return &paymentRepo{db: db}
 }
 
+const paymentColumns = `
+	id, booking_id, amount, currency, status, idempotency_key, kind, refunded_amount,
+	COALESCE(gateway_ref, '') AS gateway_ref,
+	COALESCE(failed_reason, '') AS failed_reason,
+	COALESCE(payment_method, '') AS payment_method,
+	COALESCE(decline_code, '') AS decline_code,
+	COALESCE(authorization_ref, '') AS authorization_ref,
+	capture_at, created_at, updated_at`
+
 // CreatePayment inserts a new payment record.
 func (r *paymentRepo) CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error) {
 	const q = `
-		INSERT INTO payments (booking_id, amount, currency, status, idempotency_key)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, booking_id, amount, currency, status, idempotency_key,
-		          COALESCE(gateway_ref, '') AS gateway_ref,
-		          COALESCE(failed_reason, '') AS failed_reason,
-		          created_at, updated_at
-	`
-	created := &domain.Payment{}
-	err := r.db.QueryRowContext(ctx, q,
+		INSERT INTO payments (booking_id, amount, currency, status, idempotency_key, kind, payment_method, capture_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, ''), 'charge'), NULLIF($7, ''), $8)
+		RETURNING` + paymentColumns
+
+	created, err := scanPayment(conn(ctx, r.db).QueryRowContext(ctx, q,
 		payment.BookingID,
 		payment.Amount,
 		payment.Currency,
 		payment.Status,
 		payment.IdempotencyKey,
-	).Scan(
-		&created.ID,
-		&created.BookingID,
-		&created.Amount,
-		&created.Currency,
-		&created.Status,
-		&created.IdempotencyKey,
-		&created.GatewayRef,
-		&created.FailedReason,
-		&created.CreatedAt,
-		&created.UpdatedAt,
-	)
+		payment.Kind,
+		payment.PaymentMethod,
+		payment.CaptureAt,
+	))
 	if err != nil {
 		var pgErr *pq.Error
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -62,19 +60,8 @@ func (r *paymentRepo) CreatePayment(ctx context.Context, payment *domain.Payment
 
 // GetPaymentByID fetches a single payment by primary key.
 func (r *paymentRepo) GetPaymentByID(ctx context.Context, id string) (*domain.Payment, error) {
-	const q = `
-		SELECT id, booking_id, amount, currency, status, idempotency_key,
-		       COALESCE(gateway_ref, '') AS gateway_ref,
-		       COALESCE(failed_reason, '') AS failed_reason,
-		       created_at, updated_at
-		FROM payments WHERE id = $1
-	`
-	p := &domain.Payment{}
-	err := r.db.QueryRowContext(ctx, q, id).Scan(
-		&p.ID, &p.BookingID, &p.Amount, &p.Currency, &p.Status,
-		&p.IdempotencyKey, &p.GatewayRef, &p.FailedReason,
-		&p.CreatedAt, &p.UpdatedAt,
-	)
+	q := `SELECT` + paymentColumns + ` FROM payments WHERE id = $1`
+	p, err := scanPayment(conn(ctx, r.db).QueryRowContext(ctx, q, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("payment %q not found: %w", id, domain.ErrNotFound)
@@ -84,21 +71,30 @@ func (r *paymentRepo) GetPaymentByID(ctx context.Context, id string) (*domain.Pa
 	return p, nil
 }
 
-// GetPaymentByBookingID fetches the payment associated with a booking.
+// GetPaymentForUpdate fetches a payment with SELECT ... FOR UPDATE, holding
+// the row lock until the transaction in ctx ends. Outside one the lock is
+// released as soon as the row is read.
+func (r *paymentRepo) GetPaymentForUpdate(ctx context.Context, id string) (*domain.Payment, error) {
+	q := `SELECT` + paymentColumns + ` FROM payments WHERE id = $1 FOR UPDATE`
+	p, err := scanPayment(conn(ctx, r.db).QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("payment %q not found: %w", id, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("lock payment: %w", err)
+	}
+	return p, nil
+}
+
+// GetPaymentByBookingID fetches the most recent checkout charge of a booking.
+// Top-ups and refunds created by booking modifications are not returned.
 func (r *paymentRepo) GetPaymentByBookingID(ctx context.Context, bookingID int) (*domain.Payment, error) {
-	const q = `
-		SELECT id, booking_id, amount, currency, status, idempotency_key,
-		       COALESCE(gateway_ref, '') AS gateway_ref,
-		       COALESCE(failed_reason, '') AS failed_reason,
-		       created_at, updated_at
-		FROM payments WHERE booking_id = $1 LIMIT 1
-	`
-	p := &domain.Payment{}
-	err := r.db.QueryRowContext(ctx, q, bookingID).Scan(
-		&p.ID, &p.BookingID, &p.Amount, &p.Currency, &p.Status,
-		&p.IdempotencyKey, &p.GatewayRef, &p.FailedReason,
-		&p.CreatedAt, &p.UpdatedAt,
-	)
+	q := `
+		SELECT` + paymentColumns + `
+		FROM payments WHERE booking_id = $1 AND kind = 'charge'
+		ORDER BY created_at DESC
+		LIMIT 1`
+	p, err := scanPayment(conn(ctx, r.db).QueryRowContext(ctx, q, bookingID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("payment for booking %d not found: %w", bookingID, domain.ErrNotFound)
@@ -108,42 +104,237 @@ func (r *paymentRepo) GetPaymentByBookingID(ctx context.Context, bookingID int)
 	return p, nil
 }
 
+// ListTopUps returns the top-up payments booking modifications charged on
+// top of a booking's checkout charge, oldest first.
+func (r *paymentRepo) ListTopUps(ctx context.Context, bookingID int) ([]*domain.Payment, error) {
+	q := `
+		SELECT` + paymentColumns + `
+		FROM payments WHERE booking_id = $1 AND kind = 'top_up'
+		ORDER BY created_at ASC`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("list top-ups: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []*domain.Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan top-up: %w", err)
+		}
+		payments = append(payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate top-ups: %w", err)
+	}
+	return payments, nil
+}
+
 // UpdatePaymentStatus updates status, gateway_ref, failed_reason, and updated_at.
+// An empty gatewayRef or failedReason keeps the stored value, so the saga
+// confirming a payment does not erase the reference the gateway returned.
+// A payment already in status is left as it is; ErrConflict is returned when
+// the payment state machine does not allow the move.
 func (r *paymentRepo) UpdatePaymentStatus(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error {
 	const q = `
 		UPDATE payments
 		SET status = $1,
-		    gateway_ref = NULLIF($2, ''),
-		    failed_reason = NULLIF($3, ''),
+		    gateway_ref = COALESCE(NULLIF($2, ''), gateway_ref),
+		    failed_reason = COALESCE(NULLIF($3, ''), failed_reason),
 		    updated_at = NOW()
-		WHERE id = $4
+		WHERE id = $4 AND status = ANY($5)
 	`
-	res, err := r.db.ExecContext(ctx, q, status, gatewayRef, failedReason, id)
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, status, gatewayRef, failedReason, id,
+		pq.Array(domain.PaymentStatusesBefore(status)))
 	if err != nil {
 		return fmt.Errorf("update payment status: %w", err)
 	}
 	rows, _ := res.RowsAffected()
 	if rows == 0 {
+		return r.unmoved(ctx, id, status)
+	}
+	return nil
+}
+
+// DeclinePayment marks a payment failed with the gateway's decline code.
+// Returns ErrConflict when the payment can no longer fail.
+func (r *paymentRepo) DeclinePayment(ctx context.Context, id, declineCode, reason string) error {
+	const q = `
+		UPDATE payments
+		SET status = 'failed',
+		    decline_code = NULLIF($1, ''),
+		    failed_reason = NULLIF($2, ''),
+		    updated_at = NOW()
+		WHERE id = $3 AND status = ANY($4)
+	`
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, declineCode, reason, id,
+		pq.Array(domain.PaymentStatusesBefore(domain.PaymentStatusFailed)))
+	if err != nil {
+		return fmt.Errorf("decline payment: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return r.unmoved(ctx, id, domain.PaymentStatusFailed)
+	}
+	return nil
+}
+
+// MarkAuthorized records an approved authorization that is left for the
+// capture scheduler. Returns ErrConflict when the payment is no longer in
+// flight, e.g. because its booking's hold expired meanwhile.
+func (r *paymentRepo) MarkAuthorized(ctx context.Context, id, authorizationRef string) error {
+	const q = `
+		UPDATE payments
+		SET status = 'authorized',
+		    authorization_ref = $1,
+		    updated_at = NOW()
+		WHERE id = $2 AND status = ANY($3)
+	`
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, authorizationRef, id,
+		pq.Array(domain.PaymentStatusesBefore(domain.PaymentStatusAuthorized)))
+	if err != nil {
+		return fmt.Errorf("mark payment authorized: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return r.unmoved(ctx, id, domain.PaymentStatusAuthorized)
+	}
+	return nil
+}
+
+// unmoved explains why a guarded update of a payment to status changed no
+// row: the payment does not exist (ErrNotFound), already has status (nil) or
+// may not move to it from its current status (ErrConflict).
+func (r *paymentRepo) unmoved(ctx context.Context, id string, status domain.PaymentStatus) error {
+	var current domain.PaymentStatus
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT status FROM payments WHERE id = $1`, id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("payment %q not found: %w", id, domain.ErrNotFound)
 	}
+	if err != nil {
+		return fmt.Errorf("get payment status: %w", err)
+	}
+	if current == status {
+		return nil
+	}
+	return fmt.Errorf("payment %q cannot move from %s to %s: %w", id, current, status, domain.ErrConflict)
+}
+
+// CapturePayment moves an authorized payment to captured. Returns ErrConflict
+// when the payment is no longer authorized, or no longer authorized for the
+// amount that was captured.
+func (r *paymentRepo) CapturePayment(ctx context.Context, id, captureRef string, amount int64) error {
+	const q = `
+		UPDATE payments
+		SET status = 'captured',
+		    gateway_ref = $1,
+		    updated_at = NOW()
+		WHERE id = $2 AND status = 'authorized' AND amount = $3
+	`
+	return r.transitionAuthorized(ctx, "capture", q, captureRef, id, amount)
+}
+
+// VoidPayment moves an authorized payment to voided. Returns ErrConflict
+// when the payment is no longer authorized.
+func (r *paymentRepo) VoidPayment(ctx context.Context, id string) error {
+	const q = `
+		UPDATE payments
+		SET status = 'voided',
+		    updated_at = NOW()
+		WHERE id = $1 AND status = 'authorized'
+	`
+	return r.transitionAuthorized(ctx, "void", q, id)
+}
+
+func (r *paymentRepo) transitionAuthorized(ctx context.Context, op, q string, args ...any) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("%s payment: %w", op, err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("payment is not authorized: %w", domain.ErrConflict)
+	}
 	return nil
 }
 
-// GetPaymentByIdempotencyKey fetches a payment by its idempotency key.
-func (r *paymentRepo) GetPaymentByIdempotencyKey(ctx context.Context, key string) (*domain.Payment, error) {
+// ReduceAuthorization lowers the amount still to be captured on an authorized
+// payment and returns what is left. Whatever is left becomes due for capture
+// now rather than at the scheduled time. Returns ErrConflict when the payment
+// is no longer authorized or holds less than amount.
+func (r *paymentRepo) ReduceAuthorization(ctx context.Context, id string, amount int64) (int64, error) {
 	const q = `
-		SELECT id, booking_id, amount, currency, status, idempotency_key,
-		       COALESCE(gateway_ref, '') AS gateway_ref,
-		       COALESCE(failed_reason, '') AS failed_reason,
-		       created_at, updated_at
-		FROM payments WHERE idempotency_key = $1
+		UPDATE payments
+		SET amount = amount - $1,
+		    capture_at = LEAST(capture_at, NOW()),
+		    updated_at = NOW()
+		WHERE id = $2 AND status = 'authorized' AND amount >= $1
+		RETURNING amount
 	`
-	p := &domain.Payment{}
-	err := r.db.QueryRowContext(ctx, q, key).Scan(
-		&p.ID, &p.BookingID, &p.Amount, &p.Currency, &p.Status,
-		&p.IdempotencyKey, &p.GatewayRef, &p.FailedReason,
-		&p.CreatedAt, &p.UpdatedAt,
-	)
+	return r.reduceAuthorized(ctx, q, id, amount)
+}
+
+// ReduceAuthorizedAmount lowers the amount still to be captured on an
+// authorized payment and returns what is left, which stays due for capture
+// at its scheduled time. Returns ErrConflict when the payment is no longer
+// authorized or holds less than amount.
+func (r *paymentRepo) ReduceAuthorizedAmount(ctx context.Context, id string, amount int64) (int64, error) {
+	const q = `
+		UPDATE payments
+		SET amount = amount - $1,
+		    updated_at = NOW()
+		WHERE id = $2 AND status = 'authorized' AND amount >= $1
+		RETURNING amount
+	`
+	return r.reduceAuthorized(ctx, q, id, amount)
+}
+
+func (r *paymentRepo) reduceAuthorized(ctx context.Context, q, id string, amount int64) (int64, error) {
+	var remaining int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, q, amount, id).Scan(&remaining); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("payment %q cannot release %d: %w", id, amount, domain.ErrConflict)
+		}
+		return 0, fmt.Errorf("reduce authorization: %w", err)
+	}
+	return remaining, nil
+}
+
+// ListDueCaptures returns authorized payments whose capture time is at or
+// before before, oldest first. Fully released authorizations, which are left
+// for the void, are skipped.
+func (r *paymentRepo) ListDueCaptures(ctx context.Context, before time.Time, limit int) ([]*domain.Payment, error) {
+	q := `
+		SELECT` + paymentColumns + `
+		FROM payments
+		WHERE status = 'authorized' AND capture_at <= $1 AND amount > 0
+		ORDER BY capture_at ASC
+		LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, q, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list due captures: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []*domain.Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan due capture: %w", err)
+		}
+		payments = append(payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate due captures: %w", err)
+	}
+	return payments, nil
+}
+
+// GetPaymentByIdempotencyKey fetches a payment by its idempotency key.
+func (r *paymentRepo) GetPaymentByIdempotencyKey(ctx context.Context, key string) (*domain.Payment, error) {
+	q := `SELECT` + paymentColumns + ` FROM payments WHERE idempotency_key = $1`
+	p, err := scanPayment(conn(ctx, r.db).QueryRowContext(ctx, q, key))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("payment with key %q not found: %w", key, domain.ErrNotFound)
@@ -153,6 +344,23 @@ func (r *paymentRepo) GetPaymentByIdempotencyKey(ctx context.Context, key string
 	return p, nil
 }
 
+func scanPayment(row rowScanner) (*domain.Payment, error) {
+	p := &domain.Payment{}
+	var captureAt sql.NullTime
+	if err := row.Scan(
+		&p.ID, &p.BookingID, &p.Amount, &p.Currency, &p.Status,
+		&p.IdempotencyKey, &p.Kind, &p.RefundedAmount, &p.GatewayRef, &p.FailedReason,
+		&p.PaymentMethod, &p.DeclineCode, &p.AuthorizationRef,
+		&captureAt, &p.CreatedAt, &p.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if captureAt.Valid {
+		p.CaptureAt = &captureAt.Time
+	}
+	return p, nil
+}
+
 // outboxRepo implements OutboxRepository backed by PostgreSQL.
 type outboxRepo struct {
 	db *sql.DB
@@ -163,18 +371,41 @@ func NewOutboxRepo(db *sql.DB) OutboxRepository {
 	return &outboxRepo{db: db}
 }
 
-// CreateEvent inserts a new outbox event.
+const outboxColumns = `
+	id, aggregate_type, aggregate_id, event_type, payload,
+	published_at, retry_count, created_at, COALESCE(correlation_id, '') AS correlation_id,
+	status, next_attempt_at, COALESCE(last_error, '') AS last_error, dead_lettered_at`
+
+func scanOutboxEvent(row rowScanner) (*domain.OutboxEvent, error) {
+	e := &domain.OutboxEvent{}
+	err := row.Scan(
+		&e.ID, &e.AggregateType, &e.AggregateID, &e.EventType,
+		&e.Payload, &e.PublishedAt, &e.RetryCount, &e.CreatedAt, &e.CorrelationID,
+		&e.Status, &e.NextAttemptAt, &e.LastError, &e.DeadLetteredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// CreateEvent inserts a new outbox event. An event without a correlation ID
+// takes the one of the request or message being handled.
 func (r *outboxRepo) CreateEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	if event.CorrelationID == "" {
+		event.CorrelationID = observability.CorrelationID(ctx)
+	}
 	const q = `
-		INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload, correlation_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
 		RETURNING id, created_at
 	`
-	err := r.db.QueryRowContext(ctx, q,
+	err := conn(ctx, r.db).QueryRowContext(ctx, q,
 		event.AggregateType,
 		event.AggregateID,
 		event.EventType,
 		event.Payload,
+		event.CorrelationID,
 	).Scan(&event.ID, &event.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("create outbox event: %w", err)
@@ -182,30 +413,69 @@ func (r *outboxRepo) CreateEvent(ctx context.Context, event *domain.OutboxEvent)
 	return nil
 }
 
-// ListUnpublishedEvents returns unpublished events ordered by created_at ascending,
-// with a limit on the count.
-func (r *outboxRepo) ListUnpublishedEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+// ClaimBatch leases up to limit pending events that are due to relayID for
+// lease and returns them in the order they were written. An aggregate is only
+// taken when its oldest pending event is free and due, and no event is taken
+// behind one still leased or waiting to be retried, so one aggregate's events
+// are never published out of order. Rows another relay is claiming at the
+// same time are skipped.
+func (r *outboxRepo) ClaimBatch(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
 	const q = `
-		SELECT id, aggregate_type, aggregate_id, event_type, payload,
-		       published_at, retry_count, created_at
-		FROM outbox_events
-		WHERE published_at IS NULL
-		ORDER BY created_at ASC
-		LIMIT $1
+		WITH heads AS (
+			SELECT e.aggregate_type, e.aggregate_id
+			FROM outbox_events e
+			WHERE e.status = 'pending'
+			  AND e.next_attempt_at <= NOW()
+			  AND (e.locked_until IS NULL OR e.locked_until < NOW())
+			  AND NOT EXISTS (
+				SELECT 1 FROM outbox_events p
+				WHERE p.aggregate_type = e.aggregate_type
+				  AND p.aggregate_id = e.aggregate_id
+				  AND p.status = 'pending'
+				  AND p.seq < e.seq
+			  )
+			ORDER BY e.seq
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), batch AS (
+			SELECT o.id AS claim_id
+			FROM outbox_events o
+			JOIN heads h ON h.aggregate_type = o.aggregate_type AND h.aggregate_id = o.aggregate_id
+			WHERE o.status = 'pending'
+			  AND o.next_attempt_at <= NOW()
+			  AND (o.locked_until IS NULL OR o.locked_until < NOW())
+			  AND NOT EXISTS (
+				SELECT 1 FROM outbox_events l
+				WHERE l.aggregate_type = o.aggregate_type
+				  AND l.aggregate_id = o.aggregate_id
+				  AND l.status = 'pending'
+				  AND l.seq < o.seq
+				  AND (l.locked_until >= NOW() OR l.next_attempt_at > NOW())
+			  )
+			ORDER BY o.seq
+			LIMIT $1
+			FOR UPDATE OF o SKIP LOCKED
+		), claimed AS (
+			UPDATE outbox_events o
+			SET locked_by = $2, locked_until = NOW() + make_interval(secs => $3)
+			FROM batch b
+			WHERE o.id = b.claim_id
+			RETURNING o.*
+		)
+		SELECT` + outboxColumns + `
+		FROM claimed
+		ORDER BY seq
 	`
-	rows, err := r.db.QueryContext(ctx, q, limit)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, q, limit, relayID, lease.Seconds())
 	if err != nil {
-		return nil, fmt.Errorf("list unpublished events: %w", err)
+		return nil, fmt.Errorf("claim outbox events: %w", err)
 	}
 	defer rows.Close()
 
 	var events []*domain.OutboxEvent
 	for rows.Next() {
-		e := &domain.OutboxEvent{}
-		if err := rows.Scan(
-			&e.ID, &e.AggregateType, &e.AggregateID, &e.EventType,
-			&e.Payload, &e.PublishedAt, &e.RetryCount, &e.CreatedAt,
-		); err != nil {
+		e, err := scanOutboxEvent(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan outbox event: %w", err)
 		}
 		events = append(events, e)
@@ -216,22 +486,66 @@ func (r *outboxRepo) ListUnpublishedEvents(ctx context.Context, limit int) ([]*d
 	return events, nil
 }
 
-// MarkPublished sets the published_at timestamp for an event.
+// ReleaseEvents gives up relayID's lease on events it claimed but did not
+// publish, so any relay can claim them on its next pass.
+func (r *outboxRepo) ReleaseEvents(ctx context.Context, relayID string, ids []string) error {
+	const q = `
+		UPDATE outbox_events SET locked_by = NULL, locked_until = NULL
+		WHERE id = ANY($1) AND locked_by = $2
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, pq.Array(ids), relayID)
+	if err != nil {
+		return fmt.Errorf("release outbox events: %w", err)
+	}
+	return nil
+}
+
+// MarkPublished records that an event was delivered to the broker and ends
+// its lease.
 func (r *outboxRepo) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
-	const q = `UPDATE outbox_events SET published_at = $1 WHERE id = $2`
-	_, err := r.db.ExecContext(ctx, q, publishedAt, id)
+	const q = `
+		UPDATE outbox_events
+		SET status = 'published', published_at = $1, locked_by = NULL, locked_until = NULL
+		WHERE id = $2
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, publishedAt, id)
 	if err != nil {
 		return fmt.Errorf("mark outbox event published: %w", err)
 	}
 	return nil
 }
 
-// IncrementRetry bumps the retry_count for an event.
-func (r *outboxRepo) IncrementRetry(ctx context.Context, id string) error {
-	const q = `UPDATE outbox_events SET retry_count = retry_count + 1 WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, q, id)
+// ScheduleRetry counts a failed attempt at publishing an event, keeps its
+// error and ends its lease; the event is not claimed again before
+// nextAttemptAt.
+func (r *outboxRepo) ScheduleRetry(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error {
+	const q = `
+		UPDATE outbox_events
+		SET retry_count = retry_count + 1, last_error = $2, next_attempt_at = $3,
+		    locked_by = NULL, locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, id, lastErr, nextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("schedule outbox event retry: %w", err)
+	}
+	return nil
+}
+
+// MarkDeadLettered records that the relay gave up on an event after
+// retryCount failed attempts, the last failing with lastErr, and sent it to
+// the dead-letter exchange. An empty lastErr keeps the recorded one.
+func (r *outboxRepo) MarkDeadLettered(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error {
+	const q = `
+		UPDATE outbox_events
+		SET status = 'dead_lettered', dead_lettered_at = $4, retry_count = $2,
+		    last_error = COALESCE(NULLIF($3, ''), last_error),
+		    locked_by = NULL, locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, id, retryCount, lastErr, deadLetteredAt)
 	if err != nil {
-		return fmt.Errorf("increment retry count: %w", err)
+		return fmt.Errorf("mark outbox event dead-lettered: %w", err)
 	}
 	return nil
 }
@@ -240,7 +554,7 @@ func (r *outboxRepo) IncrementRetry(ctx context.Context, id string) error {
 func (r *outboxRepo) IsEventProcessed(ctx context.Context, eventID string) (bool, error) {
 	const q = `SELECT 1 FROM processed_events WHERE event_id = $1`
 	var dummy int
-	err := r.db.QueryRowContext(ctx, q, eventID).Scan(&dummy)
+	err := conn(ctx, r.db).QueryRowContext(ctx, q, eventID).Scan(&dummy)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
@@ -256,34 +570,48 @@ func (r *outboxRepo) MarkProcessed(ctx context.Context, eventID string) error {
 		INSERT INTO processed_events (event_id) VALUES ($1)
 		ON CONFLICT (event_id) DO NOTHING
 	`
-	_, err := r.db.ExecContext(ctx, q, eventID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, q, eventID)
 	if err != nil {
 		return fmt.Errorf("mark event processed: %w", err)
 	}
 	return nil
 }
 
-// ListDLQEvents returns outbox events where retry_count >= maxRetries, paginated.
-func (r *outboxRepo) ListDLQEvents(ctx context.Context, maxRetries, page, limit int) ([]*domain.OutboxEvent, int, error) {
+// ClaimEvent records an event as processed, reporting false when it already
+// was. Inside a unit of work the claim commits with the work; a concurrent
+// claim of the same event waits until the unit of work ends.
+func (r *outboxRepo) ClaimEvent(ctx context.Context, eventID string) (bool, error) {
+	const q = `
+		INSERT INTO processed_events (event_id) VALUES ($1)
+		ON CONFLICT (event_id) DO NOTHING
+	`
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, eventID)
+	if err != nil {
+		return false, fmt.Errorf("claim event: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+// ListDLQEvents returns dead-lettered outbox events, most recently
+// dead-lettered first, paginated.
+func (r *outboxRepo) ListDLQEvents(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error) {
 	offset := (page - 1) * limit
 
 	var total int
-	if err := r.db.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM outbox_events WHERE retry_count >= $1`,
-		maxRetries,
+	if err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM outbox_events WHERE status = 'dead_lettered'`,
 	).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("count DLQ events: %w", err)
 	}
 
-	const q = `
-		SELECT id, aggregate_type, aggregate_id, event_type, payload,
-		       published_at, retry_count, created_at
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT`+outboxColumns+`
 		FROM outbox_events
-		WHERE retry_count >= $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-	rows, err := r.db.QueryContext(ctx, q, maxRetries, limit, offset)
+		WHERE status = 'dead_lettered'
+		ORDER BY dead_lettered_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("list DLQ events: %w", err)
 	}
@@ -291,11 +619,8 @@ func (r *outboxRepo) ListDLQEvents(ctx context.Context, maxRetries, page, limit
 
 	var events []*domain.OutboxEvent
 	for rows.Next() {
-		e := &domain.OutboxEvent{}
-		if err := rows.Scan(
-			&e.ID, &e.AggregateType, &e.AggregateID, &e.EventType,
-			&e.Payload, &e.PublishedAt, &e.RetryCount, &e.CreatedAt,
-		); err != nil {
+		e, err := scanOutboxEvent(rows)
+		if err != nil {
 			return nil, 0, fmt.Errorf("scan DLQ event: %w", err)
 		}
 		events = append(events, e)
@@ -309,16 +634,31 @@ func (r *outboxRepo) ListDLQEvents(ctx context.Context, maxRetries, page, limit
 	return events, total, nil
 }
 
-// ResetDLQEvent sets retry_count=0 and published_at=NULL for an event so it is retried.
+// ResetDLQEvent puts a dead-lettered event back in the outbox with a fresh
+// set of attempts, due immediately. Returns ErrConflict when the event is not
+// dead-lettered.
 func (r *outboxRepo) ResetDLQEvent(ctx context.Context, id string) error {
-	const q = `UPDATE outbox_events SET retry_count = 0, published_at = NULL WHERE id = $1`
-	res, err := r.db.ExecContext(ctx, q, id)
+	const q = `
+		UPDATE outbox_events
+		SET status = 'pending', retry_count = 0, next_attempt_at = NOW(),
+		    dead_lettered_at = NULL, published_at = NULL, locked_by = NULL, locked_until = NULL
+		WHERE id = $1 AND status = 'dead_lettered'
+	`
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, id)
 	if err != nil {
 		return fmt.Errorf("reset DLQ event: %w", err)
 	}
-	n, _ := res.RowsAffected()
-	if n == 0 {
-		return fmt.Errorf("outbox event %q not found: %w", id, domain.ErrNotFound)
+	if n, _ := res.RowsAffected(); n == 1 {
+		return nil
 	}
-	return nil
+
+	var status domain.OutboxStatus
+	err = conn(ctx, r.db).QueryRowContext(ctx, `SELECT status FROM outbox_events WHERE id = $1`, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("outbox event %q not found: %w", id, domain.ErrNotFound)
+		}
+		return fmt.Errorf("get outbox event status: %w", err)
+	}
+	return fmt.Errorf("outbox event %q is %s, not dead-lettered: %w", id, status, domain.ErrConflict)
 }
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"booking-app/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// pgPromotionRepo implements PromotionRepository using PostgreSQL. Codes are
+// redeemed by BookingRepo.CreateBooking, inside the booking's transaction.
+type pgPromotionRepo struct {
+	db *sql.DB
+}
+
+// NewPromotionRepo creates a new PostgreSQL-backed PromotionRepository.
+func NewPromotionRepo(db *sql.DB) PromotionRepository {
+	return &pgPromotionRepo{db: db}
+}
+
+// promotionColumns is the column list read by scanPromotion. Redemptions
+// counts the bookings still holding the code.
+const promotionColumns = `
+		p.id, p.code, p.description, p.created_by, p.hotel_id, p.room_id, p.percent, p.amount,
+		COALESCE(p.currency, ''), p.valid_from, p.valid_until, p.min_nights, p.max_redemptions,
+		p.max_per_user, p.active, p.created_at,
+		(SELECT COUNT(*) FROM promotion_redemptions pr
+		 WHERE pr.promotion_id = p.id AND pr.released_at IS NULL)`
+
+// CreatePromotion inserts a promotion and fills in its id and created_at.
+// Returns ErrConflict when the code is already taken.
+func (r *pgPromotionRepo) CreatePromotion(ctx context.Context, p *domain.Promotion) error {
+	var currency *string
+	if p.Currency != "" {
+		currency = &p.Currency
+	}
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO promotions (code, description, created_by, hotel_id, room_id, percent, amount, currency,
+		                        valid_from, valid_until, min_nights, max_redemptions, max_per_user, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, TRUE)
+		RETURNING id, active, created_at`,
+		p.Code, p.Description, p.CreatedBy, p.HotelID, p.RoomID, p.Percent, p.Amount, currency,
+		p.ValidFrom, p.ValidUntil, p.MinNights, p.MaxRedemptions, p.MaxPerUser,
+	).Scan(&p.ID, &p.Active, &p.CreatedAt)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("promo code %s already exists: %w", p.Code, domain.ErrConflict)
+		}
+		return fmt.Errorf("insert promotion: %w", err)
+	}
+	return nil
+}
+
+// GetPromotionByID returns a promotion by id, or ErrNotFound.
+func (r *pgPromotionRepo) GetPromotionByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	return r.getPromotion(ctx, `p.id = $1`, id)
+}
+
+// GetPromotionByCode returns the promotion with a normalized code, or ErrNotFound.
+func (r *pgPromotionRepo) GetPromotionByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	return r.getPromotion(ctx, `p.code = $1`, code)
+}
+
+func (r *pgPromotionRepo) getPromotion(ctx context.Context, where string, arg any) (*domain.Promotion, error) {
+	p, err := scanPromotion(r.db.QueryRowContext(ctx, `
+		SELECT`+promotionColumns+`
+		FROM promotions p
+		WHERE `+where, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("promotion not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get promotion: %w", err)
+	}
+	return p, nil
+}
+
+// ListPromotions returns promotions newest first, paginated. A non-empty
+// createdBy only lists the promotions that user created.
+func (r *pgPromotionRepo) ListPromotions(ctx context.Context, createdBy string, page, limit int) ([]*domain.Promotion, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM promotions WHERE $1 = '' OR created_by::text = $1`, createdBy,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count promotions: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+promotionColumns+`
+		FROM promotions p
+		WHERE $1 = '' OR p.created_by::text = $1
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $2 OFFSET $3`, createdBy, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list promotions: %w", err)
+	}
+	defer rows.Close()
+
+	promotions := []*domain.Promotion{}
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan promotion: %w", err)
+		}
+		promotions = append(promotions, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate promotions: %w", err)
+	}
+	return promotions, total, nil
+}
+
+// DeactivatePromotion stops a code from being redeemed. Bookings that already
+// redeemed it keep their discount.
+func (r *pgPromotionRepo) DeactivatePromotion(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE promotions SET active = FALSE WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deactivate promotion: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("promotion not found: %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+// ReleaseRedemption gives back the promo code redeemed by a booking, if any,
+// so it no longer counts against the code's limits.
+func (r *pgPromotionRepo) ReleaseRedemption(ctx context.Context, bookingID int) error {
+	if err := releaseRedemption(ctx, conn(ctx, r.db), bookingID); err != nil {
+		return fmt.Errorf("release promotion redemption: %w", err)
+	}
+	return nil
+}
+
+// redeemPromotion redeems the booking's promo code inside tx, after the
+// booking row was inserted. The promotion row is locked first, so concurrent
+// bookings redeeming the same code are counted one after the other and its
+// limits cannot be overrun. The code must still be redeemable at at.
+func redeemPromotion(ctx context.Context, tx *sql.Tx, booking *domain.Booking, at time.Time) error {
+	p, err := scanPromotion(tx.QueryRowContext(ctx, `
+		SELECT`+promotionColumns+`
+		FROM promotions p
+		WHERE p.id = $1
+		FOR UPDATE`, *booking.PromotionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("promo code %s no longer exists: %w", booking.PromoCode, domain.ErrBadRequest)
+		}
+		return fmt.Errorf("lock promotion: %w", err)
+	}
+	if err := p.Redeemable(at); err != nil {
+		return err
+	}
+
+	// Counted after the lock is held, so redemptions committed by bookings
+	// that held it before are included.
+	var total, byUser int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $2)
+		FROM promotion_redemptions
+		WHERE promotion_id = $1 AND released_at IS NULL`, p.ID, booking.UserID,
+	).Scan(&total, &byUser); err != nil {
+		return fmt.Errorf("count promotion redemptions: %w", err)
+	}
+	if err := p.CheckLimits(total, byUser); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO promotion_redemptions (promotion_id, booking_id, user_id, amount)
+		VALUES ($1, $2, $3, $4)`, p.ID, booking.ID, booking.UserID, booking.PromoDiscount,
+	); err != nil {
+		return fmt.Errorf("insert promotion redemption: %w", err)
+	}
+	return nil
+}
+
+// releaseRedemption releases the booking's redemption, if it has one.
+func releaseRedemption(ctx context.Context, tx dbConn, bookingID int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE promotion_redemptions SET released_at = NOW()
+		WHERE booking_id = $1 AND released_at IS NULL`, bookingID)
+	return err
+}
+
+func scanPromotion(row rowScanner) (*domain.Promotion, error) {
+	p := &domain.Promotion{}
+	var hotelID, roomID, amount, maxRedemptions, maxPerUser sql.NullInt64
+	var percent sql.NullFloat64
+	var validFrom, validUntil sql.NullTime
+	if err := row.Scan(
+		&p.ID, &p.Code, &p.Description, &p.CreatedBy, &hotelID, &roomID, &percent, &amount,
+		&p.Currency, &validFrom, &validUntil, &p.MinNights, &maxRedemptions,
+		&maxPerUser, &p.Active, &p.CreatedAt, &p.Redemptions,
+	); err != nil {
+		return nil, err
+	}
+	p.HotelID = nullIntPtr(hotelID)
+	p.RoomID = nullIntPtr(roomID)
+	if percent.Valid {
+		p.Percent = &percent.Float64
+	}
+	p.Amount = nullInt64Ptr(amount)
+	if validFrom.Valid {
+		p.ValidFrom = &validFrom.Time
+	}
+	if validUntil.Valid {
+		p.ValidUntil = &validUntil.Time
+	}
+	p.MaxRedemptions = nullIntPtr(maxRedemptions)
+	p.MaxPerUser = nullIntPtr(maxPerUser)
+	return p, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"booking-app/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// pgRatePlanRepo implements RatePlanRepository using PostgreSQL. Room-wide
+// rules live in room_rate_plans, seasons in room_seasonal_rates and per-date
+// overrides on the inventory rows of their nights.
+type pgRatePlanRepo struct {
+	db *sql.DB
+}
+
+// NewRatePlanRepo creates a new PostgreSQL-backed RatePlanRepository.
+func NewRatePlanRepo(db *sql.DB) RatePlanRepository {
+	return &pgRatePlanRepo{db: db}
+}
+
+// GetRatePlan loads the rules of a room's rate plan relevant to [from, to).
+// A room without a plan gets an empty one, priced at its base rate.
+func (r *pgRatePlanRepo) GetRatePlan(ctx context.Context, roomID int, from, to time.Time) (*domain.RatePlan, error) {
+	plan := &domain.RatePlan{RoomID: roomID, Seasons: []*domain.SeasonalRate{}, Overrides: []*domain.DateRate{}}
+
+	var weekendPrice sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT weekend_price, min_stay FROM room_rate_plans WHERE room_id = $1`, roomID,
+	).Scan(&weekendPrice, &plan.MinStay)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("get room rate plan: %w", err)
+	}
+	plan.WeekendPrice = nullInt64Ptr(weekendPrice)
+
+	seasons, err := r.db.QueryContext(ctx, `
+		SELECT id, name, start_date, end_date, price, weekend_price, min_stay
+		FROM room_seasonal_rates
+		WHERE room_id = $1 AND start_date < $3 AND end_date > $2
+		ORDER BY start_date`, roomID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get seasonal rates: %w", err)
+	}
+	defer seasons.Close()
+	for seasons.Next() {
+		s := &domain.SeasonalRate{}
+		var weekend, minStay sql.NullInt64
+		if err := seasons.Scan(&s.ID, &s.Name, &s.StartDate, &s.EndDate, &s.Price, &weekend, &minStay); err != nil {
+			return nil, fmt.Errorf("scan seasonal rate: %w", err)
+		}
+		s.WeekendPrice = nullInt64Ptr(weekend)
+		s.MinStay = nullIntPtr(minStay)
+		plan.Seasons = append(plan.Seasons, s)
+	}
+	if err := seasons.Err(); err != nil {
+		return nil, fmt.Errorf("iterate seasonal rates: %w", err)
+	}
+
+	overrides, err := r.db.QueryContext(ctx, `
+		SELECT date, price, min_stay
+		FROM inventory
+		WHERE room_id = $1 AND date >= $2 AND date < $3
+		  AND (price IS NOT NULL OR min_stay IS NOT NULL)
+		ORDER BY date`, roomID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get date rates: %w", err)
+	}
+	defer overrides.Close()
+	for overrides.Next() {
+		o := &domain.DateRate{}
+		var price, minStay sql.NullInt64
+		if err := overrides.Scan(&o.Date, &price, &minStay); err != nil {
+			return nil, fmt.Errorf("scan date rate: %w", err)
+		}
+		o.Price = nullInt64Ptr(price)
+		o.MinStay = nullIntPtr(minStay)
+		plan.Overrides = append(plan.Overrides, o)
+	}
+	if err := overrides.Err(); err != nil {
+		return nil, fmt.Errorf("iterate date rates: %w", err)
+	}
+	return plan, nil
+}
+
+// ReplaceRatePlan replaces the room-wide rules, the seasons and the per-date
+// overrides of a room in one transaction. An override for a night without
+// inventory creates its row with no rooms for sale.
+func (r *pgRatePlanRepo) ReplaceRatePlan(ctx context.Context, plan *domain.RatePlan) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx for rate plan: %w", err)
+	}
+	defer tx.Rollback()
+
+	minStay := plan.MinStay
+	if minStay < 1 {
+		minStay = 1
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO room_rate_plans (room_id, weekend_price, min_stay)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (room_id) DO UPDATE
+		SET weekend_price = EXCLUDED.weekend_price,
+		    min_stay      = EXCLUDED.min_stay,
+		    updated_at    = NOW()`,
+		plan.RoomID, plan.WeekendPrice, minStay,
+	); err != nil {
+		return fmt.Errorf("upsert room rate plan: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM room_seasonal_rates WHERE room_id = $1`, plan.RoomID); err != nil {
+		return fmt.Errorf("clear seasonal rates: %w", err)
+	}
+	for _, s := range plan.Seasons {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO room_seasonal_rates
+			    (room_id, name, start_date, end_date, price, weekend_price, min_stay)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id`,
+			plan.RoomID, s.Name, s.StartDate, s.EndDate, s.Price, s.WeekendPrice, s.MinStay,
+		).Scan(&s.ID)
+		if err != nil {
+			return fmt.Errorf("insert seasonal rate %q: %w", s.Name, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE inventory SET price = NULL, min_stay = NULL
+		WHERE room_id = $1 AND (price IS NOT NULL OR min_stay IS NOT NULL)`, plan.RoomID,
+	); err != nil {
+		return fmt.Errorf("clear date rates: %w", err)
+	}
+	for _, o := range plan.Overrides {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO inventory (room_id, date, total_inventory, booked_count, price, min_stay)
+			VALUES ($1, $2, 0, 0, $3, $4)
+			ON CONFLICT (room_id, date) DO UPDATE
+			SET price = EXCLUDED.price, min_stay = EXCLUDED.min_stay`,
+			plan.RoomID, o.Date, o.Price, o.MinStay,
+		); err != nil {
+			return fmt.Errorf("set date rate %s: %w", o.Date.Format("2006-01-02"), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit rate plan: %w", err)
+	}
+	return nil
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"booking-app/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// refundRepo implements RefundRepository backed by PostgreSQL.
+type refundRepo struct {
+	db *sql.DB
+}
+
+// NewRefundRepo creates a new RefundRepository.
+func NewRefundRepo(db *sql.DB) RefundRepository {
+	return &refundRepo{db: db}
+}
+
+const refundColumns = `
+	id, payment_id, booking_id, amount, currency, status,
+	COALESCE(reason, '') AS reason, idempotency_key,
+	COALESCE(gateway_ref, '') AS gateway_ref,
+	COALESCE(failed_reason, '') AS failed_reason,
+	created_at, updated_at`
+
+// CreateRefund inserts a pending refund against a succeeded charge. The
+// payment row is locked so concurrent refunds cannot together exceed the
+// charged amount: pending and succeeded refunds both count against it.
+func (r *refundRepo) CreateRefund(ctx context.Context, refund *domain.Refund) (*domain.Refund, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction for refund: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		charged  int64
+		currency string
+		status   domain.PaymentStatus
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT amount, currency, status FROM payments WHERE id = $1 FOR UPDATE
+	`, refund.PaymentID).Scan(&charged, &currency, &status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("payment %q not found: %w", refund.PaymentID, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("lock payment for refund: %w", err)
+	}
+	if !status.IsCaptured() {
+		return nil, fmt.Errorf("payment %q in status %q cannot be refunded: %w", refund.PaymentID, status, domain.ErrConflict)
+	}
+
+	var committed int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM refunds
+		WHERE payment_id = $1 AND status IN ('pending', 'succeeded')
+	`, refund.PaymentID).Scan(&committed)
+	if err != nil {
+		return nil, fmt.Errorf("sum refunds: %w", err)
+	}
+	if committed+refund.Amount > charged {
+		return nil, fmt.Errorf("refund of %s exceeds the %s left on payment %q: %w",
+			domain.FormatMoney(refund.Amount, currency), domain.FormatMoney(charged-committed, currency),
+			refund.PaymentID, domain.ErrConflict)
+	}
+
+	created, err := scanRefund(tx.QueryRowContext(ctx, `
+		INSERT INTO refunds (payment_id, booking_id, amount, currency, status, reason, idempotency_key)
+		VALUES ($1, $2, $3, $4, 'pending', NULLIF($5, ''), $6)
+		RETURNING`+refundColumns,
+		refund.PaymentID, refund.BookingID, refund.Amount, currency, refund.Reason, refund.IdempotencyKey,
+	))
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("refund with this idempotency key exists: %w", domain.ErrConflict)
+		}
+		return nil, fmt.Errorf("create refund: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit refund: %w", err)
+	}
+	return created, nil
+}
+
+// GetRefundByID fetches a single refund by primary key.
+func (r *refundRepo) GetRefundByID(ctx context.Context, id string) (*domain.Refund, error) {
+	refund, err := scanRefund(conn(ctx, r.db).QueryRowContext(ctx, `SELECT`+refundColumns+` FROM refunds WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("refund %q not found: %w", id, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get refund by id: %w", err)
+	}
+	return refund, nil
+}
+
+// GetRefundByIdempotencyKey fetches a refund by the key it was sent with.
+func (r *refundRepo) GetRefundByIdempotencyKey(ctx context.Context, key string) (*domain.Refund, error) {
+	refund, err := scanRefund(conn(ctx, r.db).QueryRowContext(ctx, `SELECT`+refundColumns+` FROM refunds WHERE idempotency_key = $1`, key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("refund with key %q not found: %w", key, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get refund by idempotency key: %w", err)
+	}
+	return refund, nil
+}
+
+// ListRefundsByPayment returns every refund of a payment, oldest first.
+func (r *refundRepo) ListRefundsByPayment(ctx context.Context, paymentID string) ([]*domain.Refund, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT`+refundColumns+` FROM refunds WHERE payment_id = $1 ORDER BY created_at ASC`, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("list refunds: %w", err)
+	}
+	defer rows.Close()
+
+	refunds := []*domain.Refund{}
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan refund: %w", err)
+		}
+		refunds = append(refunds, refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+// CompleteRefund marks a pending refund succeeded and adds its amount to the
+// payment's refunded total in one transaction. The payment becomes refunded
+// once the total reaches its amount, partially_refunded before that.
+// Returns ErrConflict when the refund is no longer pending.
+func (r *refundRepo) CompleteRefund(ctx context.Context, id, gatewayRef string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("begin transaction for refund completion: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		paymentID string
+		amount    int64
+	)
+	err = tx.QueryRowContext(ctx, `
+		UPDATE refunds
+		SET status = 'succeeded', gateway_ref = NULLIF($2, ''), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING payment_id, amount
+	`, id, gatewayRef).Scan(&paymentID, &amount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("refund %q is not pending: %w", id, domain.ErrConflict)
+		}
+		return fmt.Errorf("complete refund: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE payments
+		SET refunded_amount = refunded_amount + $2,
+		    status = CASE WHEN refunded_amount + $2 >= amount
+		                  THEN 'refunded' ELSE 'partially_refunded' END,
+		    updated_at = NOW()
+		WHERE id = $1 AND status = ANY($3)
+	`, paymentID, amount, pq.Array(domain.PaymentStatusesBefore(domain.PaymentStatusRefunded)))
+	if err != nil {
+		return fmt.Errorf("record refund on payment: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("payment %q can no longer be refunded: %w", paymentID, domain.ErrConflict)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit refund completion: %w", err)
+	}
+	return nil
+}
+
+// FailRefund marks a pending refund failed, freeing its amount for a retry.
+// Returns ErrConflict when the refund is no longer pending.
+func (r *refundRepo) FailRefund(ctx context.Context, id, reason string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE refunds
+		SET status = 'failed', failed_reason = NULLIF($2, ''), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, reason)
+	if err != nil {
+		return fmt.Errorf("fail refund: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("refund %q is not pending: %w", id, domain.ErrConflict)
+	}
+	return nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRefund(row rowScanner) (*domain.Refund, error) {
+	refund := &domain.Refund{}
+	err := row.Scan(
+		&refund.ID, &refund.PaymentID, &refund.BookingID, &refund.Amount, &refund.Currency,
+		&refund.Status, &refund.Reason, &refund.IdempotencyKey, &refund.GatewayRef,
+		&refund.FailedReason, &refund.CreatedAt, &refund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
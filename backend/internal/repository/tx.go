@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// TxManager runs units of work in a database transaction. Repositories
+// called with the context it hands to the unit of work run their statements
+// in that transaction instead of autocommitting them.
+type TxManager interface {
+	// WithinTx runs fn in a transaction that is committed when fn returns
+	// nil and rolled back otherwise. Called inside a unit of work, it joins
+	// the outer transaction.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txKey is the context key of the transaction of a unit of work.
+type txKey struct{}
+
+// unitOfWork is the transaction of a running WithinTx and the functions to
+// run once it commits.
+type unitOfWork struct {
+	tx          *sql.Tx
+	afterCommit []func(ctx context.Context)
+}
+
+// pgTxManager implements TxManager using PostgreSQL.
+type pgTxManager struct {
+	db *sql.DB
+}
+
+// NewTxManager creates a new PostgreSQL-backed TxManager.
+func NewTxManager(db *sql.DB) TxManager {
+	return &pgTxManager{db: db}
+}
+
+// WithinTx runs fn in a transaction, then the functions registered with
+// AfterCommit once it commits.
+func (m *pgTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if unitOfWorkFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin unit of work: %w", err)
+	}
+	defer tx.Rollback()
+
+	uow := &unitOfWork{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, uow)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit unit of work: %w", err)
+	}
+
+	for _, after := range uow.afterCommit {
+		after(ctx)
+	}
+	return nil
+}
+
+// AfterCommit defers fn until the unit of work in ctx commits and drops it if
+// the unit of work rolls back. fn is passed a context outside the
+// transaction. Outside a unit of work, fn runs straight away.
+//
+// Side effects that must not be undone or that touch the database on their
+// own connections, such as notifications and waitlist offers, go through it.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if uow := unitOfWorkFrom(ctx); uow != nil {
+		uow.afterCommit = append(uow.afterCommit, fn)
+		return
+	}
+	fn(ctx)
+}
+
+func unitOfWorkFrom(ctx context.Context) *unitOfWork {
+	uow, _ := ctx.Value(txKey{}).(*unitOfWork)
+	return uow
+}
+
+// dbConn is satisfied by *sql.DB and *sql.Tx.
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction of the unit of work in ctx, or db outside one.
+func conn(ctx context.Context, db *sql.DB) dbConn {
+	if uow := unitOfWorkFrom(ctx); uow != nil {
+		return uow.tx
+	}
+	return db
+}
+
+// joinableTx is a transaction a repository method opened itself, or the one
+// of the unit of work it joined. Committing and rolling back a joined
+// transaction is left to the unit of work.
+type joinableTx struct {
+	*sql.Tx
+	joined bool
+}
+
+// beginTx starts a transaction on db, or joins the unit of work in ctx.
+func beginTx(ctx context.Context, db *sql.DB) (*joinableTx, error) {
+	if uow := unitOfWorkFrom(ctx); uow != nil {
+		return &joinableTx{Tx: uow.tx, joined: true}, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &joinableTx{Tx: tx}, nil
+}
+
+// Commit commits the transaction unless it was joined.
+func (t *joinableTx) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback rolls the transaction back unless it was joined.
+func (t *joinableTx) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,339 @@
+package repository
+
+import (
+	"booking-app/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// waitlistOfferBatchSize caps how many waiting entries one release considers.
+const waitlistOfferBatchSize = 50
+
+// pgWaitlistRepo implements WaitlistRepository using PostgreSQL. Offers hold
+// inventory exactly like bookings do (booked_count), so claiming one inserts
+// the booking without reserving its stay again.
+type pgWaitlistRepo struct {
+	db *sql.DB
+}
+
+// NewWaitlistRepo creates a new PostgreSQL-backed WaitlistRepository.
+func NewWaitlistRepo(db *sql.DB) WaitlistRepository {
+	return &pgWaitlistRepo{db: db}
+}
+
+const waitlistColumns = `
+		id, user_id, room_id, start_date, end_date, quantity, adults, children, status,
+		offer_expires_at, booking_id, created_at`
+
+// CreateEntry inserts a waiting entry and fills in its id, status and
+// created_at. Returns ErrConflict when the guest already waits for the stay.
+func (r *pgWaitlistRepo) CreateEntry(ctx context.Context, e *domain.WaitlistEntry) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO waitlist_entries (user_id, room_id, start_date, end_date, quantity, adults, children)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, status, created_at`,
+		e.UserID, e.RoomID, e.StartDate, e.EndDate, e.Quantity, e.Adults, e.Children,
+	).Scan(&e.ID, &e.Status, &e.CreatedAt)
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("already on the waitlist for this stay: %w", domain.ErrConflict)
+		}
+		return fmt.Errorf("insert waitlist entry: %w", err)
+	}
+	return nil
+}
+
+// GetEntryByID returns a waitlist entry by id, or ErrNotFound.
+func (r *pgWaitlistRepo) GetEntryByID(ctx context.Context, id int) (*domain.WaitlistEntry, error) {
+	e, err := scanWaitlistEntry(r.db.QueryRowContext(ctx, `
+		SELECT`+waitlistColumns+`
+		FROM waitlist_entries WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("waitlist entry not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get waitlist entry: %w", err)
+	}
+	return e, nil
+}
+
+// ListEntriesByUser returns a guest's entries newest first, paginated.
+func (r *pgWaitlistRepo) ListEntriesByUser(ctx context.Context, userID string, page, limit int) ([]*domain.WaitlistEntry, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM waitlist_entries WHERE user_id = $1`, userID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count waitlist entries: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+waitlistColumns+`
+		FROM waitlist_entries
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list waitlist entries: %w", err)
+	}
+	entries, err := scanWaitlistRows(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// CancelEntry withdraws a waiting or offered entry after verifying it belongs
+// to userID. An offer's held inventory is released in the same transaction.
+// Returns the entry as it was before.
+func (r *pgWaitlistRepo) CancelEntry(ctx context.Context, id int, userID string) (*domain.WaitlistEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction for waitlist cancel: %w", err)
+	}
+	defer tx.Rollback()
+
+	e, err := scanWaitlistEntry(tx.QueryRowContext(ctx, `
+		SELECT`+waitlistColumns+`
+		FROM waitlist_entries WHERE id = $1
+		FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("waitlist entry not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("lock waitlist entry: %w", err)
+	}
+	if e.UserID != userID {
+		return nil, fmt.Errorf("waitlist entry does not belong to user: %w", domain.ErrUnauthorized)
+	}
+	if e.Status != domain.WaitlistStatusWaiting && !e.HoldsInventory() {
+		return nil, fmt.Errorf("waitlist entry is already %s: %w", e.Status, domain.ErrConflict)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE waitlist_entries SET status = 'cancelled', offer_expires_at = NULL WHERE id = $1`, id,
+	); err != nil {
+		return nil, fmt.Errorf("cancel waitlist entry: %w", err)
+	}
+	if e.HoldsInventory() {
+		if err := releaseInventory(ctx, tx, waitlistStay(e)); err != nil {
+			return nil, fmt.Errorf("release waitlist offer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit waitlist cancel: %w", err)
+	}
+	return e, nil
+}
+
+// OfferNext offers the oldest waiting entries of a room whose stay overlaps
+// [startDate, endDate) a hold on it until expiresAt:
+//  1. Begin transaction and lock the candidate entries, oldest first
+//  2. Lock the inventory rows of every candidate stay in date order
+//  3. For each candidate, reserve its stay if every night is free and mark
+//     it offered; entries that do not fit keep waiting
+//  4. Commit and return the offered entries
+//
+// Locking all nights up front, in date order, keeps this from deadlocking
+// with bookings reserving the same nights. Entries locked by a concurrent
+// release are skipped; that release considers them instead.
+func (r *pgWaitlistRepo) OfferNext(ctx context.Context, roomID int, startDate, endDate, expiresAt time.Time) ([]*domain.WaitlistEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction for waitlist offer: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT`+waitlistColumns+`
+		FROM waitlist_entries
+		WHERE room_id = $1 AND status = 'waiting' AND start_date < $3 AND end_date > $2
+		ORDER BY created_at, id
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED`, roomID, startDate, endDate, waitlistOfferBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("list waiting entries: %w", err)
+	}
+	candidates, err := scanWaitlistRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	from, to := candidates[0].StartDate, candidates[0].EndDate
+	for _, e := range candidates[1:] {
+		if e.StartDate.Before(from) {
+			from = e.StartDate
+		}
+		if e.EndDate.After(to) {
+			to = e.EndDate
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		SELECT 1 FROM inventory
+		WHERE room_id = $1 AND date >= $2 AND date < $3
+		ORDER BY date
+		FOR UPDATE`, roomID, from, to); err != nil {
+		return nil, fmt.Errorf("lock inventory for waitlist offer: %w", err)
+	}
+
+	offered := []*domain.WaitlistEntry{}
+	for _, e := range candidates {
+		nights := len(domain.StayNights(e.StartDate, e.EndDate))
+		err := reserveInventory(ctx, tx, e.RoomID, e.StartDate, e.EndDate, nights, e.Quantity)
+		if errors.Is(err, domain.ErrNotAvailable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE waitlist_entries SET status = 'offered', offer_expires_at = $2 WHERE id = $1`,
+			e.ID, expiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("offer waitlist entry: %w", err)
+		}
+		e.Status = domain.WaitlistStatusOffered
+		e.OfferExpiresAt = &expiresAt
+		offered = append(offered, e)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit waitlist offer: %w", err)
+	}
+
+	for _, e := range offered {
+		log.Printf("Waitlist offer: entry=%d, user=%s, room=%d, qty=%d, dates=%s→%s",
+			e.ID, e.UserID, e.RoomID, e.Quantity,
+			e.StartDate.Format("2006-01-02"), e.EndDate.Format("2006-01-02"))
+	}
+	return offered, nil
+}
+
+// ClaimOffer turns an offered entry into booking: the booking is inserted on
+// the inventory the offer holds and the entry is marked claimed, in one
+// transaction. Returns ErrConflict when the entry is no longer offered or its
+// offer has expired.
+func (r *pgWaitlistRepo) ClaimOffer(ctx context.Context, id int, booking *domain.Booking) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction for waitlist claim: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertBooking(ctx, tx, booking); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE waitlist_entries SET status = 'claimed', booking_id = $2
+		WHERE id = $1 AND status = 'offered' AND offer_expires_at > NOW()`, id, booking.ID)
+	if err != nil {
+		return fmt.Errorf("claim waitlist entry: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("waitlist offer %d is no longer available: %w", id, domain.ErrConflict)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit waitlist claim: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredOffers returns offered entries whose hold lapsed before before,
+// oldest first.
+func (r *pgWaitlistRepo) ListExpiredOffers(ctx context.Context, before time.Time, limit int) ([]*domain.WaitlistEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+waitlistColumns+`
+		FROM waitlist_entries
+		WHERE status = 'offered' AND offer_expires_at < $1
+		ORDER BY offer_expires_at
+		LIMIT $2`, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list expired waitlist offers: %w", err)
+	}
+	return scanWaitlistRows(rows)
+}
+
+// ExpireOffer moves an offered entry to expired and releases the inventory it
+// holds. Returns ErrConflict when the entry is no longer offered, e.g. because
+// it was claimed meanwhile.
+func (r *pgWaitlistRepo) ExpireOffer(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction for waitlist expiry: %w", err)
+	}
+	defer tx.Rollback()
+
+	e, err := scanWaitlistEntry(tx.QueryRowContext(ctx, `
+		UPDATE waitlist_entries SET status = 'expired'
+		WHERE id = $1 AND status = 'offered'
+		RETURNING`+waitlistColumns, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("waitlist entry %d is no longer offered: %w", id, domain.ErrConflict)
+		}
+		return fmt.Errorf("expire waitlist offer: %w", err)
+	}
+
+	if err := releaseInventory(ctx, tx, waitlistStay(e)); err != nil {
+		return fmt.Errorf("release expired waitlist offer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit waitlist expiry: %w", err)
+	}
+	return nil
+}
+
+// waitlistStay returns the stay an entry holds, for releaseInventory.
+func waitlistStay(e *domain.WaitlistEntry) *domain.Booking {
+	return &domain.Booking{RoomID: e.RoomID, StartDate: e.StartDate, EndDate: e.EndDate, Quantity: e.Quantity}
+}
+
+func scanWaitlistEntry(row rowScanner) (*domain.WaitlistEntry, error) {
+	e := &domain.WaitlistEntry{}
+	var expiresAt sql.NullTime
+	var bookingID sql.NullInt64
+	if err := row.Scan(
+		&e.ID, &e.UserID, &e.RoomID, &e.StartDate, &e.EndDate, &e.Quantity, &e.Adults, &e.Children, &e.Status,
+		&expiresAt, &bookingID, &e.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		e.OfferExpiresAt = &expiresAt.Time
+	}
+	e.BookingID = nullIntPtr(bookingID)
+	return e, nil
+}
+
+func scanWaitlistRows(rows *sql.Rows) ([]*domain.WaitlistEntry, error) {
+	defer rows.Close()
+	entries := []*domain.WaitlistEntry{}
+	for rows.Next() {
+		e, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan waitlist entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate waitlist entries: %w", err)
+	}
+	return entries, nil
+}
@@ -32,6 +32,13 @@ func New(
 	wsHandler *handler.WSHandler,
 	adminHandler *handler.AdminHandler,
 	chatHandler *handler.ChatHandler,
+	policyHandler *handler.CancellationPolicyHandler,
+	webhookHandler *handler.PaymentWebhookHandler,
+	fxHandler *handler.ExchangeRateHandler,
+	chargeHandler *handler.HotelChargeHandler,
+	promoHandler *handler.PromotionHandler,
+	waitlistHandler *handler.WaitlistHandler,
+	availabilityHandler *handler.AvailabilityHandler,
 ) *gin.Engine {
 	r := gin.New()
 
@@ -54,6 +61,9 @@ func New(
 		c.JSON(200, gin.H{"message": "pong"})
 	})
 
+	// Requires an Idempotency-Key header and replays retried writes.
+	idempotent := middleware.Idempotency(redisClient, 24*time.Hour, "idem")
+
 	v1 := r.Group("/api/v1")
 	{
 		// ----- Public auth routes -----
@@ -80,8 +90,12 @@ func New(
 			publicGroup.GET("/hotels/search", searchHandler.Search)
 			publicGroup.GET("/hotels/:id", hotelHandler.GetHotel)
 			publicGroup.GET("/hotels/:id/rooms", roomHandler.ListRoomsByHotel)
+			publicGroup.GET("/hotels/:id/availability", availabilityHandler.GetHotelAvailability)
 			// Reviews listing is public (no auth required).
 			publicGroup.GET("/hotels/:id/reviews", reviewHandler.ListReviewsByHotel)
+			publicGroup.GET("/rooms/:id/cancellation-policy", policyHandler.GetRoomPolicy)
+			publicGroup.GET("/hotels/:id/charges", chargeHandler.ListCharges)
+			publicGroup.GET("/rooms/:id/quote", bookingHandler.QuoteStay)
 		}
 
 		// ----- Review write routes (JWT + guest role + auth rate limit) -----
@@ -101,11 +115,32 @@ func New(
 		bookingGroup.Use(middleware.JWTAuth(tokenMgr))
 		bookingGroup.Use(middleware.RateLimiter(redisClient, rateLimitAuth, time.Minute, "rl:auth"))
 		{
-			bookingGroup.POST("", bookingHandler.CreateBooking)
+			bookingGroup.POST("", idempotent, bookingHandler.CreateBooking)
 			bookingGroup.GET("", bookingHandler.ListMyBookings)
 			bookingGroup.GET("/:id", bookingHandler.GetBooking)
 			bookingGroup.GET("/:id/status", bookingHandler.GetBookingStatus)
-			bookingGroup.DELETE("/:id", bookingHandler.CancelBooking)
+			bookingGroup.PATCH("/:id", bookingHandler.ModifyBooking)
+			bookingGroup.DELETE("/:id", idempotent, bookingHandler.CancelBooking)
+		}
+
+		// ----- Itinerary routes (JWT required + auth rate limit) -----
+		itineraryGroup := v1.Group("/itineraries")
+		itineraryGroup.Use(middleware.JWTAuth(tokenMgr))
+		itineraryGroup.Use(middleware.RateLimiter(redisClient, rateLimitAuth, time.Minute, "rl:auth"))
+		{
+			itineraryGroup.POST("", idempotent, bookingHandler.CreateItinerary)
+			itineraryGroup.GET("/:id", bookingHandler.GetItinerary)
+		}
+
+		// ----- Waitlist routes (JWT required + auth rate limit) -----
+		waitlistGroup := v1.Group("/waitlist")
+		waitlistGroup.Use(middleware.JWTAuth(tokenMgr))
+		waitlistGroup.Use(middleware.RateLimiter(redisClient, rateLimitAuth, time.Minute, "rl:auth"))
+		{
+			waitlistGroup.POST("", waitlistHandler.JoinWaitlist)
+			waitlistGroup.GET("", waitlistHandler.ListMyEntries)
+			waitlistGroup.DELETE("/:id", waitlistHandler.LeaveWaitlist)
+			waitlistGroup.POST("/:id/claim", idempotent, waitlistHandler.ClaimOffer)
 		}
 
 		// ----- Payment routes (JWT required + auth rate limit) -----
@@ -113,10 +148,13 @@ func New(
 		paymentGroup.Use(middleware.JWTAuth(tokenMgr))
 		paymentGroup.Use(middleware.RateLimiter(redisClient, rateLimitAuth, time.Minute, "rl:auth"))
 		{
-			paymentGroup.POST("/checkout", paymentHandler.Checkout)
+			paymentGroup.POST("/checkout", idempotent, paymentHandler.Checkout)
 			paymentGroup.GET("/payments/:id", paymentHandler.GetPayment)
 		}
 
+		// Payment provider webhooks — no JWT; authenticated by HMAC signature.
+		v1.POST("/payments/webhooks/:provider", webhookHandler.Receive)
+
 		// Admin init route (no auth, matches original behaviour).
 		v1.POST("/admin/init", bookingHandler.InitializeInventory)
 
@@ -136,6 +174,19 @@ func New(
 			ownerGroup.DELETE("/rooms/:id", roomHandler.DeleteRoom)
 			ownerGroup.PUT("/rooms/:id/inventory", roomHandler.SetInventory)
 			ownerGroup.GET("/rooms/:id/inventory", roomHandler.GetInventory)
+			ownerGroup.PUT("/rooms/:id/rates", roomHandler.SetRates)
+			ownerGroup.PUT("/rooms/:id/overbooking", roomHandler.SetOverbooking)
+			ownerGroup.GET("/hotels/:id/overbooked-nights", roomHandler.ListOverbookedNights)
+			ownerGroup.POST("/hotels/:id/inventory/import", roomHandler.ImportInventory)
+			ownerGroup.GET("/hotels/:id/inventory/export", roomHandler.ExportInventory)
+
+			ownerGroup.PUT("/hotels/:id/cancellation-policy", policyHandler.SetHotelPolicy)
+			ownerGroup.PUT("/rooms/:id/cancellation-policy", policyHandler.SetRoomPolicy)
+			ownerGroup.PUT("/hotels/:id/charges", chargeHandler.SetCharges)
+
+			ownerGroup.POST("/promotions", promoHandler.CreatePromotion)
+			ownerGroup.GET("/promotions", promoHandler.ListMyPromotions)
+			ownerGroup.DELETE("/promotions/:id", promoHandler.DeactivatePromotion)
 
 			ownerGroup.GET("/dashboard", ownerHandler.Dashboard)
 		}
@@ -166,6 +217,15 @@ func New(
 			adminGroup.GET("/events/dlq", adminHandler.ListDLQEvents)
 			adminGroup.POST("/events/dlq/:id/retry", adminHandler.RetryDLQEvent)
 
+			// Exchange rates used to charge guests in other currencies
+			adminGroup.GET("/exchange-rates", fxHandler.ListRates)
+			adminGroup.PUT("/exchange-rates", fxHandler.SetRates)
+
+			// Promo codes for any hotel, room or every stay
+			adminGroup.POST("/promotions", promoHandler.CreateAdminPromotion)
+			adminGroup.GET("/promotions", promoHandler.ListAllPromotions)
+			adminGroup.DELETE("/promotions/:id", promoHandler.AdminDeactivatePromotion)
+
 			// Chat broadcast
 			adminGroup.POST("/broadcast", chatHandler.BroadcastAnnouncement)
 		}
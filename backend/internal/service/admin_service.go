@@ -25,9 +25,6 @@ type AdminService struct {
 	outboxRepo  repository.OutboxRepository
 }
 
-// dlqMaxRetries is the retry threshold that marks an event as dead-lettered.
-const dlqMaxRetries = 5
-
 // NewAdminService creates a new AdminService with the required repositories.
 func NewAdminService(
 	userRepo repository.UserRepository,
@@ -78,13 +75,14 @@ func (s *AdminService) ListAllBookings(ctx context.Context, page, limit int) ([]
 	return s.bookingRepo.ListAllBookings(ctx, page, limit)
 }
 
-// ListDLQEvents returns outbox events that have exceeded the retry threshold.
+// ListDLQEvents returns the outbox events the relay gave up on.
 func (s *AdminService) ListDLQEvents(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error) {
 	page, limit = normalizePagination(page, limit)
-	return s.outboxRepo.ListDLQEvents(ctx, dlqMaxRetries, page, limit)
+	return s.outboxRepo.ListDLQEvents(ctx, page, limit)
 }
 
-// RetryDLQEvent resets the retry count for a dead-lettered event so it is republished.
+// RetryDLQEvent puts a dead-lettered event back in the outbox with a fresh set
+// of attempts. Returns ErrConflict when the event is not dead-lettered.
 func (s *AdminService) RetryDLQEvent(ctx context.Context, id string) error {
 	return s.outboxRepo.ResetDLQEvent(ctx, id)
 }
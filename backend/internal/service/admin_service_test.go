@@ -116,6 +116,26 @@ func (m *mockAdminBookingRepo) CancelBooking(ctx context.Context, id int, userID
 	return nil
 }
 
+func (m *mockAdminBookingRepo) RefundBooking(_ context.Context, _ int, _ string, _ int64) error {
+	return nil
+}
+
+func (m *mockAdminBookingRepo) ModifyBooking(ctx context.Context, updated *domain.Booking) (*domain.Booking, error) {
+	return &domain.Booking{ID: updated.ID}, nil
+}
+
+func (m *mockAdminBookingRepo) CreateItinerary(ctx context.Context, itinerary *domain.Itinerary) error {
+	return nil
+}
+
+func (m *mockAdminBookingRepo) FindItineraryByID(ctx context.Context, id int) (*domain.Itinerary, error) {
+	return &domain.Itinerary{ID: id}, nil
+}
+
+func (m *mockAdminBookingRepo) ListBookingsByItinerary(ctx context.Context, itineraryID int) ([]*domain.Booking, error) {
+	return []*domain.Booking{}, nil
+}
+
 func (m *mockAdminBookingRepo) ListAllBookings(ctx context.Context, page, limit int) ([]*domain.Booking, int, error) {
 	if m.listAllBookingsFn != nil {
 		return m.listAllBookingsFn(ctx, page, limit)
@@ -127,12 +147,15 @@ func (m *mockAdminBookingRepo) ListAllBookings(ctx context.Context, page, limit
 
 type mockAdminOutboxRepo struct {
 	createEventFn          func(ctx context.Context, event *domain.OutboxEvent) error
-	listUnpublishedFn      func(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	claimBatchFn           func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error)
+	releaseEventsFn        func(ctx context.Context, relayID string, ids []string) error
 	markPublishedFn        func(ctx context.Context, id string, publishedAt time.Time) error
-	incrementRetryFn       func(ctx context.Context, id string) error
+	scheduleRetryFn        func(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error
+	markDeadLetteredFn     func(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error
 	isEventProcessedFn     func(ctx context.Context, eventID string) (bool, error)
 	markProcessedFn        func(ctx context.Context, eventID string) error
-	listDLQEventsFn        func(ctx context.Context, maxRetries, page, limit int) ([]*domain.OutboxEvent, int, error)
+	claimEventFn           func(ctx context.Context, eventID string) (bool, error)
+	listDLQEventsFn        func(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error)
 	resetDLQEventFn        func(ctx context.Context, id string) error
 }
 
@@ -143,13 +166,20 @@ func (m *mockAdminOutboxRepo) CreateEvent(ctx context.Context, event *domain.Out
 	return nil
 }
 
-func (m *mockAdminOutboxRepo) ListUnpublishedEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
-	if m.listUnpublishedFn != nil {
-		return m.listUnpublishedFn(ctx, limit)
+func (m *mockAdminOutboxRepo) ClaimBatch(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	if m.claimBatchFn != nil {
+		return m.claimBatchFn(ctx, relayID, limit, lease)
 	}
 	return []*domain.OutboxEvent{}, nil
 }
 
+func (m *mockAdminOutboxRepo) ReleaseEvents(ctx context.Context, relayID string, ids []string) error {
+	if m.releaseEventsFn != nil {
+		return m.releaseEventsFn(ctx, relayID, ids)
+	}
+	return nil
+}
+
 func (m *mockAdminOutboxRepo) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
 	if m.markPublishedFn != nil {
 		return m.markPublishedFn(ctx, id, publishedAt)
@@ -157,9 +187,16 @@ func (m *mockAdminOutboxRepo) MarkPublished(ctx context.Context, id string, publ
 	return nil
 }
 
-func (m *mockAdminOutboxRepo) IncrementRetry(ctx context.Context, id string) error {
-	if m.incrementRetryFn != nil {
-		return m.incrementRetryFn(ctx, id)
+func (m *mockAdminOutboxRepo) ScheduleRetry(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error {
+	if m.scheduleRetryFn != nil {
+		return m.scheduleRetryFn(ctx, id, lastErr, nextAttemptAt)
+	}
+	return nil
+}
+
+func (m *mockAdminOutboxRepo) MarkDeadLettered(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error {
+	if m.markDeadLetteredFn != nil {
+		return m.markDeadLetteredFn(ctx, id, retryCount, lastErr, deadLetteredAt)
 	}
 	return nil
 }
@@ -178,9 +215,16 @@ func (m *mockAdminOutboxRepo) MarkProcessed(ctx context.Context, eventID string)
 	return nil
 }
 
-func (m *mockAdminOutboxRepo) ListDLQEvents(ctx context.Context, maxRetries, page, limit int) ([]*domain.OutboxEvent, int, error) {
+func (m *mockAdminOutboxRepo) ClaimEvent(ctx context.Context, eventID string) (bool, error) {
+	if m.claimEventFn != nil {
+		return m.claimEventFn(ctx, eventID)
+	}
+	return true, nil
+}
+
+func (m *mockAdminOutboxRepo) ListDLQEvents(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error) {
 	if m.listDLQEventsFn != nil {
-		return m.listDLQEventsFn(ctx, maxRetries, page, limit)
+		return m.listDLQEventsFn(ctx, page, limit)
 	}
 	return []*domain.OutboxEvent{}, 0, nil
 }
@@ -498,7 +542,7 @@ func TestAdminService_ListDLQEvents_ReturnsEvents(t *testing.T) {
 		{ID: "evt-2", EventType: "PaymentTimedOut", RetryCount: 7},
 	}
 	outboxRepo := &mockAdminOutboxRepo{
-		listDLQEventsFn: func(ctx context.Context, maxRetries, page, limit int) ([]*domain.OutboxEvent, int, error) {
+		listDLQEventsFn: func(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error) {
 			return events, 2, nil
 		},
 	}
@@ -517,26 +561,26 @@ func TestAdminService_ListDLQEvents_ReturnsEvents(t *testing.T) {
 	}
 }
 
-func TestAdminService_ListDLQEvents_UsesDLQMaxRetries(t *testing.T) {
-	capturedMaxRetries := 0
+func TestAdminService_ListDLQEvents_NormalizesPagination(t *testing.T) {
+	capturedPage, capturedLimit := 0, 0
 	outboxRepo := &mockAdminOutboxRepo{
-		listDLQEventsFn: func(ctx context.Context, maxRetries, page, limit int) ([]*domain.OutboxEvent, int, error) {
-			capturedMaxRetries = maxRetries
+		listDLQEventsFn: func(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error) {
+			capturedPage, capturedLimit = page, limit
 			return []*domain.OutboxEvent{}, 0, nil
 		},
 	}
 	svc := makeAdminSvc(defaultAdminUserRepo(), defaultAdminBookingRepo(), outboxRepo)
 
-	svc.ListDLQEvents(context.Background(), 1, 20)
+	svc.ListDLQEvents(context.Background(), 0, 0)
 
-	if capturedMaxRetries != 5 {
-		t.Errorf("expected maxRetries=5, got %d", capturedMaxRetries)
+	if capturedPage != 1 || capturedLimit != 20 {
+		t.Errorf("expected page=1 limit=20, got page=%d limit=%d", capturedPage, capturedLimit)
 	}
 }
 
 func TestAdminService_ListDLQEvents_RepoError(t *testing.T) {
 	outboxRepo := &mockAdminOutboxRepo{
-		listDLQEventsFn: func(ctx context.Context, maxRetries, page, limit int) ([]*domain.OutboxEvent, int, error) {
+		listDLQEventsFn: func(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error) {
 			return nil, 0, domain.ErrInternal
 		},
 	}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AvailabilityCache stores materialised availability calendars. Each hotel's
+// calendars belong to a generation: invalidating the hotel starts a new one,
+// so calendars materialised before a change are never read again and expire.
+type AvailabilityCache interface {
+	Generation(ctx context.Context, hotelID int) (int64, error)
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, val []byte, ttl time.Duration) error
+	Invalidate(ctx context.Context, hotelID int) error
+}
+
+// AvailabilityInvalidator is told whenever bookings, cancellations, inventory
+// or rates change what a hotel's availability calendar shows.
+type AvailabilityInvalidator interface {
+	InvalidateRoom(ctx context.Context, roomID int) error
+	InvalidateHotel(ctx context.Context, hotelID int) error
+}
+
+const availabilityCacheTTL = 10 * time.Minute
+
+// AvailabilityService serves guests the availability calendar of a hotel
+// across all its rooms, materialised from inventory and rate plans and cached
+// until a change invalidates it.
+type AvailabilityService struct {
+	hotelRepo     repository.HotelRepository
+	roomRepo      repository.RoomRepository
+	inventoryRepo repository.InventoryRepository
+	ratePlanRepo  repository.RatePlanRepository
+	cache         AvailabilityCache
+}
+
+// NewAvailabilityService creates an AvailabilityService. cache may be nil
+// (disables caching).
+func NewAvailabilityService(
+	hotelRepo repository.HotelRepository,
+	roomRepo repository.RoomRepository,
+	inventoryRepo repository.InventoryRepository,
+	ratePlanRepo repository.RatePlanRepository,
+	cache AvailabilityCache,
+) *AvailabilityService {
+	return &AvailabilityService{
+		hotelRepo:     hotelRepo,
+		roomRepo:      roomRepo,
+		inventoryRepo: inventoryRepo,
+		ratePlanRepo:  ratePlanRepo,
+		cache:         cache,
+	}
+}
+
+// GetHotelAvailability returns how many units of each of an approved hotel's
+// rooms are left on every night in [from, to), with each night's price and
+// the lowest price per room type.
+func (s *AvailabilityService) GetHotelAvailability(ctx context.Context, hotelID int, from, to time.Time) (*domain.HotelAvailability, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("to must be after from: %w", domain.ErrBadRequest)
+	}
+	if nights := len(domain.StayNights(from, to)); nights > domain.MaxAvailabilityNights {
+		return nil, fmt.Errorf("availability covers at most %d nights: %w", domain.MaxAvailabilityNights, domain.ErrBadRequest)
+	}
+
+	if s.cache == nil {
+		return s.materialise(ctx, hotelID, from, to)
+	}
+
+	// The generation is read before materialising, so a calendar that races
+	// with an invalidation is stored under the stale generation and never read.
+	gen, err := s.cache.Generation(ctx, hotelID)
+	if err != nil {
+		return s.materialise(ctx, hotelID, from, to)
+	}
+	key := availabilityCacheKey(hotelID, gen, from, to)
+	if cached, ok, err := s.cache.Get(ctx, key); err == nil && ok {
+		var avail domain.HotelAvailability
+		if json.Unmarshal(cached, &avail) == nil {
+			return &avail, nil
+		}
+	}
+
+	avail, err := s.materialise(ctx, hotelID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if b, marshalErr := json.Marshal(avail); marshalErr == nil {
+		_ = s.cache.Set(ctx, key, b, availabilityCacheTTL)
+	}
+	return avail, nil
+}
+
+func (s *AvailabilityService) materialise(ctx context.Context, hotelID int, from, to time.Time) (*domain.HotelAvailability, error) {
+	hotel, err := s.hotelRepo.GetHotelByID(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	if hotel.Status != domain.HotelStatusApproved {
+		return nil, fmt.Errorf("hotel not found: %w", domain.ErrNotFound)
+	}
+
+	rooms, err := s.roomRepo.ListRoomsByHotel(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	inventory, err := s.inventoryRepo.GetInventoryForHotel(ctx, hotelID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	plans := make(map[int]*domain.RatePlan, len(rooms))
+	for _, room := range rooms {
+		plan, err := s.ratePlanRepo.GetRatePlan(ctx, room.ID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("fetch rate plan of room %d: %w", room.ID, err)
+		}
+		plan.BasePrice = room.PricePerNight
+		plans[room.ID] = plan
+	}
+
+	return domain.BuildHotelAvailability(hotelID, hotel.Currency, from, to, rooms, plans, inventory), nil
+}
+
+// InvalidateRoom drops the cached calendars of the hotel a room belongs to.
+func (s *AvailabilityService) InvalidateRoom(ctx context.Context, roomID int) error {
+	if s.cache == nil {
+		return nil
+	}
+	room, err := s.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	return s.cache.Invalidate(ctx, room.HotelID)
+}
+
+// InvalidateHotel drops the cached calendars of a hotel.
+func (s *AvailabilityService) InvalidateHotel(ctx context.Context, hotelID int) error {
+	if s.cache == nil {
+		return nil
+	}
+	return s.cache.Invalidate(ctx, hotelID)
+}
+
+// availabilityCacheKey builds the cache key of a hotel's calendar for a range.
+func availabilityCacheKey(hotelID int, gen int64, from, to time.Time) string {
+	return fmt.Sprintf("availability:%d:%d:%s:%s", hotelID, gen, from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
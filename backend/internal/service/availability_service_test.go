@@ -0,0 +1,156 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// --- Mock AvailabilityCache and AvailabilityInvalidator ---
+
+type mockAvailabilityCache struct {
+	generations map[int]int64
+	entries     map[string][]byte
+}
+
+func newMockAvailabilityCache() *mockAvailabilityCache {
+	return &mockAvailabilityCache{generations: map[int]int64{}, entries: map[string][]byte{}}
+}
+
+func (m *mockAvailabilityCache) Generation(_ context.Context, hotelID int) (int64, error) {
+	return m.generations[hotelID], nil
+}
+
+func (m *mockAvailabilityCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	val, ok := m.entries[key]
+	return val, ok, nil
+}
+
+func (m *mockAvailabilityCache) Set(_ context.Context, key string, val []byte, _ time.Duration) error {
+	m.entries[key] = val
+	return nil
+}
+
+func (m *mockAvailabilityCache) Invalidate(_ context.Context, hotelID int) error {
+	m.generations[hotelID]++
+	return nil
+}
+
+type mockAvailabilityInvalidator struct {
+	rooms  []int
+	hotels []int
+}
+
+func (m *mockAvailabilityInvalidator) InvalidateRoom(_ context.Context, roomID int) error {
+	m.rooms = append(m.rooms, roomID)
+	return nil
+}
+
+func (m *mockAvailabilityInvalidator) InvalidateHotel(_ context.Context, hotelID int) error {
+	m.hotels = append(m.hotels, hotelID)
+	return nil
+}
+
+// availabilityFixture wires an AvailabilityService over one approved hotel
+// with a single room, counting how often inventory is read.
+func availabilityFixture(cache service.AvailabilityCache) (*service.AvailabilityService, *int) {
+	reads := 0
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(_ context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, Status: domain.HotelStatusApproved, Currency: "EUR"}, nil
+		},
+	}
+	room := &domain.Room{ID: 5, HotelID: 7, Name: "Double", Capacity: 2, PricePerNight: 10000}
+	roomRepo := &mockRoomRepo{
+		getRoomByIDFn: func(context.Context, int) (*domain.Room, error) { return room, nil },
+		listRoomsByHotelFn: func(context.Context, int) ([]*domain.Room, error) {
+			return []*domain.Room{room}, nil
+		},
+	}
+	inventoryRepo := &mockInventoryRepo{
+		getHotelInventoryFn: func(_ context.Context, _ int, startDate, _ time.Time) ([]*domain.Inventory, error) {
+			reads++
+			return []*domain.Inventory{{RoomID: 5, Date: startDate, TotalInventory: 3, BookedCount: 1}}, nil
+		},
+	}
+	svc := service.NewAvailabilityService(hotelRepo, roomRepo, inventoryRepo, &mockRatePlanRepo{}, cache)
+	return svc, &reads
+}
+
+// --- Tests ---
+
+func TestAvailabilityService_GetHotelAvailability_ServesFromCacheUntilInvalidated(t *testing.T) {
+	cache := newMockAvailabilityCache()
+	svc, reads := availabilityFixture(cache)
+	from, to := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	first, err := svc.GetHotelAvailability(ctx, 7, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Days) != 2 || first.Days[0].Available != 2 || first.Days[1].Available != 0 {
+		t.Fatalf("unexpected calendar: %+v", first.Days)
+	}
+
+	cached, err := svc.GetHotelAvailability(ctx, 7, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *reads != 1 {
+		t.Errorf("expected the second read to hit the cache, inventory read %d times", *reads)
+	}
+	if cached.Days[0].Available != 2 || *cached.Rooms[0].LowestPrice != 10000 {
+		t.Errorf("cached calendar differs from the materialised one: %+v", cached)
+	}
+
+	if err := svc.InvalidateRoom(ctx, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.GetHotelAvailability(ctx, 7, from, to); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *reads != 2 {
+		t.Errorf("expected invalidation to force a new materialisation, inventory read %d times", *reads)
+	}
+}
+
+func TestAvailabilityService_GetHotelAvailability_HidesUnapprovedHotels(t *testing.T) {
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(_ context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, Status: domain.HotelStatusPending}, nil
+		},
+	}
+	svc := service.NewAvailabilityService(hotelRepo, &mockRoomRepo{}, &mockInventoryRepo{}, &mockRatePlanRepo{}, nil)
+
+	_, err := svc.GetHotelAvailability(context.Background(), 7,
+		time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC))
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAvailabilityService_GetHotelAvailability_RejectsBadRanges(t *testing.T) {
+	svc, _ := availabilityFixture(nil)
+	from := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		to   time.Time
+	}{
+		{"to before from", from.AddDate(0, 0, -1)},
+		{"empty range", from},
+		{"longer than the maximum", from.AddDate(0, 0, domain.MaxAvailabilityNights+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GetHotelAvailability(context.Background(), 7, from, tt.to)
+			if !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+		})
+	}
+}
@@ -4,55 +4,502 @@ import (
 	"booking-app/internal/domain"
 	"booking-app/internal/repository"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
+// PaymentAdjuster settles the price difference when a paid booking is modified.
+type PaymentAdjuster interface {
+	StartTopUp(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Payment, error)
+	StartPartialRefund(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Refund, error)
+}
+
+// Refunder gives money back on a charge: refunded when it was captured,
+// released from the authorization when it was not.
+type Refunder interface {
+	StartRefund(ctx context.Context, paymentID string, bookingID int, amount int64, reason string) (*domain.Refund, error)
+	ReleaseAuthorization(ctx context.Context, paymentID string, bookingID int, amount int64) error
+}
+
+// QuoteSigner signs price quotes into tokens and verifies them.
+type QuoteSigner interface {
+	SignQuote(q *domain.Quote) (string, error)
+	VerifyQuote(token string) (*domain.Quote, error)
+}
+
+// BookingOption configures a BookingService.
+type BookingOption func(*BookingService)
+
+// WithPaymentAdjuster wires the payment saga used to top up or refund the
+// difference when a confirmed booking is modified.
+func WithPaymentAdjuster(a PaymentAdjuster) BookingOption {
+	return func(s *BookingService) { s.adjuster = a }
+}
+
+// WithCancellationPolicies enables refunds when a confirmed booking is
+// cancelled. The refundable amount comes from the room's or hotel's
+// cancellation policy and is refunded against the booking's charge.
+func WithCancellationPolicies(policies repository.CancellationPolicyRepository, payRepo repository.PaymentRepository, refunder Refunder) BookingOption {
+	return func(s *BookingService) {
+		s.policies = policies
+		s.payRepo = payRepo
+		s.refunder = refunder
+	}
+}
+
+// WithExchangeRates lets guests pay in a currency other than the hotel's. The
+// current rate is locked into the booking when it is priced.
+func WithExchangeRates(rates repository.ExchangeRateRepository) BookingOption {
+	return func(s *BookingService) { s.rates = rates }
+}
+
+// WithRatePlans prices stays night by night from each room's rate plan:
+// per-date overrides, seasons and weekend rates, with its minimum stay
+// enforced. Without it every night costs the room's base rate.
+func WithRatePlans(plans repository.RatePlanRepository) BookingOption {
+	return func(s *BookingService) { s.ratePlans = plans }
+}
+
+// WithHotelCharges applies each hotel's discounts, service fees and taxes to
+// the price of its stays.
+func WithHotelCharges(charges repository.HotelChargeRepository) BookingOption {
+	return func(s *BookingService) { s.charges = charges }
+}
+
+// WithQuotes signs the quotes returned by QuoteStay, valid for ttl, and lets
+// CreateBooking book a stay at the price of a signed quote.
+func WithQuotes(signer QuoteSigner, ttl time.Duration) BookingOption {
+	return func(s *BookingService) {
+		s.quotes = signer
+		s.quoteTTL = ttl
+	}
+}
+
+// WithPromotions lets guests redeem promo codes when booking. Redemption
+// limits are enforced by the booking repository, in the booking's transaction.
+func WithPromotions(promos repository.PromotionRepository) BookingOption {
+	return func(s *BookingService) { s.promotions = promos }
+}
+
+// WithWaitlist lets guests claim waitlist offers as bookings, and passes the
+// inventory released by cancelled and modified bookings on to releases.
+func WithWaitlist(waitlist repository.WaitlistRepository, releases InventoryReleaseListener) BookingOption {
+	return func(s *BookingService) {
+		s.waitlist = waitlist
+		s.releases = releases
+	}
+}
+
+// WithAvailabilityInvalidator drops cached hotel availability whenever
+// bookings take or give back inventory.
+func WithAvailabilityInvalidator(availability AvailabilityInvalidator) BookingOption {
+	return func(s *BookingService) { s.availability = availability }
+}
+
+// WithBookingTxManager runs the writes of a cancellation or modification in a
+// unit of work, so a booking only changes together with the refund, release
+// or top-up of its payment.
+func WithBookingTxManager(tm repository.TxManager) BookingOption {
+	return func(s *BookingService) { s.txManager = tm }
+}
+
+// WithBookingClock overrides the time source (used in tests).
+func WithBookingClock(now func() time.Time) BookingOption {
+	return func(s *BookingService) { s.now = now }
+}
+
 // BookingService handles booking business logic.
 type BookingService struct {
-	repo     repository.BookingRepository
-	roomRepo repository.RoomRepository
+	repo         repository.BookingRepository
+	roomRepo     repository.RoomRepository
+	adjuster     PaymentAdjuster                         // optional
+	policies     repository.CancellationPolicyRepository // optional
+	payRepo      repository.PaymentRepository            // required with policies
+	refunder     Refunder                                // required with policies
+	rates        repository.ExchangeRateRepository       // optional
+	ratePlans    repository.RatePlanRepository           // optional
+	charges      repository.HotelChargeRepository        // optional
+	quotes       QuoteSigner                             // optional
+	quoteTTL     time.Duration
+	promotions   repository.PromotionRepository // optional
+	waitlist     repository.WaitlistRepository  // optional
+	releases     InventoryReleaseListener       // optional
+	availability AvailabilityInvalidator        // optional
+	txManager    repository.TxManager           // optional
+	now          func() time.Time
 }
 
 // NewBookingService creates a new BookingService.
 // It requires both a BookingRepository for booking operations and a
 // RoomRepository to fetch room pricing for total price calculation.
-func NewBookingService(repo repository.BookingRepository, roomRepo repository.RoomRepository) *BookingService {
-	return &BookingService{
+func NewBookingService(repo repository.BookingRepository, roomRepo repository.RoomRepository, opts ...BookingOption) *BookingService {
+	s := &BookingService{
 		repo:     repo,
 		roomRepo: roomRepo,
+		now:      time.Now,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CreateBooking validates input, fetches room pricing, and creates a booking.
+// The guests must fit in the reserved units, extra beds included. The total
+// price covers every night for every reserved unit and guest in an extra bed,
+// with the hotel's discounts, fees and taxes, and is charged in the requested
+// currency, or the hotel's when none is given. A promo code is taken off
+// before fees and taxes and redeemed with the booking. With a quote token the
+// stay is booked at the quoted price instead.
 func (s *BookingService) CreateBooking(ctx context.Context, input domain.CreateBookingInput) (*domain.Booking, error) {
-	if input.EndDate.Before(input.StartDate) || input.EndDate.Equal(input.StartDate) {
+	var booking *domain.Booking
+	var err error
+	if input.QuoteToken != "" {
+		booking, err = s.bookQuote(input)
+	} else {
+		var promo *domain.Promotion
+		promo, err = s.promotion(ctx, input.PromoCode)
+		if err == nil {
+			booking, err = s.priceStay(ctx, domain.QuoteInput{
+				RoomID:   input.RoomID,
+				CheckIn:  input.StartDate,
+				CheckOut: input.EndDate,
+				Adults:   input.Adults,
+				Children: input.Children,
+				Quantity: input.Quantity,
+			}, promo)
+		}
+		if err == nil {
+			err = s.lockCharge(ctx, booking, input.Currency)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	booking.UserID = input.UserID
+
+	if err := s.repo.CreateBooking(ctx, booking); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, booking.RoomID)
+
+	return booking, nil
+}
+
+// CreateItinerary validates and prices every leg, then reserves them all in a
+// single transaction. Either every leg is booked or none is. All legs are
+// charged in one currency: the requested one, or else the hotels' when they
+// share a currency.
+func (s *BookingService) CreateItinerary(ctx context.Context, input domain.CreateItineraryInput) (*domain.Itinerary, error) {
+	if len(input.Legs) == 0 {
+		return nil, fmt.Errorf("itinerary must have at least one leg: %w", domain.ErrBadRequest)
+	}
+
+	itinerary := &domain.Itinerary{
+		UserID: input.UserID,
+		Legs:   make([]*domain.Booking, 0, len(input.Legs)),
+	}
+	for i, leg := range input.Legs {
+		booking, err := s.priceStay(ctx, domain.QuoteInput{
+			RoomID:   leg.RoomID,
+			CheckIn:  leg.StartDate,
+			CheckOut: leg.EndDate,
+			Adults:   leg.Adults,
+			Children: leg.Children,
+			Quantity: leg.Quantity,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i+1, err)
+		}
+		booking.UserID = input.UserID
+		itinerary.Legs = append(itinerary.Legs, booking)
+	}
+
+	currency := input.Currency
+	if currency == "" {
+		currency = itinerary.Legs[0].Currency
+		for _, leg := range itinerary.Legs[1:] {
+			if leg.Currency != currency {
+				return nil, fmt.Errorf("legs are priced in %s and %s, a currency is required: %w",
+					currency, leg.Currency, domain.ErrBadRequest)
+			}
+		}
+	}
+	for i, leg := range itinerary.Legs {
+		if err := s.lockCharge(ctx, leg, currency); err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i+1, err)
+		}
+		itinerary.TotalPrice += leg.ChargeTotal
+	}
+	itinerary.Currency = itinerary.Legs[0].ChargeCurrency
+
+	if err := s.repo.CreateItinerary(ctx, itinerary); err != nil {
+		return nil, err
+	}
+	for _, leg := range itinerary.Legs {
+		s.invalidate(ctx, leg.RoomID)
+	}
+
+	return itinerary, nil
+}
+
+// GetItinerary retrieves an itinerary with its legs and verifies ownership.
+// Returns ErrForbidden if the caller is not the itinerary owner.
+func (s *BookingService) GetItinerary(ctx context.Context, id int, callerUserID string) (*domain.Itinerary, error) {
+	itinerary, err := s.repo.FindItineraryByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get itinerary: %w", err)
+	}
+
+	if itinerary.UserID != callerUserID {
+		return nil, domain.ErrForbidden
+	}
+
+	return itinerary, nil
+}
+
+// priceStay validates a stay and returns an unsaved booking with its quantity,
+// guests, nightly rates, price lines and total price filled in from the
+// stay's quote, in the hotel's currency. The charge is filled in by
+// lockCharge. The stay's currency and promo code are ignored.
+func (s *BookingService) priceStay(ctx context.Context, stay domain.QuoteInput, promo *domain.Promotion) (*domain.Booking, error) {
+	quote, err := s.quoteStay(ctx, stay, promo)
+	if err != nil {
+		return nil, err
+	}
+	return bookingFromQuote(quote), nil
+}
+
+// quoteStay validates a stay and prices it night by night from the room's
+// rate plan, plus the room's fee for every guest in an extra bed, then
+// applies its hotel's discounts, fees and taxes and promo, which must be
+// valid for the stay. The quote is in the hotel's currency and has no charge
+// or expiry yet.
+func (s *BookingService) quoteStay(ctx context.Context, stay domain.QuoteInput, promo *domain.Promotion) (*domain.Quote, error) {
+	roomID, startDate, endDate := stay.RoomID, stay.CheckIn, stay.CheckOut
+	if endDate.Before(startDate) || endDate.Equal(startDate) {
 		return nil, domain.ErrBadRequest
 	}
 
-	room, err := s.roomRepo.GetRoomByID(ctx, input.RoomID)
+	quantity := stay.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return nil, fmt.Errorf("quantity must be positive: %w", domain.ErrBadRequest)
+	}
+	adults := stay.Adults
+	if adults == 0 {
+		adults = 1
+	}
+
+	room, err := s.roomRepo.GetRoomByID(ctx, roomID)
 	if err != nil {
 		return nil, fmt.Errorf("fetch room for pricing: %w", err)
 	}
+	extraGuests, err := room.CheckOccupancy(adults, stay.Children, quantity)
+	if err != nil {
+		return nil, err
+	}
 
-	nights := int(input.EndDate.Sub(input.StartDate).Hours() / 24)
-	totalPrice := float64(nights) * room.PricePerNight
+	plan := &domain.RatePlan{RoomID: roomID}
+	if s.ratePlans != nil {
+		if plan, err = s.ratePlans.GetRatePlan(ctx, roomID, startDate, endDate); err != nil {
+			return nil, fmt.Errorf("fetch rate plan: %w", err)
+		}
+	}
+	plan.BasePrice = room.PricePerNight
 
-	booking := &domain.Booking{
-		UserID:     input.UserID,
-		RoomID:     input.RoomID,
-		StartDate:  input.StartDate,
-		EndDate:    input.EndDate,
-		TotalPrice: totalPrice,
+	nights := plan.NightlyRates(startDate, endDate)
+	if minStay := plan.MinStayFor(startDate); len(nights) < minStay {
+		return nil, fmt.Errorf("stays arriving on %s must be at least %d nights: %w",
+			startDate.Format("2006-01-02"), minStay, domain.ErrBadRequest)
 	}
 
-	if err := s.repo.CreateBooking(ctx, booking); err != nil {
+	quote := &domain.Quote{
+		RoomID:   roomID,
+		HotelID:  room.HotelID,
+		CheckIn:  startDate,
+		CheckOut: endDate,
+		Adults:   adults,
+		Children: stay.Children,
+		Quantity: quantity,
+		Currency: domain.NormalizeCurrency(room.Currency),
+		Nights:   nights,
+	}
+	for _, night := range nights {
+		quote.Subtotal += night.Price
+	}
+	quote.Subtotal *= int64(quantity)
+	quote.ExtraGuests = extraGuests
+	quote.ExtraGuestCharge = int64(extraGuests) * room.ExtraGuestFee * int64(len(nights))
+	quote.Subtotal += quote.ExtraGuestCharge
+
+	var charges []*domain.HotelCharge
+	if s.charges != nil {
+		if charges, err = s.charges.ListCharges(ctx, room.HotelID); err != nil {
+			return nil, fmt.Errorf("fetch hotel charges: %w", err)
+		}
+	}
+	if promo != nil {
+		if err := promo.AppliesTo(room.HotelID, roomID, len(nights), quote.Currency); err != nil {
+			return nil, err
+		}
+	}
+	quote.ApplyCharges(charges, promo)
+	return quote, nil
+}
+
+// bookingFromQuote returns an unsaved booking priced at quote.
+func bookingFromQuote(quote *domain.Quote) *domain.Booking {
+	return &domain.Booking{
+		RoomID:     quote.RoomID,
+		StartDate:  quote.CheckIn,
+		EndDate:    quote.CheckOut,
+		Quantity:   quote.Quantity,
+		Adults:     quote.Adults,
+		Children:   quote.Children,
+		TotalPrice: quote.Total,
+		Currency:   quote.Currency,
+		Nights:     quote.Nights,
+		Lines:      quote.Lines,
+
+		ExtraGuestCharge: quote.ExtraGuestCharge,
+
+		PromotionID:   quote.PromotionID,
+		PromoCode:     quote.PromoCode,
+		PromoDiscount: quote.PromoDiscount,
+	}
+}
+
+// promotion looks up a promo code being redeemed now. An empty code is no
+// promotion; an unknown, inactive or out-of-window one is a bad request.
+func (s *BookingService) promotion(ctx context.Context, code string) (*domain.Promotion, error) {
+	code = domain.NormalizePromoCode(code)
+	if code == "" {
+		return nil, nil
+	}
+	if s.promotions == nil {
+		return nil, fmt.Errorf("promo codes are not accepted: %w", domain.ErrBadRequest)
+	}
+	promo, err := s.promotions.GetPromotionByCode(ctx, code)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("unknown promo code %s: %w", code, domain.ErrBadRequest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get promotion: %w", err)
+	}
+	if err := promo.Redeemable(s.now()); err != nil {
 		return nil, err
 	}
+	return promo, nil
+}
+
+// QuoteStay prices a stay without booking it: nightly rates, discounts, fees,
+// taxes and the total, converted to the requested currency at the current
+// rate. The returned token, empty when quotes are not wired, can be passed to
+// CreateBooking until the quote expires to book at exactly this price.
+func (s *BookingService) QuoteStay(ctx context.Context, input domain.QuoteInput) (*domain.Quote, string, error) {
+	promo, err := s.promotion(ctx, input.PromoCode)
+	if err != nil {
+		return nil, "", err
+	}
+	quote, err := s.quoteStay(ctx, input, promo)
+	if err != nil {
+		return nil, "", err
+	}
+
+	booking := bookingFromQuote(quote)
+	if err := s.lockCharge(ctx, booking, input.Currency); err != nil {
+		return nil, "", err
+	}
+	quote.ChargeCurrency = booking.ChargeCurrency
+	quote.FxRate = booking.FxRate
+	quote.ChargeTotal = booking.ChargeTotal
+
+	if s.quotes == nil {
+		return quote, "", nil
+	}
+	quote.ExpiresAt = s.now().Add(s.quoteTTL)
+	token, err := s.quotes.SignQuote(quote)
+	if err != nil {
+		return nil, "", fmt.Errorf("sign quote: %w", err)
+	}
+	return quote, token, nil
+}
+
+// bookQuote returns an unsaved booking at the price of a signed quote. The
+// quote must be unexpired and for the room, dates, quantity, guests and
+// currency being booked.
+func (s *BookingService) bookQuote(input domain.CreateBookingInput) (*domain.Booking, error) {
+	if s.quotes == nil {
+		return nil, fmt.Errorf("quote tokens are not accepted: %w", domain.ErrBadRequest)
+	}
+	quote, err := s.quotes.VerifyQuote(input.QuoteToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired quote: %w", domain.ErrBadRequest)
+	}
+	if !s.now().Before(quote.ExpiresAt) {
+		return nil, fmt.Errorf("quote expired at %s: %w", quote.ExpiresAt.Format(time.RFC3339), domain.ErrBadRequest)
+	}
+
+	quantity := input.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	adults := input.Adults
+	if adults == 0 {
+		adults = 1
+	}
+	currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+	if quote.RoomID != input.RoomID || !quote.CheckIn.Equal(input.StartDate) || !quote.CheckOut.Equal(input.EndDate) ||
+		quote.Quantity != quantity || quote.Adults != adults || quote.Children != input.Children ||
+		(currency != "" && currency != quote.ChargeCurrency) {
+		return nil, fmt.Errorf("quote is for a different stay: %w", domain.ErrBadRequest)
+	}
 
+	booking := bookingFromQuote(quote)
+	booking.ChargeCurrency = quote.ChargeCurrency
+	booking.FxRate = quote.FxRate
+	booking.ChargeTotal = quote.ChargeTotal
 	return booking, nil
 }
 
+// lockCharge prices booking in the currency the guest pays in, locking the
+// current exchange rate from the hotel's currency into the booking. An empty
+// currency pays in the hotel's.
+func (s *BookingService) lockCharge(ctx context.Context, booking *domain.Booking, currency string) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = booking.Currency
+	}
+	if !domain.ValidCurrency(currency) {
+		return fmt.Errorf("invalid currency %q: %w", currency, domain.ErrBadRequest)
+	}
+
+	rate := 1.0
+	if currency != booking.Currency {
+		if s.rates == nil {
+			return fmt.Errorf("cannot pay in %s for a hotel pricing in %s: %w", currency, booking.Currency, domain.ErrBadRequest)
+		}
+		fx, err := s.rates.GetRate(ctx, booking.Currency, currency)
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("no exchange rate from %s to %s: %w", booking.Currency, currency, domain.ErrBadRequest)
+		}
+		if err != nil {
+			return fmt.Errorf("get exchange rate: %w", err)
+		}
+		rate = fx.Rate
+	}
+
+	booking.ChargeCurrency = currency
+	booking.FxRate = rate
+	booking.ChargeTotal = booking.Charge(booking.TotalPrice)
+	return nil
+}
+
 // GetBooking retrieves a booking by ID and verifies ownership.
 // Returns ErrForbidden if the caller is not the booking owner.
 func (s *BookingService) GetBooking(ctx context.Context, id int, callerUserID string) (*domain.Booking, error) {
@@ -74,9 +521,334 @@ func (s *BookingService) ListMyBookings(ctx context.Context, userID string, page
 }
 
 // CancelBooking cancels a booking and restores inventory.
-// The repo layer handles ownership verification.
+// The repo layer handles ownership verification. When cancellation policies
+// are wired, cancelling a paid, confirmed booking moves it to refunded and
+// starts a refund of whatever the applicable policy allows. The refundable
+// amount is spread over the checkout charge and any top-ups the booking's
+// modifications took, in the order they were paid. A payment that is only
+// authorized is not refunded: its share is released from the authorization,
+// which voids it when nothing is left, and the booking is cancelled unless
+// captured money is refunded too. The booking and its payments are updated in
+// one unit of work. The released stay is offered to the waitlist.
 func (s *BookingService) CancelBooking(ctx context.Context, id int, userID string) error {
-	return s.repo.CancelBooking(ctx, id, userID)
+	err := withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		return s.cancelBooking(ctx, id, userID)
+	})
+	if err != nil {
+		return err
+	}
+	if s.releases != nil || s.availability != nil {
+		if booking, err := s.repo.FindBookingByID(ctx, id); err == nil {
+			s.released(ctx, booking)
+		}
+	}
+	return nil
+}
+
+func (s *BookingService) cancelBooking(ctx context.Context, id int, userID string) error {
+	if s.policies == nil {
+		return s.repo.CancelBooking(ctx, id, userID)
+	}
+
+	booking, err := s.repo.FindBookingByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get booking: %w", err)
+	}
+	if booking.UserID != userID {
+		return domain.ErrForbidden
+	}
+	if booking.Status != domain.BookingStatusConfirmed {
+		return s.repo.CancelBooking(ctx, id, userID)
+	}
+
+	payments, err := s.bookingPayments(ctx, booking)
+	if err != nil {
+		return err
+	}
+	if len(payments) == 0 {
+		return s.repo.CancelBooking(ctx, id, userID)
+	}
+
+	policy, err := s.policies.GetPolicyForRoom(ctx, booking.RoomID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("get cancellation policy: %w", err)
+	}
+
+	left := policy.RefundableAmount(booking.ChargeTotal, booking.StartDate, s.now())
+	shares := make([]int64, len(payments))
+	var refunded int64
+	for i, payment := range payments {
+		shares[i] = min(left, payment.Amount-payment.RefundedAmount)
+		if shares[i] <= 0 {
+			continue
+		}
+		left -= shares[i]
+		if payment.Status.IsCaptured() {
+			refunded += shares[i]
+		}
+	}
+
+	if err := s.repo.RefundBooking(ctx, id, userID, refunded); err != nil {
+		return err
+	}
+	for i, payment := range payments {
+		if err := s.giveBack(ctx, booking, payment, shares[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bookingPayments returns the payments that paid for booking and can still
+// give money back: its checkout charge followed by the top-ups charged for
+// its modifications. Payments that were never taken or already voided are
+// left out. A booking without a charge has none.
+func (s *BookingService) bookingPayments(ctx context.Context, booking *domain.Booking) ([]*domain.Payment, error) {
+	charge, err := s.chargeFor(ctx, booking)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	topUps, err := s.payRepo.ListTopUps(ctx, booking.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list booking top-ups: %w", err)
+	}
+
+	var payments []*domain.Payment
+	for _, p := range append([]*domain.Payment{charge}, topUps...) {
+		if p.Status == domain.PaymentStatusAuthorized || p.Status.IsCaptured() {
+			payments = append(payments, p)
+		}
+	}
+	return payments, nil
+}
+
+// giveBack returns amount of payment to the guest of a cancelled booking.
+// Captured money is refunded. An authorization is released instead, since no
+// money was taken; if the payment was captured in the meantime, the amount is
+// refunded after all.
+func (s *BookingService) giveBack(ctx context.Context, booking *domain.Booking, payment *domain.Payment, amount int64) error {
+	if amount <= 0 {
+		return nil
+	}
+
+	if payment.Status.IsCaptured() {
+		if _, err := s.refunder.StartRefund(ctx, payment.ID, booking.ID, amount, "booking cancelled"); err != nil {
+			return fmt.Errorf("start refund: %w", err)
+		}
+		return nil
+	}
+
+	err := s.refunder.ReleaseAuthorization(ctx, payment.ID, booking.ID, amount)
+	if errors.Is(err, domain.ErrConflict) {
+		_, err = s.refunder.StartRefund(ctx, payment.ID, booking.ID, amount, "booking cancelled")
+	}
+	if err != nil {
+		return fmt.Errorf("release payment: %w", err)
+	}
+	return nil
+}
+
+// chargeFor returns the checkout charge that paid for booking. Itinerary legs
+// share one charge, attached to the itinerary's first leg.
+func (s *BookingService) chargeFor(ctx context.Context, booking *domain.Booking) (*domain.Payment, error) {
+	payBookingID := booking.ID
+	if booking.ItineraryID != nil {
+		legs, err := s.repo.ListBookingsByItinerary(ctx, *booking.ItineraryID)
+		if err != nil {
+			return nil, fmt.Errorf("list itinerary legs: %w", err)
+		}
+		if len(legs) > 0 {
+			payBookingID = legs[0].ID
+		}
+	}
+
+	payment, err := s.payRepo.GetPaymentByBookingID(ctx, payBookingID)
+	if err != nil {
+		return nil, fmt.Errorf("get booking payment: %w", err)
+	}
+	return payment, nil
+}
+
+// ModifyBooking moves a pending or confirmed booking to new dates and/or a
+// different room of the same hotel and recomputes its total price. The
+// booking's guests must fit in the new room. The new
+// price is charged at the exchange rate locked when the booking was made. When
+// the booking was already paid, the difference is charged or refunded through
+// the payment adjuster.
+func (s *BookingService) ModifyBooking(ctx context.Context, input domain.ModifyBookingInput) (*domain.Booking, error) {
+	booking, err := s.repo.FindBookingByID(ctx, input.BookingID)
+	if err != nil {
+		return nil, fmt.Errorf("get booking: %w", err)
+	}
+	if booking.UserID != input.UserID {
+		return nil, domain.ErrForbidden
+	}
+	if booking.Status != domain.BookingStatusPending && booking.Status != domain.BookingStatusConfirmed {
+		return nil, fmt.Errorf("booking status %q cannot be modified: %w", booking.Status, domain.ErrConflict)
+	}
+
+	roomID, startDate, endDate := booking.RoomID, booking.StartDate, booking.EndDate
+	if input.RoomID != 0 {
+		roomID = input.RoomID
+	}
+	if !input.StartDate.IsZero() {
+		startDate = input.StartDate
+	}
+	if !input.EndDate.IsZero() {
+		endDate = input.EndDate
+	}
+	if roomID == booking.RoomID && startDate.Equal(booking.StartDate) && endDate.Equal(booking.EndDate) {
+		return nil, fmt.Errorf("no changes requested: %w", domain.ErrBadRequest)
+	}
+
+	if roomID != booking.RoomID {
+		if err := s.ensureSameHotel(ctx, booking.RoomID, roomID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The promo code stays redeemed by the booking; it must still apply to
+	// the new stay, but its validity window and limits are not checked again.
+	var promo *domain.Promotion
+	if booking.PromotionID != nil && s.promotions != nil {
+		if promo, err = s.promotions.GetPromotionByID(ctx, *booking.PromotionID); err != nil {
+			return nil, fmt.Errorf("get booking promotion: %w", err)
+		}
+	}
+
+	updated, err := s.priceStay(ctx, domain.QuoteInput{
+		RoomID:   roomID,
+		CheckIn:  startDate,
+		CheckOut: endDate,
+		Adults:   booking.Adults,
+		Children: booking.Children,
+		Quantity: booking.Quantity,
+	}, promo)
+	if err != nil {
+		return nil, err
+	}
+	updated.ID = booking.ID
+	updated.UserID = input.UserID
+	updated.ChargeCurrency = booking.ChargeCurrency
+	updated.FxRate = booking.FxRate
+	updated.ChargeTotal = updated.Charge(updated.TotalPrice)
+
+	var previous *domain.Booking
+	err = withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		var modifyErr error
+		if previous, modifyErr = s.repo.ModifyBooking(ctx, updated); modifyErr != nil {
+			return modifyErr
+		}
+		return s.adjustPayment(ctx, previous, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.released(ctx, previous)
+
+	return updated, nil
+}
+
+// adjustPayment charges or refunds the price difference of a modified booking
+// that was already paid for.
+func (s *BookingService) adjustPayment(ctx context.Context, previous, updated *domain.Booking) error {
+	if previous.Status != domain.BookingStatusConfirmed || s.adjuster == nil {
+		return nil
+	}
+
+	var err error
+	diff := updated.ChargeTotal - previous.ChargeTotal
+	switch {
+	case diff > 0:
+		_, err = s.adjuster.StartTopUp(ctx, updated, diff)
+	case diff < 0:
+		_, err = s.adjuster.StartPartialRefund(ctx, updated, -diff)
+	}
+	if err != nil {
+		return fmt.Errorf("price adjustment: %w", err)
+	}
+	return nil
+}
+
+// released passes the stay a booking no longer holds on to the waitlist.
+func (s *BookingService) released(ctx context.Context, booking *domain.Booking) {
+	s.invalidate(ctx, booking.RoomID)
+	if s.releases == nil {
+		return
+	}
+	_ = s.releases.InventoryReleased(ctx, booking.RoomID, booking.StartDate, booking.EndDate) // best-effort
+}
+
+// invalidate drops the cached availability of a room's hotel.
+func (s *BookingService) invalidate(ctx context.Context, roomID int) {
+	if s.availability == nil {
+		return
+	}
+	_ = s.availability.InvalidateRoom(ctx, roomID) // best-effort
+}
+
+// ClaimWaitlistOffer books the stay a guest's waitlist offer holds, priced at
+// the current rates like a new booking. The booking is pending and goes
+// through checkout like any other; the offer's inventory becomes the
+// booking's. Returns ErrConflict when the offer was already claimed or has
+// expired.
+func (s *BookingService) ClaimWaitlistOffer(ctx context.Context, input domain.ClaimWaitlistOfferInput) (*domain.Booking, error) {
+	if s.waitlist == nil {
+		return nil, fmt.Errorf("waitlist is not enabled: %w", domain.ErrBadRequest)
+	}
+	entry, err := s.waitlist.GetEntryByID(ctx, input.EntryID)
+	if err != nil {
+		return nil, fmt.Errorf("get waitlist entry: %w", err)
+	}
+	if entry.UserID != input.UserID {
+		return nil, domain.ErrForbidden
+	}
+	if !entry.HoldsInventory() {
+		return nil, fmt.Errorf("waitlist entry is %s, not offered: %w", entry.Status, domain.ErrConflict)
+	}
+	if !s.now().Before(*entry.OfferExpiresAt) {
+		return nil, fmt.Errorf("waitlist offer expired at %s: %w", entry.OfferExpiresAt.Format(time.RFC3339), domain.ErrConflict)
+	}
+
+	booking, err := s.priceStay(ctx, domain.QuoteInput{
+		RoomID:   entry.RoomID,
+		CheckIn:  entry.StartDate,
+		CheckOut: entry.EndDate,
+		Adults:   entry.Adults,
+		Children: entry.Children,
+		Quantity: entry.Quantity,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.lockCharge(ctx, booking, input.Currency); err != nil {
+		return nil, err
+	}
+	booking.UserID = input.UserID
+
+	if err := s.waitlist.ClaimOffer(ctx, entry.ID, booking); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// ensureSameHotel returns ErrBadRequest unless both rooms belong to one hotel.
+func (s *BookingService) ensureSameHotel(ctx context.Context, currentRoomID, newRoomID int) error {
+	current, err := s.roomRepo.GetRoomByID(ctx, currentRoomID)
+	if err != nil {
+		return fmt.Errorf("fetch current room: %w", err)
+	}
+	next, err := s.roomRepo.GetRoomByID(ctx, newRoomID)
+	if err != nil {
+		return fmt.Errorf("fetch new room: %w", err)
+	}
+	if current.HotelID != next.HotelID {
+		return fmt.Errorf("room %d is not in the booking's hotel: %w", newRoomID, domain.ErrBadRequest)
+	}
+	return nil
 }
 
 // GetBookingStatus returns the status string for a booking, after verifying ownership.
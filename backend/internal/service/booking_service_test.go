@@ -5,6 +5,8 @@ import (
 	"booking-app/internal/service"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 )
@@ -17,6 +19,11 @@ type mockBookingRepo struct {
 	listByUserFn         func(ctx context.Context, userID string, page, limit int) ([]*domain.Booking, int, error)
 	updateStatusFn       func(ctx context.Context, id int, status string) error
 	cancelBookingFn      func(ctx context.Context, id int, userID string) error
+	refundBookingFn      func(ctx context.Context, id int, userID string, amount int64) error
+	modifyBookingFn      func(ctx context.Context, updated *domain.Booking) (*domain.Booking, error)
+	createItineraryFn    func(ctx context.Context, itinerary *domain.Itinerary) error
+	findItineraryFn      func(ctx context.Context, id int) (*domain.Itinerary, error)
+	listByItineraryFn    func(ctx context.Context, itineraryID int) ([]*domain.Booking, error)
 }
 
 func (m *mockBookingRepo) CreateBooking(ctx context.Context, booking *domain.Booking) error {
@@ -64,6 +71,47 @@ func (m *mockBookingRepo) CancelBooking(ctx context.Context, id int, userID stri
 	return nil
 }
 
+func (m *mockBookingRepo) RefundBooking(ctx context.Context, id int, userID string, amount int64) error {
+	if m.refundBookingFn != nil {
+		return m.refundBookingFn(ctx, id, userID, amount)
+	}
+	return nil
+}
+
+func (m *mockBookingRepo) ModifyBooking(ctx context.Context, updated *domain.Booking) (*domain.Booking, error) {
+	if m.modifyBookingFn != nil {
+		return m.modifyBookingFn(ctx, updated)
+	}
+	return nil, errors.New("not configured")
+}
+
+func (m *mockBookingRepo) CreateItinerary(ctx context.Context, itinerary *domain.Itinerary) error {
+	if m.createItineraryFn != nil {
+		return m.createItineraryFn(ctx, itinerary)
+	}
+	itinerary.ID = 7
+	for i, leg := range itinerary.Legs {
+		leg.ID = 100 + i
+		leg.ItineraryID = &itinerary.ID
+		leg.Status = domain.BookingStatusPending
+	}
+	return nil
+}
+
+func (m *mockBookingRepo) FindItineraryByID(ctx context.Context, id int) (*domain.Itinerary, error) {
+	if m.findItineraryFn != nil {
+		return m.findItineraryFn(ctx, id)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockBookingRepo) ListBookingsByItinerary(ctx context.Context, itineraryID int) ([]*domain.Booking, error) {
+	if m.listByItineraryFn != nil {
+		return m.listByItineraryFn(ctx, itineraryID)
+	}
+	return []*domain.Booking{}, nil
+}
+
 func (m *mockBookingRepo) ListAllBookings(ctx context.Context, page, limit int) ([]*domain.Booking, int, error) {
 	return []*domain.Booking{}, 0, nil
 }
@@ -92,6 +140,10 @@ func (m *mockBookingRoomRepo) UpdateRoom(ctx context.Context, room *domain.Room)
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockBookingRoomRepo) SetOverbooking(ctx context.Context, roomID int, overbooking domain.Overbooking) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockBookingRoomRepo) DeleteRoom(ctx context.Context, id int, hotelID int) error {
 	return errors.New("not implemented")
 }
@@ -102,7 +154,7 @@ func TestBookingService_CreateBooking_Success(t *testing.T) {
 	repo := &mockBookingRepo{}
 	roomRepo := &mockBookingRoomRepo{
 		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
-			return &domain.Room{ID: id, PricePerNight: 150.0}, nil
+			return &domain.Room{ID: id, PricePerNight: 150}, nil
 		},
 	}
 	svc := service.NewBookingService(repo, roomRepo)
@@ -122,282 +174,1278 @@ func TestBookingService_CreateBooking_Success(t *testing.T) {
 		t.Errorf("expected ID=42, got %d", booking.ID)
 	}
 	// 4 nights * 150.0 = 600.0
-	if booking.TotalPrice != 600.0 {
-		t.Errorf("expected TotalPrice=600.0, got %f", booking.TotalPrice)
+	if booking.TotalPrice != 600 {
+		t.Errorf("expected TotalPrice=600, got %d", booking.TotalPrice)
 	}
 }
 
-func TestBookingService_CreateBooking_InvalidDates(t *testing.T) {
-	svc := service.NewBookingService(&mockBookingRepo{}, &mockBookingRoomRepo{})
+func TestBookingService_CreateBooking_QuantityMultipliesPrice(t *testing.T) {
+	repo := &mockBookingRepo{}
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, PricePerNight: 150}, nil
+		},
+	}
+	svc := service.NewBookingService(repo, roomRepo)
 
-	input := domain.CreateBookingInput{
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
 		UserID:    "user-1",
 		RoomID:    1,
-		StartDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
-		EndDate:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		Quantity:  3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-
-	_, err := svc.CreateBooking(context.Background(), input)
-	if !errors.Is(err, domain.ErrBadRequest) {
-		t.Errorf("expected ErrBadRequest, got %v", err)
+	if booking.Quantity != 3 {
+		t.Errorf("expected Quantity=3, got %d", booking.Quantity)
+	}
+	// 2 nights * 3 units * 150.0 = 900.0
+	if booking.TotalPrice != 900 {
+		t.Errorf("expected TotalPrice=900, got %d", booking.TotalPrice)
 	}
 }
 
-func TestBookingService_CreateBooking_SameDates(t *testing.T) {
-	svc := service.NewBookingService(&mockBookingRepo{}, &mockBookingRoomRepo{})
+func TestBookingService_CreateBooking_DefaultsQuantityToOne(t *testing.T) {
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, PricePerNight: 100}, nil
+		},
+	}
+	svc := service.NewBookingService(&mockBookingRepo{}, roomRepo)
 
-	same := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
-	input := domain.CreateBookingInput{
-		UserID: "user-1", RoomID: 1,
-		StartDate: same, EndDate: same,
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if booking.Quantity != 1 {
+		t.Errorf("expected Quantity=1, got %d", booking.Quantity)
 	}
+}
 
-	_, err := svc.CreateBooking(context.Background(), input)
+func TestBookingService_CreateBooking_NegativeQuantity(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, &mockBookingRoomRepo{})
+
+	_, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		Quantity:  -2,
+	})
 	if !errors.Is(err, domain.ErrBadRequest) {
-		t.Errorf("expected ErrBadRequest for same dates, got %v", err)
+		t.Errorf("expected ErrBadRequest, got %v", err)
 	}
 }
 
-func TestBookingService_CreateBooking_NotAvailable(t *testing.T) {
-	repo := &mockBookingRepo{createErr: domain.ErrNotAvailable}
+func TestBookingService_CreateBooking_PricesEachNightFromRatePlan(t *testing.T) {
 	roomRepo := &mockBookingRoomRepo{
 		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
-			return &domain.Room{ID: id, PricePerNight: 100.0}, nil
+			return &domain.Room{ID: id, PricePerNight: 100}, nil
 		},
 	}
-	svc := service.NewBookingService(repo, roomRepo)
+	weekend, sunday := int64(150), int64(300)
+	plans := &mockRatePlanRepo{
+		getRatePlanFn: func(_ context.Context, roomID int, _, _ time.Time) (*domain.RatePlan, error) {
+			return &domain.RatePlan{
+				RoomID:       roomID,
+				WeekendPrice: &weekend,
+				Overrides:    []*domain.DateRate{{Date: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), Price: &sunday}},
+			}, nil
+		},
+	}
+	svc := service.NewBookingService(&mockBookingRepo{}, roomRepo, service.WithRatePlans(plans))
 
-	input := domain.CreateBookingInput{
+	// Thursday to Monday: a weekday, two weekend nights and an overridden Sunday.
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
 		UserID:    "user-1",
 		RoomID:    1,
-		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
-		EndDate:   time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		StartDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		Quantity:  2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-
-	_, err := svc.CreateBooking(context.Background(), input)
-	if !errors.Is(err, domain.ErrNotAvailable) {
-		t.Errorf("expected ErrNotAvailable, got %v", err)
+	want := []int64{100, 150, 150, 300}
+	if len(booking.Nights) != len(want) {
+		t.Fatalf("expected %d nights, got %d", len(want), len(booking.Nights))
+	}
+	for i, price := range want {
+		if booking.Nights[i].Price != price {
+			t.Errorf("night %d: expected %d, got %d", i, price, booking.Nights[i].Price)
+		}
+	}
+	// (100 + 150 + 150 + 300) * 2 units
+	if booking.TotalPrice != 1400 {
+		t.Errorf("expected TotalPrice=1400, got %d", booking.TotalPrice)
 	}
 }
 
-func TestBookingService_CreateBooking_RoomNotFound(t *testing.T) {
+func TestBookingService_CreateBooking_ShorterThanMinStay(t *testing.T) {
 	roomRepo := &mockBookingRoomRepo{
 		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
-			return nil, domain.ErrNotFound
+			return &domain.Room{ID: id, PricePerNight: 100}, nil
 		},
 	}
-	svc := service.NewBookingService(&mockBookingRepo{}, roomRepo)
+	minStay := 3
+	plans := &mockRatePlanRepo{
+		getRatePlanFn: func(_ context.Context, roomID int, _, _ time.Time) (*domain.RatePlan, error) {
+			return &domain.RatePlan{RoomID: roomID, Seasons: []*domain.SeasonalRate{{
+				Name:      "Songkran",
+				StartDate: time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC),
+				Price:     250,
+				MinStay:   &minStay,
+			}}}, nil
+		},
+	}
+	repo := &mockBookingRepo{createErr: errors.New("booking should not be created")}
+	svc := service.NewBookingService(repo, roomRepo, service.WithRatePlans(plans))
 
-	input := domain.CreateBookingInput{
+	_, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
 		UserID:    "user-1",
-		RoomID:    999,
+		RoomID:    1,
+		StartDate: time.Date(2026, 4, 12, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC),
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func thbRoomRepo() *mockBookingRoomRepo {
+	return &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 10, PricePerNight: 200000, Currency: "THB"}, nil
+		},
+	}
+}
+
+func TestBookingService_CreateBooking_DefaultsToHotelCurrency(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, thbRoomRepo())
+
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
 		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
-		EndDate:   time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
+	if booking.TotalPrice != 400000 || booking.Currency != "THB" {
+		t.Errorf("expected 400000 THB, got %d %s", booking.TotalPrice, booking.Currency)
+	}
+	if booking.ChargeTotal != 400000 || booking.ChargeCurrency != "THB" || booking.FxRate != 1 {
+		t.Errorf("expected charge of 400000 THB at 1, got %d %s at %v",
+			booking.ChargeTotal, booking.ChargeCurrency, booking.FxRate)
+	}
+}
 
-	_, err := svc.CreateBooking(context.Background(), input)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Errorf("expected ErrNotFound for unknown room, got %v", err)
+func TestBookingService_CreateBooking_ConvertsAndLocksRate(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, thbRoomRepo(),
+		service.WithExchangeRates(fixedRates(map[string]float64{"THB/USD": 0.0275})))
+
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		Currency:  "usd",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 2 nights * 2000.00 THB = 4000.00 THB = 110.00 USD
+	if booking.TotalPrice != 400000 || booking.Currency != "THB" {
+		t.Errorf("expected price to stay 400000 THB, got %d %s", booking.TotalPrice, booking.Currency)
+	}
+	if booking.ChargeTotal != 11000 || booking.ChargeCurrency != "USD" || booking.FxRate != 0.0275 {
+		t.Errorf("expected charge of 11000 USD at 0.0275, got %d %s at %v",
+			booking.ChargeTotal, booking.ChargeCurrency, booking.FxRate)
 	}
 }
 
-func TestBookingService_CreateBooking_PricingIsCorrect(t *testing.T) {
-	repo := &mockBookingRepo{}
+func TestBookingService_CreateBooking_UnknownRateIsBadRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []service.BookingOption
+	}{
+		{"no exchange rates configured", nil},
+		{"pair not stored", []service.BookingOption{service.WithExchangeRates(fixedRates(nil))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewBookingService(&mockBookingRepo{}, thbRoomRepo(), tt.opts...)
+			_, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+				UserID:    "user-1",
+				RoomID:    1,
+				StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+				Currency:  "EUR",
+			})
+			if !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBookingService_CreateItinerary_MixedCurrenciesNeedChargeCurrency(t *testing.T) {
+	currencies := map[int]string{1: "THB", 2: "JPY"}
 	roomRepo := &mockBookingRoomRepo{
 		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
-			return &domain.Room{ID: id, PricePerNight: 200.0}, nil
+			return &domain.Room{ID: id, PricePerNight: 10000, Currency: currencies[id]}, nil
 		},
 	}
-	svc := service.NewBookingService(repo, roomRepo)
+	rates := fixedRates(map[string]float64{"THB/USD": 0.0275, "JPY/USD": 0.0067})
+	svc := service.NewBookingService(&mockBookingRepo{}, roomRepo, service.WithExchangeRates(rates))
 
-	// 7 nights * 200 = 1400
-	input := domain.CreateBookingInput{
-		UserID:    "user-2",
-		RoomID:    2,
-		StartDate: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
-		EndDate:   time.Date(2026, 4, 8, 0, 0, 0, 0, time.UTC),
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	input := domain.CreateItineraryInput{
+		UserID: "user-1",
+		Legs: []domain.ItineraryLegInput{
+			{RoomID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 1)},
+			{RoomID: 2, StartDate: start.AddDate(0, 0, 1), EndDate: start.AddDate(0, 0, 2)},
+		},
+	}
+	if _, err := svc.CreateItinerary(context.Background(), input); !errors.Is(err, domain.ErrBadRequest) {
+		t.Fatalf("expected ErrBadRequest without a currency, got %v", err)
 	}
 
-	booking, err := svc.CreateBooking(context.Background(), input)
+	input.Currency = "USD"
+	itinerary, err := svc.CreateItinerary(context.Background(), input)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if booking.TotalPrice != 1400.0 {
-		t.Errorf("expected TotalPrice=1400.0, got %f", booking.TotalPrice)
+	// 100.00 THB = 2.75 USD; 10000 JPY = 67.00 USD
+	if itinerary.TotalPrice != 6975 || itinerary.Currency != "USD" {
+		t.Errorf("expected 6975 USD, got %d %s", itinerary.TotalPrice, itinerary.Currency)
 	}
 }
 
-// ---- InitializeInventory tests ----
+func TestBookingService_CreateItinerary_PricesEveryLeg(t *testing.T) {
+	prices := map[int]int64{1: 100, 2: 250}
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, PricePerNight: prices[id]}, nil
+		},
+	}
+	svc := service.NewBookingService(&mockBookingRepo{}, roomRepo)
 
-func TestBookingService_InitializeInventory(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	itinerary, err := svc.CreateItinerary(context.Background(), domain.CreateItineraryInput{
+		UserID: "user-1",
+		Legs: []domain.ItineraryLegInput{
+			{RoomID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 2)},
+			{RoomID: 2, StartDate: start.AddDate(0, 0, 2), EndDate: start.AddDate(0, 0, 3), Quantity: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(itinerary.Legs) != 2 {
+		t.Fatalf("expected 2 legs, got %d", len(itinerary.Legs))
+	}
+	// 2 nights * 100 + 1 night * 2 units * 250 = 700
+	if itinerary.TotalPrice != 700 {
+		t.Errorf("expected TotalPrice=700, got %d", itinerary.TotalPrice)
+	}
+	for _, leg := range itinerary.Legs {
+		if leg.UserID != "user-1" {
+			t.Errorf("expected leg UserID=user-1, got %q", leg.UserID)
+		}
+	}
+}
+
+func TestBookingService_CreateItinerary_InvalidLegReservesNothing(t *testing.T) {
 	called := false
 	repo := &mockBookingRepo{
-		initInventoryFn: func(_ context.Context, roomID int, _ time.Time, days, total int) error {
+		createItineraryFn: func(_ context.Context, _ *domain.Itinerary) error {
 			called = true
-			if roomID != 1 || days != 30 || total != 1 {
-				t.Errorf("unexpected params: roomID=%d days=%d total=%d", roomID, days, total)
-			}
 			return nil
 		},
 	}
-	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, PricePerNight: 100}, nil
+		},
+	}
+	svc := service.NewBookingService(repo, roomRepo)
 
-	err := svc.InitializeInventory(context.Background(), 1, time.Now(), 30, 1)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, err := svc.CreateItinerary(context.Background(), domain.CreateItineraryInput{
+		UserID: "user-1",
+		Legs: []domain.ItineraryLegInput{
+			{RoomID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 2)},
+			{RoomID: 2, StartDate: start, EndDate: start},
+		},
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
 	}
-	if !called {
-		t.Error("expected InitializeInventory to be called")
+	if called {
+		t.Error("expected repository not to be called")
 	}
 }
 
-// ---- GetBooking tests ----
+func TestBookingService_CreateItinerary_NoLegs(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, &mockBookingRoomRepo{})
 
-func TestBookingService_GetBooking_Success(t *testing.T) {
-	booking := &domain.Booking{ID: 10, UserID: "user-1", RoomID: 1, Status: "confirmed"}
+	_, err := svc.CreateItinerary(context.Background(), domain.CreateItineraryInput{UserID: "user-1"})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestBookingService_CreateItinerary_UnavailableLeg(t *testing.T) {
 	repo := &mockBookingRepo{
-		findByIDFn: func(_ context.Context, id int) (*domain.Booking, error) {
-			return booking, nil
+		createItineraryFn: func(_ context.Context, _ *domain.Itinerary) error {
+			return domain.ErrNotAvailable
 		},
 	}
-	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
-
-	result, err := svc.GetBooking(context.Background(), 10, "user-1")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, PricePerNight: 100}, nil
+		},
 	}
-	if result.ID != 10 {
-		t.Errorf("expected booking ID=10, got %d", result.ID)
+	svc := service.NewBookingService(repo, roomRepo)
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, err := svc.CreateItinerary(context.Background(), domain.CreateItineraryInput{
+		UserID: "user-1",
+		Legs:   []domain.ItineraryLegInput{{RoomID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 1)}},
+	})
+	if !errors.Is(err, domain.ErrNotAvailable) {
+		t.Errorf("expected ErrNotAvailable, got %v", err)
 	}
 }
 
-func TestBookingService_GetBooking_WrongUser_ReturnsForbidden(t *testing.T) {
-	booking := &domain.Booking{ID: 10, UserID: "user-1", RoomID: 1, Status: "confirmed"}
+func TestBookingService_GetItinerary_Forbidden(t *testing.T) {
 	repo := &mockBookingRepo{
-		findByIDFn: func(_ context.Context, id int) (*domain.Booking, error) {
-			return booking, nil
+		findItineraryFn: func(_ context.Context, id int) (*domain.Itinerary, error) {
+			return &domain.Itinerary{ID: id, UserID: "owner"}, nil
 		},
 	}
 	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
 
-	_, err := svc.GetBooking(context.Background(), 10, "other-user")
+	_, err := svc.GetItinerary(context.Background(), 7, "someone-else")
 	if !errors.Is(err, domain.ErrForbidden) {
 		t.Errorf("expected ErrForbidden, got %v", err)
 	}
 }
 
-func TestBookingService_GetBooking_NotFound(t *testing.T) {
-	repo := &mockBookingRepo{
-		findByIDFn: func(_ context.Context, id int) (*domain.Booking, error) {
-			return nil, domain.ErrNotFound
-		},
-	}
-	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+// --- ModifyBooking ---
 
-	_, err := svc.GetBooking(context.Background(), 999, "user-1")
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Errorf("expected ErrNotFound, got %v", err)
+type mockPaymentAdjuster struct {
+	topUps  []int64
+	refunds []int64
+	err     error
+}
+
+func (m *mockPaymentAdjuster) StartTopUp(_ context.Context, _ *domain.Booking, amount int64) (*domain.Payment, error) {
+	m.topUps = append(m.topUps, amount)
+	if m.err != nil {
+		return nil, m.err
 	}
+	return &domain.Payment{Kind: domain.PaymentKindTopUp, Amount: amount}, nil
 }
 
-// ---- ListMyBookings tests ----
+func (m *mockPaymentAdjuster) StartPartialRefund(_ context.Context, _ *domain.Booking, amount int64) (*domain.Refund, error) {
+	m.refunds = append(m.refunds, amount)
+	return &domain.Refund{Amount: amount, Status: domain.RefundStatusPending}, nil
+}
 
-func TestBookingService_ListMyBookings_Success(t *testing.T) {
-	bookings := []*domain.Booking{
-		{ID: 1, UserID: "user-1"},
-		{ID: 2, UserID: "user-1"},
+func modifiableBooking(status string) *domain.Booking {
+	return &domain.Booking{
+		ID:         5,
+		UserID:     "user-1",
+		RoomID:     1,
+		StartDate:  time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		Quantity:       1,
+		TotalPrice:     200,
+		Currency:       "USD",
+		ChargeTotal:    200,
+		ChargeCurrency: "USD",
+		FxRate:         1,
+		Status:         status,
 	}
+}
+
+func modifyTestRepos(booking *domain.Booking) (*mockBookingRepo, *mockBookingRoomRepo) {
 	repo := &mockBookingRepo{
-		listByUserFn: func(_ context.Context, userID string, page, limit int) ([]*domain.Booking, int, error) {
-			if userID != "user-1" {
-				return nil, 0, errors.New("unexpected userID")
-			}
-			return bookings, 2, nil
+		findByIDFn: func(_ context.Context, _ int) (*domain.Booking, error) {
+			copied := *booking
+			return &copied, nil
+		},
+		modifyBookingFn: func(_ context.Context, _ *domain.Booking) (*domain.Booking, error) {
+			copied := *booking
+			return &copied, nil
 		},
 	}
-	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+	rooms := map[int]*domain.Room{
+		1: {ID: 1, HotelID: 10, PricePerNight: 100},
+		2: {ID: 2, HotelID: 10, PricePerNight: 180},
+		3: {ID: 3, HotelID: 99, PricePerNight: 100},
+	}
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return rooms[id], nil
+		},
+	}
+	return repo, roomRepo
+}
 
-	result, total, err := svc.ListMyBookings(context.Background(), "user-1", 1, 20)
+func TestBookingService_ModifyBooking_ConfirmedMoreExpensive_TopsUp(t *testing.T) {
+	repo, roomRepo := modifyTestRepos(modifiableBooking(domain.BookingStatusConfirmed))
+	adjuster := &mockPaymentAdjuster{}
+	svc := service.NewBookingService(repo, roomRepo, service.WithPaymentAdjuster(adjuster))
+
+	booking, err := svc.ModifyBooking(context.Background(), domain.ModifyBookingInput{
+		BookingID: 5, UserID: "user-1", RoomID: 2,
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if total != 2 {
-		t.Errorf("expected total=2, got %d", total)
+	// 2 nights * 180 = 360
+	if booking.TotalPrice != 360 {
+		t.Errorf("expected TotalPrice=360, got %d", booking.TotalPrice)
 	}
-	if len(result) != 2 {
-		t.Errorf("expected 2 bookings, got %d", len(result))
+	if len(adjuster.topUps) != 1 || adjuster.topUps[0] != 160 {
+		t.Errorf("expected one top-up of 160, got %v", adjuster.topUps)
+	}
+	if len(adjuster.refunds) != 0 {
+		t.Errorf("expected no refunds, got %v", adjuster.refunds)
 	}
 }
 
-func TestBookingService_ListMyBookings_EmptyResult(t *testing.T) {
-	repo := &mockBookingRepo{
-		listByUserFn: func(_ context.Context, userID string, page, limit int) ([]*domain.Booking, int, error) {
-			return []*domain.Booking{}, 0, nil
-		},
+func TestBookingService_ModifyBooking_TopUpSharesTransactionWithBooking(t *testing.T) {
+	repo, roomRepo := modifyTestRepos(modifiableBooking(domain.BookingStatusConfirmed))
+	modified := repo.modifyBookingFn
+	modifiedOutsideTx := false
+	repo.modifyBookingFn = func(ctx context.Context, b *domain.Booking) (*domain.Booking, error) {
+		modifiedOutsideTx = !inTx(ctx)
+		return modified(ctx, b)
 	}
-	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+	adjuster := &mockPaymentAdjuster{err: errors.New("db down")}
+	txManager := &mockTxManager{}
+	releases := &mockReleaseListener{}
+	svc := service.NewBookingService(repo, roomRepo, service.WithPaymentAdjuster(adjuster),
+		service.WithBookingTxManager(txManager), service.WithWaitlist(&mockWaitlistRepo{}, releases))
 
-	result, total, err := svc.ListMyBookings(context.Background(), "user-99", 1, 20)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if _, err := svc.ModifyBooking(context.Background(), domain.ModifyBookingInput{
+		BookingID: 5, UserID: "user-1", RoomID: 2,
+	}); err == nil {
+		t.Fatal("expected the failed top-up to fail the modification")
 	}
-	if total != 0 {
-		t.Errorf("expected total=0, got %d", total)
+	if modifiedOutsideTx {
+		t.Error("expected the booking modified in the transaction")
 	}
-	if len(result) != 0 {
-		t.Errorf("expected 0 bookings, got %d", len(result))
+	if txManager.rolledBack != 1 || txManager.committed != 0 {
+		t.Errorf("expected the modification rolled back, got %d commits and %d rollbacks",
+			txManager.committed, txManager.rolledBack)
+	}
+	if len(releases.released) != 0 {
+		t.Errorf("stay of a rolled back modification must not be offered to the waitlist, got %+v", releases.released)
+	}
+}
+
+func TestBookingService_ModifyBooking_ConfirmedShorterStay_Refunds(t *testing.T) {
+	repo, roomRepo := modifyTestRepos(modifiableBooking(domain.BookingStatusConfirmed))
+	adjuster := &mockPaymentAdjuster{}
+	svc := service.NewBookingService(repo, roomRepo, service.WithPaymentAdjuster(adjuster))
+
+	_, err := svc.ModifyBooking(context.Background(), domain.ModifyBookingInput{
+		BookingID: 5, UserID: "user-1", EndDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(adjuster.refunds) != 1 || adjuster.refunds[0] != 100 {
+		t.Errorf("expected one refund of 100, got %v", adjuster.refunds)
+	}
+}
+
+func TestBookingService_ModifyBooking_KeepsLockedRate(t *testing.T) {
+	booking := modifiableBooking(domain.BookingStatusConfirmed)
+	booking.ChargeCurrency, booking.FxRate, booking.ChargeTotal = "EUR", 0.9, 180
+	repo, roomRepo := modifyTestRepos(booking)
+	adjuster := &mockPaymentAdjuster{}
+	// Today's rate must not be used for a booking priced earlier.
+	rates := fixedRates(map[string]float64{"USD/EUR": 2})
+	svc := service.NewBookingService(repo, roomRepo,
+		service.WithPaymentAdjuster(adjuster), service.WithExchangeRates(rates))
+
+	updated, err := svc.ModifyBooking(context.Background(), domain.ModifyBookingInput{
+		BookingID: 5, UserID: "user-1", RoomID: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 2 nights * 180 USD = 360 USD = 324 EUR at 0.9
+	if updated.ChargeTotal != 324 || updated.ChargeCurrency != "EUR" || updated.FxRate != 0.9 {
+		t.Errorf("expected 324 EUR at 0.9, got %d %s at %v", updated.ChargeTotal, updated.ChargeCurrency, updated.FxRate)
+	}
+	if len(adjuster.topUps) != 1 || adjuster.topUps[0] != 144 {
+		t.Errorf("expected one top-up of 144, got %v", adjuster.topUps)
+	}
+}
+
+func TestBookingService_ModifyBooking_PendingNeedsNoAdjustment(t *testing.T) {
+	repo, roomRepo := modifyTestRepos(modifiableBooking(domain.BookingStatusPending))
+	adjuster := &mockPaymentAdjuster{}
+	svc := service.NewBookingService(repo, roomRepo, service.WithPaymentAdjuster(adjuster))
+
+	_, err := svc.ModifyBooking(context.Background(), domain.ModifyBookingInput{
+		BookingID: 5, UserID: "user-1", RoomID: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(adjuster.topUps)+len(adjuster.refunds) != 0 {
+		t.Errorf("expected no adjustments for unpaid booking, got topUps=%v refunds=%v", adjuster.topUps, adjuster.refunds)
+	}
+}
+
+func TestBookingService_ModifyBooking_RoomInOtherHotel(t *testing.T) {
+	repo, roomRepo := modifyTestRepos(modifiableBooking(domain.BookingStatusConfirmed))
+	svc := service.NewBookingService(repo, roomRepo)
+
+	_, err := svc.ModifyBooking(context.Background(), domain.ModifyBookingInput{
+		BookingID: 5, UserID: "user-1", RoomID: 3,
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestBookingService_ModifyBooking_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		status  string
+		input   domain.ModifyBookingInput
+		wantErr error
+	}{
+		{"not owner", domain.BookingStatusConfirmed, domain.ModifyBookingInput{BookingID: 5, UserID: "other", RoomID: 2}, domain.ErrForbidden},
+		{"cancelled", domain.BookingStatusCancelled, domain.ModifyBookingInput{BookingID: 5, UserID: "user-1", RoomID: 2}, domain.ErrConflict},
+		{"awaiting payment", domain.BookingStatusAwaitingPayment, domain.ModifyBookingInput{BookingID: 5, UserID: "user-1", RoomID: 2}, domain.ErrConflict},
+		{"no changes", domain.BookingStatusConfirmed, domain.ModifyBookingInput{BookingID: 5, UserID: "user-1"}, domain.ErrBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, roomRepo := modifyTestRepos(modifiableBooking(tc.status))
+			svc := service.NewBookingService(repo, roomRepo)
+
+			_, err := svc.ModifyBooking(context.Background(), tc.input)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("expected %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBookingService_CreateBooking_InvalidDates(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, &mockBookingRoomRepo{})
+
+	input := domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	_, err := svc.CreateBooking(context.Background(), input)
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestBookingService_CreateBooking_SameDates(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, &mockBookingRoomRepo{})
+
+	same := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	input := domain.CreateBookingInput{
+		UserID: "user-1", RoomID: 1,
+		StartDate: same, EndDate: same,
+	}
+
+	_, err := svc.CreateBooking(context.Background(), input)
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for same dates, got %v", err)
+	}
+}
+
+func TestBookingService_CreateBooking_NotAvailable(t *testing.T) {
+	repo := &mockBookingRepo{createErr: domain.ErrNotAvailable}
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, PricePerNight: 100}, nil
+		},
+	}
+	svc := service.NewBookingService(repo, roomRepo)
+
+	input := domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	_, err := svc.CreateBooking(context.Background(), input)
+	if !errors.Is(err, domain.ErrNotAvailable) {
+		t.Errorf("expected ErrNotAvailable, got %v", err)
+	}
+}
+
+func TestBookingService_CreateBooking_RoomNotFound(t *testing.T) {
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+	svc := service.NewBookingService(&mockBookingRepo{}, roomRepo)
+
+	input := domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    999,
+		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	_, err := svc.CreateBooking(context.Background(), input)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for unknown room, got %v", err)
+	}
+}
+
+func TestBookingService_CreateBooking_PricingIsCorrect(t *testing.T) {
+	repo := &mockBookingRepo{}
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, PricePerNight: 200}, nil
+		},
+	}
+	svc := service.NewBookingService(repo, roomRepo)
+
+	// 7 nights * 200 = 1400
+	input := domain.CreateBookingInput{
+		UserID:    "user-2",
+		RoomID:    2,
+		StartDate: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 4, 8, 0, 0, 0, 0, time.UTC),
+	}
+
+	booking, err := svc.CreateBooking(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if booking.TotalPrice != 1400 {
+		t.Errorf("expected TotalPrice=1400, got %d", booking.TotalPrice)
+	}
+}
+
+// ---- InitializeInventory tests ----
+
+func TestBookingService_InitializeInventory(t *testing.T) {
+	called := false
+	repo := &mockBookingRepo{
+		initInventoryFn: func(_ context.Context, roomID int, _ time.Time, days, total int) error {
+			called = true
+			if roomID != 1 || days != 30 || total != 1 {
+				t.Errorf("unexpected params: roomID=%d days=%d total=%d", roomID, days, total)
+			}
+			return nil
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	err := svc.InitializeInventory(context.Background(), 1, time.Now(), 30, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected InitializeInventory to be called")
+	}
+}
+
+// ---- GetBooking tests ----
+
+func TestBookingService_GetBooking_Success(t *testing.T) {
+	booking := &domain.Booking{ID: 10, UserID: "user-1", RoomID: 1, Status: "confirmed"}
+	repo := &mockBookingRepo{
+		findByIDFn: func(_ context.Context, id int) (*domain.Booking, error) {
+			return booking, nil
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	result, err := svc.GetBooking(context.Background(), 10, "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != 10 {
+		t.Errorf("expected booking ID=10, got %d", result.ID)
+	}
+}
+
+func TestBookingService_GetBooking_WrongUser_ReturnsForbidden(t *testing.T) {
+	booking := &domain.Booking{ID: 10, UserID: "user-1", RoomID: 1, Status: "confirmed"}
+	repo := &mockBookingRepo{
+		findByIDFn: func(_ context.Context, id int) (*domain.Booking, error) {
+			return booking, nil
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	_, err := svc.GetBooking(context.Background(), 10, "other-user")
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestBookingService_GetBooking_NotFound(t *testing.T) {
+	repo := &mockBookingRepo{
+		findByIDFn: func(_ context.Context, id int) (*domain.Booking, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	_, err := svc.GetBooking(context.Background(), 999, "user-1")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// ---- ListMyBookings tests ----
+
+func TestBookingService_ListMyBookings_Success(t *testing.T) {
+	bookings := []*domain.Booking{
+		{ID: 1, UserID: "user-1"},
+		{ID: 2, UserID: "user-1"},
+	}
+	repo := &mockBookingRepo{
+		listByUserFn: func(_ context.Context, userID string, page, limit int) ([]*domain.Booking, int, error) {
+			if userID != "user-1" {
+				return nil, 0, errors.New("unexpected userID")
+			}
+			return bookings, 2, nil
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	result, total, err := svc.ListMyBookings(context.Background(), "user-1", 1, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("expected total=2, got %d", total)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected 2 bookings, got %d", len(result))
+	}
+}
+
+func TestBookingService_ListMyBookings_EmptyResult(t *testing.T) {
+	repo := &mockBookingRepo{
+		listByUserFn: func(_ context.Context, userID string, page, limit int) ([]*domain.Booking, int, error) {
+			return []*domain.Booking{}, 0, nil
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	result, total, err := svc.ListMyBookings(context.Background(), "user-99", 1, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 0 {
+		t.Errorf("expected total=0, got %d", total)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected 0 bookings, got %d", len(result))
+	}
+}
+
+// ---- CancelBooking tests ----
+
+func TestBookingService_CancelBooking_Success(t *testing.T) {
+	called := false
+	repo := &mockBookingRepo{
+		cancelBookingFn: func(_ context.Context, id int, userID string) error {
+			called = true
+			if id != 10 || userID != "user-1" {
+				t.Errorf("unexpected cancel params: id=%d userID=%s", id, userID)
+			}
+			return nil
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	err := svc.CancelBooking(context.Background(), 10, "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected CancelBooking to be called on repo")
+	}
+}
+
+func TestBookingService_CancelBooking_Unauthorized(t *testing.T) {
+	repo := &mockBookingRepo{
+		cancelBookingFn: func(_ context.Context, id int, userID string) error {
+			return domain.ErrUnauthorized
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	err := svc.CancelBooking(context.Background(), 10, "wrong-user")
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestBookingService_CancelBooking_AlreadyCancelled(t *testing.T) {
+	repo := &mockBookingRepo{
+		cancelBookingFn: func(_ context.Context, id int, userID string) error {
+			return domain.ErrConflict
+		},
+	}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+
+	err := svc.CancelBooking(context.Background(), 10, "user-1")
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+// ---- CancelBooking refund tests ----
+
+var cancelNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func paidBooking(checkIn time.Time) *domain.Booking {
+	return &domain.Booking{
+		ID:         10,
+		UserID:     "user-1",
+		RoomID:     3,
+		StartDate:  checkIn,
+		EndDate:    checkIn.AddDate(0, 0, 2),
+		Quantity:       1,
+		TotalPrice:     200,
+		Currency:       "USD",
+		ChargeTotal:    200,
+		ChargeCurrency: "USD",
+		FxRate:         1,
+		Status:         domain.BookingStatusConfirmed,
+	}
+}
+
+func succeededCharge() *mockPaymentRepo {
+	return makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			return &domain.Payment{ID: "pay-1", BookingID: bookingID, Amount: 200, Status: domain.PaymentStatusSucceeded}, nil
+		},
+	})
+}
+
+type refundCall struct {
+	paymentID string
+	bookingID int
+	amount    int64
+}
+
+type mockRefunder struct {
+	calls      []refundCall
+	err        error
+	releases   []refundCall
+	releaseErr error
+}
+
+func (m *mockRefunder) StartRefund(_ context.Context, paymentID string, bookingID int, amount int64, _ string) (*domain.Refund, error) {
+	m.calls = append(m.calls, refundCall{paymentID: paymentID, bookingID: bookingID, amount: amount})
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.Refund{ID: "ref-1", PaymentID: paymentID, Amount: amount, Status: domain.RefundStatusPending}, nil
+}
+
+func (m *mockRefunder) ReleaseAuthorization(_ context.Context, paymentID string, bookingID int, amount int64) error {
+	m.releases = append(m.releases, refundCall{paymentID: paymentID, bookingID: bookingID, amount: amount})
+	return m.releaseErr
+}
+
+func authorizedCharge() *mockPaymentRepo {
+	return makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			return &domain.Payment{ID: "pay-1", BookingID: bookingID, Amount: 200, Status: domain.PaymentStatusAuthorized}, nil
+		},
+	})
+}
+
+// refundTestRepo returns a repo serving booking whose RefundBooking records
+// the amount the booking was cancelled with.
+func refundTestRepo(booking *domain.Booking, owed *int64) *mockBookingRepo {
+	*owed = -1
+	return &mockBookingRepo{
+		findByIDFn: func(_ context.Context, _ int) (*domain.Booking, error) { return booking, nil },
+		refundBookingFn: func(_ context.Context, _ int, _ string, amount int64) error {
+			*owed = amount
+			return nil
+		},
+		cancelBookingFn: func(_ context.Context, _ int, _ string) error {
+			panic("confirmed paid booking must be refunded, not plainly cancelled")
+		},
+	}
+}
+
+func TestBookingService_CancelBooking_RefundFollowsPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		checkIn time.Time
+		policy  *domain.CancellationPolicy
+		want    int64
+	}{
+		{"no policy refunds in full", cancelNow.AddDate(0, 0, 1), nil, 200},
+		{"inside free window", cancelNow.AddDate(0, 0, 10), &domain.CancellationPolicy{FreeCancellationDays: 7, PenaltyPercent: 50}, 200},
+		{"after free window pays penalty", cancelNow.AddDate(0, 0, 3), &domain.CancellationPolicy{FreeCancellationDays: 7, PenaltyPercent: 25}, 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var owed int64
+			repo := refundTestRepo(paidBooking(tt.checkIn), &owed)
+			policies := &mockPolicyRepo{
+				getPolicyForRoomFn: func(_ context.Context, _ int) (*domain.CancellationPolicy, error) {
+					if tt.policy == nil {
+						return nil, domain.ErrNotFound
+					}
+					return tt.policy, nil
+				},
+			}
+			refunder := &mockRefunder{}
+			svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+				service.WithCancellationPolicies(policies, succeededCharge(), refunder),
+				service.WithBookingClock(func() time.Time { return cancelNow }))
+
+			if err := svc.CancelBooking(context.Background(), 10, "user-1"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if owed != tt.want {
+				t.Errorf("expected booking refunded with %d owed, got %d", tt.want, owed)
+			}
+			want := refundCall{paymentID: "pay-1", bookingID: 10, amount: tt.want}
+			if len(refunder.calls) != 1 || refunder.calls[0] != want {
+				t.Errorf("expected refund %+v, got %+v", want, refunder.calls)
+			}
+		})
+	}
+}
+
+func TestBookingService_CancelBooking_NonRefundableStartsNoRefund(t *testing.T) {
+	var owed int64
+	repo := refundTestRepo(paidBooking(cancelNow.AddDate(0, 0, 30)), &owed)
+	policies := &mockPolicyRepo{
+		getPolicyForRoomFn: func(_ context.Context, _ int) (*domain.CancellationPolicy, error) {
+			return &domain.CancellationPolicy{NonRefundable: true}, nil
+		},
+	}
+	refunder := &mockRefunder{}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(policies, succeededCharge(), refunder),
+		service.WithBookingClock(func() time.Time { return cancelNow }))
+
+	if err := svc.CancelBooking(context.Background(), 10, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owed != 0 {
+		t.Errorf("expected nothing owed, got %d", owed)
+	}
+	if len(refunder.calls) != 0 {
+		t.Errorf("expected no refund, got %+v", refunder.calls)
+	}
+}
+
+func TestBookingService_CancelBooking_RefundStartFailure(t *testing.T) {
+	var owed int64
+	repo := refundTestRepo(paidBooking(cancelNow.AddDate(0, 0, 30)), &owed)
+	refunder := &mockRefunder{err: domain.ErrConflict}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(&mockPolicyRepo{}, succeededCharge(), refunder))
+
+	err := svc.CancelBooking(context.Background(), 10, "user-1")
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected wrapped ErrConflict, got %v", err)
 	}
 }
 
-// ---- CancelBooking tests ----
+func TestBookingService_CancelBooking_RefundSharesTransactionWithBooking(t *testing.T) {
+	var outsideTx []string
+	repo := &mockBookingRepo{
+		findByIDFn: func(_ context.Context, _ int) (*domain.Booking, error) {
+			return paidBooking(cancelNow.AddDate(0, 0, 30)), nil
+		},
+		refundBookingFn: func(ctx context.Context, _ int, _ string, _ int64) error {
+			if !inTx(ctx) {
+				outsideTx = append(outsideTx, "refund booking")
+			}
+			return nil
+		},
+	}
+	refunder := &mockRefunder{err: errors.New("db down")}
+	txManager := &mockTxManager{}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(&mockPolicyRepo{}, succeededCharge(), refunder),
+		service.WithBookingTxManager(txManager),
+		service.WithBookingClock(func() time.Time { return cancelNow }))
 
-func TestBookingService_CancelBooking_Success(t *testing.T) {
-	called := false
+	if err := svc.CancelBooking(context.Background(), 10, "user-1"); err == nil {
+		t.Fatal("expected the failed refund to fail the cancellation")
+	}
+	if len(outsideTx) != 0 {
+		t.Errorf("expected every write in the transaction, got %v outside it", outsideTx)
+	}
+	if txManager.rolledBack != 1 || txManager.committed != 0 {
+		t.Errorf("expected the cancellation rolled back, got %d commits and %d rollbacks",
+			txManager.committed, txManager.rolledBack)
+	}
+}
+
+func TestBookingService_CancelBooking_ReleaseSharesTransactionWithBooking(t *testing.T) {
+	var outsideTx []string
 	repo := &mockBookingRepo{
-		cancelBookingFn: func(_ context.Context, id int, userID string) error {
-			called = true
-			if id != 10 || userID != "user-1" {
-				t.Errorf("unexpected cancel params: id=%d userID=%s", id, userID)
+		findByIDFn: func(_ context.Context, _ int) (*domain.Booking, error) {
+			return paidBooking(cancelNow.AddDate(0, 0, 30)), nil
+		},
+		refundBookingFn: func(ctx context.Context, _ int, _ string, _ int64) error {
+			if !inTx(ctx) {
+				outsideTx = append(outsideTx, "cancel booking")
 			}
 			return nil
 		},
 	}
-	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+	refunder := &mockRefunder{releaseErr: errors.New("db down")}
+	txManager := &mockTxManager{}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(&mockPolicyRepo{}, authorizedCharge(), refunder),
+		service.WithBookingTxManager(txManager),
+		service.WithBookingClock(func() time.Time { return cancelNow }))
 
-	err := svc.CancelBooking(context.Background(), 10, "user-1")
-	if err != nil {
+	if err := svc.CancelBooking(context.Background(), 10, "user-1"); err == nil {
+		t.Fatal("expected the failed release to fail the cancellation")
+	}
+	if len(outsideTx) != 0 {
+		t.Errorf("expected every write in the transaction, got %v outside it", outsideTx)
+	}
+	if txManager.rolledBack != 1 || txManager.committed != 0 {
+		t.Errorf("expected the cancellation rolled back, got %d commits and %d rollbacks",
+			txManager.committed, txManager.rolledBack)
+	}
+}
+
+func TestBookingService_CancelBooking_AuthorizedPaymentIsReleasedNotRefunded(t *testing.T) {
+	var owed int64
+	repo := refundTestRepo(paidBooking(cancelNow.AddDate(0, 0, 3)), &owed)
+	policies := &mockPolicyRepo{
+		getPolicyForRoomFn: func(_ context.Context, _ int) (*domain.CancellationPolicy, error) {
+			return &domain.CancellationPolicy{FreeCancellationDays: 7, PenaltyPercent: 25}, nil
+		},
+	}
+	refunder := &mockRefunder{}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(policies, authorizedCharge(), refunder),
+		service.WithBookingClock(func() time.Time { return cancelNow }))
+
+	if err := svc.CancelBooking(context.Background(), 10, "user-1"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !called {
-		t.Error("expected CancelBooking to be called on repo")
+	if owed != 0 {
+		t.Errorf("expected booking cancelled with nothing owed, got %d", owed)
+	}
+	want := refundCall{paymentID: "pay-1", bookingID: 10, amount: 150}
+	if len(refunder.releases) != 1 || refunder.releases[0] != want {
+		t.Errorf("expected release %+v, got %+v", want, refunder.releases)
+	}
+	if len(refunder.calls) != 0 {
+		t.Errorf("expected no refund, got %+v", refunder.calls)
 	}
 }
 
-func TestBookingService_CancelBooking_Unauthorized(t *testing.T) {
+func TestBookingService_CancelBooking_CapturedMeanwhileFallsBackToRefund(t *testing.T) {
+	var owed int64
+	repo := refundTestRepo(paidBooking(cancelNow.AddDate(0, 0, 30)), &owed)
+	refunder := &mockRefunder{releaseErr: domain.ErrConflict}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(&mockPolicyRepo{}, authorizedCharge(), refunder),
+		service.WithBookingClock(func() time.Time { return cancelNow }))
+
+	if err := svc.CancelBooking(context.Background(), 10, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := refundCall{paymentID: "pay-1", bookingID: 10, amount: 200}
+	if len(refunder.calls) != 1 || refunder.calls[0] != want {
+		t.Errorf("expected fallback refund %+v, got %+v", want, refunder.calls)
+	}
+}
+
+func TestBookingService_CancelBooking_ItineraryLegRefundsSharedCharge(t *testing.T) {
+	itineraryID := 4
+	booking := paidBooking(cancelNow.AddDate(0, 0, 5))
+	booking.ID = 12
+	booking.ItineraryID = &itineraryID
+	var owed int64
+	repo := refundTestRepo(booking, &owed)
+	repo.listByItineraryFn = func(_ context.Context, _ int) ([]*domain.Booking, error) {
+		return []*domain.Booking{{ID: 11}, booking}, nil
+	}
+	var chargedBookingID int
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			chargedBookingID = bookingID
+			return &domain.Payment{ID: "pay-it", Amount: 500, RefundedAmount: 350, Status: domain.PaymentStatusPartiallyRefunded}, nil
+		},
+	})
+	refunder := &mockRefunder{}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(&mockPolicyRepo{}, payRepo, refunder),
+		service.WithBookingClock(func() time.Time { return cancelNow }))
+
+	if err := svc.CancelBooking(context.Background(), 12, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chargedBookingID != 11 {
+		t.Errorf("expected charge looked up on lead leg 11, got %d", chargedBookingID)
+	}
+	// Only 150 of the shared charge is left to refund, and it is refunded
+	// for the cancelled leg.
+	want := refundCall{paymentID: "pay-it", bookingID: 12, amount: 150}
+	if len(refunder.calls) != 1 || refunder.calls[0] != want {
+		t.Errorf("expected refund %+v, got %+v", want, refunder.calls)
+	}
+}
+
+func TestBookingService_CancelBooking_ModifiedBookingGivesBackTopUps(t *testing.T) {
+	tests := []struct {
+		name         string
+		checkIn      time.Time
+		topUpStatus  domain.PaymentStatus
+		wantOwed     int64
+		wantRefunds  []refundCall
+		wantReleases []refundCall
+	}{
+		{
+			name:         "free cancellation releases the authorized top-up",
+			checkIn:      cancelNow.AddDate(0, 0, 30),
+			topUpStatus:  domain.PaymentStatusAuthorized,
+			wantOwed:     200,
+			wantRefunds:  []refundCall{{paymentID: "pay-1", bookingID: 10, amount: 200}},
+			wantReleases: []refundCall{{paymentID: "top-1", bookingID: 10, amount: 100}},
+		},
+		{
+			name:        "free cancellation refunds the captured top-up",
+			checkIn:     cancelNow.AddDate(0, 0, 30),
+			topUpStatus: domain.PaymentStatusCaptured,
+			wantOwed:    300,
+			wantRefunds: []refundCall{
+				{paymentID: "pay-1", bookingID: 10, amount: 200},
+				{paymentID: "top-1", bookingID: 10, amount: 100},
+			},
+		},
+		{
+			name:         "penalty stays on the last payment",
+			checkIn:      cancelNow.AddDate(0, 0, 3),
+			topUpStatus:  domain.PaymentStatusAuthorized,
+			wantOwed:     200,
+			wantRefunds:  []refundCall{{paymentID: "pay-1", bookingID: 10, amount: 200}},
+			wantReleases: []refundCall{{paymentID: "top-1", bookingID: 10, amount: 25}},
+		},
+		{
+			name:        "voided top-up is left alone",
+			checkIn:     cancelNow.AddDate(0, 0, 30),
+			topUpStatus: domain.PaymentStatusVoided,
+			wantOwed:    200,
+			wantRefunds: []refundCall{{paymentID: "pay-1", bookingID: 10, amount: 200}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A 200 stay moved to a 300 one: the difference was taken as a top-up.
+			booking := paidBooking(tt.checkIn)
+			booking.TotalPrice, booking.ChargeTotal = 300, 300
+			var owed int64
+			repo := refundTestRepo(booking, &owed)
+			policies := &mockPolicyRepo{
+				getPolicyForRoomFn: func(_ context.Context, _ int) (*domain.CancellationPolicy, error) {
+					return &domain.CancellationPolicy{FreeCancellationDays: 7, PenaltyPercent: 25}, nil
+				},
+			}
+			payRepo := succeededCharge()
+			payRepo.listTopUpsFn = func(_ context.Context, bookingID int) ([]*domain.Payment, error) {
+				return []*domain.Payment{
+					{ID: "top-1", BookingID: bookingID, Amount: 100, Kind: domain.PaymentKindTopUp, Status: tt.topUpStatus},
+				}, nil
+			}
+			refunder := &mockRefunder{}
+			svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+				service.WithCancellationPolicies(policies, payRepo, refunder),
+				service.WithBookingClock(func() time.Time { return cancelNow }))
+
+			if err := svc.CancelBooking(context.Background(), 10, "user-1"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if owed != tt.wantOwed {
+				t.Errorf("expected booking refunded with %d, got %d", tt.wantOwed, owed)
+			}
+			if !slices.Equal(refunder.calls, tt.wantRefunds) {
+				t.Errorf("expected refunds %+v, got %+v", tt.wantRefunds, refunder.calls)
+			}
+			if !slices.Equal(refunder.releases, tt.wantReleases) {
+				t.Errorf("expected releases %+v, got %+v", tt.wantReleases, refunder.releases)
+			}
+		})
+	}
+}
+
+func TestBookingService_CancelBooking_UnpaidBookingIsPlainCancel(t *testing.T) {
+	booking := paidBooking(cancelNow.AddDate(0, 0, 5))
+	booking.Status = domain.BookingStatusPending
+	cancelled := false
 	repo := &mockBookingRepo{
-		cancelBookingFn: func(_ context.Context, id int, userID string) error {
-			return domain.ErrUnauthorized
+		findByIDFn: func(_ context.Context, _ int) (*domain.Booking, error) { return booking, nil },
+		cancelBookingFn: func(_ context.Context, _ int, _ string) error {
+			cancelled = true
+			return nil
 		},
 	}
-	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(&mockPolicyRepo{}, succeededCharge(), &mockRefunder{}))
 
-	err := svc.CancelBooking(context.Background(), 10, "wrong-user")
-	if !errors.Is(err, domain.ErrUnauthorized) {
-		t.Errorf("expected ErrUnauthorized, got %v", err)
+	if err := svc.CancelBooking(context.Background(), 10, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cancelled {
+		t.Error("expected pending booking to be cancelled without refund")
 	}
 }
 
-func TestBookingService_CancelBooking_AlreadyCancelled(t *testing.T) {
+func TestBookingService_CancelBooking_WithPoliciesWrongUser(t *testing.T) {
 	repo := &mockBookingRepo{
-		cancelBookingFn: func(_ context.Context, id int, userID string) error {
-			return domain.ErrConflict
+		findByIDFn: func(_ context.Context, _ int) (*domain.Booking, error) {
+			return paidBooking(cancelNow), nil
 		},
 	}
-	svc := service.NewBookingService(repo, &mockBookingRoomRepo{})
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{},
+		service.WithCancellationPolicies(&mockPolicyRepo{}, succeededCharge(), &mockRefunder{}))
 
-	err := svc.CancelBooking(context.Background(), 10, "user-1")
-	if !errors.Is(err, domain.ErrConflict) {
-		t.Errorf("expected ErrConflict, got %v", err)
+	err := svc.CancelBooking(context.Background(), 10, "other-user")
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
 	}
 }
 
@@ -435,3 +1483,461 @@ func TestBookingService_GetBookingStatus_WrongUser(t *testing.T) {
 		t.Errorf("expected ErrForbidden, got %v", err)
 	}
 }
+
+// fakeQuoteSigner keeps signed quotes in memory, keyed by their token.
+type fakeQuoteSigner struct {
+	quotes map[string]domain.Quote
+}
+
+func (f *fakeQuoteSigner) SignQuote(q *domain.Quote) (string, error) {
+	if f.quotes == nil {
+		f.quotes = map[string]domain.Quote{}
+	}
+	token := fmt.Sprintf("quote-%d", len(f.quotes)+1)
+	f.quotes[token] = *q
+	return token, nil
+}
+
+func (f *fakeQuoteSigner) VerifyQuote(token string) (*domain.Quote, error) {
+	q, ok := f.quotes[token]
+	if !ok {
+		return nil, errors.New("unknown token")
+	}
+	return &q, nil
+}
+
+func quoteTestRooms() *mockBookingRoomRepo {
+	return &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 10, PricePerNight: 10000, Currency: "USD", Capacity: 2}, nil
+		},
+	}
+}
+
+func quoteTestCharges() *mockHotelChargeRepo {
+	service, vat := 10.0, 7.0
+	return &mockHotelChargeRepo{
+		listChargesFn: func(_ context.Context, hotelID int) ([]*domain.HotelCharge, error) {
+			return []*domain.HotelCharge{
+				{HotelID: hotelID, Name: "Service", Type: domain.ChargeTypeFee, Percent: &service},
+				{HotelID: hotelID, Name: "VAT", Type: domain.ChargeTypeTax, Percent: &vat},
+			}, nil
+		},
+	}
+}
+
+func TestBookingService_QuoteStay_AppliesChargesAndSignsQuote(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	signer := &fakeQuoteSigner{}
+	svc := service.NewBookingService(&mockBookingRepo{}, quoteTestRooms(),
+		service.WithHotelCharges(quoteTestCharges()),
+		service.WithQuotes(signer, 15*time.Minute),
+		service.WithBookingClock(func() time.Time { return now }),
+	)
+
+	quote, token, err := svc.QuoteStay(context.Background(), domain.QuoteInput{
+		RoomID:   1,
+		CheckIn:  time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		CheckOut: time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC),
+		Adults:   2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 20000 + 10% service = 22000, + 7% VAT = 23540
+	if quote.Subtotal != 20000 || quote.Fees != 2000 || quote.Taxes != 1540 || quote.Total != 23540 {
+		t.Errorf("unexpected quote: subtotal=%d fees=%d taxes=%d total=%d", quote.Subtotal, quote.Fees, quote.Taxes, quote.Total)
+	}
+	if quote.ChargeCurrency != "USD" || quote.ChargeTotal != 23540 {
+		t.Errorf("expected charge of 23540 USD, got %d %s", quote.ChargeTotal, quote.ChargeCurrency)
+	}
+	if token == "" || !quote.ExpiresAt.Equal(now.Add(15*time.Minute)) {
+		t.Errorf("expected a token expiring at %v, got %q expiring at %v", now.Add(15*time.Minute), token, quote.ExpiresAt)
+	}
+}
+
+func TestBookingService_QuoteStay_TooManyGuests(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, quoteTestRooms())
+
+	_, _, err := svc.QuoteStay(context.Background(), domain.QuoteInput{
+		RoomID:   1,
+		CheckIn:  time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		CheckOut: time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC),
+		Adults:   2,
+		Children: 1,
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestBookingService_CreateBooking_BooksAtQuotedPrice(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	charges := quoteTestCharges()
+	svc := service.NewBookingService(&mockBookingRepo{}, quoteTestRooms(),
+		service.WithHotelCharges(charges),
+		service.WithQuotes(&fakeQuoteSigner{}, 15*time.Minute),
+		service.WithBookingClock(func() time.Time { return now }),
+	)
+	checkIn, checkOut := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	_, token, err := svc.QuoteStay(context.Background(), domain.QuoteInput{RoomID: 1, CheckIn: checkIn, CheckOut: checkOut})
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+
+	// The hotel drops its service fee after the quote was issued.
+	charges.listChargesFn = func(_ context.Context, _ int) ([]*domain.HotelCharge, error) {
+		return []*domain.HotelCharge{}, nil
+	}
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:     "user-1",
+		RoomID:     1,
+		StartDate:  checkIn,
+		EndDate:    checkOut,
+		QuoteToken: token,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if booking.TotalPrice != 23540 || booking.ChargeTotal != 23540 || len(booking.Lines) != 2 {
+		t.Errorf("expected the quoted 23540 with 2 lines, got %d (charge %d) with %+v", booking.TotalPrice, booking.ChargeTotal, booking.Lines)
+	}
+}
+
+func TestBookingService_CreateBooking_RejectsQuoteForAnotherStay(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockBookingRepo{createErr: errors.New("booking should not be created")}
+	svc := service.NewBookingService(repo, quoteTestRooms(),
+		service.WithQuotes(&fakeQuoteSigner{}, 15*time.Minute),
+		service.WithBookingClock(func() time.Time { return now }),
+	)
+	checkIn, checkOut := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	_, token, err := svc.QuoteStay(context.Background(), domain.QuoteInput{RoomID: 1, CheckIn: checkIn, CheckOut: checkOut})
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+
+	_, err = svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:     "user-1",
+		RoomID:     1,
+		StartDate:  checkIn,
+		EndDate:    checkOut.AddDate(0, 0, 2),
+		QuoteToken: token,
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestBookingService_CreateBooking_RejectsExpiredQuote(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockBookingRepo{createErr: errors.New("booking should not be created")}
+	svc := service.NewBookingService(repo, quoteTestRooms(),
+		service.WithQuotes(&fakeQuoteSigner{}, 15*time.Minute),
+		service.WithBookingClock(func() time.Time { return now }),
+	)
+	checkIn, checkOut := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	_, token, err := svc.QuoteStay(context.Background(), domain.QuoteInput{RoomID: 1, CheckIn: checkIn, CheckOut: checkOut})
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+
+	now = now.Add(16 * time.Minute)
+	_, err = svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:     "user-1",
+		RoomID:     1,
+		StartDate:  checkIn,
+		EndDate:    checkOut,
+		QuoteToken: token,
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func promoTestRepo(p *domain.Promotion) *mockPromotionRepo {
+	return &mockPromotionRepo{
+		getPromotionByCodeFn: func(_ context.Context, code string) (*domain.Promotion, error) {
+			if code != p.Code {
+				return nil, domain.ErrNotFound
+			}
+			return p, nil
+		},
+	}
+}
+
+func TestBookingService_CreateBooking_AppliesPromoCode(t *testing.T) {
+	pct := 20.0
+	promo := &domain.Promotion{ID: 3, Code: "SPRING20", Percent: &pct, Active: true}
+	svc := service.NewBookingService(&mockBookingRepo{}, quoteTestRooms(),
+		service.WithHotelCharges(quoteTestCharges()),
+		service.WithPromotions(promoTestRepo(promo)),
+	)
+
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC),
+		PromoCode: " spring20",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 20000 - 20% = 16000, + 10% service = 17600, + 7% VAT = 18832
+	if booking.PromoDiscount != 4000 || booking.TotalPrice != 18832 {
+		t.Errorf("expected a 4000 discount and 18832 total, got %d and %d", booking.PromoDiscount, booking.TotalPrice)
+	}
+	if booking.PromotionID == nil || *booking.PromotionID != 3 || booking.PromoCode != "SPRING20" {
+		t.Errorf("expected promotion 3 SPRING20 on the booking, got %v %q", booking.PromotionID, booking.PromoCode)
+	}
+}
+
+func TestBookingService_CreateBooking_RejectsPromoCode(t *testing.T) {
+	pct, otherHotel := 20.0, 11
+	tests := []struct {
+		name  string
+		code  string
+		promo *domain.Promotion
+	}{
+		{"unknown code", "NOPE", &domain.Promotion{Code: "SPRING20", Percent: &pct, Active: true}},
+		{"inactive", "SPRING20", &domain.Promotion{Code: "SPRING20", Percent: &pct}},
+		{"stay too short", "LONGSTAY", &domain.Promotion{Code: "LONGSTAY", Percent: &pct, MinNights: 3, Active: true}},
+		{"another hotel", "HOTEL11", &domain.Promotion{Code: "HOTEL11", Percent: &pct, HotelID: &otherHotel, Active: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockBookingRepo{createErr: errors.New("booking should not be created")}
+			svc := service.NewBookingService(repo, quoteTestRooms(), service.WithPromotions(promoTestRepo(tt.promo)))
+
+			_, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+				UserID:    "user-1",
+				RoomID:    1,
+				StartDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC),
+				PromoCode: tt.code,
+			})
+			if !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+		})
+	}
+}
+
+func extraBedTestRooms() *mockBookingRoomRepo {
+	return &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 10, PricePerNight: 10000, Currency: "USD",
+				Capacity: 2, MaxExtraBeds: 1, ExtraGuestFee: 2500}, nil
+		},
+	}
+}
+
+func TestBookingService_CreateBooking_ChargesExtraGuests(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, extraBedTestRooms(),
+		service.WithHotelCharges(quoteTestCharges()),
+	)
+
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC),
+		Adults:    2,
+		Children:  1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 20000 + 2 nights x 2500 = 25000, + 10% service = 27500, + 7% VAT = 29425
+	if booking.ExtraGuestCharge != 5000 || booking.TotalPrice != 29425 {
+		t.Errorf("expected a 5000 extra guest charge and 29425 total, got %d and %d", booking.ExtraGuestCharge, booking.TotalPrice)
+	}
+	if booking.Adults != 2 || booking.Children != 1 {
+		t.Errorf("expected 2 adults and 1 child stored, got %d and %d", booking.Adults, booking.Children)
+	}
+}
+
+func TestBookingService_CreateBooking_DefaultsToOneAdult(t *testing.T) {
+	svc := service.NewBookingService(&mockBookingRepo{}, extraBedTestRooms())
+
+	booking, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if booking.Adults != 1 || booking.ExtraGuestCharge != 0 || booking.TotalPrice != 20000 {
+		t.Errorf("expected 1 adult at 20000, got %d adults at %d", booking.Adults, booking.TotalPrice)
+	}
+}
+
+func TestBookingService_CreateBooking_RejectsGuestsBeyondExtraBeds(t *testing.T) {
+	repo := &mockBookingRepo{createErr: errors.New("booking should not be created")}
+	svc := service.NewBookingService(repo, extraBedTestRooms())
+
+	_, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:    "user-1",
+		RoomID:    1,
+		StartDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC),
+		Adults:    2,
+		Children:  2,
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestBookingService_CreateBooking_RejectsQuoteForOtherGuests(t *testing.T) {
+	repo := &mockBookingRepo{createErr: errors.New("booking should not be created")}
+	svc := service.NewBookingService(repo, extraBedTestRooms(),
+		service.WithQuotes(&fakeQuoteSigner{}, 15*time.Minute),
+	)
+	checkIn, checkOut := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2030, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	_, token, err := svc.QuoteStay(context.Background(), domain.QuoteInput{RoomID: 1, CheckIn: checkIn, CheckOut: checkOut, Adults: 2})
+	if err != nil {
+		t.Fatalf("quote: %v", err)
+	}
+
+	_, err = svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID:     "user-1",
+		RoomID:     1,
+		StartDate:  checkIn,
+		EndDate:    checkOut,
+		Adults:     2,
+		Children:   1,
+		QuoteToken: token,
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+// ---- Waitlist tests ----
+
+func TestBookingService_ClaimWaitlistOffer_BooksHeldStay(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	var claimedID int
+	var claimed *domain.Booking
+	waitlist := &mockWaitlistRepo{
+		getEntryByIDFn: func(_ context.Context, id int) (*domain.WaitlistEntry, error) {
+			return offeredEntry(id, "user-1", now.Add(10*time.Minute)), nil
+		},
+		claimOfferFn: func(_ context.Context, id int, booking *domain.Booking) error {
+			claimedID, claimed = id, booking
+			booking.ID = 77
+			return nil
+		},
+	}
+	svc := service.NewBookingService(&mockBookingRepo{}, waitlistRoomRepo(),
+		service.WithWaitlist(waitlist, &mockReleaseListener{}),
+		service.WithBookingClock(func() time.Time { return now }))
+
+	booking, err := svc.ClaimWaitlistOffer(context.Background(), domain.ClaimWaitlistOfferInput{UserID: "user-1", EntryID: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claimedID != 4 || claimed != booking || booking.ID != 77 {
+		t.Fatalf("expected entry 4 claimed as the returned booking, got entry %d", claimedID)
+	}
+	if booking.UserID != "user-1" || booking.RoomID != 5 || booking.Adults != 2 {
+		t.Errorf("expected user-1's stay in room 5 for 2 adults, got %+v", booking)
+	}
+	// 2 nights * 10000
+	if booking.TotalPrice != 20000 || booking.ChargeCurrency != "EUR" {
+		t.Errorf("expected 20000 EUR, got %d %s", booking.TotalPrice, booking.ChargeCurrency)
+	}
+}
+
+func TestBookingService_ClaimWaitlistOffer_Rejects(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	waiting := offeredEntry(4, "user-1", now.Add(time.Minute))
+	waiting.Status = domain.WaitlistStatusWaiting
+	tests := []struct {
+		name    string
+		entry   *domain.WaitlistEntry
+		wantErr error
+	}{
+		{"another guest's offer", offeredEntry(4, "user-2", now.Add(time.Minute)), domain.ErrForbidden},
+		{"not offered", waiting, domain.ErrConflict},
+		{"offer expired", offeredEntry(4, "user-1", now), domain.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waitlist := &mockWaitlistRepo{
+				getEntryByIDFn: func(context.Context, int) (*domain.WaitlistEntry, error) { return tt.entry, nil },
+				claimOfferFn: func(context.Context, int, *domain.Booking) error {
+					t.Error("offer must not be claimed")
+					return nil
+				},
+			}
+			svc := service.NewBookingService(&mockBookingRepo{}, waitlistRoomRepo(),
+				service.WithWaitlist(waitlist, &mockReleaseListener{}),
+				service.WithBookingClock(func() time.Time { return now }))
+
+			_, err := svc.ClaimWaitlistOffer(context.Background(), domain.ClaimWaitlistOfferInput{UserID: "user-1", EntryID: 4})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBookingService_CancelBooking_OffersStayToWaitlist(t *testing.T) {
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockBookingRepo{
+		cancelBookingFn: func(context.Context, int, string) error { return nil },
+		findByIDFn: func(_ context.Context, id int) (*domain.Booking, error) {
+			return &domain.Booking{ID: id, UserID: "user-1", RoomID: 5, StartDate: start, EndDate: start.AddDate(0, 0, 2),
+				Status: domain.BookingStatusCancelled}, nil
+		},
+	}
+	releases := &mockReleaseListener{}
+	svc := service.NewBookingService(repo, &mockBookingRoomRepo{}, service.WithWaitlist(&mockWaitlistRepo{}, releases))
+
+	if err := svc.CancelBooking(context.Background(), 10, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(releases.released) != 1 || releases.released[0].roomID != 5 || !releases.released[0].start.Equal(start) {
+		t.Errorf("expected room 5 from %v offered to the waitlist, got %+v", start, releases.released)
+	}
+}
+
+func TestBookingService_BookingsAndCancellationsInvalidateAvailability(t *testing.T) {
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockBookingRepo{
+		cancelBookingFn: func(context.Context, int, string) error { return nil },
+		findByIDFn: func(_ context.Context, id int) (*domain.Booking, error) {
+			return &domain.Booking{ID: id, UserID: "user-1", RoomID: 5, StartDate: start, EndDate: start.AddDate(0, 0, 2),
+				Status: domain.BookingStatusCancelled}, nil
+		},
+	}
+	roomRepo := &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, PricePerNight: 150}, nil
+		},
+	}
+	availability := &mockAvailabilityInvalidator{}
+	svc := service.NewBookingService(repo, roomRepo, service.WithAvailabilityInvalidator(availability))
+
+	if _, err := svc.CreateBooking(context.Background(), domain.CreateBookingInput{
+		UserID: "user-1", RoomID: 3, StartDate: start, EndDate: start.AddDate(0, 0, 2),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.CancelBooking(context.Background(), 10, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(availability.rooms) != "[3 5]" {
+		t.Errorf("expected rooms 3 and 5 invalidated, got %v", availability.rooms)
+	}
+}
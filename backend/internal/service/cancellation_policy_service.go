@@ -0,0 +1,107 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"fmt"
+)
+
+// CancellationPolicyInput holds the terms of a cancellation policy.
+type CancellationPolicyInput struct {
+	FreeCancellationDays int
+	PenaltyPercent       float64
+	NonRefundable        bool
+	// CaptureDaysBeforeCheckIn defers the capture of the payment; nil
+	// captures at checkout.
+	CaptureDaysBeforeCheckIn *int
+}
+
+// CancellationPolicyService manages hotel and room cancellation policies.
+type CancellationPolicyService struct {
+	policyRepo repository.CancellationPolicyRepository
+	roomRepo   repository.RoomRepository
+	hotelRepo  repository.HotelRepository
+}
+
+// NewCancellationPolicyService creates a new CancellationPolicyService.
+func NewCancellationPolicyService(
+	policyRepo repository.CancellationPolicyRepository,
+	roomRepo repository.RoomRepository,
+	hotelRepo repository.HotelRepository,
+) *CancellationPolicyService {
+	return &CancellationPolicyService{policyRepo: policyRepo, roomRepo: roomRepo, hotelRepo: hotelRepo}
+}
+
+// SetHotelPolicy sets the default policy for every room of a hotel after
+// verifying the caller owns it.
+func (s *CancellationPolicyService) SetHotelPolicy(ctx context.Context, ownerID string, hotelID int, input CancellationPolicyInput) (*domain.CancellationPolicy, error) {
+	if err := validatePolicyInput(input); err != nil {
+		return nil, err
+	}
+	if err := s.verifyHotelOwner(ctx, hotelID, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.policyRepo.UpsertPolicy(ctx, &domain.CancellationPolicy{
+		HotelID:                  hotelID,
+		FreeCancellationDays:     input.FreeCancellationDays,
+		PenaltyPercent:           input.PenaltyPercent,
+		NonRefundable:            input.NonRefundable,
+		CaptureDaysBeforeCheckIn: input.CaptureDaysBeforeCheckIn,
+	})
+}
+
+// SetRoomPolicy sets a policy for a single room, overriding its hotel's,
+// after verifying the caller owns the parent hotel.
+func (s *CancellationPolicyService) SetRoomPolicy(ctx context.Context, ownerID string, roomID int, input CancellationPolicyInput) (*domain.CancellationPolicy, error) {
+	if err := validatePolicyInput(input); err != nil {
+		return nil, err
+	}
+	room, err := s.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyHotelOwner(ctx, room.HotelID, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.policyRepo.UpsertPolicy(ctx, &domain.CancellationPolicy{
+		HotelID:                  room.HotelID,
+		RoomID:                   &room.ID,
+		FreeCancellationDays:     input.FreeCancellationDays,
+		PenaltyPercent:           input.PenaltyPercent,
+		NonRefundable:            input.NonRefundable,
+		CaptureDaysBeforeCheckIn: input.CaptureDaysBeforeCheckIn,
+	})
+}
+
+// GetRoomPolicy returns the policy that applies to a room.
+// Returns ErrNotFound when neither the room nor its hotel has one.
+func (s *CancellationPolicyService) GetRoomPolicy(ctx context.Context, roomID int) (*domain.CancellationPolicy, error) {
+	return s.policyRepo.GetPolicyForRoom(ctx, roomID)
+}
+
+func (s *CancellationPolicyService) verifyHotelOwner(ctx context.Context, hotelID int, ownerID string) error {
+	hotel, err := s.hotelRepo.GetHotelByID(ctx, hotelID)
+	if err != nil {
+		return err
+	}
+	if hotel.OwnerID != ownerID {
+		return fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
+	}
+	return nil
+}
+
+func validatePolicyInput(input CancellationPolicyInput) error {
+	if input.FreeCancellationDays < 0 {
+		return fmt.Errorf("free_cancellation_days must be non-negative: %w", domain.ErrBadRequest)
+	}
+	if input.PenaltyPercent < 0 || input.PenaltyPercent > 100 {
+		return fmt.Errorf("penalty_percent must be between 0 and 100: %w", domain.ErrBadRequest)
+	}
+	if input.CaptureDaysBeforeCheckIn != nil && *input.CaptureDaysBeforeCheckIn < 0 {
+		return fmt.Errorf("capture_days_before_check_in must be non-negative: %w", domain.ErrBadRequest)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"testing"
+)
+
+// --- Mock CancellationPolicyRepository ---
+
+type mockPolicyRepo struct {
+	upsertPolicyFn     func(ctx context.Context, policy *domain.CancellationPolicy) (*domain.CancellationPolicy, error)
+	getPolicyForRoomFn func(ctx context.Context, roomID int) (*domain.CancellationPolicy, error)
+}
+
+func (m *mockPolicyRepo) UpsertPolicy(ctx context.Context, policy *domain.CancellationPolicy) (*domain.CancellationPolicy, error) {
+	if m.upsertPolicyFn != nil {
+		return m.upsertPolicyFn(ctx, policy)
+	}
+	saved := *policy
+	saved.ID = 1
+	return &saved, nil
+}
+
+func (m *mockPolicyRepo) GetPolicyForRoom(ctx context.Context, roomID int) (*domain.CancellationPolicy, error) {
+	if m.getPolicyForRoomFn != nil {
+		return m.getPolicyForRoomFn(ctx, roomID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func policyTestRepos(ownerID string) (*mockRoomRepo, *mockHotelRepo) {
+	roomRepo := &mockRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 7}, nil
+		},
+	}
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(_ context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, OwnerID: ownerID}, nil
+		},
+	}
+	return roomRepo, hotelRepo
+}
+
+// --- Tests: CancellationPolicyService ---
+
+func TestCancellationPolicyService_SetHotelPolicy_Success(t *testing.T) {
+	roomRepo, hotelRepo := policyTestRepos("owner-1")
+	var saved *domain.CancellationPolicy
+	policyRepo := &mockPolicyRepo{
+		upsertPolicyFn: func(_ context.Context, p *domain.CancellationPolicy) (*domain.CancellationPolicy, error) {
+			saved = p
+			return p, nil
+		},
+	}
+	svc := service.NewCancellationPolicyService(policyRepo, roomRepo, hotelRepo)
+
+	_, err := svc.SetHotelPolicy(context.Background(), "owner-1", 7, service.CancellationPolicyInput{
+		FreeCancellationDays: 3, PenaltyPercent: 20,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if saved.HotelID != 7 || saved.RoomID != nil || saved.FreeCancellationDays != 3 || saved.PenaltyPercent != 20 {
+		t.Errorf("unexpected hotel policy %+v", saved)
+	}
+}
+
+func TestCancellationPolicyService_SetRoomPolicy_ScopesToRoom(t *testing.T) {
+	roomRepo, hotelRepo := policyTestRepos("owner-1")
+	var saved *domain.CancellationPolicy
+	policyRepo := &mockPolicyRepo{
+		upsertPolicyFn: func(_ context.Context, p *domain.CancellationPolicy) (*domain.CancellationPolicy, error) {
+			saved = p
+			return p, nil
+		},
+	}
+	svc := service.NewCancellationPolicyService(policyRepo, roomRepo, hotelRepo)
+
+	_, err := svc.SetRoomPolicy(context.Background(), "owner-1", 12, service.CancellationPolicyInput{NonRefundable: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if saved.HotelID != 7 || saved.RoomID == nil || *saved.RoomID != 12 || !saved.NonRefundable {
+		t.Errorf("unexpected room policy %+v", saved)
+	}
+}
+
+func TestCancellationPolicyService_SetRoomPolicy_NotOwner(t *testing.T) {
+	roomRepo, hotelRepo := policyTestRepos("owner-1")
+	svc := service.NewCancellationPolicyService(&mockPolicyRepo{}, roomRepo, hotelRepo)
+
+	_, err := svc.SetRoomPolicy(context.Background(), "intruder", 12, service.CancellationPolicyInput{})
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestCancellationPolicyService_SetHotelPolicy_InvalidPenalty(t *testing.T) {
+	roomRepo, hotelRepo := policyTestRepos("owner-1")
+	svc := service.NewCancellationPolicyService(&mockPolicyRepo{}, roomRepo, hotelRepo)
+
+	_, err := svc.SetHotelPolicy(context.Background(), "owner-1", 7, service.CancellationPolicyInput{PenaltyPercent: 120})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestCancellationPolicyService_SetHotelPolicy_NegativeCaptureDays(t *testing.T) {
+	roomRepo, hotelRepo := policyTestRepos("owner-1")
+	svc := service.NewCancellationPolicyService(&mockPolicyRepo{}, roomRepo, hotelRepo)
+
+	days := -1
+	_, err := svc.SetHotelPolicy(context.Background(), "owner-1", 7, service.CancellationPolicyInput{CaptureDaysBeforeCheckIn: &days})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"booking-app/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	captureBatchSize            = 100
+	defaultCaptureSweepInterval = 5 * time.Minute
+)
+
+// PaymentCapturer collects a single authorized payment.
+type PaymentCapturer interface {
+	CapturePayment(ctx context.Context, paymentID string) error
+}
+
+// CaptureWorkerOption configures a CaptureWorker.
+type CaptureWorkerOption func(*CaptureWorker)
+
+// WithCaptureClock overrides the time source (used in tests).
+func WithCaptureClock(now func() time.Time) CaptureWorkerOption {
+	return func(w *CaptureWorker) { w.now = now }
+}
+
+// CaptureWorker periodically captures authorized payments whose capture time
+// has come: at check-in, or as many days before it as the cancellation policy
+// asks.
+type CaptureWorker struct {
+	payRepo  repository.PaymentRepository
+	capturer PaymentCapturer
+	interval time.Duration
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewCaptureWorker creates a new CaptureWorker. A zero interval uses the
+// package default.
+func NewCaptureWorker(
+	payRepo repository.PaymentRepository,
+	capturer PaymentCapturer,
+	interval time.Duration,
+	logger *zap.Logger,
+	opts ...CaptureWorkerOption,
+) *CaptureWorker {
+	if interval <= 0 {
+		interval = defaultCaptureSweepInterval
+	}
+	w := &CaptureWorker{
+		payRepo:  payRepo,
+		capturer: capturer,
+		interval: interval,
+		logger:   logger,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run starts the sweep loop. It blocks until ctx is cancelled.
+// The first sweep runs immediately, then every interval.
+func (w *CaptureWorker) Run(ctx context.Context) error {
+	w.logger.Info("capture worker started", zap.Duration("interval", w.interval))
+
+	if _, err := w.CaptureDue(ctx); err != nil {
+		w.logger.Error("capture iteration error", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("capture worker stopped")
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := w.CaptureDue(ctx); err != nil {
+				w.logger.Error("capture iteration error", zap.Error(err))
+			}
+		}
+	}
+}
+
+// CaptureDue runs a single sweep and returns how many payments it attempted
+// to capture without error. A payment that fails is logged and retried on the
+// next sweep.
+func (w *CaptureWorker) CaptureDue(ctx context.Context) (int, error) {
+	due, err := w.payRepo.ListDueCaptures(ctx, w.now(), captureBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list due captures: %w", err)
+	}
+
+	captured := 0
+	for _, payment := range due {
+		if err := w.capturer.CapturePayment(ctx, payment.ID); err != nil {
+			w.logger.Error("failed to capture payment",
+				zap.String("payment_id", payment.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		captured++
+	}
+
+	if captured > 0 {
+		w.logger.Info("captured due payments", zap.Int("count", captured))
+	}
+	return captured, nil
+}
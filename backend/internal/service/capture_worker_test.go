@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type mockPaymentCapturer struct {
+	captureFn func(ctx context.Context, paymentID string) error
+}
+
+func (m *mockPaymentCapturer) CapturePayment(ctx context.Context, paymentID string) error {
+	return m.captureFn(ctx, paymentID)
+}
+
+// --- Tests: CaptureWorker ---
+
+func TestCaptureWorker_CaptureDue_CapturesEveryDuePayment(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	var gotBefore time.Time
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		listDueCapturesFn: func(_ context.Context, before time.Time, _ int) ([]*domain.Payment, error) {
+			gotBefore = before
+			return []*domain.Payment{{ID: "pay-1"}, {ID: "pay-2"}, {ID: "pay-3"}}, nil
+		},
+	})
+	var captured []string
+	capturer := &mockPaymentCapturer{
+		captureFn: func(_ context.Context, paymentID string) error {
+			if paymentID == "pay-2" {
+				return errors.New("gateway down")
+			}
+			captured = append(captured, paymentID)
+			return nil
+		},
+	}
+	worker := service.NewCaptureWorker(payRepo, capturer, time.Minute, zap.NewNop(),
+		service.WithCaptureClock(func() time.Time { return now }))
+
+	n, err := worker.CaptureDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !gotBefore.Equal(now) {
+		t.Errorf("expected payments due by %v, got %v", now, gotBefore)
+	}
+	// A failed capture does not stop the sweep; it is retried next time.
+	if n != 2 || len(captured) != 2 || captured[0] != "pay-1" || captured[1] != "pay-3" {
+		t.Errorf("expected pay-1 and pay-3 captured, got %d %v", n, captured)
+	}
+}
+
+func TestCaptureWorker_CaptureDue_ListError(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		listDueCapturesFn: func(_ context.Context, _ time.Time, _ int) ([]*domain.Payment, error) {
+			return nil, errors.New("db down")
+		},
+	})
+	worker := service.NewCaptureWorker(payRepo, &mockPaymentCapturer{}, 0, zap.NewNop())
+
+	if _, err := worker.CaptureDue(context.Background()); err == nil {
+		t.Error("expected error when listing due captures fails")
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ExchangeRateService manages the exchange rates bookings are converted at.
+type ExchangeRateService struct {
+	repo repository.ExchangeRateRepository
+}
+
+// NewExchangeRateService creates a new ExchangeRateService.
+func NewExchangeRateService(repo repository.ExchangeRateRepository) *ExchangeRateService {
+	return &ExchangeRateService{repo: repo}
+}
+
+// SetRates validates and stores an uploaded set of rates, replacing existing
+// rates for the same pairs. Bookings already priced keep the rate they were
+// locked at.
+func (s *ExchangeRateService) SetRates(ctx context.Context, rates []*domain.ExchangeRate) ([]*domain.ExchangeRate, error) {
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("at least one rate is required: %w", domain.ErrBadRequest)
+	}
+	seen := make(map[string]bool, len(rates))
+	for _, rate := range rates {
+		rate.Base = strings.ToUpper(strings.TrimSpace(rate.Base))
+		rate.Quote = strings.ToUpper(strings.TrimSpace(rate.Quote))
+		if !domain.ValidCurrency(rate.Base) || !domain.ValidCurrency(rate.Quote) {
+			return nil, fmt.Errorf("invalid currency pair %s/%s: %w", rate.Base, rate.Quote, domain.ErrBadRequest)
+		}
+		if rate.Base == rate.Quote {
+			return nil, fmt.Errorf("rate %s/%s converts a currency to itself: %w", rate.Base, rate.Quote, domain.ErrBadRequest)
+		}
+		if rate.Rate <= 0 {
+			return nil, fmt.Errorf("rate %s/%s must be positive: %w", rate.Base, rate.Quote, domain.ErrBadRequest)
+		}
+		pair := rate.Base + "/" + rate.Quote
+		if seen[pair] {
+			return nil, fmt.Errorf("rate %s given more than once: %w", pair, domain.ErrBadRequest)
+		}
+		seen[pair] = true
+	}
+
+	if err := s.repo.UpsertRates(ctx, rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// ListRates returns every stored rate.
+func (s *ExchangeRateService) ListRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	return s.repo.ListRates(ctx)
+}
@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"testing"
+)
+
+// --- Mock ExchangeRateRepository ---
+
+type mockExchangeRateRepo struct {
+	upsertRatesFn func(ctx context.Context, rates []*domain.ExchangeRate) error
+	listRatesFn   func(ctx context.Context) ([]*domain.ExchangeRate, error)
+	getRateFn     func(ctx context.Context, base, quote string) (*domain.ExchangeRate, error)
+}
+
+func (m *mockExchangeRateRepo) UpsertRates(ctx context.Context, rates []*domain.ExchangeRate) error {
+	if m.upsertRatesFn != nil {
+		return m.upsertRatesFn(ctx, rates)
+	}
+	return nil
+}
+
+func (m *mockExchangeRateRepo) ListRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	if m.listRatesFn != nil {
+		return m.listRatesFn(ctx)
+	}
+	return []*domain.ExchangeRate{}, nil
+}
+
+func (m *mockExchangeRateRepo) GetRate(ctx context.Context, base, quote string) (*domain.ExchangeRate, error) {
+	if m.getRateFn != nil {
+		return m.getRateFn(ctx, base, quote)
+	}
+	return nil, domain.ErrNotFound
+}
+
+// fixedRates serves the given rates, keyed "BASE/QUOTE".
+func fixedRates(rates map[string]float64) *mockExchangeRateRepo {
+	return &mockExchangeRateRepo{
+		getRateFn: func(_ context.Context, base, quote string) (*domain.ExchangeRate, error) {
+			rate, ok := rates[base+"/"+quote]
+			if !ok {
+				return nil, domain.ErrNotFound
+			}
+			return &domain.ExchangeRate{Base: base, Quote: quote, Rate: rate}, nil
+		},
+	}
+}
+
+// --- Tests: ExchangeRateService ---
+
+func TestExchangeRateService_SetRates_NormalizesAndStores(t *testing.T) {
+	var stored []*domain.ExchangeRate
+	repo := &mockExchangeRateRepo{
+		upsertRatesFn: func(_ context.Context, rates []*domain.ExchangeRate) error {
+			stored = rates
+			return nil
+		},
+	}
+	svc := service.NewExchangeRateService(repo)
+
+	_, err := svc.SetRates(context.Background(), []*domain.ExchangeRate{
+		{Base: "usd", Quote: " thb", Rate: 36.5},
+		{Base: "JPY", Quote: "USD", Rate: 0.0067},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 2 || stored[0].Base != "USD" || stored[0].Quote != "THB" {
+		t.Errorf("expected normalized rates to be stored, got %+v", stored)
+	}
+}
+
+func TestExchangeRateService_SetRates_RejectsInvalidRates(t *testing.T) {
+	tests := []struct {
+		name  string
+		rates []*domain.ExchangeRate
+	}{
+		{"empty upload", nil},
+		{"unknown currency code", []*domain.ExchangeRate{{Base: "US", Quote: "THB", Rate: 36}}},
+		{"same currency", []*domain.ExchangeRate{{Base: "USD", Quote: "USD", Rate: 1}}},
+		{"non-positive rate", []*domain.ExchangeRate{{Base: "USD", Quote: "THB", Rate: 0}}},
+		{"duplicate pair", []*domain.ExchangeRate{
+			{Base: "USD", Quote: "THB", Rate: 36},
+			{Base: "usd", Quote: "thb", Rate: 37},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			repo := &mockExchangeRateRepo{
+				upsertRatesFn: func(_ context.Context, _ []*domain.ExchangeRate) error {
+					called = true
+					return nil
+				},
+			}
+			_, err := service.NewExchangeRateService(repo).SetRates(context.Background(), tt.rates)
+			if !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+			if called {
+				t.Error("expected nothing to be stored")
+			}
+		})
+	}
+}
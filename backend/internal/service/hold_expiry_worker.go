@@ -0,0 +1,248 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	holdExpiryBatchSize       = 100
+	defaultPendingHoldTTL     = 15 * time.Minute
+	defaultAwaitingPaymentTTL = 30 * time.Minute
+	defaultHoldSweepInterval  = time.Minute
+)
+
+// HoldRepository is the booking repo surface needed by the hold reaper.
+type HoldRepository interface {
+	ListExpiredHolds(ctx context.Context, pendingBefore, awaitingBefore time.Time, limit int) ([]*domain.Booking, error)
+	ExpireBooking(ctx context.Context, id int) error
+}
+
+// HoldExpiryConfig controls how long bookings may hold inventory.
+// Zero values fall back to the package defaults.
+type HoldExpiryConfig struct {
+	// PendingTTL is how long a booking may stay pending without a checkout.
+	PendingTTL time.Duration
+	// AwaitingPaymentTTL is how long a checkout may wait for a gateway result.
+	AwaitingPaymentTTL time.Duration
+	// Interval is the delay between sweeps.
+	Interval time.Duration
+}
+
+// HoldExpiryOption configures a HoldExpiryWorker.
+type HoldExpiryOption func(*HoldExpiryWorker)
+
+// WithHoldExpiryNotifier wires an optional notification sender.
+func WithHoldExpiryNotifier(n NotificationSender) HoldExpiryOption {
+	return func(w *HoldExpiryWorker) { w.notifier = n }
+}
+
+// WithHoldExpiryTxManager expires each booking in a unit of work, so its
+// status change, inventory release, payment timeout and event commit together.
+func WithHoldExpiryTxManager(tm repository.TxManager) HoldExpiryOption {
+	return func(w *HoldExpiryWorker) { w.txManager = tm }
+}
+
+// WithHoldExpiryClock overrides the time source (used in tests).
+func WithHoldExpiryClock(now func() time.Time) HoldExpiryOption {
+	return func(w *HoldExpiryWorker) { w.now = now }
+}
+
+// HoldExpiryWorker periodically expires bookings that hold inventory without
+// being paid for: pending bookings that were never checked out, and
+// awaiting_payment bookings whose payment never got a gateway result.
+// Each expired booking releases its inventory, emits a BookingExpired outbox
+// event and notifies the guest.
+type HoldExpiryWorker struct {
+	bookingRepo       HoldRepository
+	payRepo           repository.PaymentRepository
+	outboxRepo        repository.OutboxRepository
+	inventoryRestorer InventoryRestorer
+	cfg               HoldExpiryConfig
+	logger            *zap.Logger
+	notifier          NotificationSender   // optional
+	txManager         repository.TxManager // optional
+	now               func() time.Time
+}
+
+// NewHoldExpiryWorker creates a new HoldExpiryWorker.
+func NewHoldExpiryWorker(
+	bookingRepo HoldRepository,
+	payRepo repository.PaymentRepository,
+	outboxRepo repository.OutboxRepository,
+	inventoryRestorer InventoryRestorer,
+	cfg HoldExpiryConfig,
+	logger *zap.Logger,
+	opts ...HoldExpiryOption,
+) *HoldExpiryWorker {
+	if cfg.PendingTTL <= 0 {
+		cfg.PendingTTL = defaultPendingHoldTTL
+	}
+	if cfg.AwaitingPaymentTTL <= 0 {
+		cfg.AwaitingPaymentTTL = defaultAwaitingPaymentTTL
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultHoldSweepInterval
+	}
+	w := &HoldExpiryWorker{
+		bookingRepo:       bookingRepo,
+		payRepo:           payRepo,
+		outboxRepo:        outboxRepo,
+		inventoryRestorer: inventoryRestorer,
+		cfg:               cfg,
+		logger:            logger,
+		now:               time.Now,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run starts the sweep loop. It blocks until ctx is cancelled.
+// The first sweep runs immediately, then every cfg.Interval.
+func (w *HoldExpiryWorker) Run(ctx context.Context) error {
+	w.logger.Info("hold expiry worker started",
+		zap.Duration("pending_ttl", w.cfg.PendingTTL),
+		zap.Duration("awaiting_payment_ttl", w.cfg.AwaitingPaymentTTL),
+	)
+
+	if _, err := w.ExpireHolds(ctx); err != nil {
+		w.logger.Error("hold expiry iteration error", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("hold expiry worker stopped")
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := w.ExpireHolds(ctx); err != nil {
+				w.logger.Error("hold expiry iteration error", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ExpireHolds runs a single sweep and returns how many bookings were expired.
+// A booking that leaves its holding state between listing and expiry (e.g.
+// its payment just succeeded) is skipped.
+func (w *HoldExpiryWorker) ExpireHolds(ctx context.Context) (int, error) {
+	now := w.now()
+	holds, err := w.bookingRepo.ListExpiredHolds(ctx,
+		now.Add(-w.cfg.PendingTTL), now.Add(-w.cfg.AwaitingPaymentTTL), holdExpiryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list expired holds: %w", err)
+	}
+
+	expired := 0
+	for _, booking := range holds {
+		if err := w.expire(ctx, booking, now); err != nil {
+			if errors.Is(err, domain.ErrConflict) {
+				w.logger.Debug("hold no longer active, skipping", zap.Int("booking_id", booking.ID))
+				continue
+			}
+			w.logger.Error("failed to expire hold",
+				zap.Int("booking_id", booking.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		expired++
+	}
+
+	if expired > 0 {
+		w.logger.Info("expired booking holds", zap.Int("count", expired))
+	}
+	return expired, nil
+}
+
+// expire expires a booking, releases its inventory, times out its payment and
+// emits a BookingExpired event in one unit of work, then notifies the guest.
+func (w *HoldExpiryWorker) expire(ctx context.Context, booking *domain.Booking, now time.Time) error {
+	err := withinTx(ctx, w.txManager, func(ctx context.Context) error {
+		return w.release(ctx, booking, now)
+	})
+	if err != nil {
+		return err
+	}
+
+	if w.notifier != nil {
+		_ = w.notifier.Notify(ctx, booking.UserID, domain.NotificationTypeBookingExpired,
+			"Booking Expired",
+			fmt.Sprintf("Your hold on booking #%d expired before payment was completed. The room has been released.", booking.ID),
+			map[string]any{"booking_id": booking.ID},
+		) // best-effort
+	}
+	return nil
+}
+
+// release writes the expiry of a booking: its status, inventory, payment and
+// outbox event.
+func (w *HoldExpiryWorker) release(ctx context.Context, booking *domain.Booking, now time.Time) error {
+	if err := w.bookingRepo.ExpireBooking(ctx, booking.ID); err != nil {
+		return err
+	}
+
+	if err := w.inventoryRestorer.RestoreInventory(ctx, booking.RoomID, booking.StartDate, booking.EndDate, booking.Quantity); err != nil {
+		return fmt.Errorf("restore inventory: %w", err)
+	}
+
+	paymentID, err := w.abandonPayment(ctx, booking.ID)
+	if err != nil {
+		return err
+	}
+
+	payload := domain.BookingExpiredPayload{
+		BookingID:      booking.ID,
+		PaymentID:      paymentID,
+		UserID:         booking.UserID,
+		PreviousStatus: booking.Status,
+		ExpiredAt:      now,
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	if err := w.outboxRepo.CreateEvent(ctx, &domain.OutboxEvent{
+		AggregateType: "booking",
+		AggregateID:   fmt.Sprintf("%d", booking.ID),
+		EventType:     domain.EventTypeBookingExpired,
+		Payload:       raw,
+	}); err != nil {
+		return fmt.Errorf("emit expired event: %w", err)
+	}
+	return nil
+}
+
+// abandonPayment times out a checkout payment that never got a gateway result.
+// timed_out is terminal, so a payment still queued for processing will not be
+// charged for the released room; one the gateway approves while timing out is
+// given back by the saga, which can no longer confirm the expired booking.
+// Returns the payment ID, or "" when the booking has no payment attached.
+func (w *HoldExpiryWorker) abandonPayment(ctx context.Context, bookingID int) (string, error) {
+	payment, err := w.payRepo.GetPaymentByBookingID(ctx, bookingID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get payment: %w", err)
+	}
+
+	if payment.Status == domain.PaymentStatusPending || payment.Status == domain.PaymentStatusProcessing {
+		if err := w.payRepo.UpdatePaymentStatus(ctx, payment.ID, domain.PaymentStatusTimedOut, "", "booking hold expired"); err != nil {
+			return "", fmt.Errorf("time out payment: %w", err)
+		}
+	}
+	return payment.ID, nil
+}
@@ -0,0 +1,294 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// --- Mock HoldRepository ---
+
+type mockHoldRepo struct {
+	listExpiredHoldsFn func(ctx context.Context, pendingBefore, awaitingBefore time.Time, limit int) ([]*domain.Booking, error)
+	expireBookingFn    func(ctx context.Context, id int) error
+}
+
+func (m *mockHoldRepo) ListExpiredHolds(ctx context.Context, pendingBefore, awaitingBefore time.Time, limit int) ([]*domain.Booking, error) {
+	if m.listExpiredHoldsFn != nil {
+		return m.listExpiredHoldsFn(ctx, pendingBefore, awaitingBefore, limit)
+	}
+	return []*domain.Booking{}, nil
+}
+
+func (m *mockHoldRepo) ExpireBooking(ctx context.Context, id int) error {
+	if m.expireBookingFn != nil {
+		return m.expireBookingFn(ctx, id)
+	}
+	return nil
+}
+
+func staleHold(id int, status string) *domain.Booking {
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.Booking{
+		ID:        id,
+		UserID:    "user-1",
+		RoomID:    10,
+		Quantity:  2,
+		Status:    status,
+		StartDate: start,
+		EndDate:   start.AddDate(0, 0, 3),
+	}
+}
+
+// --- Tests: HoldExpiryWorker ---
+
+func TestHoldExpiryWorker_ExpireHolds_UsesConfiguredTTLs(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	var gotPending, gotAwaiting time.Time
+	repo := &mockHoldRepo{
+		listExpiredHoldsFn: func(_ context.Context, pendingBefore, awaitingBefore time.Time, _ int) ([]*domain.Booking, error) {
+			gotPending, gotAwaiting = pendingBefore, awaitingBefore
+			return nil, nil
+		},
+	}
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewHoldExpiryWorker(repo, makePaymentRepo(mockPaymentRepo{}), makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}),
+		service.HoldExpiryConfig{PendingTTL: 10 * time.Minute, AwaitingPaymentTTL: time.Hour},
+		logger, service.WithHoldExpiryClock(func() time.Time { return now }))
+
+	if _, err := worker.ExpireHolds(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !gotPending.Equal(now.Add(-10 * time.Minute)) {
+		t.Errorf("expected pending cutoff %v, got %v", now.Add(-10*time.Minute), gotPending)
+	}
+	if !gotAwaiting.Equal(now.Add(-time.Hour)) {
+		t.Errorf("expected awaiting cutoff %v, got %v", now.Add(-time.Hour), gotAwaiting)
+	}
+}
+
+func TestHoldExpiryWorker_ExpireHolds_ReleasesInventoryAndEmitsEvent(t *testing.T) {
+	var expiredIDs []int
+	repo := &mockHoldRepo{
+		listExpiredHoldsFn: func(_ context.Context, _, _ time.Time, _ int) ([]*domain.Booking, error) {
+			return []*domain.Booking{staleHold(5, domain.BookingStatusPending)}, nil
+		},
+		expireBookingFn: func(_ context.Context, id int) error {
+			expiredIDs = append(expiredIDs, id)
+			return nil
+		},
+	}
+	var restoredQty int
+	restorer := makeMockInventoryRestorer(mockInventoryRestorer{
+		restoreInventoryFn: func(_ context.Context, roomID int, _, _ time.Time, quantity int) error {
+			restoredQty = quantity
+			return nil
+		},
+	})
+	var events []*domain.OutboxEvent
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(_ context.Context, event *domain.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	})
+	var notifType domain.NotificationType
+	notifier := makeMockNotificationSender(mockNotificationSender{
+		notifyFn: func(_ context.Context, _ string, nt domain.NotificationType, _, _ string, _ map[string]any) error {
+			notifType = nt
+			return nil
+		},
+	})
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewHoldExpiryWorker(repo, makePaymentRepo(mockPaymentRepo{}), outboxRepo, restorer,
+		service.HoldExpiryConfig{}, logger, service.WithHoldExpiryNotifier(notifier))
+
+	n, err := worker.ExpireHolds(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n != 1 || len(expiredIDs) != 1 || expiredIDs[0] != 5 {
+		t.Fatalf("expected booking 5 expired, got n=%d ids=%v", n, expiredIDs)
+	}
+	if restoredQty != 2 {
+		t.Errorf("expected 2 units restored, got %d", restoredQty)
+	}
+	if len(events) != 1 || events[0].EventType != domain.EventTypeBookingExpired {
+		t.Fatalf("expected one BookingExpired event, got %+v", events)
+	}
+	var payload domain.BookingExpiredPayload
+	if err := json.Unmarshal(events[0].Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if payload.BookingID != 5 || payload.PreviousStatus != domain.BookingStatusPending {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+	if notifType != domain.NotificationTypeBookingExpired {
+		t.Errorf("expected booking_expired notification, got %q", notifType)
+	}
+}
+
+func TestHoldExpiryWorker_ExpireHolds_TimesOutPendingPayment(t *testing.T) {
+	repo := &mockHoldRepo{
+		listExpiredHoldsFn: func(_ context.Context, _, _ time.Time, _ int) ([]*domain.Booking, error) {
+			return []*domain.Booking{staleHold(6, domain.BookingStatusAwaitingPayment)}, nil
+		},
+	}
+	var gotStatus domain.PaymentStatus
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			return &domain.Payment{ID: "pay-6", BookingID: bookingID, Status: domain.PaymentStatusPending}, nil
+		},
+		updatePaymentStatusFn: func(_ context.Context, _ string, status domain.PaymentStatus, _, _ string) error {
+			gotStatus = status
+			return nil
+		},
+	})
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewHoldExpiryWorker(repo, payRepo, makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}), service.HoldExpiryConfig{}, logger)
+
+	if _, err := worker.ExpireHolds(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotStatus != domain.PaymentStatusTimedOut {
+		t.Errorf("expected payment timed_out, got %q", gotStatus)
+	}
+}
+
+func TestHoldExpiryWorker_ExpireHolds_SkipsBookingThatLeftHold(t *testing.T) {
+	repo := &mockHoldRepo{
+		listExpiredHoldsFn: func(_ context.Context, _, _ time.Time, _ int) ([]*domain.Booking, error) {
+			return []*domain.Booking{staleHold(7, domain.BookingStatusAwaitingPayment)}, nil
+		},
+		expireBookingFn: func(_ context.Context, id int) error {
+			return fmt.Errorf("booking %d no longer holds inventory: %w", id, domain.ErrConflict)
+		},
+	}
+	restored := false
+	restorer := makeMockInventoryRestorer(mockInventoryRestorer{
+		restoreInventoryFn: func(_ context.Context, _ int, _, _ time.Time, _ int) error {
+			restored = true
+			return nil
+		},
+	})
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewHoldExpiryWorker(repo, makePaymentRepo(mockPaymentRepo{}), makeOutboxRepo(mockOutboxRepo{}),
+		restorer, service.HoldExpiryConfig{}, logger)
+
+	n, err := worker.ExpireHolds(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected 0 expired, got %d", n)
+	}
+	if restored {
+		t.Error("inventory must not be restored for a booking that left its hold")
+	}
+}
+
+func TestHoldExpiryWorker_ExpireHolds_WritesExpiryInOneTransaction(t *testing.T) {
+	var outsideTx []string
+	track := func(ctx context.Context, write string) {
+		if !inTx(ctx) {
+			outsideTx = append(outsideTx, write)
+		}
+	}
+	repo := &mockHoldRepo{
+		listExpiredHoldsFn: func(_ context.Context, _, _ time.Time, _ int) ([]*domain.Booking, error) {
+			return []*domain.Booking{staleHold(8, domain.BookingStatusAwaitingPayment)}, nil
+		},
+		expireBookingFn: func(ctx context.Context, _ int) error {
+			track(ctx, "expire booking")
+			return nil
+		},
+	}
+	restorer := makeMockInventoryRestorer(mockInventoryRestorer{
+		restoreInventoryFn: func(ctx context.Context, _ int, _, _ time.Time, _ int) error {
+			track(ctx, "restore inventory")
+			return nil
+		},
+	})
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			return &domain.Payment{ID: "pay-8", BookingID: bookingID, Status: domain.PaymentStatusProcessing}, nil
+		},
+		updatePaymentStatusFn: func(ctx context.Context, _ string, _ domain.PaymentStatus, _, _ string) error {
+			track(ctx, "time out payment")
+			return nil
+		},
+	})
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, _ *domain.OutboxEvent) error {
+			track(ctx, "create event")
+			return errors.New("db down")
+		},
+	})
+	notified := false
+	notifier := makeMockNotificationSender(mockNotificationSender{
+		notifyFn: func(_ context.Context, _ string, _ domain.NotificationType, _, _ string, _ map[string]any) error {
+			notified = true
+			return nil
+		},
+	})
+	txManager := &mockTxManager{}
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewHoldExpiryWorker(repo, payRepo, outboxRepo, restorer, service.HoldExpiryConfig{}, logger,
+		service.WithHoldExpiryNotifier(notifier), service.WithHoldExpiryTxManager(txManager))
+
+	n, err := worker.ExpireHolds(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected 0 expired, got %d", n)
+	}
+	if len(outsideTx) != 0 {
+		t.Errorf("expected every write in the transaction, got %v outside it", outsideTx)
+	}
+	if txManager.rolledBack != 1 || txManager.committed != 0 {
+		t.Errorf("expected the expiry rolled back, got %d commits and %d rollbacks",
+			txManager.committed, txManager.rolledBack)
+	}
+	if notified {
+		t.Error("guest must not be notified of an expiry that rolled back")
+	}
+}
+
+func TestHoldExpiryWorker_ExpireHolds_ListError(t *testing.T) {
+	repo := &mockHoldRepo{
+		listExpiredHoldsFn: func(_ context.Context, _, _ time.Time, _ int) ([]*domain.Booking, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewHoldExpiryWorker(repo, makePaymentRepo(mockPaymentRepo{}), makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}), service.HoldExpiryConfig{}, logger)
+
+	if _, err := worker.ExpireHolds(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestHoldExpiryWorker_Run_StopsOnCancel(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewHoldExpiryWorker(&mockHoldRepo{}, makePaymentRepo(mockPaymentRepo{}), makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}), service.HoldExpiryConfig{Interval: 10 * time.Millisecond}, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := worker.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context cancellation, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// HotelChargeService manages the taxes, service fees and discounts hotels
+// apply to their stays.
+type HotelChargeService struct {
+	chargeRepo repository.HotelChargeRepository
+	hotelRepo  repository.HotelRepository
+}
+
+// NewHotelChargeService creates a new HotelChargeService.
+func NewHotelChargeService(chargeRepo repository.HotelChargeRepository, hotelRepo repository.HotelRepository) *HotelChargeService {
+	return &HotelChargeService{chargeRepo: chargeRepo, hotelRepo: hotelRepo}
+}
+
+// SetCharges replaces every charge of a hotel after verifying the caller owns
+// it. An empty list removes them all.
+func (s *HotelChargeService) SetCharges(ctx context.Context, ownerID string, hotelID int, charges []*domain.HotelCharge) ([]*domain.HotelCharge, error) {
+	for _, c := range charges {
+		c.Name = strings.TrimSpace(c.Name)
+		if err := validateHotelCharge(c); err != nil {
+			return nil, err
+		}
+	}
+
+	hotel, err := s.hotelRepo.GetHotelByID(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	if hotel.OwnerID != ownerID {
+		return nil, fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
+	}
+
+	if err := s.chargeRepo.ReplaceCharges(ctx, hotelID, charges); err != nil {
+		return nil, err
+	}
+	return charges, nil
+}
+
+// ListCharges returns the charges of a hotel.
+func (s *HotelChargeService) ListCharges(ctx context.Context, hotelID int) ([]*domain.HotelCharge, error) {
+	return s.chargeRepo.ListCharges(ctx, hotelID)
+}
+
+func validateHotelCharge(c *domain.HotelCharge) error {
+	if c.Name == "" {
+		return fmt.Errorf("charge name is required: %w", domain.ErrBadRequest)
+	}
+	if !c.Type.Valid() {
+		return fmt.Errorf("charge %q has unknown type %q: %w", c.Name, c.Type, domain.ErrBadRequest)
+	}
+	if (c.Percent == nil) == (c.Amount == nil) {
+		return fmt.Errorf("charge %q must set exactly one of percent and amount: %w", c.Name, domain.ErrBadRequest)
+	}
+	if c.Percent != nil && (*c.Percent <= 0 || *c.Percent > 100) {
+		return fmt.Errorf("charge %q percent must be above 0 and at most 100: %w", c.Name, domain.ErrBadRequest)
+	}
+	if c.Amount != nil && *c.Amount <= 0 {
+		return fmt.Errorf("charge %q amount must be positive: %w", c.Name, domain.ErrBadRequest)
+	}
+	if c.Percent != nil && c.PerNight {
+		return fmt.Errorf("charge %q: per_night only applies to fixed amounts: %w", c.Name, domain.ErrBadRequest)
+	}
+	if c.MinNights < 0 {
+		return fmt.Errorf("charge %q min_nights must be non-negative: %w", c.Name, domain.ErrBadRequest)
+	}
+	return nil
+}
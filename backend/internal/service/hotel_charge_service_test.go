@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"testing"
+)
+
+// --- Mock HotelChargeRepository ---
+
+type mockHotelChargeRepo struct {
+	replaceChargesFn func(ctx context.Context, hotelID int, charges []*domain.HotelCharge) error
+	listChargesFn    func(ctx context.Context, hotelID int) ([]*domain.HotelCharge, error)
+}
+
+func (m *mockHotelChargeRepo) ReplaceCharges(ctx context.Context, hotelID int, charges []*domain.HotelCharge) error {
+	if m.replaceChargesFn != nil {
+		return m.replaceChargesFn(ctx, hotelID, charges)
+	}
+	return nil
+}
+
+func (m *mockHotelChargeRepo) ListCharges(ctx context.Context, hotelID int) ([]*domain.HotelCharge, error) {
+	if m.listChargesFn != nil {
+		return m.listChargesFn(ctx, hotelID)
+	}
+	return []*domain.HotelCharge{}, nil
+}
+
+func chargeTestHotelRepo() *mockHotelRepo {
+	return &mockHotelRepo{
+		getHotelByIDFn: func(ctx context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, OwnerID: "owner-uuid"}, nil
+		},
+	}
+}
+
+// --- Tests: HotelChargeService ---
+
+func TestHotelChargeService_SetCharges_Success(t *testing.T) {
+	var storedHotel int
+	var stored []*domain.HotelCharge
+	repo := &mockHotelChargeRepo{
+		replaceChargesFn: func(_ context.Context, hotelID int, charges []*domain.HotelCharge) error {
+			storedHotel, stored = hotelID, charges
+			return nil
+		},
+	}
+	svc := service.NewHotelChargeService(repo, chargeTestHotelRepo())
+
+	vat, cityTax := 7.0, int64(5000)
+	_, err := svc.SetCharges(context.Background(), "owner-uuid", 10, []*domain.HotelCharge{
+		{Name: " VAT ", Type: domain.ChargeTypeTax, Percent: &vat},
+		{Name: "City tax", Type: domain.ChargeTypeTax, Amount: &cityTax, PerNight: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if storedHotel != 10 || len(stored) != 2 {
+		t.Fatalf("expected 2 charges stored for hotel 10, got %d for hotel %d", len(stored), storedHotel)
+	}
+	if stored[0].Name != "VAT" {
+		t.Errorf("expected trimmed name, got %q", stored[0].Name)
+	}
+}
+
+func TestHotelChargeService_SetCharges_NotOwner(t *testing.T) {
+	repo := &mockHotelChargeRepo{
+		replaceChargesFn: func(_ context.Context, _ int, _ []*domain.HotelCharge) error {
+			t.Fatal("charges should not be stored")
+			return nil
+		},
+	}
+	svc := service.NewHotelChargeService(repo, chargeTestHotelRepo())
+
+	_, err := svc.SetCharges(context.Background(), "someone-else", 10, []*domain.HotelCharge{})
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestHotelChargeService_SetCharges_Invalid(t *testing.T) {
+	pct, amount, over := 10.0, int64(500), 150.0
+	tests := []struct {
+		name   string
+		charge *domain.HotelCharge
+	}{
+		{"missing name", &domain.HotelCharge{Type: domain.ChargeTypeFee, Amount: &amount}},
+		{"unknown type", &domain.HotelCharge{Name: "Resort", Type: "surcharge", Amount: &amount}},
+		{"percent and amount", &domain.HotelCharge{Name: "Service", Type: domain.ChargeTypeFee, Percent: &pct, Amount: &amount}},
+		{"neither percent nor amount", &domain.HotelCharge{Name: "Service", Type: domain.ChargeTypeFee}},
+		{"percent over 100", &domain.HotelCharge{Name: "Promo", Type: domain.ChargeTypeDiscount, Percent: &over}},
+		{"per-night percent", &domain.HotelCharge{Name: "VAT", Type: domain.ChargeTypeTax, Percent: &pct, PerNight: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewHotelChargeService(&mockHotelChargeRepo{}, chargeTestHotelRepo())
+			_, err := svc.SetCharges(context.Background(), "owner-uuid", 10, []*domain.HotelCharge{tt.charge})
+			if !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+		})
+	}
+}
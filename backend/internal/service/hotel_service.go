@@ -20,6 +20,7 @@ type CreateHotelInput struct {
 	Images      []string
 	StarRating  int
 	Description string
+	Currency    string
 }
 
 // UpdateHotelInput holds the data for updating an existing hotel.
@@ -35,6 +36,7 @@ type UpdateHotelInput struct {
 	Images      []string
 	StarRating  int
 	Description string
+	Currency    string
 }
 
 // HotelServiceInterface defines the contract for hotel business logic.
@@ -72,6 +74,10 @@ func (s *HotelService) CreateHotel(ctx context.Context, ownerID string, input Cr
 	if input.StarRating < 0 || input.StarRating > 5 {
 		return nil, fmt.Errorf("star_rating must be between 0 and 5: %w", domain.ErrBadRequest)
 	}
+	currency := domain.NormalizeCurrency(input.Currency)
+	if !domain.ValidCurrency(currency) {
+		return nil, fmt.Errorf("invalid currency %q: %w", input.Currency, domain.ErrBadRequest)
+	}
 
 	hotel := &domain.Hotel{
 		OwnerID:     ownerID,
@@ -87,6 +93,7 @@ func (s *HotelService) CreateHotel(ctx context.Context, ownerID string, input Cr
 		StarRating:  input.StarRating,
 		Status:      domain.HotelStatusPending,
 		Description: input.Description,
+		Currency:    currency,
 	}
 
 	return s.repo.CreateHotel(ctx, hotel)
@@ -115,7 +122,8 @@ func (s *HotelService) ListPendingHotels(ctx context.Context, page, limit int) (
 	return s.repo.ListPendingHotels(ctx, page, limit)
 }
 
-// UpdateHotel updates a hotel only if the caller is the owner.
+// UpdateHotel updates a hotel only if the caller is the owner. An empty
+// currency keeps the hotel's current one.
 func (s *HotelService) UpdateHotel(ctx context.Context, id int, ownerID string, input UpdateHotelInput) (*domain.Hotel, error) {
 	existing, err := s.repo.GetHotelByID(ctx, id)
 	if err != nil {
@@ -126,6 +134,14 @@ func (s *HotelService) UpdateHotel(ctx context.Context, id int, ownerID string,
 		return nil, fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
 	}
 
+	currency := existing.Currency
+	if input.Currency != "" {
+		currency = domain.NormalizeCurrency(input.Currency)
+		if !domain.ValidCurrency(currency) {
+			return nil, fmt.Errorf("invalid currency %q: %w", input.Currency, domain.ErrBadRequest)
+		}
+	}
+
 	updated := &domain.Hotel{
 		ID:          existing.ID,
 		OwnerID:     existing.OwnerID,
@@ -142,6 +158,7 @@ func (s *HotelService) UpdateHotel(ctx context.Context, id int, ownerID string,
 		Images:      input.Images,
 		StarRating:  input.StarRating,
 		Description: input.Description,
+		Currency:    currency,
 	}
 
 	return s.repo.UpdateHotel(ctx, updated)
@@ -5,31 +5,59 @@ import (
 	"booking-app/internal/repository"
 	"context"
 	"fmt"
+	"io"
+	"sort"
 	"time"
 )
 
+// InventoryOption configures an InventoryService.
+type InventoryOption func(*InventoryService)
+
+// WithInventoryReleaseListener passes inventory given back by the payment
+// saga and hold reaper, and inventory set by owners, on to releases.
+func WithInventoryReleaseListener(releases InventoryReleaseListener) InventoryOption {
+	return func(s *InventoryService) { s.releases = releases }
+}
+
+// WithInventoryAvailabilityInvalidator drops cached hotel availability
+// whenever inventory or rates change.
+func WithInventoryAvailabilityInvalidator(availability AvailabilityInvalidator) InventoryOption {
+	return func(s *InventoryService) { s.availability = availability }
+}
+
 // InventoryService handles inventory business logic.
 type InventoryService struct {
 	inventoryRepo repository.InventoryRepository
+	ratePlanRepo  repository.RatePlanRepository
 	roomRepo      repository.RoomRepository
 	hotelRepo     repository.HotelRepository
+	releases      InventoryReleaseListener // optional
+	availability  AvailabilityInvalidator  // optional
 }
 
 // NewInventoryService creates a new InventoryService.
 func NewInventoryService(
 	inventoryRepo repository.InventoryRepository,
+	ratePlanRepo repository.RatePlanRepository,
 	roomRepo repository.RoomRepository,
 	hotelRepo repository.HotelRepository,
+	opts ...InventoryOption,
 ) *InventoryService {
-	return &InventoryService{
+	s := &InventoryService{
 		inventoryRepo: inventoryRepo,
+		ratePlanRepo:  ratePlanRepo,
 		roomRepo:      roomRepo,
 		hotelRepo:     hotelRepo,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // SetInventoryRange sets inventory for a contiguous range of days.
-// Verifies the caller owns the hotel that contains the room.
+// Verifies the caller owns the hotel that contains the room. Units the new
+// totals free up are offered to the waitlist.
 func (s *InventoryService) SetInventoryRange(
 	ctx context.Context,
 	ownerID string,
@@ -45,21 +73,225 @@ func (s *InventoryService) SetInventoryRange(
 		return fmt.Errorf("total must be non-negative: %w", domain.ErrBadRequest)
 	}
 
-	room, err := s.roomRepo.GetRoomByID(ctx, roomID)
-	if err != nil {
+	if _, err := s.ownedRoom(ctx, ownerID, roomID); err != nil {
 		return err
 	}
 
-	hotel, err := s.hotelRepo.GetHotelByID(ctx, room.HotelID)
+	if err := s.inventoryRepo.BulkSetInventory(ctx, roomID, startDate, days, total); err != nil {
+		return err
+	}
+	s.released(ctx, roomID, startDate, startDate.AddDate(0, 0, days))
+	return nil
+}
+
+// SetRatePlan replaces the weekend rate, minimum stay, seasons and per-date
+// overrides of a room after verifying the caller owns its hotel. The base
+// rate stays the room's price_per_night.
+func (s *InventoryService) SetRatePlan(ctx context.Context, ownerID string, roomID int, plan *domain.RatePlan) (*domain.RatePlan, error) {
+	if err := validateRatePlan(plan); err != nil {
+		return nil, err
+	}
+
+	room, err := s.ownedRoom(ctx, ownerID, roomID)
 	if err != nil {
+		return nil, err
+	}
+
+	plan.RoomID = room.ID
+	if plan.MinStay == 0 {
+		plan.MinStay = 1
+	}
+	if err := s.ratePlanRepo.ReplaceRatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, room.ID)
+
+	plan.BasePrice = room.PricePerNight
+	plan.Currency = domain.NormalizeCurrency(room.Currency)
+	return plan, nil
+}
+
+// SetOverbooking replaces the overbooking allowance of a room after verifying
+// the caller owns its hotel. Bookings may then take the room's nights past
+// their inventory by up to the allowance.
+func (s *InventoryService) SetOverbooking(ctx context.Context, ownerID string, roomID int, overbooking domain.Overbooking) (*domain.Room, error) {
+	if err := overbooking.Validate(); err != nil {
+		return nil, err
+	}
+
+	room, err := s.ownedRoom(ctx, ownerID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.roomRepo.SetOverbooking(ctx, room.ID, overbooking); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, room.ID)
+
+	room.Overbooking = overbooking
+	return room, nil
+}
+
+// ListOverbookedNights returns the nights in [startDate, endDate) on which
+// the rooms of a hotel the caller owns are sold past their inventory, so the
+// front desk can plan relocations.
+func (s *InventoryService) ListOverbookedNights(
+	ctx context.Context,
+	ownerID string,
+	hotelID int,
+	startDate time.Time,
+	endDate time.Time,
+) ([]*domain.OverbookedNight, error) {
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("start_date must be before end_date: %w", domain.ErrBadRequest)
+	}
+
+	if err := s.ownedHotel(ctx, ownerID, hotelID); err != nil {
+		return nil, err
+	}
+
+	return s.inventoryRepo.ListOverbookedNights(ctx, hotelID, startDate, endDate)
+}
+
+// ImportInventory sets the inventory of a hotel the caller owns from CSV
+// rows of room_id, date, total and an optional price. Every row is checked
+// first: rows that do not parse, name a room outside the hotel or repeat a
+// night are returned as row errors and nothing is applied. A dry run only
+// checks. Units the new totals free up are offered to the waitlist.
+func (s *InventoryService) ImportInventory(
+	ctx context.Context,
+	ownerID string,
+	hotelID int,
+	src io.Reader,
+	dryRun bool,
+) (*domain.InventoryImport, error) {
+	if err := s.ownedHotel(ctx, ownerID, hotelID); err != nil {
+		return nil, err
+	}
+
+	rows, rowErrors, err := domain.ParseInventoryCSV(src)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms, err := s.roomRepo.ListRoomsByHotel(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	inHotel := make(map[int]bool, len(rooms))
+	for _, room := range rooms {
+		inHotel[room.ID] = true
+	}
+
+	valid := make([]*domain.InventoryRow, 0, len(rows))
+	firstLine := make(map[string]int, len(rows))
+	for _, row := range rows {
+		if !inHotel[row.RoomID] {
+			rowErrors = append(rowErrors, &domain.InventoryRowError{
+				Line: row.Line, Column: "room_id", Message: "room is not an active room of this hotel",
+			})
+			continue
+		}
+		night := fmt.Sprintf("%d/%s", row.RoomID, row.Date.Format("2006-01-02"))
+		if line, seen := firstLine[night]; seen {
+			rowErrors = append(rowErrors, &domain.InventoryRowError{
+				Line: row.Line, Column: "date", Message: fmt.Sprintf("night already set on line %d", line),
+			})
+			continue
+		}
+		firstLine[night] = row.Line
+		valid = append(valid, row)
+	}
+	sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Line < rowErrors[j].Line })
+
+	result := &domain.InventoryImport{DryRun: dryRun, Rows: len(rows), Errors: rowErrors}
+	if result.Errors == nil {
+		result.Errors = []*domain.InventoryRowError{}
+	}
+	if dryRun || len(rowErrors) > 0 || len(valid) == 0 {
+		return result, nil
+	}
+
+	if err := s.inventoryRepo.ImportInventory(ctx, valid); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+
+	// Offer what the import freed up, room by room, over the nights it touched.
+	type span struct{ start, end time.Time }
+	spans := make(map[int]*span)
+	for _, row := range valid {
+		sp, ok := spans[row.RoomID]
+		if !ok {
+			spans[row.RoomID] = &span{start: row.Date, end: row.Date.AddDate(0, 0, 1)}
+			continue
+		}
+		if row.Date.Before(sp.start) {
+			sp.start = row.Date
+		}
+		if next := row.Date.AddDate(0, 0, 1); next.After(sp.end) {
+			sp.end = next
+		}
+	}
+	for roomID, sp := range spans {
+		s.released(ctx, roomID, sp.start, sp.end)
+	}
+	return result, nil
+}
+
+// ExportInventory calls fn with each night of inventory of a hotel the caller
+// owns within [startDate, endDate), ordered by room and date, as it is read.
+func (s *InventoryService) ExportInventory(
+	ctx context.Context,
+	ownerID string,
+	hotelID int,
+	startDate time.Time,
+	endDate time.Time,
+	fn func(*domain.Inventory) error,
+) error {
+	if !startDate.Before(endDate) {
+		return fmt.Errorf("from must be before to: %w", domain.ErrBadRequest)
+	}
+	if endDate.After(startDate.AddDate(0, 0, domain.MaxInventoryExportNights)) {
+		return fmt.Errorf("export covers at most %d nights: %w", domain.MaxInventoryExportNights, domain.ErrBadRequest)
+	}
+
+	if err := s.ownedHotel(ctx, ownerID, hotelID); err != nil {
 		return err
 	}
 
+	return s.inventoryRepo.StreamInventoryForHotel(ctx, hotelID, startDate, endDate, fn)
+}
+
+// ownedHotel verifies the caller owns a hotel.
+func (s *InventoryService) ownedHotel(ctx context.Context, ownerID string, hotelID int) error {
+	hotel, err := s.hotelRepo.GetHotelByID(ctx, hotelID)
+	if err != nil {
+		return err
+	}
 	if hotel.OwnerID != ownerID {
 		return fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
 	}
+	return nil
+}
 
-	return s.inventoryRepo.BulkSetInventory(ctx, roomID, startDate, days, total)
+// ownedRoom fetches a room and verifies the caller owns the hotel containing it.
+func (s *InventoryService) ownedRoom(ctx context.Context, ownerID string, roomID int) (*domain.Room, error) {
+	room, err := s.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	hotel, err := s.hotelRepo.GetHotelByID(ctx, room.HotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hotel.OwnerID != ownerID {
+		return nil, fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
+	}
+	return room, nil
 }
 
 // GetInventoryRange returns inventory records for a room over a date range.
@@ -76,13 +308,90 @@ func (s *InventoryService) GetInventoryRange(
 	return s.inventoryRepo.GetInventoryForRoom(ctx, roomID, startDate, endDate)
 }
 
-// RestoreInventory decrements booked_count by 1 for each day in [startDate, endDate).
+// RestoreInventory decrements booked_count by quantity for each day in [startDate, endDate).
 // Used by the payment saga when a payment fails or times out. This is the correct
-// inverse of CreateBooking's "booked_count = booked_count + 1" operation.
-func (s *InventoryService) RestoreInventory(ctx context.Context, roomID int, startDate, endDate time.Time) error {
+// inverse of CreateBooking's "booked_count = booked_count + quantity" operation.
+// The restored units are offered to the waitlist.
+func (s *InventoryService) RestoreInventory(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
 	days := int(endDate.Sub(startDate).Hours() / 24)
 	if days <= 0 {
 		return fmt.Errorf("invalid date range for inventory restore: %w", domain.ErrBadRequest)
 	}
-	return s.inventoryRepo.BulkDecrementBookedCount(ctx, roomID, startDate, days, 1)
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be positive for inventory restore: %w", domain.ErrBadRequest)
+	}
+	if err := s.inventoryRepo.BulkDecrementBookedCount(ctx, roomID, startDate, days, quantity); err != nil {
+		return err
+	}
+	s.released(ctx, roomID, startDate, endDate)
+	return nil
+}
+
+// released passes released inventory on to the waitlist. Offers reserve the
+// units on a transaction of their own, so inside a unit of work they wait
+// until it has committed the release.
+func (s *InventoryService) released(ctx context.Context, roomID int, startDate, endDate time.Time) {
+	repository.AfterCommit(ctx, func(ctx context.Context) {
+		s.invalidate(ctx, roomID)
+		if s.releases == nil {
+			return
+		}
+		_ = s.releases.InventoryReleased(ctx, roomID, startDate, endDate) // best-effort
+	})
+}
+
+// invalidate drops the cached availability of a room's hotel.
+func (s *InventoryService) invalidate(ctx context.Context, roomID int) {
+	if s.availability == nil {
+		return
+	}
+	_ = s.availability.InvalidateRoom(ctx, roomID) // best-effort
+}
+
+func validateRatePlan(plan *domain.RatePlan) error {
+	if plan.WeekendPrice != nil && *plan.WeekendPrice <= 0 {
+		return fmt.Errorf("weekend_price must be positive: %w", domain.ErrBadRequest)
+	}
+	if plan.MinStay < 0 {
+		return fmt.Errorf("min_stay must be positive: %w", domain.ErrBadRequest)
+	}
+
+	seasons := append([]*domain.SeasonalRate(nil), plan.Seasons...)
+	sort.Slice(seasons, func(i, j int) bool { return seasons[i].StartDate.Before(seasons[j].StartDate) })
+	for i, season := range seasons {
+		if !season.EndDate.After(season.StartDate) {
+			return fmt.Errorf("season %q must end after it starts: %w", season.Name, domain.ErrBadRequest)
+		}
+		if season.Price <= 0 {
+			return fmt.Errorf("season %q price must be positive: %w", season.Name, domain.ErrBadRequest)
+		}
+		if season.WeekendPrice != nil && *season.WeekendPrice <= 0 {
+			return fmt.Errorf("season %q weekend_price must be positive: %w", season.Name, domain.ErrBadRequest)
+		}
+		if season.MinStay != nil && *season.MinStay < 1 {
+			return fmt.Errorf("season %q min_stay must be positive: %w", season.Name, domain.ErrBadRequest)
+		}
+		if i > 0 && season.StartDate.Before(seasons[i-1].EndDate) {
+			return fmt.Errorf("seasons %q and %q overlap: %w", seasons[i-1].Name, season.Name, domain.ErrBadRequest)
+		}
+	}
+
+	seen := make(map[string]bool, len(plan.Overrides))
+	for _, o := range plan.Overrides {
+		day := o.Date.Format("2006-01-02")
+		if seen[day] {
+			return fmt.Errorf("date %s is overridden twice: %w", day, domain.ErrBadRequest)
+		}
+		seen[day] = true
+		if o.Price == nil && o.MinStay == nil {
+			return fmt.Errorf("override for %s sets neither price nor min_stay: %w", day, domain.ErrBadRequest)
+		}
+		if o.Price != nil && *o.Price <= 0 {
+			return fmt.Errorf("price for %s must be positive: %w", day, domain.ErrBadRequest)
+		}
+		if o.MinStay != nil && *o.MinStay < 1 {
+			return fmt.Errorf("min_stay for %s must be positive: %w", day, domain.ErrBadRequest)
+		}
+	}
+	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"booking-app/internal/domain"
 	"booking-app/internal/service"
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -13,8 +15,12 @@ import (
 type mockInventoryRepo struct {
 	setInventoryFn              func(ctx context.Context, roomID int, date time.Time, total int) error
 	getInventoryFn              func(ctx context.Context, roomID int, startDate, endDate time.Time) ([]*domain.Inventory, error)
+	getHotelInventoryFn         func(ctx context.Context, hotelID int, startDate, endDate time.Time) ([]*domain.Inventory, error)
+	listOverbookedNightsFn      func(ctx context.Context, hotelID int, startDate, endDate time.Time) ([]*domain.OverbookedNight, error)
 	bulkSetInventoryFn          func(ctx context.Context, roomID int, startDate time.Time, days, total int) error
 	bulkDecrementBookedCountFn  func(ctx context.Context, roomID int, startDate time.Time, days, amount int) error
+	importInventoryFn           func(ctx context.Context, rows []*domain.InventoryRow) error
+	streamHotelInventoryFn      func(ctx context.Context, hotelID int, startDate, endDate time.Time, fn func(*domain.Inventory) error) error
 }
 
 func (m *mockInventoryRepo) SetInventory(ctx context.Context, roomID int, date time.Time, total int) error {
@@ -25,6 +31,20 @@ func (m *mockInventoryRepo) GetInventoryForRoom(ctx context.Context, roomID int,
 	return m.getInventoryFn(ctx, roomID, startDate, endDate)
 }
 
+func (m *mockInventoryRepo) GetInventoryForHotel(ctx context.Context, hotelID int, startDate, endDate time.Time) ([]*domain.Inventory, error) {
+	if m.getHotelInventoryFn != nil {
+		return m.getHotelInventoryFn(ctx, hotelID, startDate, endDate)
+	}
+	return []*domain.Inventory{}, nil
+}
+
+func (m *mockInventoryRepo) ListOverbookedNights(ctx context.Context, hotelID int, startDate, endDate time.Time) ([]*domain.OverbookedNight, error) {
+	if m.listOverbookedNightsFn != nil {
+		return m.listOverbookedNightsFn(ctx, hotelID, startDate, endDate)
+	}
+	return []*domain.OverbookedNight{}, nil
+}
+
 func (m *mockInventoryRepo) BulkSetInventory(ctx context.Context, roomID int, startDate time.Time, days, total int) error {
 	return m.bulkSetInventoryFn(ctx, roomID, startDate, days, total)
 }
@@ -36,6 +56,41 @@ func (m *mockInventoryRepo) BulkDecrementBookedCount(ctx context.Context, roomID
 	return nil
 }
 
+func (m *mockInventoryRepo) ImportInventory(ctx context.Context, rows []*domain.InventoryRow) error {
+	if m.importInventoryFn != nil {
+		return m.importInventoryFn(ctx, rows)
+	}
+	return nil
+}
+
+func (m *mockInventoryRepo) StreamInventoryForHotel(ctx context.Context, hotelID int, startDate, endDate time.Time, fn func(*domain.Inventory) error) error {
+	if m.streamHotelInventoryFn != nil {
+		return m.streamHotelInventoryFn(ctx, hotelID, startDate, endDate, fn)
+	}
+	return nil
+}
+
+// --- Mock RatePlanRepository ---
+
+type mockRatePlanRepo struct {
+	getRatePlanFn     func(ctx context.Context, roomID int, from, to time.Time) (*domain.RatePlan, error)
+	replaceRatePlanFn func(ctx context.Context, plan *domain.RatePlan) error
+}
+
+func (m *mockRatePlanRepo) GetRatePlan(ctx context.Context, roomID int, from, to time.Time) (*domain.RatePlan, error) {
+	if m.getRatePlanFn != nil {
+		return m.getRatePlanFn(ctx, roomID, from, to)
+	}
+	return &domain.RatePlan{RoomID: roomID}, nil
+}
+
+func (m *mockRatePlanRepo) ReplaceRatePlan(ctx context.Context, plan *domain.RatePlan) error {
+	if m.replaceRatePlanFn != nil {
+		return m.replaceRatePlanFn(ctx, plan)
+	}
+	return nil
+}
+
 // --- Tests: SetInventoryRange ---
 
 func TestInventoryService_SetInventoryRange_Success(t *testing.T) {
@@ -65,7 +120,7 @@ func TestInventoryService_SetInventoryRange_Success(t *testing.T) {
 			return &domain.Hotel{ID: 10, OwnerID: "owner-uuid"}, nil
 		},
 	}
-	svc := service.NewInventoryService(inventoryRepo, roomRepo, hotelRepo)
+	svc := service.NewInventoryService(inventoryRepo, &mockRatePlanRepo{}, roomRepo, hotelRepo)
 
 	err := svc.SetInventoryRange(context.Background(), "owner-uuid", 1, time.Now(), 7, 5)
 
@@ -88,7 +143,7 @@ func TestInventoryService_SetInventoryRange_RejectsNonOwner(t *testing.T) {
 			return &domain.Hotel{ID: 10, OwnerID: "real-owner"}, nil
 		},
 	}
-	svc := service.NewInventoryService(&mockInventoryRepo{}, roomRepo, hotelRepo)
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, roomRepo, hotelRepo)
 
 	err := svc.SetInventoryRange(context.Background(), "attacker", 1, time.Now(), 7, 5)
 
@@ -98,7 +153,7 @@ func TestInventoryService_SetInventoryRange_RejectsNonOwner(t *testing.T) {
 }
 
 func TestInventoryService_SetInventoryRange_InvalidDays(t *testing.T) {
-	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
 
 	err := svc.SetInventoryRange(context.Background(), "owner", 1, time.Now(), 0, 5)
 
@@ -108,7 +163,7 @@ func TestInventoryService_SetInventoryRange_InvalidDays(t *testing.T) {
 }
 
 func TestInventoryService_SetInventoryRange_InvalidTotal(t *testing.T) {
-	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
 
 	err := svc.SetInventoryRange(context.Background(), "owner", 1, time.Now(), 7, -1)
 
@@ -131,7 +186,7 @@ func TestInventoryService_GetInventoryRange_ReturnsInventory(t *testing.T) {
 			return inventoryData, nil
 		},
 	}
-	svc := service.NewInventoryService(inventoryRepo, &mockRoomRepo{}, &mockHotelRepo{})
+	svc := service.NewInventoryService(inventoryRepo, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
 
 	result, err := svc.GetInventoryRange(context.Background(), 1, start, end)
 
@@ -144,7 +199,7 @@ func TestInventoryService_GetInventoryRange_ReturnsInventory(t *testing.T) {
 }
 
 func TestInventoryService_GetInventoryRange_InvalidDateRange(t *testing.T) {
-	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
 
 	end := time.Now()
 	start := end.AddDate(0, 0, 7)
@@ -186,9 +241,9 @@ func TestInventoryService_RestoreInventory_DecrementsBookedCount(t *testing.T) {
 			return nil
 		},
 	}
-	svc := service.NewInventoryService(inventoryRepo, &mockRoomRepo{}, &mockHotelRepo{})
+	svc := service.NewInventoryService(inventoryRepo, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
 
-	err := svc.RestoreInventory(context.Background(), 42, start, end)
+	err := svc.RestoreInventory(context.Background(), 42, start, end, 1)
 
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -211,14 +266,352 @@ func TestInventoryService_RestoreInventory_DecrementsBookedCount(t *testing.T) {
 }
 
 func TestInventoryService_RestoreInventory_InvalidDateRange(t *testing.T) {
-	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
 
 	start := time.Now()
 	end := start // same day → 0 days
 
-	err := svc.RestoreInventory(context.Background(), 1, start, end)
+	err := svc.RestoreInventory(context.Background(), 1, start, end, 1)
 
 	if err == nil {
 		t.Error("expected error for zero-day date range")
 	}
 }
+
+func TestInventoryService_RestoreInventory_DecrementsByQuantity(t *testing.T) {
+	start := time.Now().Truncate(24 * time.Hour)
+	end := start.AddDate(0, 0, 2)
+
+	var gotAmount int
+	inventoryRepo := &mockInventoryRepo{
+		bulkDecrementBookedCountFn: func(ctx context.Context, roomID int, startDate time.Time, days, amount int) error {
+			gotAmount = amount
+			return nil
+		},
+	}
+	svc := service.NewInventoryService(inventoryRepo, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
+
+	if err := svc.RestoreInventory(context.Background(), 1, start, end, 3); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotAmount != 3 {
+		t.Errorf("expected decrement amount 3 (booking quantity), got %d", gotAmount)
+	}
+}
+
+func TestInventoryService_RestoreInventory_InvalidQuantity(t *testing.T) {
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{})
+
+	start := time.Now()
+	err := svc.RestoreInventory(context.Background(), 1, start, start.AddDate(0, 0, 1), 0)
+
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for zero quantity, got %v", err)
+	}
+}
+
+// --- Tests: SetRatePlan ---
+
+func rateTestRepos() (*mockRoomRepo, *mockHotelRepo) {
+	roomRepo := &mockRoomRepo{
+		getRoomByIDFn: func(ctx context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 10, PricePerNight: 15000, Currency: "USD", IsActive: true}, nil
+		},
+	}
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(ctx context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: 10, OwnerID: "owner-uuid"}, nil
+		},
+	}
+	return roomRepo, hotelRepo
+}
+
+func TestInventoryService_SetRatePlan_Success(t *testing.T) {
+	var stored *domain.RatePlan
+	ratePlanRepo := &mockRatePlanRepo{
+		replaceRatePlanFn: func(ctx context.Context, plan *domain.RatePlan) error {
+			stored = plan
+			return nil
+		},
+	}
+	roomRepo, hotelRepo := rateTestRepos()
+	svc := service.NewInventoryService(&mockInventoryRepo{}, ratePlanRepo, roomRepo, hotelRepo)
+
+	weekend := int64(18000)
+	plan, err := svc.SetRatePlan(context.Background(), "owner-uuid", 5, &domain.RatePlan{
+		WeekendPrice: &weekend,
+		Seasons: []*domain.SeasonalRate{
+			{Name: "High", StartDate: time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC), Price: 25000},
+			{Name: "Spring", StartDate: time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC), Price: 17000},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored == nil || stored.RoomID != 5 || stored.MinStay != 1 {
+		t.Errorf("expected plan for room 5 with a one-night minimum to be stored, got %+v", stored)
+	}
+	if plan.BasePrice != 15000 || plan.Currency != "USD" {
+		t.Errorf("expected base price 15000 USD from the room, got %d %s", plan.BasePrice, plan.Currency)
+	}
+}
+
+func TestInventoryService_SetRatePlan_WrongOwner(t *testing.T) {
+	roomRepo, hotelRepo := rateTestRepos()
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, roomRepo, hotelRepo)
+
+	_, err := svc.SetRatePlan(context.Background(), "someone-else", 5, &domain.RatePlan{})
+
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestInventoryService_SetRatePlan_RejectsInvalidPlans(t *testing.T) {
+	zero := int64(0)
+	price := int64(20000)
+	tests := []struct {
+		name string
+		plan *domain.RatePlan
+	}{
+		{"non-positive weekend price", &domain.RatePlan{WeekendPrice: &zero}},
+		{"season ending before it starts", &domain.RatePlan{Seasons: []*domain.SeasonalRate{
+			{Name: "Backwards", StartDate: time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), Price: 20000},
+		}}},
+		{"overlapping seasons", &domain.RatePlan{Seasons: []*domain.SeasonalRate{
+			{Name: "Summer", StartDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Price: 20000},
+			{Name: "August", StartDate: time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), Price: 25000},
+		}}},
+		{"override setting nothing", &domain.RatePlan{Overrides: []*domain.DateRate{{Date: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)}}}},
+		{"date overridden twice", &domain.RatePlan{Overrides: []*domain.DateRate{
+			{Date: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), Price: &price},
+			{Date: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), Price: &price},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			ratePlanRepo := &mockRatePlanRepo{
+				replaceRatePlanFn: func(ctx context.Context, plan *domain.RatePlan) error {
+					called = true
+					return nil
+				},
+			}
+			roomRepo, hotelRepo := rateTestRepos()
+			svc := service.NewInventoryService(&mockInventoryRepo{}, ratePlanRepo, roomRepo, hotelRepo)
+
+			_, err := svc.SetRatePlan(context.Background(), "owner-uuid", 5, tt.plan)
+
+			if !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+			if called {
+				t.Error("expected nothing to be stored")
+			}
+		})
+	}
+}
+
+func TestInventoryService_RestoreInventory_OffersStayToWaitlist(t *testing.T) {
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	releases := &mockReleaseListener{}
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, &mockRoomRepo{}, &mockHotelRepo{},
+		service.WithInventoryReleaseListener(releases))
+
+	if err := svc.RestoreInventory(context.Background(), 42, start, start.AddDate(0, 0, 3), 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(releases.released) != 1 || releases.released[0].roomID != 42 || !releases.released[0].end.Equal(start.AddDate(0, 0, 3)) {
+		t.Errorf("expected room 42's restored nights offered to the waitlist, got %+v", releases.released)
+	}
+}
+
+func TestInventoryService_InventoryAndRateChangesInvalidateAvailability(t *testing.T) {
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	roomRepo := &mockRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 1}, nil
+		},
+	}
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(_ context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, OwnerID: "owner-1"}, nil
+		},
+	}
+	inventoryRepo := &mockInventoryRepo{
+		bulkSetInventoryFn: func(context.Context, int, time.Time, int, int) error { return nil },
+	}
+	availability := &mockAvailabilityInvalidator{}
+	svc := service.NewInventoryService(inventoryRepo, &mockRatePlanRepo{}, roomRepo, hotelRepo,
+		service.WithInventoryAvailabilityInvalidator(availability))
+
+	if err := svc.SetInventoryRange(context.Background(), "owner-1", 42, start, 7, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.RestoreInventory(context.Background(), 43, start, start.AddDate(0, 0, 2), 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.SetRatePlan(context.Background(), "owner-1", 44, &domain.RatePlan{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(availability.rooms) != 3 || availability.rooms[0] != 42 || availability.rooms[1] != 43 || availability.rooms[2] != 44 {
+		t.Errorf("expected rooms 42, 43 and 44 invalidated, got %v", availability.rooms)
+	}
+}
+
+func TestInventoryService_SetOverbooking_SavesAllowance(t *testing.T) {
+	var saved domain.Overbooking
+	roomRepo := &mockRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 1}, nil
+		},
+		setOverbookingFn: func(_ context.Context, _ int, overbooking domain.Overbooking) error {
+			saved = overbooking
+			return nil
+		},
+	}
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(_ context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, OwnerID: "owner-1"}, nil
+		},
+	}
+	availability := &mockAvailabilityInvalidator{}
+	svc := service.NewInventoryService(&mockInventoryRepo{}, &mockRatePlanRepo{}, roomRepo, hotelRepo,
+		service.WithInventoryAvailabilityInvalidator(availability))
+
+	allowance := domain.Overbooking{Type: domain.OverbookingPercent, Value: 10}
+	room, err := svc.SetOverbooking(context.Background(), "owner-1", 42, allowance)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved != allowance || room.Overbooking != allowance {
+		t.Errorf("expected %+v saved and returned, got %+v and %+v", allowance, saved, room.Overbooking)
+	}
+	if len(availability.rooms) != 1 || availability.rooms[0] != 42 {
+		t.Errorf("expected room 42's availability invalidated, got %v", availability.rooms)
+	}
+
+	_, err = svc.SetOverbooking(context.Background(), "owner-1", 42, domain.Overbooking{Type: domain.OverbookingPercent, Value: 150})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for 150 percent, got %v", err)
+	}
+}
+
+func TestInventoryService_ListOverbookedNights_NotOwner(t *testing.T) {
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(_ context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, OwnerID: "owner-1"}, nil
+		},
+	}
+	inventoryRepo := &mockInventoryRepo{
+		listOverbookedNightsFn: func(context.Context, int, time.Time, time.Time) ([]*domain.OverbookedNight, error) {
+			t.Fatal("overbooked nights must not be listed for another owner")
+			return nil, nil
+		},
+	}
+	svc := service.NewInventoryService(inventoryRepo, &mockRatePlanRepo{}, &mockRoomRepo{}, hotelRepo)
+
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	_, err := svc.ListOverbookedNights(context.Background(), "owner-2", 1, start, start.AddDate(0, 1, 0))
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+// importFixture wires an InventoryService over hotel 1, owned by owner-1,
+// with the active room 5, recording the rows it imports.
+func importFixture(releases *mockReleaseListener) (*service.InventoryService, *[]*domain.InventoryRow) {
+	var imported []*domain.InventoryRow
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(_ context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, OwnerID: "owner-1"}, nil
+		},
+	}
+	roomRepo := &mockRoomRepo{
+		listRoomsByHotelFn: func(context.Context, int) ([]*domain.Room, error) {
+			return []*domain.Room{{ID: 5, HotelID: 1}}, nil
+		},
+	}
+	inventoryRepo := &mockInventoryRepo{
+		importInventoryFn: func(_ context.Context, rows []*domain.InventoryRow) error {
+			imported = rows
+			return nil
+		},
+	}
+	svc := service.NewInventoryService(inventoryRepo, &mockRatePlanRepo{}, roomRepo, hotelRepo,
+		service.WithInventoryReleaseListener(releases))
+	return svc, &imported
+}
+
+func TestInventoryService_ImportInventory_AppliesAndOffersReleasedNights(t *testing.T) {
+	releases := &mockReleaseListener{}
+	svc, imported := importFixture(releases)
+
+	csv := "room_id,date,total,price\n5,2026-07-03,4,\n5,2026-07-01,4,12000\n"
+	result, err := svc.ImportInventory(context.Background(), "owner-1", 1, strings.NewReader(csv), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Applied || result.Rows != 2 || len(result.Errors) != 0 {
+		t.Fatalf("expected both rows applied, got %+v", result)
+	}
+	if len(*imported) != 2 {
+		t.Errorf("expected 2 rows imported, got %d", len(*imported))
+	}
+	if len(releases.released) != 1 {
+		t.Fatalf("expected one release for room 5, got %d", len(releases.released))
+	}
+	got := releases.released[0]
+	if got.roomID != 5 || !got.start.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) || !got.end.Equal(time.Date(2026, 7, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected nights 07-01..07-04 of room 5 released, got %+v", got)
+	}
+}
+
+func TestInventoryService_ImportInventory_RowErrorsApplyNothing(t *testing.T) {
+	releases := &mockReleaseListener{}
+	svc, imported := importFixture(releases)
+
+	csv := "room_id,date,total\n5,2026-07-01,4\n9,2026-07-01,4\n5,2026-07-01,2\n5,2026-07-02,x\n"
+	result, err := svc.ImportInventory(context.Background(), "owner-1", 1, strings.NewReader(csv), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Applied || *imported != nil || len(releases.released) != 0 {
+		t.Errorf("expected nothing applied, got %+v", result)
+	}
+
+	wantLines := []int{3, 4, 5}
+	if len(result.Errors) != len(wantLines) {
+		t.Fatalf("expected %d row errors, got %+v", len(wantLines), result.Errors)
+	}
+	for i, line := range wantLines {
+		if result.Errors[i].Line != line {
+			t.Errorf("row error %d: expected line %d, got %+v", i, line, result.Errors[i])
+		}
+	}
+}
+
+func TestInventoryService_ImportInventory_DryRunAppliesNothing(t *testing.T) {
+	svc, imported := importFixture(&mockReleaseListener{})
+
+	result, err := svc.ImportInventory(context.Background(), "owner-1", 1, strings.NewReader("room_id,date,total\n5,2026-07-01,4\n"), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.DryRun || result.Applied || result.Rows != 1 || *imported != nil {
+		t.Errorf("expected a checked but unapplied dry run, got %+v", result)
+	}
+}
+
+func TestInventoryService_ExportInventory_RejectsLongRanges(t *testing.T) {
+	svc, _ := importFixture(&mockReleaseListener{})
+	from := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	err := svc.ExportInventory(context.Background(), "owner-1", 1, from, from.AddDate(0, 0, domain.MaxInventoryExportNights+1),
+		func(*domain.Inventory) error { return nil })
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
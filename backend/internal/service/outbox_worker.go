@@ -1,107 +1,317 @@
 package service
 
 import (
+	"booking-app/internal/domain"
+	"booking-app/internal/observability"
 	"booking-app/internal/repository"
 	"context"
 	"fmt"
+	"math/rand/v2"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
 )
 
 const (
-	outboxMaxRetries = 5
-	outboxBatchSize  = 50
-	outboxPollDelay  = 2 * time.Second
+	outboxBatchSize = 50
+	outboxPollDelay = 2 * time.Second
+	// outboxFallbackPollDelay is how often a relay woken by notifications
+	// still polls, to pick up events whose notification it missed.
+	outboxFallbackPollDelay = 30 * time.Second
+	// outboxLease is how long a claimed batch stays reserved for its relay;
+	// a relay that dies mid-batch frees its events once the lease runs out.
+	outboxLease = 30 * time.Second
+
+	defaultOutboxMaxAttempts    = 5
+	defaultOutboxRetryBaseDelay = 1 * time.Minute
+	defaultOutboxRetryMaxDelay  = 30 * time.Minute
 )
 
+// OutboxRetryPolicy decides how many times, and how far apart, the relay
+// offers an event to the broker before dead-lettering it. Zero fields take the
+// package defaults.
+type OutboxRetryPolicy struct {
+	// MaxAttempts applies to event types without an entry in
+	// MaxAttemptsByType.
+	MaxAttempts       int
+	MaxAttemptsByType map[string]int
+	// BaseDelay is the wait after the first failed attempt. It doubles with
+	// every further failure up to MaxDelay, plus up to half again of jitter
+	// so events failed together are not retried together.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func (p OutboxRetryPolicy) withDefaults() OutboxRetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultOutboxMaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultOutboxRetryBaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultOutboxRetryMaxDelay
+	}
+	p.MaxDelay = max(p.MaxDelay, p.BaseDelay)
+	return p
+}
+
+// maxAttempts returns how many attempts events of eventType get.
+func (p OutboxRetryPolicy) maxAttempts(eventType string) int {
+	if n := p.MaxAttemptsByType[eventType]; n > 0 {
+		return n
+	}
+	return p.MaxAttempts
+}
+
+// retryDelay returns how long to wait after an event's failures-th failed
+// attempt.
+func (p OutboxRetryPolicy) retryDelay(failures int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxDelay)
+	return d + rand.N(d/2+1)
+}
+
 // MessagePublisher abstracts the RabbitMQ publisher used by the outbox worker.
+// Events are published with their ID as the message ID.
 type MessagePublisher interface {
-	Publish(ctx context.Context, exchange, routingKey string, body []byte) error
+	Publish(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error
 }
 
-// OutboxWorker polls the outbox table and publishes unpublished events to the message broker.
+// OutboxWorkerOption configures an OutboxWorker.
+type OutboxWorkerOption func(*OutboxWorker)
+
+// WithOutboxWakeups makes the worker relay as soon as a value arrives on
+// wakeups, falling back to polling every outboxFallbackPollDelay.
+func WithOutboxWakeups(wakeups <-chan struct{}) OutboxWorkerOption {
+	return func(w *OutboxWorker) {
+		w.wakeups = wakeups
+		w.pollDelay = outboxFallbackPollDelay
+	}
+}
+
+// WithOutboxRetryPolicy sets how failed events are retried and when they are
+// dead-lettered.
+func WithOutboxRetryPolicy(p OutboxRetryPolicy) OutboxWorkerOption {
+	return func(w *OutboxWorker) { w.retry = p.withDefaults() }
+}
+
+// WithOutboxClock overrides the time source (used in tests).
+func WithOutboxClock(now func() time.Time) OutboxWorkerOption {
+	return func(w *OutboxWorker) { w.now = now }
+}
+
+// WithOutboxRelayID sets the name the worker claims events under. It
+// defaults to the host name and process ID.
+func WithOutboxRelayID(id string) OutboxWorkerOption {
+	return func(w *OutboxWorker) { w.relayID = id }
+}
+
+// OutboxWorker relays outbox events to the message broker. Any number of
+// workers can run against one outbox: each claims its batch under a lease, and
+// events of one aggregate are published in the order they were written.
 type OutboxWorker struct {
 	outboxRepo repository.OutboxRepository
 	publisher  MessagePublisher
 	logger     *zap.Logger
+	relayID    string
+	wakeups    <-chan struct{}
+	pollDelay  time.Duration
+	retry      OutboxRetryPolicy
+	now        func() time.Time
 }
 
 // NewOutboxWorker creates a new OutboxWorker.
-func NewOutboxWorker(outboxRepo repository.OutboxRepository, publisher MessagePublisher, logger *zap.Logger) *OutboxWorker {
-	return &OutboxWorker{
+func NewOutboxWorker(outboxRepo repository.OutboxRepository, publisher MessagePublisher, logger *zap.Logger, opts ...OutboxWorkerOption) *OutboxWorker {
+	w := &OutboxWorker{
 		outboxRepo: outboxRepo,
 		publisher:  publisher,
 		logger:     logger,
+		relayID:    defaultRelayID(),
+		pollDelay:  outboxPollDelay,
+		retry:      OutboxRetryPolicy{}.withDefaults(),
+		now:        time.Now,
 	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
 }
 
-// Run starts the polling loop. It blocks until ctx is cancelled.
-// The first batch is processed immediately, then every outboxPollDelay.
+// Run starts the relay loop. It blocks until ctx is cancelled.
+// Events are relayed immediately, then on every wake-up and poll tick.
 func (w *OutboxWorker) Run(ctx context.Context) error {
-	w.logger.Info("outbox worker started")
+	w.logger.Info("outbox worker started",
+		zap.String("relay_id", w.relayID),
+		zap.Bool("notifications", w.wakeups != nil),
+		zap.Duration("poll_interval", w.pollDelay),
+	)
 
-	// Process once immediately before entering the tick loop.
-	if err := w.processEvents(ctx); err != nil {
-		w.logger.Error("outbox worker iteration error", zap.Error(err))
-	}
+	w.drain(ctx)
 
-	ticker := time.NewTicker(outboxPollDelay)
+	ticker := time.NewTicker(w.pollDelay)
 	defer ticker.Stop()
 
+	wakeups := w.wakeups
 	for {
 		select {
 		case <-ctx.Done():
 			w.logger.Info("outbox worker stopped")
 			return ctx.Err()
-		case <-ticker.C:
-			if err := w.processEvents(ctx); err != nil {
-				w.logger.Error("outbox worker iteration error", zap.Error(err))
+		case _, ok := <-wakeups:
+			if !ok {
+				w.logger.Warn("outbox notifications closed, polling only")
+				wakeups = nil
+				ticker.Reset(outboxPollDelay)
+				continue
 			}
+			w.drain(ctx)
+		case <-ticker.C:
+			w.drain(ctx)
 		}
 	}
 }
 
-func (w *OutboxWorker) processEvents(ctx context.Context) error {
-	events, err := w.outboxRepo.ListUnpublishedEvents(ctx, outboxBatchSize)
+// drain relays batches until one comes back short of outboxBatchSize.
+func (w *OutboxWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := w.processEvents(ctx)
+		if err != nil {
+			w.logger.Error("outbox worker iteration error", zap.Error(err))
+			return
+		}
+		if n < outboxBatchSize {
+			return
+		}
+	}
+}
+
+// processEvents claims and publishes one batch, returning how many events it
+// claimed. Once an event fails, later events of its aggregate are handed back
+// unpublished so they cannot overtake it.
+func (w *OutboxWorker) processEvents(ctx context.Context) (int, error) {
+	events, err := w.outboxRepo.ClaimBatch(ctx, w.relayID, outboxBatchSize, outboxLease)
 	if err != nil {
-		return fmt.Errorf("list unpublished events: %w", err)
+		return 0, fmt.Errorf("claim outbox events: %w", err)
 	}
+	observability.OutboxRelayBatchSize.Observe(float64(len(events)))
 
+	blocked := map[string]bool{}
+	var deferred []string
 	for _, event := range events {
-		if event.RetryCount >= outboxMaxRetries {
-			// Send to DLQ by publishing to dead letter exchange.
-			if dlqErr := w.publisher.Publish(ctx, "booking.events.dlx", "dead."+event.EventType, event.Payload); dlqErr != nil {
-				w.logger.Error("failed to send event to DLQ",
-					zap.String("event_id", event.ID),
-					zap.Error(dlqErr),
-				)
-			} else {
-				// Mark as published to stop retrying.
-				_ = w.outboxRepo.MarkPublished(ctx, event.ID, time.Now())
-			}
+		aggregate := event.AggregateType + "/" + event.AggregateID
+		if blocked[aggregate] {
+			deferred = append(deferred, event.ID)
 			continue
 		}
+		if !w.relay(ctx, event) {
+			blocked[aggregate] = true
+		}
+	}
 
-		routingKey := eventTypeToRoutingKey(event.EventType)
-		if err := w.publisher.Publish(ctx, "booking.events", routingKey, event.Payload); err != nil {
-			w.logger.Warn("failed to publish outbox event",
-				zap.String("event_id", event.ID),
-				zap.String("event_type", event.EventType),
+	if len(deferred) > 0 {
+		observability.OutboxEventsRelayedTotal.WithLabelValues("deferred").Add(float64(len(deferred)))
+		if err := w.outboxRepo.ReleaseEvents(ctx, w.relayID, deferred); err != nil {
+			w.logger.Error("failed to release deferred outbox events",
+				zap.Strings("event_ids", deferred),
 				zap.Error(err),
 			)
-			_ = w.outboxRepo.IncrementRetry(ctx, event.ID)
-			continue
 		}
+	}
+	return len(events), nil
+}
 
-		if err := w.outboxRepo.MarkPublished(ctx, event.ID, time.Now()); err != nil {
-			w.logger.Error("failed to mark event published",
-				zap.String("event_id", event.ID),
-				zap.Error(err),
-			)
+// relay publishes a single event, or dead-letters it once it has used up its
+// attempts. It reports whether the event left the outbox.
+func (w *OutboxWorker) relay(ctx context.Context, event *domain.OutboxEvent) bool {
+	maxAttempts := w.retry.maxAttempts(event.EventType)
+	if event.RetryCount >= maxAttempts {
+		// Out of attempts already: a previous dead-lettering failed, or the
+		// limit was lowered since.
+		return w.deadLetter(ctx, event, "")
+	}
+
+	routingKey := eventTypeToRoutingKey(event.EventType)
+	if err := w.publisher.Publish(ctx, "booking.events", routingKey, event); err != nil {
+		w.logger.Warn("failed to publish outbox event",
+			zap.String("event_id", event.ID),
+			zap.String("event_type", event.EventType),
+			zap.Int("attempt", event.RetryCount+1),
+			zap.Error(err),
+		)
+		observability.OutboxEventsRelayedTotal.WithLabelValues("failed").Inc()
+		event.RetryCount++
+		if event.RetryCount >= maxAttempts {
+			return w.deadLetter(ctx, event, err.Error())
 		}
+		w.scheduleRetry(ctx, event, err.Error())
+		return false
+	}
+
+	publishedAt := w.now()
+	observability.OutboxEventsRelayedTotal.WithLabelValues("published").Inc()
+	observability.OutboxRelayLag.Observe(publishedAt.Sub(event.CreatedAt).Seconds())
+	if err := w.outboxRepo.MarkPublished(ctx, event.ID, publishedAt); err != nil {
+		// The lease runs out and the event is published again; consumers
+		// drop the duplicate by its message ID.
+		w.logger.Error("failed to mark event published",
+			zap.String("event_id", event.ID),
+			zap.Error(err),
+		)
+	}
+	return true
+}
+
+// deadLetter sends an event that has used up its attempts to the dead-letter
+// exchange and records it as dead-lettered, not published. When the
+// dead-letter exchange refuses it too, the event is retried later.
+func (w *OutboxWorker) deadLetter(ctx context.Context, event *domain.OutboxEvent, lastErr string) bool {
+	if err := w.publisher.Publish(ctx, "booking.events.dlx", "dead."+event.EventType, event); err != nil {
+		w.logger.Error("failed to send event to DLQ",
+			zap.String("event_id", event.ID),
+			zap.Error(err),
+		)
+		w.scheduleRetry(ctx, event, "dead-letter: "+err.Error())
+		return false
+	}
+
+	observability.OutboxEventsRelayedTotal.WithLabelValues("dead_lettered").Inc()
+	w.logger.Warn("outbox event dead-lettered",
+		zap.String("event_id", event.ID),
+		zap.String("event_type", event.EventType),
+		zap.Int("attempts", event.RetryCount),
+	)
+	if err := w.outboxRepo.MarkDeadLettered(ctx, event.ID, event.RetryCount, lastErr, w.now()); err != nil {
+		w.logger.Error("failed to mark event dead-lettered",
+			zap.String("event_id", event.ID),
+			zap.Error(err),
+		)
+	}
+	return true
+}
+
+// scheduleRetry records a failed attempt and backs the event off.
+func (w *OutboxWorker) scheduleRetry(ctx context.Context, event *domain.OutboxEvent, lastErr string) {
+	next := w.now().Add(w.retry.retryDelay(max(event.RetryCount, 1)))
+	if err := w.outboxRepo.ScheduleRetry(ctx, event.ID, lastErr, next); err != nil {
+		w.logger.Error("failed to schedule outbox event retry",
+			zap.String("event_id", event.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// defaultRelayID names a relay after its host and process.
+func defaultRelayID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
 	}
-	return nil
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
 }
 
 // eventTypeToRoutingKey converts a domain event type to a RabbitMQ routing key.
@@ -115,6 +325,19 @@ func eventTypeToRoutingKey(eventType string) string {
 		return "payment.failed"
 	case "PaymentTimedOut":
 		return "payment.timed_out"
+	case "BookingExpired":
+		return "booking.expired"
+	// Refund events share the payment.# binding of the payments queue.
+	case "RefundRequested":
+		return "payment.refund.requested"
+	case "RefundSucceeded":
+		return "payment.refund.succeeded"
+	case "RefundFailed":
+		return "payment.refund.failed"
+	case "PaymentVoidRequested":
+		return "payment.void.requested"
+	case "PaymentCaptureFailed":
+		return "payment.capture_failed"
 	default:
 		return "payment.unknown"
 	}
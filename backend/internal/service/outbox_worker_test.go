@@ -15,12 +15,12 @@ import (
 // --- Mock MessagePublisher ---
 
 type mockPublisher struct {
-	publishFn func(ctx context.Context, exchange, routingKey string, body []byte) error
+	publishFn func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error
 }
 
-func (m *mockPublisher) Publish(ctx context.Context, exchange, routingKey string, body []byte) error {
+func (m *mockPublisher) Publish(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
 	if m.publishFn != nil {
-		return m.publishFn(ctx, exchange, routingKey, body)
+		return m.publishFn(ctx, exchange, routingKey, event)
 	}
 	return nil
 }
@@ -29,7 +29,7 @@ func (m *mockPublisher) Publish(ctx context.Context, exchange, routingKey string
 
 func TestOutboxWorker_ProcessEvents_EmptyBatch(t *testing.T) {
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{
-		listUnpublishedFn: func(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
 			return []*domain.OutboxEvent{}, nil
 		},
 	})
@@ -64,7 +64,7 @@ func TestOutboxWorker_ProcessEvents_PublishesEvent(t *testing.T) {
 	markPublishedCalled := false
 
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{
-		listUnpublishedFn: func(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
 			// Return events once, then empty.
 			if !publishCalled {
 				return events, nil
@@ -79,11 +79,14 @@ func TestOutboxWorker_ProcessEvents_PublishesEvent(t *testing.T) {
 		},
 	})
 	publisher := &mockPublisher{
-		publishFn: func(ctx context.Context, exchange, routingKey string, body []byte) error {
+		publishFn: func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
 			publishCalled = true
 			if exchange != "booking.events" {
 				return errors.New("unexpected exchange: " + exchange)
 			}
+			if event.ID != "evt-1" {
+				return errors.New("expected the outbox event itself to be published, got " + event.ID)
+			}
 			return nil
 		},
 	}
@@ -117,16 +120,23 @@ func TestOutboxWorker_ProcessEvents_DLQAfterMaxRetries(t *testing.T) {
 	}
 
 	dlqPublished := false
+	deadLettered := false
+	markedPublished := false
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{
-		listUnpublishedFn: func(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
 			return events, nil
 		},
 		markPublishedFn: func(ctx context.Context, id string, publishedAt time.Time) error {
+			markedPublished = true
+			return nil
+		},
+		markDeadLetteredFn: func(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error {
+			deadLettered = id == "evt-dlq"
 			return nil
 		},
 	})
 	publisher := &mockPublisher{
-		publishFn: func(ctx context.Context, exchange, routingKey string, body []byte) error {
+		publishFn: func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
 			if exchange == "booking.events.dlx" {
 				dlqPublished = true
 			}
@@ -143,9 +153,15 @@ func TestOutboxWorker_ProcessEvents_DLQAfterMaxRetries(t *testing.T) {
 	if !dlqPublished {
 		t.Error("expected event to be published to DLQ after max retries")
 	}
+	if !deadLettered {
+		t.Error("expected event to be marked dead-lettered")
+	}
+	if markedPublished {
+		t.Error("expected a dead-lettered event not to be marked published")
+	}
 }
 
-func TestOutboxWorker_ProcessEvents_IncrementRetryOnPublishFailure(t *testing.T) {
+func TestOutboxWorker_ProcessEvents_ScheduleRetryOnPublishFailure(t *testing.T) {
 	payload, _ := json.Marshal(domain.PaymentInitiatedPayload{PaymentID: "pay-1"})
 	events := []*domain.OutboxEvent{
 		{
@@ -158,32 +174,307 @@ func TestOutboxWorker_ProcessEvents_IncrementRetryOnPublishFailure(t *testing.T)
 		},
 	}
 
-	retryIncremented := false
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	retryScheduled := false
+	var gotErr string
+	var gotNext time.Time
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{
-		listUnpublishedFn: func(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
 			return events, nil
 		},
-		incrementRetryFn: func(ctx context.Context, id string) error {
+		scheduleRetryFn: func(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error {
 			if id == "evt-retry" {
-				retryIncremented = true
+				retryScheduled = true
+				gotErr, gotNext = lastErr, nextAttemptAt
+			}
+			return nil
+		},
+	})
+	publisher := &mockPublisher{
+		publishFn: func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
+			return errors.New("broker unavailable")
+		},
+	}
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewOutboxWorker(outboxRepo, publisher, logger,
+		service.WithOutboxClock(func() time.Time { return now }),
+		service.WithOutboxRetryPolicy(service.OutboxRetryPolicy{BaseDelay: time.Minute, MaxDelay: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = worker.Run(ctx)
+
+	if !retryScheduled {
+		t.Fatal("expected a retry to be scheduled on publish failure")
+	}
+	if gotErr != "broker unavailable" {
+		t.Errorf("expected the publish error to be recorded, got %q", gotErr)
+	}
+	// Third failure: 1m doubled twice, plus up to half again of jitter.
+	if delay := gotNext.Sub(now); delay < 4*time.Minute || delay > 6*time.Minute {
+		t.Errorf("expected a retry in 4m-6m, got %v", delay)
+	}
+}
+
+func TestOutboxWorker_ProcessEvents_RetryDelayCapped(t *testing.T) {
+	payload, _ := json.Marshal(domain.PaymentInitiatedPayload{PaymentID: "pay-1"})
+	events := []*domain.OutboxEvent{
+		{ID: "evt-retry", AggregateType: "payment", AggregateID: "pay-1", EventType: domain.EventTypeBookingPaymentInitiated, Payload: payload, RetryCount: 20},
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var gotNext time.Time
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+			return events, nil
+		},
+		scheduleRetryFn: func(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error {
+			gotNext = nextAttemptAt
+			return nil
+		},
+	})
+	publisher := &mockPublisher{
+		publishFn: func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
+			return errors.New("broker unavailable")
+		},
+	}
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewOutboxWorker(outboxRepo, publisher, logger,
+		service.WithOutboxClock(func() time.Time { return now }),
+		service.WithOutboxRetryPolicy(service.OutboxRetryPolicy{MaxAttempts: 50, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = worker.Run(ctx)
+
+	if delay := gotNext.Sub(now); delay < 10*time.Minute || delay > 15*time.Minute {
+		t.Errorf("expected a capped retry in 10m-15m, got %v", delay)
+	}
+}
+
+func TestOutboxWorker_ProcessEvents_MaxAttemptsPerEventType(t *testing.T) {
+	payload, _ := json.Marshal(domain.PaymentInitiatedPayload{PaymentID: "pay-1"})
+	events := []*domain.OutboxEvent{
+		{ID: "evt-initiated", AggregateType: "payment", AggregateID: "pay-1", EventType: domain.EventTypeBookingPaymentInitiated, Payload: payload, RetryCount: 1},
+		{ID: "evt-succeeded", AggregateType: "payment", AggregateID: "pay-2", EventType: domain.EventTypePaymentSucceeded, Payload: payload, RetryCount: 1},
+	}
+
+	claims := 0
+	var deadLettered, retried []string
+	var deadLetterErr string
+	var deadLetterCount int
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+			claims++
+			if claims == 1 {
+				return events, nil
 			}
+			return []*domain.OutboxEvent{}, nil
+		},
+		scheduleRetryFn: func(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error {
+			retried = append(retried, id)
+			return nil
+		},
+		markDeadLetteredFn: func(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error {
+			deadLettered = append(deadLettered, id)
+			deadLetterErr, deadLetterCount = lastErr, retryCount
 			return nil
 		},
 	})
 	publisher := &mockPublisher{
-		publishFn: func(ctx context.Context, exchange, routingKey string, body []byte) error {
+		publishFn: func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
+			if exchange == "booking.events.dlx" {
+				return nil
+			}
 			return errors.New("broker unavailable")
 		},
 	}
 	logger, _ := zap.NewDevelopment()
+	worker := service.NewOutboxWorker(outboxRepo, publisher, logger,
+		service.WithOutboxRetryPolicy(service.OutboxRetryPolicy{
+			MaxAttempts:       5,
+			MaxAttemptsByType: map[string]int{domain.EventTypeBookingPaymentInitiated: 2},
+		}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = worker.Run(ctx)
+
+	if len(deadLettered) != 1 || deadLettered[0] != "evt-initiated" {
+		t.Errorf("expected the event type limited to 2 attempts to be dead-lettered, got %v", deadLettered)
+	}
+	if deadLetterErr != "broker unavailable" || deadLetterCount != 2 {
+		t.Errorf("expected dead-lettering after 2 attempts with the last error, got %d %q", deadLetterCount, deadLetterErr)
+	}
+	if len(retried) != 1 || retried[0] != "evt-succeeded" {
+		t.Errorf("expected the other event to be retried, got %v", retried)
+	}
+}
+
+func TestOutboxWorker_ProcessEvents_DLQFailureRetriesLater(t *testing.T) {
+	payload, _ := json.Marshal(domain.PaymentInitiatedPayload{PaymentID: "pay-1"})
+	events := []*domain.OutboxEvent{
+		{ID: "evt-dlq", AggregateType: "payment", AggregateID: "pay-1", EventType: domain.EventTypeBookingPaymentInitiated, Payload: payload, RetryCount: 5},
+	}
+
+	var retryErr string
+	deadLettered := false
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+			return events, nil
+		},
+		scheduleRetryFn: func(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error {
+			retryErr = lastErr
+			return nil
+		},
+		markDeadLetteredFn: func(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error {
+			deadLettered = true
+			return nil
+		},
+	})
+	publisher := &mockPublisher{
+		publishFn: func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
+			return errors.New("dlx unavailable")
+		},
+	}
+	logger, _ := zap.NewDevelopment()
 	worker := service.NewOutboxWorker(outboxRepo, publisher, logger)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 	_ = worker.Run(ctx)
 
-	if !retryIncremented {
-		t.Error("expected retry count to be incremented on publish failure")
+	if deadLettered {
+		t.Error("expected an event the DLX refused not to be marked dead-lettered")
+	}
+	if retryErr != "dead-letter: dlx unavailable" {
+		t.Errorf("expected a retry recording the dead-letter failure, got %q", retryErr)
+	}
+}
+
+func TestOutboxWorker_ProcessEvents_HoldsBackAggregateAfterFailure(t *testing.T) {
+	payload, _ := json.Marshal(domain.PaymentInitiatedPayload{PaymentID: "pay-1"})
+	events := []*domain.OutboxEvent{
+		{ID: "evt-1", AggregateType: "payment", AggregateID: "pay-1", EventType: domain.EventTypeBookingPaymentInitiated, Payload: payload},
+		{ID: "evt-2", AggregateType: "payment", AggregateID: "pay-2", EventType: domain.EventTypeBookingPaymentInitiated, Payload: payload},
+		{ID: "evt-3", AggregateType: "payment", AggregateID: "pay-1", EventType: domain.EventTypePaymentSucceeded, Payload: payload},
+	}
+
+	claims := 0
+	var published, released []string
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+			claims++
+			if claims == 1 {
+				return events, nil
+			}
+			return []*domain.OutboxEvent{}, nil
+		},
+		releaseEventsFn: func(ctx context.Context, relayID string, ids []string) error {
+			released = append(released, ids...)
+			return nil
+		},
+	})
+	publisher := &mockPublisher{
+		publishFn: func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
+			if event.ID == "evt-1" {
+				return errors.New("broker unavailable")
+			}
+			published = append(published, event.ID)
+			return nil
+		},
+	}
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewOutboxWorker(outboxRepo, publisher, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = worker.Run(ctx)
+
+	if len(published) != 1 || published[0] != "evt-2" {
+		t.Errorf("expected only the other aggregate's event to be published, got %v", published)
+	}
+	if len(released) != 1 || released[0] != "evt-3" {
+		t.Errorf("expected the event behind the failed one to be released, got %v", released)
+	}
+}
+
+func TestOutboxWorker_ClaimsUnderRelayID(t *testing.T) {
+	var gotRelay string
+	var gotLease time.Duration
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+			gotRelay, gotLease = relayID, lease
+			return []*domain.OutboxEvent{}, nil
+		},
+	})
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewOutboxWorker(outboxRepo, &mockPublisher{}, logger, service.WithOutboxRelayID("relay-a"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_ = worker.Run(ctx)
+
+	if gotRelay != "relay-a" {
+		t.Errorf("expected events claimed by relay-a, got %q", gotRelay)
+	}
+	if gotLease <= 0 {
+		t.Errorf("expected a positive lease, got %v", gotLease)
+	}
+}
+
+func TestOutboxWorker_RelaysOnWakeup(t *testing.T) {
+	claimed := make(chan struct{}, 10)
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+			claimed <- struct{}{}
+			return []*domain.OutboxEvent{}, nil
+		},
+	})
+	wakeups := make(chan struct{}, 1)
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewOutboxWorker(outboxRepo, &mockPublisher{}, logger, service.WithOutboxWakeups(wakeups))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go func() { _ = worker.Run(ctx) }()
+
+	<-claimed // initial pass
+	wakeups <- struct{}{}
+	select {
+	case <-claimed:
+	case <-ctx.Done():
+		t.Fatal("expected a wake-up to trigger a relay pass before the fallback poll")
+	}
+}
+
+func TestOutboxWorker_DrainsFullBatches(t *testing.T) {
+	payload, _ := json.Marshal(domain.PaymentInitiatedPayload{PaymentID: "pay-1"})
+	full := make([]*domain.OutboxEvent, 50)
+	for i := range full {
+		full[i] = &domain.OutboxEvent{ID: "evt", AggregateType: "payment", AggregateID: "pay-1", EventType: domain.EventTypeBookingPaymentInitiated, Payload: payload}
+	}
+
+	claims := make(chan struct{}, 10)
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+			claims <- struct{}{}
+			if len(claims) == 1 {
+				return full, nil
+			}
+			return []*domain.OutboxEvent{}, nil
+		},
+	})
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewOutboxWorker(outboxRepo, &mockPublisher{}, logger)
+
+	// Shorter than the poll interval: a second claim can only come from draining.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = worker.Run(ctx)
+
+	if len(claims) != 2 {
+		t.Errorf("expected a full batch to be followed by another claim, got %d claims", len(claims))
 	}
 }
 
@@ -196,6 +487,12 @@ func TestOutboxWorker_EventTypeToRoutingKey(t *testing.T) {
 		{domain.EventTypePaymentSucceeded, "payment.succeeded"},
 		{domain.EventTypePaymentFailed, "payment.failed"},
 		{domain.EventTypePaymentTimedOut, "payment.timed_out"},
+		{domain.EventTypeBookingExpired, "booking.expired"},
+		{domain.EventTypeRefundRequested, "payment.refund.requested"},
+		{domain.EventTypeRefundSucceeded, "payment.refund.succeeded"},
+		{domain.EventTypeRefundFailed, "payment.refund.failed"},
+		{domain.EventTypePaymentVoidRequested, "payment.void.requested"},
+		{domain.EventTypePaymentCaptureFailed, "payment.capture_failed"},
 	}
 
 	payload, _ := json.Marshal(domain.PaymentInitiatedPayload{PaymentID: "pay-1"})
@@ -214,12 +511,12 @@ func TestOutboxWorker_EventTypeToRoutingKey(t *testing.T) {
 
 			var capturedKey string
 			outboxRepo := makeOutboxRepo(mockOutboxRepo{
-				listUnpublishedFn: func(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+				claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
 					return events, nil
 				},
 			})
 			publisher := &mockPublisher{
-				publishFn: func(ctx context.Context, exchange, routingKey string, body []byte) error {
+				publishFn: func(ctx context.Context, exchange, routingKey string, event *domain.OutboxEvent) error {
 					capturedKey = routingKey
 					return nil
 				},
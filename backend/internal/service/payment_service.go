@@ -2,44 +2,77 @@ package service
 
 import (
 	"booking-app/internal/domain"
+	"booking-app/internal/infrastructure/gateway"
 	"booking-app/internal/repository"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
 	"time"
 )
 
 // PaymentServiceInterface defines the contract for payment business logic.
 type PaymentServiceInterface interface {
-	// ProcessPayment simulates payment processing via mock gateway.
+	// ProcessPayment charges a payment through the payment gateway.
 	// Called by the worker consumer after receiving BookingPaymentInitiated event.
 	ProcessPayment(ctx context.Context, paymentID string) error
 	// GetPayment retrieves a payment by ID.
 	GetPayment(ctx context.Context, id string, callerUserID string) (*domain.Payment, error)
 }
 
-// PaymentService implements PaymentServiceInterface with a mock gateway.
+// PaymentOption configures a PaymentService.
+type PaymentOption func(*PaymentService)
+
+// WithRefundProcessing enables ProcessRefund.
+func WithRefundProcessing(refundRepo repository.RefundRepository) PaymentOption {
+	return func(s *PaymentService) { s.refundRepo = refundRepo }
+}
+
+// WithPaymentTxManager commits each payment outcome together with the outbox
+// event announcing it. Without one, every statement autocommits on its own.
+func WithPaymentTxManager(tm repository.TxManager) PaymentOption {
+	return func(s *PaymentService) { s.txManager = tm }
+}
+
+// WithPaymentClock overrides the time source (used in tests).
+func WithPaymentClock(now func() time.Time) PaymentOption {
+	return func(s *PaymentService) { s.now = now }
+}
+
+// PaymentService implements PaymentServiceInterface on top of a PaymentGateway.
 type PaymentService struct {
 	payRepo    repository.PaymentRepository
 	outboxRepo repository.OutboxRepository
-	rng        *rand.Rand
+	refundRepo repository.RefundRepository // required for ProcessRefund
+	gateway    gateway.PaymentGateway
+	txManager  repository.TxManager // optional
+	now        func() time.Time
 }
 
-// NewPaymentService creates a new PaymentService.
-// seed controls the random number generator — use a fixed seed for deterministic tests.
-func NewPaymentService(payRepo repository.PaymentRepository, outboxRepo repository.OutboxRepository, seed int64) *PaymentService {
-	return &PaymentService{
+// NewPaymentService creates a new PaymentService that moves money through gw.
+func NewPaymentService(payRepo repository.PaymentRepository, outboxRepo repository.OutboxRepository, gw gateway.PaymentGateway, opts ...PaymentOption) *PaymentService {
+	s := &PaymentService{
 		payRepo:    payRepo,
 		outboxRepo: outboxRepo,
-		rng:        rand.New(rand.NewSource(seed)), //nolint:gosec
+		gateway:    gw,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// ProcessPayment runs the mock payment gateway logic:
-//   - 80% → success (PaymentSucceeded)
-//   - 15% → failure (PaymentFailed)
-//   - 5%  → timeout (PaymentTimedOut)
+// ProcessPayment authorizes the payment with the gateway. A payment whose
+// capture is scheduled for later stays authorized until CapturePayment
+// collects it; any other is captured straight away:
+//   - approved            → authorized or captured (PaymentSucceeded)
+//   - declined            → failed with the decline code (PaymentFailed)
+//   - authorize timed out → timed_out (PaymentTimedOut)
+//
+// Any other gateway error is returned so the message is redelivered; the
+// payment's idempotency key makes the retry safe. Each outcome is recorded in
+// one transaction with its event.
 func (s *PaymentService) ProcessPayment(ctx context.Context, paymentID string) error {
 	payment, err := s.payRepo.GetPaymentByID(ctx, paymentID)
 	if err != nil {
@@ -56,67 +89,271 @@ func (s *PaymentService) ProcessPayment(ctx context.Context, paymentID string) e
 		return fmt.Errorf("mark processing: %w", updateErr)
 	}
 
-	// Simulate gateway call.
-	outcome := s.rng.Intn(100) //nolint:gosec
+	auth, err := s.gateway.Authorize(ctx, gateway.AuthorizeRequest{
+		PaymentMethod:  payment.PaymentMethod,
+		Amount:         payment.Amount,
+		Currency:       payment.Currency,
+		IdempotencyKey: payment.IdempotencyKey,
+	})
 	switch {
-	case outcome < 80:
-		return s.handleGatewaySuccess(ctx, payment)
-	case outcome < 95:
-		return s.handleGatewayFailure(ctx, payment, "card declined by mock gateway")
-	default:
+	case errors.Is(err, gateway.ErrTimeout):
 		return s.handleGatewayTimeout(ctx, payment)
+	case err != nil:
+		return fmt.Errorf("authorize payment: %w", err)
+	case !auth.Approved:
+		return s.handleGatewayDecline(ctx, payment, auth)
+	}
+
+	if payment.CaptureAt != nil && payment.CaptureAt.After(s.now()) {
+		return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+			if err := s.payRepo.MarkAuthorized(ctx, payment.ID, auth.Ref); err != nil {
+				return fmt.Errorf("update payment authorized: %w", err)
+			}
+			return s.emitSucceeded(ctx, payment, auth.Ref)
+		})
 	}
-}
 
-func (s *PaymentService) handleGatewaySuccess(ctx context.Context, payment *domain.Payment) error {
-	gatewayRef := fmt.Sprintf("GW-%d", time.Now().UnixNano())
-	if err := s.payRepo.UpdatePaymentStatus(ctx, payment.ID, domain.PaymentStatusSucceeded, gatewayRef, ""); err != nil {
-		return fmt.Errorf("update payment succeeded: %w", err)
+	capture, err := s.gateway.Capture(ctx, auth.Ref, payment.Amount)
+	if err != nil {
+		return fmt.Errorf("capture payment: %w", err)
 	}
+	if !capture.Approved {
+		// Release the hold so the customer's funds are not tied up.
+		if _, voidErr := s.gateway.Void(ctx, auth.Ref); voidErr != nil {
+			return fmt.Errorf("void declined capture: %w", voidErr)
+		}
+		return s.handleGatewayDecline(ctx, payment, capture)
+	}
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		if err := s.payRepo.UpdatePaymentStatus(ctx, payment.ID, domain.PaymentStatusCaptured, capture.Ref, ""); err != nil {
+			return fmt.Errorf("update payment captured: %w", err)
+		}
+		return s.emitSucceeded(ctx, payment, capture.Ref)
+	})
+}
 
+func (s *PaymentService) emitSucceeded(ctx context.Context, payment *domain.Payment, gatewayRef string) error {
 	payload := domain.PaymentResultPayload{
 		PaymentID:  payment.ID,
 		BookingID:  payment.BookingID,
 		GatewayRef: gatewayRef,
 	}
-	return s.emitEvent(ctx, payment.ID, domain.EventTypePaymentSucceeded, payload)
+	return s.emitEvent(ctx, "payment", payment.ID, domain.EventTypePaymentSucceeded, payload)
 }
 
-func (s *PaymentService) handleGatewayFailure(ctx context.Context, payment *domain.Payment, reason string) error {
-	if err := s.payRepo.UpdatePaymentStatus(ctx, payment.ID, domain.PaymentStatusFailed, "", reason); err != nil {
-		return fmt.Errorf("update payment failed: %w", err)
+// CapturePayment collects an authorized payment whose capture is due. The
+// booking stays confirmed whatever the outcome; a declined capture fails the
+// payment and emits PaymentCaptureFailed so the guest can be told. Payments
+// that are no longer authorized, or whose whole amount was released, are
+// skipped. Gateway errors are returned so the next sweep retries.
+//
+// The payment row stays locked while the gateway captures it, so a
+// cancellation cannot lower or void the authorization in the meantime. If it
+// changed all the same, only what is still owed is recorded as captured and
+// the rest is refunded.
+func (s *PaymentService) CapturePayment(ctx context.Context, paymentID string) error {
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		payment, err := s.payRepo.GetPaymentForUpdate(ctx, paymentID)
+		if err != nil {
+			return fmt.Errorf("get payment: %w", err)
+		}
+		if payment.Status != domain.PaymentStatusAuthorized || payment.Amount <= 0 {
+			return nil
+		}
+
+		result, err := s.gateway.Capture(ctx, payment.AuthorizationRef, payment.Amount)
+		if err != nil {
+			return fmt.Errorf("capture payment: %w", err)
+		}
+		if !result.Approved {
+			reason := result.Reason()
+			if err := s.payRepo.DeclinePayment(ctx, payment.ID, string(result.DeclineCode), reason); err != nil {
+				return fmt.Errorf("update payment failed: %w", err)
+			}
+			payload := domain.PaymentResultPayload{
+				PaymentID:   payment.ID,
+				BookingID:   payment.BookingID,
+				Reason:      reason,
+				DeclineCode: string(result.DeclineCode),
+			}
+			return s.emitEvent(ctx, "payment", payment.ID, domain.EventTypePaymentCaptureFailed, payload)
+		}
+
+		err = s.payRepo.CapturePayment(ctx, payment.ID, result.Ref, payment.Amount)
+		if errors.Is(err, domain.ErrConflict) {
+			return s.settleOvercapture(ctx, payment.ID, result.Ref, payment.Amount)
+		}
+		if err != nil {
+			return fmt.Errorf("update payment captured: %w", err)
+		}
+		return nil
+	})
+}
+
+// settleOvercapture records a capture of captured whose payment changed while
+// the gateway collected it. A payment still authorized is captured for what
+// it now holds; one that was voided or failed owes nothing. Whatever was
+// collected beyond that is refunded to the card.
+func (s *PaymentService) settleOvercapture(ctx context.Context, paymentID, captureRef string, captured int64) error {
+	payment, err := s.payRepo.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		return fmt.Errorf("get payment: %w", err)
 	}
 
-	payload := domain.PaymentResultPayload{
-		PaymentID: payment.ID,
-		BookingID: payment.BookingID,
-		Reason:    reason,
+	var owed int64
+	switch payment.Status {
+	case domain.PaymentStatusAuthorized:
+		owed = min(payment.Amount, captured)
+	case domain.PaymentStatusCaptured, domain.PaymentStatusPartiallyRefunded, domain.PaymentStatusRefunded:
+		return nil // the capture was already recorded
 	}
-	return s.emitEvent(ctx, payment.ID, domain.EventTypePaymentFailed, payload)
+
+	if excess := captured - owed; excess > 0 {
+		result, err := s.gateway.Refund(ctx, gateway.RefundRequest{
+			ChargeRef:      captureRef,
+			Amount:         excess,
+			Currency:       payment.Currency,
+			IdempotencyKey: "overcapture:" + paymentID,
+		})
+		if err != nil {
+			return fmt.Errorf("refund overcapture: %w", err)
+		}
+		if !result.Approved {
+			return fmt.Errorf("refund overcapture of %d declined: %s", excess, result.Reason())
+		}
+	}
+	if owed > 0 {
+		if err := s.payRepo.CapturePayment(ctx, paymentID, captureRef, owed); err != nil {
+			return fmt.Errorf("update payment captured: %w", err)
+		}
+	}
+	return nil
+}
+
+// ProcessVoid releases an authorization whose booking was cancelled before
+// capture. Payments that are no longer authorized are skipped; gateway errors
+// are returned so the message is redelivered.
+func (s *PaymentService) ProcessVoid(ctx context.Context, paymentID string) error {
+	payment, err := s.payRepo.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		return fmt.Errorf("get payment: %w", err)
+	}
+	if payment.Status != domain.PaymentStatusAuthorized {
+		return nil
+	}
+
+	result, err := s.gateway.Void(ctx, payment.AuthorizationRef)
+	if err != nil {
+		return fmt.Errorf("void payment: %w", err)
+	}
+	if !result.Approved {
+		return fmt.Errorf("gateway declined void of payment %s: %s", payment.ID, result.Reason())
+	}
+
+	if err := s.payRepo.VoidPayment(ctx, payment.ID); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil
+		}
+		return fmt.Errorf("update payment voided: %w", err)
+	}
+	return nil
+}
+
+func (s *PaymentService) handleGatewayDecline(ctx context.Context, payment *domain.Payment, result *gateway.Result) error {
+	reason := result.Reason()
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		if err := s.payRepo.DeclinePayment(ctx, payment.ID, string(result.DeclineCode), reason); err != nil {
+			if errors.Is(err, domain.ErrConflict) {
+				return nil // settled otherwise meanwhile; no money moved
+			}
+			return fmt.Errorf("update payment failed: %w", err)
+		}
+
+		payload := domain.PaymentResultPayload{
+			PaymentID:   payment.ID,
+			BookingID:   payment.BookingID,
+			Reason:      reason,
+			DeclineCode: string(result.DeclineCode),
+		}
+		return s.emitEvent(ctx, "payment", payment.ID, domain.EventTypePaymentFailed, payload)
+	})
 }
 
 func (s *PaymentService) handleGatewayTimeout(ctx context.Context, payment *domain.Payment) error {
-	if err := s.payRepo.UpdatePaymentStatus(ctx, payment.ID, domain.PaymentStatusTimedOut, "", "gateway timeout"); err != nil {
-		return fmt.Errorf("update payment timed out: %w", err)
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		if err := s.payRepo.UpdatePaymentStatus(ctx, payment.ID, domain.PaymentStatusTimedOut, "", "gateway timeout"); err != nil {
+			if errors.Is(err, domain.ErrConflict) {
+				return nil // settled otherwise meanwhile
+			}
+			return fmt.Errorf("update payment timed out: %w", err)
+		}
+
+		payload := domain.PaymentResultPayload{
+			PaymentID: payment.ID,
+			BookingID: payment.BookingID,
+			Reason:    "gateway timeout",
+		}
+		return s.emitEvent(ctx, "payment", payment.ID, domain.EventTypePaymentTimedOut, payload)
+	})
+}
+
+// ProcessRefund sends a pending refund to the gateway against the captured
+// charge it belongs to and emits RefundSucceeded or RefundFailed. Gateway
+// errors are returned so the message is redelivered; the refund's idempotency
+// key keeps the retry from paying twice.
+//
+// The refund saga records the outcome when it consumes the result event.
+func (s *PaymentService) ProcessRefund(ctx context.Context, refundID string) error {
+	if s.refundRepo == nil {
+		return fmt.Errorf("refund processing is not configured")
+	}
+	refund, err := s.refundRepo.GetRefundByID(ctx, refundID)
+	if err != nil {
+		return fmt.Errorf("get refund: %w", err)
 	}
 
-	payload := domain.PaymentResultPayload{
-		PaymentID: payment.ID,
-		BookingID: payment.BookingID,
-		Reason:    "gateway timeout",
+	// Idempotency: a settled refund is never sent again.
+	if refund.Status != domain.RefundStatusPending {
+		return nil
+	}
+
+	charge, err := s.payRepo.GetPaymentByID(ctx, refund.PaymentID)
+	if err != nil {
+		return fmt.Errorf("get refunded payment: %w", err)
+	}
+
+	result, err := s.gateway.Refund(ctx, gateway.RefundRequest{
+		ChargeRef:      charge.GatewayRef,
+		Amount:         refund.Amount,
+		Currency:       refund.Currency,
+		IdempotencyKey: refund.IdempotencyKey,
+	})
+	if err != nil {
+		return fmt.Errorf("refund payment: %w", err)
+	}
+
+	payload := domain.RefundResultPayload{
+		RefundID:  refund.ID,
+		PaymentID: refund.PaymentID,
+		BookingID: refund.BookingID,
+	}
+	if result.Approved {
+		payload.GatewayRef = result.Ref
+		return s.emitEvent(ctx, "refund", refund.ID, domain.EventTypeRefundSucceeded, payload)
 	}
-	return s.emitEvent(ctx, payment.ID, domain.EventTypePaymentTimedOut, payload)
+	payload.Reason = result.Reason()
+	return s.emitEvent(ctx, "refund", refund.ID, domain.EventTypeRefundFailed, payload)
 }
 
-func (s *PaymentService) emitEvent(ctx context.Context, paymentID, eventType string, payload interface{}) error {
+func (s *PaymentService) emitEvent(ctx context.Context, aggregateType, aggregateID, eventType string, payload interface{}) error {
 	raw, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal event payload: %w", err)
 	}
 
 	event := &domain.OutboxEvent{
-		AggregateType: "payment",
-		AggregateID:   paymentID,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
 		EventType:     eventType,
 		Payload:       raw,
 	}
@@ -137,7 +374,9 @@ func (s *PaymentService) GetPayment(ctx context.Context, id string, callerUserID
 // isTerminalStatus returns true for statuses that should not be re-processed.
 func isTerminalStatus(status domain.PaymentStatus) bool {
 	switch status {
-	case domain.PaymentStatusSucceeded, domain.PaymentStatusFailed, domain.PaymentStatusTimedOut, domain.PaymentStatusRefunded:
+	case domain.PaymentStatusSucceeded, domain.PaymentStatusFailed, domain.PaymentStatusTimedOut,
+		domain.PaymentStatusRefunded, domain.PaymentStatusPartiallyRefunded,
+		domain.PaymentStatusAuthorized, domain.PaymentStatusCaptured, domain.PaymentStatusVoided:
 		return true
 	}
 	return false
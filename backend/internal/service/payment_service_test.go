@@ -2,9 +2,12 @@ package service_test
 
 import (
 	"booking-app/internal/domain"
+	"booking-app/internal/infrastructure/gateway"
 	"booking-app/internal/service"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -15,8 +18,17 @@ type mockPaymentRepo struct {
 	createPaymentFn              func(ctx context.Context, p *domain.Payment) (*domain.Payment, error)
 	getPaymentByIDFn             func(ctx context.Context, id string) (*domain.Payment, error)
 	getPaymentByBookingIDFn      func(ctx context.Context, bookingID int) (*domain.Payment, error)
+	listTopUpsFn                 func(ctx context.Context, bookingID int) ([]*domain.Payment, error)
 	updatePaymentStatusFn        func(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error
+	declinePaymentFn             func(ctx context.Context, id, declineCode, reason string) error
 	getPaymentByIdempotencyKeyFn func(ctx context.Context, key string) (*domain.Payment, error)
+	markAuthorizedFn             func(ctx context.Context, id, authorizationRef string) error
+	getPaymentForUpdateFn        func(ctx context.Context, id string) (*domain.Payment, error)
+	capturePaymentFn             func(ctx context.Context, id, captureRef string, amount int64) error
+	voidPaymentFn                func(ctx context.Context, id string) error
+	reduceAuthorizationFn        func(ctx context.Context, id string, amount int64) (int64, error)
+	reduceAuthorizedAmountFn     func(ctx context.Context, id string, amount int64) (int64, error)
+	listDueCapturesFn            func(ctx context.Context, before time.Time, limit int) ([]*domain.Payment, error)
 }
 
 func (m *mockPaymentRepo) CreatePayment(ctx context.Context, p *domain.Payment) (*domain.Payment, error) {
@@ -31,39 +43,94 @@ func (m *mockPaymentRepo) GetPaymentByBookingID(ctx context.Context, bookingID i
 	return m.getPaymentByBookingIDFn(ctx, bookingID)
 }
 
+func (m *mockPaymentRepo) ListTopUps(ctx context.Context, bookingID int) ([]*domain.Payment, error) {
+	return m.listTopUpsFn(ctx, bookingID)
+}
+
 func (m *mockPaymentRepo) UpdatePaymentStatus(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error {
 	return m.updatePaymentStatusFn(ctx, id, status, gatewayRef, failedReason)
 }
 
+func (m *mockPaymentRepo) DeclinePayment(ctx context.Context, id, declineCode, reason string) error {
+	return m.declinePaymentFn(ctx, id, declineCode, reason)
+}
+
 func (m *mockPaymentRepo) GetPaymentByIdempotencyKey(ctx context.Context, key string) (*domain.Payment, error) {
 	return m.getPaymentByIdempotencyKeyFn(ctx, key)
 }
 
+func (m *mockPaymentRepo) MarkAuthorized(ctx context.Context, id, authorizationRef string) error {
+	return m.markAuthorizedFn(ctx, id, authorizationRef)
+}
+
+// GetPaymentForUpdate reads through getPaymentByIDFn unless a test needs to
+// tell the locking read apart.
+func (m *mockPaymentRepo) GetPaymentForUpdate(ctx context.Context, id string) (*domain.Payment, error) {
+	if m.getPaymentForUpdateFn != nil {
+		return m.getPaymentForUpdateFn(ctx, id)
+	}
+	return m.getPaymentByIDFn(ctx, id)
+}
+
+func (m *mockPaymentRepo) CapturePayment(ctx context.Context, id, captureRef string, amount int64) error {
+	return m.capturePaymentFn(ctx, id, captureRef, amount)
+}
+
+func (m *mockPaymentRepo) VoidPayment(ctx context.Context, id string) error {
+	return m.voidPaymentFn(ctx, id)
+}
+
+func (m *mockPaymentRepo) ReduceAuthorization(ctx context.Context, id string, amount int64) (int64, error) {
+	return m.reduceAuthorizationFn(ctx, id, amount)
+}
+
+func (m *mockPaymentRepo) ReduceAuthorizedAmount(ctx context.Context, id string, amount int64) (int64, error) {
+	return m.reduceAuthorizedAmountFn(ctx, id, amount)
+}
+
+func (m *mockPaymentRepo) ListDueCaptures(ctx context.Context, before time.Time, limit int) ([]*domain.Payment, error) {
+	return m.listDueCapturesFn(ctx, before, limit)
+}
+
 // --- Mock OutboxRepository ---
 
 type mockOutboxRepo struct {
 	createEventFn         func(ctx context.Context, event *domain.OutboxEvent) error
-	listUnpublishedFn     func(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	claimBatchFn          func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error)
+	releaseEventsFn       func(ctx context.Context, relayID string, ids []string) error
 	markPublishedFn       func(ctx context.Context, id string, publishedAt time.Time) error
-	incrementRetryFn      func(ctx context.Context, id string) error
+	scheduleRetryFn       func(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error
+	markDeadLetteredFn    func(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error
 	isEventProcessedFn    func(ctx context.Context, eventID string) (bool, error)
 	markProcessedFn       func(ctx context.Context, eventID string) error
+	claimEventFn          func(ctx context.Context, eventID string) (bool, error)
 }
 
 func (m *mockOutboxRepo) CreateEvent(ctx context.Context, event *domain.OutboxEvent) error {
 	return m.createEventFn(ctx, event)
 }
 
-func (m *mockOutboxRepo) ListUnpublishedEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
-	return m.listUnpublishedFn(ctx, limit)
+func (m *mockOutboxRepo) ClaimBatch(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	return m.claimBatchFn(ctx, relayID, limit, lease)
+}
+
+func (m *mockOutboxRepo) ReleaseEvents(ctx context.Context, relayID string, ids []string) error {
+	if m.releaseEventsFn != nil {
+		return m.releaseEventsFn(ctx, relayID, ids)
+	}
+	return nil
 }
 
 func (m *mockOutboxRepo) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
 	return m.markPublishedFn(ctx, id, publishedAt)
 }
 
-func (m *mockOutboxRepo) IncrementRetry(ctx context.Context, id string) error {
-	return m.incrementRetryFn(ctx, id)
+func (m *mockOutboxRepo) ScheduleRetry(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error {
+	return m.scheduleRetryFn(ctx, id, lastErr, nextAttemptAt)
+}
+
+func (m *mockOutboxRepo) MarkDeadLettered(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error {
+	return m.markDeadLetteredFn(ctx, id, retryCount, lastErr, deadLetteredAt)
 }
 
 func (m *mockOutboxRepo) IsEventProcessed(ctx context.Context, eventID string) (bool, error) {
@@ -74,7 +141,14 @@ func (m *mockOutboxRepo) MarkProcessed(ctx context.Context, eventID string) erro
 	return m.markProcessedFn(ctx, eventID)
 }
 
-func (m *mockOutboxRepo) ListDLQEvents(ctx context.Context, maxRetries, page, limit int) ([]*domain.OutboxEvent, int, error) {
+func (m *mockOutboxRepo) ClaimEvent(ctx context.Context, eventID string) (bool, error) {
+	if m.claimEventFn != nil {
+		return m.claimEventFn(ctx, eventID)
+	}
+	return true, nil
+}
+
+func (m *mockOutboxRepo) ListDLQEvents(ctx context.Context, page, limit int) ([]*domain.OutboxEvent, int, error) {
 	return []*domain.OutboxEvent{}, 0, nil
 }
 
@@ -105,12 +179,36 @@ func makePaymentRepo(overrides mockPaymentRepo) *mockPaymentRepo {
 		getPaymentByBookingIDFn: func(ctx context.Context, bookingID int) (*domain.Payment, error) {
 			return nil, domain.ErrNotFound
 		},
+		listTopUpsFn: func(ctx context.Context, bookingID int) ([]*domain.Payment, error) {
+			return nil, nil
+		},
 		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error {
 			return nil
 		},
+		declinePaymentFn: func(ctx context.Context, id, declineCode, reason string) error {
+			return nil
+		},
 		getPaymentByIdempotencyKeyFn: func(ctx context.Context, key string) (*domain.Payment, error) {
 			return nil, domain.ErrNotFound
 		},
+		markAuthorizedFn: func(ctx context.Context, id, authorizationRef string) error {
+			return nil
+		},
+		capturePaymentFn: func(ctx context.Context, id, captureRef string, amount int64) error {
+			return nil
+		},
+		voidPaymentFn: func(ctx context.Context, id string) error {
+			return nil
+		},
+		reduceAuthorizationFn: func(ctx context.Context, id string, amount int64) (int64, error) {
+			return 0, nil
+		},
+		reduceAuthorizedAmountFn: func(ctx context.Context, id string, amount int64) (int64, error) {
+			return 0, nil
+		},
+		listDueCapturesFn: func(ctx context.Context, before time.Time, limit int) ([]*domain.Payment, error) {
+			return nil, nil
+		},
 	}
 	if overrides.createPaymentFn != nil {
 		defaults.createPaymentFn = overrides.createPaymentFn
@@ -118,15 +216,42 @@ func makePaymentRepo(overrides mockPaymentRepo) *mockPaymentRepo {
 	if overrides.getPaymentByIDFn != nil {
 		defaults.getPaymentByIDFn = overrides.getPaymentByIDFn
 	}
+	if overrides.getPaymentForUpdateFn != nil {
+		defaults.getPaymentForUpdateFn = overrides.getPaymentForUpdateFn
+	}
 	if overrides.getPaymentByBookingIDFn != nil {
 		defaults.getPaymentByBookingIDFn = overrides.getPaymentByBookingIDFn
 	}
+	if overrides.listTopUpsFn != nil {
+		defaults.listTopUpsFn = overrides.listTopUpsFn
+	}
 	if overrides.updatePaymentStatusFn != nil {
 		defaults.updatePaymentStatusFn = overrides.updatePaymentStatusFn
 	}
+	if overrides.declinePaymentFn != nil {
+		defaults.declinePaymentFn = overrides.declinePaymentFn
+	}
 	if overrides.getPaymentByIdempotencyKeyFn != nil {
 		defaults.getPaymentByIdempotencyKeyFn = overrides.getPaymentByIdempotencyKeyFn
 	}
+	if overrides.markAuthorizedFn != nil {
+		defaults.markAuthorizedFn = overrides.markAuthorizedFn
+	}
+	if overrides.capturePaymentFn != nil {
+		defaults.capturePaymentFn = overrides.capturePaymentFn
+	}
+	if overrides.voidPaymentFn != nil {
+		defaults.voidPaymentFn = overrides.voidPaymentFn
+	}
+	if overrides.reduceAuthorizationFn != nil {
+		defaults.reduceAuthorizationFn = overrides.reduceAuthorizationFn
+	}
+	if overrides.reduceAuthorizedAmountFn != nil {
+		defaults.reduceAuthorizedAmountFn = overrides.reduceAuthorizedAmountFn
+	}
+	if overrides.listDueCapturesFn != nil {
+		defaults.listDueCapturesFn = overrides.listDueCapturesFn
+	}
 	return defaults
 }
 
@@ -135,13 +260,16 @@ func makeOutboxRepo(overrides mockOutboxRepo) *mockOutboxRepo {
 		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
 			return nil
 		},
-		listUnpublishedFn: func(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+		claimBatchFn: func(ctx context.Context, relayID string, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
 			return []*domain.OutboxEvent{}, nil
 		},
 		markPublishedFn: func(ctx context.Context, id string, publishedAt time.Time) error {
 			return nil
 		},
-		incrementRetryFn: func(ctx context.Context, id string) error {
+		scheduleRetryFn: func(ctx context.Context, id, lastErr string, nextAttemptAt time.Time) error {
+			return nil
+		},
+		markDeadLetteredFn: func(ctx context.Context, id string, retryCount int, lastErr string, deadLetteredAt time.Time) error {
 			return nil
 		},
 		isEventProcessedFn: func(ctx context.Context, eventID string) (bool, error) {
@@ -154,14 +282,20 @@ func makeOutboxRepo(overrides mockOutboxRepo) *mockOutboxRepo {
 	if overrides.createEventFn != nil {
 		defaults.createEventFn = overrides.createEventFn
 	}
-	if overrides.listUnpublishedFn != nil {
-		defaults.listUnpublishedFn = overrides.listUnpublishedFn
+	if overrides.claimBatchFn != nil {
+		defaults.claimBatchFn = overrides.claimBatchFn
+	}
+	if overrides.releaseEventsFn != nil {
+		defaults.releaseEventsFn = overrides.releaseEventsFn
 	}
 	if overrides.markPublishedFn != nil {
 		defaults.markPublishedFn = overrides.markPublishedFn
 	}
-	if overrides.incrementRetryFn != nil {
-		defaults.incrementRetryFn = overrides.incrementRetryFn
+	if overrides.scheduleRetryFn != nil {
+		defaults.scheduleRetryFn = overrides.scheduleRetryFn
+	}
+	if overrides.markDeadLetteredFn != nil {
+		defaults.markDeadLetteredFn = overrides.markDeadLetteredFn
 	}
 	if overrides.isEventProcessedFn != nil {
 		defaults.isEventProcessedFn = overrides.isEventProcessedFn
@@ -169,9 +303,84 @@ func makeOutboxRepo(overrides mockOutboxRepo) *mockOutboxRepo {
 	if overrides.markProcessedFn != nil {
 		defaults.markProcessedFn = overrides.markProcessedFn
 	}
+	if overrides.claimEventFn != nil {
+		defaults.claimEventFn = overrides.claimEventFn
+	}
+	return defaults
+}
+
+// --- Mock PaymentGateway ---
+
+type mockGateway struct {
+	authorizeFn func(ctx context.Context, req gateway.AuthorizeRequest) (*gateway.Result, error)
+	captureFn   func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error)
+	voidFn      func(ctx context.Context, authRef string) (*gateway.Result, error)
+	refundFn    func(ctx context.Context, req gateway.RefundRequest) (*gateway.Result, error)
+}
+
+func (m *mockGateway) Authorize(ctx context.Context, req gateway.AuthorizeRequest) (*gateway.Result, error) {
+	return m.authorizeFn(ctx, req)
+}
+
+func (m *mockGateway) Capture(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+	return m.captureFn(ctx, authRef, amount)
+}
+
+func (m *mockGateway) Void(ctx context.Context, authRef string) (*gateway.Result, error) {
+	return m.voidFn(ctx, authRef)
+}
+
+func (m *mockGateway) Refund(ctx context.Context, req gateway.RefundRequest) (*gateway.Result, error) {
+	return m.refundFn(ctx, req)
+}
+
+// makeGateway returns a gateway that approves everything unless overridden.
+func makeGateway(overrides mockGateway) *mockGateway {
+	defaults := &mockGateway{
+		authorizeFn: func(ctx context.Context, req gateway.AuthorizeRequest) (*gateway.Result, error) {
+			return &gateway.Result{Approved: true, Ref: "auth-1"}, nil
+		},
+		captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+			return &gateway.Result{Approved: true, Ref: "ch-1"}, nil
+		},
+		voidFn: func(ctx context.Context, authRef string) (*gateway.Result, error) {
+			return &gateway.Result{Approved: true, Ref: "void-1"}, nil
+		},
+		refundFn: func(ctx context.Context, req gateway.RefundRequest) (*gateway.Result, error) {
+			return &gateway.Result{Approved: true, Ref: "re-1"}, nil
+		},
+	}
+	if overrides.authorizeFn != nil {
+		defaults.authorizeFn = overrides.authorizeFn
+	}
+	if overrides.captureFn != nil {
+		defaults.captureFn = overrides.captureFn
+	}
+	if overrides.voidFn != nil {
+		defaults.voidFn = overrides.voidFn
+	}
+	if overrides.refundFn != nil {
+		defaults.refundFn = overrides.refundFn
+	}
 	return defaults
 }
 
+func declineAuthorize(code gateway.DeclineCode) mockGateway {
+	return mockGateway{
+		authorizeFn: func(ctx context.Context, req gateway.AuthorizeRequest) (*gateway.Result, error) {
+			return &gateway.Result{DeclineCode: code, Message: "declined"}, nil
+		},
+	}
+}
+
+func timeoutAuthorize() mockGateway {
+	return mockGateway{
+		authorizeFn: func(ctx context.Context, req gateway.AuthorizeRequest) (*gateway.Result, error) {
+			return nil, gateway.ErrTimeout
+		},
+	}
+}
+
 // --- Tests: PaymentService.ProcessPayment ---
 
 func TestPaymentService_ProcessPayment_PaymentNotFound(t *testing.T) {
@@ -181,7 +390,7 @@ func TestPaymentService_ProcessPayment_PaymentNotFound(t *testing.T) {
 		},
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 42)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 
 	err := svc.ProcessPayment(context.Background(), "non-existent-id")
 
@@ -200,7 +409,7 @@ func TestPaymentService_ProcessPayment_AlreadyProcessed(t *testing.T) {
 		},
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 42)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 
 	err := svc.ProcessPayment(context.Background(), "pay-id")
 
@@ -216,7 +425,7 @@ func TestPaymentService_ProcessPayment_UpdateStatusError(t *testing.T) {
 		},
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 42)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 
 	err := svc.ProcessPayment(context.Background(), "pay-id")
 
@@ -237,8 +446,7 @@ func TestPaymentService_ProcessPayment_OutboxEventCreated(t *testing.T) {
 			return nil
 		},
 	})
-	// Use a fixed seed so the result is deterministic — seed 0 produces known outcome.
-	svc := service.NewPaymentService(payRepo, outboxRepo, 0)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 
 	_ = svc.ProcessPayment(context.Background(), "pay-id")
 
@@ -247,24 +455,82 @@ func TestPaymentService_ProcessPayment_OutboxEventCreated(t *testing.T) {
 	}
 }
 
-func TestPaymentService_ProcessPayment_DeterministicWithSeed(t *testing.T) {
-	// With seed=1, we can call ProcessPayment many times and all results must be
-	// one of the three valid outcomes.
-	payRepo := makePaymentRepo(mockPaymentRepo{})
-	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 1)
-
-	for i := 0; i < 5; i++ {
-		// Reset payment to pending each iteration via a fresh mock.
-		pr := makePaymentRepo(mockPaymentRepo{})
-		or := makeOutboxRepo(mockOutboxRepo{})
-		svc2 := service.NewPaymentService(pr, or, int64(i))
-		err := svc2.ProcessPayment(context.Background(), "pay-id")
-		if err != nil {
-			t.Errorf("iteration %d: unexpected error %v", i, err)
+func TestPaymentService_ProcessPayment_AuthorizesThenCaptures(t *testing.T) {
+	var authReq gateway.AuthorizeRequest
+	var capturedRef string
+	gw := makeGateway(mockGateway{
+		authorizeFn: func(ctx context.Context, req gateway.AuthorizeRequest) (*gateway.Result, error) {
+			authReq = req
+			return &gateway.Result{Approved: true, Ref: "auth-7"}, nil
+		},
+		captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+			capturedRef = authRef
+			return &gateway.Result{Approved: true, Ref: "ch-7"}, nil
+		},
+	})
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: 150, Currency: "USD", Status: domain.PaymentStatusPending,
+				IdempotencyKey: "checkout:1:user-1", PaymentMethod: "tok_visa"}, nil
+		},
+	})
+	var events []*domain.OutboxEvent
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	})
+	svc := service.NewPaymentService(payRepo, outboxRepo, gw)
+
+	if err := svc.ProcessPayment(context.Background(), "pay-id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authReq.PaymentMethod != "tok_visa" || authReq.Amount != 150 || authReq.IdempotencyKey != "checkout:1:user-1" {
+		t.Errorf("unexpected authorize request %+v", authReq)
+	}
+	if capturedRef != "auth-7" {
+		t.Errorf("expected capture of auth-7, got %q", capturedRef)
+	}
+	if len(events) != 1 || events[0].EventType != domain.EventTypePaymentSucceeded {
+		t.Fatalf("expected one PaymentSucceeded event, got %+v", events)
+	}
+	var payload domain.PaymentResultPayload
+	_ = json.Unmarshal(events[0].Payload, &payload)
+	if payload.GatewayRef != "ch-7" {
+		t.Errorf("expected capture ref in payload, got %q", payload.GatewayRef)
+	}
+}
+
+func TestPaymentService_ProcessPayment_SameOutcomeForSamePayment(t *testing.T) {
+	// The scripted gateway makes the outcome a function of card and amount,
+	// so processing the same payment twice gives the same event.
+	outcome := func() string {
+		var eventType string
+		payRepo := makePaymentRepo(mockPaymentRepo{
+			getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+				return &domain.Payment{ID: id, Amount: 99, Status: domain.PaymentStatusPending,
+					IdempotencyKey: "checkout:3:user-1", PaymentMethod: gateway.TestCardInsufficientFunds}, nil
+			},
+		})
+		outboxRepo := makeOutboxRepo(mockOutboxRepo{
+			createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+				eventType = event.EventType
+				return nil
+			},
+		})
+		svc := service.NewPaymentService(payRepo, outboxRepo, gateway.NewScriptedGateway())
+		if err := svc.ProcessPayment(context.Background(), "pay-3"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return eventType
+	}
+
+	for i := 0; i < 3; i++ {
+		if got := outcome(); got != domain.EventTypePaymentFailed {
+			t.Fatalf("run %d: expected PaymentFailed, got %q", i, got)
 		}
 	}
-	_ = svc
 }
 
 // --- Tests: PaymentService.GetPayment ---
@@ -281,7 +547,7 @@ func TestPaymentService_GetPayment_Success(t *testing.T) {
 		},
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 0)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 
 	payment, err := svc.GetPayment(context.Background(), "pay-id", "user-1")
 
@@ -300,7 +566,7 @@ func TestPaymentService_GetPayment_NotFound(t *testing.T) {
 		},
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 0)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 
 	_, err := svc.GetPayment(context.Background(), "missing-id", "user-1")
 
@@ -312,7 +578,7 @@ func TestPaymentService_GetPayment_NotFound(t *testing.T) {
 func TestPaymentService_GetPayment_EmptyIDReturnsError(t *testing.T) {
 	payRepo := makePaymentRepo(mockPaymentRepo{})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 0)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 
 	_, err := svc.GetPayment(context.Background(), "", "user-1")
 
@@ -322,38 +588,94 @@ func TestPaymentService_GetPayment_EmptyIDReturnsError(t *testing.T) {
 }
 
 func TestPaymentService_ProcessPayment_FailureOutcome(t *testing.T) {
-	// seed=1 produces Intn(100)==81 which is in the [80,95) failure range.
-	var capturedStatus domain.PaymentStatus
+	var declineCode, reason string
 	var capturedEventType string
 	payRepo := makePaymentRepo(mockPaymentRepo{
-		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, ref, reason string) error {
-			capturedStatus = status
+		declinePaymentFn: func(ctx context.Context, id, code, r string) error {
+			declineCode, reason = code, r
 			return nil
 		},
 	})
+	var payload domain.PaymentResultPayload
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{
 		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
 			capturedEventType = event.EventType
-			return nil
+			return json.Unmarshal(event.Payload, &payload)
 		},
 	})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 1)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(declineAuthorize(gateway.DeclineCodeInsufficientFunds)))
 
 	err := svc.ProcessPayment(context.Background(), "pay-id")
 
 	if err != nil {
 		t.Fatalf("expected no error on failure outcome, got %v", err)
 	}
-	if capturedStatus != domain.PaymentStatusFailed {
-		t.Errorf("expected final status=failed, got %q", capturedStatus)
+	if declineCode != "insufficient_funds" || reason != "insufficient_funds: declined" {
+		t.Errorf("expected decline recorded, got code=%q reason=%q", declineCode, reason)
 	}
 	if capturedEventType != domain.EventTypePaymentFailed {
 		t.Errorf("expected event type %q, got %q", domain.EventTypePaymentFailed, capturedEventType)
 	}
+	if payload.DeclineCode != "insufficient_funds" {
+		t.Errorf("expected decline code in payload, got %q", payload.DeclineCode)
+	}
+}
+
+func TestPaymentService_ProcessPayment_CaptureDeclinedVoidsAuthorization(t *testing.T) {
+	var voided string
+	gw := makeGateway(mockGateway{
+		captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+			return &gateway.Result{DeclineCode: gateway.DeclineCodeCardDeclined}, nil
+		},
+		voidFn: func(ctx context.Context, authRef string) (*gateway.Result, error) {
+			voided = authRef
+			return &gateway.Result{Approved: true}, nil
+		},
+	})
+	declined := false
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		declinePaymentFn: func(ctx context.Context, id, code, reason string) error {
+			declined = true
+			return nil
+		},
+	})
+	svc := service.NewPaymentService(payRepo, makeOutboxRepo(mockOutboxRepo{}), gw)
+
+	if err := svc.ProcessPayment(context.Background(), "pay-id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if voided != "auth-1" {
+		t.Errorf("expected authorization auth-1 voided, got %q", voided)
+	}
+	if !declined {
+		t.Error("expected payment declined")
+	}
+}
+
+func TestPaymentService_ProcessPayment_GatewayErrorIsRetried(t *testing.T) {
+	eventEmitted := false
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			eventEmitted = true
+			return nil
+		},
+	})
+	gw := makeGateway(mockGateway{
+		authorizeFn: func(ctx context.Context, req gateway.AuthorizeRequest) (*gateway.Result, error) {
+			return nil, errors.New("gateway returned 502")
+		},
+	})
+	svc := service.NewPaymentService(makePaymentRepo(mockPaymentRepo{}), outboxRepo, gw)
+
+	if err := svc.ProcessPayment(context.Background(), "pay-id"); err == nil {
+		t.Error("expected error so the message is redelivered")
+	}
+	if eventEmitted {
+		t.Error("no outcome event must be emitted when the outcome is unknown")
+	}
 }
 
 func TestPaymentService_ProcessPayment_TimeoutOutcome(t *testing.T) {
-	// seed=59 produces Intn(100)==95 which is in the [95,100) timeout range.
 	var capturedStatus domain.PaymentStatus
 	var capturedEventType string
 	payRepo := makePaymentRepo(mockPaymentRepo{
@@ -368,7 +690,7 @@ func TestPaymentService_ProcessPayment_TimeoutOutcome(t *testing.T) {
 			return nil
 		},
 	})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 59)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(timeoutAuthorize()))
 
 	err := svc.ProcessPayment(context.Background(), "pay-id")
 
@@ -384,7 +706,7 @@ func TestPaymentService_ProcessPayment_TimeoutOutcome(t *testing.T) {
 }
 
 func TestPaymentService_ProcessPayment_SuccessUpdateError(t *testing.T) {
-	// seed=0 → success path (outcome=74). Second UpdatePaymentStatus (mark succeeded) fails.
+	// Second UpdatePaymentStatus (mark succeeded) fails.
 	callCount := 0
 	payRepo := makePaymentRepo(mockPaymentRepo{
 		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, ref, reason string) error {
@@ -396,7 +718,7 @@ func TestPaymentService_ProcessPayment_SuccessUpdateError(t *testing.T) {
 		},
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 0) // seed=0 → success
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 
 	err := svc.ProcessPayment(context.Background(), "pay-id")
 
@@ -406,29 +728,23 @@ func TestPaymentService_ProcessPayment_SuccessUpdateError(t *testing.T) {
 }
 
 func TestPaymentService_ProcessPayment_FailureUpdateError(t *testing.T) {
-	// seed=1 → failure path (outcome=81). Second UpdatePaymentStatus (mark failed) fails.
-	callCount := 0
 	payRepo := makePaymentRepo(mockPaymentRepo{
-		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, ref, reason string) error {
-			callCount++
-			if callCount == 2 {
-				return domain.ErrInternal
-			}
-			return nil
+		declinePaymentFn: func(ctx context.Context, id, code, reason string) error {
+			return domain.ErrInternal
 		},
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 1) // seed=1 → failure
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(declineAuthorize(gateway.DeclineCodeCardDeclined)))
 
 	err := svc.ProcessPayment(context.Background(), "pay-id")
 
 	if err == nil {
-		t.Error("expected error when second UpdatePaymentStatus fails on failure path")
+		t.Error("expected error when DeclinePayment fails on failure path")
 	}
 }
 
 func TestPaymentService_ProcessPayment_TimeoutUpdateError(t *testing.T) {
-	// seed=59 → timeout path (outcome=95). Second UpdatePaymentStatus (mark timed_out) fails.
+	// Second UpdatePaymentStatus (mark timed_out) fails.
 	callCount := 0
 	payRepo := makePaymentRepo(mockPaymentRepo{
 		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, ref, reason string) error {
@@ -440,7 +756,7 @@ func TestPaymentService_ProcessPayment_TimeoutUpdateError(t *testing.T) {
 		},
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 59) // seed=59 → timeout
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(timeoutAuthorize()))
 
 	err := svc.ProcessPayment(context.Background(), "pay-id")
 
@@ -451,7 +767,6 @@ func TestPaymentService_ProcessPayment_TimeoutUpdateError(t *testing.T) {
 
 func TestPaymentService_ProcessPayment_EventTypeOnSuccess(t *testing.T) {
 	var capturedEventType string
-	// seed=100 → we can observe what outcome occurs; we capture the event type.
 	payRepo := makePaymentRepo(mockPaymentRepo{})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{
 		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
@@ -459,15 +774,431 @@ func TestPaymentService_ProcessPayment_EventTypeOnSuccess(t *testing.T) {
 			return nil
 		},
 	})
-	svc := service.NewPaymentService(payRepo, outboxRepo, 100)
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(mockGateway{}))
 	_ = svc.ProcessPayment(context.Background(), "pay-id")
 
-	validTypes := map[string]bool{
-		domain.EventTypePaymentSucceeded: true,
-		domain.EventTypePaymentFailed:    true,
-		domain.EventTypePaymentTimedOut:  true,
+	if capturedEventType != domain.EventTypePaymentSucceeded {
+		t.Errorf("expected event type %q, got %q", domain.EventTypePaymentSucceeded, capturedEventType)
+	}
+}
+
+// --- Tests: PaymentService.ProcessRefund ---
+
+func TestPaymentService_ProcessRefund_RefundsCapturedCharge(t *testing.T) {
+	var refundReq gateway.RefundRequest
+	gw := makeGateway(mockGateway{
+		refundFn: func(ctx context.Context, req gateway.RefundRequest) (*gateway.Result, error) {
+			refundReq = req
+			return &gateway.Result{Approved: true, Ref: "re-9"}, nil
+		},
+	})
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, GatewayRef: "ch-1", Status: domain.PaymentStatusSucceeded}, nil
+		},
+	})
+	var events []*domain.OutboxEvent
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	})
+	svc := service.NewPaymentService(payRepo, outboxRepo, gw,
+		service.WithRefundProcessing(makeRefundRepo(mockRefundRepo{})))
+
+	if err := svc.ProcessRefund(context.Background(), "ref-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if refundReq.ChargeRef != "ch-1" || refundReq.Amount != 50 {
+		t.Errorf("unexpected refund request %+v", refundReq)
 	}
-	if !validTypes[capturedEventType] {
-		t.Errorf("unexpected event type %q", capturedEventType)
+	if len(events) != 1 || events[0].EventType != domain.EventTypeRefundSucceeded || events[0].AggregateID != "ref-1" {
+		t.Fatalf("expected one RefundSucceeded event for ref-1, got %+v", events)
+	}
+}
+
+func TestPaymentService_ProcessRefund_RejectedRefundFails(t *testing.T) {
+	gw := makeGateway(mockGateway{
+		refundFn: func(ctx context.Context, req gateway.RefundRequest) (*gateway.Result, error) {
+			return &gateway.Result{DeclineCode: gateway.DeclineCodeRefundRejected, Message: "closed account"}, nil
+		},
+	})
+	var payload domain.RefundResultPayload
+	var eventType string
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			eventType = event.EventType
+			return json.Unmarshal(event.Payload, &payload)
+		},
+	})
+	svc := service.NewPaymentService(makePaymentRepo(mockPaymentRepo{}), outboxRepo, gw,
+		service.WithRefundProcessing(makeRefundRepo(mockRefundRepo{})))
+
+	if err := svc.ProcessRefund(context.Background(), "ref-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if eventType != domain.EventTypeRefundFailed || payload.Reason != "refund_rejected: closed account" {
+		t.Errorf("expected RefundFailed with reason, got %q %+v", eventType, payload)
+	}
+}
+
+func TestPaymentService_ProcessRefund_SettledRefundSkipped(t *testing.T) {
+	eventEmitted := false
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			eventEmitted = true
+			return nil
+		},
+	})
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		getRefundByIDFn: func(ctx context.Context, id string) (*domain.Refund, error) {
+			return &domain.Refund{ID: id, Status: domain.RefundStatusFailed}, nil
+		},
+	})
+	svc := service.NewPaymentService(makePaymentRepo(mockPaymentRepo{}), outboxRepo, makeGateway(mockGateway{}), service.WithRefundProcessing(refundRepo))
+
+	if err := svc.ProcessRefund(context.Background(), "ref-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if eventEmitted {
+		t.Error("a settled refund must not be sent again")
+	}
+}
+
+// --- Tests: authorize-then-capture ---
+
+func TestPaymentService_ProcessPayment_DeferredCaptureOnlyAuthorizes(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	captureAt := now.AddDate(0, 0, 10)
+	gw := makeGateway(mockGateway{
+		captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+			t.Fatal("payment with a future capture time must not be captured at checkout")
+			return nil, nil
+		},
+	})
+	var authorizedRef string
+	var statuses []domain.PaymentStatus
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: 150, Status: domain.PaymentStatusPending, CaptureAt: &captureAt}, nil
+		},
+		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error {
+			statuses = append(statuses, status)
+			return nil
+		},
+		markAuthorizedFn: func(ctx context.Context, id, authorizationRef string) error {
+			authorizedRef = authorizationRef
+			return nil
+		},
+	})
+	var events []*domain.OutboxEvent
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	})
+	svc := service.NewPaymentService(payRepo, outboxRepo, gw, service.WithPaymentClock(func() time.Time { return now }))
+
+	if err := svc.ProcessPayment(context.Background(), "pay-id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authorizedRef != "auth-1" {
+		t.Errorf("expected payment authorized with auth-1, got %q", authorizedRef)
+	}
+	if len(statuses) != 1 || statuses[0] != domain.PaymentStatusProcessing {
+		t.Errorf("expected only the processing update, got %v", statuses)
+	}
+	if len(events) != 1 || events[0].EventType != domain.EventTypePaymentSucceeded {
+		t.Fatalf("expected PaymentSucceeded so the booking is confirmed, got %+v", events)
+	}
+}
+
+func TestPaymentService_ProcessPayment_ImmediateCaptureMarksCaptured(t *testing.T) {
+	var final domain.PaymentStatus
+	var finalRef string
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error {
+			final, finalRef = status, gatewayRef
+			return nil
+		},
+	})
+	svc := service.NewPaymentService(payRepo, makeOutboxRepo(mockOutboxRepo{}), makeGateway(mockGateway{}))
+
+	if err := svc.ProcessPayment(context.Background(), "pay-id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if final != domain.PaymentStatusCaptured || finalRef != "ch-1" {
+		t.Errorf("expected captured with ch-1, got %q %q", final, finalRef)
+	}
+}
+
+func authorizedPayment(amount int64) mockPaymentRepo {
+	return mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: amount, Status: domain.PaymentStatusAuthorized, AuthorizationRef: "auth-9"}, nil
+		},
+	}
+}
+
+func TestPaymentService_CapturePayment_CapturesAuthorization(t *testing.T) {
+	var gotRef string
+	var gotAmount int64
+	gw := makeGateway(mockGateway{
+		captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+			gotRef, gotAmount = authRef, amount
+			return &gateway.Result{Approved: true, Ref: "ch-9"}, nil
+		},
+	})
+	overrides := authorizedPayment(120)
+	var capturedWith string
+	overrides.capturePaymentFn = func(ctx context.Context, id, captureRef string, amount int64) error {
+		capturedWith = captureRef
+		return nil
+	}
+	svc := service.NewPaymentService(makePaymentRepo(overrides), makeOutboxRepo(mockOutboxRepo{}), gw)
+
+	if err := svc.CapturePayment(context.Background(), "pay-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotRef != "auth-9" || gotAmount != 120 {
+		t.Errorf("expected capture of 120 on auth-9, got %d on %q", gotAmount, gotRef)
+	}
+	if capturedWith != "ch-9" {
+		t.Errorf("expected payment captured with ch-9, got %q", capturedWith)
+	}
+}
+
+func TestPaymentService_CapturePayment_HoldsPaymentLockDuringGatewayCall(t *testing.T) {
+	var outsideTx []string
+	gw := makeGateway(mockGateway{
+		captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+			if !inTx(ctx) {
+				outsideTx = append(outsideTx, "gateway capture")
+			}
+			return &gateway.Result{Approved: true, Ref: "ch-9"}, nil
+		},
+	})
+	var locked bool
+	var capturedAmount int64
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentForUpdateFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			locked = inTx(ctx)
+			return &domain.Payment{ID: id, Amount: 120, Status: domain.PaymentStatusAuthorized, AuthorizationRef: "auth-9"}, nil
+		},
+		capturePaymentFn: func(ctx context.Context, id, captureRef string, amount int64) error {
+			if !inTx(ctx) {
+				outsideTx = append(outsideTx, "record capture")
+			}
+			capturedAmount = amount
+			return nil
+		},
+	})
+	txManager := &mockTxManager{}
+	svc := service.NewPaymentService(payRepo, makeOutboxRepo(mockOutboxRepo{}), gw, service.WithPaymentTxManager(txManager))
+
+	if err := svc.CapturePayment(context.Background(), "pay-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !locked || len(outsideTx) != 0 || txManager.committed != 1 {
+		t.Errorf("expected lock, capture and record in one transaction, got locked=%v outside=%v commits=%d",
+			locked, outsideTx, txManager.committed)
+	}
+	if capturedAmount != 120 {
+		t.Errorf("expected the capture guarded on 120, got %d", capturedAmount)
+	}
+}
+
+func TestPaymentService_CapturePayment_ChangedMeanwhileRefundsExcess(t *testing.T) {
+	tests := []struct {
+		name       string
+		now        *domain.Payment
+		wantRefund int64
+		wantRecord int64
+	}{
+		{"authorization reduced", &domain.Payment{ID: "pay-1", Amount: 30, Status: domain.PaymentStatusAuthorized}, 90, 30},
+		{"authorization voided", &domain.Payment{ID: "pay-1", Amount: 120, Status: domain.PaymentStatusVoided}, 120, 0},
+		{"capture already recorded", &domain.Payment{ID: "pay-1", Amount: 120, Status: domain.PaymentStatusCaptured}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var refunded int64
+			var refundRef string
+			gw := makeGateway(mockGateway{
+				captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+					return &gateway.Result{Approved: true, Ref: "ch-9"}, nil
+				},
+				refundFn: func(ctx context.Context, req gateway.RefundRequest) (*gateway.Result, error) {
+					refunded, refundRef = req.Amount, req.ChargeRef
+					return &gateway.Result{Approved: true, Ref: "re-1"}, nil
+				},
+			})
+			var recorded int64
+			payRepo := makePaymentRepo(mockPaymentRepo{
+				getPaymentForUpdateFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+					return &domain.Payment{ID: id, Amount: 120, Status: domain.PaymentStatusAuthorized, AuthorizationRef: "auth-9"}, nil
+				},
+				getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+					return tt.now, nil
+				},
+				capturePaymentFn: func(ctx context.Context, id, captureRef string, amount int64) error {
+					if amount != tt.now.Amount || tt.now.Status != domain.PaymentStatusAuthorized {
+						return fmt.Errorf("payment is not authorized for %d: %w", amount, domain.ErrConflict)
+					}
+					recorded = amount
+					return nil
+				},
+			})
+			svc := service.NewPaymentService(payRepo, makeOutboxRepo(mockOutboxRepo{}), gw)
+
+			if err := svc.CapturePayment(context.Background(), "pay-1"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if refunded != tt.wantRefund || (refunded > 0 && refundRef != "ch-9") {
+				t.Errorf("expected %d refunded on ch-9, got %d on %q", tt.wantRefund, refunded, refundRef)
+			}
+			if recorded != tt.wantRecord {
+				t.Errorf("expected %d recorded as captured, got %d", tt.wantRecord, recorded)
+			}
+		})
+	}
+}
+
+func TestPaymentService_CapturePayment_DeclineEmitsCaptureFailed(t *testing.T) {
+	gw := makeGateway(mockGateway{
+		captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+			return &gateway.Result{DeclineCode: gateway.DeclineCodeCardDeclined}, nil
+		},
+	})
+	overrides := authorizedPayment(120)
+	var declineCode string
+	overrides.declinePaymentFn = func(ctx context.Context, id, code, reason string) error {
+		declineCode = code
+		return nil
+	}
+	var events []*domain.OutboxEvent
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	})
+	svc := service.NewPaymentService(makePaymentRepo(overrides), outboxRepo, gw)
+
+	if err := svc.CapturePayment(context.Background(), "pay-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if declineCode != string(gateway.DeclineCodeCardDeclined) {
+		t.Errorf("expected payment declined with card_declined, got %q", declineCode)
+	}
+	if len(events) != 1 || events[0].EventType != domain.EventTypePaymentCaptureFailed {
+		t.Fatalf("expected one PaymentCaptureFailed event, got %+v", events)
+	}
+}
+
+func TestPaymentService_CapturePayment_SkipsSettledOrReleased(t *testing.T) {
+	gw := makeGateway(mockGateway{
+		captureFn: func(ctx context.Context, authRef string, amount int64) (*gateway.Result, error) {
+			t.Fatal("capture must not reach the gateway")
+			return nil, nil
+		},
+	})
+	for name, payRepo := range map[string]*mockPaymentRepo{
+		"voided": makePaymentRepo(mockPaymentRepo{
+			getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+				return &domain.Payment{ID: id, Amount: 120, Status: domain.PaymentStatusVoided}, nil
+			},
+		}),
+		"fully released": makePaymentRepo(authorizedPayment(0)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc := service.NewPaymentService(payRepo, makeOutboxRepo(mockOutboxRepo{}), gw)
+			if err := svc.CapturePayment(context.Background(), "pay-1"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPaymentService_ProcessVoid_VoidsAuthorization(t *testing.T) {
+	var voidedRef string
+	gw := makeGateway(mockGateway{
+		voidFn: func(ctx context.Context, authRef string) (*gateway.Result, error) {
+			voidedRef = authRef
+			return &gateway.Result{Approved: true}, nil
+		},
+	})
+	overrides := authorizedPayment(120)
+	voided := false
+	overrides.voidPaymentFn = func(ctx context.Context, id string) error {
+		voided = true
+		return nil
+	}
+	svc := service.NewPaymentService(makePaymentRepo(overrides), makeOutboxRepo(mockOutboxRepo{}), gw)
+
+	if err := svc.ProcessVoid(context.Background(), "pay-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if voidedRef != "auth-9" || !voided {
+		t.Errorf("expected auth-9 voided and recorded, got ref=%q recorded=%v", voidedRef, voided)
+	}
+}
+
+func TestPaymentService_ProcessVoid_GatewayErrorIsRetried(t *testing.T) {
+	gw := makeGateway(mockGateway{
+		voidFn: func(ctx context.Context, authRef string) (*gateway.Result, error) {
+			return nil, gateway.ErrTimeout
+		},
+	})
+	svc := service.NewPaymentService(makePaymentRepo(authorizedPayment(120)), makeOutboxRepo(mockOutboxRepo{}), gw)
+
+	if err := svc.ProcessVoid(context.Background(), "pay-1"); !errors.Is(err, gateway.ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestPaymentService_ProcessPayment_DeclineRolledBackWithoutEvent(t *testing.T) {
+	var declinedInTx bool
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		declinePaymentFn: func(ctx context.Context, id, code, r string) error {
+			declinedInTx = inTx(ctx)
+			return nil
+		},
+	})
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			return errors.New("db down")
+		},
+	})
+	txManager := &mockTxManager{}
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(declineAuthorize(gateway.DeclineCodeCardDeclined)),
+		service.WithPaymentTxManager(txManager))
+
+	if err := svc.ProcessPayment(context.Background(), "pay-id"); err == nil {
+		t.Fatal("expected the event error to be returned so the message is redelivered")
+	}
+	if !declinedInTx || txManager.rolledBack != 1 {
+		t.Errorf("expected the decline rolled back with its event, got in tx %v and %d rollbacks",
+			declinedInTx, txManager.rolledBack)
+	}
+}
+
+func TestPaymentService_ProcessPayment_DeclineOfSettledPaymentDropped(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		declinePaymentFn: func(ctx context.Context, id, code, r string) error {
+			return fmt.Errorf("payment %q cannot move from timed_out to failed: %w", id, domain.ErrConflict)
+		},
+	})
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			t.Errorf("expected no %s event for a payment that already settled", event.EventType)
+			return nil
+		},
+	})
+	svc := service.NewPaymentService(payRepo, outboxRepo, makeGateway(declineAuthorize(gateway.DeclineCodeCardDeclined)))
+
+	if err := svc.ProcessPayment(context.Background(), "pay-id"); err != nil {
+		t.Fatalf("expected the decline dropped, got %v", err)
 	}
 }
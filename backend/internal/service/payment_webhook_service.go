@@ -0,0 +1,186 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/infrastructure/gateway"
+	"booking-app/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// webhookEventNamespace derives processed_events IDs from provider event IDs,
+// which are not UUIDs.
+var webhookEventNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("booking-app/payment-webhooks"))
+
+// PaymentWebhookServiceInterface defines the contract for provider webhooks.
+type PaymentWebhookServiceInterface interface {
+	// HandleWebhook verifies, dedupes and applies one provider notification.
+	HandleWebhook(ctx context.Context, provider string, body []byte, signature string) error
+}
+
+// PaymentResultHandler is the part of the payment saga that settles payments
+// and refunds.
+type PaymentResultHandler interface {
+	HandlePaymentSuccess(ctx context.Context, paymentID string) error
+	HandleLateSuccess(ctx context.Context, paymentID string, status domain.PaymentStatus, gatewayRef string) error
+	HandlePaymentFailure(ctx context.Context, paymentID string, reason string) error
+	HandleCaptureFailed(ctx context.Context, paymentID, reason string) error
+	HandleRefundSucceeded(ctx context.Context, refundID, gatewayRef string) error
+	HandleRefundFailed(ctx context.Context, refundID, reason string) error
+}
+
+// PaymentWebhookOption configures a PaymentWebhookService.
+type PaymentWebhookOption func(*PaymentWebhookService)
+
+// WithWebhookTxManager applies each webhook event, the saga step it triggers
+// and the record that it was processed in one transaction.
+func WithWebhookTxManager(tm repository.TxManager) PaymentWebhookOption {
+	return func(s *PaymentWebhookService) { s.txManager = tm }
+}
+
+// WithWebhookClock overrides the time source (used in tests).
+func WithWebhookClock(now func() time.Time) PaymentWebhookOption {
+	return func(s *PaymentWebhookService) { s.now = now }
+}
+
+// PaymentWebhookService applies the payment provider's asynchronous results.
+// An event is matched to its payment or refund by the idempotency key we sent
+// with the original request and fed into the same saga handlers the worker
+// uses, so a result that arrives both synchronously and by webhook is applied
+// once.
+type PaymentWebhookService struct {
+	payRepo    repository.PaymentRepository
+	refundRepo repository.RefundRepository
+	outboxRepo repository.OutboxRepository
+	saga       PaymentResultHandler
+	// secrets maps a provider name to its webhook signing secret.
+	secrets   map[string]string
+	txManager repository.TxManager // optional
+	now       func() time.Time
+}
+
+// NewPaymentWebhookService creates a new PaymentWebhookService. Providers
+// missing from secrets are rejected.
+func NewPaymentWebhookService(
+	payRepo repository.PaymentRepository,
+	refundRepo repository.RefundRepository,
+	outboxRepo repository.OutboxRepository,
+	saga PaymentResultHandler,
+	secrets map[string]string,
+	opts ...PaymentWebhookOption,
+) *PaymentWebhookService {
+	s := &PaymentWebhookService{
+		payRepo:    payRepo,
+		refundRepo: refundRepo,
+		outboxRepo: outboxRepo,
+		saga:       saga,
+		secrets:    secrets,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// HandleWebhook verifies the signature of body with the provider's secret,
+// claims the event and applies it in the same transaction, so a redelivery
+// racing the first attempt finds it claimed and is skipped:
+//   - payment.succeeded → payment authorized or captured (HandlePaymentSuccess,
+//     or HandleLateSuccess when the payment had already timed out)
+//   - payment.failed    → payment declined (HandlePaymentFailure, or
+//     HandleCaptureFailed when an authorized payment could not be collected)
+//   - refund.succeeded  → HandleRefundSucceeded
+//   - refund.failed     → HandleRefundFailed
+//
+// Unknown event types are acknowledged and ignored. Returns ErrNotFound for an
+// unknown provider or an event about a payment or refund we do not have,
+// ErrUnauthorized for a bad signature and ErrBadRequest for a malformed body.
+func (s *PaymentWebhookService) HandleWebhook(ctx context.Context, provider string, body []byte, signature string) error {
+	secret, ok := s.secrets[provider]
+	if !ok {
+		return fmt.Errorf("payment provider %q: %w", provider, domain.ErrNotFound)
+	}
+	if !gateway.VerifyWebhookSignature(secret, body, signature) {
+		return fmt.Errorf("invalid webhook signature: %w", domain.ErrUnauthorized)
+	}
+	event, err := gateway.ParseWebhookEvent(body)
+	if err != nil {
+		return fmt.Errorf("invalid webhook event: %v: %w", err, domain.ErrBadRequest)
+	}
+
+	eventID := uuid.NewSHA1(webhookEventNamespace, []byte(provider+":"+event.ID)).String()
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		claimed, err := s.outboxRepo.ClaimEvent(ctx, eventID)
+		if err != nil {
+			return fmt.Errorf("claim webhook event: %w", err)
+		}
+		if !claimed {
+			return nil
+		}
+
+		switch event.Type {
+		case gateway.WebhookPaymentSucceeded, gateway.WebhookPaymentFailed:
+			return s.applyPaymentResult(ctx, event)
+		case gateway.WebhookRefundSucceeded, gateway.WebhookRefundFailed:
+			return s.applyRefundResult(ctx, event)
+		}
+		return nil
+	})
+}
+
+func (s *PaymentWebhookService) applyPaymentResult(ctx context.Context, event *gateway.WebhookEvent) error {
+	payment, err := s.payRepo.GetPaymentByIdempotencyKey(ctx, event.Data.IdempotencyKey)
+	if err != nil {
+		return fmt.Errorf("get webhook payment: %w", err)
+	}
+	result := event.Result()
+	inFlight := payment.Status == domain.PaymentStatusPending || payment.Status == domain.PaymentStatusProcessing
+	authorizeOnly := payment.CaptureAt != nil && payment.CaptureAt.After(s.now())
+
+	switch {
+	case result.Approved && inFlight:
+		if authorizeOnly {
+			if err := s.payRepo.MarkAuthorized(ctx, payment.ID, result.Ref); err != nil {
+				return fmt.Errorf("update payment authorized: %w", err)
+			}
+		} else if err := s.payRepo.UpdatePaymentStatus(ctx, payment.ID, domain.PaymentStatusCaptured, result.Ref, ""); err != nil {
+			return fmt.Errorf("update payment captured: %w", err)
+		}
+		return s.saga.HandlePaymentSuccess(ctx, payment.ID)
+	case result.Approved && payment.Status == domain.PaymentStatusTimedOut:
+		// The gateway took the money after we gave up on it.
+		status := domain.PaymentStatusCaptured
+		if authorizeOnly {
+			status = domain.PaymentStatusAuthorized
+		}
+		return s.saga.HandleLateSuccess(ctx, payment.ID, status, result.Ref)
+	case !result.Approved && inFlight:
+		if err := s.payRepo.DeclinePayment(ctx, payment.ID, string(result.DeclineCode), result.Reason()); err != nil {
+			return fmt.Errorf("update payment failed: %w", err)
+		}
+		return s.saga.HandlePaymentFailure(ctx, payment.ID, result.Reason())
+	case !result.Approved && payment.Status == domain.PaymentStatusAuthorized:
+		if err := s.payRepo.DeclinePayment(ctx, payment.ID, string(result.DeclineCode), result.Reason()); err != nil {
+			return fmt.Errorf("update payment failed: %w", err)
+		}
+		return s.saga.HandleCaptureFailed(ctx, payment.ID, result.Reason())
+	}
+	// The result was already applied when the gateway answered synchronously.
+	return nil
+}
+
+func (s *PaymentWebhookService) applyRefundResult(ctx context.Context, event *gateway.WebhookEvent) error {
+	refund, err := s.refundRepo.GetRefundByIdempotencyKey(ctx, event.Data.IdempotencyKey)
+	if err != nil {
+		return fmt.Errorf("get webhook refund: %w", err)
+	}
+	result := event.Result()
+	if result.Approved {
+		return s.saga.HandleRefundSucceeded(ctx, refund.ID, result.Ref)
+	}
+	return s.saga.HandleRefundFailed(ctx, refund.ID, result.Reason())
+}
@@ -0,0 +1,371 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/infrastructure/gateway"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type mockPaymentResultHandler struct {
+	calls []string
+}
+
+func (m *mockPaymentResultHandler) HandlePaymentSuccess(_ context.Context, paymentID string) error {
+	m.calls = append(m.calls, "success:"+paymentID)
+	return nil
+}
+
+func (m *mockPaymentResultHandler) HandleLateSuccess(_ context.Context, paymentID string, status domain.PaymentStatus, gatewayRef string) error {
+	m.calls = append(m.calls, "late_success:"+paymentID+":"+string(status)+":"+gatewayRef)
+	return nil
+}
+
+func (m *mockPaymentResultHandler) HandlePaymentFailure(_ context.Context, paymentID, reason string) error {
+	m.calls = append(m.calls, "failure:"+paymentID+":"+reason)
+	return nil
+}
+
+func (m *mockPaymentResultHandler) HandleCaptureFailed(_ context.Context, paymentID, reason string) error {
+	m.calls = append(m.calls, "capture_failed:"+paymentID+":"+reason)
+	return nil
+}
+
+func (m *mockPaymentResultHandler) HandleRefundSucceeded(_ context.Context, refundID, gatewayRef string) error {
+	m.calls = append(m.calls, "refund_succeeded:"+refundID+":"+gatewayRef)
+	return nil
+}
+
+func (m *mockPaymentResultHandler) HandleRefundFailed(_ context.Context, refundID, reason string) error {
+	m.calls = append(m.calls, "refund_failed:"+refundID+":"+reason)
+	return nil
+}
+
+const testWebhookSecret = "whsec_test"
+
+func newWebhookSvc(payRepo *mockPaymentRepo, outboxRepo *mockOutboxRepo, saga *mockPaymentResultHandler, now time.Time) *service.PaymentWebhookService {
+	return service.NewPaymentWebhookService(payRepo, makeRefundRepo(mockRefundRepo{}), outboxRepo, saga,
+		map[string]string{"acme": testWebhookSecret},
+		service.WithWebhookClock(func() time.Time { return now }))
+}
+
+func pendingPaymentByKey(status domain.PaymentStatus, captureAt *time.Time) func(context.Context, string) (*domain.Payment, error) {
+	return func(_ context.Context, key string) (*domain.Payment, error) {
+		return &domain.Payment{ID: "pay-1", BookingID: 1, Status: status, IdempotencyKey: key, CaptureAt: captureAt}, nil
+	}
+}
+
+// --- Tests: PaymentWebhookService ---
+
+func TestHandleWebhook_PaymentSucceeded_CapturesAndConfirms(t *testing.T) {
+	var gotStatus domain.PaymentStatus
+	var gotRef string
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIdempotencyKeyFn: pendingPaymentByKey(domain.PaymentStatusProcessing, nil),
+		updatePaymentStatusFn: func(_ context.Context, _ string, status domain.PaymentStatus, ref, _ string) error {
+			gotStatus, gotRef = status, ref
+			return nil
+		},
+	})
+	var claimed string
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimEventFn: func(_ context.Context, eventID string) (bool, error) {
+			claimed = eventID
+			return true, nil
+		},
+	})
+	saga := &mockPaymentResultHandler{}
+	svc := newWebhookSvc(payRepo, outboxRepo, saga, time.Now())
+
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"id":"ch_1","idempotency_key":"booking-1"}}`)
+	if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotStatus != domain.PaymentStatusCaptured || gotRef != "ch_1" {
+		t.Errorf("expected payment captured with ch_1, got %s %q", gotStatus, gotRef)
+	}
+	if len(saga.calls) != 1 || saga.calls[0] != "success:pay-1" {
+		t.Errorf("expected HandlePaymentSuccess, got %v", saga.calls)
+	}
+	if claimed == "" {
+		t.Error("expected the event to be claimed")
+	}
+}
+
+func TestHandleWebhook_PaymentSucceeded_ScheduledCaptureStaysAuthorized(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(72 * time.Hour)
+	var authorizedRef string
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIdempotencyKeyFn: pendingPaymentByKey(domain.PaymentStatusProcessing, &later),
+		markAuthorizedFn: func(_ context.Context, _, ref string) error {
+			authorizedRef = ref
+			return nil
+		},
+		updatePaymentStatusFn: func(_ context.Context, _ string, status domain.PaymentStatus, _, _ string) error {
+			t.Errorf("unexpected status update to %s", status)
+			return nil
+		},
+	})
+	saga := &mockPaymentResultHandler{}
+	svc := newWebhookSvc(payRepo, makeOutboxRepo(mockOutboxRepo{}), saga, now)
+
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"id":"auth_1","idempotency_key":"booking-1"}}`)
+	if err := svc.HandleWebhook(context.Background(), "acme", body, "sha256="+gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authorizedRef != "auth_1" {
+		t.Errorf("expected payment authorized with auth_1, got %q", authorizedRef)
+	}
+	if len(saga.calls) != 1 || saga.calls[0] != "success:pay-1" {
+		t.Errorf("expected HandlePaymentSuccess, got %v", saga.calls)
+	}
+}
+
+func TestHandleWebhook_PaymentFailed(t *testing.T) {
+	var declined string
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIdempotencyKeyFn: pendingPaymentByKey(domain.PaymentStatusPending, nil),
+		declinePaymentFn: func(_ context.Context, _, code, _ string) error {
+			declined = code
+			return nil
+		},
+	})
+	saga := &mockPaymentResultHandler{}
+	svc := newWebhookSvc(payRepo, makeOutboxRepo(mockOutboxRepo{}), saga, time.Now())
+
+	body := []byte(`{"id":"evt_2","type":"payment.failed","data":{"idempotency_key":"booking-1","decline_code":"insufficient_funds"}}`)
+	if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if declined != "insufficient_funds" {
+		t.Errorf("expected insufficient_funds decline, got %q", declined)
+	}
+	if len(saga.calls) != 1 || saga.calls[0] != "failure:pay-1:insufficient_funds" {
+		t.Errorf("expected HandlePaymentFailure, got %v", saga.calls)
+	}
+}
+
+func TestHandleWebhook_PaymentFailed_AuthorizedIsCaptureFailure(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIdempotencyKeyFn: pendingPaymentByKey(domain.PaymentStatusAuthorized, nil),
+	})
+	saga := &mockPaymentResultHandler{}
+	svc := newWebhookSvc(payRepo, makeOutboxRepo(mockOutboxRepo{}), saga, time.Now())
+
+	body := []byte(`{"id":"evt_3","type":"payment.failed","data":{"idempotency_key":"booking-1","decline_code":"expired_card"}}`)
+	if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(saga.calls) != 1 || saga.calls[0] != "capture_failed:pay-1:expired_card" {
+		t.Errorf("expected HandleCaptureFailed, got %v", saga.calls)
+	}
+}
+
+func TestHandleWebhook_PaymentAlreadySettled_IsNoOp(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIdempotencyKeyFn: pendingPaymentByKey(domain.PaymentStatusCaptured, nil),
+	})
+	saga := &mockPaymentResultHandler{}
+	svc := newWebhookSvc(payRepo, makeOutboxRepo(mockOutboxRepo{}), saga, time.Now())
+
+	body := []byte(`{"id":"evt_4","type":"payment.succeeded","data":{"id":"ch_1","idempotency_key":"booking-1"}}`)
+	if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(saga.calls) != 0 {
+		t.Errorf("expected no saga calls, got %v", saga.calls)
+	}
+}
+
+func TestHandleWebhook_Refund(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"id":"evt_5","type":"refund.succeeded","data":{"id":"re_1","idempotency_key":"refund-1"}}`, "refund_succeeded:ref-uuid-1:re_1"},
+		{`{"id":"evt_6","type":"refund.failed","data":{"idempotency_key":"refund-1","decline_code":"refund_rejected"}}`, "refund_failed:ref-uuid-1:refund_rejected"},
+	}
+	for _, tt := range tests {
+		saga := &mockPaymentResultHandler{}
+		svc := newWebhookSvc(makePaymentRepo(mockPaymentRepo{}), makeOutboxRepo(mockOutboxRepo{}), saga, time.Now())
+
+		body := []byte(tt.body)
+		if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(saga.calls) != 1 || saga.calls[0] != tt.want {
+			t.Errorf("expected %s, got %v", tt.want, saga.calls)
+		}
+	}
+}
+
+func TestHandleWebhook_DuplicateEventIsSkipped(t *testing.T) {
+	var checked string
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimEventFn: func(_ context.Context, eventID string) (bool, error) {
+			checked = eventID
+			return false, nil
+		},
+	})
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIdempotencyKeyFn: func(_ context.Context, _ string) (*domain.Payment, error) {
+			t.Error("duplicate event should not be applied")
+			return nil, errors.New("unexpected")
+		},
+	})
+	saga := &mockPaymentResultHandler{}
+	svc := newWebhookSvc(payRepo, outboxRepo, saga, time.Now())
+
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"id":"ch_1","idempotency_key":"booking-1"}}`)
+	if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The provider event ID is mapped to a stable UUID for processed_events.
+	first := checked
+	_ = svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body))
+	if first == "" || first != checked || len(first) != 36 {
+		t.Errorf("expected a stable UUID event id, got %q and %q", first, checked)
+	}
+}
+
+func TestHandleWebhook_UnknownTypeIsAcknowledged(t *testing.T) {
+	var claimed bool
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimEventFn: func(_ context.Context, _ string) (bool, error) {
+			claimed = true
+			return true, nil
+		},
+	})
+	saga := &mockPaymentResultHandler{}
+	svc := newWebhookSvc(makePaymentRepo(mockPaymentRepo{}), outboxRepo, saga, time.Now())
+
+	body := []byte(`{"id":"evt_7","type":"dispute.created","data":{"idempotency_key":"booking-1"}}`)
+	if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !claimed || len(saga.calls) != 0 {
+		t.Errorf("expected event acknowledged without saga calls, claimed=%v calls=%v", claimed, saga.calls)
+	}
+}
+
+func TestHandleWebhook_Rejections(t *testing.T) {
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"id":"ch_1","idempotency_key":"booking-1"}}`)
+	tests := []struct {
+		name      string
+		provider  string
+		body      []byte
+		signature string
+		wantErr   error
+	}{
+		{"unknown provider", "other", body, gateway.SignWebhook(testWebhookSecret, body), domain.ErrNotFound},
+		{"bad signature", "acme", body, gateway.SignWebhook("wrong", body), domain.ErrUnauthorized},
+		{"malformed body", "acme", []byte(`{}`), gateway.SignWebhook(testWebhookSecret, []byte(`{}`)), domain.ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saga := &mockPaymentResultHandler{}
+			svc := newWebhookSvc(makePaymentRepo(mockPaymentRepo{}), makeOutboxRepo(mockOutboxRepo{}), saga, time.Now())
+
+			err := svc.HandleWebhook(context.Background(), tt.provider, tt.body, tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if len(saga.calls) != 0 {
+				t.Errorf("expected no saga calls, got %v", saga.calls)
+			}
+		})
+	}
+}
+
+func TestHandleWebhook_UnknownPaymentReleasesClaim(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIdempotencyKeyFn: func(_ context.Context, _ string) (*domain.Payment, error) {
+			return nil, domain.ErrNotFound
+		},
+	})
+	txManager := &mockTxManager{}
+	svc := service.NewPaymentWebhookService(payRepo, makeRefundRepo(mockRefundRepo{}), makeOutboxRepo(mockOutboxRepo{}),
+		&mockPaymentResultHandler{}, map[string]string{"acme": testWebhookSecret}, service.WithWebhookTxManager(txManager))
+
+	body := []byte(`{"id":"evt_8","type":"payment.succeeded","data":{"id":"ch_1","idempotency_key":"nope"}}`)
+	err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body))
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	// The claim is rolled back with the failed event so a retry applies it.
+	if txManager.rolledBack != 1 || txManager.committed != 0 {
+		t.Errorf("expected the claim rolled back, got %d commits and %d rollbacks",
+			txManager.committed, txManager.rolledBack)
+	}
+}
+
+func TestHandleWebhook_AppliesResultInOneTransaction(t *testing.T) {
+	var outsideTx []string
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIdempotencyKeyFn: pendingPaymentByKey(domain.PaymentStatusProcessing, nil),
+		declinePaymentFn: func(ctx context.Context, _, _, _ string) error {
+			if !inTx(ctx) {
+				outsideTx = append(outsideTx, "decline")
+			}
+			return nil
+		},
+	})
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		claimEventFn: func(ctx context.Context, _ string) (bool, error) {
+			if !inTx(ctx) {
+				outsideTx = append(outsideTx, "claim")
+			}
+			return true, nil
+		},
+	})
+	txManager := &mockTxManager{}
+	svc := service.NewPaymentWebhookService(payRepo, makeRefundRepo(mockRefundRepo{}), outboxRepo, &mockPaymentResultHandler{},
+		map[string]string{"acme": testWebhookSecret}, service.WithWebhookTxManager(txManager))
+
+	body := []byte(`{"id":"evt_9","type":"payment.failed","data":{"id":"ch_1","idempotency_key":"booking-1","decline_code":"card_declined"}}`)
+	if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outsideTx) != 0 || txManager.committed != 1 {
+		t.Errorf("expected decline and claim committed together, got %v outside and %d commits",
+			outsideTx, txManager.committed)
+	}
+}
+
+func TestHandleWebhook_PaymentSucceeded_AfterTimeoutIsLateSuccess(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(72 * time.Hour)
+	tests := []struct {
+		name      string
+		captureAt *time.Time
+		want      string
+	}{
+		{"captured", nil, "late_success:pay-1:captured:ch_1"},
+		{"scheduled capture", &later, "late_success:pay-1:authorized:ch_1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payRepo := makePaymentRepo(mockPaymentRepo{
+				getPaymentByIdempotencyKeyFn: pendingPaymentByKey(domain.PaymentStatusTimedOut, tt.captureAt),
+				updatePaymentStatusFn: func(_ context.Context, _ string, status domain.PaymentStatus, _, _ string) error {
+					t.Errorf("unexpected status update to %s outside the saga", status)
+					return nil
+				},
+			})
+			saga := &mockPaymentResultHandler{}
+			svc := newWebhookSvc(payRepo, makeOutboxRepo(mockOutboxRepo{}), saga, now)
+
+			body := []byte(`{"id":"evt_10","type":"payment.succeeded","data":{"id":"ch_1","idempotency_key":"booking-1"}}`)
+			if err := svc.HandleWebhook(context.Background(), "acme", body, gateway.SignWebhook(testWebhookSecret, body)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(saga.calls) != 1 || saga.calls[0] != tt.want {
+				t.Errorf("expected %s, got %v", tt.want, saga.calls)
+			}
+		})
+	}
+}
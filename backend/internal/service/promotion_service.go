@@ -0,0 +1,156 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// promoCodePattern is what a normalized promo code may look like.
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+// PromotionService manages promo codes. Owners create codes for their own
+// hotels; admins can create codes for any hotel or room, or for every stay.
+type PromotionService struct {
+	promoRepo repository.PromotionRepository
+	hotelRepo repository.HotelRepository
+	roomRepo  repository.RoomRepository
+}
+
+// NewPromotionService creates a new PromotionService.
+func NewPromotionService(promoRepo repository.PromotionRepository, hotelRepo repository.HotelRepository, roomRepo repository.RoomRepository) *PromotionService {
+	return &PromotionService{promoRepo: promoRepo, hotelRepo: hotelRepo, roomRepo: roomRepo}
+}
+
+// CreatePromotion creates a code for a hotel the caller owns, or for one of
+// its rooms. A fixed amount defaults to the hotel's currency.
+func (s *PromotionService) CreatePromotion(ctx context.Context, ownerID string, p *domain.Promotion) (*domain.Promotion, error) {
+	if p.HotelID == nil {
+		return nil, fmt.Errorf("hotel_id is required: %w", domain.ErrBadRequest)
+	}
+	hotel, err := s.scope(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if hotel.OwnerID != ownerID {
+		return nil, fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
+	}
+	p.CreatedBy = ownerID
+	return s.create(ctx, p, hotel)
+}
+
+// CreateAdminPromotion creates a code for any hotel or room, or for every
+// stay when neither is set. An unscoped fixed amount needs a currency.
+func (s *PromotionService) CreateAdminPromotion(ctx context.Context, adminID string, p *domain.Promotion) (*domain.Promotion, error) {
+	var hotel *domain.Hotel
+	if p.HotelID != nil || p.RoomID != nil {
+		var err error
+		if hotel, err = s.scope(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+	p.CreatedBy = adminID
+	return s.create(ctx, p, hotel)
+}
+
+// ListMyPromotions returns the codes the caller created.
+func (s *PromotionService) ListMyPromotions(ctx context.Context, ownerID string, page, limit int) ([]*domain.Promotion, int, error) {
+	page, limit = normalizePagination(page, limit)
+	return s.promoRepo.ListPromotions(ctx, ownerID, page, limit)
+}
+
+// ListAllPromotions returns every code.
+func (s *PromotionService) ListAllPromotions(ctx context.Context, page, limit int) ([]*domain.Promotion, int, error) {
+	page, limit = normalizePagination(page, limit)
+	return s.promoRepo.ListPromotions(ctx, "", page, limit)
+}
+
+// DeactivatePromotion stops a code the caller created from being redeemed.
+func (s *PromotionService) DeactivatePromotion(ctx context.Context, ownerID string, id int) error {
+	p, err := s.promoRepo.GetPromotionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if p.CreatedBy != ownerID {
+		return fmt.Errorf("caller did not create this promotion: %w", domain.ErrUnauthorized)
+	}
+	return s.promoRepo.DeactivatePromotion(ctx, id)
+}
+
+// AdminDeactivatePromotion stops any code from being redeemed.
+func (s *PromotionService) AdminDeactivatePromotion(ctx context.Context, id int) error {
+	return s.promoRepo.DeactivatePromotion(ctx, id)
+}
+
+// scope resolves the hotel a code is scoped to, filling in HotelID from
+// RoomID and checking the room belongs to the hotel when both are set.
+func (s *PromotionService) scope(ctx context.Context, p *domain.Promotion) (*domain.Hotel, error) {
+	if p.RoomID != nil {
+		room, err := s.roomRepo.GetRoomByID(ctx, *p.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if p.HotelID != nil && *p.HotelID != room.HotelID {
+			return nil, fmt.Errorf("room %d is not in hotel %d: %w", room.ID, *p.HotelID, domain.ErrBadRequest)
+		}
+		hotelID := room.HotelID
+		p.HotelID = &hotelID
+	}
+	return s.hotelRepo.GetHotelByID(ctx, *p.HotelID)
+}
+
+func (s *PromotionService) create(ctx context.Context, p *domain.Promotion, hotel *domain.Hotel) (*domain.Promotion, error) {
+	p.Code = domain.NormalizePromoCode(p.Code)
+	p.Description = strings.TrimSpace(p.Description)
+	p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+	if p.Amount != nil && p.Currency == "" && hotel != nil {
+		p.Currency = domain.NormalizeCurrency(hotel.Currency)
+	}
+	if p.Amount == nil {
+		p.Currency = ""
+	}
+	if err := validatePromotion(p); err != nil {
+		return nil, err
+	}
+
+	if err := s.promoRepo.CreatePromotion(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func validatePromotion(p *domain.Promotion) error {
+	if !promoCodePattern.MatchString(p.Code) {
+		return fmt.Errorf("promo code must be 3 to 50 letters, digits, '-' or '_': %w", domain.ErrBadRequest)
+	}
+	if (p.Percent == nil) == (p.Amount == nil) {
+		return fmt.Errorf("promotion must set exactly one of percent and amount: %w", domain.ErrBadRequest)
+	}
+	if p.Percent != nil && (*p.Percent <= 0 || *p.Percent > 100) {
+		return fmt.Errorf("promotion percent must be above 0 and at most 100: %w", domain.ErrBadRequest)
+	}
+	if p.Amount != nil {
+		if *p.Amount <= 0 {
+			return fmt.Errorf("promotion amount must be positive: %w", domain.ErrBadRequest)
+		}
+		if !domain.ValidCurrency(p.Currency) {
+			return fmt.Errorf("promotion amount needs a valid currency, got %q: %w", p.Currency, domain.ErrBadRequest)
+		}
+	}
+	if p.ValidFrom != nil && p.ValidUntil != nil && !p.ValidUntil.After(*p.ValidFrom) {
+		return fmt.Errorf("valid_until must be after valid_from: %w", domain.ErrBadRequest)
+	}
+	if p.MinNights < 0 {
+		return fmt.Errorf("min_nights must be non-negative: %w", domain.ErrBadRequest)
+	}
+	if p.MaxRedemptions != nil && *p.MaxRedemptions < 1 {
+		return fmt.Errorf("max_redemptions must be positive: %w", domain.ErrBadRequest)
+	}
+	if p.MaxPerUser != nil && *p.MaxPerUser < 1 {
+		return fmt.Errorf("max_per_user must be positive: %w", domain.ErrBadRequest)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// --- Mock PromotionRepository ---
+
+type mockPromotionRepo struct {
+	createPromotionFn     func(ctx context.Context, p *domain.Promotion) error
+	getPromotionByIDFn    func(ctx context.Context, id int) (*domain.Promotion, error)
+	getPromotionByCodeFn  func(ctx context.Context, code string) (*domain.Promotion, error)
+	listPromotionsFn      func(ctx context.Context, createdBy string, page, limit int) ([]*domain.Promotion, int, error)
+	deactivatePromotionFn func(ctx context.Context, id int) error
+	releaseRedemptionFn   func(ctx context.Context, bookingID int) error
+}
+
+func (m *mockPromotionRepo) CreatePromotion(ctx context.Context, p *domain.Promotion) error {
+	if m.createPromotionFn != nil {
+		return m.createPromotionFn(ctx, p)
+	}
+	p.ID = 1
+	p.Active = true
+	return nil
+}
+
+func (m *mockPromotionRepo) GetPromotionByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	if m.getPromotionByIDFn != nil {
+		return m.getPromotionByIDFn(ctx, id)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockPromotionRepo) GetPromotionByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	if m.getPromotionByCodeFn != nil {
+		return m.getPromotionByCodeFn(ctx, code)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockPromotionRepo) ListPromotions(ctx context.Context, createdBy string, page, limit int) ([]*domain.Promotion, int, error) {
+	if m.listPromotionsFn != nil {
+		return m.listPromotionsFn(ctx, createdBy, page, limit)
+	}
+	return []*domain.Promotion{}, 0, nil
+}
+
+func (m *mockPromotionRepo) DeactivatePromotion(ctx context.Context, id int) error {
+	if m.deactivatePromotionFn != nil {
+		return m.deactivatePromotionFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockPromotionRepo) ReleaseRedemption(ctx context.Context, bookingID int) error {
+	if m.releaseRedemptionFn != nil {
+		return m.releaseRedemptionFn(ctx, bookingID)
+	}
+	return nil
+}
+
+// promoTestRepos returns a room 5 in hotel 10, owned by owner-uuid and
+// pricing in EUR.
+func promoTestRepos() (*mockHotelRepo, *mockRoomRepo) {
+	hotelRepo := &mockHotelRepo{
+		getHotelByIDFn: func(ctx context.Context, id int) (*domain.Hotel, error) {
+			return &domain.Hotel{ID: id, OwnerID: "owner-uuid", Currency: "EUR"}, nil
+		},
+	}
+	roomRepo := &mockRoomRepo{
+		getRoomByIDFn: func(ctx context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 10}, nil
+		},
+	}
+	return hotelRepo, roomRepo
+}
+
+// --- Tests: PromotionService ---
+
+func TestPromotionService_CreatePromotion_Success(t *testing.T) {
+	var stored *domain.Promotion
+	repo := &mockPromotionRepo{
+		createPromotionFn: func(_ context.Context, p *domain.Promotion) error {
+			stored = p
+			return nil
+		},
+	}
+	hotelRepo, roomRepo := promoTestRepos()
+	svc := service.NewPromotionService(repo, hotelRepo, roomRepo)
+
+	hotelID, roomID, amount := 10, 5, int64(1500)
+	_, err := svc.CreatePromotion(context.Background(), "owner-uuid", &domain.Promotion{
+		Code:    " summer-15 ",
+		HotelID: &hotelID,
+		RoomID:  &roomID,
+		Amount:  &amount,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Code != "SUMMER-15" || stored.CreatedBy != "owner-uuid" {
+		t.Errorf("expected normalized code created by the owner, got %q by %q", stored.Code, stored.CreatedBy)
+	}
+	if stored.Currency != "EUR" {
+		t.Errorf("expected fixed amount in the hotel's currency, got %q", stored.Currency)
+	}
+}
+
+func TestPromotionService_CreatePromotion_RequiresOwnedHotel(t *testing.T) {
+	pct, hotelID := 10.0, 10
+	hotelRepo, roomRepo := promoTestRepos()
+	svc := service.NewPromotionService(&mockPromotionRepo{}, hotelRepo, roomRepo)
+
+	_, err := svc.CreatePromotion(context.Background(), "owner-uuid", &domain.Promotion{Code: "ANYWHERE", Percent: &pct})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest without a hotel, got %v", err)
+	}
+
+	_, err = svc.CreatePromotion(context.Background(), "someone-else", &domain.Promotion{Code: "MINE", HotelID: &hotelID, Percent: &pct})
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for another owner's hotel, got %v", err)
+	}
+}
+
+func TestPromotionService_CreatePromotion_RoomOfAnotherHotel(t *testing.T) {
+	pct, hotelID, roomID := 10.0, 11, 5
+	hotelRepo, roomRepo := promoTestRepos()
+	svc := service.NewPromotionService(&mockPromotionRepo{}, hotelRepo, roomRepo)
+
+	_, err := svc.CreatePromotion(context.Background(), "owner-uuid", &domain.Promotion{
+		Code: "ROOM5", HotelID: &hotelID, RoomID: &roomID, Percent: &pct,
+	})
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestPromotionService_CreateAdminPromotion_Invalid(t *testing.T) {
+	pct, amount := 10.0, int64(500)
+	from := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	until := from.AddDate(0, 0, -1)
+	tests := []struct {
+		name  string
+		promo *domain.Promotion
+	}{
+		{"bad code", &domain.Promotion{Code: "a!", Percent: &pct}},
+		{"percent and amount", &domain.Promotion{Code: "BOTH", Percent: &pct, Amount: &amount, Currency: "USD"}},
+		{"unscoped amount without currency", &domain.Promotion{Code: "FIVE", Amount: &amount}},
+		{"window ends before it starts", &domain.Promotion{Code: "BACKWARDS", Percent: &pct, ValidFrom: &from, ValidUntil: &until}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hotelRepo, roomRepo := promoTestRepos()
+			svc := service.NewPromotionService(&mockPromotionRepo{}, hotelRepo, roomRepo)
+			_, err := svc.CreateAdminPromotion(context.Background(), "admin-uuid", tt.promo)
+			if !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPromotionService_CreateAdminPromotion_Unscoped(t *testing.T) {
+	amount := int64(500)
+	hotelRepo, roomRepo := promoTestRepos()
+	svc := service.NewPromotionService(&mockPromotionRepo{}, hotelRepo, roomRepo)
+
+	p, err := svc.CreateAdminPromotion(context.Background(), "admin-uuid", &domain.Promotion{Code: "WELCOME5", Amount: &amount, Currency: "usd"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.HotelID != nil || p.Currency != "USD" {
+		t.Errorf("expected an unscoped USD code, got hotel %v currency %q", p.HotelID, p.Currency)
+	}
+}
+
+func TestPromotionService_DeactivatePromotion_NotCreator(t *testing.T) {
+	repo := &mockPromotionRepo{
+		getPromotionByIDFn: func(_ context.Context, id int) (*domain.Promotion, error) {
+			return &domain.Promotion{ID: id, CreatedBy: "owner-uuid"}, nil
+		},
+		deactivatePromotionFn: func(_ context.Context, _ int) error {
+			t.Fatal("promotion should not be deactivated")
+			return nil
+		},
+	}
+	hotelRepo, roomRepo := promoTestRepos()
+	svc := service.NewPromotionService(repo, hotelRepo, roomRepo)
+
+	if err := svc.DeactivatePromotion(context.Background(), "someone-else", 3); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
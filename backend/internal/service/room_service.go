@@ -13,7 +13,9 @@ type CreateRoomInput struct {
 	Name          string
 	Description   string
 	Capacity      int
-	PricePerNight float64
+	MaxExtraBeds  int
+	ExtraGuestFee int64
+	PricePerNight int64
 	Amenities     []string
 	Images        []string
 }
@@ -23,21 +25,37 @@ type UpdateRoomInput struct {
 	Name          string
 	Description   string
 	Capacity      int
-	PricePerNight float64
+	MaxExtraBeds  int
+	ExtraGuestFee int64
+	PricePerNight int64
 	Amenities     []string
 	Images        []string
 	IsActive      bool
 }
 
+// RoomOption configures a RoomService.
+type RoomOption func(*RoomService)
+
+// WithRoomAvailabilityInvalidator drops cached hotel availability whenever
+// rooms are added, repriced or removed.
+func WithRoomAvailabilityInvalidator(availability AvailabilityInvalidator) RoomOption {
+	return func(s *RoomService) { s.availability = availability }
+}
+
 // RoomService handles room business logic.
 type RoomService struct {
-	roomRepo  repository.RoomRepository
-	hotelRepo repository.HotelRepository
+	roomRepo     repository.RoomRepository
+	hotelRepo    repository.HotelRepository
+	availability AvailabilityInvalidator // optional
 }
 
 // NewRoomService creates a new RoomService.
-func NewRoomService(roomRepo repository.RoomRepository, hotelRepo repository.HotelRepository) *RoomService {
-	return &RoomService{roomRepo: roomRepo, hotelRepo: hotelRepo}
+func NewRoomService(roomRepo repository.RoomRepository, hotelRepo repository.HotelRepository, opts ...RoomOption) *RoomService {
+	s := &RoomService{roomRepo: roomRepo, hotelRepo: hotelRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CreateRoom validates ownership then creates a room under the given hotel.
@@ -48,6 +66,9 @@ func (s *RoomService) CreateRoom(ctx context.Context, ownerID string, input Crea
 	if input.PricePerNight < 0 {
 		return nil, fmt.Errorf("price_per_night must be non-negative: %w", domain.ErrBadRequest)
 	}
+	if err := validateOccupancy(input.Capacity, input.MaxExtraBeds, input.ExtraGuestFee); err != nil {
+		return nil, err
+	}
 
 	hotel, err := s.hotelRepo.GetHotelByID(ctx, input.HotelID)
 	if err != nil {
@@ -63,13 +84,20 @@ func (s *RoomService) CreateRoom(ctx context.Context, ownerID string, input Crea
 		Name:          input.Name,
 		Description:   input.Description,
 		Capacity:      input.Capacity,
+		MaxExtraBeds:  input.MaxExtraBeds,
+		ExtraGuestFee: input.ExtraGuestFee,
 		PricePerNight: input.PricePerNight,
 		Amenities:     input.Amenities,
 		Images:        input.Images,
 		IsActive:      true,
 	}
 
-	return s.roomRepo.CreateRoom(ctx, room)
+	created, err := s.roomRepo.CreateRoom(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, input.HotelID)
+	return created, nil
 }
 
 // GetRoomByID returns a room by its ID.
@@ -84,6 +112,10 @@ func (s *RoomService) ListRoomsByHotel(ctx context.Context, hotelID int) ([]*dom
 
 // UpdateRoom updates a room after verifying the caller owns the parent hotel.
 func (s *RoomService) UpdateRoom(ctx context.Context, roomID int, ownerID string, input UpdateRoomInput) (*domain.Room, error) {
+	if err := validateOccupancy(input.Capacity, input.MaxExtraBeds, input.ExtraGuestFee); err != nil {
+		return nil, err
+	}
+
 	room, err := s.roomRepo.GetRoomByID(ctx, roomID)
 	if err != nil {
 		return nil, err
@@ -105,13 +137,20 @@ func (s *RoomService) UpdateRoom(ctx context.Context, roomID int, ownerID string
 		Name:          input.Name,
 		Description:   input.Description,
 		Capacity:      input.Capacity,
+		MaxExtraBeds:  input.MaxExtraBeds,
+		ExtraGuestFee: input.ExtraGuestFee,
 		PricePerNight: input.PricePerNight,
 		Amenities:     input.Amenities,
 		Images:        input.Images,
 		IsActive:      input.IsActive,
 	}
 
-	return s.roomRepo.UpdateRoom(ctx, updated)
+	result, err := s.roomRepo.UpdateRoom(ctx, updated)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, room.HotelID)
+	return result, nil
 }
 
 // DeleteRoom soft-deletes a room by setting is_active = false.
@@ -131,5 +170,29 @@ func (s *RoomService) DeleteRoom(ctx context.Context, roomID int, ownerID string
 		return fmt.Errorf("caller does not own this hotel: %w", domain.ErrUnauthorized)
 	}
 
-	return s.roomRepo.DeleteRoom(ctx, roomID, room.HotelID)
+	if err := s.roomRepo.DeleteRoom(ctx, roomID, room.HotelID); err != nil {
+		return err
+	}
+	s.invalidate(ctx, room.HotelID)
+	return nil
+}
+
+// invalidate drops the cached availability of a hotel.
+func (s *RoomService) invalidate(ctx context.Context, hotelID int) {
+	if s.availability == nil {
+		return
+	}
+	_ = s.availability.InvalidateHotel(ctx, hotelID) // best-effort
+}
+
+// validateOccupancy checks a room's capacity and extra-bed settings. Extra
+// beds need a capacity, since an unlimited room has nothing to add to.
+func validateOccupancy(capacity, maxExtraBeds int, extraGuestFee int64) error {
+	if capacity < 0 || maxExtraBeds < 0 || extraGuestFee < 0 {
+		return fmt.Errorf("capacity, max_extra_beds and extra_guest_fee must be non-negative: %w", domain.ErrBadRequest)
+	}
+	if maxExtraBeds > 0 && capacity == 0 {
+		return fmt.Errorf("max_extra_beds needs a capacity: %w", domain.ErrBadRequest)
+	}
+	return nil
 }
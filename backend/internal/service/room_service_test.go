@@ -16,6 +16,7 @@ type mockRoomRepo struct {
 	getRoomByIDFn     func(ctx context.Context, id int) (*domain.Room, error)
 	listRoomsByHotelFn func(ctx context.Context, hotelID int) ([]*domain.Room, error)
 	updateRoomFn      func(ctx context.Context, room *domain.Room) (*domain.Room, error)
+	setOverbookingFn  func(ctx context.Context, roomID int, overbooking domain.Overbooking) error
 	deleteRoomFn      func(ctx context.Context, id int, hotelID int) error
 }
 
@@ -35,6 +36,13 @@ func (m *mockRoomRepo) UpdateRoom(ctx context.Context, room *domain.Room) (*doma
 	return m.updateRoomFn(ctx, room)
 }
 
+func (m *mockRoomRepo) SetOverbooking(ctx context.Context, roomID int, overbooking domain.Overbooking) error {
+	if m.setOverbookingFn != nil {
+		return m.setOverbookingFn(ctx, roomID, overbooking)
+	}
+	return nil
+}
+
 func (m *mockRoomRepo) DeleteRoom(ctx context.Context, id int, hotelID int) error {
 	return m.deleteRoomFn(ctx, id, hotelID)
 }
@@ -65,7 +73,7 @@ func TestRoomService_CreateRoom_Success(t *testing.T) {
 		Name:          "Deluxe King",
 		Description:   "Spacious room",
 		Capacity:      2,
-		PricePerNight: 150.0,
+		PricePerNight: 150,
 	}
 
 	room, err := svc.CreateRoom(context.Background(), ownerID, input)
@@ -144,6 +152,20 @@ func TestRoomService_CreateRoom_RequiresPositivePrice(t *testing.T) {
 	}
 }
 
+func TestRoomService_CreateRoom_ExtraBedsNeedCapacity(t *testing.T) {
+	svc := service.NewRoomService(&mockRoomRepo{}, &mockHotelRepo{})
+
+	_, err := svc.CreateRoom(context.Background(), "owner", service.CreateRoomInput{
+		HotelID:      1,
+		Name:         "Room",
+		MaxExtraBeds: 1,
+	})
+
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for extra beds without capacity, got %v", err)
+	}
+}
+
 // --- Tests: GetRoomByID ---
 
 func TestRoomService_GetRoomByID_ReturnsRoom(t *testing.T) {
@@ -227,7 +249,7 @@ func TestRoomService_UpdateRoom_SuccessForOwner(t *testing.T) {
 	}
 	svc := service.NewRoomService(roomRepo, hotelRepo)
 
-	input := service.UpdateRoomInput{Name: "New Name", PricePerNight: 200.0}
+	input := service.UpdateRoomInput{Name: "New Name", PricePerNight: 200}
 	result, err := svc.UpdateRoom(context.Background(), 5, ownerID, input)
 
 	if err != nil {
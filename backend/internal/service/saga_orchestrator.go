@@ -5,31 +5,60 @@ import (
 	"booking-app/internal/repository"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // SagaOrchestratorInterface defines the contract for the payment saga FSM.
 type SagaOrchestratorInterface interface {
-	// StartCheckout initiates the payment saga for a booking.
-	StartCheckout(ctx context.Context, bookingID int, userID string) (*domain.Payment, error)
+	// StartCheckout initiates the payment saga for a booking, charging
+	// paymentMethod (a card token).
+	StartCheckout(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error)
 	// HandlePaymentSuccess transitions booking to confirmed state.
 	HandlePaymentSuccess(ctx context.Context, paymentID string) error
+	// HandleLateSuccess records a gateway success for a timed-out payment and
+	// gives the money back if the booking can no longer be confirmed.
+	HandleLateSuccess(ctx context.Context, paymentID string, status domain.PaymentStatus, gatewayRef string) error
 	// HandlePaymentFailure marks booking as failed and restores inventory.
 	HandlePaymentFailure(ctx context.Context, paymentID string, reason string) error
 	// HandlePaymentTimeout cancels booking and restores inventory.
 	HandlePaymentTimeout(ctx context.Context, paymentID string) error
+	// StartTopUp charges the extra cost of a modified, already paid booking.
+	StartTopUp(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Payment, error)
+	// StartPartialRefund pays back the saving of a modified, already paid booking.
+	StartPartialRefund(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Refund, error)
+	// StartRefund records a pending refund against a succeeded charge and asks
+	// the gateway to send it.
+	StartRefund(ctx context.Context, paymentID string, bookingID int, amount int64, reason string) (*domain.Refund, error)
+	// HandleRefundSucceeded adds a sent refund to its payment's refunded total.
+	HandleRefundSucceeded(ctx context.Context, refundID, gatewayRef string) error
+	// HandleRefundFailed marks a refund failed so it can be retried.
+	HandleRefundFailed(ctx context.Context, refundID, reason string) error
+	// ReleaseAuthorization gives back part or all of an uncaptured payment.
+	ReleaseAuthorization(ctx context.Context, paymentID string, bookingID int, amount int64) error
+	// HandleCaptureFailed tells the guest a scheduled capture was declined.
+	HandleCaptureFailed(ctx context.Context, paymentID, reason string) error
 }
 
 // SagaBookingRepository is the minimal booking repo surface needed by the saga.
 type SagaBookingRepository interface {
 	FindBookingByID(ctx context.Context, id int) (*domain.Booking, error)
 	UpdateBookingStatus(ctx context.Context, id int, status string) error
+	ListBookingsByItinerary(ctx context.Context, itineraryID int) ([]*domain.Booking, error)
 }
 
 // InventoryRestorer restores inventory when a payment fails or times out.
 type InventoryRestorer interface {
-	RestoreInventory(ctx context.Context, roomID int, startDate, endDate time.Time) error
+	RestoreInventory(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error
+}
+
+// PromotionReleaser gives back the promo code a booking redeemed when its
+// payment fails or times out.
+type PromotionReleaser interface {
+	ReleaseRedemption(ctx context.Context, bookingID int) error
 }
 
 // NotificationSender is an optional side-effect: send a user notification after
@@ -47,13 +76,47 @@ func WithNotificationSender(n NotificationSender) SagaOption {
 	return func(s *SagaOrchestrator) { s.notifier = n }
 }
 
+// WithRefundRepository enables the refund saga.
+func WithRefundRepository(r repository.RefundRepository) SagaOption {
+	return func(s *SagaOrchestrator) { s.refundRepo = r }
+}
+
+// WithCapturePolicies defers the capture of checkouts whose cancellation
+// policy asks for it: the card is only authorized at checkout and captured by
+// the capture scheduler before check-in.
+func WithCapturePolicies(policies repository.CancellationPolicyRepository) SagaOption {
+	return func(s *SagaOrchestrator) { s.policies = policies }
+}
+
+// WithPromotionReleaser releases the promo codes of bookings whose payment
+// fails or times out, so they count against the codes' limits no more.
+func WithPromotionReleaser(r PromotionReleaser) SagaOption {
+	return func(s *SagaOrchestrator) { s.promotions = r }
+}
+
+// WithSagaTxManager commits each saga step together with the outbox events
+// it emits. Without one, every statement autocommits on its own.
+func WithSagaTxManager(tm repository.TxManager) SagaOption {
+	return func(s *SagaOrchestrator) { s.txManager = tm }
+}
+
+// WithSagaClock overrides the time source (used in tests).
+func WithSagaClock(now func() time.Time) SagaOption {
+	return func(s *SagaOrchestrator) { s.now = now }
+}
+
 // SagaOrchestrator implements the payment saga FSM.
 type SagaOrchestrator struct {
 	bookingRepo       SagaBookingRepository
 	payRepo           repository.PaymentRepository
 	outboxRepo        repository.OutboxRepository
 	inventoryRestorer InventoryRestorer
-	notifier          NotificationSender // optional
+	notifier          NotificationSender                      // optional
+	refundRepo        repository.RefundRepository             // required for refunds
+	policies          repository.CancellationPolicyRepository // optional
+	promotions        PromotionReleaser                       // optional
+	txManager         repository.TxManager                    // optional
+	now               func() time.Time
 }
 
 // NewSagaOrchestrator creates a new SagaOrchestrator.
@@ -69,6 +132,7 @@ func NewSagaOrchestrator(
 		payRepo:           payRepo,
 		outboxRepo:        outboxRepo,
 		inventoryRestorer: inventoryRestorer,
+		now:               time.Now,
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -81,7 +145,18 @@ func NewSagaOrchestrator(
 //  2. Creates a Payment record (status=pending).
 //  3. Creates an outbox event (BookingPaymentInitiated).
 //  4. Updates booking status to awaiting_payment.
-func (s *SagaOrchestrator) StartCheckout(ctx context.Context, bookingID int, userID string) (*domain.Payment, error) {
+//
+// Steps 2 to 4 commit together, so a checkout never leaves a payment without
+// its event or a booking awaiting a payment nobody will process.
+//
+// When the booking is a leg of an itinerary, the whole itinerary is checked
+// out: every leg must be pending, the payment covers the sum of all legs and is
+// attached to the lead (lowest ID) leg, and every leg moves to awaiting_payment.
+//
+// With capture policies wired, the payment is only authorized when every leg's
+// policy defers the capture; it is then captured at the earliest leg's
+// capture time.
+func (s *SagaOrchestrator) StartCheckout(ctx context.Context, bookingID int, userID, paymentMethod string) (*domain.Payment, error) {
 	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
 	if err != nil {
 		return nil, fmt.Errorf("find booking: %w", err)
@@ -91,48 +166,100 @@ func (s *SagaOrchestrator) StartCheckout(ctx context.Context, bookingID int, use
 		return nil, fmt.Errorf("booking does not belong to caller: %w", domain.ErrForbidden)
 	}
 
-	// Guard: only pending bookings can be checked out.
-	if booking.Status != domain.BookingStatusPending {
-		return nil, fmt.Errorf("booking status %q cannot be checked out: %w", booking.Status, domain.ErrConflict)
+	legs, err := s.bookingLegs(ctx, booking)
+	if err != nil {
+		return nil, err
+	}
+
+	var amount int64
+	for _, leg := range legs {
+		// Guard: only pending bookings can be checked out.
+		if leg.Status != domain.BookingStatusPending {
+			return nil, fmt.Errorf("booking %d status %q cannot be checked out: %w", leg.ID, leg.Status, domain.ErrConflict)
+		}
+		amount += leg.ChargeTotal
 	}
+	lead := legs[0]
 
 	idempotencyKey := fmt.Sprintf("checkout:%d:%s", bookingID, userID)
+	if booking.ItineraryID != nil {
+		idempotencyKey = fmt.Sprintf("checkout:itinerary:%d:%s", *booking.ItineraryID, userID)
+	}
 
 	payment := &domain.Payment{
-		BookingID:      bookingID,
-		Amount:         booking.TotalPrice,
-		Currency:       "USD",
+		BookingID:      lead.ID,
+		Amount:         amount,
+		Currency:       legs[0].ChargeCurrency,
 		Status:         domain.PaymentStatusPending,
 		IdempotencyKey: idempotencyKey,
+		Kind:           domain.PaymentKindCharge,
+		PaymentMethod:  paymentMethod,
 	}
-
-	created, err := s.payRepo.CreatePayment(ctx, payment)
+	captureAt, err := s.captureAt(ctx, legs)
 	if err != nil {
-		return nil, fmt.Errorf("create payment: %w", err)
+		return nil, err
 	}
-
-	// Emit BookingPaymentInitiated outbox event.
-	if emitErr := s.emitInitiatedEvent(ctx, created, booking); emitErr != nil {
-		return nil, fmt.Errorf("emit initiated event: %w", emitErr)
-	}
-
-	// Transition booking to awaiting_payment.
-	if updateErr := s.bookingRepo.UpdateBookingStatus(ctx, bookingID, domain.BookingStatusAwaitingPayment); updateErr != nil {
-		return nil, fmt.Errorf("update booking status: %w", updateErr)
+	payment.CaptureAt = captureAt
+
+	var created *domain.Payment
+	err = withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		var createErr error
+		created, createErr = s.payRepo.CreatePayment(ctx, payment)
+		if createErr != nil {
+			return fmt.Errorf("create payment: %w", createErr)
+		}
+
+		// Emit BookingPaymentInitiated outbox event.
+		if emitErr := s.emitInitiatedEvent(ctx, created, lead); emitErr != nil {
+			return fmt.Errorf("emit initiated event: %w", emitErr)
+		}
+
+		// Transition every leg to awaiting_payment.
+		for _, leg := range legs {
+			if updateErr := s.bookingRepo.UpdateBookingStatus(ctx, leg.ID, domain.BookingStatusAwaitingPayment); updateErr != nil {
+				return fmt.Errorf("update booking status: %w", updateErr)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return created, nil
 }
 
-// HandlePaymentSuccess transitions booking to confirmed.
+// HandlePaymentSuccess transitions the paid booking (every leg of its
+// itinerary, if any) to confirmed. A leg that can no longer be confirmed,
+// because it was cancelled or its hold expired while the payment was in
+// flight, has its share of the payment given back instead.
 func (s *SagaOrchestrator) HandlePaymentSuccess(ctx context.Context, paymentID string) error {
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		return s.handlePaymentSuccess(ctx, paymentID)
+	})
+}
+
+func (s *SagaOrchestrator) handlePaymentSuccess(ctx context.Context, paymentID string) error {
 	payment, err := s.payRepo.GetPaymentByID(ctx, paymentID)
 	if err != nil {
 		return fmt.Errorf("get payment: %w", err)
 	}
 
-	if err := s.payRepo.UpdatePaymentStatus(ctx, paymentID, domain.PaymentStatusSucceeded, "", ""); err != nil {
-		return fmt.Errorf("update payment status: %w", err)
+	// A late success must be recorded as authorized or captured first (see
+	// HandleLateSuccess); until then nothing is held that could be given back.
+	if payment.Status == domain.PaymentStatusTimedOut {
+		return fmt.Errorf("payment %q timed out before its success was recorded: %w", paymentID, domain.ErrConflict)
+	}
+
+	// The payment service records authorized and captured payments itself;
+	// only payments it left in flight are marked succeeded here.
+	if payment.Status == domain.PaymentStatusPending || payment.Status == domain.PaymentStatusProcessing {
+		if err := s.payRepo.UpdatePaymentStatus(ctx, paymentID, domain.PaymentStatusSucceeded, "", ""); err != nil {
+			return fmt.Errorf("update payment status: %w", err)
+		}
+	}
+
+	if payment.IsAdjustment() {
+		return s.settleAdjustment(ctx, payment, true, "")
 	}
 
 	booking, err := s.bookingRepo.FindBookingByID(ctx, payment.BookingID)
@@ -140,8 +267,27 @@ func (s *SagaOrchestrator) HandlePaymentSuccess(ctx context.Context, paymentID s
 		return fmt.Errorf("find booking for confirmation: %w", err)
 	}
 
-	if err := s.bookingRepo.UpdateBookingStatus(ctx, payment.BookingID, domain.BookingStatusConfirmed); err != nil {
-		return fmt.Errorf("update booking confirmed: %w", err)
+	legs, err := s.bookingLegs(ctx, booking)
+	if err != nil {
+		return err
+	}
+
+	confirmed := 0
+	for _, leg := range legs {
+		err := s.bookingRepo.UpdateBookingStatus(ctx, leg.ID, domain.BookingStatusConfirmed)
+		if errors.Is(err, domain.ErrConflict) {
+			if err := s.compensateLeg(ctx, payment, leg); err != nil {
+				return fmt.Errorf("compensate booking %d: %w", leg.ID, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("update booking confirmed: %w", err)
+		}
+		confirmed++
+	}
+	if confirmed == 0 {
+		return nil
 	}
 
 	s.notify(ctx, booking.UserID, domain.NotificationTypeBookingConfirmed,
@@ -152,28 +298,61 @@ func (s *SagaOrchestrator) HandlePaymentSuccess(ctx context.Context, paymentID s
 	return nil
 }
 
-// HandlePaymentFailure marks booking as failed and restores inventory.
+// HandleLateSuccess records a gateway success that arrived after the payment
+// timed out, moving it to status (authorized or captured) with gatewayRef,
+// then settles it like any other success in the same transaction. The booking
+// was usually released when the payment timed out, so the authorization is
+// released or the capture refunded.
+func (s *SagaOrchestrator) HandleLateSuccess(ctx context.Context, paymentID string, status domain.PaymentStatus, gatewayRef string) error {
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		var err error
+		switch status {
+		case domain.PaymentStatusAuthorized:
+			err = s.payRepo.MarkAuthorized(ctx, paymentID, gatewayRef)
+		case domain.PaymentStatusCaptured:
+			err = s.payRepo.UpdatePaymentStatus(ctx, paymentID, status, gatewayRef, "")
+		default:
+			return fmt.Errorf("late success cannot move payment to %s: %w", status, domain.ErrBadRequest)
+		}
+		if err != nil {
+			return fmt.Errorf("record late payment success: %w", err)
+		}
+		return s.handlePaymentSuccess(ctx, paymentID)
+	})
+}
+
+// HandlePaymentFailure marks booking as failed and restores inventory and
+// promo codes for every leg covered by the payment.
 func (s *SagaOrchestrator) HandlePaymentFailure(ctx context.Context, paymentID string, reason string) error {
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		return s.handlePaymentFailure(ctx, paymentID, reason)
+	})
+}
+
+func (s *SagaOrchestrator) handlePaymentFailure(ctx context.Context, paymentID string, reason string) error {
 	payment, err := s.payRepo.GetPaymentByID(ctx, paymentID)
 	if err != nil {
 		return fmt.Errorf("get payment: %w", err)
 	}
 
 	if err := s.payRepo.UpdatePaymentStatus(ctx, paymentID, domain.PaymentStatusFailed, "", reason); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil // a stale result for a payment that settled otherwise
+		}
 		return fmt.Errorf("update payment status: %w", err)
 	}
 
+	if payment.IsAdjustment() {
+		return s.settleAdjustment(ctx, payment, false, reason)
+	}
+
 	booking, err := s.bookingRepo.FindBookingByID(ctx, payment.BookingID)
 	if err != nil {
 		return fmt.Errorf("find booking for inventory restore: %w", err)
 	}
 
-	if err := s.bookingRepo.UpdateBookingStatus(ctx, payment.BookingID, domain.BookingStatusFailed); err != nil {
-		return fmt.Errorf("update booking failed: %w", err)
-	}
-
-	if err := s.inventoryRestorer.RestoreInventory(ctx, booking.RoomID, booking.StartDate, booking.EndDate); err != nil {
-		return fmt.Errorf("restore inventory: %w", err)
+	if err := s.releaseLegs(ctx, booking, domain.BookingStatusFailed); err != nil {
+		return err
 	}
 
 	s.notify(ctx, booking.UserID, domain.NotificationTypePaymentFailed,
@@ -184,28 +363,38 @@ func (s *SagaOrchestrator) HandlePaymentFailure(ctx context.Context, paymentID s
 	return nil
 }
 
-// HandlePaymentTimeout cancels booking and restores inventory.
+// HandlePaymentTimeout cancels booking and restores inventory and promo
+// codes for every leg covered by the payment.
 func (s *SagaOrchestrator) HandlePaymentTimeout(ctx context.Context, paymentID string) error {
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		return s.handlePaymentTimeout(ctx, paymentID)
+	})
+}
+
+func (s *SagaOrchestrator) handlePaymentTimeout(ctx context.Context, paymentID string) error {
 	payment, err := s.payRepo.GetPaymentByID(ctx, paymentID)
 	if err != nil {
 		return fmt.Errorf("get payment: %w", err)
 	}
 
 	if err := s.payRepo.UpdatePaymentStatus(ctx, paymentID, domain.PaymentStatusTimedOut, "", "gateway timeout"); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil // a stale result for a payment that settled otherwise
+		}
 		return fmt.Errorf("update payment status: %w", err)
 	}
 
+	if payment.IsAdjustment() {
+		return s.settleAdjustment(ctx, payment, false, "gateway timeout")
+	}
+
 	booking, err := s.bookingRepo.FindBookingByID(ctx, payment.BookingID)
 	if err != nil {
 		return fmt.Errorf("find booking for inventory restore: %w", err)
 	}
 
-	if err := s.bookingRepo.UpdateBookingStatus(ctx, payment.BookingID, domain.BookingStatusCancelled); err != nil {
-		return fmt.Errorf("update booking cancelled: %w", err)
-	}
-
-	if err := s.inventoryRestorer.RestoreInventory(ctx, booking.RoomID, booking.StartDate, booking.EndDate); err != nil {
-		return fmt.Errorf("restore inventory: %w", err)
+	if err := s.releaseLegs(ctx, booking, domain.BookingStatusCancelled); err != nil {
+		return err
 	}
 
 	s.notify(ctx, booking.UserID, domain.NotificationTypePaymentTimedOut,
@@ -216,12 +405,502 @@ func (s *SagaOrchestrator) HandlePaymentTimeout(ctx context.Context, paymentID s
 	return nil
 }
 
+// StartTopUp creates a top-up payment for the extra cost of a modified booking
+// and emits BookingPaymentInitiated so the worker charges it like a checkout.
+// When the charge that paid for the booking is only authorized, the top-up is
+// due for capture with it rather than straight away. The booking keeps its
+// status whatever the outcome.
+func (s *SagaOrchestrator) StartTopUp(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Payment, error) {
+	return s.startAdjustment(ctx, booking, domain.PaymentKindTopUp, amount)
+}
+
+// StartPartialRefund gives the saving of a modified booking back on the charge
+// that paid for it. A captured charge is refunded. A charge that is only
+// authorized has the saving released from its authorization instead, keeping
+// its scheduled capture, and no refund is returned. The booking keeps its
+// status whatever the outcome.
+func (s *SagaOrchestrator) StartPartialRefund(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Refund, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("refund amount must be positive: %w", domain.ErrBadRequest)
+	}
+	legs, err := s.bookingLegs(ctx, booking)
+	if err != nil {
+		return nil, err
+	}
+	charge, err := s.payRepo.GetPaymentByBookingID(ctx, legs[0].ID)
+	if err != nil {
+		return nil, fmt.Errorf("find charge to refund: %w", err)
+	}
+
+	if charge.Status == domain.PaymentStatusAuthorized {
+		err := s.lowerAuthorization(ctx, charge, booking, amount)
+		if !errors.Is(err, domain.ErrConflict) {
+			return nil, err
+		}
+		// Captured in the meantime: refund instead.
+	}
+	return s.StartRefund(ctx, charge.ID, booking.ID, amount, "booking modified")
+}
+
+// lowerAuthorization releases the saving of a modified booking from the
+// authorization of its charge, which is still captured when it was due.
+func (s *SagaOrchestrator) lowerAuthorization(ctx context.Context, charge *domain.Payment, booking *domain.Booking, amount int64) error {
+	if _, err := s.payRepo.ReduceAuthorizedAmount(ctx, charge.ID, amount); err != nil {
+		return fmt.Errorf("reduce authorization: %w", err)
+	}
+	s.notify(ctx, booking.UserID, domain.NotificationTypePaymentVoided,
+		"Payment Hold Reduced",
+		fmt.Sprintf("We released %s from the hold on your card for the changes to booking #%d.", domain.FormatMoney(amount, booking.ChargeCurrency), booking.ID),
+		map[string]any{"booking_id": booking.ID, "payment_id": charge.ID})
+	return nil
+}
+
+// StartRefund starts the refund saga for part or all of a succeeded charge:
+//  1. Records a pending refund (the repository rejects refunds that would
+//     exceed what is left on the charge).
+//  2. Emits RefundRequested so the worker sends it to the gateway.
+//
+// Both steps commit together.
+//
+// bookingID is the booking the money is returned for; for an itinerary it may
+// differ from the leg the charge is attached to.
+func (s *SagaOrchestrator) StartRefund(ctx context.Context, paymentID string, bookingID int, amount int64, reason string) (*domain.Refund, error) {
+	// Partial refunds of one charge are distinct, so the key is unique per call.
+	return s.startRefund(ctx, paymentID, bookingID, amount, reason, fmt.Sprintf("refund:%s:%s", paymentID, uuid.NewString()))
+}
+
+func (s *SagaOrchestrator) startRefund(ctx context.Context, paymentID string, bookingID int, amount int64, reason, idempotencyKey string) (*domain.Refund, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("refund amount must be positive: %w", domain.ErrBadRequest)
+	}
+	if s.refundRepo == nil {
+		return nil, fmt.Errorf("refunds are not configured")
+	}
+
+	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("find booking for refund: %w", err)
+	}
+
+	var created *domain.Refund
+	err = withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		var createErr error
+		created, createErr = s.refundRepo.CreateRefund(ctx, &domain.Refund{
+			PaymentID:      paymentID,
+			BookingID:      bookingID,
+			Amount:         amount,
+			Reason:         reason,
+			IdempotencyKey: idempotencyKey,
+		})
+		if createErr != nil {
+			return fmt.Errorf("create refund: %w", createErr)
+		}
+
+		payload := domain.RefundRequestedPayload{
+			RefundID:  created.ID,
+			PaymentID: paymentID,
+			BookingID: bookingID,
+			Amount:    created.Amount,
+			Currency:  created.Currency,
+			UserID:    booking.UserID,
+		}
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal payload: %w", err)
+		}
+		if err := s.outboxRepo.CreateEvent(ctx, &domain.OutboxEvent{
+			AggregateType: "refund",
+			AggregateID:   created.ID,
+			EventType:     domain.EventTypeRefundRequested,
+			Payload:       raw,
+		}); err != nil {
+			return fmt.Errorf("emit refund requested event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// HandleRefundSucceeded records a refund the gateway sent: the refund becomes
+// succeeded and its amount is added to the payment's refunded total, moving
+// the payment to refunded or partially_refunded. Redelivered results for a
+// refund that is no longer pending are ignored.
+func (s *SagaOrchestrator) HandleRefundSucceeded(ctx context.Context, refundID, gatewayRef string) error {
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		return s.handleRefundSucceeded(ctx, refundID, gatewayRef)
+	})
+}
+
+func (s *SagaOrchestrator) handleRefundSucceeded(ctx context.Context, refundID, gatewayRef string) error {
+	refund, err := s.pendingRefund(ctx, refundID)
+	if err != nil || refund == nil {
+		return err
+	}
+
+	if err := s.refundRepo.CompleteRefund(ctx, refundID, gatewayRef); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil
+		}
+		return fmt.Errorf("complete refund: %w", err)
+	}
+
+	s.notifyRefund(ctx, refund, domain.NotificationTypeRefundSucceeded,
+		"Refund Issued",
+		fmt.Sprintf("We refunded %s for booking #%d.", domain.FormatMoney(refund.Amount, refund.Currency), refund.BookingID),
+		"")
+	return nil
+}
+
+// HandleRefundFailed marks a refund the gateway rejected as failed. Its
+// amount no longer counts against the charge, so it can be requested again.
+func (s *SagaOrchestrator) HandleRefundFailed(ctx context.Context, refundID, reason string) error {
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		return s.handleRefundFailed(ctx, refundID, reason)
+	})
+}
+
+func (s *SagaOrchestrator) handleRefundFailed(ctx context.Context, refundID, reason string) error {
+	refund, err := s.pendingRefund(ctx, refundID)
+	if err != nil || refund == nil {
+		return err
+	}
+
+	if err := s.refundRepo.FailRefund(ctx, refundID, reason); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil
+		}
+		return fmt.Errorf("fail refund: %w", err)
+	}
+
+	s.notifyRefund(ctx, refund, domain.NotificationTypeRefundFailed,
+		"Refund Failed",
+		fmt.Sprintf("The refund of %s for booking #%d did not go through: %s. Our team will follow up.", domain.FormatMoney(refund.Amount, refund.Currency), refund.BookingID, reason),
+		reason)
+	return nil
+}
+
+// ReleaseAuthorization is how a booking cancelled before its payment was
+// captured gets its money back: instead of refunding, amount is taken off the
+// authorization. When nothing is left the whole authorization is voided
+// (PaymentVoidRequested); otherwise the rest, the cancellation penalty, is
+// captured by the next capture sweep. For a leg of an itinerary the rest also
+// pays for the other legs, so it keeps its scheduled capture.
+//
+// Returns ErrConflict when the payment is no longer authorized (it was
+// captured in the meantime), in which case the caller should refund instead.
+func (s *SagaOrchestrator) ReleaseAuthorization(ctx context.Context, paymentID string, bookingID int, amount int64) error {
+	if amount <= 0 {
+		return fmt.Errorf("release amount must be positive: %w", domain.ErrBadRequest)
+	}
+	return withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		return s.releaseAuthorization(ctx, paymentID, bookingID, amount)
+	})
+}
+
+func (s *SagaOrchestrator) releaseAuthorization(ctx context.Context, paymentID string, bookingID int, amount int64) error {
+	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+	if err != nil {
+		return fmt.Errorf("find booking for release: %w", err)
+	}
+
+	reduce := s.payRepo.ReduceAuthorization
+	if booking.ItineraryID != nil {
+		reduce = s.payRepo.ReduceAuthorizedAmount
+	}
+	remaining, err := reduce(ctx, paymentID, amount)
+	if err != nil {
+		return fmt.Errorf("reduce authorization: %w", err)
+	}
+
+	data := map[string]any{"booking_id": bookingID, "payment_id": paymentID}
+	if remaining > 0 {
+		s.notify(ctx, booking.UserID, domain.NotificationTypePaymentVoided,
+			"Payment Hold Reduced",
+			fmt.Sprintf("We released %s from the hold on your card for booking #%d.", domain.FormatMoney(amount, booking.ChargeCurrency), bookingID),
+			data)
+		return nil
+	}
+
+	raw, err := json.Marshal(domain.PaymentResultPayload{
+		PaymentID: paymentID,
+		BookingID: bookingID,
+		Reason:    "booking cancelled",
+	})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	if err := s.outboxRepo.CreateEvent(ctx, &domain.OutboxEvent{
+		AggregateType: "payment",
+		AggregateID:   paymentID,
+		EventType:     domain.EventTypePaymentVoidRequested,
+		Payload:       raw,
+	}); err != nil {
+		return fmt.Errorf("emit void requested event: %w", err)
+	}
+
+	s.notify(ctx, booking.UserID, domain.NotificationTypePaymentVoided,
+		"Payment Hold Released",
+		fmt.Sprintf("Your card was not charged for booking #%d and the hold has been released.", bookingID),
+		data)
+	return nil
+}
+
+// HandleCaptureFailed tells the guest that the scheduled capture of their
+// payment was declined. The booking stays as it is; the hotel settles the
+// balance at check-in.
+func (s *SagaOrchestrator) HandleCaptureFailed(ctx context.Context, paymentID, reason string) error {
+	payment, err := s.payRepo.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		return fmt.Errorf("get payment: %w", err)
+	}
+	booking, err := s.bookingRepo.FindBookingByID(ctx, payment.BookingID)
+	if err != nil {
+		return fmt.Errorf("find booking for capture failure: %w", err)
+	}
+
+	s.notify(ctx, booking.UserID, domain.NotificationTypeCaptureFailed,
+		"Payment Could Not Be Collected",
+		fmt.Sprintf("We could not charge your card for booking #%d: %s. Please update your payment details or pay at check-in.", payment.BookingID, reason),
+		map[string]any{"booking_id": payment.BookingID, "payment_id": paymentID, "reason": reason},
+	)
+	return nil
+}
+
+// captureAt returns when a checkout of legs should be captured, or nil to
+// capture it straight away.
+func (s *SagaOrchestrator) captureAt(ctx context.Context, legs []*domain.Booking) (*time.Time, error) {
+	if s.policies == nil {
+		return nil, nil
+	}
+	now := s.now()
+	var earliest *time.Time
+	for _, leg := range legs {
+		policy, err := s.policies.GetPolicyForRoom(ctx, leg.RoomID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("get cancellation policy: %w", err)
+		}
+		at := policy.CaptureAt(leg.StartDate, now)
+		if at == nil {
+			return nil, nil
+		}
+		if earliest == nil || at.Before(*earliest) {
+			earliest = at
+		}
+	}
+	return earliest, nil
+}
+
+// pendingRefund loads a refund, returning nil when it has already settled.
+func (s *SagaOrchestrator) pendingRefund(ctx context.Context, refundID string) (*domain.Refund, error) {
+	if s.refundRepo == nil {
+		return nil, fmt.Errorf("refunds are not configured")
+	}
+	refund, err := s.refundRepo.GetRefundByID(ctx, refundID)
+	if err != nil {
+		return nil, fmt.Errorf("get refund: %w", err)
+	}
+	if refund.Status != domain.RefundStatusPending {
+		return nil, nil
+	}
+	return refund, nil
+}
+
+func (s *SagaOrchestrator) notifyRefund(ctx context.Context, refund *domain.Refund, notifType domain.NotificationType, title, message, reason string) {
+	booking, err := s.bookingRepo.FindBookingByID(ctx, refund.BookingID)
+	if err != nil {
+		return // best-effort
+	}
+	data := map[string]any{"booking_id": refund.BookingID, "payment_id": refund.PaymentID, "refund_id": refund.ID}
+	if reason != "" {
+		data["reason"] = reason
+	}
+	s.notify(ctx, booking.UserID, notifType, title, message, data)
+}
+
+func (s *SagaOrchestrator) startAdjustment(ctx context.Context, booking *domain.Booking, kind domain.PaymentKind, amount int64) (*domain.Payment, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("%s amount must be positive: %w", kind, domain.ErrBadRequest)
+	}
+
+	payment := &domain.Payment{
+		BookingID: booking.ID,
+		Amount:    amount,
+		Currency:  booking.ChargeCurrency,
+		Status:    domain.PaymentStatusPending,
+		// Each modification is a distinct adjustment, so the key is unique per call.
+		IdempotencyKey: fmt.Sprintf("%s:%d:%s", kind, booking.ID, uuid.NewString()),
+		Kind:           kind,
+	}
+	// Adjustments are charged to the card that paid for the booking, and
+	// captured with it when it is not captured yet.
+	if legs, err := s.bookingLegs(ctx, booking); err == nil {
+		if charge, err := s.payRepo.GetPaymentByBookingID(ctx, legs[0].ID); err == nil {
+			payment.PaymentMethod = charge.PaymentMethod
+			if charge.Status == domain.PaymentStatusAuthorized {
+				payment.CaptureAt = charge.CaptureAt
+			}
+		}
+	}
+
+	var created *domain.Payment
+	err := withinTx(ctx, s.txManager, func(ctx context.Context) error {
+		var createErr error
+		created, createErr = s.payRepo.CreatePayment(ctx, payment)
+		if createErr != nil {
+			return fmt.Errorf("create %s payment: %w", kind, createErr)
+		}
+
+		if emitErr := s.emitInitiatedEvent(ctx, created, booking); emitErr != nil {
+			return fmt.Errorf("emit initiated event: %w", emitErr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// settleAdjustment notifies the guest of a top-up or refund outcome. The
+// booking was already moved when the adjustment started, so nothing else
+// changes.
+func (s *SagaOrchestrator) settleAdjustment(ctx context.Context, payment *domain.Payment, succeeded bool, reason string) error {
+	booking, err := s.bookingRepo.FindBookingByID(ctx, payment.BookingID)
+	if err != nil {
+		return fmt.Errorf("find booking for %s: %w", payment.Kind, err)
+	}
+
+	data := map[string]any{"booking_id": payment.BookingID, "payment_id": payment.ID, "kind": string(payment.Kind)}
+	switch {
+	case succeeded && payment.Kind == domain.PaymentKindRefund:
+		s.notify(ctx, booking.UserID, domain.NotificationTypePaymentSucceeded,
+			"Refund Issued",
+			fmt.Sprintf("We refunded %s for the changes to booking #%d.", domain.FormatMoney(payment.Amount, payment.Currency), payment.BookingID),
+			data)
+	case succeeded && payment.Status == domain.PaymentStatusAuthorized:
+		s.notify(ctx, booking.UserID, domain.NotificationTypePaymentSucceeded,
+			"Booking Change Authorized",
+			fmt.Sprintf("We placed a hold of %s on your card for the changes to booking #%d. It is charged with the rest of your booking.", domain.FormatMoney(payment.Amount, payment.Currency), payment.BookingID),
+			data)
+	case succeeded:
+		s.notify(ctx, booking.UserID, domain.NotificationTypePaymentSucceeded,
+			"Booking Change Paid",
+			fmt.Sprintf("We charged %s for the changes to booking #%d.", domain.FormatMoney(payment.Amount, payment.Currency), payment.BookingID),
+			data)
+	default:
+		label := "payment"
+		if payment.Kind == domain.PaymentKindRefund {
+			label = "refund"
+		}
+		data["reason"] = reason
+		s.notify(ctx, booking.UserID, domain.NotificationTypePaymentFailed,
+			"Booking Change Payment Failed",
+			fmt.Sprintf("The %s of %s for booking #%d did not go through: %s.", label, domain.FormatMoney(payment.Amount, payment.Currency), payment.BookingID, reason),
+			data)
+	}
+	return nil
+}
+
+// compensateLeg gives back the share of payment charged for a leg that could
+// not be confirmed. An authorization is reduced, a captured payment refunded.
+// Nothing is done when nothing is left to give back, e.g. because the result
+// was redelivered after the leg was compensated.
+func (s *SagaOrchestrator) compensateLeg(ctx context.Context, payment *domain.Payment, leg *domain.Booking) error {
+	amount := min(leg.ChargeTotal, payment.Amount)
+	if amount <= 0 {
+		return nil
+	}
+
+	if payment.Status == domain.PaymentStatusAuthorized {
+		err := s.ReleaseAuthorization(ctx, payment.ID, leg.ID, amount)
+		if !errors.Is(err, domain.ErrConflict) {
+			return err
+		}
+		// Captured in the meantime: refund instead.
+	}
+
+	// One refund per leg and payment, so a redelivered result finds it.
+	key := fmt.Sprintf("compensation:%s:%d", payment.ID, leg.ID)
+	if s.refundRepo != nil {
+		_, err := s.refundRepo.GetRefundByIdempotencyKey(ctx, key)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("find compensation refund: %w", err)
+		}
+	}
+	_, err := s.startRefund(ctx, payment.ID, leg.ID, amount, "booking no longer awaiting payment", key)
+	return err
+}
+
+// bookingLegs returns every booking paid for together with booking: all legs
+// of its itinerary ordered by ID, or just the booking itself when standalone.
+func (s *SagaOrchestrator) bookingLegs(ctx context.Context, booking *domain.Booking) ([]*domain.Booking, error) {
+	if booking.ItineraryID == nil {
+		return []*domain.Booking{booking}, nil
+	}
+	legs, err := s.bookingRepo.ListBookingsByItinerary(ctx, *booking.ItineraryID)
+	if err != nil {
+		return nil, fmt.Errorf("list itinerary legs: %w", err)
+	}
+	if len(legs) == 0 {
+		return []*domain.Booking{booking}, nil
+	}
+	return legs, nil
+}
+
+// releaseLegs moves every leg paid for with booking to status, restores the
+// inventory each leg was holding and releases any promo code it redeemed.
+// Legs that already left awaiting_payment, e.g. cancelled by the guest or
+// expired by the hold reaper, released their inventory then and are skipped.
+func (s *SagaOrchestrator) releaseLegs(ctx context.Context, booking *domain.Booking, status string) error {
+	legs, err := s.bookingLegs(ctx, booking)
+	if err != nil {
+		return err
+	}
+
+	for _, leg := range legs {
+		if err := s.bookingRepo.UpdateBookingStatus(ctx, leg.ID, status); err != nil {
+			if errors.Is(err, domain.ErrConflict) {
+				continue
+			}
+			return fmt.Errorf("update booking %s: %w", status, err)
+		}
+
+		if err := s.inventoryRestorer.RestoreInventory(ctx, leg.RoomID, leg.StartDate, leg.EndDate, leg.Quantity); err != nil {
+			return fmt.Errorf("restore inventory: %w", err)
+		}
+
+		if s.promotions != nil {
+			if err := s.promotions.ReleaseRedemption(ctx, leg.ID); err != nil {
+				return fmt.Errorf("release promotion: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// withinTx runs fn in a unit of work of tm, or straight away when tm is nil.
+func withinTx(ctx context.Context, tm repository.TxManager, fn func(ctx context.Context) error) error {
+	if tm == nil {
+		return fn(ctx)
+	}
+	return tm.WithinTx(ctx, fn)
+}
+
 // notify sends a notification if a notifier is configured. Errors are non-fatal.
+// Inside a unit of work the notification waits until the work commits.
 func (s *SagaOrchestrator) notify(ctx context.Context, userID string, notifType domain.NotificationType, title, message string, data map[string]any) {
 	if s.notifier == nil {
 		return
 	}
-	_ = s.notifier.Notify(ctx, userID, notifType, title, message, data) // best-effort
+	repository.AfterCommit(ctx, func(ctx context.Context) {
+		_ = s.notifier.Notify(ctx, userID, notifType, title, message, data) // best-effort
+	})
 }
 
 func (s *SagaOrchestrator) emitInitiatedEvent(ctx context.Context, payment *domain.Payment, booking *domain.Booking) error {
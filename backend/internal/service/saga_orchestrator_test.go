@@ -4,7 +4,9 @@ import (
 	"booking-app/internal/domain"
 	"booking-app/internal/service"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -14,6 +16,7 @@ import (
 type mockSagaBookingRepo struct {
 	findBookingByIDFn    func(ctx context.Context, id int) (*domain.Booking, error)
 	updateBookingStatusFn func(ctx context.Context, id int, status string) error
+	listByItineraryFn     func(ctx context.Context, itineraryID int) ([]*domain.Booking, error)
 }
 
 func (m *mockSagaBookingRepo) FindBookingByID(ctx context.Context, id int) (*domain.Booking, error) {
@@ -24,6 +27,13 @@ func (m *mockSagaBookingRepo) UpdateBookingStatus(ctx context.Context, id int, s
 	return m.updateBookingStatusFn(ctx, id, status)
 }
 
+func (m *mockSagaBookingRepo) ListBookingsByItinerary(ctx context.Context, itineraryID int) ([]*domain.Booking, error) {
+	if m.listByItineraryFn != nil {
+		return m.listByItineraryFn(ctx, itineraryID)
+	}
+	return nil, nil
+}
+
 func makeSagaBookingRepo(overrides mockSagaBookingRepo) *mockSagaBookingRepo {
 	defaults := &mockSagaBookingRepo{
 		findBookingByIDFn: func(ctx context.Context, id int) (*domain.Booking, error) {
@@ -31,8 +41,10 @@ func makeSagaBookingRepo(overrides mockSagaBookingRepo) *mockSagaBookingRepo {
 				ID:         id,
 				UserID:     "user-1",
 				RoomID:     10,
-				TotalPrice: 200.00,
-				Status:     domain.BookingStatusPending,
+				TotalPrice:     200,
+				ChargeTotal:    200,
+				ChargeCurrency: "USD",
+				Status:         domain.BookingStatusPending,
 				StartDate:  time.Now(),
 				EndDate:    time.Now().Add(48 * time.Hour),
 			}, nil
@@ -47,22 +59,23 @@ func makeSagaBookingRepo(overrides mockSagaBookingRepo) *mockSagaBookingRepo {
 	if overrides.updateBookingStatusFn != nil {
 		defaults.updateBookingStatusFn = overrides.updateBookingStatusFn
 	}
+	defaults.listByItineraryFn = overrides.listByItineraryFn
 	return defaults
 }
 
 // --- Mock InventoryRestorer (used to restore inventory on failure/timeout) ---
 
 type mockInventoryRestorer struct {
-	restoreInventoryFn func(ctx context.Context, roomID int, startDate, endDate time.Time) error
+	restoreInventoryFn func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error
 }
 
-func (m *mockInventoryRestorer) RestoreInventory(ctx context.Context, roomID int, startDate, endDate time.Time) error {
-	return m.restoreInventoryFn(ctx, roomID, startDate, endDate)
+func (m *mockInventoryRestorer) RestoreInventory(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
+	return m.restoreInventoryFn(ctx, roomID, startDate, endDate, quantity)
 }
 
 func makeMockInventoryRestorer(overrides mockInventoryRestorer) *mockInventoryRestorer {
 	defaults := &mockInventoryRestorer{
-		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time) error {
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
 			return nil
 		},
 	}
@@ -81,7 +94,7 @@ func TestSagaOrchestrator_StartCheckout_Success(t *testing.T) {
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{})
 
 	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, inventoryRestorer)
-	payment, err := orch.StartCheckout(context.Background(), 1, "user-1")
+	payment, err := orch.StartCheckout(context.Background(), 1, "user-1", "")
 
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -108,7 +121,7 @@ func TestSagaOrchestrator_StartCheckout_BookingNotFound(t *testing.T) {
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{})
 
 	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, inventoryRestorer)
-	_, err := orch.StartCheckout(context.Background(), 999, "user-1")
+	_, err := orch.StartCheckout(context.Background(), 999, "user-1", "")
 
 	if !errors.Is(err, domain.ErrNotFound) {
 		t.Errorf("expected ErrNotFound, got %v", err)
@@ -130,7 +143,7 @@ func TestSagaOrchestrator_StartCheckout_WrongUser(t *testing.T) {
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{})
 
 	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, inventoryRestorer)
-	_, err := orch.StartCheckout(context.Background(), 1, "different-user")
+	_, err := orch.StartCheckout(context.Background(), 1, "different-user", "")
 
 	if !errors.Is(err, domain.ErrForbidden) {
 		t.Errorf("expected ErrForbidden, got %v", err)
@@ -152,7 +165,7 @@ func TestSagaOrchestrator_StartCheckout_AlreadyAwaitingPayment(t *testing.T) {
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{})
 
 	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, inventoryRestorer)
-	_, err := orch.StartCheckout(context.Background(), 1, "user-1")
+	_, err := orch.StartCheckout(context.Background(), 1, "user-1", "")
 
 	if !errors.Is(err, domain.ErrConflict) {
 		t.Errorf("expected ErrConflict for already-awaiting-payment booking, got %v", err)
@@ -172,7 +185,7 @@ func TestSagaOrchestrator_StartCheckout_BookingStatusUpdated(t *testing.T) {
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{})
 
 	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, inventoryRestorer)
-	_, err := orch.StartCheckout(context.Background(), 1, "user-1")
+	_, err := orch.StartCheckout(context.Background(), 1, "user-1", "")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -195,7 +208,7 @@ func TestSagaOrchestrator_StartCheckout_OutboxEventCreated(t *testing.T) {
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{})
 
 	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, inventoryRestorer)
-	_, err := orch.StartCheckout(context.Background(), 1, "user-1")
+	_, err := orch.StartCheckout(context.Background(), 1, "user-1", "")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -278,7 +291,7 @@ func TestSagaOrchestrator_HandlePaymentFailure_BookingMarkedFailed(t *testing.T)
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{
-		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time) error {
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
 			inventoryRestored = true
 			return nil
 		},
@@ -298,6 +311,267 @@ func TestSagaOrchestrator_HandlePaymentFailure_BookingMarkedFailed(t *testing.T)
 	}
 }
 
+func TestSagaOrchestrator_HandlePaymentFailure_RestoresBookingQuantity(t *testing.T) {
+	var gotQuantity int
+	payRepo := makePaymentRepo(mockPaymentRepo{})
+	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{
+		findBookingByIDFn: func(ctx context.Context, id int) (*domain.Booking, error) {
+			return &domain.Booking{
+				ID:        id,
+				UserID:    "user-1",
+				RoomID:    10,
+				Quantity:  3,
+				Status:    domain.BookingStatusAwaitingPayment,
+				StartDate: time.Now(),
+				EndDate:   time.Now().Add(48 * time.Hour),
+			}, nil
+		},
+	})
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
+	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
+			gotQuantity = quantity
+			return nil
+		},
+	})
+
+	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, inventoryRestorer)
+	if err := orch.HandlePaymentFailure(context.Background(), "pay-id", "card declined"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotQuantity != 3 {
+		t.Errorf("expected inventory restore for 3 units, got %d", gotQuantity)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentFailure_ReleasesPromoCode(t *testing.T) {
+	var released []int
+	payRepo := makePaymentRepo(mockPaymentRepo{})
+	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{})
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
+	promoRepo := &mockPromotionRepo{
+		releaseRedemptionFn: func(ctx context.Context, bookingID int) error {
+			released = append(released, bookingID)
+			return nil
+		},
+	}
+
+	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, makeMockInventoryRestorer(mockInventoryRestorer{}),
+		service.WithPromotionReleaser(promoRepo))
+	if err := orch.HandlePaymentFailure(context.Background(), "pay-id", "card declined"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(released) != 1 || released[0] != 1 {
+		t.Errorf("expected the redemption of booking 1 to be released, got %v", released)
+	}
+}
+
+// itineraryLegs returns three pending legs of itinerary 9 for saga tests.
+func itineraryLegs() []*domain.Booking {
+	itineraryID := 9
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	// Legs are priced in baht and charged in dollars.
+	leg := func(id, roomID, quantity int, totalTHB, chargeUSD int64, start, end time.Time) *domain.Booking {
+		return &domain.Booking{ID: id, UserID: "user-1", RoomID: roomID, Quantity: quantity,
+			TotalPrice: totalTHB, Currency: "THB", ChargeTotal: chargeUSD, ChargeCurrency: "USD", FxRate: 0.0275,
+			Status: domain.BookingStatusPending, StartDate: start, EndDate: end, ItineraryID: &itineraryID}
+	}
+	return []*domain.Booking{
+		leg(11, 10, 1, 727300, 20000, start, start.AddDate(0, 0, 2)),
+		leg(12, 20, 2, 1090900, 30000, start.AddDate(0, 0, 2), start.AddDate(0, 0, 3)),
+		leg(13, 30, 1, 545500, 15000, start.AddDate(0, 0, 3), start.AddDate(0, 0, 4)),
+	}
+}
+
+func makeItineraryBookingRepo(legs []*domain.Booking, statuses map[int]string) *mockSagaBookingRepo {
+	return makeSagaBookingRepo(mockSagaBookingRepo{
+		findBookingByIDFn: func(ctx context.Context, id int) (*domain.Booking, error) {
+			for _, leg := range legs {
+				if leg.ID == id {
+					return leg, nil
+				}
+			}
+			return nil, domain.ErrNotFound
+		},
+		updateBookingStatusFn: func(ctx context.Context, id int, status string) error {
+			statuses[id] = status
+			return nil
+		},
+		listByItineraryFn: func(ctx context.Context, itineraryID int) ([]*domain.Booking, error) {
+			return legs, nil
+		},
+	})
+}
+
+func TestSagaOrchestrator_StartCheckout_ItineraryChargesAllLegsOnce(t *testing.T) {
+	legs := itineraryLegs()
+	statuses := map[int]string{}
+	var created []*domain.Payment
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		createPaymentFn: func(ctx context.Context, p *domain.Payment) (*domain.Payment, error) {
+			result := *p
+			result.ID = "pay-itin"
+			created = append(created, &result)
+			return &result, nil
+		},
+	})
+
+	orch := service.NewSagaOrchestrator(makeItineraryBookingRepo(legs, statuses), payRepo,
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	// Checking out any leg checks out the whole itinerary.
+	payment, err := orch.StartCheckout(context.Background(), 12, "user-1", "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(created) != 1 {
+		t.Fatalf("expected exactly one payment, got %d", len(created))
+	}
+	if payment.Amount != 65000 || payment.Currency != "USD" {
+		t.Errorf("expected 65000 USD (sum of leg charges), got %d %s", payment.Amount, payment.Currency)
+	}
+	if payment.BookingID != 11 {
+		t.Errorf("expected payment attached to lead leg 11, got %d", payment.BookingID)
+	}
+	if payment.IdempotencyKey != "checkout:itinerary:9:user-1" {
+		t.Errorf("unexpected idempotency key %q", payment.IdempotencyKey)
+	}
+	for _, leg := range legs {
+		if statuses[leg.ID] != domain.BookingStatusAwaitingPayment {
+			t.Errorf("leg %d: expected awaiting_payment, got %q", leg.ID, statuses[leg.ID])
+		}
+	}
+}
+
+func TestSagaOrchestrator_StartCheckout_ItineraryRejectsNonPendingLeg(t *testing.T) {
+	legs := itineraryLegs()
+	legs[2].Status = domain.BookingStatusCancelled
+	statuses := map[int]string{}
+
+	orch := service.NewSagaOrchestrator(makeItineraryBookingRepo(legs, statuses), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	_, err := orch.StartCheckout(context.Background(), 11, "user-1", "")
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if len(statuses) != 0 {
+		t.Errorf("expected no status changes, got %v", statuses)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentFailure_RestoresEveryItineraryLeg(t *testing.T) {
+	legs := itineraryLegs()
+	statuses := map[int]string{}
+	restored := map[int]int{}
+	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
+			restored[roomID] = quantity
+			return nil
+		},
+	})
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 11, Amount: 650, Status: domain.PaymentStatusPending}, nil
+		},
+	})
+
+	orch := service.NewSagaOrchestrator(makeItineraryBookingRepo(legs, statuses), payRepo,
+		makeOutboxRepo(mockOutboxRepo{}), inventoryRestorer)
+
+	if err := orch.HandlePaymentFailure(context.Background(), "pay-itin", "card declined"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, leg := range legs {
+		if statuses[leg.ID] != domain.BookingStatusFailed {
+			t.Errorf("leg %d: expected failed, got %q", leg.ID, statuses[leg.ID])
+		}
+		if restored[leg.RoomID] != leg.Quantity {
+			t.Errorf("room %d: expected %d units restored, got %d", leg.RoomID, leg.Quantity, restored[leg.RoomID])
+		}
+	}
+}
+
+func TestSagaOrchestrator_StartTopUp_CreatesTopUpPayment(t *testing.T) {
+	var events []*domain.OutboxEvent
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		outboxRepo, makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	payment, err := orch.StartTopUp(context.Background(), &domain.Booking{ID: 3, UserID: "user-1"}, 75)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if payment.Kind != domain.PaymentKindTopUp || payment.Amount != 75 || payment.BookingID != 3 {
+		t.Errorf("unexpected payment %+v", payment)
+	}
+	if len(events) != 1 || events[0].EventType != domain.EventTypeBookingPaymentInitiated {
+		t.Errorf("expected one BookingPaymentInitiated event, got %+v", events)
+	}
+}
+
+func TestSagaOrchestrator_StartPartialRefund_RejectsNonPositiveAmount(t *testing.T) {
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	_, err := orch.StartPartialRefund(context.Background(), &domain.Booking{ID: 3}, 0)
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentFailure_TopUpLeavesBookingAlone(t *testing.T) {
+	statusChanged, restored := false, false
+	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{
+		findBookingByIDFn: func(ctx context.Context, id int) (*domain.Booking, error) {
+			return &domain.Booking{ID: id, UserID: "user-1", Status: domain.BookingStatusConfirmed}, nil
+		},
+		updateBookingStatusFn: func(ctx context.Context, id int, status string) error {
+			statusChanged = true
+			return nil
+		},
+	})
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 3, Amount: 75, Kind: domain.PaymentKindTopUp}, nil
+		},
+	})
+	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
+			restored = true
+			return nil
+		},
+	})
+	var notifType domain.NotificationType
+	notifier := makeMockNotificationSender(mockNotificationSender{
+		notifyFn: func(ctx context.Context, userID string, nt domain.NotificationType, title, message string, data map[string]any) error {
+			notifType = nt
+			return nil
+		},
+	})
+
+	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, makeOutboxRepo(mockOutboxRepo{}), inventoryRestorer,
+		service.WithNotificationSender(notifier))
+
+	if err := orch.HandlePaymentFailure(context.Background(), "pay-topup", "card declined"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if statusChanged {
+		t.Error("a failed top-up must not change the booking status")
+	}
+	if restored {
+		t.Error("a failed top-up must not restore inventory")
+	}
+	if notifType != domain.NotificationTypePaymentFailed {
+		t.Errorf("expected payment_failed notification, got %q", notifType)
+	}
+}
+
 // --- Tests: SagaOrchestrator.HandlePaymentTimeout ---
 
 func TestSagaOrchestrator_HandlePaymentTimeout_BookingCancelled(t *testing.T) {
@@ -320,7 +594,7 @@ func TestSagaOrchestrator_HandlePaymentTimeout_BookingCancelled(t *testing.T) {
 	})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{
-		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time) error {
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
 			inventoryRestored = true
 			return nil
 		},
@@ -399,7 +673,7 @@ func TestSagaOrchestrator_HandlePaymentFailure_RestoreInventoryError(t *testing.
 	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{
-		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time) error {
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
 			return domain.ErrInternal
 		},
 	})
@@ -471,7 +745,7 @@ func TestSagaOrchestrator_HandlePaymentTimeout_RestoreInventoryError(t *testing.
 	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{})
 	outboxRepo := makeOutboxRepo(mockOutboxRepo{})
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{
-		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time) error {
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
 			return domain.ErrInternal
 		},
 	})
@@ -679,7 +953,7 @@ func TestSagaOrchestrator_StartCheckout_CreatePaymentError(t *testing.T) {
 	inventoryRestorer := makeMockInventoryRestorer(mockInventoryRestorer{})
 
 	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo, inventoryRestorer)
-	_, err := orch.StartCheckout(context.Background(), 1, "user-1")
+	_, err := orch.StartCheckout(context.Background(), 1, "user-1", "")
 
 	if err == nil {
 		t.Error("expected error when create payment fails")
@@ -703,3 +977,958 @@ func TestSagaOrchestrator_HandlePaymentSuccess_UpdateStatusError(t *testing.T) {
 		t.Error("expected error when update payment status fails")
 	}
 }
+
+// --- Mock RefundRepository ---
+
+type mockRefundRepo struct {
+	createRefundFn   func(ctx context.Context, r *domain.Refund) (*domain.Refund, error)
+	getRefundByIDFn  func(ctx context.Context, id string) (*domain.Refund, error)
+	getRefundByKeyFn func(ctx context.Context, key string) (*domain.Refund, error)
+	listByPaymentFn  func(ctx context.Context, paymentID string) ([]*domain.Refund, error)
+	completeRefundFn func(ctx context.Context, id, gatewayRef string) error
+	failRefundFn     func(ctx context.Context, id, reason string) error
+}
+
+func (m *mockRefundRepo) CreateRefund(ctx context.Context, r *domain.Refund) (*domain.Refund, error) {
+	return m.createRefundFn(ctx, r)
+}
+
+func (m *mockRefundRepo) GetRefundByID(ctx context.Context, id string) (*domain.Refund, error) {
+	return m.getRefundByIDFn(ctx, id)
+}
+
+func (m *mockRefundRepo) GetRefundByIdempotencyKey(ctx context.Context, key string) (*domain.Refund, error) {
+	return m.getRefundByKeyFn(ctx, key)
+}
+
+func (m *mockRefundRepo) ListRefundsByPayment(ctx context.Context, paymentID string) ([]*domain.Refund, error) {
+	return m.listByPaymentFn(ctx, paymentID)
+}
+
+func (m *mockRefundRepo) CompleteRefund(ctx context.Context, id, gatewayRef string) error {
+	return m.completeRefundFn(ctx, id, gatewayRef)
+}
+
+func (m *mockRefundRepo) FailRefund(ctx context.Context, id, reason string) error {
+	return m.failRefundFn(ctx, id, reason)
+}
+
+func makeRefundRepo(overrides mockRefundRepo) *mockRefundRepo {
+	defaults := &mockRefundRepo{
+		createRefundFn: func(ctx context.Context, r *domain.Refund) (*domain.Refund, error) {
+			result := *r
+			result.ID = "ref-uuid-1"
+			result.Currency = "USD"
+			result.Status = domain.RefundStatusPending
+			return &result, nil
+		},
+		getRefundByIDFn: func(ctx context.Context, id string) (*domain.Refund, error) {
+			return &domain.Refund{
+				ID:        id,
+				PaymentID: "pay-1",
+				BookingID: 1,
+				Amount:    50,
+				Currency:  "USD",
+				Status:    domain.RefundStatusPending,
+			}, nil
+		},
+		getRefundByKeyFn: func(ctx context.Context, key string) (*domain.Refund, error) {
+			return &domain.Refund{
+				ID:             "ref-uuid-1",
+				PaymentID:      "pay-1",
+				BookingID:      1,
+				Amount:         50,
+				Currency:       "USD",
+				Status:         domain.RefundStatusPending,
+				IdempotencyKey: key,
+			}, nil
+		},
+		listByPaymentFn: func(ctx context.Context, paymentID string) ([]*domain.Refund, error) {
+			return []*domain.Refund{}, nil
+		},
+		completeRefundFn: func(ctx context.Context, id, gatewayRef string) error {
+			return nil
+		},
+		failRefundFn: func(ctx context.Context, id, reason string) error {
+			return nil
+		},
+	}
+	if overrides.createRefundFn != nil {
+		defaults.createRefundFn = overrides.createRefundFn
+	}
+	if overrides.getRefundByIDFn != nil {
+		defaults.getRefundByIDFn = overrides.getRefundByIDFn
+	}
+	if overrides.getRefundByKeyFn != nil {
+		defaults.getRefundByKeyFn = overrides.getRefundByKeyFn
+	}
+	if overrides.listByPaymentFn != nil {
+		defaults.listByPaymentFn = overrides.listByPaymentFn
+	}
+	if overrides.completeRefundFn != nil {
+		defaults.completeRefundFn = overrides.completeRefundFn
+	}
+	if overrides.failRefundFn != nil {
+		defaults.failRefundFn = overrides.failRefundFn
+	}
+	return defaults
+}
+
+// --- Tests: refund saga ---
+
+func TestSagaOrchestrator_StartRefund_RecordsRefundAndEmitsEvent(t *testing.T) {
+	var created *domain.Refund
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(_ context.Context, r *domain.Refund) (*domain.Refund, error) {
+			created = r
+			result := *r
+			result.ID = "ref-1"
+			result.Status = domain.RefundStatusPending
+			return &result, nil
+		},
+	})
+	var events []*domain.OutboxEvent
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(_ context.Context, event *domain.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		outboxRepo, makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	refund, err := orch.StartRefund(context.Background(), "pay-1", 7, 80, "booking cancelled")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if refund.ID != "ref-1" || created.PaymentID != "pay-1" || created.BookingID != 7 || created.Amount != 80 {
+		t.Errorf("unexpected refund %+v", created)
+	}
+	if created.IdempotencyKey == "" {
+		t.Error("expected an idempotency key on the refund")
+	}
+	if len(events) != 1 || events[0].EventType != domain.EventTypeRefundRequested || events[0].AggregateID != "ref-1" {
+		t.Fatalf("expected one RefundRequested event for ref-1, got %+v", events)
+	}
+	var payload domain.RefundRequestedPayload
+	if err := json.Unmarshal(events[0].Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if payload.RefundID != "ref-1" || payload.Amount != 80 || payload.UserID != "user-1" {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+}
+
+func TestSagaOrchestrator_StartRefund_ExceedingChargeRejected(t *testing.T) {
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(_ context.Context, _ *domain.Refund) (*domain.Refund, error) {
+			return nil, fmt.Errorf("refund exceeds charge: %w", domain.ErrConflict)
+		},
+	})
+	eventEmitted := false
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(_ context.Context, _ *domain.OutboxEvent) error {
+			eventEmitted = true
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		outboxRepo, makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	_, err := orch.StartRefund(context.Background(), "pay-1", 7, 500, "booking cancelled")
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if eventEmitted {
+		t.Error("no event must be emitted for a rejected refund")
+	}
+}
+
+func TestSagaOrchestrator_StartPartialRefund_RefundsOriginalCharge(t *testing.T) {
+	var refundedPayment string
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(_ context.Context, r *domain.Refund) (*domain.Refund, error) {
+			refundedPayment = r.PaymentID
+			result := *r
+			result.ID = "ref-2"
+			return &result, nil
+		},
+	})
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			return &domain.Payment{ID: "pay-orig", BookingID: bookingID, Amount: 300, Status: domain.PaymentStatusSucceeded}, nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), payRepo,
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	refund, err := orch.StartPartialRefund(context.Background(), &domain.Booking{ID: 3, UserID: "user-1"}, 40)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if refundedPayment != "pay-orig" || refund.Amount != 40 {
+		t.Errorf("expected 40 refunded against pay-orig, got %+v", refund)
+	}
+}
+
+func TestSagaOrchestrator_StartPartialRefund_AuthorizedChargeReducesHold(t *testing.T) {
+	refunded := false
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(_ context.Context, r *domain.Refund) (*domain.Refund, error) {
+			refunded = true
+			return r, nil
+		},
+	})
+	var reducedID string
+	var reducedBy int64
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			return &domain.Payment{ID: "pay-orig", BookingID: bookingID, Amount: 300, Status: domain.PaymentStatusAuthorized}, nil
+		},
+		reduceAuthorizedAmountFn: func(_ context.Context, id string, amount int64) (int64, error) {
+			reducedID, reducedBy = id, amount
+			return 300 - amount, nil
+		},
+		reduceAuthorizationFn: func(_ context.Context, _ string, _ int64) (int64, error) {
+			t.Fatal("a modified booking must not bring its capture forward")
+			return 0, nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), payRepo,
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	refund, err := orch.StartPartialRefund(context.Background(), &domain.Booking{ID: 3, UserID: "user-1"}, 40)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reducedID != "pay-orig" || reducedBy != 40 {
+		t.Errorf("expected 40 released from pay-orig, got %d from %q", reducedBy, reducedID)
+	}
+	if refunded || refund != nil {
+		t.Errorf("expected no refund of an uncaptured charge, got %+v", refund)
+	}
+}
+
+func TestSagaOrchestrator_StartPartialRefund_CapturedMeanwhileRefunds(t *testing.T) {
+	var refundedPayment string
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(_ context.Context, r *domain.Refund) (*domain.Refund, error) {
+			refundedPayment = r.PaymentID
+			result := *r
+			result.ID = "ref-3"
+			return &result, nil
+		},
+	})
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			return &domain.Payment{ID: "pay-orig", BookingID: bookingID, Amount: 300, Status: domain.PaymentStatusAuthorized}, nil
+		},
+		reduceAuthorizedAmountFn: func(_ context.Context, id string, amount int64) (int64, error) {
+			return 0, fmt.Errorf("payment %q cannot release %d: %w", id, amount, domain.ErrConflict)
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), payRepo,
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	refund, err := orch.StartPartialRefund(context.Background(), &domain.Booking{ID: 3, UserID: "user-1"}, 40)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if refundedPayment != "pay-orig" || refund == nil || refund.Amount != 40 {
+		t.Errorf("expected 40 refunded against pay-orig, got %+v", refund)
+	}
+}
+
+func TestSagaOrchestrator_StartTopUp_AuthorizedChargeDefersCapture(t *testing.T) {
+	captureAt := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByBookingIDFn: func(_ context.Context, bookingID int) (*domain.Payment, error) {
+			return &domain.Payment{ID: "pay-orig", BookingID: bookingID, Amount: 300,
+				Status: domain.PaymentStatusAuthorized, CaptureAt: &captureAt}, nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), payRepo,
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	payment, err := orch.StartTopUp(context.Background(), &domain.Booking{ID: 3, UserID: "user-1"}, 75)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if payment.CaptureAt == nil || !payment.CaptureAt.Equal(captureAt) {
+		t.Errorf("expected the top-up captured with the charge at %v, got %v", captureAt, payment.CaptureAt)
+	}
+}
+
+func TestSagaOrchestrator_HandleRefundSucceeded_CompletesRefund(t *testing.T) {
+	var gotRef string
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		completeRefundFn: func(_ context.Context, _, gatewayRef string) error {
+			gotRef = gatewayRef
+			return nil
+		},
+	})
+	var notifType domain.NotificationType
+	notifier := makeMockNotificationSender(mockNotificationSender{
+		notifyFn: func(_ context.Context, _ string, nt domain.NotificationType, _, _ string, _ map[string]any) error {
+			notifType = nt
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}),
+		service.WithRefundRepository(refundRepo), service.WithNotificationSender(notifier))
+
+	if err := orch.HandleRefundSucceeded(context.Background(), "ref-1", "RF-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotRef != "RF-1" {
+		t.Errorf("expected gateway ref RF-1, got %q", gotRef)
+	}
+	if notifType != domain.NotificationTypeRefundSucceeded {
+		t.Errorf("expected refund_succeeded notification, got %q", notifType)
+	}
+}
+
+func TestSagaOrchestrator_HandleRefundSucceeded_SettledRefundIgnored(t *testing.T) {
+	completed := false
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		getRefundByIDFn: func(_ context.Context, id string) (*domain.Refund, error) {
+			return &domain.Refund{ID: id, Status: domain.RefundStatusSucceeded}, nil
+		},
+		completeRefundFn: func(_ context.Context, _, _ string) error {
+			completed = true
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	if err := orch.HandleRefundSucceeded(context.Background(), "ref-1", "RF-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if completed {
+		t.Error("a settled refund must not be completed again")
+	}
+}
+
+func TestSagaOrchestrator_HandleRefundFailed_MarksRefundFailed(t *testing.T) {
+	var gotReason string
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		failRefundFn: func(_ context.Context, _, reason string) error {
+			gotReason = reason
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	if err := orch.HandleRefundFailed(context.Background(), "ref-1", "card closed"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotReason != "card closed" {
+		t.Errorf("expected reason recorded, got %q", gotReason)
+	}
+}
+
+// --- Tests: authorize-then-capture ---
+
+func TestSagaOrchestrator_StartCheckout_DeferredCaptureUsesEarliestLeg(t *testing.T) {
+	legs := itineraryLegs()
+	now := legs[0].StartDate.AddDate(0, 0, -30)
+	days := func(n int) *int { return &n }
+	policies := &mockPolicyRepo{
+		getPolicyForRoomFn: func(_ context.Context, roomID int) (*domain.CancellationPolicy, error) {
+			// Room 20's leg starts two days after room 10's but captures a week ahead.
+			if roomID == 20 {
+				return &domain.CancellationPolicy{CaptureDaysBeforeCheckIn: days(7)}, nil
+			}
+			return &domain.CancellationPolicy{CaptureDaysBeforeCheckIn: days(0)}, nil
+		},
+	}
+	orch := service.NewSagaOrchestrator(makeItineraryBookingRepo(legs, map[int]string{}), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}),
+		service.WithCapturePolicies(policies), service.WithSagaClock(func() time.Time { return now }))
+
+	payment, err := orch.StartCheckout(context.Background(), 11, "user-1", "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := legs[1].StartDate.AddDate(0, 0, -7)
+	if payment.CaptureAt == nil || !payment.CaptureAt.Equal(want) {
+		t.Errorf("expected capture at %v, got %v", want, payment.CaptureAt)
+	}
+}
+
+func TestSagaOrchestrator_StartCheckout_AnyImmediateLegCapturesAtCheckout(t *testing.T) {
+	legs := itineraryLegs()
+	days := 3
+	policies := &mockPolicyRepo{
+		getPolicyForRoomFn: func(_ context.Context, roomID int) (*domain.CancellationPolicy, error) {
+			if roomID == 30 {
+				return nil, domain.ErrNotFound
+			}
+			return &domain.CancellationPolicy{CaptureDaysBeforeCheckIn: &days}, nil
+		},
+	}
+	orch := service.NewSagaOrchestrator(makeItineraryBookingRepo(legs, map[int]string{}), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}),
+		service.WithCapturePolicies(policies),
+		service.WithSagaClock(func() time.Time { return legs[0].StartDate.AddDate(0, 0, -30) }))
+
+	payment, err := orch.StartCheckout(context.Background(), 11, "user-1", "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if payment.CaptureAt != nil {
+		t.Errorf("expected capture at checkout, got %v", *payment.CaptureAt)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentSuccess_KeepsAuthorizedStatus(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Status: domain.PaymentStatusAuthorized}, nil
+		},
+		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, ref, reason string) error {
+			t.Errorf("authorized payment must not be moved to %q", status)
+			return nil
+		},
+	})
+	var confirmed bool
+	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{
+		updateBookingStatusFn: func(ctx context.Context, id int, status string) error {
+			confirmed = status == domain.BookingStatusConfirmed
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	if err := orch.HandlePaymentSuccess(context.Background(), "pay-id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !confirmed {
+		t.Error("expected booking confirmed on authorization")
+	}
+}
+
+func TestSagaOrchestrator_ReleaseAuthorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining int64
+		wantVoid  bool
+	}{
+		{"whole authorization voided", 0, true},
+		{"penalty left for capture", 50, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var released int64
+			payRepo := makePaymentRepo(mockPaymentRepo{
+				reduceAuthorizationFn: func(ctx context.Context, id string, amount int64) (int64, error) {
+					released = amount
+					return tt.remaining, nil
+				},
+			})
+			var events []*domain.OutboxEvent
+			outboxRepo := makeOutboxRepo(mockOutboxRepo{
+				createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+					events = append(events, event)
+					return nil
+				},
+			})
+			var notified domain.NotificationType
+			notifier := makeMockNotificationSender(mockNotificationSender{
+				notifyFn: func(ctx context.Context, userID string, notifType domain.NotificationType, title, message string, data map[string]any) error {
+					notified = notifType
+					return nil
+				},
+			})
+			orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), payRepo, outboxRepo,
+				makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithNotificationSender(notifier))
+
+			if err := orch.ReleaseAuthorization(context.Background(), "pay-1", 1, 150); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if released != 150 {
+				t.Errorf("expected 150 released, got %d", released)
+			}
+			voided := len(events) == 1 && events[0].EventType == domain.EventTypePaymentVoidRequested
+			if voided != tt.wantVoid || (!tt.wantVoid && len(events) != 0) {
+				t.Errorf("expected void requested=%v, got %+v", tt.wantVoid, events)
+			}
+			if notified != domain.NotificationTypePaymentVoided {
+				t.Errorf("expected %q notification, got %q", domain.NotificationTypePaymentVoided, notified)
+			}
+		})
+	}
+}
+
+func TestSagaOrchestrator_ReleaseAuthorization_CapturedPaymentConflicts(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		reduceAuthorizationFn: func(ctx context.Context, id string, amount int64) (int64, error) {
+			return 0, domain.ErrConflict
+		},
+	})
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), payRepo,
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	err := orch.ReleaseAuthorization(context.Background(), "pay-1", 1, 150)
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestSagaOrchestrator_ReleaseAuthorization_CaptureTime(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	checkIn := now.AddDate(0, 0, 20)
+	itineraryID := 4
+	tests := []struct {
+		name        string
+		itineraryID *int
+		want        time.Time
+	}{
+		// The rest is the penalty of the one booking it paid for: due now.
+		{"single booking", nil, now},
+		// The rest pays for the other legs too: due at check-in as scheduled.
+		{"itinerary leg", &itineraryID, checkIn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureAt := checkIn
+			payRepo := makePaymentRepo(mockPaymentRepo{
+				reduceAuthorizationFn: func(ctx context.Context, id string, amount int64) (int64, error) {
+					if now.Before(captureAt) {
+						captureAt = now
+					}
+					return 300, nil
+				},
+				reduceAuthorizedAmountFn: func(ctx context.Context, id string, amount int64) (int64, error) {
+					return 300, nil
+				},
+			})
+			bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{
+				findBookingByIDFn: func(ctx context.Context, id int) (*domain.Booking, error) {
+					return &domain.Booking{ID: id, UserID: "user-1", ItineraryID: tt.itineraryID}, nil
+				},
+			})
+			orch := service.NewSagaOrchestrator(bookingRepo, payRepo, makeOutboxRepo(mockOutboxRepo{}),
+				makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+			if err := orch.ReleaseAuthorization(context.Background(), "pay-1", 12, 150); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !captureAt.Equal(tt.want) {
+				t.Errorf("expected the rest captured at %s, got %s", tt.want, captureAt)
+			}
+		})
+	}
+}
+
+func TestSagaOrchestrator_HandleCaptureFailed_NotifiesGuestOnly(t *testing.T) {
+	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{
+		updateBookingStatusFn: func(ctx context.Context, id int, status string) error {
+			t.Errorf("booking must keep its status, got %q", status)
+			return nil
+		},
+	})
+	var notified domain.NotificationType
+	notifier := makeMockNotificationSender(mockNotificationSender{
+		notifyFn: func(ctx context.Context, userID string, notifType domain.NotificationType, title, message string, data map[string]any) error {
+			notified = notifType
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(bookingRepo, makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}),
+		service.WithNotificationSender(notifier))
+
+	if err := orch.HandleCaptureFailed(context.Background(), "pay-1", "card_declined"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notified != domain.NotificationTypeCaptureFailed {
+		t.Errorf("expected %q notification, got %q", domain.NotificationTypeCaptureFailed, notified)
+	}
+}
+
+// --- Mock TxManager ---
+
+type inTxKey struct{}
+
+// mockTxManager runs units of work in a fake transaction that repository
+// mocks can detect with inTx, and records how each one ended.
+type mockTxManager struct {
+	committed  int
+	rolledBack int
+}
+
+func (m *mockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+	if err := fn(context.WithValue(ctx, inTxKey{}, true)); err != nil {
+		m.rolledBack++
+		return err
+	}
+	m.committed++
+	return nil
+}
+
+func inTx(ctx context.Context) bool {
+	return ctx.Value(inTxKey{}) != nil
+}
+
+// --- Tests: unit of work ---
+
+func TestSagaOrchestrator_StartCheckout_WritesInOneTransaction(t *testing.T) {
+	var outsideTx []string
+	track := func(ctx context.Context, write string) {
+		if !inTx(ctx) {
+			outsideTx = append(outsideTx, write)
+		}
+	}
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		createPaymentFn: func(ctx context.Context, p *domain.Payment) (*domain.Payment, error) {
+			track(ctx, "payment")
+			return &domain.Payment{ID: "pay-1", BookingID: p.BookingID}, nil
+		},
+	})
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			track(ctx, "event")
+			return nil
+		},
+	})
+	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{
+		updateBookingStatusFn: func(ctx context.Context, id int, status string) error {
+			track(ctx, "booking")
+			return errors.New("db down")
+		},
+	})
+	txManager := &mockTxManager{}
+	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, outboxRepo,
+		makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithSagaTxManager(txManager))
+
+	if _, err := orch.StartCheckout(context.Background(), 1, "user-1", "tok_visa"); err == nil {
+		t.Fatal("expected the failed booking update to fail the checkout")
+	}
+	if len(outsideTx) != 0 {
+		t.Errorf("expected every write in the transaction, got %v outside it", outsideTx)
+	}
+	if txManager.rolledBack != 1 || txManager.committed != 0 {
+		t.Errorf("expected the payment and its event rolled back, got %d commits and %d rollbacks",
+			txManager.committed, txManager.rolledBack)
+	}
+}
+
+func TestSagaOrchestrator_StartRefund_RefundAndEventShareTransaction(t *testing.T) {
+	var outsideTx int
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(ctx context.Context, r *domain.Refund) (*domain.Refund, error) {
+			if !inTx(ctx) {
+				outsideTx++
+			}
+			created := *r
+			created.ID = "ref-1"
+			return &created, nil
+		},
+	})
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			if !inTx(ctx) {
+				outsideTx++
+			}
+			return nil
+		},
+	})
+	txManager := &mockTxManager{}
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		outboxRepo, makeMockInventoryRestorer(mockInventoryRestorer{}),
+		service.WithRefundRepository(refundRepo), service.WithSagaTxManager(txManager))
+
+	if _, err := orch.StartRefund(context.Background(), "pay-1", 1, 50, "cancelled"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outsideTx != 0 || txManager.committed != 1 {
+		t.Errorf("expected refund and event committed together, got %d writes outside and %d commits",
+			outsideTx, txManager.committed)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentFailure_RollsBackWithInventoryRestore(t *testing.T) {
+	var paymentFailedInTx bool
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error {
+			paymentFailedInTx = inTx(ctx)
+			return nil
+		},
+	})
+	restorer := makeMockInventoryRestorer(mockInventoryRestorer{
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
+			return errors.New("db down")
+		},
+	})
+	txManager := &mockTxManager{}
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), payRepo,
+		makeOutboxRepo(mockOutboxRepo{}), restorer, service.WithSagaTxManager(txManager))
+
+	if err := orch.HandlePaymentFailure(context.Background(), "pay-1", "card_declined"); err == nil {
+		t.Fatal("expected the restore error to be returned")
+	}
+	if !paymentFailedInTx || txManager.rolledBack != 1 {
+		t.Errorf("expected the failed payment rolled back with the restore, got in tx %v and %d rollbacks",
+			paymentFailedInTx, txManager.rolledBack)
+	}
+}
+
+// --- Tests: illegal transitions ---
+
+func conflictingBookingRepo() *mockSagaBookingRepo {
+	return makeSagaBookingRepo(mockSagaBookingRepo{
+		updateBookingStatusFn: func(ctx context.Context, id int, status string) error {
+			return fmt.Errorf("booking %d cannot move from cancelled to %s: %w", id, status, domain.ErrConflict)
+		},
+	})
+}
+
+func TestSagaOrchestrator_HandlePaymentSuccess_CancelledBookingIsRefunded(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: 200, Status: domain.PaymentStatusCaptured}, nil
+		},
+	})
+	var refunded *domain.Refund
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(ctx context.Context, r *domain.Refund) (*domain.Refund, error) {
+			refunded = r
+			created := *r
+			created.ID = "ref-1"
+			return &created, nil
+		},
+		getRefundByKeyFn: func(ctx context.Context, key string) (*domain.Refund, error) {
+			return nil, domain.ErrNotFound
+		},
+	})
+	notifier := makeMockNotificationSender(mockNotificationSender{
+		notifyFn: func(ctx context.Context, userID string, notifType domain.NotificationType, title, message string, data map[string]any) error {
+			t.Errorf("expected no %q notification for a booking that was not confirmed", notifType)
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(conflictingBookingRepo(), payRepo, makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}),
+		service.WithRefundRepository(refundRepo), service.WithNotificationSender(notifier))
+
+	if err := orch.HandlePaymentSuccess(context.Background(), "pay-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refunded == nil || refunded.PaymentID != "pay-1" || refunded.BookingID != 1 || refunded.Amount != 200 {
+		t.Errorf("expected the whole charge refunded for booking 1, got %+v", refunded)
+	}
+	if refunded != nil && refunded.IdempotencyKey != "compensation:pay-1:1" {
+		t.Errorf("expected the compensation keyed by payment and leg, got %q", refunded.IdempotencyKey)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentSuccess_ExpiredBookingReleasesAuthorization(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: 200, Status: domain.PaymentStatusAuthorized}, nil
+		},
+		reduceAuthorizationFn: func(ctx context.Context, id string, amount int64) (int64, error) {
+			if amount != 200 {
+				t.Errorf("expected the whole authorization released, got %d", amount)
+			}
+			return 0, nil
+		},
+	})
+	var voidRequested bool
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			voidRequested = event.EventType == domain.EventTypePaymentVoidRequested
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(conflictingBookingRepo(), payRepo, outboxRepo,
+		makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	if err := orch.HandlePaymentSuccess(context.Background(), "pay-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !voidRequested {
+		t.Error("expected the authorization voided")
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentSuccess_CompensationConflictIsReturned(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: 200, Status: domain.PaymentStatusCaptured}, nil
+		},
+	})
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(ctx context.Context, r *domain.Refund) (*domain.Refund, error) {
+			return nil, fmt.Errorf("refund exceeds remaining balance: %w", domain.ErrConflict)
+		},
+		getRefundByKeyFn: func(ctx context.Context, key string) (*domain.Refund, error) {
+			return nil, domain.ErrNotFound
+		},
+	})
+	orch := service.NewSagaOrchestrator(conflictingBookingRepo(), payRepo, makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	err := orch.HandlePaymentSuccess(context.Background(), "pay-1")
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected ErrConflict from the compensation refund, got %v", err)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentSuccess_TimedOutPaymentConflicts(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: 200, Status: domain.PaymentStatusTimedOut}, nil
+		},
+	})
+	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{
+		updateBookingStatusFn: func(ctx context.Context, id int, status string) error {
+			t.Errorf("expected booking %d left alone, got moved to %s", id, status)
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	err := orch.HandlePaymentSuccess(context.Background(), "pay-1")
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected ErrConflict for a timed out payment, got %v", err)
+	}
+}
+
+func TestSagaOrchestrator_HandleLateSuccess_AuthorizedReleasesHold(t *testing.T) {
+	status := domain.PaymentStatusTimedOut
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: 200, Status: status}, nil
+		},
+		markAuthorizedFn: func(ctx context.Context, id, authorizationRef string) error {
+			if authorizationRef != "gw-late" {
+				t.Errorf("expected gateway ref gw-late, got %q", authorizationRef)
+			}
+			status = domain.PaymentStatusAuthorized
+			return nil
+		},
+		reduceAuthorizationFn: func(ctx context.Context, id string, amount int64) (int64, error) {
+			if amount != 200 {
+				t.Errorf("expected the whole authorization released, got %d", amount)
+			}
+			return 0, nil
+		},
+	})
+	var voidRequested bool
+	outboxRepo := makeOutboxRepo(mockOutboxRepo{
+		createEventFn: func(ctx context.Context, event *domain.OutboxEvent) error {
+			voidRequested = event.EventType == domain.EventTypePaymentVoidRequested
+			return nil
+		},
+	})
+	tm := &mockTxManager{}
+	orch := service.NewSagaOrchestrator(conflictingBookingRepo(), payRepo, outboxRepo,
+		makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithSagaTxManager(tm))
+
+	if err := orch.HandleLateSuccess(context.Background(), "pay-1", domain.PaymentStatusAuthorized, "gw-late"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != domain.PaymentStatusAuthorized {
+		t.Errorf("expected the late authorization recorded, got %s", status)
+	}
+	if !voidRequested {
+		t.Error("expected the late authorization voided")
+	}
+	if tm.committed != 1 {
+		t.Errorf("expected one committed transaction, got %d", tm.committed)
+	}
+}
+
+func TestSagaOrchestrator_HandleLateSuccess_CapturedIsRefunded(t *testing.T) {
+	status := domain.PaymentStatusTimedOut
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		getPaymentByIDFn: func(ctx context.Context, id string) (*domain.Payment, error) {
+			return &domain.Payment{ID: id, BookingID: 1, Amount: 200, Status: status}, nil
+		},
+		updatePaymentStatusFn: func(ctx context.Context, id string, to domain.PaymentStatus, gatewayRef, failedReason string) error {
+			if gatewayRef != "gw-late" {
+				t.Errorf("expected gateway ref gw-late, got %q", gatewayRef)
+			}
+			status = to
+			return nil
+		},
+	})
+	var refunded *domain.Refund
+	refundRepo := makeRefundRepo(mockRefundRepo{
+		createRefundFn: func(ctx context.Context, r *domain.Refund) (*domain.Refund, error) {
+			refunded = r
+			created := *r
+			created.ID = "ref-1"
+			return &created, nil
+		},
+		getRefundByKeyFn: func(ctx context.Context, key string) (*domain.Refund, error) {
+			return nil, domain.ErrNotFound
+		},
+	})
+	orch := service.NewSagaOrchestrator(conflictingBookingRepo(), payRepo, makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}), service.WithRefundRepository(refundRepo))
+
+	if err := orch.HandleLateSuccess(context.Background(), "pay-1", domain.PaymentStatusCaptured, "gw-late"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != domain.PaymentStatusCaptured {
+		t.Errorf("expected the late capture recorded, got %s", status)
+	}
+	if refunded == nil || refunded.Amount != 200 || refunded.IdempotencyKey != "compensation:pay-1:1" {
+		t.Errorf("expected the late capture refunded once, got %+v", refunded)
+	}
+}
+
+func TestSagaOrchestrator_HandleLateSuccess_RejectsOtherStatus(t *testing.T) {
+	orch := service.NewSagaOrchestrator(makeSagaBookingRepo(mockSagaBookingRepo{}), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	err := orch.HandleLateSuccess(context.Background(), "pay-1", domain.PaymentStatusFailed, "gw-late")
+	if !errors.Is(err, domain.ErrBadRequest) {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentFailure_SkipsAlreadyReleasedBooking(t *testing.T) {
+	restorer := makeMockInventoryRestorer(mockInventoryRestorer{
+		restoreInventoryFn: func(ctx context.Context, roomID int, startDate, endDate time.Time, quantity int) error {
+			t.Error("expected no inventory restored twice for a cancelled booking")
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(conflictingBookingRepo(), makePaymentRepo(mockPaymentRepo{}),
+		makeOutboxRepo(mockOutboxRepo{}), restorer)
+
+	if err := orch.HandlePaymentFailure(context.Background(), "pay-1", "card_declined"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSagaOrchestrator_HandlePaymentTimeout_IgnoresSettledPayment(t *testing.T) {
+	payRepo := makePaymentRepo(mockPaymentRepo{
+		updatePaymentStatusFn: func(ctx context.Context, id string, status domain.PaymentStatus, gatewayRef, failedReason string) error {
+			return fmt.Errorf("payment %q cannot move from captured to %s: %w", id, status, domain.ErrConflict)
+		},
+	})
+	bookingRepo := makeSagaBookingRepo(mockSagaBookingRepo{
+		updateBookingStatusFn: func(ctx context.Context, id int, status string) error {
+			t.Errorf("expected the booking of a captured payment left alone, got %q", status)
+			return nil
+		},
+	})
+	orch := service.NewSagaOrchestrator(bookingRepo, payRepo, makeOutboxRepo(mockOutboxRepo{}),
+		makeMockInventoryRestorer(mockInventoryRestorer{}))
+
+	if err := orch.HandlePaymentTimeout(context.Background(), "pay-1"); err != nil {
+		t.Fatalf("expected a stale timeout to be dropped, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	waitlistExpiryBatchSize            = 100
+	defaultWaitlistExpirySweepInterval = time.Minute
+)
+
+// WaitlistExpiryOption configures a WaitlistExpiryWorker.
+type WaitlistExpiryOption func(*WaitlistExpiryWorker)
+
+// WithWaitlistExpiryNotifier wires an optional notification sender.
+func WithWaitlistExpiryNotifier(n NotificationSender) WaitlistExpiryOption {
+	return func(w *WaitlistExpiryWorker) { w.notifier = n }
+}
+
+// WithWaitlistExpiryClock overrides the time source (used in tests).
+func WithWaitlistExpiryClock(now func() time.Time) WaitlistExpiryOption {
+	return func(w *WaitlistExpiryWorker) { w.now = now }
+}
+
+// WaitlistExpiryWorker periodically lapses waitlist offers that were not
+// claimed in time. Each lapsed offer releases the inventory it held, which is
+// offered to the next guests in line, and the guest is told.
+type WaitlistExpiryWorker struct {
+	repo     repository.WaitlistRepository
+	releases InventoryReleaseListener
+	interval time.Duration
+	logger   *zap.Logger
+	notifier NotificationSender // optional
+	now      func() time.Time
+}
+
+// NewWaitlistExpiryWorker creates a new WaitlistExpiryWorker. A zero interval
+// uses the package default.
+func NewWaitlistExpiryWorker(
+	repo repository.WaitlistRepository,
+	releases InventoryReleaseListener,
+	interval time.Duration,
+	logger *zap.Logger,
+	opts ...WaitlistExpiryOption,
+) *WaitlistExpiryWorker {
+	if interval <= 0 {
+		interval = defaultWaitlistExpirySweepInterval
+	}
+	w := &WaitlistExpiryWorker{
+		repo:     repo,
+		releases: releases,
+		interval: interval,
+		logger:   logger,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run starts the sweep loop. It blocks until ctx is cancelled.
+// The first sweep runs immediately, then every interval.
+func (w *WaitlistExpiryWorker) Run(ctx context.Context) error {
+	w.logger.Info("waitlist expiry worker started", zap.Duration("interval", w.interval))
+
+	if _, err := w.ExpireOffers(ctx); err != nil {
+		w.logger.Error("waitlist expiry iteration error", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("waitlist expiry worker stopped")
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := w.ExpireOffers(ctx); err != nil {
+				w.logger.Error("waitlist expiry iteration error", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ExpireOffers runs a single sweep and returns how many offers lapsed. An
+// offer claimed or declined between listing and expiry is skipped.
+func (w *WaitlistExpiryWorker) ExpireOffers(ctx context.Context) (int, error) {
+	offers, err := w.repo.ListExpiredOffers(ctx, w.now(), waitlistExpiryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list expired waitlist offers: %w", err)
+	}
+
+	expired := 0
+	for _, entry := range offers {
+		if err := w.repo.ExpireOffer(ctx, entry.ID); err != nil {
+			if errors.Is(err, domain.ErrConflict) {
+				w.logger.Debug("waitlist offer no longer open, skipping", zap.Int("entry_id", entry.ID))
+				continue
+			}
+			w.logger.Error("failed to expire waitlist offer",
+				zap.Int("entry_id", entry.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		expired++
+
+		if w.notifier != nil {
+			_ = w.notifier.Notify(ctx, entry.UserID, domain.NotificationTypeWaitlistExpired,
+				"Waitlist Offer Expired",
+				fmt.Sprintf("Your offer for %s to %s was not booked in time and has gone to the next guest.",
+					entry.StartDate.Format("2006-01-02"), entry.EndDate.Format("2006-01-02")),
+				map[string]any{"entry_id": entry.ID, "room_id": entry.RoomID},
+			) // best-effort
+		}
+
+		if err := w.releases.InventoryReleased(ctx, entry.RoomID, entry.StartDate, entry.EndDate); err != nil {
+			w.logger.Error("failed to pass on expired waitlist offer",
+				zap.Int("entry_id", entry.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if expired > 0 {
+		w.logger.Info("expired waitlist offers", zap.Int("count", expired))
+	}
+	return expired, nil
+}
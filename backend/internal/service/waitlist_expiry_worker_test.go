@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWaitlistExpiryWorker_ExpireOffers_PassesLapsedOffersOn(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	lapsed := now.Add(-time.Minute)
+	var gotBefore time.Time
+	var expiredIDs []int
+	repo := &mockWaitlistRepo{
+		listExpiredOffersFn: func(_ context.Context, before time.Time, _ int) ([]*domain.WaitlistEntry, error) {
+			gotBefore = before
+			return []*domain.WaitlistEntry{offeredEntry(1, "user-1", lapsed), offeredEntry(2, "user-2", lapsed)}, nil
+		},
+		expireOfferFn: func(_ context.Context, id int) error {
+			expiredIDs = append(expiredIDs, id)
+			return nil
+		},
+	}
+	releases := &mockReleaseListener{}
+	var notified []string
+	notifier := makeMockNotificationSender(mockNotificationSender{
+		notifyFn: func(_ context.Context, userID string, notifType domain.NotificationType, _, _ string, _ map[string]any) error {
+			if notifType != domain.NotificationTypeWaitlistExpired {
+				t.Errorf("expected waitlist expired notification, got %q", notifType)
+			}
+			notified = append(notified, userID)
+			return nil
+		},
+	})
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewWaitlistExpiryWorker(repo, releases, time.Minute, logger,
+		service.WithWaitlistExpiryNotifier(notifier),
+		service.WithWaitlistExpiryClock(func() time.Time { return now }))
+
+	expired, err := worker.ExpireOffers(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expired != 2 {
+		t.Errorf("expected 2 offers expired, got %d", expired)
+	}
+	if !gotBefore.Equal(now) {
+		t.Errorf("expected cutoff %v, got %v", now, gotBefore)
+	}
+	if fmt.Sprint(expiredIDs) != "[1 2]" {
+		t.Errorf("expected entries 1 and 2 expired, got %v", expiredIDs)
+	}
+	if len(releases.released) != 2 || releases.released[0].roomID != 5 {
+		t.Errorf("expected both stays passed on, got %+v", releases.released)
+	}
+	if len(notified) != 2 {
+		t.Errorf("expected both guests notified, got %v", notified)
+	}
+}
+
+func TestWaitlistExpiryWorker_ExpireOffers_SkipsClaimedOffers(t *testing.T) {
+	lapsed := time.Now().Add(-time.Minute)
+	repo := &mockWaitlistRepo{
+		listExpiredOffersFn: func(context.Context, time.Time, int) ([]*domain.WaitlistEntry, error) {
+			return []*domain.WaitlistEntry{offeredEntry(1, "user-1", lapsed)}, nil
+		},
+		expireOfferFn: func(context.Context, int) error {
+			return fmt.Errorf("claimed meanwhile: %w", domain.ErrConflict)
+		},
+	}
+	releases := &mockReleaseListener{}
+	logger, _ := zap.NewDevelopment()
+	worker := service.NewWaitlistExpiryWorker(repo, releases, 0, logger)
+
+	expired, err := worker.ExpireOffers(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expired != 0 {
+		t.Errorf("expected no offers expired, got %d", expired)
+	}
+	if len(releases.released) != 0 {
+		t.Errorf("a claimed offer releases nothing, got %+v", releases.released)
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/repository"
+	"context"
+	"fmt"
+	"time"
+)
+
+const defaultWaitlistOfferTTL = 30 * time.Minute
+
+// InventoryReleaseListener is told whenever a room's inventory is released
+// for [startDate, endDate): a booking was cancelled, refunded, moved or
+// expired, its payment failed, or the owner changed the room's inventory.
+type InventoryReleaseListener interface {
+	InventoryReleased(ctx context.Context, roomID int, startDate, endDate time.Time) error
+}
+
+// UserPusher pushes a real-time message to a user's connected clients.
+type UserPusher interface {
+	Push(userID, msgType string, payload map[string]any)
+}
+
+// WaitlistOption configures a WaitlistService.
+type WaitlistOption func(*WaitlistService)
+
+// WithWaitlistNotifier wires an optional notification sender for offers.
+func WithWaitlistNotifier(n NotificationSender) WaitlistOption {
+	return func(s *WaitlistService) { s.notifier = n }
+}
+
+// WithWaitlistPusher wires an optional real-time push for offers.
+func WithWaitlistPusher(p UserPusher) WaitlistOption {
+	return func(s *WaitlistService) { s.pusher = p }
+}
+
+// WithWaitlistAvailabilityInvalidator drops cached hotel availability when
+// offers hold inventory or release it.
+func WithWaitlistAvailabilityInvalidator(availability AvailabilityInvalidator) WaitlistOption {
+	return func(s *WaitlistService) { s.availability = availability }
+}
+
+// WithWaitlistClock overrides the time source (used in tests).
+func WithWaitlistClock(now func() time.Time) WaitlistOption {
+	return func(s *WaitlistService) { s.now = now }
+}
+
+// WaitlistService lets guests wait for sold-out stays. It listens for
+// released inventory and offers it to the oldest waiting guests whose stay it
+// frees up, holding the stay for them for offerTTL. Offers are claimed as
+// bookings through BookingService.ClaimWaitlistOffer.
+type WaitlistService struct {
+	repo         repository.WaitlistRepository
+	roomRepo     repository.RoomRepository
+	offerTTL     time.Duration
+	notifier     NotificationSender      // optional
+	pusher       UserPusher              // optional
+	availability AvailabilityInvalidator // optional
+	now          func() time.Time
+}
+
+// NewWaitlistService creates a new WaitlistService. A zero offerTTL uses the
+// package default.
+func NewWaitlistService(repo repository.WaitlistRepository, roomRepo repository.RoomRepository, offerTTL time.Duration, opts ...WaitlistOption) *WaitlistService {
+	if offerTTL <= 0 {
+		offerTTL = defaultWaitlistOfferTTL
+	}
+	s := &WaitlistService{
+		repo:     repo,
+		roomRepo: roomRepo,
+		offerTTL: offerTTL,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// JoinWaitlist puts a guest on the waitlist for a stay. The guests must fit
+// in the requested units, extra beds included. When the stay is already free
+// the guest is offered it right away, unless guests waiting longer take it.
+func (s *WaitlistService) JoinWaitlist(ctx context.Context, input domain.JoinWaitlistInput) (*domain.WaitlistEntry, error) {
+	if !input.EndDate.After(input.StartDate) {
+		return nil, fmt.Errorf("end_date must be after start_date: %w", domain.ErrBadRequest)
+	}
+	quantity := input.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return nil, fmt.Errorf("quantity must be positive: %w", domain.ErrBadRequest)
+	}
+	adults := input.Adults
+	if adults == 0 {
+		adults = 1
+	}
+
+	room, err := s.roomRepo.GetRoomByID(ctx, input.RoomID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch room: %w", err)
+	}
+	if _, err := room.CheckOccupancy(adults, input.Children, quantity); err != nil {
+		return nil, err
+	}
+
+	entry := &domain.WaitlistEntry{
+		UserID:    input.UserID,
+		RoomID:    input.RoomID,
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+		Quantity:  quantity,
+		Adults:    adults,
+		Children:  input.Children,
+	}
+	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	offered, err := s.offer(ctx, entry.RoomID, entry.StartDate, entry.EndDate)
+	if err != nil {
+		return entry, nil // the guest keeps waiting for the next release
+	}
+	if len(offered) > 0 {
+		s.invalidate(ctx, entry.RoomID)
+	}
+	for _, e := range offered {
+		if e.ID == entry.ID {
+			return e, nil
+		}
+	}
+	return entry, nil
+}
+
+// ListMyEntries returns a guest's waitlist entries, newest first.
+func (s *WaitlistService) ListMyEntries(ctx context.Context, userID string, page, limit int) ([]*domain.WaitlistEntry, int, error) {
+	page, limit = normalizePagination(page, limit)
+	return s.repo.ListEntriesByUser(ctx, userID, page, limit)
+}
+
+// LeaveWaitlist withdraws a guest's waiting entry or declines their offer.
+// A declined offer is passed on to the next guests in line.
+func (s *WaitlistService) LeaveWaitlist(ctx context.Context, id int, userID string) error {
+	entry, err := s.repo.CancelEntry(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if entry.HoldsInventory() {
+		_ = s.InventoryReleased(ctx, entry.RoomID, entry.StartDate, entry.EndDate) // best-effort
+	}
+	return nil
+}
+
+// InventoryReleased offers released inventory of a room to the oldest
+// waiting guests whose whole stay it frees up, and tells each of them.
+func (s *WaitlistService) InventoryReleased(ctx context.Context, roomID int, startDate, endDate time.Time) error {
+	_, err := s.offer(ctx, roomID, startDate, endDate)
+	s.invalidate(ctx, roomID)
+	return err
+}
+
+func (s *WaitlistService) offer(ctx context.Context, roomID int, startDate, endDate time.Time) ([]*domain.WaitlistEntry, error) {
+	offered, err := s.repo.OfferNext(ctx, roomID, startDate, endDate, s.now().Add(s.offerTTL))
+	if err != nil {
+		return nil, fmt.Errorf("offer released inventory: %w", err)
+	}
+	for _, e := range offered {
+		s.announce(ctx, e)
+	}
+	return offered, nil
+}
+
+// invalidate drops the cached availability of a room's hotel.
+func (s *WaitlistService) invalidate(ctx context.Context, roomID int) {
+	if s.availability == nil {
+		return
+	}
+	_ = s.availability.InvalidateRoom(ctx, roomID) // best-effort
+}
+
+// announce tells a guest about their offer, as a notification and a push to
+// their connected clients.
+func (s *WaitlistService) announce(ctx context.Context, e *domain.WaitlistEntry) {
+	data := map[string]any{
+		"entry_id":         e.ID,
+		"room_id":          e.RoomID,
+		"start_date":       e.StartDate.Format("2006-01-02"),
+		"end_date":         e.EndDate.Format("2006-01-02"),
+		"quantity":         e.Quantity,
+		"offer_expires_at": e.OfferExpiresAt.Format(time.RFC3339),
+	}
+	if s.notifier != nil {
+		_ = s.notifier.Notify(ctx, e.UserID, domain.NotificationTypeWaitlistOffer,
+			"Your Room Is Available",
+			fmt.Sprintf("A room you were waiting for is available from %s to %s. Book it before %s, after that it goes to the next guest.",
+				e.StartDate.Format("2006-01-02"), e.EndDate.Format("2006-01-02"), e.OfferExpiresAt.Format(time.RFC3339)),
+			data,
+		) // best-effort
+	}
+	if s.pusher != nil {
+		s.pusher.Push(e.UserID, string(domain.NotificationTypeWaitlistOffer), data)
+	}
+}
@@ -0,0 +1,293 @@
+package service_test
+
+import (
+	"booking-app/internal/domain"
+	"booking-app/internal/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// --- Mock WaitlistRepository ---
+
+type mockWaitlistRepo struct {
+	createEntryFn       func(ctx context.Context, e *domain.WaitlistEntry) error
+	getEntryByIDFn      func(ctx context.Context, id int) (*domain.WaitlistEntry, error)
+	listEntriesByUserFn func(ctx context.Context, userID string, page, limit int) ([]*domain.WaitlistEntry, int, error)
+	cancelEntryFn       func(ctx context.Context, id int, userID string) (*domain.WaitlistEntry, error)
+	offerNextFn         func(ctx context.Context, roomID int, startDate, endDate, expiresAt time.Time) ([]*domain.WaitlistEntry, error)
+	claimOfferFn        func(ctx context.Context, id int, booking *domain.Booking) error
+	listExpiredOffersFn func(ctx context.Context, before time.Time, limit int) ([]*domain.WaitlistEntry, error)
+	expireOfferFn       func(ctx context.Context, id int) error
+}
+
+func (m *mockWaitlistRepo) CreateEntry(ctx context.Context, e *domain.WaitlistEntry) error {
+	if m.createEntryFn != nil {
+		return m.createEntryFn(ctx, e)
+	}
+	e.ID = 1
+	e.Status = domain.WaitlistStatusWaiting
+	return nil
+}
+
+func (m *mockWaitlistRepo) GetEntryByID(ctx context.Context, id int) (*domain.WaitlistEntry, error) {
+	if m.getEntryByIDFn != nil {
+		return m.getEntryByIDFn(ctx, id)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockWaitlistRepo) ListEntriesByUser(ctx context.Context, userID string, page, limit int) ([]*domain.WaitlistEntry, int, error) {
+	if m.listEntriesByUserFn != nil {
+		return m.listEntriesByUserFn(ctx, userID, page, limit)
+	}
+	return []*domain.WaitlistEntry{}, 0, nil
+}
+
+func (m *mockWaitlistRepo) CancelEntry(ctx context.Context, id int, userID string) (*domain.WaitlistEntry, error) {
+	if m.cancelEntryFn != nil {
+		return m.cancelEntryFn(ctx, id, userID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockWaitlistRepo) OfferNext(ctx context.Context, roomID int, startDate, endDate, expiresAt time.Time) ([]*domain.WaitlistEntry, error) {
+	if m.offerNextFn != nil {
+		return m.offerNextFn(ctx, roomID, startDate, endDate, expiresAt)
+	}
+	return []*domain.WaitlistEntry{}, nil
+}
+
+func (m *mockWaitlistRepo) ClaimOffer(ctx context.Context, id int, booking *domain.Booking) error {
+	if m.claimOfferFn != nil {
+		return m.claimOfferFn(ctx, id, booking)
+	}
+	booking.ID = 77
+	booking.Status = domain.BookingStatusPending
+	return nil
+}
+
+func (m *mockWaitlistRepo) ListExpiredOffers(ctx context.Context, before time.Time, limit int) ([]*domain.WaitlistEntry, error) {
+	if m.listExpiredOffersFn != nil {
+		return m.listExpiredOffersFn(ctx, before, limit)
+	}
+	return []*domain.WaitlistEntry{}, nil
+}
+
+func (m *mockWaitlistRepo) ExpireOffer(ctx context.Context, id int) error {
+	if m.expireOfferFn != nil {
+		return m.expireOfferFn(ctx, id)
+	}
+	return nil
+}
+
+// --- Mock InventoryReleaseListener and UserPusher ---
+
+type releasedStay struct {
+	roomID     int
+	start, end time.Time
+}
+
+type mockReleaseListener struct {
+	released []releasedStay
+	err      error
+}
+
+func (m *mockReleaseListener) InventoryReleased(_ context.Context, roomID int, startDate, endDate time.Time) error {
+	m.released = append(m.released, releasedStay{roomID: roomID, start: startDate, end: endDate})
+	return m.err
+}
+
+type mockUserPusher struct {
+	pushed map[string][]string
+}
+
+func (m *mockUserPusher) Push(userID, msgType string, _ map[string]any) {
+	if m.pushed == nil {
+		m.pushed = map[string][]string{}
+	}
+	m.pushed[userID] = append(m.pushed[userID], msgType)
+}
+
+func waitlistRoomRepo() *mockBookingRoomRepo {
+	return &mockBookingRoomRepo{
+		getRoomByIDFn: func(_ context.Context, id int) (*domain.Room, error) {
+			return &domain.Room{ID: id, HotelID: 10, PricePerNight: 10000, Currency: "EUR", Capacity: 2}, nil
+		},
+	}
+}
+
+func offeredEntry(id int, userID string, expiresAt time.Time) *domain.WaitlistEntry {
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.WaitlistEntry{
+		ID:             id,
+		UserID:         userID,
+		RoomID:         5,
+		StartDate:      start,
+		EndDate:        start.AddDate(0, 0, 2),
+		Quantity:       1,
+		Adults:         2,
+		Status:         domain.WaitlistStatusOffered,
+		OfferExpiresAt: &expiresAt,
+	}
+}
+
+// --- Tests: WaitlistService ---
+
+func TestWaitlistService_JoinWaitlist_WaitsForSoldOutStay(t *testing.T) {
+	var created *domain.WaitlistEntry
+	repo := &mockWaitlistRepo{
+		createEntryFn: func(_ context.Context, e *domain.WaitlistEntry) error {
+			created = e
+			e.ID = 3
+			e.Status = domain.WaitlistStatusWaiting
+			return nil
+		},
+	}
+	svc := service.NewWaitlistService(repo, waitlistRoomRepo(), time.Hour)
+
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	entry, err := svc.JoinWaitlist(context.Background(), domain.JoinWaitlistInput{
+		UserID: "user-1", RoomID: 5, StartDate: start, EndDate: start.AddDate(0, 0, 2),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Status != domain.WaitlistStatusWaiting {
+		t.Errorf("expected waiting entry, got %q", entry.Status)
+	}
+	if created.Quantity != 1 || created.Adults != 1 {
+		t.Errorf("expected quantity and adults to default to 1, got %d and %d", created.Quantity, created.Adults)
+	}
+}
+
+func TestWaitlistService_JoinWaitlist_OffersFreeStayRightAway(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	var gotExpiry time.Time
+	repo := &mockWaitlistRepo{
+		offerNextFn: func(_ context.Context, roomID int, startDate, endDate, expiresAt time.Time) ([]*domain.WaitlistEntry, error) {
+			gotExpiry = expiresAt
+			return []*domain.WaitlistEntry{offeredEntry(1, "user-1", expiresAt)}, nil
+		},
+	}
+	pusher := &mockUserPusher{}
+	svc := service.NewWaitlistService(repo, waitlistRoomRepo(), 20*time.Minute,
+		service.WithWaitlistPusher(pusher),
+		service.WithWaitlistClock(func() time.Time { return now }))
+
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	entry, err := svc.JoinWaitlist(context.Background(), domain.JoinWaitlistInput{
+		UserID: "user-1", RoomID: 5, StartDate: start, EndDate: start.AddDate(0, 0, 2), Adults: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Status != domain.WaitlistStatusOffered {
+		t.Errorf("expected offered entry, got %q", entry.Status)
+	}
+	if !gotExpiry.Equal(now.Add(20 * time.Minute)) {
+		t.Errorf("expected offer to expire at %v, got %v", now.Add(20*time.Minute), gotExpiry)
+	}
+	if len(pusher.pushed["user-1"]) != 1 {
+		t.Errorf("expected one push to user-1, got %v", pusher.pushed)
+	}
+}
+
+func TestWaitlistService_JoinWaitlist_Rejects(t *testing.T) {
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		input domain.JoinWaitlistInput
+	}{
+		{"end before start", domain.JoinWaitlistInput{RoomID: 5, StartDate: start, EndDate: start}},
+		{"negative quantity", domain.JoinWaitlistInput{RoomID: 5, StartDate: start, EndDate: start.AddDate(0, 0, 1), Quantity: -1}},
+		{"too many guests", domain.JoinWaitlistInput{RoomID: 5, StartDate: start, EndDate: start.AddDate(0, 0, 1), Adults: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockWaitlistRepo{
+				createEntryFn: func(context.Context, *domain.WaitlistEntry) error {
+					t.Error("entry must not be created")
+					return nil
+				},
+			}
+			svc := service.NewWaitlistService(repo, waitlistRoomRepo(), time.Hour)
+
+			tt.input.UserID = "user-1"
+			if _, err := svc.JoinWaitlist(context.Background(), tt.input); !errors.Is(err, domain.ErrBadRequest) {
+				t.Errorf("expected ErrBadRequest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWaitlistService_InventoryReleased_NotifiesEveryOfferedGuest(t *testing.T) {
+	expiresAt := time.Date(2026, 6, 1, 13, 0, 0, 0, time.UTC)
+	repo := &mockWaitlistRepo{
+		offerNextFn: func(context.Context, int, time.Time, time.Time, time.Time) ([]*domain.WaitlistEntry, error) {
+			return []*domain.WaitlistEntry{offeredEntry(1, "user-1", expiresAt), offeredEntry(2, "user-2", expiresAt)}, nil
+		},
+	}
+	var notified []string
+	notifier := makeMockNotificationSender(mockNotificationSender{
+		notifyFn: func(_ context.Context, userID string, notifType domain.NotificationType, _, _ string, _ map[string]any) error {
+			if notifType != domain.NotificationTypeWaitlistOffer {
+				t.Errorf("expected waitlist offer notification, got %q", notifType)
+			}
+			notified = append(notified, userID)
+			return nil
+		},
+	})
+	svc := service.NewWaitlistService(repo, waitlistRoomRepo(), time.Hour, service.WithWaitlistNotifier(notifier))
+
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	if err := svc.InventoryReleased(context.Background(), 5, start, start.AddDate(0, 0, 3)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notified) != 2 || notified[0] != "user-1" || notified[1] != "user-2" {
+		t.Errorf("expected user-1 and user-2 notified, got %v", notified)
+	}
+}
+
+func TestWaitlistService_LeaveWaitlist_PassesDeclinedOfferOn(t *testing.T) {
+	declined := offeredEntry(1, "user-1", time.Date(2026, 6, 1, 13, 0, 0, 0, time.UTC))
+	var offeredRoom int
+	repo := &mockWaitlistRepo{
+		cancelEntryFn: func(_ context.Context, id int, userID string) (*domain.WaitlistEntry, error) {
+			return declined, nil
+		},
+		offerNextFn: func(_ context.Context, roomID int, startDate, endDate, _ time.Time) ([]*domain.WaitlistEntry, error) {
+			offeredRoom = roomID
+			if !startDate.Equal(declined.StartDate) || !endDate.Equal(declined.EndDate) {
+				t.Errorf("expected the declined stay to be offered, got %v-%v", startDate, endDate)
+			}
+			return nil, nil
+		},
+	}
+	svc := service.NewWaitlistService(repo, waitlistRoomRepo(), time.Hour)
+
+	if err := svc.LeaveWaitlist(context.Background(), 1, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if offeredRoom != 5 {
+		t.Errorf("expected room 5 offered to the next guest, got %d", offeredRoom)
+	}
+}
+
+func TestWaitlistService_LeaveWaitlist_WaitingEntryReleasesNothing(t *testing.T) {
+	repo := &mockWaitlistRepo{
+		cancelEntryFn: func(context.Context, int, string) (*domain.WaitlistEntry, error) {
+			return &domain.WaitlistEntry{ID: 1, RoomID: 5, Status: domain.WaitlistStatusWaiting}, nil
+		},
+		offerNextFn: func(context.Context, int, time.Time, time.Time, time.Time) ([]*domain.WaitlistEntry, error) {
+			t.Error("a waiting entry holds no inventory to offer")
+			return nil, nil
+		},
+	}
+	svc := service.NewWaitlistService(repo, waitlistRoomRepo(), time.Hour)
+
+	if err := svc.LeaveWaitlist(context.Background(), 1, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,4 @@
+-- Multi-unit bookings: a single booking may reserve several units of the
+-- same room type. Existing rows represent one unit each.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0);
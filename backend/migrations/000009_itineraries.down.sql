@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bookings_itinerary_id;
+ALTER TABLE bookings DROP COLUMN IF EXISTS itinerary_id;
+DROP TABLE IF EXISTS itineraries;
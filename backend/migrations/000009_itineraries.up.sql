@@ -0,0 +1,15 @@
+-- Itineraries group several bookings (legs) that are reserved all-or-nothing
+-- and paid for with a single checkout. Standalone bookings keep a NULL
+-- itinerary_id.
+CREATE TABLE IF NOT EXISTS itineraries (
+    id          SERIAL PRIMARY KEY,
+    user_id     UUID NOT NULL,
+    total_price DECIMAL(10, 2) NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS itinerary_id INT REFERENCES itineraries(id);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_itinerary_id ON bookings(itinerary_id) WHERE itinerary_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_itineraries_user_id ON itineraries(user_id);
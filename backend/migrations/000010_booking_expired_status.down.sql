@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bookings_hold_status;
+UPDATE bookings SET status = 'cancelled' WHERE status = 'expired';
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'awaiting_payment', 'confirmed', 'failed', 'cancelled'));
@@ -0,0 +1,9 @@
+-- Holds that are never paid for are released by the worker's hold reaper,
+-- which moves the booking to 'expired'.
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'awaiting_payment', 'confirmed', 'failed', 'cancelled', 'expired'));
+
+-- Supports the reaper's scan for stale holds.
+CREATE INDEX IF NOT EXISTS idx_bookings_hold_status ON bookings(status, created_at)
+    WHERE status IN ('pending', 'awaiting_payment');
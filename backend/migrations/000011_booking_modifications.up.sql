@@ -0,0 +1,8 @@
+-- Modifying a paid booking creates extra payments on it: a top-up when the
+-- new stay costs more, a refund when it costs less. The original checkout
+-- payment is the 'charge'.
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'charge'
+        CHECK (kind IN ('charge', 'top_up', 'refund'));
+
+CREATE INDEX IF NOT EXISTS idx_payments_booking_kind ON payments(booking_id, kind, created_at);
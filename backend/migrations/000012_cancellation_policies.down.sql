@@ -0,0 +1,8 @@
+UPDATE bookings SET status = 'cancelled' WHERE status = 'refunded';
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'awaiting_payment', 'confirmed', 'failed', 'cancelled', 'expired'));
+
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_amount;
+
+DROP TABLE IF EXISTS cancellation_policies;
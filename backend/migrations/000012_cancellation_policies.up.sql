@@ -0,0 +1,28 @@
+-- Cancellation policies decide how much of a paid booking is refunded when
+-- the guest cancels. A hotel-wide policy has a NULL room_id; a room policy
+-- overrides its hotel's.
+CREATE TABLE IF NOT EXISTS cancellation_policies (
+    id                     SERIAL PRIMARY KEY,
+    hotel_id               INT NOT NULL REFERENCES hotels(id) ON DELETE CASCADE,
+    room_id                INT REFERENCES rooms(id) ON DELETE CASCADE,
+    free_cancellation_days INT NOT NULL DEFAULT 0 CHECK (free_cancellation_days >= 0),
+    penalty_percent        DECIMAL(5, 2) NOT NULL DEFAULT 0
+        CHECK (penalty_percent >= 0 AND penalty_percent <= 100),
+    non_refundable         BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_cancellation_policies_hotel
+    ON cancellation_policies(hotel_id) WHERE room_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_cancellation_policies_room
+    ON cancellation_policies(room_id) WHERE room_id IS NOT NULL;
+
+-- Running total refunded against a charge; equal to amount once fully refunded.
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS refunded_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Cancelling a confirmed booking with a refund moves it to 'refunded'.
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'awaiting_payment', 'confirmed', 'failed', 'cancelled', 'expired', 'refunded'));
@@ -0,0 +1,21 @@
+-- Refunds are sent back against a succeeded charge by the refund saga. A
+-- charge can be refunded several times in part; payments.refunded_amount
+-- holds the total of its succeeded refunds.
+CREATE TABLE IF NOT EXISTS refunds (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id      UUID NOT NULL REFERENCES payments(id),
+    booking_id      INTEGER NOT NULL REFERENCES bookings(id),
+    amount          DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    currency        VARCHAR(3) NOT NULL DEFAULT 'USD',
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    reason          TEXT,
+    idempotency_key VARCHAR(255) UNIQUE NOT NULL,
+    gateway_ref     VARCHAR(255),
+    failed_reason   TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_payment_id ON refunds(payment_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_booking_id ON refunds(booking_id);
@@ -0,0 +1,3 @@
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS decline_code,
+    DROP COLUMN IF EXISTS payment_method;
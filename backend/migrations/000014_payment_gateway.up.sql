@@ -0,0 +1,4 @@
+-- Payment method the customer checked out with and the gateway's decline code.
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS payment_method VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS decline_code   VARCHAR(50);
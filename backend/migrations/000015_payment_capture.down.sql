@@ -0,0 +1,7 @@
+ALTER TABLE cancellation_policies DROP COLUMN IF EXISTS capture_days_before_check_in;
+
+DROP INDEX IF EXISTS idx_payments_due_capture;
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS capture_at,
+    DROP COLUMN IF EXISTS authorization_ref;
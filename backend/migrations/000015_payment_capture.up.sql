@@ -0,0 +1,13 @@
+-- Authorize-then-capture: a checkout may only authorize the card and leave the
+-- capture to the worker's scheduler, at check-in or some days before.
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS authorization_ref VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS capture_at        TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_payments_due_capture
+    ON payments(capture_at) WHERE status = 'authorized';
+
+-- NULL captures at booking; N captures N days before check-in.
+ALTER TABLE cancellation_policies
+    ADD COLUMN IF NOT EXISTS capture_days_before_check_in INT
+        CHECK (capture_days_before_check_in >= 0);
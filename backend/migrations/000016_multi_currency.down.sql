@@ -0,0 +1,25 @@
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS fx_rate,
+    DROP COLUMN IF EXISTS charge_currency,
+    DROP COLUMN IF EXISTS charge_total,
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN total_price TYPE DECIMAL(10, 2) USING total_price / 100.0;
+
+ALTER TABLE refunds
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+
+ALTER TABLE payments
+    ALTER COLUMN refunded_amount TYPE DECIMAL(10, 2) USING refunded_amount / 100.0,
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+
+ALTER TABLE itineraries
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN total_price TYPE DECIMAL(10, 2) USING total_price / 100.0;
+
+ALTER TABLE rooms
+    ALTER COLUMN price_per_night TYPE DECIMAL(10, 2) USING price_per_night / 100.0;
+
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE hotels
+    DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,44 @@
+-- Hotels price their rooms in their own currency; guests may pay in another
+-- one at a rate locked into the booking when it is priced.
+ALTER TABLE hotels
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+-- One unit of base_currency buys rate units of quote_currency. Uploaded by
+-- admins; the inverse pair is used when only one direction is stored.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base_currency  VARCHAR(3)      NOT NULL,
+    quote_currency VARCHAR(3)      NOT NULL,
+    rate           NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_at     TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (base_currency, quote_currency)
+);
+
+-- Money is stored as integer minor units (cents, or whole yen/dong for
+-- zero-decimal currencies). Every existing amount is in USD.
+ALTER TABLE rooms
+    ALTER COLUMN price_per_night TYPE BIGINT USING ROUND(price_per_night * 100);
+
+ALTER TABLE itineraries
+    ALTER COLUMN total_price TYPE BIGINT USING ROUND(total_price * 100),
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE payments
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100),
+    ALTER COLUMN refunded_amount TYPE BIGINT USING ROUND(refunded_amount * 100);
+
+ALTER TABLE refunds
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+-- total_price is in the hotel's currency; charge_total is what the guest pays
+-- in charge_currency, converted at fx_rate.
+ALTER TABLE bookings
+    ALTER COLUMN total_price TYPE BIGINT USING ROUND(total_price * 100),
+    ADD COLUMN IF NOT EXISTS currency        VARCHAR(3)      NOT NULL DEFAULT 'USD',
+    ADD COLUMN IF NOT EXISTS charge_total    BIGINT,
+    ADD COLUMN IF NOT EXISTS charge_currency VARCHAR(3)      NOT NULL DEFAULT 'USD',
+    ADD COLUMN IF NOT EXISTS fx_rate         NUMERIC(20, 10) NOT NULL DEFAULT 1;
+
+UPDATE bookings SET charge_total = total_price WHERE charge_total IS NULL;
+
+ALTER TABLE bookings
+    ALTER COLUMN charge_total SET NOT NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS nightly_rates;
+
+DROP TABLE IF EXISTS room_seasonal_rates;
+DROP TABLE IF EXISTS room_rate_plans;
+
+ALTER TABLE inventory
+    DROP COLUMN IF EXISTS min_stay,
+    DROP COLUMN IF EXISTS price;
@@ -0,0 +1,35 @@
+-- Dynamic pricing: a night is priced by a per-date override, else the season
+-- it falls in, else the weekend rate, else rooms.price_per_night.
+
+-- Per-date overrides live on the inventory row of that night.
+ALTER TABLE inventory
+    ADD COLUMN price    BIGINT CHECK (price > 0),
+    ADD COLUMN min_stay INT    CHECK (min_stay >= 1);
+
+-- Room-wide weekend rate (Friday and Saturday nights) and minimum stay.
+CREATE TABLE IF NOT EXISTS room_rate_plans (
+    room_id       INT PRIMARY KEY REFERENCES rooms(id) ON DELETE CASCADE,
+    weekend_price BIGINT CHECK (weekend_price > 0),
+    min_stay      INT NOT NULL DEFAULT 1 CHECK (min_stay >= 1),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Seasons price the nights in [start_date, end_date).
+CREATE TABLE IF NOT EXISTS room_seasonal_rates (
+    id            SERIAL PRIMARY KEY,
+    room_id       INT NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    name          VARCHAR(100) NOT NULL DEFAULT '',
+    start_date    DATE NOT NULL,
+    end_date      DATE NOT NULL,
+    price         BIGINT NOT NULL CHECK (price > 0),
+    weekend_price BIGINT CHECK (weekend_price > 0),
+    min_stay      INT CHECK (min_stay >= 1),
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_seasonal_rates_room
+    ON room_seasonal_rates(room_id, start_date);
+
+-- The rate of every night a booking was priced at, per unit.
+ALTER TABLE bookings
+    ADD COLUMN nightly_rates JSONB NOT NULL DEFAULT '[]';
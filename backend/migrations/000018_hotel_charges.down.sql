@@ -0,0 +1,3 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS price_lines;
+
+DROP TABLE IF EXISTS hotel_charges;
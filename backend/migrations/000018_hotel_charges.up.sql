@@ -0,0 +1,19 @@
+-- Taxes, service fees and discounts a hotel applies to its stays, either a
+-- percentage or a fixed amount (minor units of the hotel's currency).
+CREATE TABLE IF NOT EXISTS hotel_charges (
+    id         SERIAL PRIMARY KEY,
+    hotel_id   INT NOT NULL REFERENCES hotels(id) ON DELETE CASCADE,
+    name       VARCHAR(100) NOT NULL,
+    type       VARCHAR(20) NOT NULL CHECK (type IN ('discount', 'fee', 'tax')),
+    percent    NUMERIC(6, 3) CHECK (percent > 0 AND percent <= 100),
+    amount     BIGINT CHECK (amount > 0),
+    per_night  BOOLEAN NOT NULL DEFAULT FALSE,
+    min_nights INT NOT NULL DEFAULT 0 CHECK (min_nights >= 0),
+    CHECK ((percent IS NULL) <> (amount IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_hotel_charges_hotel ON hotel_charges(hotel_id);
+
+-- The discounts, fees and taxes included in a booking's total_price.
+ALTER TABLE bookings
+    ADD COLUMN price_lines JSONB NOT NULL DEFAULT '[]';
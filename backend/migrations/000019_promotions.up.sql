@@ -0,0 +1,47 @@
+-- Promo codes created by hotel owners (scoped to their hotel or one of its
+-- rooms) or admins (optionally unscoped). A code takes either a percentage or
+-- a fixed amount (minor units of currency) off the stay.
+CREATE TABLE IF NOT EXISTS promotions (
+    id              SERIAL PRIMARY KEY,
+    code            VARCHAR(50) NOT NULL UNIQUE,
+    description     TEXT NOT NULL DEFAULT '',
+    created_by      UUID NOT NULL REFERENCES users(id),
+    hotel_id        INT REFERENCES hotels(id) ON DELETE CASCADE,
+    room_id         INT REFERENCES rooms(id) ON DELETE CASCADE,
+    percent         NUMERIC(6, 3) CHECK (percent > 0 AND percent <= 100),
+    amount          BIGINT CHECK (amount > 0),
+    currency        VARCHAR(3),
+    valid_from      TIMESTAMPTZ,
+    valid_until     TIMESTAMPTZ,
+    min_nights      INT NOT NULL DEFAULT 0 CHECK (min_nights >= 0),
+    max_redemptions INT CHECK (max_redemptions > 0),
+    max_per_user    INT CHECK (max_per_user > 0),
+    active          BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((percent IS NULL) <> (amount IS NULL)),
+    CHECK (amount IS NULL OR currency IS NOT NULL),
+    CHECK (valid_until IS NULL OR valid_from IS NULL OR valid_until > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_created_by ON promotions(created_by);
+
+-- One row per booking that redeemed a code. A redemption stops counting
+-- against the code's limits once released_at is set: the booking was
+-- cancelled, expired or its payment failed.
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id           SERIAL PRIMARY KEY,
+    promotion_id INT NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    booking_id   INT NOT NULL UNIQUE REFERENCES bookings(id) ON DELETE CASCADE,
+    user_id      UUID NOT NULL,
+    amount       BIGINT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    released_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_active
+    ON promotion_redemptions(promotion_id, user_id) WHERE released_at IS NULL;
+
+ALTER TABLE bookings
+    ADD COLUMN promotion_id   INT REFERENCES promotions(id),
+    ADD COLUMN promo_code     VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN promo_discount BIGINT NOT NULL DEFAULT 0;
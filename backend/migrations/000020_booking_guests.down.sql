@@ -0,0 +1,8 @@
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS extra_guest_charge,
+    DROP COLUMN IF EXISTS children,
+    DROP COLUMN IF EXISTS adults;
+
+ALTER TABLE rooms
+    DROP COLUMN IF EXISTS extra_guest_fee,
+    DROP COLUMN IF EXISTS max_extra_beds;
@@ -0,0 +1,12 @@
+-- Guests a room sleeps beyond its capacity, in extra beds, and what each of
+-- them pays per night (minor units of the hotel's currency).
+ALTER TABLE rooms
+    ADD COLUMN IF NOT EXISTS max_extra_beds  INT    NOT NULL DEFAULT 0 CHECK (max_extra_beds >= 0),
+    ADD COLUMN IF NOT EXISTS extra_guest_fee BIGINT NOT NULL DEFAULT 0 CHECK (extra_guest_fee >= 0);
+
+-- The guests staying on a booking, and what those in extra beds pay for the
+-- stay (included in total_price).
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS adults             INT    NOT NULL DEFAULT 1 CHECK (adults >= 1),
+    ADD COLUMN IF NOT EXISTS children           INT    NOT NULL DEFAULT 0 CHECK (children >= 0),
+    ADD COLUMN IF NOT EXISTS extra_guest_charge BIGINT NOT NULL DEFAULT 0;
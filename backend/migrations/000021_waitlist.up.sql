@@ -0,0 +1,33 @@
+-- Guests waiting for a sold-out stay. When the room's inventory is released
+-- for the stay's dates, the oldest waiting entries that fit are offered a
+-- hold on it until offer_expires_at; a claimed offer becomes booking_id.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id               SERIAL PRIMARY KEY,
+    user_id          UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    room_id          INT NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    start_date       DATE NOT NULL,
+    end_date         DATE NOT NULL,
+    quantity         INT NOT NULL DEFAULT 1 CHECK (quantity >= 1),
+    adults           INT NOT NULL DEFAULT 1 CHECK (adults >= 1),
+    children         INT NOT NULL DEFAULT 0 CHECK (children >= 0),
+    status           VARCHAR(20) NOT NULL DEFAULT 'waiting'
+                     CHECK (status IN ('waiting', 'offered', 'claimed', 'expired', 'cancelled')),
+    offer_expires_at TIMESTAMPTZ,
+    booking_id       INT REFERENCES bookings(id),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (end_date > start_date),
+    CHECK (status <> 'offered' OR offer_expires_at IS NOT NULL)
+);
+
+-- A guest waits at most once for the same stay.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waitlist_entries_active
+    ON waitlist_entries(user_id, room_id, start_date, end_date)
+    WHERE status IN ('waiting', 'offered');
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_waiting
+    ON waitlist_entries(room_id, created_at) WHERE status = 'waiting';
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_offered
+    ON waitlist_entries(offer_expires_at) WHERE status = 'offered';
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_user ON waitlist_entries(user_id, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_inventory_overbooked;
+
+ALTER TABLE rooms
+    DROP COLUMN IF EXISTS overbooking_value,
+    DROP COLUMN IF EXISTS overbooking_type;
@@ -0,0 +1,11 @@
+-- How far past its inventory a room may be sold each night: a number of
+-- units, or a percentage of the night's total_inventory (rounded down).
+ALTER TABLE rooms
+    ADD COLUMN IF NOT EXISTS overbooking_type  TEXT NOT NULL DEFAULT 'units'
+        CHECK (overbooking_type IN ('units', 'percent')),
+    ADD COLUMN IF NOT EXISTS overbooking_value INT  NOT NULL DEFAULT 0
+        CHECK (overbooking_value >= 0 AND (overbooking_type <> 'percent' OR overbooking_value <= 100));
+
+-- Nights sold past their inventory, for the front desk to plan relocations.
+CREATE INDEX IF NOT EXISTS idx_inventory_overbooked ON inventory (room_id, date)
+    WHERE booked_count > total_inventory;
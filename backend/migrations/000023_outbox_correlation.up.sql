@@ -0,0 +1,4 @@
+-- The correlation ID of the request or message that caused the event. The
+-- relay publishes it with the event so consumers can tie their work, and the
+-- events it emits, back to the original request.
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS correlation_id VARCHAR(255);
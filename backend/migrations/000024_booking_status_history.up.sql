@@ -0,0 +1,14 @@
+-- Every status a booking moved through. The repository appends a row in the
+-- same transaction as each move; from_status is NULL for the pending status a
+-- booking is created in.
+CREATE TABLE IF NOT EXISTS booking_status_history (
+    id             BIGSERIAL PRIMARY KEY,
+    booking_id     INT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    from_status    VARCHAR(50),
+    to_status      VARCHAR(50) NOT NULL,
+    correlation_id VARCHAR(255),
+    changed_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_status_history_booking
+    ON booking_status_history(booking_id, changed_at);
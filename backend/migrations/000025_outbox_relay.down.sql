@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS outbox_events_notify ON outbox_events;
+DROP FUNCTION IF EXISTS notify_outbox_event();
+DROP INDEX IF EXISTS idx_outbox_pending_aggregate;
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS locked_by,
+    DROP COLUMN IF EXISTS seq;
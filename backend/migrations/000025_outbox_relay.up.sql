@@ -0,0 +1,25 @@
+-- Relays claim outbox events under a lease so several of them can run side
+-- by side without publishing an event twice: locked_by names the relay and
+-- locked_until is when its claim lapses if it dies mid-batch. seq orders the
+-- events of an aggregate, including those written in one transaction that
+-- share a created_at.
+ALTER TABLE outbox_events
+    ADD COLUMN seq BIGSERIAL,
+    ADD COLUMN locked_by VARCHAR(255),
+    ADD COLUMN locked_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_aggregate
+    ON outbox_events(aggregate_type, aggregate_id, seq) WHERE published_at IS NULL;
+
+-- New events wake the relays listening on outbox_events; the notification is
+-- delivered when the writing transaction commits.
+CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_events_notify
+    AFTER INSERT ON outbox_events
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_outbox_event();
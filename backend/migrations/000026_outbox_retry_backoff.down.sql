@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_outbox_dead_lettered;
+DROP INDEX IF EXISTS idx_outbox_pending_aggregate;
+
+-- Dead-lettered events go back to being marked published.
+UPDATE outbox_events SET published_at = dead_lettered_at WHERE status = 'dead_lettered';
+
+ALTER TABLE outbox_events
+    DROP CONSTRAINT IF EXISTS outbox_events_status_check,
+    DROP COLUMN IF EXISTS dead_lettered_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS status;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox_events(published_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_aggregate
+    ON outbox_events(aggregate_type, aggregate_id, seq) WHERE published_at IS NULL;
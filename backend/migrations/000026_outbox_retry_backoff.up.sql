@@ -0,0 +1,30 @@
+-- Failed outbox events are retried with backoff instead of on every relay
+-- pass: next_attempt_at is when the relay may try again and last_error why the
+-- previous attempt failed.
+--
+-- status separates events delivered to the broker from those given up on and
+-- sent to the dead-letter exchange, which used to be marked published too.
+ALTER TABLE outbox_events
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN last_error TEXT,
+    ADD COLUMN dead_lettered_at TIMESTAMPTZ;
+
+-- Events published with five or more retries were dead-lettered: the relay
+-- only ever published those to the dead-letter exchange.
+UPDATE outbox_events
+SET status = 'dead_lettered', dead_lettered_at = published_at, published_at = NULL
+WHERE published_at IS NOT NULL AND retry_count >= 5;
+
+UPDATE outbox_events SET status = 'published' WHERE published_at IS NOT NULL;
+
+ALTER TABLE outbox_events
+    ADD CONSTRAINT outbox_events_status_check
+    CHECK (status IN ('pending', 'published', 'dead_lettered'));
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+DROP INDEX IF EXISTS idx_outbox_pending_aggregate;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_aggregate
+    ON outbox_events(aggregate_type, aggregate_id, seq) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_dead_lettered
+    ON outbox_events(dead_lettered_at) WHERE status = 'dead_lettered';
@@ -1,10 +1,18 @@
 import { apiClient } from "./api";
 import { API } from "@/constants/api";
 import type { Booking, CreateBookingRequest, BookingStatus, ApiResponse } from "@/types";
+import { idempotencyHeaders, newIdempotencyKey } from "@/utils/idempotency";
 
 export const bookingService = {
-  async create(data: CreateBookingRequest): Promise<Booking> {
-    const response = await apiClient.post<ApiResponse<Booking>>(API.BOOKINGS.CREATE, data);
+  async create(
+    data: CreateBookingRequest,
+    idempotencyKey = newIdempotencyKey(),
+  ): Promise<Booking> {
+    const response = await apiClient.post<ApiResponse<Booking>>(
+      API.BOOKINGS.CREATE,
+      data,
+      idempotencyHeaders(idempotencyKey),
+    );
     return response.data.data!;
   },
 
@@ -28,7 +36,7 @@ export const bookingService = {
     return response.data.data!;
   },
 
-  async cancel(id: string): Promise<void> {
-    await apiClient.delete(API.BOOKINGS.CANCEL(id));
+  async cancel(id: string, idempotencyKey = newIdempotencyKey()): Promise<void> {
+    await apiClient.delete(API.BOOKINGS.CANCEL(id), idempotencyHeaders(idempotencyKey));
   },
 };
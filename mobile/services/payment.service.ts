@@ -1,12 +1,17 @@
 import { apiClient } from "./api";
 import { API } from "@/constants/api";
 import type { Payment, CheckoutRequest, ApiResponse } from "@/types";
+import { idempotencyHeaders, newIdempotencyKey } from "@/utils/idempotency";
 
 export const paymentService = {
-  async checkout(data: CheckoutRequest): Promise<Payment> {
+  async checkout(
+    data: CheckoutRequest,
+    idempotencyKey = newIdempotencyKey(),
+  ): Promise<Payment> {
     const response = await apiClient.post<ApiResponse<Payment>>(
       API.PAYMENTS.CHECKOUT,
       data,
+      idempotencyHeaders(idempotencyKey),
     );
     return response.data.data!;
   },
@@ -0,0 +1,12 @@
+export const IDEMPOTENCY_KEY_HEADER = "Idempotency-Key";
+
+// A fresh key for one user action. Retries of that action must resend the
+// same key so the server replays the first response instead of repeating it.
+export function newIdempotencyKey(): string {
+  const random = Math.random().toString(36).slice(2, 12);
+  return `${Date.now().toString(36)}-${random}`;
+}
+
+export function idempotencyHeaders(key: string) {
+  return { headers: { [IDEMPOTENCY_KEY_HEADER]: key } };
+}
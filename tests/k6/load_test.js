@@ -34,6 +34,7 @@ export default function () {
   const params = {
     headers: {
       'Content-Type': 'application/json',
+      'Idempotency-Key': uuidv4(),
     },
   };
 